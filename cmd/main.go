@@ -8,18 +8,24 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"task-manager/internal/auth"
 	"task-manager/internal/config"
 	_ "task-manager/internal/config"
+	"task-manager/internal/grpc"
+	"task-manager/internal/tenant"
+	"task-manager/internal/version"
 	"task-manager/pkg/logger"
-	"time"
 )
 
 // Set Main Operation
 const op = "TaskManager.app"
 
+// GitCommit and BuildTime are stamped by the linker, e.g.
+// -ldflags "-X main.GitCommit=$(git rev-parse --short HEAD) -X main.BuildTime=$(date -u +%FT%TZ)".
+// The VCS revision and time recorded by the Go toolchain are used otherwise.
 var (
-	GitCommit     = "Development"
-	BuildTime     = time.Now().Format(time.RFC1123Z)
+	GitCommit     = version.DevelopmentCommit
+	BuildTime     string
 	ContainerName string
 )
 
@@ -27,11 +33,19 @@ func main() {
 
 	// Load Config File
 	var configFile string
+	var showVersion bool
 	flag.StringVar(&configFile, "c", "", "the environment configuration file of application")
 	flag.StringVar(&configFile, "config", "", "the environment configuration file of application")
+	flag.BoolVar(&showVersion, "v", false, "show the version of the application")
+	flag.BoolVar(&showVersion, "version", false, "show the version of the application")
 	flag.Usage = usage
 	flag.Parse()
 
+	if showVersion {
+		fmt.Println(version.New(GitCommit, BuildTime, "").String())
+		os.Exit(0)
+	}
+
 	// Loading the config file
 	cfg, err := config.LoadConfig(configFile)
 	if err != nil {
@@ -40,7 +54,9 @@ func main() {
 	}
 
 	// Setup Logger
-	loggerInstance := logger.CreateLogger(cfg.Logger)
+	// The records logged with the context of a request carry its principal and tenant,
+	// and the trace ID sent by gRPC callers
+	loggerInstance := logger.CreateLogger(cfg.Logger, logger.WithContextAttrs(auth.LogAttrs, tenant.LogAttrs, grpc.LogAttrs))
 	loggerInstance.Info("logger configured")
 	// Show the loaded config file
 	loggerInstance.InfoF("loaded config file: '%s'", configFile)
@@ -66,10 +82,12 @@ func main() {
 	loggerInstance.InfoF("hostname acquired :%s", hostname)
 
 	// Commit, BuildTime
-	loggerInstance.InfoF("commit number:%s, build time: %s", GitCommit, BuildTime)
+	build := version.New(GitCommit, BuildTime, ContainerName)
+	loggerInstance.InfoF("commit number:%s, build time: %s", build.GitCommit, build.BuildTime)
 
 	// Create New Server
 	server := NewServer(*cfg)
+	server.Version = build
 
 	// Initialize the Server Dependencies
 	err = server.Initialize(loggerInstance)
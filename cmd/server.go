@@ -4,17 +4,47 @@ import (
 	"context"
 	"fmt"
 	"github.com/cockroachdb/errors"
+	"github.com/go-redis/redis/v8"
+	"net"
 	"os"
 	"sync"
+	"task-manager/database"
+	"task-manager/internal/auth"
+	"task-manager/internal/authz"
+	"task-manager/internal/cache"
 	"task-manager/internal/config"
+	"task-manager/internal/events"
+	"task-manager/internal/grpc"
+	"task-manager/internal/health"
 	"task-manager/internal/http"
+	"task-manager/internal/idempotency"
+	"task-manager/internal/jobs"
+	"task-manager/internal/notifier"
+	"task-manager/internal/ratelimit"
 	"task-manager/internal/repository/postgres"
 	"task-manager/internal/service"
+	"task-manager/internal/stream"
+	"task-manager/internal/version"
 	"task-manager/pkg/db"
 	"task-manager/pkg/logger"
 	"task-manager/pkg/monitoring"
+	"task-manager/pkg/tracing"
+	"time"
 )
 
+// Default background job intervals, used when not configured
+const (
+	DefaultRecurrenceInterval = time.Minute
+	DefaultReminderInterval   = time.Minute
+	DefaultWebhookInterval    = 5 * time.Second
+	DefaultOutboxInterval     = time.Second
+	DefaultStreamInterval     = 500 * time.Millisecond
+	DefaultIdempotencyPurge   = time.Hour
+)
+
+// TracingShutdownTimeout bounds the export of the remaining spans on shutdown
+const TracingShutdownTimeout = 5 * time.Second
+
 // Global database connection (could also be encapsulated)
 var dbConn db.DB
 var err error
@@ -22,9 +52,15 @@ var err error
 // Server represents the main application server with all dependencies
 type Server struct {
 	sync.WaitGroup
-	Config      config.Config // Application configuration
-	Logger      logger.Logger // Logger instance
-	restHandler *http.Handler // REST API handler
+	Config      config.Config               // Application configuration
+	Logger      logger.Logger               // Logger instance
+	restHandler *http.Handler               // REST API handler
+	grpcServer  *grpc.Server                // gRPC API server
+	jobs        []*jobs.Runner              // Background jobs started with the server
+	EventBus    *events.Bus                 // In-process subscribers of the task events
+	redis       *redis.Client               // Shared rate limit buckets and idempotency keys, nil unless a redis backend is used
+	Version     version.Info                // Build of the instance
+	tracing     func(context.Context) error // Flushes the spans and stops the tracer provider
 }
 
 // NewServer creates a new Server instance with the provided configuration
@@ -36,6 +72,13 @@ func NewServer(cfg config.Config) *Server {
 
 // Initialize sets up the application: DB connection, repositories, services, metrics, and HTTP handler
 func (s *Server) Initialize(logger logger.Logger) error {
+	// Export the spans of the requests, the services and the SQL statements
+	s.tracing, err = tracing.Setup(context.Background(), s.Config.Tracing, s.Version.GitCommit)
+	if err != nil {
+		return errors.Wrap(err, "[NOK] failed to initialize tracing")
+	}
+	logger.InfoF("[OK] tracing initialized, exporter: %s", tracingExporter(s.Config.Tracing.Exporter))
+
 	// Initialize primary DB connection depending on DBType (Postgres / MySQL)
 	if s.Config.DBType == "mysql" {
 		dbConn, err = db.NewMySQLDB(s.Config.DB.Postgres)
@@ -56,39 +99,342 @@ func (s *Server) Initialize(logger logger.Logger) error {
 
 	// Initialize Task-related metrics
 	taskMetrics := monitoring.InitTaskMetrics(metricsManager)
+	jobMetrics := monitoring.InitJobMetrics(metricsManager)
+	grpcMetrics := monitoring.InitGRPCMetrics(metricsManager)
+	buildMetrics := monitoring.InitBuildMetrics(metricsManager)
+	buildMetrics.Info.WithLabelValues(
+		s.Version.GitCommit,
+		s.Version.BuildTime,
+		s.Version.GoVersion,
+		s.Version.Module.Version,
+		s.Version.ContainerName,
+	).Set(1)
 
 	// Create repositories
-	taskRepository := postgres.NewTaskRepository(dbConn)
+	var taskRepositoryOptions []postgres.TaskRepositoryOption
+	if s.Config.Tenant.RowLevelSecurity {
+		taskRepositoryOptions = append(taskRepositoryOptions, postgres.WithRowLevelSecurity())
+	}
+	taskRepository := postgres.NewTaskRepository(dbConn, taskRepositoryOptions...)
+	userRepository := postgres.NewUserRepository(dbConn)
+	reminderRepository := postgres.NewReminderRepository(dbConn)
+	webhookRepository := postgres.NewWebhookRepository(dbConn)
+	outboxRepository := postgres.NewOutboxRepository(dbConn)
+	apiKeyRepository := postgres.NewAPIKeyRepository(dbConn)
+	transactor := postgres.NewTransactor(dbConn)
 
 	// Create services and inject dependencies (repositories + metrics)
-	TaskService := service.NewTaskService(taskRepository, taskMetrics)
+	WebhookService := service.NewWebhookService(webhookRepository, service.WebhookSettings{
+		MaxAttempts: s.Config.Webhooks.MaxAttempts,
+		BaseBackoff: s.Config.Webhooks.BaseBackoff,
+		MaxBackoff:  s.Config.Webhooks.MaxBackoff,
+		Timeout:     s.Config.Webhooks.Timeout,
+		BatchSize:   s.Config.Webhooks.BatchSize,
+
+		AllowPrivateAddresses: s.Config.Webhooks.AllowPrivateAddresses,
+	})
+	// Task operations of authenticated requests follow the role based policy
+	policy, err := authz.LoadPolicy(s.Config.Auth.PolicyFile)
+	if err != nil {
+		return errors.Wrap(err, "[NOK] failed to load authorization policy")
+	}
+	TaskService := service.NewTaskService(taskRepository, userRepository, taskMetrics,
+		service.WithOutbox(transactor, outboxRepository),
+		service.WithAuthorizer(policy),
+	)
+	UserService := service.NewUserService(userRepository, taskMetrics)
+	APIKeyService := service.NewAPIKeyService(apiKeyRepository, service.APIKeySettings{
+		CacheTTL: s.Config.Auth.APIKeyCacheTTL,
+	})
+	RecurrenceService := service.NewRecurrenceService(taskRepository, s.Config.Jobs.RecurrenceBatchSize,
+		service.WithOccurrenceEvents(transactor, outboxRepository))
 
 	s.Logger = logger
 
+	// Task events are relayed from the outbox to the in-process bus and the webhooks,
+	// plug broker sinks (events.NewBrokerSink) in here
+	s.EventBus = events.NewBus()
+
+	// Live task streams (SSE) follow the outbox itself rather than the events relayed by
+	// this instance, so every instance streams every event under the same ID
+	taskEvents := stream.NewHub(stream.Settings{
+		ReplaySize:   s.Config.Stream.ReplaySize,
+		ClientBuffer: s.Config.Stream.ClientBuffer,
+	})
+	taskEventsTailer := stream.NewTailer(taskEvents, outboxRepository, stream.TailSettings{
+		BatchSize:  s.Config.Stream.BatchSize,
+		GapTimeout: s.Config.Stream.GapTimeout,
+	})
+	sinks := events.Multi{s.EventBus, WebhookService}
+	if s.Config.Outbox.LogEvents {
+		sinks = append(sinks, events.NewLogSink(s.Logger))
+	}
+	OutboxService := service.NewOutboxService(outboxRepository, sinks, service.OutboxSettings{
+		BatchSize:   s.Config.Outbox.BatchSize,
+		Lease:       s.Config.Outbox.Lease,
+		BaseBackoff: s.Config.Outbox.BaseBackoff,
+		MaxBackoff:  s.Config.Outbox.MaxBackoff,
+		Retention:   s.Config.Outbox.Retention,
+	})
+
+	// Deadline notifications are only logged for now, plug other channels in here
+	ReminderService := service.NewReminderService(reminderRepository, notifier.NewLogNotifier(s.Logger), service.ReminderSettings{
+		LeadTime:        s.Config.Jobs.ReminderLeadTime,
+		EscalationAfter: s.Config.Jobs.EscalationAfter,
+		BatchSize:       s.Config.Jobs.ReminderBatchSize,
+	})
+
 	// Initialize REST handler with services, logger, metrics, and config
 	s.restHandler = http.CreateHandler(
 		s.Logger,
 		s.Config,
 		TaskService,
+		UserService,
+		WebhookService,
+		APIKeyService,
+		taskEvents,
 		taskMetrics,
 	)
+	s.restHandler.VersionInfo = s.Version
+	// Users, webhooks and task subscriptions are authorized by the same policy as the tasks
+	s.restHandler.Policy = policy
+	s.restHandler.Authorizer = stream.PolicyAuthorizer(policy)
+
+	// The instance is ready while the database answers, and started once the migrations
+	// the binary was built with are applied
+	s.restHandler.Health.Register(dbType(s.Config.DBType), dbConn)
+	if s.Config.DBType != "mysql" {
+		version, err := database.LatestMigration(database.Migrations)
+		if err != nil {
+			return errors.Wrap(err, "[NOK] failed to read the database migrations")
+		}
+		s.restHandler.Health.RegisterStartup("migrations", postgres.NewMigrationChecker(dbConn, version))
+	}
+
+	// Require a valid JWT or API key on the API routes when authentication is enabled
+	if s.Config.Auth.Enabled {
+		jwtAuthenticator, err := auth.NewJWTAuthenticatorFromConfig(s.Config.Auth)
+		if err != nil {
+			return errors.Wrap(err, "[NOK] failed to initialize JWT authentication")
+		}
+		s.restHandler.Authenticators = append(s.restHandler.Authenticators, jwtAuthenticator, APIKeyService)
+		logger.Info("[OK] JWT and API key authentication enabled")
+	}
+
+	// Limit the requests of every client when rate limiting is enabled
+	if s.Config.RateLimit.Enabled {
+		if err := s.initializeRateLimit(); err != nil {
+			return errors.Wrap(err, "[NOK] failed to initialize rate limiting")
+		}
+		logger.InfoF("[OK] %s rate limiting enabled", s.Config.RateLimit.Backend)
+	}
+
+	// Replay the responses of the requests retried with an Idempotency-Key
+	if err := s.initializeIdempotency(jobMetrics); err != nil {
+		return errors.Wrap(err, "[NOK] failed to initialize idempotency keys")
+	}
+
+	// Initialize gRPC server on top of the same services, for internal clients, which
+	// authenticate like the REST ones
+	s.grpcServer = grpc.CreateServer(
+		s.Logger,
+		s.Config,
+		TaskService,
+		taskEvents,
+		grpcMetrics,
+		s.restHandler.Authenticators...,
+	)
+	s.grpcServer.Authorizer = s.restHandler.Authorizer
+
+	// Register background jobs, started together with the HTTP server
+	s.jobs = append(s.jobs,
+		jobs.NewRunner(jobs.NewRecurrenceJob(RecurrenceService, s.Logger),
+			interval(s.Config.Jobs.RecurrenceInterval, DefaultRecurrenceInterval), s.Logger, jobMetrics),
+		jobs.NewRunner(jobs.NewReminderJob(ReminderService),
+			interval(s.Config.Jobs.ReminderInterval, DefaultReminderInterval), s.Logger, jobMetrics),
+		jobs.NewRunner(jobs.NewWebhookDeliveryJob(WebhookService),
+			interval(s.Config.Webhooks.DeliveryInterval, DefaultWebhookInterval), s.Logger, jobMetrics),
+		jobs.NewRunner(jobs.NewOutboxRelayJob(OutboxService),
+			interval(s.Config.Outbox.RelayInterval, DefaultOutboxInterval), s.Logger, jobMetrics),
+		jobs.NewRunner(jobs.NewStreamTailJob(taskEventsTailer),
+			interval(s.Config.Stream.PollInterval, DefaultStreamInterval), s.Logger, jobMetrics),
+	)
+
+	// Stalled jobs are reported, the instance still serves requests without them
+	for _, job := range s.jobs {
+		s.restHandler.Health.Register("job:"+job.Name(), job, health.Optional())
+	}
 
 	return nil
 }
 
-// Start runs the HTTP server in blocking mode
+// Start launches the background jobs and the gRPC server, and runs the HTTP server in
+// blocking mode. Jobs stop when ctx is canceled; Wait blocks until all of them and the
+// gRPC server returned.
 func (s *Server) Start(ctx context.Context) {
 	fmt.Println("Starting server with config:", s.Config)
+
+	s.Add(1)
+	go func() {
+		defer s.Done()
+		s.grpcServer.StartBlocking(ctx, s.Config.GRPC.Port)
+	}()
+
+	for _, job := range s.jobs {
+		s.Add(1)
+		go func(job *jobs.Runner) {
+			defer s.Done()
+			job.Run(ctx)
+		}(job)
+	}
+
 	s.restHandler.StartBlocking(ctx, s.Config.Port)
 }
 
+// initializeRateLimit sets the rate limiter and the route limits of the REST handler.
+// The redis backend connects to the configured Redis, the buckets are kept in memory
+// otherwise.
+func (s *Server) initializeRateLimit() error {
+	defaultLimit := ratelimit.DefaultLimit
+	if s.Config.RateLimit.Requests > 0 {
+		defaultLimit.Requests = s.Config.RateLimit.Requests
+	}
+	if s.Config.RateLimit.Window > 0 {
+		defaultLimit.Window = s.Config.RateLimit.Window
+	}
+	rules, err := ratelimit.ParseRules(defaultLimit, s.Config.RateLimit.Routes)
+	if err != nil {
+		return err
+	}
+	s.restHandler.RateLimits = rules
+
+	s.restHandler.IPRateLimit = ratelimit.DefaultIPLimit
+	if s.Config.RateLimit.IPRequests > 0 {
+		s.restHandler.IPRateLimit.Requests = s.Config.RateLimit.IPRequests
+	}
+	if s.Config.RateLimit.IPWindow > 0 {
+		s.restHandler.IPRateLimit.Window = s.Config.RateLimit.IPWindow
+	}
+
+	switch s.Config.RateLimit.Backend {
+	case "", ratelimit.BackendMemory:
+		s.Config.RateLimit.Backend = ratelimit.BackendMemory
+		s.restHandler.RateLimiter = ratelimit.NewMemory()
+	case ratelimit.BackendRedis:
+		client, err := s.redisClient()
+		if err != nil {
+			return err
+		}
+		s.restHandler.RateLimiter = ratelimit.NewRedis(client, ratelimit.DefaultRedisPrefix)
+	default:
+		return errors.Newf("unknown rate limit backend %q", s.Config.RateLimit.Backend)
+	}
+
+	return nil
+}
+
+// initializeIdempotency sets the idempotency key store of the REST handler. The db
+// backend also registers the job purging the expired keys.
+func (s *Server) initializeIdempotency(jobMetrics *monitoring.JobMetrics) error {
+	s.restHandler.IdempotencyTTL = s.Config.Idempotency.TTL
+
+	switch s.Config.Idempotency.Backend {
+	case "", idempotency.BackendDB:
+		repository := postgres.NewIdempotencyRepository(dbConn)
+		s.restHandler.Idempotency = repository
+		s.jobs = append(s.jobs, jobs.NewRunner(jobs.NewIdempotencyPurgeJob(repository),
+			interval(s.Config.Idempotency.PurgeInterval, DefaultIdempotencyPurge), s.Logger, jobMetrics))
+	case idempotency.BackendMemory:
+		s.restHandler.Idempotency = idempotency.NewCacheStore(cache.NewMemory())
+	case idempotency.BackendRedis:
+		client, err := s.redisClient()
+		if err != nil {
+			return err
+		}
+		s.restHandler.Idempotency = idempotency.NewCacheStore(cache.NewRedis(client, ""))
+	default:
+		return errors.Newf("unknown idempotency backend %q", s.Config.Idempotency.Backend)
+	}
+
+	return nil
+}
+
+// redisClient returns the client of the configured Redis, connected on first use.
+func (s *Server) redisClient() (*redis.Client, error) {
+	if s.redis != nil {
+		return s.redis, nil
+	}
+
+	client := redis.NewClient(&redis.Options{
+		Addr:     net.JoinHostPort(s.Config.Redis.Host, s.Config.Redis.Port),
+		Password: s.Config.Redis.Password,
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		_ = client.Close()
+		return nil, errors.Wrap(err, "failed to connect to Redis")
+	}
+
+	s.redis = client
+	s.restHandler.Health.Register("redis", health.CheckerFunc(func(ctx context.Context) error {
+		return client.Ping(ctx).Err()
+	}))
+	return client, nil
+}
+
+// dbType returns the name of the configured database, postgres by default.
+func dbType(configured string) string {
+	if configured == "mysql" {
+		return configured
+	}
+	return "postgres"
+}
+
+// interval returns the configured job interval, or fallback if it is not set
+func interval(configured, fallback time.Duration) time.Duration {
+	if configured <= 0 {
+		return fallback
+	}
+	return configured
+}
+
+// tracingExporter returns the name of the configured exporter, none if unset.
+func tracingExporter(exporter string) string {
+	if exporter == "" {
+		return tracing.ExporterNone
+	}
+	return exporter
+}
+
 // GracefulShutdown listens for OS signals and performs a clean shutdown of the server
 func (s *Server) GracefulShutdown(quitSignal <-chan os.Signal, done chan<- bool) {
 	// Wait for OS signal (SIGINT/SIGTERM)
 	<-quitSignal
 
-	// Stop the REST HTTP server gracefully
+	// Fail the readiness probe, and keep serving until the load balancers stopped
+	// routing requests to the instance
+	s.restHandler.Health.Drain()
+	if s.Config.Health.DrainDelay > 0 {
+		s.Logger.InfoF("draining for %s before shutting down", s.Config.Health.DrainDelay)
+		time.Sleep(s.Config.Health.DrainDelay)
+	}
+
+	// Stop the REST HTTP and gRPC servers gracefully
 	s.restHandler.Stop()
+	s.grpcServer.Stop()
+	if s.redis != nil {
+		_ = s.redis.Close()
+	}
+
+	// Export the spans of the last requests
+	if s.tracing != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), TracingShutdownTimeout)
+		if err := s.tracing(ctx); err != nil {
+			s.Logger.ErrorF("failed to flush the spans: %v", err)
+		}
+		cancel()
+	}
 
 	// Signal that shutdown is complete
 	close(done)
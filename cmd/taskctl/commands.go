@@ -0,0 +1,602 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"task-manager/pkg/client"
+	"time"
+)
+
+// newFlagSet returns the flag set of a command, printing its usage to stderr.
+func newFlagSet(e *env, name, args string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(e.stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(e.stderr, "Usage:\n    taskctl %s [options] %s\n\nOptions:\n", name, args)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parse parses the command line of a command, mapping failures to errUsage. Unlike
+// flag.Parse, options may also follow the arguments, as in taskctl get 42 -o json.
+func parse(fs *flag.FlagSet, args []string) error {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return err
+			}
+			return errUsage
+		}
+
+		if args = fs.Args(); len(args) == 0 {
+			break
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+
+	// Leave the arguments to fs.Args.
+	return fs.Parse(append([]string{"--"}, positional...))
+}
+
+// connect returns the client and a context bounded by the --timeout option.
+func connect(ctx context.Context, conn *connection) (*client.Client, context.Context, context.CancelFunc, error) {
+	c, err := conn.client()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, conn.timeout)
+	return c, ctx, cancel, nil
+}
+
+// argID parses the task ID argument of a command.
+func argID(fs *flag.FlagSet) (int64, error) {
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 0, errUsage
+	}
+
+	id, err := strconv.ParseInt(fs.Arg(0), 10, 64)
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("invalid task ID %q", fs.Arg(0))
+	}
+	return id, nil
+}
+
+// runList lists a page of tasks, or all of them with --all.
+func runList(ctx context.Context, e *env, args []string) error {
+	var (
+		conn   connection
+		filter filters
+		format string
+		all    bool
+	)
+
+	fs := newFlagSet(e, "list", "")
+	conn.register(fs)
+	filter.register(fs, true)
+	fs.StringVar(&format, "o", FormatTable, "output format: table, json or yaml")
+	fs.BoolVar(&all, "all", false, "fetch every page")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+	if err := checkFormat(format, FormatTable, FormatJSON, FormatYAML); err != nil {
+		return err
+	}
+
+	opts, err := filter.options()
+	if err != nil {
+		return err
+	}
+
+	c, ctx, cancel, err := connect(ctx, &conn)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	if all {
+		tasks, err := collect(ctx, c, opts)
+		if err != nil {
+			return err
+		}
+		if err := filter.sortTasks(tasks); err != nil {
+			return err
+		}
+		return writeTasks(e.stdout, format, tasks, nil)
+	}
+
+	page, err := c.ListTasks(ctx, opts)
+	if err != nil {
+		return err
+	}
+	if err := filter.sortTasks(page.Tasks); err != nil {
+		return err
+	}
+	return writeTasks(e.stdout, format, page.Tasks, &page.Meta)
+}
+
+// runGet shows a task.
+func runGet(ctx context.Context, e *env, args []string) error {
+	var (
+		conn   connection
+		format string
+	)
+
+	fs := newFlagSet(e, "get", "<id>")
+	conn.register(fs)
+	fs.StringVar(&format, "o", FormatTable, "output format: table, json or yaml")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+	if err := checkFormat(format, FormatTable, FormatJSON, FormatYAML); err != nil {
+		return err
+	}
+
+	id, err := argID(fs)
+	if err != nil {
+		return err
+	}
+
+	c, ctx, cancel, err := connect(ctx, &conn)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	task, err := c.GetTask(ctx, id)
+	if err != nil {
+		return err
+	}
+	return writeTask(e.stdout, format, task)
+}
+
+// taskFields holds the task options of create and update.
+type taskFields struct {
+	title       string
+	description string
+	status      string
+	assignees   string
+	watchers    string
+	due         string
+	rrule       string
+}
+
+// register adds the task options to a command's flag set.
+func (t *taskFields) register(fs *flag.FlagSet) {
+	fs.StringVar(&t.title, "title", "", "title")
+	fs.StringVar(&t.description, "description", "", "description")
+	fs.StringVar(&t.status, "status", "", "status: pending, in_progress, done or canceled")
+	fs.StringVar(&t.assignees, "assignee", "", "comma separated user IDs, the first one is the primary assignee, replacing the current ones")
+	fs.StringVar(&t.watchers, "watcher", "", "comma separated user IDs of the watchers, replacing the current ones")
+	fs.StringVar(&t.due, "due", "", "deadline, RFC 3339 or YYYY-MM-DD (local midnight), empty to clear")
+	fs.StringVar(&t.rrule, "rrule", "", "recurrence rule, e.g. FREQ=WEEKLY;BYDAY=MO, empty to stop the recurrence")
+}
+
+// runCreate creates a task.
+func runCreate(ctx context.Context, e *env, args []string) error {
+	var (
+		conn   connection
+		fields taskFields
+		format string
+	)
+
+	fs := newFlagSet(e, "create", "")
+	conn.register(fs)
+	fields.register(fs)
+	fs.StringVar(&format, "o", FormatTable, "output format: table, json or yaml")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+	if err := checkFormat(format, FormatTable, FormatJSON, FormatYAML); err != nil {
+		return err
+	}
+	if fields.title == "" || fields.assignees == "" {
+		fmt.Fprintln(e.stderr, "taskctl: --title and --assignee are required")
+		fs.Usage()
+		return errUsage
+	}
+
+	req := client.CreateTaskRequest{
+		Title:          fields.title,
+		Description:    fields.description,
+		Status:         client.TaskStatus(fields.status),
+		RecurrenceRule: fields.rrule,
+	}
+
+	var err error
+	if req.AssigneeIDs, err = parseIDs("assignee", fields.assignees); err != nil {
+		return err
+	}
+	req.AssigneeID = req.AssigneeIDs[0]
+	if req.WatcherIDs, err = parseIDs("watcher", fields.watchers); err != nil {
+		return err
+	}
+	if req.DueAt, err = parseDue(fields.due); err != nil {
+		return err
+	}
+
+	c, ctx, cancel, err := connect(ctx, &conn)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	task, err := c.CreateTask(ctx, req)
+	if err != nil {
+		return err
+	}
+	return writeTask(e.stdout, format, task)
+}
+
+// runUpdate changes the given fields of a task. As the API replaces the whole task,
+// the current task is fetched first and the fields not given are kept.
+func runUpdate(ctx context.Context, e *env, args []string) error {
+	var (
+		conn   connection
+		fields taskFields
+		format string
+	)
+
+	fs := newFlagSet(e, "update", "<id>")
+	conn.register(fs)
+	fields.register(fs)
+	fs.StringVar(&format, "o", FormatTable, "output format: table, json or yaml")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+	if err := checkFormat(format, FormatTable, FormatJSON, FormatYAML); err != nil {
+		return err
+	}
+
+	id, err := argID(fs)
+	if err != nil {
+		return err
+	}
+
+	c, ctx, cancel, err := connect(ctx, &conn)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	task, err := c.GetTask(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	req := client.UpdateTaskRequest{
+		Title:          task.Title,
+		Description:    task.Description,
+		Status:         task.Status,
+		AssigneeID:     task.AssigneeID,
+		DueAt:          task.DueAt,
+		RecurrenceRule: task.RecurrenceRule,
+	}
+
+	var visitErr error
+	fs.Visit(func(f *flag.Flag) {
+		var err error
+		switch f.Name {
+		case "title":
+			req.Title = fields.title
+		case "description":
+			req.Description = fields.description
+		case "status":
+			req.Status = client.TaskStatus(fields.status)
+		case "assignee":
+			if req.AssigneeIDs, err = parseIDs("assignee", fields.assignees); err == nil && len(req.AssigneeIDs) > 0 {
+				req.AssigneeID = req.AssigneeIDs[0]
+			}
+		case "watcher":
+			req.WatcherIDs, err = parseIDs("watcher", fields.watchers)
+		case "due":
+			req.DueAt, err = parseDue(fields.due)
+		case "rrule":
+			req.RecurrenceRule = fields.rrule
+		}
+		visitErr = errors.Join(visitErr, err)
+	})
+	if visitErr != nil {
+		return visitErr
+	}
+
+	if task, err = c.UpdateTask(ctx, id, req); err != nil {
+		return err
+	}
+	return writeTask(e.stdout, format, task)
+}
+
+// runDelete deletes the given tasks, stopping at the first failure.
+func runDelete(ctx context.Context, e *env, args []string) error {
+	var conn connection
+
+	fs := newFlagSet(e, "delete", "<id>...")
+	conn.register(fs)
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return errUsage
+	}
+
+	ids, err := parseIDs("id", strings.Join(fs.Args(), ","))
+	if err != nil {
+		return err
+	}
+
+	c, ctx, cancel, err := connect(ctx, &conn)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	for _, id := range ids {
+		if err := c.DeleteTask(ctx, id); err != nil {
+			return fmt.Errorf("delete task %d: %w", id, err)
+		}
+		fmt.Fprintf(e.stdout, "deleted task %d\n", id)
+	}
+	return nil
+}
+
+// runExport writes every task matching the filters, in a format accepted by import.
+func runExport(ctx context.Context, e *env, args []string) error {
+	var (
+		conn   connection
+		filter filters
+		format string
+		file   string
+	)
+
+	fs := newFlagSet(e, "export", "")
+	conn.register(fs)
+	filter.register(fs, false)
+	fs.StringVar(&format, "o", FormatJSON, "output format: json or yaml")
+	fs.StringVar(&file, "file", "", "file to write, stdout if empty")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+	if err := checkFormat(format, FormatJSON, FormatYAML); err != nil {
+		return err
+	}
+
+	opts, err := filter.options()
+	if err != nil {
+		return err
+	}
+
+	c, ctx, cancel, err := connect(ctx, &conn)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	tasks, err := collect(ctx, c, opts)
+	if err != nil {
+		return err
+	}
+	if err := filter.sortTasks(tasks); err != nil {
+		return err
+	}
+
+	if file == "" {
+		return writeTasks(e.stdout, format, tasks, nil)
+	}
+
+	var buf bytes.Buffer
+	if err := writeTasks(&buf, format, tasks, nil); err != nil {
+		return err
+	}
+	if err := os.WriteFile(file, buf.Bytes(), 0o644); err != nil {
+		return err
+	}
+	fmt.Fprintf(e.stderr, "exported %d tasks to %s\n", len(tasks), file)
+	return nil
+}
+
+// runImport creates the tasks listed in a JSON or YAML file, such as an export.
+// IDs, timestamps and other read-only fields of the records are ignored.
+func runImport(ctx context.Context, e *env, args []string) error {
+	var (
+		conn      connection
+		format    string
+		keepGoing bool
+	)
+
+	fs := newFlagSet(e, "import", "<file|->")
+	conn.register(fs)
+	fs.StringVar(&format, "format", "", "input format: json or yaml (default from the file extension, json for stdin)")
+	fs.BoolVar(&keepGoing, "keep-going", false, "create the remaining tasks after a failure")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errUsage
+	}
+
+	reqs, err := readImport(e, fs.Arg(0), format)
+	if err != nil {
+		return err
+	}
+
+	c, ctx, cancel, err := connect(ctx, &conn)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	var failed int
+	for i, req := range reqs {
+		task, err := c.CreateTask(ctx, req)
+		if err != nil {
+			err = fmt.Errorf("record %d (%q): %w", i+1, req.Title, err)
+			if !keepGoing {
+				return err
+			}
+			fmt.Fprintf(e.stderr, "taskctl: %v\n", err)
+			failed++
+			continue
+		}
+		fmt.Fprintf(e.stdout, "created task %d: %s\n", task.ID, task.Title)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d tasks failed to import", failed, len(reqs))
+	}
+	return nil
+}
+
+// readImport decodes the task records of an import file, - being stdin.
+func readImport(e *env, path, format string) ([]client.CreateTaskRequest, error) {
+	var (
+		data []byte
+		err  error
+	)
+	if path == "-" {
+		data, err = io.ReadAll(e.stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if format == "" {
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".yaml", ".yml":
+			format = FormatYAML
+		default:
+			format = FormatJSON
+		}
+	}
+	if err := checkFormat(format, FormatJSON, FormatYAML); err != nil {
+		return nil, err
+	}
+
+	// YAML records are converted to JSON, so both formats use the API field names.
+	if format == FormatYAML {
+		var records []any
+		if err := yaml.Unmarshal(data, &records); err != nil {
+			return nil, fmt.Errorf("parse %s: %w", path, err)
+		}
+		if data, err = json.Marshal(records); err != nil {
+			return nil, fmt.Errorf("parse %s: %w", path, err)
+		}
+	}
+
+	var reqs []client.CreateTaskRequest
+	if err := json.Unmarshal(data, &reqs); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return reqs, nil
+}
+
+// runProfile lists, adds, selects and removes the profiles of the config file.
+func runProfile(_ context.Context, e *env, args []string) error {
+	var conn connection
+
+	fs := newFlagSet(e, "profile", "list | set <name> | use <name> | remove <name>")
+	conn.register(fs)
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 || fs.Arg(0) != "list" && fs.NArg() != 2 {
+		fs.Usage()
+		return errUsage
+	}
+
+	path, err := conn.path()
+	if err != nil {
+		return err
+	}
+	cfg, err := loadConfig(path)
+	if err != nil {
+		return err
+	}
+
+	name := fs.Arg(1)
+	switch fs.Arg(0) {
+	case "list":
+		for _, name := range slices.Sorted(maps.Keys(cfg.Profiles)) {
+			marker := " "
+			if name == cfg.Current {
+				marker = "*"
+			}
+			fmt.Fprintf(e.stdout, "%s %s\t%s\n", marker, name, cfg.Profiles[name].Server)
+		}
+		return nil
+	case "set":
+		if conn.server == "" {
+			fmt.Fprintln(e.stderr, "taskctl: --server is required")
+			return errUsage
+		}
+		cfg.Profiles[name] = Profile{Server: conn.server, Token: conn.token}
+		if cfg.Current == "" {
+			cfg.Current = name
+		}
+	case "use":
+		if _, ok := cfg.Profiles[name]; !ok {
+			return fmt.Errorf("profile %q not found in %s", name, path)
+		}
+		cfg.Current = name
+	case "remove":
+		if _, ok := cfg.Profiles[name]; !ok {
+			return fmt.Errorf("profile %q not found in %s", name, path)
+		}
+		delete(cfg.Profiles, name)
+		if cfg.Current == name {
+			cfg.Current = ""
+		}
+	default:
+		fs.Usage()
+		return errUsage
+	}
+
+	return saveConfig(path, cfg)
+}
+
+// collect fetches every task matching the options.
+func collect(ctx context.Context, c *client.Client, opts client.ListOptions) ([]client.Task, error) {
+	tasks := []client.Task{}
+	for task, err := range c.Tasks(ctx, opts) {
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, nil
+}
+
+// parseDue parses the --due option, an empty value meaning no deadline.
+func parseDue(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	if due, err := time.Parse(time.RFC3339, value); err == nil {
+		return &due, nil
+	}
+	due, err := time.ParseInLocation(time.DateOnly, value, time.Local)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --due %q, expected RFC 3339 or YYYY-MM-DD", value)
+	}
+	return &due, nil
+}
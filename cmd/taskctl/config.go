@@ -0,0 +1,156 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"task-manager/pkg/client"
+	"time"
+)
+
+// Defaults of the connection options.
+const (
+	DefaultServer  = "http://localhost:8080"
+	DefaultTimeout = 30 * time.Second
+)
+
+// Config is the taskctl config file, holding the known servers as named profiles.
+//
+//	current: local
+//	profiles:
+//	  local:
+//	    server: http://localhost:8080
+//	  prod:
+//	    server: https://tasks.example.com
+//	    token: eyJhbGciOi...
+type Config struct {
+	Current  string             `yaml:"current,omitempty"`  // Profile used when none is selected
+	Profiles map[string]Profile `yaml:"profiles,omitempty"` // Profiles by name
+}
+
+// Profile holds the connection settings of a server.
+type Profile struct {
+	Server string `yaml:"server"`          // API base URL
+	Token  string `yaml:"token,omitempty"` // Bearer token sent with every request
+}
+
+// connection holds the connection options shared by all commands.
+type connection struct {
+	configPath string
+	profile    string
+	server     string
+	token      string
+	timeout    time.Duration
+}
+
+// register adds the connection options to a command's flag set.
+func (c *connection) register(fs *flag.FlagSet) {
+	fs.StringVar(&c.configPath, "config", os.Getenv("TASKCTL_CONFIG"), "config file")
+	fs.StringVar(&c.profile, "profile", os.Getenv("TASKCTL_PROFILE"), "profile of the config file to use")
+	fs.StringVar(&c.server, "server", os.Getenv("TASKCTL_SERVER"), "API base URL, overrides the profile")
+	fs.StringVar(&c.token, "token", os.Getenv("TASKCTL_TOKEN"), "bearer token, overrides the profile")
+	fs.DurationVar(&c.timeout, "timeout", DefaultTimeout, "timeout of the whole command")
+}
+
+// path returns the config file path, defaulting to taskctl/config.yaml in the user
+// config directory.
+func (c *connection) path() (string, error) {
+	if c.configPath != "" {
+		return c.configPath, nil
+	}
+
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("locate config file: %w", err)
+	}
+	return filepath.Join(dir, "taskctl", "config.yaml"), nil
+}
+
+// resolve returns the profile to connect with: the selected or current profile of
+// the config file, overridden by the --server and --token options.
+func (c *connection) resolve() (Profile, error) {
+	path, err := c.path()
+	if err != nil {
+		return Profile{}, err
+	}
+
+	cfg, err := loadConfig(path)
+	if err != nil {
+		return Profile{}, err
+	}
+
+	name := c.profile
+	if name == "" {
+		name = cfg.Current
+	}
+
+	var profile Profile
+	if name != "" {
+		var ok bool
+		if profile, ok = cfg.Profiles[name]; !ok {
+			return Profile{}, fmt.Errorf("profile %q not found in %s", name, path)
+		}
+	}
+
+	if c.server != "" {
+		profile.Server = c.server
+	}
+	if c.token != "" {
+		profile.Token = c.token
+	}
+	if profile.Server == "" {
+		profile.Server = DefaultServer
+	}
+
+	return profile, nil
+}
+
+// client returns an API client for the resolved profile.
+func (c *connection) client() (*client.Client, error) {
+	profile, err := c.resolve()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.New(profile.Server, client.Config{Token: profile.Token, UserAgent: "taskctl/1.0"}), nil
+}
+
+// loadConfig reads the config file at path. A missing file yields an empty config.
+func loadConfig(path string) (*Config, error) {
+	cfg := &Config{Profiles: map[string]Profile{}}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	if cfg.Profiles == nil {
+		cfg.Profiles = map[string]Profile{}
+	}
+
+	return cfg, nil
+}
+
+// saveConfig writes the config file at path, readable by the owner only as it may
+// hold tokens.
+func saveConfig(path string, cfg *Config) error {
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
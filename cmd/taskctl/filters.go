@@ -0,0 +1,164 @@
+package main
+
+import (
+	"cmp"
+	"flag"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"task-manager/pkg/client"
+	"time"
+)
+
+// filters holds the list options, mirroring the query parameters understood by
+// rest.ParseQuery, and the sort order applied to the fetched tasks.
+type filters struct {
+	page            int
+	perPage         int
+	ids             string
+	status          string
+	title           string
+	assignees       string
+	watchers        string
+	includeAssignee bool
+	sort            string
+}
+
+// register adds the filter options to a command's flag set. Commands fetching every
+// page leave out the paging options.
+func (f *filters) register(fs *flag.FlagSet, paging bool) {
+	if paging {
+		fs.IntVar(&f.page, "page", 0, "page to fetch (default 1)")
+		fs.IntVar(&f.perPage, "per-page", 0, "tasks per page (default 20)")
+	} else {
+		fs.IntVar(&f.perPage, "per-page", 100, "tasks fetched per request")
+	}
+	fs.StringVar(&f.ids, "id", "", "comma separated task IDs")
+	fs.StringVar(&f.status, "status", "", "status: pending, in_progress, done or canceled")
+	fs.StringVar(&f.title, "title", "", "exact title")
+	fs.StringVar(&f.assignees, "assignee", "", "comma separated user IDs, tasks assigned to any of them")
+	fs.StringVar(&f.watchers, "watcher", "", "comma separated user IDs, tasks watched by any of them")
+	fs.BoolVar(&f.includeAssignee, "include-assignee", false, "embed the primary assignee")
+	fs.StringVar(&f.sort, "sort", "", "comma separated sort fields, prefixed with - for descending order: "+strings.Join(sortFields, ", "))
+}
+
+// options returns the list options of the filters.
+func (f *filters) options() (client.ListOptions, error) {
+	opts := client.ListOptions{
+		Page:            f.page,
+		PerPage:         f.perPage,
+		Status:          client.TaskStatus(f.status),
+		Title:           f.title,
+		IncludeAssignee: f.includeAssignee,
+	}
+
+	var err error
+	if opts.IDs, err = parseIDs("id", f.ids); err != nil {
+		return opts, err
+	}
+	if opts.AssigneeIDs, err = parseIDs("assignee", f.assignees); err != nil {
+		return opts, err
+	}
+	if opts.WatcherIDs, err = parseIDs("watcher", f.watchers); err != nil {
+		return opts, err
+	}
+
+	return opts, nil
+}
+
+// sortFields are the task fields accepted by --sort.
+var sortFields = []string{"id", "title", "status", "assignee_id", "due_at", "created_at", "updated_at"}
+
+// sorter returns the comparison of the --sort option, or nil if it is not set.
+//
+// The API lists tasks in no particular order, so sorting happens on the fetched
+// tasks: within the page with --page, over all tasks with --all and for exports.
+func (f *filters) sorter() (func(a, b client.Task) int, error) {
+	if f.sort == "" {
+		return nil, nil
+	}
+
+	var keys []func(a, b client.Task) int
+	for _, field := range strings.Split(f.sort, ",") {
+		field = strings.TrimSpace(field)
+		desc := strings.HasPrefix(field, "-")
+		field = strings.TrimPrefix(field, "-")
+
+		var key func(a, b client.Task) int
+		switch field {
+		case "id":
+			key = func(a, b client.Task) int { return cmp.Compare(a.ID, b.ID) }
+		case "title":
+			key = func(a, b client.Task) int { return strings.Compare(a.Title, b.Title) }
+		case "status":
+			key = func(a, b client.Task) int { return strings.Compare(string(a.Status), string(b.Status)) }
+		case "assignee_id":
+			key = func(a, b client.Task) int { return cmp.Compare(a.AssigneeID, b.AssigneeID) }
+		case "due_at":
+			key = func(a, b client.Task) int { return compareDue(a.DueAt, b.DueAt) }
+		case "created_at":
+			key = func(a, b client.Task) int { return a.CreatedAt.Compare(b.CreatedAt) }
+		case "updated_at":
+			key = func(a, b client.Task) int { return a.UpdatedAt.Compare(b.UpdatedAt) }
+		default:
+			return nil, fmt.Errorf("invalid sort field %q, expected one of %s", field, strings.Join(sortFields, ", "))
+		}
+
+		if desc {
+			asc := key
+			key = func(a, b client.Task) int { return asc(b, a) }
+		}
+		keys = append(keys, key)
+	}
+
+	return func(a, b client.Task) int {
+		for _, key := range keys {
+			if c := key(a, b); c != 0 {
+				return c
+			}
+		}
+		return 0
+	}, nil
+}
+
+// sortTasks sorts tasks in place by the --sort option.
+func (f *filters) sortTasks(tasks []client.Task) error {
+	compare, err := f.sorter()
+	if err != nil || compare == nil {
+		return err
+	}
+
+	slices.SortStableFunc(tasks, compare)
+	return nil
+}
+
+// compareDue orders deadlines ascending, tasks without one last.
+func compareDue(a, b *time.Time) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return 1
+	case b == nil:
+		return -1
+	}
+	return a.Compare(*b)
+}
+
+// parseIDs parses a comma separated list of IDs given to the named option.
+func parseIDs(name, value string) ([]int64, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	var ids []int64
+	for _, field := range strings.Split(value, ",") {
+		id, err := strconv.ParseInt(strings.TrimSpace(field), 10, 64)
+		if err != nil || id <= 0 {
+			return nil, fmt.Errorf("invalid --%s: %q is not an ID", name, field)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
@@ -0,0 +1,118 @@
+// Command taskctl manages tasks of a task manager API from the terminal and scripts.
+//
+// It is built on pkg/client and reads the servers to talk to from config profiles,
+// see the profile command. Run taskctl help for the list of commands.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// command is a taskctl subcommand.
+type command struct {
+	name    string
+	summary string
+	run     func(ctx context.Context, env *env, args []string) error
+}
+
+// env holds the streams a command reads from and writes to.
+type env struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+}
+
+// errUsage is returned when a command was called with invalid arguments, after the
+// usage has been printed.
+var errUsage = errors.New("invalid usage")
+
+var commands = []command{
+	{name: "list", summary: "List tasks matching filters", run: runList},
+	{name: "get", summary: "Show a task", run: runGet},
+	{name: "create", summary: "Create a task", run: runCreate},
+	{name: "update", summary: "Change fields of a task", run: runUpdate},
+	{name: "delete", summary: "Delete tasks", run: runDelete},
+	{name: "import", summary: "Create tasks from a JSON or YAML file", run: runImport},
+	{name: "export", summary: "Write tasks matching filters as JSON or YAML", run: runExport},
+	{name: "profile", summary: "Manage the server profiles", run: runProfile},
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	err := run(ctx, &env{stdin: os.Stdin, stdout: os.Stdout, stderr: os.Stderr}, os.Args[1:])
+	if err != nil {
+		if !errors.Is(err, errUsage) {
+			fmt.Fprintf(os.Stderr, "taskctl: %v\n", err)
+		}
+		os.Exit(1)
+	}
+}
+
+// run dispatches args to the matching command.
+func run(ctx context.Context, e *env, args []string) error {
+	if len(args) == 0 {
+		usage(e.stderr)
+		return errUsage
+	}
+
+	switch args[0] {
+	case "help", "-h", "-help", "--help":
+		usage(e.stdout)
+		return nil
+	}
+
+	for _, cmd := range commands {
+		if cmd.name == args[0] {
+			err := cmd.run(ctx, e, args[1:])
+			if errors.Is(err, flag.ErrHelp) {
+				return nil
+			}
+			return err
+		}
+	}
+
+	fmt.Fprintf(e.stderr, "taskctl: unknown command %q\n", args[0])
+	usage(e.stderr)
+	return errUsage
+}
+
+func usage(w io.Writer) {
+	fmt.Fprint(w, `
+taskctl - command-line client of the TaskManager API
+
+Usage:
+    taskctl <command> [options] [arguments]
+
+Commands:
+`)
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "    %-10s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprint(w, `
+Run taskctl <command> -h for the options of a command.
+
+Connection options (all commands):
+    --profile  <name>          Profile of the config file to use (env TASKCTL_PROFILE)
+    --server   <url>           API base URL, overrides the profile (env TASKCTL_SERVER)
+    --token    <token>         Bearer token, overrides the profile (env TASKCTL_TOKEN)
+    --config   <file>          Config file (env TASKCTL_CONFIG, default <user config dir>/taskctl/config.yaml)
+    --timeout  <duration>      Timeout of the whole command (default 30s)
+
+Examples:
+    # Tasks in progress assigned to user 3, most urgent first
+    taskctl list --status in_progress --assignee 3 --sort due_at
+
+    # Back up all tasks and restore them on another server
+    taskctl export > tasks.json
+    taskctl import --profile staging tasks.json
+`)
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"task-manager/internal/entities"
+	HTTPhandler "task-manager/internal/http"
+	"task-manager/internal/service"
+	"task-manager/pkg/client"
+	"task-manager/pkg/rest"
+	"testing"
+	"time"
+)
+
+// setupServer starts the real router backed by the given mock and returns its URL.
+// The Authorization header of every request is sent to auth, if not nil.
+func setupServer(t *testing.T, taskService *service.MockTaskService, auth chan<- string) string {
+	t.Helper()
+
+	router := HTTPhandler.SetupHandler(taskService).SetupRouter()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if auth != nil {
+			auth <- r.Header.Get("Authorization")
+		}
+		router.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	return server.URL
+}
+
+// execute runs taskctl with args and returns its stdout.
+func execute(t *testing.T, stdin string, args ...string) (string, error) {
+	t.Helper()
+
+	var stdout, stderr bytes.Buffer
+	err := run(context.Background(), &env{stdin: strings.NewReader(stdin), stdout: &stdout, stderr: &stderr}, args)
+	return stdout.String(), err
+}
+
+// TestList_ShouldSendFiltersAndSortPage tests that filters become query parameters and the page is sorted.
+func TestList_ShouldSendFiltersAndSortPage(t *testing.T) {
+	mockService := &service.MockTaskService{}
+	url := setupServer(t, mockService, nil)
+
+	early := time.Date(2030, 1, 1, 9, 0, 0, 0, time.UTC)
+	late := early.Add(24 * time.Hour)
+	mockService.On("List", mock.Anything, mock.MatchedBy(func(q rest.Query) bool {
+		return q.Page == 2 && q.PerPage == 3 && q.Filter["status"] == "in_progress" &&
+			q.Filter["assignee_id"] == "3,4" && q.Filter["id"] == "1,2,3"
+	})).Return([]entities.Task{
+		{ID: 1, Title: "No deadline"},
+		{ID: 2, Title: "Late", DueAt: &late},
+		{ID: 3, Title: "Early", DueAt: &early},
+	}, 6, nil).Once()
+
+	out, err := execute(t, "", "list", "--server", url, "--page", "2", "--per-page", "3",
+		"--status", "in_progress", "--assignee", "3,4", "--id", "1,2,3", "--sort", "due_at", "-o", "json")
+	require.NoError(t, err)
+
+	var tasks []client.Task
+	require.NoError(t, json.Unmarshal([]byte(out), &tasks))
+	require.Len(t, tasks, 3)
+	assert.Equal(t, []int64{3, 2, 1}, []int64{tasks[0].ID, tasks[1].ID, tasks[2].ID})
+	mockService.AssertExpectations(t)
+}
+
+// TestUpdate_ShouldKeepFieldsNotGiven tests that update only changes the given fields of the current task.
+func TestUpdate_ShouldKeepFieldsNotGiven(t *testing.T) {
+	mockService := &service.MockTaskService{}
+	url := setupServer(t, mockService, nil)
+
+	dueAt := time.Date(2030, 1, 2, 9, 0, 0, 0, time.UTC)
+	current := &entities.Task{
+		ID: 7, Title: "Write docs", Description: "API guide", Status: entities.TaskStatusPending,
+		AssigneeID: 3, AssigneeIDs: []int64{3}, DueAt: &dueAt, RecurrenceRule: "FREQ=WEEKLY",
+	}
+	mockService.On("GetByID", mock.Anything, int64(7)).Return(current, nil).Once()
+	mockService.On("Update", mock.Anything, mock.MatchedBy(func(task *entities.Task) bool {
+		return task.ID == 7 && task.Status == entities.TaskStatusDone && task.Title == "Write docs" &&
+			task.Description == "API guide" && task.AssigneeID == 3 && task.AssigneeIDs == nil &&
+			task.DueAt != nil && task.DueAt.Equal(dueAt) && task.RecurrenceRule == "FREQ=WEEKLY"
+	})).Return(&entities.Task{ID: 7, Title: "Write docs", Status: entities.TaskStatusDone, AssigneeID: 3}, nil).Once()
+
+	out, err := execute(t, "", "update", "7", "--server", url, "--status", "done")
+
+	require.NoError(t, err)
+	assert.Contains(t, out, "done")
+	mockService.AssertExpectations(t)
+}
+
+// TestExportImport_YAML_ShouldRoundTrip tests that an exported YAML file can be imported again.
+func TestExportImport_YAML_ShouldRoundTrip(t *testing.T) {
+	mockService := &service.MockTaskService{}
+	url := setupServer(t, mockService, nil)
+
+	dueAt := time.Date(2030, 1, 2, 9, 0, 0, 0, time.UTC)
+	mockService.On("List", mock.Anything, mock.Anything).Return([]entities.Task{
+		{ID: 1, Title: "123", Status: entities.TaskStatusDone, AssigneeID: 3, AssigneeIDs: []int64{3, 4}, WatcherIDs: []int64{5}, DueAt: &dueAt},
+	}, 1, nil).Once()
+
+	file := filepath.Join(t.TempDir(), "tasks.yaml")
+	_, err := execute(t, "", "export", "--server", url, "-o", "yaml", "--file", file)
+	require.NoError(t, err)
+
+	mockService.On("Create", mock.Anything, mock.MatchedBy(func(task *entities.Task) bool {
+		return task.ID == 0 && task.Title == "123" && task.Status == entities.TaskStatusDone && task.AssigneeID == 3 &&
+			assert.ObjectsAreEqual([]int64{3, 4}, task.AssigneeIDs) && assert.ObjectsAreEqual([]int64{5}, task.WatcherIDs) &&
+			task.DueAt != nil && task.DueAt.Equal(dueAt)
+	})).Return(&entities.Task{ID: 2, Title: "123"}, nil).Once()
+
+	out, err := execute(t, "", "import", "--server", url, file)
+
+	require.NoError(t, err)
+	assert.Equal(t, "created task 2: 123\n", out)
+	mockService.AssertExpectations(t)
+}
+
+// TestProfile_ShouldSelectServerAndToken tests that the current profile is used and flags override it.
+func TestProfile_ShouldSelectServerAndToken(t *testing.T) {
+	mockService := &service.MockTaskService{}
+	auth := make(chan string, 2)
+	url := setupServer(t, mockService, auth)
+
+	config := filepath.Join(t.TempDir(), "config.yaml")
+	_, err := execute(t, "", "profile", "--config", config, "--server", url, "--token", "secret", "set", "local")
+	require.NoError(t, err)
+	_, err = execute(t, "", "profile", "--config", config, "--server", "http://unused", "set", "prod")
+	require.NoError(t, err)
+
+	info, err := os.Stat(config)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	mockService.On("GetByID", mock.Anything, int64(7)).Return(&entities.Task{ID: 7, Title: "Write docs"}, nil).Twice()
+
+	_, err = execute(t, "", "get", "--config", config, "7")
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer secret", <-auth)
+
+	_, err = execute(t, "", "get", "--config", config, "--token", "other", "7")
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer other", <-auth)
+
+	_, err = execute(t, "", "get", "--config", config, "--profile", "missing", "7")
+	assert.ErrorContains(t, err, `profile "missing" not found`)
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"io"
+	"strconv"
+	"strings"
+	"task-manager/pkg/client"
+	"task-manager/pkg/rest"
+	"text/tabwriter"
+	"time"
+)
+
+// Output formats.
+const (
+	FormatTable = "table"
+	FormatJSON  = "json"
+	FormatYAML  = "yaml"
+)
+
+// checkFormat returns an error if format is not one of the given ones.
+func checkFormat(format string, allowed ...string) error {
+	for _, f := range allowed {
+		if format == f {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown output format %q, expected one of %s", format, strings.Join(allowed, ", "))
+}
+
+// writeJSON writes v as indented JSON.
+func writeJSON(w io.Writer, v any) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// writeYAML writes v as YAML. It goes through its JSON encoding, so the YAML
+// keys and field order match the API and the JSON output.
+func writeYAML(w io.Writer, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return err
+	}
+	blockStyle(&node)
+
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&node); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// blockStyle resets the flow style that JSON input yields, so the document is
+// written in the usual block style.
+func blockStyle(node *yaml.Node) {
+	node.Style &^= yaml.FlowStyle | yaml.DoubleQuotedStyle
+	for _, child := range node.Content {
+		blockStyle(child)
+	}
+}
+
+// writeTasks writes tasks in the given format. meta, if not nil, is summarized
+// below the table.
+func writeTasks(w io.Writer, format string, tasks []client.Task, meta *rest.PaginationMeta) error {
+	switch format {
+	case FormatJSON:
+		return writeJSON(w, tasks)
+	case FormatYAML:
+		return writeYAML(w, tasks)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tTITLE\tSTATUS\tASSIGNEES\tWATCHERS\tDUE\tUPDATED")
+	for _, task := range tasks {
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			task.ID,
+			truncate(task.Title, 40),
+			task.Status,
+			formatIDs(task.AssigneeIDs),
+			formatIDs(task.WatcherIDs),
+			formatTime(task.DueAt),
+			formatTime(&task.UpdatedAt),
+		)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if meta != nil {
+		_, err := fmt.Fprintf(w, "\npage %d of %d, %d tasks\n", meta.Page, max(meta.TotalPages, 1), meta.Total)
+		return err
+	}
+	return nil
+}
+
+// writeTask writes a single task in the given format.
+func writeTask(w io.Writer, format string, task *client.Task) error {
+	switch format {
+	case FormatJSON:
+		return writeJSON(w, task)
+	case FormatYAML:
+		return writeYAML(w, task)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "ID:\t%d\n", task.ID)
+	fmt.Fprintf(tw, "Title:\t%s\n", task.Title)
+	if task.Description != "" {
+		fmt.Fprintf(tw, "Description:\t%s\n", task.Description)
+	}
+	fmt.Fprintf(tw, "Status:\t%s\n", task.Status)
+	fmt.Fprintf(tw, "Assignee:\t%d\n", task.AssigneeID)
+	fmt.Fprintf(tw, "Assignees:\t%s\n", formatIDs(task.AssigneeIDs))
+	fmt.Fprintf(tw, "Watchers:\t%s\n", formatIDs(task.WatcherIDs))
+	fmt.Fprintf(tw, "Due:\t%s\n", formatTime(task.DueAt))
+	if task.RecurrenceRule != "" {
+		fmt.Fprintf(tw, "Recurrence:\t%s\n", task.RecurrenceRule)
+	}
+	if task.RecurrenceParentID != nil {
+		fmt.Fprintf(tw, "Occurrence of:\t%d\n", *task.RecurrenceParentID)
+	}
+	fmt.Fprintf(tw, "Created:\t%s\n", formatTime(&task.CreatedAt))
+	fmt.Fprintf(tw, "Updated:\t%s\n", formatTime(&task.UpdatedAt))
+	return tw.Flush()
+}
+
+// formatIDs formats IDs as a comma separated list, or - if there are none.
+func formatIDs(ids []int64) string {
+	if len(ids) == 0 {
+		return "-"
+	}
+
+	values := make([]string, 0, len(ids))
+	for _, id := range ids {
+		values = append(values, strconv.FormatInt(id, 10))
+	}
+	return strings.Join(values, ",")
+}
+
+// formatTime formats a time in local time to the minute, or - if it is unset.
+func formatTime(t *time.Time) string {
+	if t == nil || t.IsZero() {
+		return "-"
+	}
+	return t.Local().Format("2006-01-02 15:04")
+}
+
+// truncate shortens s to at most n runes for the table.
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n-1]) + "…"
+}
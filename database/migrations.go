@@ -0,0 +1,40 @@
+// Package database embeds the SQL migrations of database/migrations, applied by
+// golang-migrate as <version>_<name>.up.sql and <version>_<name>.down.sql.
+package database
+
+import (
+	"embed"
+	"io/fs"
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+)
+
+// Migrations holds the up migrations.
+//
+//go:embed migrations/*.up.sql
+var Migrations embed.FS
+
+// LatestMigration returns the version of the last migration of fsys, holding
+// migrations/<version>_<name>.up.sql files.
+func LatestMigration(fsys fs.FS) (uint, error) {
+	files, err := fs.Glob(fsys, "migrations/*.up.sql")
+	if err != nil {
+		return 0, err
+	}
+
+	var latest uint
+	for _, file := range files {
+		prefix, _, _ := strings.Cut(strings.TrimPrefix(file, "migrations/"), "_")
+		version, err := strconv.ParseUint(prefix, 10, 64)
+		if err != nil {
+			return 0, errors.Wrapf(err, "invalid migration version of %s", file)
+		}
+		latest = max(latest, uint(version))
+	}
+	if latest == 0 {
+		return 0, errors.New("no migration found")
+	}
+	return latest, nil
+}
@@ -0,0 +1,2 @@
+ALTER TABLE tasks DROP CONSTRAINT IF EXISTS tasks_assignee_id_fkey;
+DROP TABLE IF EXISTS users;
@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS users (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    email VARCHAR(255) NOT NULL UNIQUE,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+    );
+
+-- Existing tasks were assigned to free-form IDs: create a placeholder user for each of
+-- them, to be renamed through the API, so that the foreign key holds.
+INSERT INTO users (id, name, email)
+SELECT DISTINCT assignee_id, 'User ' || assignee_id, 'user-' || assignee_id || '@placeholder.invalid'
+FROM tasks
+ON CONFLICT (id) DO NOTHING;
+
+SELECT setval(pg_get_serial_sequence('users', 'id'), GREATEST((SELECT COALESCE(MAX(id), 0) FROM users), 1),
+              (SELECT COUNT(*) > 0 FROM users));
+
+ALTER TABLE tasks
+    ADD CONSTRAINT tasks_assignee_id_fkey
+    FOREIGN KEY (assignee_id) REFERENCES users (id);
@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS task_assignees (
+    task_id INTEGER NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users (id),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (task_id, user_id)
+    );
+
+CREATE TABLE IF NOT EXISTS task_watchers (
+    task_id INTEGER NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (task_id, user_id)
+    );
+
+CREATE INDEX IF NOT EXISTS idx_task_assignees_user_id ON task_assignees (user_id);
+CREATE INDEX IF NOT EXISTS idx_task_watchers_user_id ON task_watchers (user_id);
+
+-- tasks.assignee_id stays the primary assignee and is always part of task_assignees
+INSERT INTO task_assignees (task_id, user_id)
+SELECT id, assignee_id FROM tasks
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_tasks_recurrence_occurrence;
+DROP INDEX IF EXISTS idx_tasks_recurrence_pending;
+
+ALTER TABLE tasks
+    DROP COLUMN IF EXISTS recurrence_materialized,
+    DROP COLUMN IF EXISTS recurrence_parent_id,
+    DROP COLUMN IF EXISTS recurrence_start_at,
+    DROP COLUMN IF EXISTS recurrence_rule,
+    DROP COLUMN IF EXISTS due_at;
@@ -0,0 +1,16 @@
+ALTER TABLE tasks
+    ADD COLUMN IF NOT EXISTS due_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN IF NOT EXISTS recurrence_rule TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS recurrence_start_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN IF NOT EXISTS recurrence_parent_id INTEGER REFERENCES tasks (id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS recurrence_materialized BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Occurrences still waiting for their successor, polled by the recurrence scheduler
+CREATE INDEX IF NOT EXISTS idx_tasks_recurrence_pending
+    ON tasks (due_at)
+    WHERE recurrence_rule <> '' AND NOT recurrence_materialized;
+
+-- A series can never get the same occurrence twice, even if two pods race
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tasks_recurrence_occurrence
+    ON tasks (recurrence_parent_id)
+    WHERE recurrence_parent_id IS NOT NULL;
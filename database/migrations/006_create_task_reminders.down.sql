@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_tasks_open_due_at;
+DROP TABLE IF EXISTS task_reminders;
@@ -0,0 +1,14 @@
+-- One row per notification sent for a task deadline, so reminders are never sent twice.
+-- due_at is the deadline the notification refers to: moving the deadline re-arms them.
+CREATE TABLE IF NOT EXISTS task_reminders (
+    task_id INTEGER NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('due_soon', 'overdue')),
+    due_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    sent_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (task_id, kind, due_at)
+    );
+
+-- Open tasks with a deadline, scanned by the reminder scheduler
+CREATE INDEX IF NOT EXISTS idx_tasks_open_due_at
+    ON tasks (due_at)
+    WHERE due_at IS NOT NULL AND status IN ('pending', 'in_progress');
@@ -0,0 +1,24 @@
+-- Transactional outbox: task events are written together with the task change and
+-- relayed to the event sinks afterwards, in id order per task
+CREATE TABLE IF NOT EXISTS outbox (
+    id BIGSERIAL PRIMARY KEY,
+    event_id VARCHAR(36) NOT NULL UNIQUE,
+    event_type VARCHAR(50) NOT NULL,
+    task_id BIGINT NOT NULL, -- no foreign key: task.deleted events outlive their task
+    previous_status VARCHAR(20) NOT NULL DEFAULT '',
+    task JSONB,
+    occurred_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    last_error TEXT NOT NULL DEFAULT '',
+    published_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+    );
+
+CREATE INDEX IF NOT EXISTS idx_outbox_unpublished
+    ON outbox (task_id, id)
+    WHERE published_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_outbox_published_at
+    ON outbox (published_at)
+    WHERE published_at IS NOT NULL;
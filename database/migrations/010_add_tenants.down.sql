@@ -0,0 +1,8 @@
+DROP POLICY IF EXISTS tasks_tenant_isolation ON tasks;
+ALTER TABLE tasks NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE tasks DISABLE ROW LEVEL SECURITY;
+
+DROP INDEX IF EXISTS idx_tasks_tenant_id;
+
+ALTER TABLE api_keys DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE tasks DROP COLUMN IF EXISTS tenant_id;
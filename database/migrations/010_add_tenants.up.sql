@@ -0,0 +1,18 @@
+-- Teams sharing a deployment are isolated by tenant. Existing rows belong to the
+-- default tenant.
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS tenant_id VARCHAR(64) NOT NULL DEFAULT 'default';
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS tenant_id VARCHAR(64) NOT NULL DEFAULT 'default';
+
+CREATE INDEX IF NOT EXISTS idx_tasks_tenant_id ON tasks (tenant_id, id);
+
+-- Row-level security backing the tenant scoping of the repository. The application
+-- sets app.tenant_id per transaction when TENANT_ROW_LEVEL_SECURITY is enabled;
+-- connections without it (migrations, background jobs) see every tenant.
+-- The policy only binds roles that do not own the table, unless it is forced with
+-- ALTER TABLE tasks FORCE ROW LEVEL SECURITY.
+ALTER TABLE tasks ENABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS tasks_tenant_isolation ON tasks;
+CREATE POLICY tasks_tenant_isolation ON tasks
+    USING (COALESCE(current_setting('app.tenant_id', TRUE), '') IN ('', tenant_id))
+    WITH CHECK (COALESCE(current_setting('app.tenant_id', TRUE), '') IN ('', tenant_id));
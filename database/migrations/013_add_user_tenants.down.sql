@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_users_tenant_id_email;
+ALTER TABLE users ADD CONSTRAINT users_email_key UNIQUE (email);
+
+ALTER TABLE users DROP COLUMN IF EXISTS tenant_id;
@@ -0,0 +1,23 @@
+-- Users belong to a tenant, whose tasks are the only ones they can be assigned to or
+-- watch. Existing users move to the tenant of the tasks referencing them when there is
+-- a single one, the others belong to the default tenant. Emails are unique per tenant.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS tenant_id VARCHAR(64) NOT NULL DEFAULT 'default';
+
+UPDATE users
+SET tenant_id = owners.tenant_id
+FROM (
+    SELECT user_id, MIN(tenant_id) AS tenant_id
+    FROM (
+        SELECT assignee_id AS user_id, tenant_id FROM tasks
+        UNION
+        SELECT a.user_id, t.tenant_id FROM task_assignees a JOIN tasks t ON t.id = a.task_id
+        UNION
+        SELECT w.user_id, t.tenant_id FROM task_watchers w JOIN tasks t ON t.id = w.task_id
+    ) memberships
+    GROUP BY user_id
+    HAVING COUNT(DISTINCT tenant_id) = 1
+) owners
+WHERE users.id = owners.user_id;
+
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_email_key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_tenant_id_email ON users (tenant_id, email);
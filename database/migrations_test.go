@@ -0,0 +1,37 @@
+package database_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"task-manager/database"
+)
+
+// TestLatestMigration tests that the version of the last migration is read from the
+// file names.
+func TestLatestMigration(t *testing.T) {
+	version, err := database.LatestMigration(fstest.MapFS{
+		"migrations/001_create_tasks.up.sql":   {},
+		"migrations/001_create_tasks.down.sql": {},
+		"migrations/012_add_index.up.sql":      {},
+		"migrations/002_create_users.up.sql":   {},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, uint(12), version)
+
+	_, err = database.LatestMigration(fstest.MapFS{"migrations/first.up.sql": {}})
+	assert.ErrorContains(t, err, "invalid migration version of migrations/first.up.sql")
+
+	_, err = database.LatestMigration(fstest.MapFS{})
+	assert.EqualError(t, err, "no migration found")
+}
+
+// TestMigrations_ShouldEmbedEveryMigration tests that the embedded migrations end with
+// the last migration of the repository.
+func TestMigrations_ShouldEmbedEveryMigration(t *testing.T) {
+	version, err := database.LatestMigration(database.Migrations)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, version, uint(13))
+}
@@ -24,7 +24,7 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/api/tasks": {
+        "/api/v1/tasks": {
             "get": {
                 "description": "Retrieves a paginated list of tasks with optional filters.",
                 "consumes": [
@@ -105,7 +105,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/tasks/": {
+        "/api/v1/tasks/": {
             "post": {
                 "description": "Creates a new task with a title, description, status, and assignee.",
                 "consumes": [
@@ -187,7 +187,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/tasks/{id}": {
+        "/api/v1/tasks/{id}": {
             "get": {
                 "description": "Retrieves the details of a specific task using its ID.",
                 "consumes": [
@@ -470,6 +470,571 @@ const docTemplate = `{
                     }
                 }
             }
+        },
+        "/api/v2/tasks": {
+            "get": {
+                "description": "Retrieves a paginated list of tasks with optional filters.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tasks"
+                ],
+                "summary": "List tasks",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Number of tasks per page",
+                        "name": "per_page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by any task field (e.g., title, status)",
+                        "name": "filter",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of tasks successfully fetched",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/task-manager_pkg_rest.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        " meta": {
+                                            "$ref": "#/definitions/task-manager_pkg_rest.PaginationMeta"
+                                        },
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/task-manager_internal_entities.Task"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/task-manager_pkg_rest.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v2/tasks/": {
+            "post": {
+                "description": "Creates a new task with a title, description, status, and assignees, the first one being the primary assignee.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tasks"
+                ],
+                "summary": "Create a new task",
+                "parameters": [
+                    {
+                        "description": "Task creation payload",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_http.CreateTaskRequestV2"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Task successfully created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/task-manager_pkg_rest.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/internal_http.TaskResponseV2"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload or invalid task status",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/task-manager_pkg_rest.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/task-manager_pkg_rest.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v2/tasks/{id}": {
+            "get": {
+                "description": "Retrieves the details of a specific task using its ID.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tasks"
+                ],
+                "summary": "Get task by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Task successfully fetched",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/task-manager_pkg_rest.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/internal_http.TaskResponseV2"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid task ID",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/task-manager_pkg_rest.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/task-manager_pkg_rest.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/task-manager_pkg_rest.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Updates the details of an existing task such as title, description, status, and assignees.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tasks"
+                ],
+                "summary": "Update an existing task",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated task data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_http.UpdateTaskRequestV2"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Task successfully updated",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/task-manager_pkg_rest.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/internal_http.TaskResponseV2"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid task ID or request payload",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/task-manager_pkg_rest.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/task-manager_pkg_rest.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/task-manager_pkg_rest.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Deletes an existing task identified by its ID.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tasks"
+                ],
+                "summary": "Delete a task",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Task successfully deleted"
+                    },
+                    "400": {
+                        "description": "Invalid task ID",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/task-manager_pkg_rest.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/task-manager_pkg_rest.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/task-manager_pkg_rest.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/health": {
+            "get": {
+                "description": "Runs every startup and readiness check, reporting the status, latency and error of each. Fails like the readiness probe.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Health"
+                ],
+                "summary": "Detailed health status",
+                "responses": {
+                    "200": {
+                        "description": "Instance is ready, optional checks may fail",
+                        "schema": {
+                            "$ref": "#/definitions/task-manager_internal_health.Report"
+                        }
+                    },
+                    "503": {
+                        "description": "Instance is not ready",
+                        "schema": {
+                            "$ref": "#/definitions/task-manager_internal_health.Report"
+                        }
+                    }
+                }
+            }
+        },
+        "/healthz": {
+            "get": {
+                "description": "Reports that the process serves requests. Dependencies are not checked: restarting the instance would not fix them.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Health"
+                ],
+                "summary": "Liveness probe",
+                "responses": {
+                    "200": {
+                        "description": "Instance is alive",
+                        "schema": {
+                            "$ref": "#/definitions/task-manager_internal_health.Report"
+                        }
+                    }
+                }
+            }
+        },
+        "/readyz": {
+            "get": {
+                "description": "Runs the readiness checks of the dependencies. Fails until the startup checks passed, while a required check fails, and once the instance is draining for shutdown.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Health"
+                ],
+                "summary": "Readiness probe",
+                "responses": {
+                    "200": {
+                        "description": "Instance is ready, optional checks may fail",
+                        "schema": {
+                            "$ref": "#/definitions/task-manager_internal_health.Report"
+                        }
+                    },
+                    "503": {
+                        "description": "Instance is not ready",
+                        "schema": {
+                            "$ref": "#/definitions/task-manager_internal_health.Report"
+                        }
+                    }
+                }
+            }
+        },
+        "/startupz": {
+            "get": {
+                "description": "Runs the startup checks, such as the database migrations, until they all passed once.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Health"
+                ],
+                "summary": "Startup probe",
+                "responses": {
+                    "200": {
+                        "description": "Instance started",
+                        "schema": {
+                            "$ref": "#/definitions/task-manager_internal_health.Report"
+                        }
+                    },
+                    "503": {
+                        "description": "Instance is starting",
+                        "schema": {
+                            "$ref": "#/definitions/task-manager_internal_health.Report"
+                        }
+                    }
+                }
+            }
+        },
+        "/version": {
+            "get": {
+                "description": "Reports the commit, build time, Go version and modules of the build, the container and the uptime of the instance.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Version"
+                ],
+                "summary": "Build information",
+                "responses": {
+                    "200": {
+                        "description": "Build of the instance",
+                        "schema": {
+                            "$ref": "#/definitions/internal_http.VersionResponse"
+                        }
+                    }
+                }
+            }
         }
     },
     "definitions": {
@@ -525,6 +1090,60 @@ const docTemplate = `{
                 "TaskStatusCanceled"
             ]
         },
+        "task-manager_internal_health.Report": {
+            "type": "object",
+            "properties": {
+                "checks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/task-manager_internal_health.Result"
+                    }
+                },
+                "draining": {
+                    "type": "boolean"
+                },
+                "started": {
+                    "type": "boolean"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "task-manager_internal_health.Result": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "latency_ms": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "optional": {
+                    "type": "boolean"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "task-manager_internal_version.Module": {
+            "type": "object",
+            "properties": {
+                "path": {
+                    "type": "string"
+                },
+                "sum": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "string"
+                }
+            }
+        },
         "task-manager_pkg_rest.PaginationMeta": {
             "type": "object",
             "properties": {
@@ -574,19 +1193,93 @@ const docTemplate = `{
                 }
             }
         },
+        "internal_http.AssigneeResponse": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
         "internal_http.CreateTaskRequest": {
             "type": "object",
             "required": [
-                "assignee_id",
                 "title"
             ],
             "properties": {
                 "assignee_id": {
                     "type": "integer"
                 },
+                "assignee_ids": {
+                    "description": "optional co-assignees",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "description": {
+                    "type": "string"
+                },
+                "due_at": {
+                    "description": "optional deadline, RFC 3339",
+                    "type": "string"
+                },
+                "recurrence_rule": {
+                    "description": "optional RRULE, e.g. FREQ=WEEKLY;BYDAY=MO, requires due_at",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "optional, default pending",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/task-manager_internal_entities.TaskStatus"
+                        }
+                    ]
+                },
+                "title": {
+                    "type": "string"
+                },
+                "watcher_ids": {
+                    "description": "optional watchers",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "internal_http.CreateTaskRequestV2": {
+            "type": "object",
+            "required": [
+                "assignee_ids",
+                "title"
+            ],
+            "properties": {
+                "assignee_ids": {
+                    "description": "the first one is the primary assignee",
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "integer"
+                    }
+                },
                 "description": {
                     "type": "string"
                 },
+                "due_at": {
+                    "description": "optional deadline, RFC 3339",
+                    "type": "string"
+                },
+                "recurrence_rule": {
+                    "description": "optional RRULE, e.g. FREQ=WEEKLY;BYDAY=MO, requires due_at",
+                    "type": "string"
+                },
                 "status": {
                     "description": "optional, default pending",
                     "allOf": [
@@ -597,6 +1290,13 @@ const docTemplate = `{
                 },
                 "title": {
                     "type": "string"
+                },
+                "watcher_ids": {
+                    "description": "optional watchers",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
                 }
             }
         },
@@ -629,10 +1329,63 @@ const docTemplate = `{
                 }
             }
         },
+        "internal_http.TaskResponseV2": {
+            "type": "object",
+            "properties": {
+                "assignee": {
+                    "description": "primary assignee, only with ?include=assignee",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/internal_http.AssigneeResponse"
+                        }
+                    ]
+                },
+                "assignee_ids": {
+                    "description": "the first one is the primary assignee",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "due_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "recurrence_parent_id": {
+                    "description": "occurrence this task was created from",
+                    "type": "integer"
+                },
+                "recurrence_rule": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/task-manager_internal_entities.TaskStatus"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "watcher_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
         "internal_http.UpdateTaskRequest": {
             "type": "object",
             "required": [
-                "assignee_id",
                 "status",
                 "title"
             ],
@@ -640,9 +1393,76 @@ const docTemplate = `{
                 "assignee_id": {
                     "type": "integer"
                 },
+                "assignee_ids": {
+                    "description": "replaces co-assignees when present",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "description": {
+                    "type": "string"
+                },
+                "due_at": {
+                    "description": "clears the deadline when absent",
+                    "type": "string"
+                },
+                "recurrence_rule": {
+                    "description": "stops the recurrence when absent",
+                    "type": "string"
+                },
+                "status": {
+                    "enum": [
+                        "pending",
+                        "in_progress",
+                        "done",
+                        "canceled"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/task-manager_internal_entities.TaskStatus"
+                        }
+                    ]
+                },
+                "title": {
+                    "type": "string"
+                },
+                "watcher_ids": {
+                    "description": "replaces watchers when present",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "internal_http.UpdateTaskRequestV2": {
+            "type": "object",
+            "required": [
+                "assignee_ids",
+                "status",
+                "title"
+            ],
+            "properties": {
+                "assignee_ids": {
+                    "description": "replaces the assignees, the first one is the primary assignee",
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "integer"
+                    }
+                },
                 "description": {
                     "type": "string"
                 },
+                "due_at": {
+                    "description": "clears the deadline when absent",
+                    "type": "string"
+                },
+                "recurrence_rule": {
+                    "description": "stops the recurrence when absent",
+                    "type": "string"
+                },
                 "status": {
                     "enum": [
                         "pending",
@@ -658,6 +1478,63 @@ const docTemplate = `{
                 },
                 "title": {
                     "type": "string"
+                },
+                "watcher_ids": {
+                    "description": "replaces watchers when present",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "internal_http.VersionResponse": {
+            "type": "object",
+            "properties": {
+                "build_time": {
+                    "type": "string"
+                },
+                "container_name": {
+                    "type": "string"
+                },
+                "deps": {
+                    "description": "Dependencies, after replacements",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/task-manager_internal_version.Module"
+                    }
+                },
+                "git_commit": {
+                    "type": "string"
+                },
+                "go_version": {
+                    "type": "string"
+                },
+                "module": {
+                    "description": "Main module",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/task-manager_internal_version.Module"
+                        }
+                    ]
+                },
+                "settings": {
+                    "description": "Build settings, e.g. vcs.revision, GOOS",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "start_time": {
+                    "type": "string"
+                },
+                "uptime": {
+                    "description": "e.g. 1h2m3s",
+                    "type": "string"
+                },
+                "uptime_seconds": {
+                    "description": "Uptime in seconds",
+                    "type": "number"
                 }
             }
         }
@@ -666,6 +1543,14 @@ const docTemplate = `{
         {
             "description": "Task management endpoints",
             "name": "Tasks"
+        },
+        {
+            "description": "Liveness, readiness and startup probes",
+            "name": "Health"
+        },
+        {
+            "description": "Build information of the instance",
+            "name": "Version"
         }
     ]
 }`
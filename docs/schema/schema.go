@@ -0,0 +1,10 @@
+// Package schema embeds the JSON Schemas of the request bodies, stored by API version
+// as <version>/<name>_schema.json.
+package schema
+
+import "embed"
+
+// FS holds the JSON Schemas, loaded by validation.Load.
+//
+//go:embed v1/*.json v2/*.json
+var FS embed.FS
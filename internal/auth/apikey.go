@@ -0,0 +1,76 @@
+package auth
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// APIKeyPrefix starts every API key, so leaked keys are easy to recognize.
+const APIKeyPrefix = "tm_"
+
+// APIKeyHashCost is the bcrypt cost of the stored API key hashes.
+const APIKeyHashCost = bcrypt.DefaultCost
+
+// APIKey is a generated API key: APIKeyPrefix, the public lookup prefix and the secret,
+// separated by underscores, e.g. tm_1f2e3d4c5b6a_<secret>. Only the prefix and the hash
+// of the secret are stored.
+type APIKey struct {
+	Key    string // Full key, handed out once to the caller
+	Prefix string // Public part identifying the key in storage and listings
+	Secret string // Secret part, only stored hashed
+}
+
+// GenerateAPIKey returns a new random API key.
+func GenerateAPIKey() (APIKey, error) {
+	prefix := make([]byte, 6)
+	secret := make([]byte, 32)
+	if _, err := rand.Read(prefix); err != nil {
+		return APIKey{}, err
+	}
+	if _, err := rand.Read(secret); err != nil {
+		return APIKey{}, err
+	}
+
+	key := APIKey{
+		Prefix: hex.EncodeToString(prefix),
+		Secret: base64.RawURLEncoding.EncodeToString(secret),
+	}
+	key.Key = APIKeyPrefix + key.Prefix + "_" + key.Secret
+	return key, nil
+}
+
+// ParseAPIKey splits an API key into its prefix and secret. It returns an error
+// wrapping ErrInvalidCredentials if key is not formatted like a generated key.
+func ParseAPIKey(key string) (APIKey, error) {
+	rest, ok := strings.CutPrefix(key, APIKeyPrefix)
+	if !ok {
+		return APIKey{}, fmt.Errorf("%w: malformed API key", ErrInvalidCredentials)
+	}
+
+	prefix, secret, ok := strings.Cut(rest, "_")
+	if !ok || prefix == "" || secret == "" {
+		return APIKey{}, fmt.Errorf("%w: malformed API key", ErrInvalidCredentials)
+	}
+
+	return APIKey{Key: key, Prefix: prefix, Secret: secret}, nil
+}
+
+// HashAPIKeySecret returns the bcrypt hash of the secret of an API key.
+func HashAPIKeySecret(secret string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(secret), APIKeyHashCost)
+	if err != nil {
+		return "", errors.Wrap(err, "hash API key")
+	}
+	return string(hash), nil
+}
+
+// VerifyAPIKeySecret reports whether secret matches the hash of HashAPIKeySecret.
+func VerifyAPIKeySecret(hash, secret string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(secret)) == nil
+}
@@ -0,0 +1,149 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"task-manager/internal/config"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// DefaultJWTAlgorithms are the signing algorithms accepted when none are configured.
+var DefaultJWTAlgorithms = []string{"HS256", "HS384", "HS512", "RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}
+
+// JWTSettings configures the validation of the JWT claims.
+type JWTSettings struct {
+	Issuer     string        // Required iss claim, not checked if empty
+	Audience   string        // Required aud claim, not checked if empty
+	Leeway     time.Duration // Clock skew tolerated on exp, nbf and iat
+	Algorithms []string      // Accepted signing algorithms, DefaultJWTAlgorithms if empty
+}
+
+// Claims are the JWT claims mapped to a Principal, next to the registered ones:
+// sub is the subject and, if numeric, the user ID, unless user_id is set; roles
+// and scope (space separated, or the scp array) become the roles and scopes, and
+// tenant_id the tenant.
+type Claims struct {
+	jwt.RegisteredClaims
+	UserID   int64    `json:"user_id,omitempty"`
+	Name     string   `json:"name,omitempty"`
+	Roles    []string `json:"roles,omitempty"`
+	Scope    string   `json:"scope,omitempty"`
+	Scp      []string `json:"scp,omitempty"`
+	TenantID string   `json:"tenant_id,omitempty"`
+}
+
+// JWTAuthenticator authenticates Bearer JSON Web Tokens signed with a key of its
+// KeySource.
+type JWTAuthenticator struct {
+	keys   KeySource
+	parser *jwt.Parser
+}
+
+// NewJWTAuthenticator returns an authenticator verifying tokens with keys.
+func NewJWTAuthenticator(keys KeySource, settings JWTSettings) *JWTAuthenticator {
+	if len(settings.Algorithms) == 0 {
+		settings.Algorithms = DefaultJWTAlgorithms
+	}
+
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods(settings.Algorithms),
+		jwt.WithLeeway(settings.Leeway),
+		jwt.WithExpirationRequired(),
+	}
+	if settings.Issuer != "" {
+		options = append(options, jwt.WithIssuer(settings.Issuer))
+	}
+	if settings.Audience != "" {
+		options = append(options, jwt.WithAudience(settings.Audience))
+	}
+
+	return &JWTAuthenticator{keys: keys, parser: jwt.NewParser(options...)}
+}
+
+// NewJWTAuthenticatorFromConfig returns the JWT authenticator described by the
+// config, with the key sources it lists: the shared secret, the public key file, the
+// local JWKS file and the JWKS URL.
+func NewJWTAuthenticatorFromConfig(cfg config.AuthConfig) (*JWTAuthenticator, error) {
+	var keys KeySources
+	if cfg.JWTSecret != "" {
+		keys = append(keys, HMACSecret([]byte(cfg.JWTSecret)))
+	}
+	if cfg.JWTPublicKeyFile != "" {
+		source, err := LoadPublicKeyFile(cfg.JWTPublicKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, source)
+	}
+	if cfg.JWKSFile != "" {
+		source, err := LoadJWKSFile(cfg.JWKSFile)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, source)
+	}
+	if cfg.JWKSURL != "" {
+		keys = append(keys, NewRemoteJWKS(cfg.JWKSURL, cfg.JWKSRefresh, nil))
+	}
+
+	if len(keys) == 0 {
+		return nil, errors.New("authentication is enabled but no JWT key is configured")
+	}
+
+	return NewJWTAuthenticator(keys, JWTSettings{
+		Issuer:     cfg.JWTIssuer,
+		Audience:   cfg.JWTAudience,
+		Leeway:     cfg.JWTLeeway,
+		Algorithms: cfg.JWTAlgorithms,
+	}), nil
+}
+
+// Scheme implements Authenticator.
+func (a *JWTAuthenticator) Scheme() string {
+	return "Bearer"
+}
+
+// Authenticate implements Authenticator.
+func (a *JWTAuthenticator) Authenticate(ctx context.Context, credentials string) (*Principal, error) {
+	var claims Claims
+	_, err := a.parser.ParseWithClaims(credentials, &claims, func(token *jwt.Token) (any, error) {
+		kid, _ := token.Header["kid"].(string)
+		return a.keys.Key(ctx, kid, token.Method.Alg())
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidCredentials, err)
+	}
+
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("%w: token has no sub claim", ErrInvalidCredentials)
+	}
+
+	return claims.principal(), nil
+}
+
+// principal maps the claims to a Principal.
+func (c *Claims) principal() *Principal {
+	principal := &Principal{
+		Subject:  c.Subject,
+		UserID:   c.UserID,
+		Name:     c.Name,
+		Roles:    c.Roles,
+		Scopes:   c.Scp,
+		TenantID: c.TenantID,
+		Method:   MethodJWT,
+	}
+
+	if principal.UserID == 0 {
+		principal.UserID, _ = strconv.ParseInt(c.Subject, 10, 64)
+	}
+	if c.Scope != "" {
+		principal.Scopes = strings.Fields(c.Scope)
+	}
+
+	return principal
+}
@@ -0,0 +1,235 @@
+package auth_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"task-manager/internal/auth"
+	"task-manager/internal/config"
+	"testing"
+	"time"
+)
+
+// sign returns a token signed with key, expiring in an hour unless claims say otherwise.
+func sign(t *testing.T, method jwt.SigningMethod, key any, kid string, claims jwt.MapClaims) string {
+	t.Helper()
+
+	if _, ok := claims["exp"]; !ok {
+		claims["exp"] = time.Now().Add(time.Hour).Unix()
+	}
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+
+	signed, err := token.SignedString(key)
+	require.NoError(t, err)
+	return signed
+}
+
+// writeFile writes data to a temporary file and returns its path.
+func writeFile(t *testing.T, name string, data []byte) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+	return path
+}
+
+// jwks returns the JSON Web Key Set of the given EC keys by kid.
+func jwks(t *testing.T, keys map[string]*ecdsa.PrivateKey) []byte {
+	t.Helper()
+
+	var set struct {
+		Keys []map[string]string `json:"keys"`
+	}
+	for kid, key := range keys {
+		set.Keys = append(set.Keys, map[string]string{
+			"kty": "EC", "kid": kid, "alg": "ES256", "use": "sig", "crv": "P-256",
+			"x": base64.RawURLEncoding.EncodeToString(key.X.FillBytes(make([]byte, 32))),
+			"y": base64.RawURLEncoding.EncodeToString(key.Y.FillBytes(make([]byte, 32))),
+		})
+	}
+
+	data, err := json.Marshal(set)
+	require.NoError(t, err)
+	return data
+}
+
+// TestJWTAuthenticator_HS256_ShouldMapClaims tests that the claims of a valid token become the principal.
+func TestJWTAuthenticator_HS256_ShouldMapClaims(t *testing.T) {
+	secret := []byte("s3cr3t")
+	authenticator := auth.NewJWTAuthenticator(auth.HMACSecret(secret), auth.JWTSettings{Issuer: "https://id.example.com", Audience: "tasks"})
+
+	token := sign(t, jwt.SigningMethodHS256, secret, "", jwt.MapClaims{
+		"sub": "42", "iss": "https://id.example.com", "aud": "tasks", "name": "Ada",
+		"roles": []string{"admin"}, "scope": "tasks:read tasks:write", "tenant_id": "acme",
+	})
+
+	principal, err := authenticator.Authenticate(context.Background(), token)
+
+	require.NoError(t, err)
+	assert.Equal(t, &auth.Principal{
+		Subject: "42", UserID: 42, Name: "Ada", Roles: []string{"admin"},
+		Scopes: []string{"tasks:read", "tasks:write"}, Method: auth.MethodJWT, TenantID: "acme",
+	}, principal)
+	assert.True(t, principal.HasScope("tasks:write"))
+}
+
+// TestJWTAuthenticator_InvalidTokens_ShouldFail tests the rejected tokens.
+func TestJWTAuthenticator_InvalidTokens_ShouldFail(t *testing.T) {
+	secret := []byte("s3cr3t")
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	authenticator := auth.NewJWTAuthenticator(
+		auth.KeySources{auth.HMACSecret(secret), staticRSA{&rsaKey.PublicKey}},
+		auth.JWTSettings{Issuer: "https://id.example.com", Algorithms: []string{"HS256", "RS256"}},
+	)
+	pem := x509.MarshalPKCS1PublicKey(&rsaKey.PublicKey)
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"malformed", "not-a-token"},
+		{"wrong secret", sign(t, jwt.SigningMethodHS256, []byte("other"), "", jwt.MapClaims{"sub": "1", "iss": "https://id.example.com"})},
+		{"expired", sign(t, jwt.SigningMethodHS256, secret, "", jwt.MapClaims{"sub": "1", "iss": "https://id.example.com", "exp": time.Now().Add(-time.Minute).Unix()})},
+		{"no expiry", sign(t, jwt.SigningMethodHS256, secret, "", jwt.MapClaims{"sub": "1", "iss": "https://id.example.com", "exp": nil})},
+		{"wrong issuer", sign(t, jwt.SigningMethodHS256, secret, "", jwt.MapClaims{"sub": "1", "iss": "https://evil.example.com"})},
+		{"missing subject", sign(t, jwt.SigningMethodHS256, secret, "", jwt.MapClaims{"iss": "https://id.example.com"})},
+		{"algorithm not allowed", sign(t, jwt.SigningMethodHS512, secret, "", jwt.MapClaims{"sub": "1", "iss": "https://id.example.com"})},
+		{"public key as HMAC secret", sign(t, jwt.SigningMethodHS256, pem, "", jwt.MapClaims{"sub": "1", "iss": "https://id.example.com"})},
+		{"none algorithm", sign(t, jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, "", jwt.MapClaims{"sub": "1", "iss": "https://id.example.com"})},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			principal, err := authenticator.Authenticate(context.Background(), tt.token)
+
+			assert.Nil(t, principal)
+			assert.ErrorIs(t, err, auth.ErrInvalidCredentials)
+		})
+	}
+}
+
+// staticRSA is a key source of a single RSA public key.
+type staticRSA struct {
+	key *rsa.PublicKey
+}
+
+func (s staticRSA) Key(_ context.Context, _, alg string) (any, error) {
+	if alg != "RS256" {
+		return nil, auth.ErrKeyNotFound
+	}
+	return s.key, nil
+}
+
+// TestLoadPublicKeyFile_RS256_ShouldVerify tests tokens verified with a PEM public key.
+func TestLoadPublicKeyFile_RS256_ShouldVerify(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+
+	path := writeFile(t, "key.pem", pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+	principal, err := authenticate(t, config.AuthConfig{JWTPublicKeyFile: path}, sign(t, jwt.SigningMethodRS256, key, "", jwt.MapClaims{"sub": "svc-backup"}))
+
+	require.NoError(t, err)
+	assert.Equal(t, "svc-backup", principal.Subject)
+	assert.Zero(t, principal.UserID)
+}
+
+// TestLoadJWKSFile_ES256_ShouldSelectKeyByKid tests tokens verified with the key of their kid.
+func TestLoadJWKSFile_ES256_ShouldSelectKeyByKid(t *testing.T) {
+	first, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	second, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	cfg := config.AuthConfig{JWKSFile: writeFile(t, "jwks.json", jwks(t, map[string]*ecdsa.PrivateKey{"first": first, "second": second}))}
+
+	principal, err := authenticate(t, cfg, sign(t, jwt.SigningMethodES256, second, "second", jwt.MapClaims{"sub": "7"}))
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), principal.UserID)
+
+	_, err = authenticate(t, cfg, sign(t, jwt.SigningMethodES256, second, "first", jwt.MapClaims{"sub": "7"}))
+	assert.ErrorIs(t, err, auth.ErrInvalidCredentials)
+
+	_, err = authenticate(t, cfg, sign(t, jwt.SigningMethodES256, second, "", jwt.MapClaims{"sub": "7"}))
+	assert.ErrorIs(t, err, auth.ErrInvalidCredentials, "a token without kid is ambiguous with two keys")
+}
+
+// TestRemoteJWKS_ShouldCacheAndRefetchOnUnknownKid tests that the set is cached and fetched again for rotated keys.
+func TestRemoteJWKS_ShouldCacheAndRefetchOnUnknownKid(t *testing.T) {
+	old, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	rotated, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	var (
+		fetches atomic.Int32
+		set     atomic.Value
+	)
+	set.Store(jwks(t, map[string]*ecdsa.PrivateKey{"old": old}))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		_, _ = w.Write(set.Load().([]byte))
+	}))
+	defer server.Close()
+
+	authenticator := auth.NewJWTAuthenticator(auth.NewRemoteJWKS(server.URL, time.Hour, nil), auth.JWTSettings{})
+
+	for range 3 {
+		_, err := authenticator.Authenticate(context.Background(), sign(t, jwt.SigningMethodES256, old, "old", jwt.MapClaims{"sub": "1"}))
+		require.NoError(t, err)
+	}
+	assert.Equal(t, int32(1), fetches.Load())
+
+	// Unknown kids are not fetched again right away, so they can't hammer the provider
+	set.Store(jwks(t, map[string]*ecdsa.PrivateKey{"old": old, "rotated": rotated}))
+	_, err = authenticator.Authenticate(context.Background(), sign(t, jwt.SigningMethodES256, rotated, "rotated", jwt.MapClaims{"sub": "1"}))
+	assert.ErrorIs(t, err, auth.ErrInvalidCredentials)
+	assert.Equal(t, int32(1), fetches.Load())
+}
+
+// TestNewJWTAuthenticatorFromConfig_NoKey_ShouldFail tests that enabling authentication requires a key.
+func TestNewJWTAuthenticatorFromConfig_NoKey_ShouldFail(t *testing.T) {
+	_, err := auth.NewJWTAuthenticatorFromConfig(config.AuthConfig{Enabled: true})
+	assert.Error(t, err)
+
+	_, err = auth.NewJWTAuthenticatorFromConfig(config.AuthConfig{JWTPublicKeyFile: "missing.pem"})
+	assert.Error(t, err)
+}
+
+// TestParseJWKS_InvalidPoint_ShouldFail tests that EC keys off their curve are rejected.
+func TestParseJWKS_InvalidPoint_ShouldFail(t *testing.T) {
+	one := base64.RawURLEncoding.EncodeToString(big.NewInt(1).Bytes())
+	_, err := auth.ParseJWKS([]byte(`{"keys": [{"kty": "EC", "crv": "P-256", "x": "` + one + `", "y": "` + one + `"}]}`))
+
+	assert.Error(t, err)
+}
+
+// authenticate authenticates token with the authenticator of cfg.
+func authenticate(t *testing.T, cfg config.AuthConfig, token string) (*auth.Principal, error) {
+	t.Helper()
+
+	authenticator, err := auth.NewJWTAuthenticatorFromConfig(cfg)
+	require.NoError(t, err)
+	return authenticator.Authenticate(context.Background(), token)
+}
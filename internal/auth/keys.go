@@ -0,0 +1,355 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/errors"
+)
+
+// Defaults of a RemoteJWKS.
+const (
+	DefaultJWKSRefresh    = time.Hour
+	DefaultJWKSMinRefetch = 30 * time.Second
+	DefaultJWKSTimeout    = 10 * time.Second
+)
+
+// ErrKeyNotFound is returned by a KeySource without a key for a token.
+var ErrKeyNotFound = errors.New("no key found for the token")
+
+// KeySource resolves the key verifying the signature of a token, by the key ID
+// (kid header, possibly empty) and the algorithm (alg header) of the token.
+//
+// The type of the key tells the signing method it may verify: []byte for HMAC,
+// *rsa.PublicKey for RSA and *ecdsa.PublicKey for ECDSA, so a token can't get an
+// RSA public key accepted as an HMAC secret.
+type KeySource interface {
+	Key(ctx context.Context, kid, alg string) (any, error)
+}
+
+// KeySources tries every key source in order, returning the first key found.
+type KeySources []KeySource
+
+// Key implements KeySource.
+func (s KeySources) Key(ctx context.Context, kid, alg string) (any, error) {
+	for _, source := range s {
+		key, err := source.Key(ctx, kid, alg)
+		if err == nil {
+			return key, nil
+		}
+		if !errors.Is(err, ErrKeyNotFound) {
+			return nil, err
+		}
+	}
+	return nil, ErrKeyNotFound
+}
+
+// staticKey is a single key verifying tokens of matching algorithms, whatever their kid.
+type staticKey struct {
+	key any
+}
+
+// HMACSecret returns the key source of a shared secret verifying HS256, HS384 and
+// HS512 tokens.
+func HMACSecret(secret []byte) KeySource {
+	return staticKey{key: secret}
+}
+
+// Key implements KeySource.
+func (s staticKey) Key(_ context.Context, _, alg string) (any, error) {
+	if !keyMatches(s.key, alg) {
+		return nil, ErrKeyNotFound
+	}
+	return s.key, nil
+}
+
+// LoadPublicKeyFile reads a PEM encoded RSA or ECDSA public key (PKIX or PKCS #1) or
+// certificate, verifying RS*/PS* or ES* tokens respectively.
+func LoadPublicKeyFile(path string) (KeySource, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "read public key")
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.Newf("%s: no PEM data found", path)
+	}
+
+	var key any
+	switch block.Type {
+	case "PUBLIC KEY":
+		key, err = x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		key, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	case "CERTIFICATE":
+		var cert *x509.Certificate
+		if cert, err = x509.ParseCertificate(block.Bytes); err == nil {
+			key = cert.PublicKey
+		}
+	default:
+		return nil, errors.Newf("%s: unsupported PEM block %q", path, block.Type)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "%s: parse public key", path)
+	}
+
+	switch key.(type) {
+	case *rsa.PublicKey, *ecdsa.PublicKey:
+		return staticKey{key: key}, nil
+	}
+	return nil, errors.Newf("%s: unsupported public key type %T", path, key)
+}
+
+// JWKS is a JSON Web Key Set (RFC 7517) of RSA and EC signing keys.
+type JWKS struct {
+	keys []jwk
+}
+
+// jwk is a parsed key of a set.
+type jwk struct {
+	kid string
+	alg string
+	key any
+}
+
+// ParseJWKS parses a JSON Web Key Set. Keys that are not RSA or EC signing keys are skipped.
+func ParseJWKS(data []byte) (*JWKS, error) {
+	var set struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			Alg string `json:"alg"`
+			Use string `json:"use"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+			Crv string `json:"crv"`
+			X   string `json:"x"`
+			Y   string `json:"y"`
+		} `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, errors.Wrap(err, "parse JWKS")
+	}
+
+	jwks := &JWKS{}
+	for i, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+
+		var (
+			key any
+			err error
+		)
+		switch k.Kty {
+		case "RSA":
+			key, err = rsaKey(k.N, k.E)
+		case "EC":
+			key, err = ecKey(k.Crv, k.X, k.Y)
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "parse JWKS key %d (kid %q)", i, k.Kid)
+		}
+
+		jwks.keys = append(jwks.keys, jwk{kid: k.Kid, alg: k.Alg, key: key})
+	}
+
+	return jwks, nil
+}
+
+// LoadJWKSFile reads a JSON Web Key Set from a local file.
+func LoadJWKSFile(path string) (*JWKS, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "read JWKS")
+	}
+	return ParseJWKS(data)
+}
+
+// Key implements KeySource. A token without kid is verified by the only key of the
+// set matching its algorithm, if there is exactly one.
+func (s *JWKS) Key(_ context.Context, kid, alg string) (any, error) {
+	var found []any
+	for _, k := range s.keys {
+		if kid != "" && k.kid != kid || k.alg != "" && k.alg != alg || !keyMatches(k.key, alg) {
+			continue
+		}
+		found = append(found, k.key)
+	}
+
+	if len(found) != 1 {
+		return nil, ErrKeyNotFound
+	}
+	return found[0], nil
+}
+
+// RemoteJWKS is a JSON Web Key Set fetched from a URL, such as the jwks_uri of an
+// identity provider. The set is fetched again once it is older than the refresh
+// interval, or when a token names an unknown kid (rotated keys), at most once every
+// DefaultJWKSMinRefetch.
+type RemoteJWKS struct {
+	url     string
+	client  *http.Client
+	refresh time.Duration
+
+	mu        sync.Mutex
+	set       *JWKS
+	fetchedAt time.Time
+}
+
+// NewRemoteJWKS returns the key set served at url, refreshed every refresh
+// (DefaultJWKSRefresh if zero). It is fetched on first use.
+func NewRemoteJWKS(url string, refresh time.Duration, client *http.Client) *RemoteJWKS {
+	if refresh <= 0 {
+		refresh = DefaultJWKSRefresh
+	}
+	if client == nil {
+		client = &http.Client{Timeout: DefaultJWKSTimeout}
+	}
+
+	return &RemoteJWKS{url: url, client: client, refresh: refresh}
+}
+
+// Key implements KeySource.
+func (r *RemoteJWKS) Key(ctx context.Context, kid, alg string) (any, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.set == nil || time.Since(r.fetchedAt) > r.refresh {
+		if err := r.fetch(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	key, err := r.set.Key(ctx, kid, alg)
+	if errors.Is(err, ErrKeyNotFound) && kid != "" && time.Since(r.fetchedAt) > DefaultJWKSMinRefetch {
+		if err := r.fetch(ctx); err != nil {
+			return nil, err
+		}
+		key, err = r.set.Key(ctx, kid, alg)
+	}
+
+	return key, err
+}
+
+// fetch downloads the key set. The previous set is kept on failure, so a provider
+// outage does not reject tokens signed with known keys before the refresh.
+func (r *RemoteJWKS) fetch(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return r.fetchFailed(errors.Wrap(err, "fetch JWKS"))
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return r.fetchFailed(errors.Newf("fetch JWKS: status %d", resp.StatusCode))
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return r.fetchFailed(errors.Wrap(err, "fetch JWKS"))
+	}
+
+	set, err := ParseJWKS(data)
+	if err != nil {
+		return r.fetchFailed(err)
+	}
+
+	r.set = set
+	r.fetchedAt = time.Now()
+	return nil
+}
+
+// fetchFailed keeps using the previous set, if any, until the next refresh.
+func (r *RemoteJWKS) fetchFailed(err error) error {
+	if r.set == nil {
+		return err
+	}
+	r.fetchedAt = time.Now()
+	return nil
+}
+
+// keyMatches reports whether key may verify tokens signed with alg.
+func keyMatches(key any, alg string) bool {
+	switch key.(type) {
+	case []byte:
+		return strings.HasPrefix(alg, "HS")
+	case *rsa.PublicKey:
+		return strings.HasPrefix(alg, "RS") || strings.HasPrefix(alg, "PS")
+	case *ecdsa.PublicKey:
+		return strings.HasPrefix(alg, "ES")
+	}
+	return false
+}
+
+// rsaKey decodes the base64url modulus and exponent of an RSA JWK.
+func rsaKey(n, e string) (*rsa.PublicKey, error) {
+	modulus, err := base64.RawURLEncoding.DecodeString(n)
+	if err != nil {
+		return nil, errors.Wrap(err, "decode n")
+	}
+	exponent, err := base64.RawURLEncoding.DecodeString(e)
+	if err != nil {
+		return nil, errors.Wrap(err, "decode e")
+	}
+	if len(modulus) == 0 || len(exponent) == 0 || len(exponent) > 4 {
+		return nil, errors.New("invalid RSA key")
+	}
+
+	return &rsa.PublicKey{
+		N: new(big.Int).SetBytes(modulus),
+		E: int(new(big.Int).SetBytes(exponent).Int64()),
+	}, nil
+}
+
+// ecKey decodes the curve and base64url coordinates of an EC JWK.
+func ecKey(crv, x, y string) (*ecdsa.PublicKey, error) {
+	var curve elliptic.Curve
+	switch crv {
+	case "P-256":
+		curve = elliptic.P256()
+	case "P-384":
+		curve = elliptic.P384()
+	case "P-521":
+		curve = elliptic.P521()
+	default:
+		return nil, errors.Newf("unsupported curve %q", crv)
+	}
+
+	xBytes, err := base64.RawURLEncoding.DecodeString(x)
+	if err != nil {
+		return nil, errors.Wrap(err, "decode x")
+	}
+	yBytes, err := base64.RawURLEncoding.DecodeString(y)
+	if err != nil {
+		return nil, errors.Wrap(err, "decode y")
+	}
+
+	key := &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(xBytes), Y: new(big.Int).SetBytes(yBytes)}
+	if !curve.IsOnCurve(key.X, key.Y) {
+		return nil, errors.New("point is not on the curve")
+	}
+	return key, nil
+}
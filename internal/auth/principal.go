@@ -0,0 +1,111 @@
+// Package auth authenticates the callers of the API.
+//
+// Every authentication method (JWT bearer tokens, API keys, ...) implements
+// Authenticator and yields a Principal, which the transport layer stores in the
+// request context for the services to consult.
+package auth
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"task-manager/pkg/logger"
+
+	"github.com/cockroachdb/errors"
+)
+
+// Authentication methods of a Principal.
+const (
+	MethodJWT    = "jwt"
+	MethodAPIKey = "api_key"
+)
+
+// Scopes an API key can be limited to.
+const (
+	ScopeTasksRead     = "tasks:read"
+	ScopeTasksWrite    = "tasks:write"
+	ScopeUsersRead     = "users:read"
+	ScopeUsersWrite    = "users:write"
+	ScopeWebhooksRead  = "webhooks:read"
+	ScopeWebhooksWrite = "webhooks:write"
+)
+
+// Scopes lists every scope an API key can be issued with.
+var Scopes = []string{
+	ScopeTasksRead, ScopeTasksWrite,
+	ScopeUsersRead, ScopeUsersWrite,
+	ScopeWebhooksRead, ScopeWebhooksWrite,
+}
+
+// RoleAdmin is the role managing the API keys.
+const RoleAdmin = "admin"
+
+// RolePlatformAdmin is the role of the operators of the deployment, who may act in
+// any tenant. Tenant admins only have RoleAdmin, within their own tenant.
+const RolePlatformAdmin = "platform_admin"
+
+// ErrUnauthenticated is returned when a request carries no credentials.
+var ErrUnauthenticated = errors.New("missing credentials")
+
+// ErrInvalidCredentials is returned when the credentials of a request are rejected.
+var ErrInvalidCredentials = errors.New("invalid credentials")
+
+// Principal is the authenticated caller of a request.
+type Principal struct {
+	Subject string   // Stable identifier of the caller, e.g. the sub claim of a token
+	UserID  int64    // User the caller acts as, 0 if it is not a user (e.g. a service)
+	Name    string   // Display name, if known
+	Roles   []string // Roles granted to the caller
+	Scopes  []string // Scopes the credentials are limited to, e.g. tasks:read
+	Method  string   // Authentication method, e.g. MethodJWT
+
+	TenantID string // Tenant the caller belongs to, empty for the default tenant
+}
+
+// HasRole reports whether the principal was granted role.
+func (p *Principal) HasRole(role string) bool {
+	return slices.Contains(p.Roles, role)
+}
+
+// HasScope reports whether the credentials of the principal include scope.
+func (p *Principal) HasScope(scope string) bool {
+	return slices.Contains(p.Scopes, scope)
+}
+
+// Permits reports whether the credentials of the principal allow acting within scope.
+// API keys are limited to the scopes they were issued with; user tokens are not, their
+// scopes are the ones of the identity provider.
+func (p *Principal) Permits(scope string) bool {
+	return p.Method != MethodAPIKey || p.HasScope(scope)
+}
+
+// Authenticator verifies the credentials sent with one Authorization scheme.
+type Authenticator interface {
+	// Scheme returns the Authorization scheme handled, e.g. Bearer.
+	Scheme() string
+	// Authenticate returns the principal of the credentials following the scheme,
+	// or an error wrapping ErrInvalidCredentials.
+	Authenticate(ctx context.Context, credentials string) (*Principal, error)
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns a copy of ctx carrying principal.
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFromContext returns the principal of ctx, if the request was authenticated.
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(*Principal)
+	return principal, ok && principal != nil
+}
+
+// LogAttrs returns the subject of the principal of ctx, logged with the records of the
+// authenticated requests (see logger.WithContextAttrs).
+func LogAttrs(ctx context.Context) []slog.Attr {
+	if principal, ok := PrincipalFromContext(ctx); ok {
+		return []slog.Attr{slog.String(logger.PrincipalKey, principal.Subject)}
+	}
+	return nil
+}
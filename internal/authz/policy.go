@@ -0,0 +1,234 @@
+// Package authz decides which operations the principals of package auth may
+// perform on tasks, and which of them may manage the users and webhooks.
+//
+// Decisions follow a role based Policy, configured in YAML: every role is allowed
+// a set of actions on any task, and another set on the tasks its user is assigned
+// to. The services consult an Authorizer before acting, so every transport (REST,
+// gRPC, GraphQL) enforces the same rules.
+package authz
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"os"
+	"slices"
+	"task-manager/internal/auth"
+	"task-manager/internal/entities"
+
+	"github.com/cockroachdb/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// Action is an operation on tasks, or a management operation of the deployment.
+type Action string
+
+// Actions of the policy.
+const (
+	ActionTaskRead     Action = "task:read"
+	ActionTaskCreate   Action = "task:create"
+	ActionTaskUpdate   Action = "task:update"
+	ActionTaskDelete   Action = "task:delete"
+	ActionTaskAssign   Action = "task:assign"   // Add or remove co-assignees
+	ActionTaskWatch    Action = "task:watch"    // Add or remove watchers
+	ActionUserWrite    Action = "user:write"    // Create, update or delete users
+	ActionWebhookWrite Action = "webhook:write" // Create, update or delete webhooks and retry their deliveries
+	ActionAll          Action = "*"             // Every action
+)
+
+// Actions lists every action a role can be granted.
+var Actions = []Action{
+	ActionTaskRead, ActionTaskCreate, ActionTaskUpdate,
+	ActionTaskDelete, ActionTaskAssign, ActionTaskWatch,
+	ActionUserWrite, ActionWebhookWrite,
+}
+
+// Roles of the default policy.
+const (
+	RoleViewer = "viewer"
+	RoleMember = "member"
+	RoleAdmin  = auth.RoleAdmin
+)
+
+// ErrForbidden is returned when the principal of a request may not perform an action.
+var ErrForbidden = errors.New("forbidden")
+
+// ErrInvalidPolicy is returned when a policy can't be parsed or references unknown
+// actions or roles.
+var ErrInvalidPolicy = errors.New("invalid authorization policy")
+
+//go:embed policy.yaml
+var defaultPolicy []byte
+
+// Authorizer decides whether the principal of a context may perform an action.
+type Authorizer interface {
+	// Authorize returns nil if the principal of ctx may perform action, an error
+	// wrapping ErrForbidden otherwise. task loads the task acted upon and is only
+	// called when ownership decides; it is nil for actions not bound to an existing
+	// task, such as creating or listing tasks. Errors of task are returned as is.
+	Authorize(ctx context.Context, action Action, task func() (*entities.Task, error)) error
+}
+
+// Grants are the actions of a role.
+type Grants struct {
+	Allow []Action `yaml:"allow"` // Actions allowed on any task
+	Own   []Action `yaml:"own"`   // Actions allowed on the tasks the user is assigned to
+}
+
+type internalCallerKey struct{}
+
+// WithInternalCaller returns a copy of ctx acting on behalf of the deployment itself:
+// the background jobs (scheduler, outbox relay, ...) and the requests of a server
+// running without authentication. The policy does not restrict them.
+func WithInternalCaller(ctx context.Context) context.Context {
+	return context.WithValue(ctx, internalCallerKey{}, true)
+}
+
+// IsInternalCaller reports whether ctx was marked by WithInternalCaller.
+func IsInternalCaller(ctx context.Context) bool {
+	internal, _ := ctx.Value(internalCallerKey{}).(bool)
+	return internal
+}
+
+// Policy is the role based Authorizer.
+//
+// The roles of a principal are the ones it was granted that the policy knows of;
+// users without any fall back to DefaultRoles, API keys always get APIKeyRoles.
+// Internal callers (see WithInternalCaller) are allowed everything, contexts with
+// neither a principal nor the internal caller mark nothing.
+type Policy struct {
+	Roles        map[string]Grants `yaml:"roles"`         // Grants by role
+	DefaultRoles []string          `yaml:"default_roles"` // Roles of users without any role of the policy
+	APIKeyRoles  []string          `yaml:"api_key_roles"` // Roles of API keys, on top of their scopes
+}
+
+// DefaultPolicy
+//
+// Returns the built-in policy: viewers read, members also create tasks, watch them
+// and update the tasks they are assigned to, admins do anything, including managing
+// the users and webhooks.
+func DefaultPolicy() *Policy {
+	policy, err := ParsePolicy(defaultPolicy)
+	if err != nil {
+		panic(err)
+	}
+
+	return policy
+}
+
+// LoadPolicy
+//
+// Reads a YAML policy file. Returns the default policy if path is empty.
+func LoadPolicy(path string) (*Policy, error) {
+	if path == "" {
+		return DefaultPolicy(), nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read authorization policy")
+	}
+
+	return ParsePolicy(data)
+}
+
+// ParsePolicy
+//
+// Parses and validates a YAML policy.
+func ParsePolicy(data []byte) (*Policy, error) {
+	var policy Policy
+	if err := yaml.Unmarshal(data, &policy); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPolicy, err)
+	}
+
+	if err := policy.validate(); err != nil {
+		return nil, err
+	}
+
+	return &policy, nil
+}
+
+// Authorize implements Authorizer.
+func (p *Policy) Authorize(ctx context.Context, action Action, task func() (*entities.Task, error)) error {
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok {
+		if IsInternalCaller(ctx) {
+			return nil
+		}
+		return fmt.Errorf("%w: %s requires an authenticated caller", ErrForbidden, action)
+	}
+
+	owned := false
+	for _, role := range p.rolesOf(principal) {
+		grants := p.Roles[role]
+		if grants.allows(action) {
+			return nil
+		}
+		owned = owned || slices.Contains(grants.Own, action) || slices.Contains(grants.Own, ActionAll)
+	}
+
+	if owned && task != nil && principal.UserID != 0 {
+		current, err := task()
+		if err != nil {
+			return err
+		}
+		if isAssignee(current, principal.UserID) {
+			return nil
+		}
+		return fmt.Errorf("%w: %s is limited to the tasks you are assigned to", ErrForbidden, action)
+	}
+
+	return fmt.Errorf("%w: your roles do not allow %s", ErrForbidden, action)
+}
+
+// rolesOf
+//
+// Returns the roles of the policy held by principal.
+func (p *Policy) rolesOf(principal *auth.Principal) []string {
+	if principal.Method == auth.MethodAPIKey {
+		return p.APIKeyRoles
+	}
+
+	var roles []string
+	for _, role := range principal.Roles {
+		if _, ok := p.Roles[role]; ok {
+			roles = append(roles, role)
+		}
+	}
+	if len(roles) == 0 {
+		return p.DefaultRoles
+	}
+
+	return roles
+}
+
+// validate
+//
+// Ensures every action is known and every referenced role is defined.
+func (p *Policy) validate() error {
+	for role, grants := range p.Roles {
+		for _, action := range append(slices.Clone(grants.Allow), grants.Own...) {
+			if action != ActionAll && !slices.Contains(Actions, action) {
+				return fmt.Errorf("%w: unknown action %q of role %q", ErrInvalidPolicy, action, role)
+			}
+		}
+	}
+
+	for _, role := range append(slices.Clone(p.DefaultRoles), p.APIKeyRoles...) {
+		if _, ok := p.Roles[role]; !ok {
+			return fmt.Errorf("%w: undefined role %q", ErrInvalidPolicy, role)
+		}
+	}
+
+	return nil
+}
+
+// allows reports whether the grants include action on any task.
+func (g Grants) allows(action Action) bool {
+	return slices.Contains(g.Allow, action) || slices.Contains(g.Allow, ActionAll)
+}
+
+// isAssignee reports whether the user is the primary assignee or a co-assignee of task.
+func isAssignee(task *entities.Task, userID int64) bool {
+	return task.AssigneeID == userID || slices.Contains(task.AssigneeIDs, userID)
+}
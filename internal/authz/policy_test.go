@@ -0,0 +1,165 @@
+package authz_test
+
+import (
+	"context"
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"task-manager/internal/auth"
+	"task-manager/internal/authz"
+	"task-manager/internal/entities"
+	"testing"
+)
+
+// ownTask is assigned to user 7, as co-assignee.
+var ownTask = &entities.Task{ID: 1, AssigneeID: 3, AssigneeIDs: []int64{3, 7}}
+
+// otherTask is assigned to somebody else.
+var otherTask = &entities.Task{ID: 2, AssigneeID: 3, AssigneeIDs: []int64{3}}
+
+// TestDefaultPolicy_Matrix tests every action of the default policy for every role,
+// on a task of the user and on somebody else's.
+func TestDefaultPolicy_Matrix(t *testing.T) {
+	policy := authz.DefaultPolicy()
+
+	viewer := &auth.Principal{Subject: "v", UserID: 7, Roles: []string{authz.RoleViewer}, Method: auth.MethodJWT}
+	member := &auth.Principal{Subject: "m", UserID: 7, Roles: []string{authz.RoleMember}, Method: auth.MethodJWT}
+	admin := &auth.Principal{Subject: "a", UserID: 7, Roles: []string{authz.RoleAdmin}, Method: auth.MethodJWT}
+	noRole := &auth.Principal{Subject: "n", UserID: 7, Roles: []string{"unknown"}, Method: auth.MethodJWT}
+	apiKey := &auth.Principal{Subject: "api-key:1", Scopes: []string{auth.ScopeTasksWrite}, Method: auth.MethodAPIKey}
+
+	tests := []struct {
+		name      string
+		principal *auth.Principal
+		action    authz.Action
+		task      *entities.Task
+		allowed   bool
+	}{
+		{"viewer reads", viewer, authz.ActionTaskRead, otherTask, true},
+		{"viewer creates", viewer, authz.ActionTaskCreate, nil, false},
+		{"viewer updates own", viewer, authz.ActionTaskUpdate, ownTask, false},
+		{"viewer deletes own", viewer, authz.ActionTaskDelete, ownTask, false},
+		{"viewer watches", viewer, authz.ActionTaskWatch, otherTask, false},
+
+		{"member reads", member, authz.ActionTaskRead, otherTask, true},
+		{"member creates", member, authz.ActionTaskCreate, nil, true},
+		{"member updates own", member, authz.ActionTaskUpdate, ownTask, true},
+		{"member updates other", member, authz.ActionTaskUpdate, otherTask, false},
+		{"member assigns own", member, authz.ActionTaskAssign, ownTask, true},
+		{"member assigns other", member, authz.ActionTaskAssign, otherTask, false},
+		{"member watches other", member, authz.ActionTaskWatch, otherTask, true},
+		{"member deletes own", member, authz.ActionTaskDelete, ownTask, false},
+
+		{"admin updates other", admin, authz.ActionTaskUpdate, otherTask, true},
+		{"admin deletes other", admin, authz.ActionTaskDelete, otherTask, true},
+		{"admin assigns other", admin, authz.ActionTaskAssign, otherTask, true},
+
+		{"unknown role reads as viewer", noRole, authz.ActionTaskRead, otherTask, true},
+		{"unknown role creates as viewer", noRole, authz.ActionTaskCreate, nil, false},
+
+		{"api key creates as member", apiKey, authz.ActionTaskCreate, nil, true},
+		{"api key updates without user", apiKey, authz.ActionTaskUpdate, ownTask, false},
+		{"api key deletes", apiKey, authz.ActionTaskDelete, otherTask, false},
+
+		{"viewer writes users", viewer, authz.ActionUserWrite, nil, false},
+		{"member writes users", member, authz.ActionUserWrite, nil, false},
+		{"admin writes users", admin, authz.ActionUserWrite, nil, true},
+		{"api key writes users", apiKey, authz.ActionUserWrite, nil, false},
+		{"viewer writes webhooks", viewer, authz.ActionWebhookWrite, nil, false},
+		{"member writes webhooks", member, authz.ActionWebhookWrite, nil, false},
+		{"admin writes webhooks", admin, authz.ActionWebhookWrite, nil, true},
+		{"api key writes webhooks", apiKey, authz.ActionWebhookWrite, nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := auth.WithPrincipal(context.Background(), tt.principal)
+
+			var load func() (*entities.Task, error)
+			if tt.task != nil {
+				load = func() (*entities.Task, error) { return tt.task, nil }
+			}
+
+			err := policy.Authorize(ctx, tt.action, load)
+			if tt.allowed {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, authz.ErrForbidden)
+			}
+		})
+	}
+}
+
+// TestPolicyAuthorize_WithoutPrincipal_ShouldDeny tests that contexts without principal
+// are denied, unless they are marked as internal callers.
+func TestPolicyAuthorize_WithoutPrincipal_ShouldDeny(t *testing.T) {
+	policy := authz.DefaultPolicy()
+
+	err := policy.Authorize(context.Background(), authz.ActionTaskDelete, nil)
+	assert.ErrorIs(t, err, authz.ErrForbidden)
+
+	err = policy.Authorize(authz.WithInternalCaller(context.Background()), authz.ActionTaskDelete, nil)
+	assert.NoError(t, err)
+}
+
+// TestPolicyAuthorize_OwnershipLoadFails_ShouldReturnError tests that the error of the
+// task lookup, e.g. a task that does not exist, is returned as is.
+func TestPolicyAuthorize_OwnershipLoadFails_ShouldReturnError(t *testing.T) {
+	notFound := errors.New("task not found")
+	ctx := auth.WithPrincipal(context.Background(), &auth.Principal{UserID: 7, Roles: []string{authz.RoleMember}})
+
+	err := authz.DefaultPolicy().Authorize(ctx, authz.ActionTaskUpdate, func() (*entities.Task, error) {
+		return nil, notFound
+	})
+
+	assert.ErrorIs(t, err, notFound)
+}
+
+// TestPolicyAuthorize_AllowedAnyTask_ShouldNotLoadTask tests that the task is only
+// loaded when ownership decides.
+func TestPolicyAuthorize_AllowedAnyTask_ShouldNotLoadTask(t *testing.T) {
+	ctx := auth.WithPrincipal(context.Background(), &auth.Principal{UserID: 7, Roles: []string{authz.RoleAdmin}})
+
+	err := authz.DefaultPolicy().Authorize(ctx, authz.ActionTaskUpdate, func() (*entities.Task, error) {
+		t.Fatal("task loaded")
+		return nil, nil
+	})
+
+	assert.NoError(t, err)
+}
+
+// TestParsePolicy_Custom_ShouldApply tests a policy configured in YAML.
+func TestParsePolicy_Custom_ShouldApply(t *testing.T) {
+	policy, err := authz.ParsePolicy([]byte(`
+roles:
+  triager:
+    allow: [task:read, task:assign]
+default_roles: [triager]
+api_key_roles: [triager]
+`))
+	require.NoError(t, err)
+
+	ctx := auth.WithPrincipal(context.Background(), &auth.Principal{UserID: 7})
+
+	assert.NoError(t, policy.Authorize(ctx, authz.ActionTaskAssign, nil))
+	assert.ErrorIs(t, policy.Authorize(ctx, authz.ActionTaskCreate, nil), authz.ErrForbidden)
+}
+
+// TestParsePolicy_Invalid_ShouldFail tests malformed policies, unknown actions and
+// undefined roles.
+func TestParsePolicy_Invalid_ShouldFail(t *testing.T) {
+	tests := map[string]string{
+		"malformed":         "roles: [",
+		"unknown action":    "roles: {viewer: {allow: [task:archive]}}",
+		"undefined default": "roles: {viewer: {allow: [task:read]}}\ndefault_roles: [member]",
+		"undefined api key": "roles: {viewer: {allow: [task:read]}}\napi_key_roles: [member]",
+	}
+
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := authz.ParsePolicy([]byte(data))
+
+			assert.ErrorIs(t, err, authz.ErrInvalidPolicy)
+		})
+	}
+}
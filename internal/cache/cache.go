@@ -4,6 +4,7 @@ import (
 	"time"
 )
 
+// Cache stores string values for a limited time, see Memory and Redis.
 type Cache interface {
 	Get(key string) (string, bool)
 	Set(key string, value string, ttl time.Duration)
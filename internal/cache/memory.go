@@ -0,0 +1,62 @@
+package cache
+
+import (
+	"sync"
+	"time"
+)
+
+// Memory is a Cache keeping the values in the memory of the instance.
+// Expired values are dropped when they are read, or overwritten.
+type Memory struct {
+	mu      sync.Mutex
+	entries map[string]memoryEntry
+	now     func() time.Time
+}
+
+// memoryEntry is a cached value and its expiry, zero if it never expires.
+type memoryEntry struct {
+	value     string
+	expiresAt time.Time
+}
+
+// NewMemory returns an empty in-memory Cache.
+func NewMemory() *Memory {
+	return &Memory{entries: make(map[string]memoryEntry), now: time.Now}
+}
+
+// Get implements Cache.
+func (m *Memory) Get(key string) (string, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, ok := m.entries[key]
+	if !ok {
+		return "", false
+	}
+	if !entry.expiresAt.IsZero() && !m.now().Before(entry.expiresAt) {
+		delete(m.entries, key)
+		return "", false
+	}
+
+	return entry.value, true
+}
+
+// Set implements Cache. Values with a ttl <= 0 never expire.
+func (m *Memory) Set(key string, value string, ttl time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry := memoryEntry{value: value}
+	if ttl > 0 {
+		entry.expiresAt = m.now().Add(ttl)
+	}
+	m.entries[key] = entry
+}
+
+// Delete implements Cache.
+func (m *Memory) Delete(key string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.entries, key)
+}
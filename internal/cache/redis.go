@@ -0,0 +1,55 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// redisTimeout bounds every Redis command, Cache calls carry no context.
+const redisTimeout = 2 * time.Second
+
+// Redis is a Cache keeping the values in Redis, shared by the instances of the API.
+// Failing commands are ignored: Get reports a miss, Set and Delete do nothing.
+type Redis struct {
+	client redis.UniversalClient
+	prefix string
+}
+
+// NewRedis returns a Cache storing the values in Redis under keys starting with prefix.
+func NewRedis(client redis.UniversalClient, prefix string) *Redis {
+	return &Redis{client: client, prefix: prefix}
+}
+
+// Get implements Cache.
+func (r *Redis) Get(key string) (string, bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+
+	value, err := r.client.Get(ctx, r.prefix+key).Result()
+	if err != nil {
+		return "", false
+	}
+
+	return value, true
+}
+
+// Set implements Cache. Values with a ttl <= 0 never expire.
+func (r *Redis) Set(key string, value string, ttl time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+
+	if ttl < 0 {
+		ttl = 0
+	}
+	r.client.Set(ctx, r.prefix+key, value, ttl)
+}
+
+// Delete implements Cache.
+func (r *Redis) Delete(key string) {
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+
+	r.client.Del(ctx, r.prefix+key)
+}
@@ -1,23 +1,40 @@
 package config
 
 import (
+	"fmt"
 	"github.com/kelseyhightower/envconfig"
 	"gopkg.in/yaml.v3"
 	"os"
 	"task-manager/pkg/db"
 	"task-manager/pkg/logger"
+	"task-manager/pkg/tracing"
+	"time"
 )
 
 // Config represents the main application configuration.
 // It can be loaded from a YAML file and/or overridden by environment variables.
 type Config struct {
-	Logger       logger.Config   `json:"logger" yaml:"LOGGER"`                 // Logger configuration
-	DB           db.Configs      `json:"db" yaml:"DB"`                         // Database connection settings
-	Redis        RedisConfig     `json:"redis" yaml:"REDIS"`                   // Redis configuration
-	DBType       string          `json:"db_type" yaml:"DB_TYPE"`               // Database type (e.g., postgres)
-	HostBasePath string          `json:"host_base_path" yaml:"HOST_BASE_PATH"` // Base host URL for Swagger/docs
-	Metrics      MetricsSettings `json:"metrics" yaml:"METRICS"`               // Metrics server settings
-	Port         int             `json:"port" yaml:"PORT"`                     // Application listening port
+	Logger       logger.Config     `json:"logger" yaml:"LOGGER"`                 // Logger configuration
+	DB           db.Configs        `json:"db" yaml:"DB"`                         // Database connection settings
+	Redis        RedisConfig       `json:"redis" yaml:"REDIS"`                   // Redis configuration
+	DBType       string            `json:"db_type" yaml:"DB_TYPE"`               // Database type (e.g., postgres)
+	HostBasePath string            `json:"host_base_path" yaml:"HOST_BASE_PATH"` // Base host URL for Swagger/docs
+	Metrics      MetricsSettings   `json:"metrics" yaml:"METRICS"`               // Metrics server settings
+	Port         int               `json:"port" yaml:"PORT"`                     // Application listening port
+	HTTP         HTTPConfig        `json:"http" yaml:"HTTP"`                     // HTTP server settings
+	Jobs         JobsConfig        `json:"jobs" yaml:"JOBS"`                     // Background jobs settings
+	Webhooks     WebhooksConfig    `json:"webhooks" yaml:"WEBHOOKS"`             // Outgoing webhooks settings
+	Outbox       OutboxConfig      `json:"outbox" yaml:"OUTBOX"`                 // Task event outbox relay settings
+	Stream       StreamConfig      `json:"stream" yaml:"STREAM"`                 // Live task event stream settings
+	GRPC         GRPCConfig        `json:"grpc" yaml:"GRPC"`                     // gRPC server settings
+	GraphQL      GraphQLConfig     `json:"graphql" yaml:"GRAPHQL"`               // GraphQL endpoint settings
+	Auth         AuthConfig        `json:"auth" yaml:"AUTH"`                     // API authentication settings
+	Tenant       TenantConfig      `json:"tenant" yaml:"TENANT"`                 // Multi-tenancy settings
+	RateLimit    RateLimitConfig   `json:"rate_limit" yaml:"RATE_LIMIT"`         // Per-client rate limiting settings
+	Idempotency  IdempotencyConfig `json:"idempotency" yaml:"IDEMPOTENCY"`       // Idempotency-Key settings
+	API          APIConfig         `json:"api" yaml:"API"`                       // REST API versioning settings
+	Health       HealthConfig      `json:"health" yaml:"HEALTH"`                 // Health probes settings
+	Tracing      tracing.Config    `json:"tracing" yaml:"TRACING"`               // OpenTelemetry tracing settings
 }
 
 // MetricsSettings holds Prometheus metrics configuration.
@@ -36,6 +53,152 @@ type RedisConfig struct {
 	TTL      string `json:"ttl" yaml:"TTL"`           // Default TTL for cached items
 }
 
+// JobsConfig holds background job settings. Zero values fall back to the defaults
+// of the respective job.
+type JobsConfig struct {
+	RecurrenceInterval  time.Duration `json:"recurrence_interval" yaml:"RECURRENCE_INTERVAL" envconfig:"JOBS_RECURRENCE_INTERVAL"`       // How often recurring tasks are materialized (e.g. 1m)
+	RecurrenceBatchSize int           `json:"recurrence_batch_size" yaml:"RECURRENCE_BATCH_SIZE" envconfig:"JOBS_RECURRENCE_BATCH_SIZE"` // Max series advanced per run
+	ReminderInterval    time.Duration `json:"reminder_interval" yaml:"REMINDER_INTERVAL" envconfig:"JOBS_REMINDER_INTERVAL"`             // How often deadlines are scanned (e.g. 1m)
+	ReminderLeadTime    time.Duration `json:"reminder_lead_time" yaml:"REMINDER_LEAD_TIME" envconfig:"JOBS_REMINDER_LEAD_TIME"`          // Reminder sent this long before the deadline (e.g. 24h)
+	EscalationAfter     time.Duration `json:"escalation_after" yaml:"ESCALATION_AFTER" envconfig:"JOBS_ESCALATION_AFTER"`                // Escalation sent this long after the deadline (e.g. 1h)
+	ReminderBatchSize   int           `json:"reminder_batch_size" yaml:"REMINDER_BATCH_SIZE" envconfig:"JOBS_REMINDER_BATCH_SIZE"`       // Max tasks notified per kind and run
+}
+
+// WebhooksConfig holds outgoing webhook delivery settings. Zero values fall back to
+// the service defaults.
+type WebhooksConfig struct {
+	DeliveryInterval      time.Duration `json:"delivery_interval" yaml:"DELIVERY_INTERVAL" envconfig:"WEBHOOKS_DELIVERY_INTERVAL"`                   // How often the retry queue is polled (e.g. 5s)
+	MaxAttempts           int           `json:"max_attempts" yaml:"MAX_ATTEMPTS" envconfig:"WEBHOOKS_MAX_ATTEMPTS"`                                  // Attempts before dead-lettering
+	BaseBackoff           time.Duration `json:"base_backoff" yaml:"BASE_BACKOFF" envconfig:"WEBHOOKS_BASE_BACKOFF"`                                  // First retry delay, doubled on every retry
+	MaxBackoff            time.Duration `json:"max_backoff" yaml:"MAX_BACKOFF" envconfig:"WEBHOOKS_MAX_BACKOFF"`                                     // Upper bound of the retry delay
+	Timeout               time.Duration `json:"timeout" yaml:"TIMEOUT" envconfig:"WEBHOOKS_TIMEOUT"`                                                 // Timeout of a single delivery attempt
+	BatchSize             int           `json:"batch_size" yaml:"BATCH_SIZE" envconfig:"WEBHOOKS_BATCH_SIZE"`                                        // Max deliveries attempted per run
+	AllowPrivateAddresses bool          `json:"allow_private_addresses" yaml:"ALLOW_PRIVATE_ADDRESSES" envconfig:"WEBHOOKS_ALLOW_PRIVATE_ADDRESSES"` // Let the subscriptions target loopback, link-local and private addresses (default false)
+}
+
+// OutboxConfig holds the task event outbox relay settings. Zero values fall back to
+// the service defaults.
+type OutboxConfig struct {
+	RelayInterval time.Duration `json:"relay_interval" yaml:"RELAY_INTERVAL" envconfig:"OUTBOX_RELAY_INTERVAL"` // How often the outbox is polled (e.g. 1s)
+	BatchSize     int           `json:"batch_size" yaml:"BATCH_SIZE" envconfig:"OUTBOX_BATCH_SIZE"`             // Max events relayed per run
+	Lease         time.Duration `json:"lease" yaml:"LEASE" envconfig:"OUTBOX_LEASE"`                            // Time a claimed batch is hidden from other relays
+	BaseBackoff   time.Duration `json:"base_backoff" yaml:"BASE_BACKOFF" envconfig:"OUTBOX_BASE_BACKOFF"`       // First retry delay, doubled on every retry
+	MaxBackoff    time.Duration `json:"max_backoff" yaml:"MAX_BACKOFF" envconfig:"OUTBOX_MAX_BACKOFF"`          // Upper bound of the retry delay
+	Retention     time.Duration `json:"retention" yaml:"RETENTION" envconfig:"OUTBOX_RETENTION"`                // Published events are purged after this long
+	LogEvents     bool          `json:"log_events" yaml:"LOG_EVENTS" envconfig:"OUTBOX_LOG_EVENTS"`             // Also write every relayed event to the log
+}
+
+// StreamConfig holds the live task event stream (SSE and WebSocket) settings. Zero values fall back
+// to the defaults.
+type StreamConfig struct {
+	ReplaySize    int           `json:"replay_size" yaml:"REPLAY_SIZE" envconfig:"STREAM_REPLAY_SIZE"`          // Recent events kept for Last-Event-ID resume
+	ClientBuffer  int           `json:"client_buffer" yaml:"CLIENT_BUFFER" envconfig:"STREAM_CLIENT_BUFFER"`    // Events queued per client before it is dropped as too slow
+	Heartbeat     time.Duration `json:"heartbeat" yaml:"HEARTBEAT" envconfig:"STREAM_HEARTBEAT"`                // Idle time after which a heartbeat is sent (e.g. 15s)
+	SocketOrigins []string      `json:"socket_origins" yaml:"SOCKET_ORIGINS" envconfig:"STREAM_SOCKET_ORIGINS"` // Origins allowed to open a task socket besides the API's own ("*" for any)
+	PollInterval  time.Duration `json:"poll_interval" yaml:"POLL_INTERVAL" envconfig:"STREAM_POLL_INTERVAL"`    // How often the outbox is read for new events (e.g. 500ms)
+	BatchSize     int           `json:"batch_size" yaml:"BATCH_SIZE" envconfig:"STREAM_BATCH_SIZE"`             // Max events read per poll
+	GapTimeout    time.Duration `json:"gap_timeout" yaml:"GAP_TIMEOUT" envconfig:"STREAM_GAP_TIMEOUT"`          // How long an uncommitted event is waited for before it is skipped
+}
+
+// GRPCConfig holds the gRPC server settings.
+type GRPCConfig struct {
+	Port int `json:"port" yaml:"PORT" envconfig:"GRPC_PORT"` // gRPC listening port (default 50051)
+}
+
+// GraphQLConfig holds the GraphQL endpoint limits. Zero values fall back to the defaults.
+type GraphQLConfig struct {
+	MaxDepth      int `json:"max_depth" yaml:"MAX_DEPTH" envconfig:"GRAPHQL_MAX_DEPTH"`                // Deepest field nesting allowed in an operation (default 8)
+	MaxComplexity int `json:"max_complexity" yaml:"MAX_COMPLEXITY" envconfig:"GRAPHQL_MAX_COMPLEXITY"` // Highest estimated field count allowed in an operation (default 1000)
+}
+
+// AuthConfig holds the API authentication settings. Requests are not authenticated
+// unless Enabled is set; then at least one JWT key source is required, and API keys
+// are accepted next to the tokens.
+type AuthConfig struct {
+	Enabled          bool          `json:"enabled" yaml:"ENABLED" envconfig:"AUTH_ENABLED"`                                     // Require authentication on the API routes
+	JWTSecret        string        `json:"-" yaml:"JWT_SECRET" envconfig:"AUTH_JWT_SECRET"`                                     // Shared secret of HS256/384/512 tokens, never printed
+	JWTPublicKeyFile string        `json:"jwt_public_key_file" yaml:"JWT_PUBLIC_KEY_FILE" envconfig:"AUTH_JWT_PUBLIC_KEY_FILE"` // PEM RSA or EC public key of RS*/PS*/ES* tokens
+	JWKSFile         string        `json:"jwks_file" yaml:"JWKS_FILE" envconfig:"AUTH_JWKS_FILE"`                               // Local JSON Web Key Set file
+	JWKSURL          string        `json:"jwks_url" yaml:"JWKS_URL" envconfig:"AUTH_JWKS_URL"`                                  // JSON Web Key Set URL of the identity provider
+	JWKSRefresh      time.Duration `json:"jwks_refresh" yaml:"JWKS_REFRESH" envconfig:"AUTH_JWKS_REFRESH"`                      // How often the JWKS URL is fetched again (default 1h)
+	JWTIssuer        string        `json:"jwt_issuer" yaml:"JWT_ISSUER" envconfig:"AUTH_JWT_ISSUER"`                            // Required iss claim, if set
+	JWTAudience      string        `json:"jwt_audience" yaml:"JWT_AUDIENCE" envconfig:"AUTH_JWT_AUDIENCE"`                      // Required aud claim, if set
+	JWTLeeway        time.Duration `json:"jwt_leeway" yaml:"JWT_LEEWAY" envconfig:"AUTH_JWT_LEEWAY"`                            // Clock skew tolerated on exp, nbf and iat
+	JWTAlgorithms    []string      `json:"jwt_algorithms" yaml:"JWT_ALGORITHMS" envconfig:"AUTH_JWT_ALGORITHMS"`                // Accepted signing algorithms (default all HS, RS, PS and ES)
+	APIKeyCacheTTL   time.Duration `json:"api_key_cache_ttl" yaml:"API_KEY_CACHE_TTL" envconfig:"AUTH_API_KEY_CACHE_TTL"`       // How long a verified API key is trusted before it is checked again (default 1m)
+	PolicyFile       string        `json:"policy_file" yaml:"POLICY_FILE" envconfig:"AUTH_POLICY_FILE"`                         // YAML authorization policy of the task, user and webhook operations (default built-in policy)
+}
+
+// String formats the settings with the JWT secret redacted, as the configuration is
+// printed on startup.
+func (c AuthConfig) String() string {
+	type settings AuthConfig
+	if c.JWTSecret != "" {
+		c.JWTSecret = "[REDACTED]"
+	}
+	return fmt.Sprintf("%v", settings(c))
+}
+
+// TenantConfig holds the multi-tenancy settings. Tasks are always scoped to the
+// tenant of the request; row-level security adds the database policy on top.
+type TenantConfig struct {
+	RowLevelSecurity bool `json:"row_level_security" yaml:"ROW_LEVEL_SECURITY" envconfig:"TENANT_ROW_LEVEL_SECURITY"` // Set app.tenant_id for the tasks row-level security policy
+}
+
+// HTTPConfig holds the settings of the HTTP server.
+type HTTPConfig struct {
+	TrustedProxies []string `json:"trusted_proxies" yaml:"TRUSTED_PROXIES" envconfig:"HTTP_TRUSTED_PROXIES"` // IPs or CIDRs of the proxies whose X-Forwarded-For is trusted (default none)
+}
+
+// RateLimitConfig holds the per-client rate limiting settings of the API routes. Zero
+// values fall back to the defaults.
+type RateLimitConfig struct {
+	Enabled    bool          `json:"enabled" yaml:"ENABLED" envconfig:"RATE_LIMIT_ENABLED"`             // Limit the requests of every client
+	Backend    string        `json:"backend" yaml:"BACKEND" envconfig:"RATE_LIMIT_BACKEND"`             // Buckets store: memory (per instance, default) or redis (shared)
+	Requests   int           `json:"requests" yaml:"REQUESTS" envconfig:"RATE_LIMIT_REQUESTS"`          // Requests per window of the routes without rule (default 100)
+	Window     time.Duration `json:"window" yaml:"WINDOW" envconfig:"RATE_LIMIT_WINDOW"`                // Window of the default limit (default 1m)
+	Routes     []string      `json:"routes" yaml:"ROUTES" envconfig:"RATE_LIMIT_ROUTES"`                // Route limits, e.g. "POST /api/tasks/=10/1m", 0 requests exempting the route
+	IPRequests int           `json:"ip_requests" yaml:"IP_REQUESTS" envconfig:"RATE_LIMIT_IP_REQUESTS"` // Requests per window of every IP address, before the authentication (default 300)
+	IPWindow   time.Duration `json:"ip_window" yaml:"IP_WINDOW" envconfig:"RATE_LIMIT_IP_WINDOW"`       // Window of the IP address limit (default 1m)
+}
+
+// IdempotencyConfig holds the settings of the requests sent with an Idempotency-Key
+// header. Zero values fall back to the defaults.
+type IdempotencyConfig struct {
+	Backend       string        `json:"backend" yaml:"BACKEND" envconfig:"IDEMPOTENCY_BACKEND"`                      // Keys store: db (default), redis or memory (per instance)
+	TTL           time.Duration `json:"ttl" yaml:"TTL" envconfig:"IDEMPOTENCY_TTL"`                                  // How long the responses are replayed (default 24h)
+	PurgeInterval time.Duration `json:"purge_interval" yaml:"PURGE_INTERVAL" envconfig:"IDEMPOTENCY_PURGE_INTERVAL"` // How often the expired keys are deleted from the db (default 1h)
+}
+
+// APIConfig holds the REST API versioning settings. Deprecated versions announce their
+// deprecation and sunset dates on every response.
+type APIConfig struct {
+	V1Deprecation Timestamp `json:"v1_deprecation" yaml:"V1_DEPRECATION" envconfig:"API_V1_DEPRECATION"` // When v1 is deprecated in favor of v2, RFC 3339 (default not deprecated)
+	V1Sunset      Timestamp `json:"v1_sunset" yaml:"V1_SUNSET" envconfig:"API_V1_SUNSET"`                // When v1 is removed, RFC 3339 (default not scheduled)
+}
+
+// HealthConfig holds the settings of the health probes. Zero values fall back to the
+// defaults.
+type HealthConfig struct {
+	CheckTimeout time.Duration `json:"check_timeout" yaml:"CHECK_TIMEOUT" envconfig:"HEALTH_CHECK_TIMEOUT"` // Timeout of every dependency check (default 2s)
+	DrainDelay   time.Duration `json:"drain_delay" yaml:"DRAIN_DELAY" envconfig:"HEALTH_DRAIN_DELAY"`       // How long the instance reports not ready before shutting down (default none)
+}
+
+// Timestamp is an optional RFC 3339 time of the configuration, so that an empty
+// variable leaves it unset.
+type Timestamp struct {
+	time.Time
+}
+
+// UnmarshalText parses an RFC 3339 time, the zero time if empty.
+func (t *Timestamp) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		t.Time = time.Time{}
+		return nil
+	}
+	return t.Time.UnmarshalText(text)
+}
+
 // LoadConfig loads application configuration from a YAML file and environment variables.
 // Env variables take precedence over YAML values if both are provided.
 func LoadConfig(filePath string) (*Config, error) {
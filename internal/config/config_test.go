@@ -0,0 +1,25 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestConfig_ShouldNotPrintTheJWTSecret tests that the configuration printed on startup
+// and its JSON form leave the JWT secret out.
+func TestConfig_ShouldNotPrintTheJWTSecret(t *testing.T) {
+	cfg := Config{Auth: AuthConfig{Enabled: true, JWTSecret: "s3cr3t", JWTIssuer: "issuer"}}
+
+	printed := fmt.Sprint(cfg)
+	assert.NotContains(t, printed, "s3cr3t")
+	assert.Contains(t, printed, "[REDACTED]")
+	assert.Contains(t, printed, "issuer")
+
+	data, err := json.Marshal(cfg)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "s3cr3t")
+}
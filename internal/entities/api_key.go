@@ -0,0 +1,26 @@
+package entities
+
+import (
+	"time"
+)
+
+// APIKey represents the credentials of a service-to-service caller, corresponding to
+// the `api_keys` table in the database. The secret of the key is only stored hashed.
+type APIKey struct {
+	ID         int64      `db:"id"`           // Primary key
+	TenantID   string     `db:"tenant_id"`    // Tenant the key acts in
+	Name       string     `db:"name"`         // Human readable label, e.g. the calling service
+	Prefix     string     `db:"prefix"`       // Public part of the key, used to look it up
+	SecretHash string     `db:"secret_hash"`  // bcrypt hash of the secret part of the key
+	Scopes     []string   `db:"-"`            // Scopes the key is limited to, e.g. tasks:read
+	ExpiresAt  *time.Time `db:"expires_at"`   // Expiry, the key never expires if nil
+	LastUsedAt *time.Time `db:"last_used_at"` // Last successful authentication, approximately
+	RevokedAt  *time.Time `db:"revoked_at"`   // Timestamp of the revocation, nil while usable
+	CreatedAt  time.Time  `db:"created_at"`   // Timestamp when the key was issued
+	UpdatedAt  time.Time  `db:"updated_at"`   // Timestamp when the key was last rotated or revoked
+}
+
+// Active reports whether the key may authenticate at now: it is neither revoked nor expired.
+func (k *APIKey) Active(now time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || now.Before(*k.ExpiresAt))
+}
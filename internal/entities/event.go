@@ -0,0 +1,52 @@
+package entities
+
+import (
+	"time"
+)
+
+// TaskEventType represents the kind of change a TaskEvent describes.
+type TaskEventType string
+
+// -------------------------------
+// Task Event Type Constants
+// -------------------------------
+const (
+	TaskEventCreated       TaskEventType = "task.created"
+	TaskEventUpdated       TaskEventType = "task.updated"
+	TaskEventDeleted       TaskEventType = "task.deleted"
+	TaskEventStatusChanged TaskEventType = "task.status_changed"
+)
+
+// TaskEvents lists every task event type, e.g. to validate subscription filters.
+var TaskEvents = []TaskEventType{TaskEventCreated, TaskEventUpdated, TaskEventDeleted, TaskEventStatusChanged}
+
+// TaskEvent is a domain event emitted when a task changes.
+type TaskEvent struct {
+	ID             string        // Unique event ID, receivers use it for deduplication
+	Type           TaskEventType // Kind of change
+	TaskID         int64         // Task the event belongs to
+	Task           *Task         // Task state after the change, or before it for task.deleted
+	PreviousStatus TaskStatus    // Status before the change, only set for task.status_changed
+	OccurredAt     time.Time     // Timestamp when the change happened
+}
+
+// IsValid checks if the TaskEventType value is one of the known event types.
+func (t TaskEventType) IsValid() bool {
+	for _, e := range TaskEvents {
+		if e == t {
+			return true
+		}
+	}
+	return false
+}
+
+// OutboxEvent is a TaskEvent stored in the transactional outbox until it is relayed
+// to the event sinks.
+type OutboxEvent struct {
+	TaskEvent
+	Sequence      int64      // Outbox position, events of a task are relayed in this order
+	Attempts      int        // Failed relay attempts so far
+	NextAttemptAt time.Time  // Earliest time of the next relay attempt
+	LastError     string     // Error of the last failed attempt
+	PublishedAt   *time.Time // Set once every sink accepted the event
+}
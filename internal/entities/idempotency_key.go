@@ -0,0 +1,28 @@
+package entities
+
+import (
+	"time"
+)
+
+// IdempotencyKey records a request made with an Idempotency-Key header, corresponding
+// to the `idempotency_keys` table in the database. Its response is stored once the
+// request completed, and replayed to the retries of the request until it expires.
+type IdempotencyKey struct {
+	Key         string          `db:"key"`         // Idempotency-Key of the request, scoped to its tenant and client
+	Fingerprint string          `db:"fingerprint"` // Hash of the method, path and body of the request
+	Response    *StoredResponse `db:"-"`           // Response of the request, nil while it is in flight
+	ExpiresAt   time.Time       `db:"expires_at"`  // Expiry, the key can be reused afterward
+	CreatedAt   time.Time       `db:"created_at"`  // Timestamp when the request was received
+}
+
+// StoredResponse is the response of a request replayed to its retries.
+type StoredResponse struct {
+	StatusCode int               `json:"status_code"` // HTTP status
+	Header     map[string]string `json:"header"`      // Headers describing the body, e.g. Content-Type
+	Body       []byte            `json:"body"`        // Response body
+}
+
+// Completed reports whether the request of the key completed.
+func (k *IdempotencyKey) Completed() bool {
+	return k.Response != nil
+}
@@ -0,0 +1,24 @@
+package entities
+
+import (
+	"time"
+)
+
+// ReminderKind represents the kind of deadline notification sent for a task.
+type ReminderKind string
+
+// -------------------------------
+// Reminder Kind Constants
+// -------------------------------
+const (
+	ReminderKindDueSoon ReminderKind = "due_soon" // The deadline is approaching
+	ReminderKindOverdue ReminderKind = "overdue"  // The deadline has passed, escalation
+)
+
+// Reminder records a deadline notification, corresponding to the `task_reminders` table in the database.
+type Reminder struct {
+	TaskID int64        `db:"task_id"` // Task the notification was sent for
+	Kind   ReminderKind `db:"kind"`    // Reminder or escalation
+	DueAt  time.Time    `db:"due_at"`  // Deadline the notification refers to
+	SentAt time.Time    `db:"sent_at"` // Timestamp when the notification was sent
+}
@@ -21,12 +21,25 @@ const (
 // Task represents the task entity, corresponding to the `tasks` table in the database.
 type Task struct {
 	ID          int64      `db:"id"`                    // Primary key
+	TenantID    string     `db:"tenant_id"`             // Tenant owning the task
 	Title       string     `db:"title"`                 // Task title
 	Description string     `db:"description,omitempty"` // Optional task description
 	Status      TaskStatus `db:"status"`                // Current task status
-	AssigneeID  int64      `db:"assignee_id"`           // User assigned to the task
+	AssigneeID  int64      `db:"assignee_id"`           // Primary user assigned to the task
+	AssigneeIDs []int64    `db:"-"`                     // All assignees, including the primary one
+	WatcherIDs  []int64    `db:"-"`                     // Users following the task
+	DueAt       *time.Time `db:"due_at"`                // Optional deadline, required for recurring tasks
 	CreatedAt   time.Time  `db:"created_at"`            // Timestamp when the task was created
 	UpdatedAt   time.Time  `db:"updated_at"`            // Timestamp when the task was last updated
+
+	RecurrenceRule     string     `db:"recurrence_rule"`      // Optional RFC 5545 RRULE, e.g. FREQ=WEEKLY;BYDAY=MO
+	RecurrenceStartAt  *time.Time `db:"recurrence_start_at"`  // Due date of the first occurrence of the series
+	RecurrenceParentID *int64     `db:"recurrence_parent_id"` // Occurrence this task was materialized from
+}
+
+// IsRecurring reports whether the task carries a recurrence rule.
+func (t *Task) IsRecurring() bool {
+	return t.RecurrenceRule != ""
 }
 
 // -------------------------------
@@ -0,0 +1,16 @@
+package entities
+
+import (
+	"time"
+)
+
+// User represents a person that tasks can be assigned to, corresponding to the `users` table in the database.
+type User struct {
+	ID        int64     `db:"id"`         // Primary key
+	TenantID  string    `db:"tenant_id"`  // Tenant the user belongs to, whose tasks they can be assigned to
+	Name      string    `db:"name"`       // Full name of the user
+	Email     string    `db:"email"`      // Email address, unique within the tenant
+	IsActive  bool      `db:"is_active"`  // Deactivated users can no longer receive tasks
+	CreatedAt time.Time `db:"created_at"` // Timestamp when the user was created
+	UpdatedAt time.Time `db:"updated_at"` // Timestamp when the user was last updated
+}
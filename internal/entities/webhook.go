@@ -0,0 +1,64 @@
+package entities
+
+import (
+	"time"
+)
+
+// WebhookDeliveryStatus represents the state of a webhook delivery in the retry queue.
+type WebhookDeliveryStatus string
+
+// -------------------------------
+// Webhook Delivery Status Constants
+// -------------------------------
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending"   // Waiting for its next attempt
+	WebhookDeliveryDelivered WebhookDeliveryStatus = "delivered" // Acknowledged with a 2xx response
+	WebhookDeliveryDead      WebhookDeliveryStatus = "dead"      // Gave up after the last attempt (dead letter)
+)
+
+// WebhookSubscription represents an endpoint receiving task events, corresponding to the
+// `webhook_subscriptions` table in the database.
+type WebhookSubscription struct {
+	ID        int64           `db:"id"`         // Primary key
+	TenantID  string          `db:"tenant_id"`  // Tenant owning the subscription, whose task events it receives
+	URL       string          `db:"url"`        // Endpoint receiving the events
+	Secret    string          `db:"secret"`     // HMAC-SHA256 signing secret
+	Events    []TaskEventType `db:"-"`          // Subscribed event types, empty means all
+	IsActive  bool            `db:"is_active"`  // Inactive subscriptions receive no new deliveries
+	CreatedAt time.Time       `db:"created_at"` // Timestamp when the subscription was created
+	UpdatedAt time.Time       `db:"updated_at"` // Timestamp when the subscription was last updated
+}
+
+// Accepts reports whether the subscription wants events of the given type.
+func (s *WebhookSubscription) Accepts(eventType TaskEventType) bool {
+	if len(s.Events) == 0 {
+		return true
+	}
+	for _, e := range s.Events {
+		if e == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDelivery represents one event sent to one subscription, corresponding to the
+// `webhook_deliveries` table in the database. It doubles as retry queue entry and delivery log.
+type WebhookDelivery struct {
+	ID             int64                 `db:"id"`              // Primary key
+	SubscriptionID int64                 `db:"subscription_id"` // Receiving subscription
+	EventID        string                `db:"event_id"`        // ID of the delivered TaskEvent
+	EventType      TaskEventType         `db:"event_type"`      // Type of the delivered TaskEvent
+	Payload        []byte                `db:"payload"`         // JSON body sent to the endpoint
+	Status         WebhookDeliveryStatus `db:"status"`          // Queue state
+	Attempts       int                   `db:"attempts"`        // Attempts made so far
+	NextAttemptAt  time.Time             `db:"next_attempt_at"` // When the next attempt is due
+	LastError      string                `db:"last_error"`      // Error of the last failed attempt
+	ResponseStatus int                   `db:"response_status"` // HTTP status of the last attempt, 0 if none
+	DeliveredAt    *time.Time            `db:"delivered_at"`    // Timestamp of the successful attempt
+	CreatedAt      time.Time             `db:"created_at"`      // Timestamp when the delivery was queued
+	UpdatedAt      time.Time             `db:"updated_at"`      // Timestamp of the last attempt
+
+	URL    string `db:"url"`    // Endpoint, joined from the subscription when claimed
+	Secret string `db:"secret"` // Signing secret, joined from the subscription when claimed
+}
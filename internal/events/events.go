@@ -0,0 +1,177 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"task-manager/internal/entities"
+	"task-manager/pkg/logger"
+)
+
+// Sink receives the task events relayed from the outbox. Implementations must
+// return an error if the event was not accepted, so it is relayed again.
+// Delivery is at-least-once: sinks may see an event more than once and should
+// deduplicate on its ID.
+type Sink interface {
+	Publish(ctx context.Context, event entities.TaskEvent) error
+}
+
+// -----------------------------------------------------------------------------
+// Multi sink
+// -----------------------------------------------------------------------------
+
+// Multi fans an event out to several sinks. Every sink is called even if a
+// previous one failed; the errors are joined and the whole event is relayed again.
+type Multi []Sink
+
+// Publish implements Sink.
+func (m Multi) Publish(ctx context.Context, event entities.TaskEvent) error {
+	var errs []error
+	for _, s := range m {
+		if err := s.Publish(ctx, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// -----------------------------------------------------------------------------
+// In-process bus
+// -----------------------------------------------------------------------------
+
+// Handler processes a task event delivered by the Bus.
+type Handler func(ctx context.Context, event entities.TaskEvent) error
+
+// Bus dispatches events to in-process subscribers, synchronously and in the
+// order they are relayed.
+type Bus struct {
+	mu       sync.RWMutex
+	handlers []subscription
+}
+
+// subscription is a handler together with the event types it accepts.
+type subscription struct {
+	types   []entities.TaskEventType
+	handler Handler
+}
+
+// accepts reports whether the subscription wants the given event type.
+// An empty filter accepts every event.
+func (s subscription) accepts(eventType entities.TaskEventType) bool {
+	if len(s.types) == 0 {
+		return true
+	}
+	for _, t := range s.types {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// NewBus returns an empty in-process Bus.
+func NewBus() *Bus {
+	return &Bus{}
+}
+
+// Subscribe registers a handler for the given event types, or for every event
+// if none are given.
+func (b *Bus) Subscribe(handler Handler, types ...entities.TaskEventType) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.handlers = append(b.handlers, subscription{types: types, handler: handler})
+}
+
+// Publish implements Sink. Every matching handler is called; their errors are joined.
+func (b *Bus) Publish(ctx context.Context, event entities.TaskEvent) error {
+	b.mu.RLock()
+	handlers := b.handlers
+	b.mu.RUnlock()
+
+	var errs []error
+	for _, s := range handlers {
+		if !s.accepts(event.Type) {
+			continue
+		}
+		if err := s.handler(ctx, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// -----------------------------------------------------------------------------
+// Log sink
+// -----------------------------------------------------------------------------
+
+// Log writes every event to the application logger.
+type Log struct {
+	logger logger.Logger
+}
+
+// NewLogSink returns a Sink writing events to the given logger.
+func NewLogSink(logger logger.Logger) *Log {
+	return &Log{logger: logger}
+}
+
+// Publish implements Sink.
+func (l *Log) Publish(_ context.Context, event entities.TaskEvent) error {
+	l.logger.InfoF("event %s %s for task %d (occurred at %s)",
+		event.ID, event.Type, event.TaskID, event.OccurredAt.Format(time.RFC3339))
+
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+// Broker sink
+// -----------------------------------------------------------------------------
+
+// Message is a record handed to a message broker.
+type Message struct {
+	Topic   string            // Destination topic, queue or subject
+	Key     string            // Partitioning key, the task ID keeps a task's events ordered
+	Value   []byte            // JSON encoded Payload
+	Headers map[string]string // Event metadata
+}
+
+// Broker abstracts a message broker client (Kafka, NATS, RabbitMQ...). Send must
+// only return once the broker acknowledged the message.
+type Broker interface {
+	Send(ctx context.Context, message Message) error
+}
+
+// BrokerSink publishes events to a message broker topic.
+type BrokerSink struct {
+	broker Broker
+	topic  string
+}
+
+// NewBrokerSink returns a Sink publishing events to the given broker topic.
+func NewBrokerSink(broker Broker, topic string) *BrokerSink {
+	return &BrokerSink{broker: broker, topic: topic}
+}
+
+// Publish implements Sink.
+func (s *BrokerSink) Publish(ctx context.Context, event entities.TaskEvent) error {
+	value, err := json.Marshal(NewPayload(event))
+	if err != nil {
+		return err
+	}
+
+	return s.broker.Send(ctx, Message{
+		Topic: s.topic,
+		Key:   strconv.FormatInt(event.TaskID, 10),
+		Value: value,
+		Headers: map[string]string{
+			"event_id":   event.ID,
+			"event_type": string(event.Type),
+		},
+	})
+}
@@ -0,0 +1,67 @@
+package events_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"task-manager/internal/entities"
+	"task-manager/internal/events"
+)
+
+// recordingBroker collects the messages sent to it.
+type recordingBroker struct {
+	messages []events.Message
+}
+
+func (b *recordingBroker) Send(_ context.Context, message events.Message) error {
+	b.messages = append(b.messages, message)
+	return nil
+}
+
+func TestBus_FiltersByType(t *testing.T) {
+	bus := events.NewBus()
+
+	var all, deleted []string
+	bus.Subscribe(func(_ context.Context, e entities.TaskEvent) error {
+		all = append(all, e.ID)
+		return nil
+	})
+	bus.Subscribe(func(_ context.Context, e entities.TaskEvent) error {
+		deleted = append(deleted, e.ID)
+		return errors.New("handler failed")
+	}, entities.TaskEventDeleted)
+
+	require.NoError(t, bus.Publish(context.Background(), entities.TaskEvent{ID: "1", Type: entities.TaskEventCreated}))
+	require.Error(t, bus.Publish(context.Background(), entities.TaskEvent{ID: "2", Type: entities.TaskEventDeleted}))
+
+	assert.Equal(t, []string{"1", "2"}, all)
+	assert.Equal(t, []string{"2"}, deleted)
+}
+
+func TestBrokerSink_KeysByTask(t *testing.T) {
+	broker := &recordingBroker{}
+	sink := events.NewBrokerSink(broker, "tasks")
+
+	err := sink.Publish(context.Background(), entities.TaskEvent{
+		ID:     "evt-1",
+		Type:   entities.TaskEventStatusChanged,
+		TaskID: 42,
+		Task:   &entities.Task{ID: 42, Status: entities.TaskStatusDone},
+	})
+	require.NoError(t, err)
+	require.Len(t, broker.messages, 1)
+
+	message := broker.messages[0]
+	assert.Equal(t, "tasks", message.Topic)
+	assert.Equal(t, "42", message.Key)
+	assert.Equal(t, "task.status_changed", message.Headers["event_type"])
+
+	var payload events.Payload
+	require.NoError(t, json.Unmarshal(message.Value, &payload))
+	assert.Equal(t, "evt-1", payload.ID)
+	assert.Equal(t, entities.TaskStatusDone, payload.Data.Task.Status)
+}
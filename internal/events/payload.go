@@ -0,0 +1,72 @@
+package events
+
+import (
+	"time"
+
+	"task-manager/internal/entities"
+)
+
+// Payload is the JSON representation of a task event, shared by webhooks and brokers.
+type Payload struct {
+	ID         string                 `json:"id"`
+	Type       entities.TaskEventType `json:"type"`
+	OccurredAt time.Time              `json:"occurred_at"`
+	Data       PayloadData            `json:"data"`
+}
+
+// PayloadData carries the task the event belongs to.
+type PayloadData struct {
+	TaskID         int64               `json:"task_id"`
+	PreviousStatus entities.TaskStatus `json:"previous_status,omitempty"` // only for task.status_changed
+	Task           *PayloadTask        `json:"task,omitempty"`            // state after the change, before it for task.deleted
+}
+
+// PayloadTask is the representation of a task in event payloads.
+type PayloadTask struct {
+	ID                 int64               `json:"id"`
+	TenantID           string              `json:"tenant_id"`
+	Title              string              `json:"title"`
+	Description        string              `json:"description,omitempty"`
+	Status             entities.TaskStatus `json:"status"`
+	AssigneeID         int64               `json:"assignee_id"`
+	AssigneeIDs        []int64             `json:"assignee_ids"`
+	WatcherIDs         []int64             `json:"watcher_ids"`
+	DueAt              *time.Time          `json:"due_at,omitempty"`
+	RecurrenceRule     string              `json:"recurrence_rule,omitempty"`
+	RecurrenceParentID *int64              `json:"recurrence_parent_id,omitempty"`
+	CreatedAt          time.Time           `json:"created_at"`
+	UpdatedAt          time.Time           `json:"updated_at"`
+}
+
+// NewPayload maps a task event to its JSON representation.
+func NewPayload(event entities.TaskEvent) Payload {
+	payload := Payload{
+		ID:         event.ID,
+		Type:       event.Type,
+		OccurredAt: event.OccurredAt.UTC(),
+		Data: PayloadData{
+			TaskID:         event.TaskID,
+			PreviousStatus: event.PreviousStatus,
+		},
+	}
+
+	if t := event.Task; t != nil {
+		payload.Data.Task = &PayloadTask{
+			ID:                 t.ID,
+			TenantID:           t.TenantID,
+			Title:              t.Title,
+			Description:        t.Description,
+			Status:             t.Status,
+			AssigneeID:         t.AssigneeID,
+			AssigneeIDs:        t.AssigneeIDs,
+			WatcherIDs:         t.WatcherIDs,
+			DueAt:              t.DueAt,
+			RecurrenceRule:     t.RecurrenceRule,
+			RecurrenceParentID: t.RecurrenceParentID,
+			CreatedAt:          t.CreatedAt,
+			UpdatedAt:          t.UpdatedAt,
+		}
+	}
+
+	return payload
+}
@@ -0,0 +1,92 @@
+package gql
+
+import (
+	"context"
+	"task-manager/internal/authz"
+	"task-manager/internal/repository/postgres"
+	"task-manager/internal/service"
+	"task-manager/internal/stream"
+
+	"github.com/cockroachdb/errors"
+)
+
+// Error codes returned in the "code" extension of the errors.
+const (
+	CodeBadUserInput       = "BAD_USER_INPUT"
+	CodeNotFound           = "NOT_FOUND"
+	CodeFailedPrecondition = "FAILED_PRECONDITION"
+	CodeForbidden          = "FORBIDDEN"
+	CodeQueryTooDeep       = "QUERY_TOO_DEEP"
+	CodeQueryTooComplex    = "QUERY_TOO_COMPLEX"
+	CodeStreamEnded        = "STREAM_ENDED"
+	CodeInternal           = "INTERNAL_SERVER_ERROR"
+)
+
+// Error is a GraphQL error carrying a machine readable code, so clients do not
+// depend on the messages.
+type Error struct {
+	Message string
+	Code    string
+}
+
+// newError returns an Error with the given code.
+func newError(code, message string) *Error {
+	return &Error{Message: message, Code: code}
+}
+
+// Error returns the message.
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Extensions returns the code, rendered in the "extensions" of the error.
+func (e *Error) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": e.Code}
+}
+
+// resolverError maps service errors to GraphQL errors, like the REST handlers map
+// them to HTTP status codes. Internal errors are logged and not exposed to the caller.
+func (s *Server) resolverError(ctx context.Context, err error) error {
+	switch {
+	case errors.Is(err, postgres.ErrTaskNotFound),
+		errors.Is(err, postgres.ErrTaskMemberNotFound),
+		errors.Is(err, postgres.ErrUserNotFound):
+		return newError(CodeNotFound, err.Error())
+	case errors.Is(err, service.ErrPrimaryAssignee):
+		return newError(CodeFailedPrecondition, err.Error())
+	case errors.Is(err, stream.ErrForbidden), errors.Is(err, authz.ErrForbidden):
+		return newError(CodeForbidden, err.Error())
+	case isValidationError(err):
+		return newError(CodeBadUserInput, err.Error())
+	default:
+		s.logger.ErrorWithContext(ctx, LogResolverFailed, LogKeyError, err)
+		return newError(CodeInternal, ErrInternal)
+	}
+}
+
+// isValidationError reports whether err was caused by invalid members, an invalid
+// recurrence or an invalid filter.
+func isValidationError(err error) bool {
+	return errors.Is(err, service.ErrAssigneeNotFound) ||
+		errors.Is(err, service.ErrAssigneeInactive) ||
+		errors.Is(err, service.ErrWatcherNotFound) ||
+		errors.Is(err, service.ErrInvalidRecurrenceRule) ||
+		errors.Is(err, service.ErrRecurrenceRequiresDueAt) ||
+		errors.Is(err, stream.ErrInvalidFilter)
+}
+
+const (
+	ErrTitleRequired     = "title is required"
+	ErrInvalidStatus     = "invalid task status"
+	ErrAssigneeRequired  = "assigneeId or assigneeIds is required"
+	ErrInvalidID         = "IDs must be positive integers"
+	ErrInvalidPage       = "page and perPage must be positive"
+	ErrQueryTooDeep      = "query depth %d exceeds the limit of %d"
+	ErrQueryTooComplex   = "query complexity %d exceeds the limit of %d"
+	ErrUseStream         = "subscriptions are served as Server-Sent Events by the stream endpoint"
+	ErrStreamTooSlow     = "subscription fell behind, resubscribe with lastEventId"
+	ErrStreamClosed      = "server shutting down, resubscribe with lastEventId"
+	ErrMissingScope      = "credentials lack the %s scope"
+	ErrMutationNotPosted = "mutations must be posted"
+	ErrInternal          = "Internal Server Error"
+)
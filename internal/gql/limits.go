@@ -0,0 +1,181 @@
+package gql
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// Defaults applied to zero limits.
+const (
+	DefaultMaxDepth      = 8
+	DefaultMaxComplexity = 1000
+)
+
+// DefaultListSize is the size assumed for lists without a perPage argument, such as
+// the assignees of a task, when computing the complexity.
+const DefaultListSize = 10
+
+// cost is the depth and complexity of a selection.
+type cost struct {
+	depth      int
+	complexity int
+}
+
+// analysis computes the cost of an operation before it is executed, so expensive
+// queries are rejected before they reach the database.
+//
+// The depth is the longest chain of nested fields. The complexity counts one per
+// resolved field, multiplying the fields below a list by its expected size: the
+// perPage argument of the field or of its parent (e.g. tasks(perPage: 50) { items }),
+// or DefaultListSize. Introspection fields are free.
+type analysis struct {
+	schema    graphql.Schema
+	fragments map[string]*ast.FragmentDefinition
+	variables map[string]interface{}
+}
+
+// analyze returns the cost of the operation. The document must be valid, which
+// rules out fragment cycles.
+func analyze(schema graphql.Schema, doc *ast.Document, op *ast.OperationDefinition, variables map[string]interface{}) cost {
+	a := &analysis{
+		schema:    schema,
+		fragments: make(map[string]*ast.FragmentDefinition),
+		variables: variables,
+	}
+	for _, def := range doc.Definitions {
+		if fragment, ok := def.(*ast.FragmentDefinition); ok {
+			a.fragments[fragment.Name.Value] = fragment
+		}
+	}
+
+	return a.selectionSet(op.SelectionSet, rootType(schema, op), 0)
+}
+
+// selectionSet returns the cost of the selections of a value of type parent.
+// pageSize is the perPage argument passed down to the next list, if any.
+func (a *analysis) selectionSet(set *ast.SelectionSet, parent graphql.Type, pageSize int) cost {
+	var total cost
+	if set == nil {
+		return total
+	}
+
+	for _, selection := range set.Selections {
+		var c cost
+		switch selection := selection.(type) {
+		case *ast.Field:
+			c = a.field(selection, parent, pageSize)
+		case *ast.InlineFragment:
+			c = a.selectionSet(selection.SelectionSet, a.typeCondition(selection.TypeCondition, parent), pageSize)
+		case *ast.FragmentSpread:
+			if fragment, ok := a.fragments[selection.Name.Value]; ok {
+				c = a.selectionSet(fragment.SelectionSet, a.typeCondition(fragment.TypeCondition, parent), pageSize)
+			}
+		}
+
+		total.depth = max(total.depth, c.depth)
+		total.complexity += c.complexity
+	}
+
+	return total
+}
+
+// field returns the cost of a field and its selections.
+func (a *analysis) field(field *ast.Field, parent graphql.Type, pageSize int) cost {
+	if strings.HasPrefix(field.Name.Value, "__") {
+		return cost{}
+	}
+
+	def := fieldDefinition(parent, field.Name.Value)
+	if def == nil {
+		return cost{depth: 1, complexity: 1}
+	}
+
+	if size, ok := a.intArgument(field, "perPage"); ok {
+		pageSize = size
+	}
+
+	multiplier := 1
+	if isList(def.Type) {
+		multiplier = DefaultListSize
+		if pageSize > 0 {
+			multiplier = pageSize
+		}
+		pageSize = 0
+	}
+
+	children := a.selectionSet(field.SelectionSet, graphql.GetNamed(def.Type).(graphql.Type), pageSize)
+
+	return cost{
+		depth:      1 + children.depth,
+		complexity: 1 + multiplier*children.complexity,
+	}
+}
+
+// intArgument returns the value of an integer argument, given inline or as a variable.
+func (a *analysis) intArgument(field *ast.Field, name string) (int, bool) {
+	for _, arg := range field.Arguments {
+		if arg.Name.Value != name {
+			continue
+		}
+
+		switch value := arg.Value.(type) {
+		case *ast.IntValue:
+			n, err := strconv.Atoi(value.Value)
+			return n, err == nil
+		case *ast.Variable:
+			switch n := a.variables[value.Name.Value].(type) {
+			case float64:
+				return int(n), true
+			case int:
+				return n, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// typeCondition returns the type of a fragment, or parent if it has no condition.
+func (a *analysis) typeCondition(condition *ast.Named, parent graphql.Type) graphql.Type {
+	if condition == nil {
+		return parent
+	}
+	if t := a.schema.Type(condition.Name.Value); t != nil {
+		return t
+	}
+	return parent
+}
+
+// rootType returns the root type of the operation.
+func rootType(schema graphql.Schema, op *ast.OperationDefinition) graphql.Type {
+	switch op.Operation {
+	case ast.OperationTypeMutation:
+		return schema.MutationType()
+	case ast.OperationTypeSubscription:
+		return schema.SubscriptionType()
+	default:
+		return schema.QueryType()
+	}
+}
+
+// fieldDefinition returns the definition of a field of an object or interface type.
+func fieldDefinition(parent graphql.Type, name string) *graphql.FieldDefinition {
+	switch parent := parent.(type) {
+	case *graphql.Object:
+		return parent.Fields()[name]
+	case *graphql.Interface:
+		return parent.Fields()[name]
+	}
+	return nil
+}
+
+// isList reports whether t is a list, nullable or not.
+func isList(t graphql.Type) bool {
+	if nonNull, ok := t.(*graphql.NonNull); ok {
+		t = nonNull.OfType
+	}
+	_, ok := t.(*graphql.List)
+	return ok
+}
@@ -0,0 +1,216 @@
+package gql
+
+import (
+	"context"
+	"sync"
+	"task-manager/internal/entities"
+	"task-manager/internal/service"
+	"task-manager/pkg/rest"
+)
+
+// batchFunc fetches the values of the given keys at once. Keys without a value are
+// left out of the result.
+type batchFunc[K comparable, V any] func(ctx context.Context, keys []K) (map[K]V, error)
+
+// loader batches and caches the loads of a request, so resolving a relation of
+// every task of a page costs one query instead of one per task.
+//
+// Load only queues the key and returns a thunk; the executor resolves the thunks
+// of a level after all its fields were visited, so the first thunk fetches the
+// keys of the whole level in a single batch.
+type loader[K comparable, V any] struct {
+	fetch batchFunc[K, V]
+
+	mu      sync.Mutex
+	pending []K
+	values  map[K]V
+	errs    map[K]error
+}
+
+// newLoader returns an empty loader fetching with fetch.
+func newLoader[K comparable, V any](fetch batchFunc[K, V]) *loader[K, V] {
+	return &loader[K, V]{
+		fetch:  fetch,
+		values: make(map[K]V),
+		errs:   make(map[K]error),
+	}
+}
+
+// Load queues the key and returns a thunk resolving to its value, the zero value if
+// it does not exist.
+func (l *loader[K, V]) Load(ctx context.Context, key K) func() (interface{}, error) {
+	l.queue(key)
+
+	return func() (interface{}, error) {
+		return l.get(ctx, key)
+	}
+}
+
+// LoadMany queues the keys and returns a thunk resolving to the values that exist,
+// in the order of keys.
+func (l *loader[K, V]) LoadMany(ctx context.Context, keys []K) func() (interface{}, error) {
+	for _, key := range keys {
+		l.queue(key)
+	}
+
+	return func() (interface{}, error) {
+		values := make([]V, 0, len(keys))
+		for _, key := range keys {
+			value, err := l.get(ctx, key)
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := l.lookup(key); ok {
+				values = append(values, value)
+			}
+		}
+		return values, nil
+	}
+}
+
+// Prime caches a value fetched by other means, e.g. the tasks of a list.
+func (l *loader[K, V]) Prime(key K, value V) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.values[key] = value
+}
+
+// Clear empties the cache, e.g. between the events of a subscription.
+func (l *loader[K, V]) Clear() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.pending = nil
+	l.values = make(map[K]V)
+	l.errs = make(map[K]error)
+}
+
+// queue adds the key to the next batch unless it is cached or already queued.
+func (l *loader[K, V]) queue(key K) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, ok := l.values[key]; ok {
+		return
+	}
+	if _, ok := l.errs[key]; ok {
+		return
+	}
+	for _, k := range l.pending {
+		if k == key {
+			return
+		}
+	}
+	l.pending = append(l.pending, key)
+}
+
+// get returns the value of the key, fetching the pending batch first if needed.
+func (l *loader[K, V]) get(ctx context.Context, key K) (V, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if len(l.pending) > 0 {
+		l.dispatch(ctx)
+	}
+
+	var zero V
+	if err, ok := l.errs[key]; ok {
+		return zero, err
+	}
+	return l.values[key], nil
+}
+
+// lookup returns the cached value of the key and whether it exists.
+func (l *loader[K, V]) lookup(key K) (V, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	value, ok := l.values[key]
+	return value, ok
+}
+
+// dispatch fetches the pending keys. Callers hold l.mu.
+func (l *loader[K, V]) dispatch(ctx context.Context) {
+	keys := l.pending
+	l.pending = nil
+
+	values, err := l.fetch(ctx, keys)
+	for _, key := range keys {
+		if err != nil {
+			l.errs[key] = err
+			continue
+		}
+		if value, ok := values[key]; ok {
+			l.values[key] = value
+		}
+	}
+}
+
+// -----------------------------------------------------------------------------
+// Request loaders
+// -----------------------------------------------------------------------------
+
+// loaders holds the loaders of a request.
+type loaders struct {
+	tasks *loader[int64, *entities.Task]
+	users *loader[int64, *entities.User]
+}
+
+// newLoaders returns the loaders of a request, batching through the task and user
+// lists filtered by ID.
+func newLoaders(taskService service.TaskService, userService service.UserService) *loaders {
+	return &loaders{
+		tasks: newLoader(func(ctx context.Context, ids []int64) (map[int64]*entities.Task, error) {
+			tasks, _, err := taskService.List(ctx, idQuery(ids))
+			if err != nil {
+				return nil, err
+			}
+
+			values := make(map[int64]*entities.Task, len(tasks))
+			for i := range tasks {
+				values[tasks[i].ID] = &tasks[i]
+			}
+			return values, nil
+		}),
+		users: newLoader(func(ctx context.Context, ids []int64) (map[int64]*entities.User, error) {
+			users, _, err := userService.List(ctx, idQuery(ids))
+			if err != nil {
+				return nil, err
+			}
+
+			values := make(map[int64]*entities.User, len(users))
+			for i := range users {
+				values[users[i].ID] = &users[i]
+			}
+			return values, nil
+		}),
+	}
+}
+
+// clear empties the caches of every loader.
+func (l *loaders) clear() {
+	l.tasks.Clear()
+	l.users.Clear()
+}
+
+// loadersKey is the context key of the request loaders.
+type loadersKey struct{}
+
+// withLoaders stores the request loaders in the context.
+func withLoaders(ctx context.Context, l *loaders) context.Context {
+	return context.WithValue(ctx, loadersKey{}, l)
+}
+
+// loadersFromContext returns the request loaders stored in the context.
+func loadersFromContext(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
+
+// idQuery returns the list query matching the given IDs, on a single page.
+func idQuery(ids []int64) rest.Query {
+	return rest.Query{
+		Filter:         rest.Filter{"id": joinIDs(ids)},
+		PaginationMeta: rest.PaginationMeta{Page: 1, PerPage: len(ids)},
+	}
+}
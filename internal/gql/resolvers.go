@@ -0,0 +1,471 @@
+package gql
+
+import (
+	"strconv"
+	"strings"
+	"task-manager/internal/entities"
+	"task-manager/internal/stream"
+	"task-manager/internal/tenant"
+	"task-manager/pkg/rest"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+)
+
+// DefaultPerPage is the page size of the tasks query when perPage is not given.
+const DefaultPerPage = 20
+
+// taskPage is a page of the tasks query.
+type taskPage struct {
+	Items   []*entities.Task
+	Total   int
+	Page    int
+	PerPage int
+}
+
+// -----------------------------------------------------------------------------
+// Queries
+// -----------------------------------------------------------------------------
+
+// resolveTask returns a task by ID.
+func (s *Server) resolveTask(p graphql.ResolveParams) (interface{}, error) {
+	id, err := parseID(p.Args["id"])
+	if err != nil {
+		return nil, err
+	}
+
+	task, err := s.TaskService.GetByID(p.Context, id)
+	if err != nil {
+		return nil, s.resolverError(p.Context, err)
+	}
+
+	return task, nil
+}
+
+// resolveTasks returns a page of the tasks matching the filter. The tasks are
+// cached, so relations pointing back to them are not fetched again.
+func (s *Server) resolveTasks(p graphql.ResolveParams) (interface{}, error) {
+	page, _ := p.Args["page"].(int)
+	perPage, _ := p.Args["perPage"].(int)
+	if page < 1 || perPage < 1 {
+		return nil, newError(CodeBadUserInput, ErrInvalidPage)
+	}
+
+	filter, err := restFilter(p.Args["filter"])
+	if err != nil {
+		return nil, err
+	}
+
+	query := rest.Query{
+		Filter:         filter,
+		PaginationMeta: rest.PaginationMeta{Page: page, PerPage: perPage},
+	}
+
+	tasks, total, err := s.TaskService.List(p.Context, query)
+	if err != nil {
+		return nil, s.resolverError(p.Context, err)
+	}
+
+	loaders := loadersFromContext(p.Context)
+	items := make([]*entities.Task, 0, len(tasks))
+	for i := range tasks {
+		loaders.tasks.Prime(tasks[i].ID, &tasks[i])
+		items = append(items, &tasks[i])
+	}
+
+	return taskPage{Items: items, Total: total, Page: page, PerPage: perPage}, nil
+}
+
+// resolveUser returns a user by ID.
+func (s *Server) resolveUser(p graphql.ResolveParams) (interface{}, error) {
+	id, err := parseID(p.Args["id"])
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.UserService.GetByID(p.Context, id)
+	if err != nil {
+		return nil, s.resolverError(p.Context, err)
+	}
+
+	return user, nil
+}
+
+// -----------------------------------------------------------------------------
+// Task relations, batched per request
+// -----------------------------------------------------------------------------
+
+// resolveTaskAssignee loads the primary assignee of a task.
+func (s *Server) resolveTaskAssignee(p graphql.ResolveParams) (interface{}, error) {
+	task := p.Source.(*entities.Task)
+	return s.thunk(p, loadersFromContext(p.Context).users.Load(p.Context, task.AssigneeID)), nil
+}
+
+// resolveTaskAssignees loads all assignees of a task.
+func (s *Server) resolveTaskAssignees(p graphql.ResolveParams) (interface{}, error) {
+	task := p.Source.(*entities.Task)
+	return s.thunk(p, loadersFromContext(p.Context).users.LoadMany(p.Context, task.AssigneeIDs)), nil
+}
+
+// resolveTaskWatchers loads the watchers of a task.
+func (s *Server) resolveTaskWatchers(p graphql.ResolveParams) (interface{}, error) {
+	task := p.Source.(*entities.Task)
+	return s.thunk(p, loadersFromContext(p.Context).users.LoadMany(p.Context, task.WatcherIDs)), nil
+}
+
+// resolveTaskRecurrenceParent loads the occurrence a task was materialized from.
+func (s *Server) resolveTaskRecurrenceParent(p graphql.ResolveParams) (interface{}, error) {
+	task := p.Source.(*entities.Task)
+	if task.RecurrenceParentID == nil {
+		return nil, nil
+	}
+	return s.thunk(p, loadersFromContext(p.Context).tasks.Load(p.Context, *task.RecurrenceParentID)), nil
+}
+
+// thunk maps the errors of a loader thunk like the errors of a resolver.
+func (s *Server) thunk(p graphql.ResolveParams, load func() (interface{}, error)) func() (interface{}, error) {
+	return func() (interface{}, error) {
+		value, err := load()
+		if err != nil {
+			return nil, s.resolverError(p.Context, err)
+		}
+		return value, nil
+	}
+}
+
+// -----------------------------------------------------------------------------
+// Mutations
+// -----------------------------------------------------------------------------
+
+// resolveCreateTask creates a task. The status defaults to pending.
+func (s *Server) resolveCreateTask(p graphql.ResolveParams) (interface{}, error) {
+	task, err := taskFromInput(p.Args["input"])
+	if err != nil {
+		return nil, err
+	}
+	if task.Status == "" {
+		task.Status = entities.TaskStatusPending
+	}
+	if err := validateTask(task); err != nil {
+		return nil, err
+	}
+
+	createdTask, err := s.TaskService.Create(p.Context, task)
+	if err != nil {
+		return nil, s.resolverError(p.Context, err)
+	}
+
+	return createdTask, nil
+}
+
+// resolveUpdateTask replaces the fields of a task, like the REST update.
+func (s *Server) resolveUpdateTask(p graphql.ResolveParams) (interface{}, error) {
+	id, err := parseID(p.Args["id"])
+	if err != nil {
+		return nil, err
+	}
+
+	task, err := taskFromInput(p.Args["input"])
+	if err != nil {
+		return nil, err
+	}
+	task.ID = id
+	if err := validateTask(task); err != nil {
+		return nil, err
+	}
+
+	updatedTask, err := s.TaskService.Update(p.Context, task)
+	if err != nil {
+		return nil, s.resolverError(p.Context, err)
+	}
+
+	return updatedTask, nil
+}
+
+// resolveDeleteTask deletes a task.
+func (s *Server) resolveDeleteTask(p graphql.ResolveParams) (interface{}, error) {
+	id, err := parseID(p.Args["id"])
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.TaskService.Delete(p.Context, id); err != nil {
+		return nil, s.resolverError(p.Context, err)
+	}
+
+	return true, nil
+}
+
+// resolveAddAssignee adds an active user as co-assignee.
+func (s *Server) resolveAddAssignee(p graphql.ResolveParams) (interface{}, error) {
+	taskID, userID, err := memberIDs(p.Args)
+	if err != nil {
+		return nil, err
+	}
+
+	task, err := s.TaskService.AddAssignee(p.Context, taskID, userID)
+	if err != nil {
+		return nil, s.resolverError(p.Context, err)
+	}
+
+	return task, nil
+}
+
+// resolveRemoveAssignee removes a co-assignee.
+func (s *Server) resolveRemoveAssignee(p graphql.ResolveParams) (interface{}, error) {
+	taskID, userID, err := memberIDs(p.Args)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.TaskService.RemoveAssignee(p.Context, taskID, userID); err != nil {
+		return nil, s.resolverError(p.Context, err)
+	}
+
+	return true, nil
+}
+
+// resolveAddWatcher subscribes a user to the task.
+func (s *Server) resolveAddWatcher(p graphql.ResolveParams) (interface{}, error) {
+	taskID, userID, err := memberIDs(p.Args)
+	if err != nil {
+		return nil, err
+	}
+
+	task, err := s.TaskService.AddWatcher(p.Context, taskID, userID)
+	if err != nil {
+		return nil, s.resolverError(p.Context, err)
+	}
+
+	return task, nil
+}
+
+// resolveRemoveWatcher unsubscribes a user from the task.
+func (s *Server) resolveRemoveWatcher(p graphql.ResolveParams) (interface{}, error) {
+	taskID, userID, err := memberIDs(p.Args)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.TaskService.RemoveWatcher(p.Context, taskID, userID); err != nil {
+		return nil, s.resolverError(p.Context, err)
+	}
+
+	return true, nil
+}
+
+// -----------------------------------------------------------------------------
+// Subscriptions
+// -----------------------------------------------------------------------------
+
+// subscribeTaskEvents subscribes to the task events matching the filter, if the
+// Authorizer allows it. The first message tells whether the events missed since
+// lastEventId could be replayed. The subscription ends with an error when the client
+// falls behind or the server shuts down.
+func (s *Server) subscribeTaskEvents(p graphql.ResolveParams) (interface{}, error) {
+	values, err := restFilter(p.Args["filter"])
+	if err != nil {
+		return nil, subscribeError(err)
+	}
+
+	filter, err := stream.NewFilter(values)
+	if err != nil {
+		return nil, subscribeError(s.resolverError(p.Context, err))
+	}
+	filter = filter.WithTenant(tenant.Of(p.Context))
+	if err := s.Authorizer.Authorize(p.Context, filter); err != nil {
+		return nil, subscribeError(s.resolverError(p.Context, err))
+	}
+
+	lastEventID, _ := p.Args["lastEventId"].(string)
+	sub, replay, resumed := s.TaskEvents.Subscribe(lastEventID)
+
+	messages := make(chan interface{})
+	go func() {
+		defer close(messages)
+		defer s.TaskEvents.Unsubscribe(sub)
+
+		send := func(message interface{}) bool {
+			select {
+			case messages <- message:
+				return true
+			case <-p.Context.Done():
+				return false
+			}
+		}
+
+		if !send(streamReady{ResetRequired: !resumed}) {
+			return
+		}
+		for _, e := range replay {
+			if filter.Match(e.Event) && !send(e) {
+				return
+			}
+		}
+
+		for {
+			select {
+			case <-p.Context.Done():
+				return
+			case e, ok := <-sub.Events():
+				if !ok {
+					if s.TaskEvents.Dropped(sub) {
+						send(newError(CodeStreamEnded, ErrStreamTooSlow))
+					} else {
+						send(newError(CodeStreamEnded, ErrStreamClosed))
+					}
+					return
+				}
+				if filter.Match(e.Event) && !send(e) {
+					return
+				}
+			}
+		}
+	}()
+
+	return messages, nil
+}
+
+// subscribeError wraps an error of a subscribe function, whose extensions are only
+// rendered when it comes located.
+func subscribeError(err error) error {
+	return gqlerrors.NewError(err.Error(), nil, "", nil, nil, err)
+}
+
+// resolveTaskEvent resolves a message of the subscription. The loaders are cleared,
+// so the relations of every event are loaded fresh.
+func (s *Server) resolveTaskEvent(p graphql.ResolveParams) (interface{}, error) {
+	loadersFromContext(p.Context).clear()
+
+	if err, ok := p.Source.(*Error); ok {
+		return nil, err
+	}
+	return p.Source, nil
+}
+
+// -----------------------------------------------------------------------------
+// Arguments
+// -----------------------------------------------------------------------------
+
+// taskFromInput maps a TaskInput to a task.
+func taskFromInput(value interface{}) (*entities.Task, error) {
+	input, _ := value.(map[string]interface{})
+
+	task := &entities.Task{}
+	task.Title, _ = input["title"].(string)
+	task.Description, _ = input["description"].(string)
+	task.RecurrenceRule, _ = input["recurrenceRule"].(string)
+	if status, ok := input["status"].(entities.TaskStatus); ok {
+		task.Status = status
+	}
+	if dueAt, ok := input["dueAt"].(time.Time); ok {
+		task.DueAt = &dueAt
+	}
+
+	var err error
+	if input["assigneeId"] != nil {
+		if task.AssigneeID, err = parseID(input["assigneeId"]); err != nil {
+			return nil, err
+		}
+	}
+	if task.AssigneeIDs, err = parseIDs(input["assigneeIds"]); err != nil {
+		return nil, err
+	}
+	if task.WatcherIDs, err = parseIDs(input["watcherIds"]); err != nil {
+		return nil, err
+	}
+
+	// Keep backward compatibility with a single primary assignee, like REST
+	if task.AssigneeID == 0 && len(task.AssigneeIDs) > 0 {
+		task.AssigneeID = task.AssigneeIDs[0]
+	}
+
+	return task, nil
+}
+
+// validateTask checks the fields the REST API validates when binding the request.
+func validateTask(task *entities.Task) error {
+	switch {
+	case task.Title == "":
+		return newError(CodeBadUserInput, ErrTitleRequired)
+	case !task.Status.IsValid():
+		return newError(CodeBadUserInput, ErrInvalidStatus)
+	case task.AssigneeID == 0:
+		return newError(CodeBadUserInput, ErrAssigneeRequired)
+	}
+	return nil
+}
+
+// restFilter maps a TaskFilter to the filters of the task list.
+func restFilter(value interface{}) (rest.Filter, error) {
+	input, _ := value.(map[string]interface{})
+	filter := make(rest.Filter)
+
+	if status, ok := input["status"].(entities.TaskStatus); ok {
+		filter["status"] = string(status)
+	}
+	if title, ok := input["title"].(string); ok {
+		filter["title"] = title
+	}
+
+	for field, key := range map[string]string{"assigneeIds": "assignee_id", "watcherIds": "watcher_id"} {
+		ids, err := parseIDs(input[field])
+		if err != nil {
+			return nil, err
+		}
+		if len(ids) > 0 {
+			filter[key] = joinIDs(ids)
+		}
+	}
+
+	return filter, nil
+}
+
+// memberIDs returns the taskId and userId arguments.
+func memberIDs(args map[string]interface{}) (taskID, userID int64, err error) {
+	if taskID, err = parseID(args["taskId"]); err != nil {
+		return 0, 0, err
+	}
+	if userID, err = parseID(args["userId"]); err != nil {
+		return 0, 0, err
+	}
+	return taskID, userID, nil
+}
+
+// parseID parses an ID argument.
+func parseID(value interface{}) (int64, error) {
+	s, _ := value.(string)
+	id, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || id <= 0 {
+		return 0, newError(CodeBadUserInput, ErrInvalidID)
+	}
+	return id, nil
+}
+
+// parseIDs parses a list of IDs, nil if absent.
+func parseIDs(value interface{}) ([]int64, error) {
+	values, _ := value.([]interface{})
+	if values == nil {
+		return nil, nil
+	}
+
+	ids := make([]int64, 0, len(values))
+	for _, v := range values {
+		id, err := parseID(v)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// joinIDs formats IDs as a comma separated list.
+func joinIDs(ids []int64) string {
+	values := make([]string, 0, len(ids))
+	for _, id := range ids {
+		values = append(values, strconv.FormatInt(id, 10))
+	}
+	return strings.Join(values, ",")
+}
@@ -0,0 +1,294 @@
+package gql
+
+import (
+	"task-manager/internal/entities"
+	"task-manager/internal/stream"
+
+	"github.com/graphql-go/graphql"
+)
+
+// taskStatusEnum maps the task statuses to GraphQL enum values.
+var taskStatusEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name: "TaskStatus",
+	Values: graphql.EnumValueConfigMap{
+		"PENDING":     {Value: entities.TaskStatusPending},
+		"IN_PROGRESS": {Value: entities.TaskStatusInProgress},
+		"DONE":        {Value: entities.TaskStatusDone},
+		"CANCELED":    {Value: entities.TaskStatusCanceled},
+	},
+})
+
+// taskEventTypeEnum maps the task event types to GraphQL enum values.
+var taskEventTypeEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name: "TaskEventType",
+	Values: graphql.EnumValueConfigMap{
+		"CREATED":        {Value: entities.TaskEventCreated},
+		"UPDATED":        {Value: entities.TaskEventUpdated},
+		"DELETED":        {Value: entities.TaskEventDeleted},
+		"STATUS_CHANGED": {Value: entities.TaskEventStatusChanged},
+	},
+})
+
+// taskFilterInput mirrors the filters of the task list and stream.
+var taskFilterInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name: "TaskFilter",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"status":      {Type: taskStatusEnum},
+		"title":       {Type: graphql.String},
+		"assigneeIds": {Type: graphql.NewList(graphql.NewNonNull(graphql.ID)), Description: "Tasks assigned to any of these users"},
+		"watcherIds":  {Type: graphql.NewList(graphql.NewNonNull(graphql.ID)), Description: "Tasks watched by any of these users"},
+	},
+})
+
+// taskInput holds the fields of a created or replaced task.
+var taskInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name: "TaskInput",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"title":          {Type: graphql.NewNonNull(graphql.String)},
+		"description":    {Type: graphql.String},
+		"status":         {Type: taskStatusEnum, Description: "Defaults to PENDING on creation"},
+		"assigneeId":     {Type: graphql.ID, Description: "Primary assignee, defaults to the first of assigneeIds"},
+		"assigneeIds":    {Type: graphql.NewList(graphql.NewNonNull(graphql.ID))},
+		"watcherIds":     {Type: graphql.NewList(graphql.NewNonNull(graphql.ID))},
+		"dueAt":          {Type: graphql.DateTime},
+		"recurrenceRule": {Type: graphql.String, Description: "RFC 5545 RRULE, requires dueAt"},
+	},
+})
+
+// newSchema builds the schema, resolving against the services of the server.
+func (s *Server) newSchema() (graphql.Schema, error) {
+	userType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "User",
+		Fields: graphql.Fields{
+			"id":        {Type: graphql.NewNonNull(graphql.ID)},
+			"name":      {Type: graphql.NewNonNull(graphql.String)},
+			"email":     {Type: graphql.NewNonNull(graphql.String)},
+			"isActive":  {Type: graphql.NewNonNull(graphql.Boolean)},
+			"createdAt": {Type: graphql.NewNonNull(graphql.DateTime)},
+			"updatedAt": {Type: graphql.NewNonNull(graphql.DateTime)},
+		},
+	})
+
+	var taskType *graphql.Object
+	taskType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Task",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id":             {Type: graphql.NewNonNull(graphql.ID)},
+				"title":          {Type: graphql.NewNonNull(graphql.String)},
+				"description":    {Type: graphql.NewNonNull(graphql.String)},
+				"status":         {Type: graphql.NewNonNull(taskStatusEnum)},
+				"assigneeId":     {Type: graphql.NewNonNull(graphql.ID)},
+				"dueAt":          {Type: graphql.DateTime},
+				"recurrenceRule": {Type: graphql.NewNonNull(graphql.String)},
+				"createdAt":      {Type: graphql.NewNonNull(graphql.DateTime)},
+				"updatedAt":      {Type: graphql.NewNonNull(graphql.DateTime)},
+
+				"assignee": {
+					Type:        userType,
+					Description: "Primary assignee, null if the user was deleted",
+					Resolve:     s.resolveTaskAssignee,
+				},
+				"assignees": {
+					Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(userType))),
+					Description: "All assignees, including the primary one",
+					Resolve:     s.resolveTaskAssignees,
+				},
+				"watchers": {
+					Type:    graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(userType))),
+					Resolve: s.resolveTaskWatchers,
+				},
+				"recurrenceParent": {
+					Type:        taskType,
+					Description: "Occurrence this task was materialized from",
+					Resolve:     s.resolveTaskRecurrenceParent,
+				},
+			}
+		}),
+	})
+
+	taskPageType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "TaskPage",
+		Fields: graphql.Fields{
+			"items":   {Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(taskType)))},
+			"total":   {Type: graphql.NewNonNull(graphql.Int)},
+			"page":    {Type: graphql.NewNonNull(graphql.Int)},
+			"perPage": {Type: graphql.NewNonNull(graphql.Int)},
+		},
+	})
+
+	taskEventType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "TaskEvent",
+		Fields: graphql.Fields{
+			"id": {
+				Type:        graphql.NewNonNull(graphql.String),
+				Description: "Position in the stream, to resubscribe with lastEventId",
+				Resolve:     eventField(func(e stream.Event) interface{} { return e.ID }),
+			},
+			"eventId": {
+				Type:    graphql.NewNonNull(graphql.ID),
+				Resolve: eventField(func(e stream.Event) interface{} { return e.Event.ID }),
+			},
+			"type": {
+				Type:    graphql.NewNonNull(taskEventTypeEnum),
+				Resolve: eventField(func(e stream.Event) interface{} { return e.Event.Type }),
+			},
+			"taskId": {
+				Type:    graphql.NewNonNull(graphql.ID),
+				Resolve: eventField(func(e stream.Event) interface{} { return e.Event.TaskID }),
+			},
+			"task": {
+				Type:        taskType,
+				Description: "Task after the change, or before it for DELETED",
+				Resolve:     eventField(func(e stream.Event) interface{} { return e.Event.Task }),
+			},
+			"previousStatus": {
+				Type:        taskStatusEnum,
+				Description: "Status before a STATUS_CHANGED event",
+				Resolve: eventField(func(e stream.Event) interface{} {
+					if e.Event.PreviousStatus == "" {
+						return nil
+					}
+					return e.Event.PreviousStatus
+				}),
+			},
+			"occurredAt": {
+				Type:    graphql.NewNonNull(graphql.DateTime),
+				Resolve: eventField(func(e stream.Event) interface{} { return e.Event.OccurredAt }),
+			},
+		},
+	})
+
+	streamReadyType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "TaskStreamReady",
+		Description: "First message of a subscription",
+		Fields: graphql.Fields{
+			"resetRequired": {
+				Type:        graphql.NewNonNull(graphql.Boolean),
+				Description: "The events since lastEventId are no longer available, reload the tasks",
+			},
+		},
+	})
+
+	streamMessageType := graphql.NewUnion(graphql.UnionConfig{
+		Name:  "TaskStreamMessage",
+		Types: []*graphql.Object{streamReadyType, taskEventType},
+		ResolveType: func(p graphql.ResolveTypeParams) *graphql.Object {
+			if _, ok := p.Value.(streamReady); ok {
+				return streamReadyType
+			}
+			return taskEventType
+		},
+	})
+
+	idArgs := graphql.FieldConfigArgument{
+		"id": {Type: graphql.NewNonNull(graphql.ID)},
+	}
+	memberArgs := graphql.FieldConfigArgument{
+		"taskId": {Type: graphql.NewNonNull(graphql.ID)},
+		"userId": {Type: graphql.NewNonNull(graphql.ID)},
+	}
+
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"task": {
+				Type:    taskType,
+				Args:    idArgs,
+				Resolve: s.resolveTask,
+			},
+			"tasks": {
+				Type: graphql.NewNonNull(taskPageType),
+				Args: graphql.FieldConfigArgument{
+					"filter":  {Type: taskFilterInput},
+					"page":    {Type: graphql.Int, DefaultValue: 1},
+					"perPage": {Type: graphql.Int, DefaultValue: DefaultPerPage},
+				},
+				Resolve: s.resolveTasks,
+			},
+			"user": {
+				Type:    userType,
+				Args:    idArgs,
+				Resolve: s.resolveUser,
+			},
+		},
+	})
+
+	mutation := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Mutation",
+		Fields: graphql.Fields{
+			"createTask": {
+				Type:    graphql.NewNonNull(taskType),
+				Args:    graphql.FieldConfigArgument{"input": {Type: graphql.NewNonNull(taskInput)}},
+				Resolve: s.resolveCreateTask,
+			},
+			"updateTask": {
+				Type: graphql.NewNonNull(taskType),
+				Args: graphql.FieldConfigArgument{
+					"id":    {Type: graphql.NewNonNull(graphql.ID)},
+					"input": {Type: graphql.NewNonNull(taskInput)},
+				},
+				Resolve: s.resolveUpdateTask,
+			},
+			"deleteTask": {
+				Type:    graphql.NewNonNull(graphql.Boolean),
+				Args:    idArgs,
+				Resolve: s.resolveDeleteTask,
+			},
+			"addAssignee": {
+				Type:    graphql.NewNonNull(taskType),
+				Args:    memberArgs,
+				Resolve: s.resolveAddAssignee,
+			},
+			"removeAssignee": {
+				Type:    graphql.NewNonNull(graphql.Boolean),
+				Args:    memberArgs,
+				Resolve: s.resolveRemoveAssignee,
+			},
+			"addWatcher": {
+				Type:    graphql.NewNonNull(taskType),
+				Args:    memberArgs,
+				Resolve: s.resolveAddWatcher,
+			},
+			"removeWatcher": {
+				Type:    graphql.NewNonNull(graphql.Boolean),
+				Args:    memberArgs,
+				Resolve: s.resolveRemoveWatcher,
+			},
+		},
+	})
+
+	subscription := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Subscription",
+		Fields: graphql.Fields{
+			"taskEvents": {
+				Type:        graphql.NewNonNull(streamMessageType),
+				Description: "Changes of the matching tasks, starting with a TaskStreamReady message",
+				Args: graphql.FieldConfigArgument{
+					"filter":      {Type: taskFilterInput},
+					"lastEventId": {Type: graphql.String, Description: "Resume after this event"},
+				},
+				Subscribe: s.subscribeTaskEvents,
+				Resolve:   s.resolveTaskEvent,
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{
+		Query:        query,
+		Mutation:     mutation,
+		Subscription: subscription,
+	})
+}
+
+// eventField returns a resolver reading a field of the stream event being resolved.
+func eventField(field func(e stream.Event) interface{}) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		return field(p.Source.(stream.Event)), nil
+	}
+}
+
+// streamReady is the first message of a subscription.
+type streamReady struct {
+	ResetRequired bool
+}
@@ -0,0 +1,232 @@
+package gql
+
+import (
+	"context"
+	"fmt"
+	"task-manager/internal/auth"
+	"task-manager/internal/config"
+	"task-manager/internal/service"
+	"task-manager/internal/stream"
+	"task-manager/pkg/logger"
+	"task-manager/pkg/tracing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/location"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+)
+
+// Server executes GraphQL operations on top of the task and user services. It
+// serves the queries and mutations of the GraphQL endpoint and the subscriptions
+// of its stream, next to the REST handlers.
+type Server struct {
+	TaskService service.TaskService
+	UserService service.UserService
+	TaskEvents  *stream.Hub
+	Authorizer  stream.Authorizer // Authorizes the taskEvents subscriptions
+	Schema      graphql.Schema
+	logger      logger.Logger
+
+	config config.GraphQLConfig
+}
+
+// Request is a GraphQL request, as posted to the endpoints or sent in the query string
+// of a GET request.
+type Request struct {
+	Query         string                 `json:"query" binding:"required"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+	ReadOnly      bool                   `json:"-"` // Rejects mutations, set for the requests sent with GET
+}
+
+// CreateServer initializes a new GraphQL server with all dependencies. It panics if
+// the schema is invalid, which is a programming error.
+func CreateServer(
+	logger logger.Logger,
+	config config.GraphQLConfig,
+	TaskService service.TaskService,
+	UserService service.UserService,
+	TaskEvents *stream.Hub,
+	Authorizer stream.Authorizer,
+) *Server {
+	s := &Server{
+		logger:      logger,
+		config:      config,
+		TaskService: TaskService,
+		UserService: UserService,
+		TaskEvents:  TaskEvents,
+		Authorizer:  Authorizer,
+	}
+
+	schema, err := s.newSchema()
+	if err != nil {
+		panic(fmt.Sprintf("invalid GraphQL schema: %v", err))
+	}
+	s.Schema = schema
+
+	return s
+}
+
+// Execute runs a query or mutation. Subscriptions are rejected, they are served by Subscribe.
+func (s *Server) Execute(ctx context.Context, req Request) *graphql.Result {
+	doc, op, result := s.prepare(req)
+	if result == nil {
+		result = authorize(ctx, op)
+	}
+	if result != nil {
+		return result
+	}
+	if op != nil && op.Operation == ast.OperationTypeSubscription {
+		return ErrorResult(newError(CodeBadUserInput, ErrUseStream))
+	}
+
+	return s.execute(ctx, doc, req)
+}
+
+// Subscribe runs a subscription and returns its results, until ctx is done or the
+// subscription ends. Queries and mutations yield a single result. The channel must
+// be drained until it is closed.
+func (s *Server) Subscribe(ctx context.Context, req Request) <-chan *graphql.Result {
+	doc, op, result := s.prepare(req)
+	if result == nil {
+		result = authorize(ctx, op)
+	}
+	if result == nil && (op == nil || op.Operation != ast.OperationTypeSubscription) {
+		result = s.execute(ctx, doc, req)
+	}
+	if result != nil {
+		results := make(chan *graphql.Result, 1)
+		results <- result
+		close(results)
+		return results
+	}
+
+	return graphql.ExecuteSubscription(graphql.ExecuteParams{
+		Schema:        s.Schema,
+		AST:           doc,
+		OperationName: req.OperationName,
+		Args:          req.Variables,
+		Context:       withLoaders(ctx, newLoaders(s.TaskService, s.UserService)),
+	})
+}
+
+// prepare parses and validates the request and checks the operation against the
+// depth and complexity limits. It returns the result to send instead of executing
+// the operation if any of them fails.
+func (s *Server) prepare(req Request) (*ast.Document, *ast.OperationDefinition, *graphql.Result) {
+	doc, err := parser.Parse(parser.ParseParams{
+		Source: source.NewSource(&source.Source{Body: []byte(req.Query), Name: "GraphQL request"}),
+	})
+	if err != nil {
+		return nil, nil, &graphql.Result{Errors: gqlerrors.FormatErrors(err)}
+	}
+
+	validation := graphql.ValidateDocument(&s.Schema, doc, nil)
+	if !validation.IsValid {
+		return nil, nil, &graphql.Result{Errors: validation.Errors}
+	}
+
+	op := operation(doc, req.OperationName)
+	if op == nil {
+		// Unknown or ambiguous operation, reported by the executor
+		return doc, nil, nil
+	}
+	if req.ReadOnly && op.Operation == ast.OperationTypeMutation {
+		return nil, nil, ErrorResult(newError(CodeBadUserInput, ErrMutationNotPosted))
+	}
+
+	c := analyze(s.Schema, doc, op, req.Variables)
+	if c.depth > s.maxDepth() {
+		return nil, nil, ErrorResult(newError(CodeQueryTooDeep, fmt.Sprintf(ErrQueryTooDeep, c.depth, s.maxDepth())))
+	}
+	if c.complexity > s.maxComplexity() {
+		return nil, nil, ErrorResult(newError(CodeQueryTooComplex, fmt.Sprintf(ErrQueryTooComplex, c.complexity, s.maxComplexity())))
+	}
+
+	return doc, op, nil
+}
+
+// authorize returns the result to send instead of running a mutation with credentials
+// lacking the tasks:write scope, nil if the operation may run. Queries and
+// subscriptions only need tasks:read. The endpoints already require tasks:write to post
+// operations, this keeps the server safe on its own.
+func authorize(ctx context.Context, op *ast.OperationDefinition) *graphql.Result {
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if ok && op != nil && op.Operation == ast.OperationTypeMutation && !principal.Permits(auth.ScopeTasksWrite) {
+		return ErrorResult(newError(CodeForbidden, fmt.Sprintf(ErrMissingScope, auth.ScopeTasksWrite)))
+	}
+	return nil
+}
+
+// execute runs a prepared query or mutation with fresh loaders.
+func (s *Server) execute(ctx context.Context, doc *ast.Document, req Request) *graphql.Result {
+	return graphql.Execute(graphql.ExecuteParams{
+		Schema:        s.Schema,
+		AST:           doc,
+		OperationName: req.OperationName,
+		Args:          req.Variables,
+		Context:       withLoaders(ctx, newLoaders(s.TaskService, s.UserService)),
+	})
+}
+
+// maxDepth returns the configured depth limit, or DefaultMaxDepth.
+func (s *Server) maxDepth() int {
+	if s.config.MaxDepth > 0 {
+		return s.config.MaxDepth
+	}
+	return DefaultMaxDepth
+}
+
+// maxComplexity returns the configured complexity limit, or DefaultMaxComplexity.
+func (s *Server) maxComplexity() int {
+	if s.config.MaxComplexity > 0 {
+		return s.config.MaxComplexity
+	}
+	return DefaultMaxComplexity
+}
+
+// operation returns the operation to run: the named one, or the only one of the
+// document. It returns nil if there is no such operation.
+func operation(doc *ast.Document, name string) *ast.OperationDefinition {
+	var found *ast.OperationDefinition
+	for _, def := range doc.Definitions {
+		op, ok := def.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+		if name == "" {
+			if found != nil {
+				return nil
+			}
+			found = op
+			continue
+		}
+		if op.Name != nil && op.Name.Value == name {
+			return op
+		}
+	}
+	return found
+}
+
+// ErrorResult returns a result holding a single error, e.g. for a request that could
+// not be decoded.
+func ErrorResult(err *Error) *graphql.Result {
+	return &graphql.Result{Errors: []gqlerrors.FormattedError{{
+		Message:    err.Message,
+		Locations:  []location.SourceLocation{},
+		Extensions: err.Extensions(),
+	}}}
+}
+
+// TraceIDFromContext retrieves the trace ID from the context, if available.
+// Returns empty string if not found.
+func TraceIDFromContext(ctx context.Context) string {
+	return tracing.TraceID(ctx)
+}
+
+const (
+	LogResolverFailed = "GraphQL resolver failed"
+	LogKeyError       = "error"
+)
@@ -0,0 +1,211 @@
+package gql_test
+
+import (
+	"context"
+	"encoding/json"
+	"task-manager/internal/entities"
+	"task-manager/internal/gql"
+	"task-manager/internal/repository/postgres"
+	"task-manager/internal/service"
+	"task-manager/internal/stream"
+	"task-manager/pkg/rest"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// errorCode returns the code extension of the first error of the result.
+func errorCode(t *testing.T, result *graphql.Result) string {
+	t.Helper()
+	require.NotEmpty(t, result.Errors)
+	code, _ := result.Errors[0].Extensions["code"].(string)
+	return code
+}
+
+// TestTasksQuery_ShouldBatchRelations tests that the relations of all tasks of a page
+// are loaded with one query per relation instead of one per task.
+func TestTasksQuery_ShouldBatchRelations(t *testing.T) {
+	taskService := &service.MockTaskService{}
+	userService := &service.MockUserService{}
+	server := gql.SetupServer(taskService, userService)
+
+	parentID := int64(10)
+	tasks := []entities.Task{
+		{ID: 1, Title: "Write docs", Status: entities.TaskStatusPending, AssigneeID: 1, AssigneeIDs: []int64{1, 2}, RecurrenceParentID: &parentID},
+		{ID: 2, Title: "Ship", Status: entities.TaskStatusDone, AssigneeID: 2, AssigneeIDs: []int64{2}, WatcherIDs: []int64{3}, RecurrenceParentID: &parentID},
+	}
+	taskService.On("List", mock.Anything, mock.MatchedBy(func(q rest.Query) bool { return q.Filter["id"] == "" })).
+		Return(tasks, 2, nil).Once()
+	taskService.On("List", mock.Anything, mock.MatchedBy(func(q rest.Query) bool { return q.Filter["id"] == "10" })).
+		Return([]entities.Task{{ID: 10, Title: "Weekly sync", Status: entities.TaskStatusDone, AssigneeID: 1}}, 1, nil).Once()
+	userService.On("List", mock.Anything, mock.MatchedBy(func(q rest.Query) bool { return q.Filter["id"] == "1,2,3" })).
+		Return([]entities.User{{ID: 1, Name: "Ada"}, {ID: 2, Name: "Grace"}, {ID: 3, Name: "Linus"}}, 3, nil).Once()
+
+	result := server.Execute(context.Background(), gql.Request{Query: `{
+		tasks(filter: {status: PENDING}) {
+			total
+			items {
+				id
+				assignee { name }
+				assignees { name }
+				watchers { name }
+				recurrenceParent { title }
+			}
+		}
+	}`})
+	require.Empty(t, result.Errors)
+
+	body, _ := json.Marshal(result.Data)
+	assert.JSONEq(t, `{"tasks": {"total": 2, "items": [
+		{"id": "1", "assignee": {"name": "Ada"}, "assignees": [{"name": "Ada"}, {"name": "Grace"}], "watchers": [], "recurrenceParent": {"title": "Weekly sync"}},
+		{"id": "2", "assignee": {"name": "Grace"}, "assignees": [{"name": "Grace"}], "watchers": [{"name": "Linus"}], "recurrenceParent": {"title": "Weekly sync"}}
+	]}}`, string(body))
+
+	taskService.AssertExpectations(t)
+	userService.AssertExpectations(t)
+	taskService.AssertCalled(t, "List", mock.Anything, mock.MatchedBy(func(q rest.Query) bool {
+		return q.Filter["status"] == string(entities.TaskStatusPending) && q.PerPage == gql.DefaultPerPage
+	}))
+}
+
+// TestQuery_TooDeep_ShouldBeRejected tests that deeply nested queries are rejected before execution.
+func TestQuery_TooDeep_ShouldBeRejected(t *testing.T) {
+	taskService := &service.MockTaskService{}
+	server := gql.SetupServer(taskService, &service.MockUserService{})
+
+	result := server.Execute(context.Background(), gql.Request{Query: `{
+		task(id: 1) { recurrenceParent { recurrenceParent { recurrenceParent { recurrenceParent {
+			recurrenceParent { recurrenceParent { recurrenceParent { recurrenceParent { id } } } }
+		} } } } }
+	}`})
+
+	assert.Equal(t, gql.CodeQueryTooDeep, errorCode(t, result))
+	taskService.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+}
+
+// TestQuery_TooComplex_ShouldBeRejected tests that the page size counts towards the complexity.
+func TestQuery_TooComplex_ShouldBeRejected(t *testing.T) {
+	taskService := &service.MockTaskService{}
+	server := gql.SetupServer(taskService, &service.MockUserService{})
+
+	query := `query($perPage: Int) { tasks(perPage: $perPage) { items { id assignees { id name } watchers { id name } } } }`
+
+	result := server.Execute(context.Background(), gql.Request{Query: query, Variables: map[string]interface{}{"perPage": 500.0}})
+	assert.Equal(t, gql.CodeQueryTooComplex, errorCode(t, result))
+	taskService.AssertNotCalled(t, "List", mock.Anything, mock.Anything)
+
+	taskService.On("List", mock.Anything, mock.Anything).Return([]entities.Task{}, 0, nil).Once()
+	result = server.Execute(context.Background(), gql.Request{Query: query, Variables: map[string]interface{}{"perPage": 10.0}})
+	assert.Empty(t, result.Errors)
+}
+
+// TestCreateTaskMutation tests task creation and the validation of its input.
+func TestCreateTaskMutation(t *testing.T) {
+	taskService := &service.MockTaskService{}
+	server := gql.SetupServer(taskService, &service.MockUserService{})
+
+	taskService.On("Create", mock.Anything, mock.MatchedBy(func(task *entities.Task) bool {
+		return task.Title == "Write docs" && task.Status == entities.TaskStatusPending &&
+			task.AssigneeID == 3 && len(task.WatcherIDs) == 1
+	})).Return(&entities.Task{ID: 7, Title: "Write docs", Status: entities.TaskStatusPending, AssigneeID: 3}, nil).Once()
+
+	result := server.Execute(context.Background(), gql.Request{Query: `mutation {
+		createTask(input: {title: "Write docs", assigneeIds: [3], watcherIds: [4]}) { id status }
+	}`})
+	require.Empty(t, result.Errors)
+	body, _ := json.Marshal(result.Data)
+	assert.JSONEq(t, `{"createTask": {"id": "7", "status": "PENDING"}}`, string(body))
+
+	result = server.Execute(context.Background(), gql.Request{Query: `mutation { createTask(input: {title: "Orphan"}) { id } }`})
+	assert.Equal(t, gql.CodeBadUserInput, errorCode(t, result))
+	assert.Equal(t, gql.ErrAssigneeRequired, result.Errors[0].Message)
+
+	taskService.AssertExpectations(t)
+}
+
+// TestMutation_ShouldMapServiceErrors tests that service errors carry their code and
+// internal errors are not exposed.
+func TestMutation_ShouldMapServiceErrors(t *testing.T) {
+	taskService := &service.MockTaskService{}
+	server := gql.SetupServer(taskService, &service.MockUserService{})
+
+	taskService.On("Delete", mock.Anything, int64(1)).Return(postgres.ErrTaskNotFound).Once()
+	taskService.On("Delete", mock.Anything, int64(2)).Return(errors.New("connection refused")).Once()
+
+	result := server.Execute(context.Background(), gql.Request{Query: `mutation { deleteTask(id: 1) }`})
+	assert.Equal(t, gql.CodeNotFound, errorCode(t, result))
+
+	result = server.Execute(context.Background(), gql.Request{Query: `mutation { deleteTask(id: 2) }`})
+	assert.Equal(t, gql.CodeInternal, errorCode(t, result))
+	assert.Equal(t, gql.ErrInternal, result.Errors[0].Message)
+
+	result = server.Execute(context.Background(), gql.Request{Query: `mutation { deleteTask(id: "abc") }`})
+	assert.Equal(t, gql.CodeBadUserInput, errorCode(t, result))
+}
+
+// TestExecute_Subscription_ShouldBeRejected tests that subscriptions are not executed as queries.
+func TestExecute_Subscription_ShouldBeRejected(t *testing.T) {
+	server := gql.SetupServer(&service.MockTaskService{}, &service.MockUserService{})
+
+	result := server.Execute(context.Background(), gql.Request{Query: `subscription { taskEvents { __typename } }`})
+	assert.Equal(t, gql.CodeBadUserInput, errorCode(t, result))
+}
+
+// TestTaskEventsSubscription tests that the subscription starts with a ready message
+// and pushes the matching events only.
+func TestTaskEventsSubscription(t *testing.T) {
+	server := gql.SetupServer(&service.MockTaskService{}, &service.MockUserService{})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	results := server.Subscribe(ctx, gql.Request{Query: `subscription {
+		taskEvents(filter: {status: DONE}) {
+			... on TaskStreamReady { resetRequired }
+			... on TaskEvent { eventId type task { title } }
+		}
+	}`})
+
+	result := <-results
+	require.Empty(t, result.Errors)
+	body, _ := json.Marshal(result.Data)
+	assert.JSONEq(t, `{"taskEvents": {"resetRequired": false}}`, string(body))
+
+	pending := &entities.Task{ID: 1, Title: "Write docs", Status: entities.TaskStatusPending}
+	done := &entities.Task{ID: 2, Title: "Ship", Status: entities.TaskStatusDone}
+	require.NoError(t, server.TaskEvents.Publish(ctx, entities.OutboxEvent{Sequence: 1, TaskEvent: entities.TaskEvent{ID: "e1", Type: entities.TaskEventUpdated, TaskID: 1, Task: pending}}))
+	require.NoError(t, server.TaskEvents.Publish(ctx, entities.OutboxEvent{Sequence: 2, TaskEvent: entities.TaskEvent{ID: "e2", Type: entities.TaskEventUpdated, TaskID: 2, Task: done}}))
+
+	result = <-results
+	require.Empty(t, result.Errors)
+	body, _ = json.Marshal(result.Data)
+	assert.JSONEq(t, `{"taskEvents": {"eventId": "e2", "type": "UPDATED", "task": {"title": "Ship"}}}`, string(body))
+
+	server.TaskEvents.Close()
+	result = <-results
+	assert.Equal(t, gql.CodeStreamEnded, errorCode(t, result))
+
+	for range results {
+	}
+}
+
+// TestTaskEventsSubscription_Forbidden tests that the Authorizer is asked for every subscription.
+func TestTaskEventsSubscription_Forbidden(t *testing.T) {
+	server := gql.SetupServer(&service.MockTaskService{}, &service.MockUserService{})
+	server.Authorizer = stream.AuthorizerFunc(func(context.Context, stream.Filter) error {
+		return stream.ErrForbidden
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	results := server.Subscribe(ctx, gql.Request{Query: `subscription { taskEvents { __typename } }`})
+
+	result := <-results
+	assert.Equal(t, gql.CodeForbidden, errorCode(t, result))
+}
@@ -0,0 +1,18 @@
+package gql
+
+import (
+	"log/slog"
+	"os"
+	"task-manager/internal/config"
+	"task-manager/internal/service"
+	"task-manager/internal/stream"
+	"task-manager/pkg/logger"
+)
+
+func SetupServer(taskService *service.MockTaskService, userService *service.MockUserService) *Server {
+	myLogger := &logger.StandardLogger{
+		Logger: slog.New(slog.NewTextHandler(os.Stdout, nil)),
+	}
+
+	return CreateServer(myLogger, config.GraphQLConfig{}, taskService, userService, stream.NewHub(stream.Settings{}), stream.AllowAll)
+}
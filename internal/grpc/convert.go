@@ -0,0 +1,92 @@
+package grpc
+
+import (
+	"task-manager/internal/entities"
+	"task-manager/internal/stream"
+	"task-manager/pkg/api/taskv1"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// statuses maps the task statuses to their protobuf values.
+var statuses = map[entities.TaskStatus]taskv1.TaskStatus{
+	entities.TaskStatusPending:    taskv1.TaskStatus_TASK_STATUS_PENDING,
+	entities.TaskStatusInProgress: taskv1.TaskStatus_TASK_STATUS_IN_PROGRESS,
+	entities.TaskStatusDone:       taskv1.TaskStatus_TASK_STATUS_DONE,
+	entities.TaskStatusCanceled:   taskv1.TaskStatus_TASK_STATUS_CANCELED,
+}
+
+// eventTypes maps the task event types to their protobuf values.
+var eventTypes = map[entities.TaskEventType]taskv1.TaskEventType{
+	entities.TaskEventCreated:       taskv1.TaskEventType_TASK_EVENT_TYPE_CREATED,
+	entities.TaskEventUpdated:       taskv1.TaskEventType_TASK_EVENT_TYPE_UPDATED,
+	entities.TaskEventDeleted:       taskv1.TaskEventType_TASK_EVENT_TYPE_DELETED,
+	entities.TaskEventStatusChanged: taskv1.TaskEventType_TASK_EVENT_TYPE_STATUS_CHANGED,
+}
+
+// toProtoTask maps a task entity to its protobuf representation.
+func toProtoTask(task *entities.Task) *taskv1.Task {
+	if task == nil {
+		return nil
+	}
+
+	pb := &taskv1.Task{
+		Id:             task.ID,
+		Title:          task.Title,
+		Description:    task.Description,
+		Status:         statuses[task.Status],
+		AssigneeId:     task.AssigneeID,
+		AssigneeIds:    task.AssigneeIDs,
+		WatcherIds:     task.WatcherIDs,
+		DueAt:          toProtoTime(task.DueAt),
+		RecurrenceRule: task.RecurrenceRule,
+		CreatedAt:      timestamppb.New(task.CreatedAt),
+		UpdatedAt:      timestamppb.New(task.UpdatedAt),
+	}
+	if task.RecurrenceParentID != nil {
+		pb.RecurrenceParentId = *task.RecurrenceParentID
+	}
+
+	return pb
+}
+
+// toProtoEvent maps a stream event to its protobuf representation.
+func toProtoEvent(e stream.Event) *taskv1.TaskEvent {
+	return &taskv1.TaskEvent{
+		StreamId:       e.ID,
+		Id:             e.Event.ID,
+		Type:           eventTypes[e.Event.Type],
+		TaskId:         e.Event.TaskID,
+		Task:           toProtoTask(e.Event.Task),
+		PreviousStatus: statuses[e.Event.PreviousStatus],
+		OccurredAt:     timestamppb.New(e.Event.OccurredAt),
+	}
+}
+
+// fromProtoStatus maps a protobuf status to the task status, empty if unknown.
+func fromProtoStatus(pb taskv1.TaskStatus) entities.TaskStatus {
+	for taskStatus, value := range statuses {
+		if value == pb {
+			return taskStatus
+		}
+	}
+	return ""
+}
+
+// toProtoTime maps an optional time to a timestamp.
+func toProtoTime(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
+
+// fromProtoTime maps an optional timestamp to a time.
+func fromProtoTime(ts *timestamppb.Timestamp) *time.Time {
+	if ts == nil {
+		return nil
+	}
+	t := ts.AsTime()
+	return &t
+}
@@ -0,0 +1,377 @@
+package grpc
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"task-manager/internal/auth"
+	"task-manager/internal/authz"
+	"task-manager/internal/tenant"
+	"task-manager/pkg/api/taskv1"
+	"task-manager/pkg/logger"
+	"task-manager/pkg/monitoring"
+	"task-manager/pkg/tracing"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// TraceIDMetadata is the metadata key carrying the trace ID of a request. Callers
+// may set it to correlate their logs with ours; the ID of the trace of the RPC is
+// used otherwise. Traced callers send the W3C traceparent metadata instead.
+const TraceIDMetadata = "x-trace-id"
+
+// TenantMetadata is the metadata key naming the tenant of a request, like the
+// X-Tenant-ID header of the REST API.
+const TenantMetadata = "x-tenant-id"
+
+// AuthorizationMetadata is the metadata key carrying the credentials of a request,
+// like the Authorization header of the REST API, e.g. "Bearer <JWT>" or "ApiKey <key>".
+const AuthorizationMetadata = "authorization"
+
+// readMethods are the RPCs API keys may call with the tasks:read scope, the others
+// need tasks:write.
+var readMethods = map[string]bool{
+	taskv1.TaskService_GetTask_FullMethodName:    true,
+	taskv1.TaskService_ListTasks_FullMethodName:  true,
+	taskv1.TaskService_WatchTasks_FullMethodName: true,
+}
+
+// TracingUnaryInterceptor runs every RPC in a server span, child of the span of the
+// traceparent metadata when the caller sent one, and injects the trace ID into the
+// request context, like the REST TracingMiddleware.
+func TracingUnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (res any, err error) {
+		ctx, span := startSpan(ctx, info.FullMethod)
+		defer func() { endSpan(span, err) }()
+
+		return handler(withTraceID(ctx), req)
+	}
+}
+
+// TracingStreamInterceptor runs every stream in a server span and injects the trace
+// ID into the stream context.
+func TracingStreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		ctx, span := startSpan(ss.Context(), info.FullMethod)
+		defer func() { endSpan(span, err) }()
+
+		return handler(srv, &contextStream{ServerStream: ss, ctx: withTraceID(ctx)})
+	}
+}
+
+// AuthenticationUnaryInterceptor authenticates every RPC with the authenticator of the
+// scheme of its authorization metadata and stores the principal in the request context,
+// like the REST AuthenticationMiddleware. RPCs without credentials, with an unknown
+// scheme or rejected credentials fail with Unauthenticated, the ones of API keys lacking
+// the task scope of the method with PermissionDenied. Without authenticators, i.e. when
+// authentication is disabled, every RPC passes as an internal caller (see
+// authz.WithInternalCaller).
+func AuthenticationUnaryInterceptor(authenticators ...auth.Authenticator) grpc.UnaryServerInterceptor {
+	schemes := authenticatorSchemes(authenticators)
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if len(schemes) == 0 {
+			return handler(authz.WithInternalCaller(ctx), req)
+		}
+
+		ctx, err := authenticate(ctx, schemes, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// AuthenticationStreamInterceptor authenticates every stream and stores the principal
+// in the stream context.
+func AuthenticationStreamInterceptor(authenticators ...auth.Authenticator) grpc.StreamServerInterceptor {
+	schemes := authenticatorSchemes(authenticators)
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if len(schemes) == 0 {
+			return handler(srv, &contextStream{ServerStream: ss, ctx: authz.WithInternalCaller(ss.Context())})
+		}
+
+		ctx, err := authenticate(ss.Context(), schemes, info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// TenantUnaryInterceptor stores the tenant of the RPC in the request context, like the
+// REST TenantMiddleware: the one of the principal, or the one named by the x-tenant-id
+// metadata (the default one if none) when authentication is disabled. It runs after
+// the authentication. Malformed tenants are rejected with InvalidArgument, tenants the
+// principal may not act in with PermissionDenied.
+func TenantUnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := withTenant(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// TenantStreamInterceptor stores the tenant in the stream context.
+func TenantStreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := withTenant(ss.Context())
+		if err != nil {
+			return err
+		}
+		return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// LoggingUnaryInterceptor logs every RPC with its trace and method and, if it failed,
+// its status.
+func LoggingUnaryInterceptor(log logger.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		log.InfoWithContext(ctx, LogIncoming)
+
+		res, err := handler(ctx, req)
+		logResult(ctx, log, err)
+
+		return res, err
+	}
+}
+
+// LoggingStreamInterceptor logs every stream when it opens and when it ends.
+func LoggingStreamInterceptor(log logger.Logger) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		log.InfoWithContext(ss.Context(), LogIncoming)
+
+		err := handler(srv, ss)
+		logResult(ss.Context(), log, err)
+
+		return err
+	}
+}
+
+// MetricsUnaryInterceptor records the count and latency of every RPC per status code.
+func MetricsUnaryInterceptor(metrics *monitoring.GRPCMetrics) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+
+		res, err := handler(ctx, req)
+
+		metrics.Requests.WithLabelValues(info.FullMethod, status.Code(err).String()).Inc()
+		metrics.Duration.WithLabelValues(info.FullMethod).Observe(time.Since(start).Seconds())
+
+		return res, err
+	}
+}
+
+// MetricsStreamInterceptor records the count and duration of every stream, and the open streams.
+func MetricsStreamInterceptor(metrics *monitoring.GRPCMetrics) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		open := metrics.Streams.WithLabelValues(info.FullMethod)
+
+		open.Inc()
+		err := handler(srv, ss)
+		open.Dec()
+
+		metrics.Requests.WithLabelValues(info.FullMethod, status.Code(err).String()).Inc()
+		metrics.Duration.WithLabelValues(info.FullMethod).Observe(time.Since(start).Seconds())
+
+		return err
+	}
+}
+
+// TraceIDFromContext retrieves the trace ID from the context, if available: the one
+// of the caller, or else the one of the trace of the RPC. Returns empty string if not
+// found.
+func TraceIDFromContext(ctx context.Context) string {
+	if v, ok := ctx.Value(traceIDKey{}).(string); ok {
+		return v
+	}
+	return tracing.TraceID(ctx)
+}
+
+// traceIDKey is the context key of the trace ID sent by the caller.
+type traceIDKey struct{}
+
+// LogAttrs returns the trace ID sent by the caller in the x-trace-id metadata, if
+// any, for the records logged with ctx. Registered with logger.WithContextAttrs.
+func LogAttrs(ctx context.Context) []slog.Attr {
+	if v, ok := ctx.Value(traceIDKey{}).(string); ok {
+		return []slog.Attr{slog.String(LogKeyCallerTraceID, v)}
+	}
+	return nil
+}
+
+// withTraceID stores the caller's trace ID in the context, if any, and returns the
+// trace ID of the RPC in the response header.
+func withTraceID(ctx context.Context) context.Context {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(TraceIDMetadata); len(values) > 0 && values[0] != "" {
+			ctx = context.WithValue(ctx, traceIDKey{}, values[0])
+		}
+	}
+
+	_ = grpc.SetHeader(ctx, metadata.Pairs(TraceIDMetadata, TraceIDFromContext(ctx)))
+
+	return ctx
+}
+
+// startSpan starts the server span of an RPC, child of the span of the traceparent
+// metadata of the caller, and stores the method as the route of the logs.
+func startSpan(ctx context.Context, fullMethod string) (context.Context, trace.Span) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	ctx = tracing.Propagator().Extract(ctx, metadataCarrier(md))
+
+	service, method, _ := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+	ctx, span := tracing.Start(ctx, fullMethod,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(semconv.RPCSystemGRPC, semconv.RPCService(service), semconv.RPCMethod(method)),
+	)
+	return logger.WithRoute(ctx, fullMethod), span
+}
+
+// endSpan ends the span of an RPC with its status code, failed on the codes of server
+// errors.
+func endSpan(span trace.Span, err error) {
+	code := status.Code(err)
+	span.SetAttributes(semconv.RPCGRPCStatusCodeKey.Int(int(code)))
+	switch code {
+	case codes.Unknown, codes.DeadlineExceeded, codes.Unimplemented, codes.Internal, codes.Unavailable, codes.DataLoss:
+		tracing.End(span, err)
+	default:
+		span.End()
+	}
+}
+
+// metadataCarrier adapts the metadata of a request to the propagators.
+type metadataCarrier metadata.MD
+
+// Get returns the first value of key.
+func (c metadataCarrier) Get(key string) string {
+	if values := metadata.MD(c).Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// Set sets the value of key.
+func (c metadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+// Keys returns the keys of the metadata.
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// authenticatorSchemes indexes the authenticators by their lower-cased scheme.
+func authenticatorSchemes(authenticators []auth.Authenticator) map[string]auth.Authenticator {
+	schemes := make(map[string]auth.Authenticator, len(authenticators))
+	for _, authenticator := range authenticators {
+		schemes[strings.ToLower(authenticator.Scheme())] = authenticator
+	}
+	return schemes
+}
+
+// authenticate returns ctx with the principal of the credentials of the RPC, and checks
+// that they allow calling fullMethod.
+func authenticate(ctx context.Context, schemes map[string]auth.Authenticator, fullMethod string) (context.Context, error) {
+	var header string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(AuthorizationMetadata); len(values) > 0 {
+			header = values[0]
+		}
+	}
+
+	scheme, credentials, found := strings.Cut(header, " ")
+	authenticator, ok := schemes[strings.ToLower(scheme)]
+	switch {
+	case !found || strings.TrimSpace(credentials) == "":
+		return nil, status.Error(codes.Unauthenticated, auth.ErrUnauthenticated.Error())
+	case !ok:
+		return nil, status.Errorf(codes.Unauthenticated, "%s: unsupported authorization scheme %q", auth.ErrInvalidCredentials, scheme)
+	}
+
+	principal, err := authenticator.Authenticate(ctx, strings.TrimSpace(credentials))
+	switch {
+	case errors.Is(err, auth.ErrUnauthenticated), errors.Is(err, auth.ErrInvalidCredentials):
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	case err != nil:
+		// The credentials could not be checked, e.g. the key store is unavailable
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+
+	scope := auth.ScopeTasksWrite
+	if readMethods[fullMethod] {
+		scope = auth.ScopeTasksRead
+	}
+	if !principal.Permits(scope) {
+		return nil, status.Errorf(codes.PermissionDenied, "credentials lack the %s scope", scope)
+	}
+
+	return auth.WithPrincipal(ctx, principal), nil
+}
+
+// withTenant resolves the tenant of an RPC from its principal and its metadata (see
+// tenant.Resolve).
+func withTenant(ctx context.Context) (context.Context, error) {
+	var requested string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(TenantMetadata); len(values) > 0 {
+			requested = values[0]
+		}
+	}
+
+	principal, _ := auth.PrincipalFromContext(ctx)
+	id, err := tenant.Resolve(principal, requested)
+	switch {
+	case errors.Is(err, tenant.ErrForeignTenant):
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	case err != nil:
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	return tenant.WithID(ctx, id), nil
+}
+
+// logResult logs the outcome of an RPC.
+func logResult(ctx context.Context, log logger.Logger, err error) {
+	if err != nil {
+		log.ErrorWithContext(ctx, LogFailed, LogKeyCode, status.Code(err).String(), LogKeyError, err)
+		return
+	}
+	log.InfoWithContext(ctx, LogDone)
+}
+
+// contextStream overrides the context of a ServerStream.
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context returns the overridden context.
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
+
+const (
+	LogIncoming = "Incoming gRPC request"
+	LogDone     = "gRPC request completed"
+	LogFailed   = "gRPC request failed"
+
+	// Keys of the attributes of the RPC logs, next to their trace, method and tenant
+	LogKeyCode          = "code"
+	LogKeyError         = "error"
+	LogKeyCallerTraceID = "caller_trace_id"
+)
@@ -0,0 +1,140 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"task-manager/internal/auth"
+	"task-manager/internal/authz"
+	"task-manager/internal/config"
+	"task-manager/internal/service"
+	"task-manager/internal/stream"
+	"task-manager/pkg/api/taskv1"
+	"task-manager/pkg/logger"
+	"task-manager/pkg/monitoring"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"google.golang.org/grpc"
+)
+
+// -------------------------------
+// Server Constants
+// -------------------------------
+
+// DefaultPort is the gRPC listening port, used when GRPC_PORT is not set.
+const DefaultPort = 50051
+
+// DefaultTimeOutForGracefulShutDown defines how long the server waits for running
+// RPCs to finish during shutdown before cancelling them.
+const DefaultTimeOutForGracefulShutDown = 5 * time.Second
+
+// -------------------------------
+// Server Struct
+// -------------------------------
+
+// Server serves the task API over gRPC, next to the REST handler, on top of the
+// same services.
+type Server struct {
+	taskv1.UnimplementedTaskServiceServer
+
+	TaskService service.TaskService
+	TaskEvents  *stream.Hub
+	Authorizer  stream.Authorizer // Authorizes the WatchTasks subscriptions, task:read of authz.DefaultPolicy by default
+	logger      logger.Logger
+	GRPCServer  *grpc.Server
+
+	config  config.Config
+	Metrics *monitoring.GRPCMetrics
+}
+
+// -------------------------------
+// Constructor
+// -------------------------------
+
+// CreateServer initializes a new gRPC server with all dependencies and registers
+// the task service, wrapped in the tracing, logging, metrics, authentication and
+// tenant interceptors. RPCs are authenticated with authenticators, when there are any.
+func CreateServer(
+	logger logger.Logger,
+	config config.Config,
+	TaskService service.TaskService,
+	TaskEvents *stream.Hub,
+	Metrics *monitoring.GRPCMetrics,
+	authenticators ...auth.Authenticator,
+) *Server {
+	s := &Server{
+		logger:      logger,
+		config:      config,
+		TaskService: TaskService,
+		TaskEvents:  TaskEvents,
+		Authorizer:  stream.PolicyAuthorizer(authz.DefaultPolicy()),
+		Metrics:     Metrics,
+	}
+
+	s.GRPCServer = grpc.NewServer(
+		grpc.ChainUnaryInterceptor(
+			TracingUnaryInterceptor(),
+			LoggingUnaryInterceptor(logger),
+			MetricsUnaryInterceptor(Metrics),
+			AuthenticationUnaryInterceptor(authenticators...),
+			TenantUnaryInterceptor(),
+		),
+		grpc.ChainStreamInterceptor(
+			TracingStreamInterceptor(),
+			LoggingStreamInterceptor(logger),
+			MetricsStreamInterceptor(Metrics),
+			AuthenticationStreamInterceptor(authenticators...),
+			TenantStreamInterceptor(),
+		),
+	)
+	taskv1.RegisterTaskServiceServer(s.GRPCServer, s)
+
+	return s
+}
+
+// -------------------------------
+// Server Lifecycle Methods
+// -------------------------------
+
+// StartBlocking listens on the given port, or DefaultPort, and serves until Stop is called.
+func (s *Server) StartBlocking(_ context.Context, port int) {
+	if port == 0 {
+		port = DefaultPort
+	}
+	addr := fmt.Sprintf(":%v", port)
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		s.logger.Error(errors.Wrap(err, "[NOK] failed to listen for gRPC").Error())
+		return
+	}
+
+	s.logger.InfoF("[OK] Starting gRPC Server on %s", addr)
+	if err := s.GRPCServer.Serve(listener); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+		s.logger.Error(err.Error())
+	}
+
+	s.logger.Info("[OK] gRPC Server is shutting down!")
+}
+
+// Stop gracefully shuts down the gRPC server within DefaultTimeOutForGracefulShutDown.
+// Running RPCs are given the time to complete, open WatchTasks streams end right away.
+func (s *Server) Stop() {
+	// GracefulStop waits for the streams, which only end when their subscription does
+	s.TaskEvents.Close()
+
+	stopped := make(chan struct{})
+	go func() {
+		s.GRPCServer.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-time.After(DefaultTimeOutForGracefulShutDown):
+		s.GRPCServer.Stop()
+	}
+
+	s.logger.Info("[OK] gRPC Server graceful shutdown completed")
+}
@@ -0,0 +1,271 @@
+package grpc_test
+
+import (
+	"context"
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"io"
+	"net"
+	"task-manager/internal/auth"
+	"task-manager/internal/entities"
+	GRPCserver "task-manager/internal/grpc"
+	"task-manager/internal/repository/postgres"
+	"task-manager/internal/service"
+	"task-manager/internal/stream"
+	"task-manager/internal/tenant"
+	"task-manager/pkg/api/taskv1"
+	"task-manager/pkg/rest"
+	"testing"
+	"time"
+)
+
+// dialServer serves the server over an in-memory listener and returns a client.
+func dialServer(t *testing.T, server *GRPCserver.Server) taskv1.TaskServiceClient {
+	t.Helper()
+
+	listener := bufconn.Listen(1 << 20)
+	go func() { _ = server.GRPCServer.Serve(listener) }()
+	t.Cleanup(server.GRPCServer.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return taskv1.NewTaskServiceClient(conn)
+}
+
+// TestCreateTask_Success_ShouldDefaultStatus tests the mapping of a created task.
+func TestCreateTask_Success_ShouldDefaultStatus(t *testing.T) {
+	taskService := service.MockTaskService{}
+	dueAt := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+	taskService.On("Create", mock.Anything, mock.MatchedBy(func(task *entities.Task) bool {
+		return task.Status == entities.TaskStatusPending && task.AssigneeID == 4 && task.DueAt.Equal(dueAt)
+	})).Return(&entities.Task{ID: 9, Title: "Ship", Status: entities.TaskStatusPending, AssigneeID: 4, AssigneeIDs: []int64{4, 5}, DueAt: &dueAt}, nil)
+
+	client := dialServer(t, GRPCserver.SetupServer(&taskService))
+
+	var header metadata.MD
+	task, err := client.CreateTask(
+		metadata.AppendToOutgoingContext(context.Background(), GRPCserver.TraceIDMetadata, "trace-1"),
+		&taskv1.CreateTaskRequest{Title: "Ship", AssigneeIds: []int64{4, 5}, DueAt: timestamppb.New(dueAt)},
+		grpc.Header(&header),
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(9), task.GetId())
+	assert.Equal(t, taskv1.TaskStatus_TASK_STATUS_PENDING, task.GetStatus())
+	assert.Equal(t, []int64{4, 5}, task.GetAssigneeIds())
+	assert.True(t, task.GetDueAt().AsTime().Equal(dueAt))
+	assert.Equal(t, []string{"trace-1"}, header.Get(GRPCserver.TraceIDMetadata))
+}
+
+// TestCreateTask_Invalid_ShouldReturnInvalidArgument tests the request and service validation errors.
+func TestCreateTask_Invalid_ShouldReturnInvalidArgument(t *testing.T) {
+	taskService := service.MockTaskService{}
+	taskService.On("Create", mock.Anything, mock.Anything).Return((*entities.Task)(nil), service.ErrAssigneeInactive)
+
+	client := dialServer(t, GRPCserver.SetupServer(&taskService))
+
+	_, err := client.CreateTask(context.Background(), &taskv1.CreateTaskRequest{AssigneeId: 4})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = client.CreateTask(context.Background(), &taskv1.CreateTaskRequest{Title: "Ship", AssigneeId: 4})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Contains(t, status.Convert(err).Message(), service.ErrAssigneeInactive.Error())
+}
+
+// TestGetTask_Errors tests the mapping of not found and internal errors.
+func TestGetTask_Errors(t *testing.T) {
+	taskService := service.MockTaskService{}
+	taskService.On("GetByID", mock.Anything, int64(1)).Return((*entities.Task)(nil), postgres.ErrTaskNotFound)
+	taskService.On("GetByID", mock.Anything, int64(2)).Return((*entities.Task)(nil), errors.New("connection refused"))
+
+	client := dialServer(t, GRPCserver.SetupServer(&taskService))
+
+	_, err := client.GetTask(context.Background(), &taskv1.GetTaskRequest{Id: 1})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	// Internal details are not exposed
+	_, err = client.GetTask(context.Background(), &taskv1.GetTaskRequest{Id: 2})
+	assert.Equal(t, codes.Internal, status.Code(err))
+	assert.Equal(t, GRPCserver.ErrInternal, status.Convert(err).Message())
+}
+
+// TestListTasks_ShouldStreamAllPages tests that the list is streamed page by page.
+func TestListTasks_ShouldStreamAllPages(t *testing.T) {
+	taskService := service.MockTaskService{}
+	page := func(n int) rest.Query {
+		return rest.Query{Filter: rest.Filter{"status": "done"}, PaginationMeta: rest.PaginationMeta{Page: n, PerPage: 2}}
+	}
+	taskService.On("List", mock.Anything, page(1)).Return([]entities.Task{{ID: 1}, {ID: 2}}, 3, nil)
+	taskService.On("List", mock.Anything, page(2)).Return([]entities.Task{{ID: 3}}, 3, nil)
+
+	client := dialServer(t, GRPCserver.SetupServer(&taskService))
+
+	list, err := client.ListTasks(context.Background(), &taskv1.ListTasksRequest{Filter: map[string]string{"status": "done"}, PageSize: 2})
+	require.NoError(t, err)
+
+	var ids []int64
+	for {
+		task, err := list.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+		ids = append(ids, task.GetId())
+	}
+
+	assert.Equal(t, []int64{1, 2, 3}, ids)
+	taskService.AssertNumberOfCalls(t, "List", 2)
+}
+
+// TestWatchTasks_ShouldStreamMatchingEvents tests the ready message and the event filter.
+func TestWatchTasks_ShouldStreamMatchingEvents(t *testing.T) {
+	server := GRPCserver.SetupServer(&service.MockTaskService{})
+	client := dialServer(t, server)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	watch, err := client.WatchTasks(ctx, &taskv1.WatchTasksRequest{Filter: map[string]string{"status": "done"}, LastEventId: "unknown-1"})
+	require.NoError(t, err)
+
+	ready, err := watch.Recv()
+	require.NoError(t, err)
+	assert.True(t, ready.GetReady().GetResetRequired())
+
+	require.NoError(t, server.TaskEvents.Publish(ctx, entities.OutboxEvent{Sequence: 1, TaskEvent: entities.TaskEvent{ID: "e1", Type: entities.TaskEventUpdated, TaskID: 1,
+		Task: &entities.Task{ID: 1, Status: entities.TaskStatusPending}}}))
+	require.NoError(t, server.TaskEvents.Publish(ctx, entities.OutboxEvent{Sequence: 2, TaskEvent: entities.TaskEvent{ID: "e2", Type: entities.TaskEventStatusChanged, TaskID: 2,
+		Task: &entities.Task{ID: 2, Status: entities.TaskStatusDone}, PreviousStatus: entities.TaskStatusInProgress}}))
+
+	res, err := watch.Recv()
+	require.NoError(t, err)
+	event := res.GetEvent()
+	require.NotNil(t, event)
+	assert.Equal(t, "e2", event.GetId())
+	assert.Equal(t, taskv1.TaskEventType_TASK_EVENT_TYPE_STATUS_CHANGED, event.GetType())
+	assert.Equal(t, taskv1.TaskStatus_TASK_STATUS_IN_PROGRESS, event.GetPreviousStatus())
+	assert.NotEmpty(t, event.GetStreamId())
+
+	// Shutdown ends the stream
+	server.TaskEvents.Close()
+	_, err = watch.Recv()
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+}
+
+// TestWatchTasks_Denied_ShouldReturnPermissionDenied tests that the subscription is
+// authorized before the stream starts.
+func TestWatchTasks_Denied_ShouldReturnPermissionDenied(t *testing.T) {
+	server := GRPCserver.SetupServer(&service.MockTaskService{})
+	server.Authorizer = stream.AuthorizerFunc(func(context.Context, stream.Filter) error {
+		return stream.ErrForbidden
+	})
+	client := dialServer(t, server)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	watch, err := client.WatchTasks(ctx, &taskv1.WatchTasksRequest{})
+	require.NoError(t, err)
+
+	_, err = watch.Recv()
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+}
+
+// tokenAuthenticator accepts the "valid" token of user 42 and the "reader" API key
+// limited to tasks:read.
+type tokenAuthenticator struct{ scheme string }
+
+func (a tokenAuthenticator) Scheme() string { return a.scheme }
+
+func (a tokenAuthenticator) Authenticate(_ context.Context, credentials string) (*auth.Principal, error) {
+	switch {
+	case a.scheme == "Bearer" && credentials == "valid":
+		return &auth.Principal{Subject: "42", UserID: 42, Method: auth.MethodJWT}, nil
+	case a.scheme == "ApiKey" && credentials == "reader":
+		return &auth.Principal{Subject: "api-key:1", Scopes: []string{auth.ScopeTasksRead}, Method: auth.MethodAPIKey}, nil
+	}
+	return nil, auth.ErrInvalidCredentials
+}
+
+// TestAuthentication_ShouldRequireCredentials tests that RPCs and streams are rejected
+// without valid credentials, and that the principal reaches the TaskService.
+func TestAuthentication_ShouldRequireCredentials(t *testing.T) {
+	taskService := service.MockTaskService{}
+	taskService.On("GetByID", mock.MatchedBy(func(ctx context.Context) bool {
+		principal, ok := auth.PrincipalFromContext(ctx)
+		return ok && principal.UserID == 42
+	}), int64(1)).Return(&entities.Task{ID: 1, Title: "Ship"}, nil).Once()
+	taskService.On("GetByID", mock.Anything, int64(2)).Return(&entities.Task{ID: 2, Title: "Read"}, nil).Once()
+
+	client := dialServer(t, GRPCserver.SetupServer(&taskService,
+		tokenAuthenticator{scheme: "Bearer"}, tokenAuthenticator{scheme: "ApiKey"}))
+	withCredentials := func(credentials string) context.Context {
+		return metadata.AppendToOutgoingContext(context.Background(), GRPCserver.AuthorizationMetadata, credentials)
+	}
+
+	_, err := client.GetTask(context.Background(), &taskv1.GetTaskRequest{Id: 1})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = client.GetTask(withCredentials("Bearer expired"), &taskv1.GetTaskRequest{Id: 1})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = client.GetTask(withCredentials("Basic dXNlcjpwYXNz"), &taskv1.GetTaskRequest{Id: 1})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	task, err := client.GetTask(withCredentials("Bearer valid"), &taskv1.GetTaskRequest{Id: 1})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), task.GetId())
+
+	// API keys are limited to their scopes
+	task, err = client.GetTask(withCredentials("ApiKey reader"), &taskv1.GetTaskRequest{Id: 2})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), task.GetId())
+
+	_, err = client.DeleteTask(withCredentials("ApiKey reader"), &taskv1.DeleteTaskRequest{Id: 2})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	watch, err := client.WatchTasks(context.Background(), &taskv1.WatchTasksRequest{})
+	require.NoError(t, err)
+	_, err = watch.Recv()
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	taskService.AssertExpectations(t)
+	taskService.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
+
+// TestTenant_ShouldFollowThePrincipal tests that authenticated RPCs act in the tenant of
+// their principal, which the x-tenant-id metadata can't switch.
+func TestTenant_ShouldFollowThePrincipal(t *testing.T) {
+	taskService := service.MockTaskService{}
+	taskService.On("GetByID", mock.MatchedBy(func(ctx context.Context) bool {
+		return tenant.Of(ctx) == tenant.Default
+	}), int64(1)).Return(&entities.Task{ID: 1, Title: "Ship"}, nil).Once()
+
+	client := dialServer(t, GRPCserver.SetupServer(&taskService, tokenAuthenticator{scheme: "Bearer"}))
+	ctx := metadata.AppendToOutgoingContext(context.Background(), GRPCserver.AuthorizationMetadata, "Bearer valid")
+
+	_, err := client.GetTask(ctx, &taskv1.GetTaskRequest{Id: 1})
+	require.NoError(t, err)
+
+	_, err = client.GetTask(metadata.AppendToOutgoingContext(ctx, GRPCserver.TenantMetadata, "acme"), &taskv1.GetTaskRequest{Id: 1})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	_, err = client.GetTask(metadata.AppendToOutgoingContext(ctx, GRPCserver.TenantMetadata, "Not Valid"), &taskv1.GetTaskRequest{Id: 1})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	taskService.AssertExpectations(t)
+}
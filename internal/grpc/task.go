@@ -0,0 +1,293 @@
+package grpc
+
+import (
+	"context"
+	"task-manager/internal/authz"
+	"task-manager/internal/entities"
+	"task-manager/internal/repository/postgres"
+	"task-manager/internal/service"
+	"task-manager/internal/stream"
+	"task-manager/internal/tenant"
+	"task-manager/pkg/api/taskv1"
+	"task-manager/pkg/rest"
+
+	"github.com/cockroachdb/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// Page sizes of ListTasks.
+const (
+	DefaultListPageSize = 100
+	MaxListPageSize     = 1000
+)
+
+// CreateTask creates a task. The status defaults to pending.
+func (s *Server) CreateTask(ctx context.Context, req *taskv1.CreateTaskRequest) (*taskv1.Task, error) {
+	taskStatus := entities.TaskStatusPending
+	if req.GetStatus() != taskv1.TaskStatus_TASK_STATUS_UNSPECIFIED {
+		taskStatus = fromProtoStatus(req.GetStatus())
+	}
+
+	task := &entities.Task{
+		Title:       req.GetTitle(),
+		Description: req.GetDescription(),
+		Status:      taskStatus,
+		AssigneeID:  primaryAssignee(req.GetAssigneeId(), req.GetAssigneeIds()),
+		AssigneeIDs: req.GetAssigneeIds(),
+		WatcherIDs:  req.GetWatcherIds(),
+		DueAt:       fromProtoTime(req.GetDueAt()),
+
+		RecurrenceRule: req.GetRecurrenceRule(),
+	}
+	if err := validateTask(task); err != nil {
+		return nil, err
+	}
+
+	createdTask, err := s.TaskService.Create(ctx, task)
+	if err != nil {
+		return nil, s.statusError(ctx, err)
+	}
+
+	return toProtoTask(createdTask), nil
+}
+
+// GetTask returns a task by ID.
+func (s *Server) GetTask(ctx context.Context, req *taskv1.GetTaskRequest) (*taskv1.Task, error) {
+	task, err := s.TaskService.GetByID(ctx, req.GetId())
+	if err != nil {
+		return nil, s.statusError(ctx, err)
+	}
+
+	return toProtoTask(task), nil
+}
+
+// UpdateTask replaces the fields of a task, like the REST update.
+func (s *Server) UpdateTask(ctx context.Context, req *taskv1.UpdateTaskRequest) (*taskv1.Task, error) {
+	task := &entities.Task{
+		ID:          req.GetId(),
+		Title:       req.GetTitle(),
+		Description: req.GetDescription(),
+		Status:      fromProtoStatus(req.GetStatus()),
+		AssigneeID:  primaryAssignee(req.GetAssigneeId(), req.GetAssigneeIds()),
+		AssigneeIDs: req.GetAssigneeIds(),
+		WatcherIDs:  req.GetWatcherIds(),
+		DueAt:       fromProtoTime(req.GetDueAt()),
+
+		RecurrenceRule: req.GetRecurrenceRule(),
+	}
+	if err := validateTask(task); err != nil {
+		return nil, err
+	}
+
+	updatedTask, err := s.TaskService.Update(ctx, task)
+	if err != nil {
+		return nil, s.statusError(ctx, err)
+	}
+
+	return toProtoTask(updatedTask), nil
+}
+
+// DeleteTask deletes a task.
+func (s *Server) DeleteTask(ctx context.Context, req *taskv1.DeleteTaskRequest) (*emptypb.Empty, error) {
+	if err := s.TaskService.Delete(ctx, req.GetId()); err != nil {
+		return nil, s.statusError(ctx, err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// ListTasks streams every task matching the filter, fetching them page by page.
+// Tasks changed while listing may be skipped or sent twice, as with the REST pages.
+func (s *Server) ListTasks(req *taskv1.ListTasksRequest, srv grpc.ServerStreamingServer[taskv1.Task]) error {
+	perPage := int(req.GetPageSize())
+	if perPage <= 0 {
+		perPage = DefaultListPageSize
+	}
+	perPage = min(perPage, MaxListPageSize)
+
+	query := rest.Query{
+		Filter:         rest.Filter(req.GetFilter()),
+		PaginationMeta: rest.PaginationMeta{Page: 1, PerPage: perPage},
+	}
+	if query.Filter == nil {
+		query.Filter = make(rest.Filter)
+	}
+
+	for sent := 0; ; query.Page++ {
+		tasks, total, err := s.TaskService.List(srv.Context(), query)
+		if err != nil {
+			return s.statusError(srv.Context(), err)
+		}
+
+		for i := range tasks {
+			if err := srv.Send(toProtoTask(&tasks[i])); err != nil {
+				return err
+			}
+		}
+
+		sent += len(tasks)
+		if len(tasks) < perPage || sent >= total {
+			return nil
+		}
+	}
+}
+
+// WatchTasks streams the changes of the matching tasks until the client cancels or
+// the server shuts down. The first message tells whether the events missed since
+// last_event_id could be replayed. Subscriptions denied by the Authorizer fail with
+// PermissionDenied.
+func (s *Server) WatchTasks(req *taskv1.WatchTasksRequest, srv grpc.ServerStreamingServer[taskv1.WatchTasksResponse]) error {
+	filter, err := stream.NewFilter(req.GetFilter())
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	filter = filter.WithTenant(tenant.Of(srv.Context()))
+
+	if err := s.Authorizer.Authorize(srv.Context(), filter); err != nil {
+		return s.statusError(srv.Context(), err)
+	}
+
+	sub, replay, resumed := s.TaskEvents.Subscribe(req.GetLastEventId())
+	defer s.TaskEvents.Unsubscribe(sub)
+
+	ready := &taskv1.WatchTasksResponse{Payload: &taskv1.WatchTasksResponse_Ready{
+		Ready: &taskv1.WatchReady{ResetRequired: !resumed},
+	}}
+	if err := srv.Send(ready); err != nil {
+		return err
+	}
+
+	send := func(e stream.Event) error {
+		if !filter.Match(e.Event) {
+			return nil
+		}
+		return srv.Send(&taskv1.WatchTasksResponse{Payload: &taskv1.WatchTasksResponse_Event{
+			Event: toProtoEvent(e),
+		}})
+	}
+
+	for _, e := range replay {
+		if err := send(e); err != nil {
+			return err
+		}
+	}
+
+	for {
+		select {
+		case <-srv.Context().Done():
+			return nil
+		case e, ok := <-sub.Events():
+			if !ok {
+				if s.TaskEvents.Dropped(sub) {
+					return status.Error(codes.ResourceExhausted, ErrWatchTooSlow)
+				}
+				return status.Error(codes.Unavailable, ErrWatchClosed)
+			}
+			if err := send(e); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// AddAssignee adds an active user as co-assignee.
+func (s *Server) AddAssignee(ctx context.Context, req *taskv1.TaskMemberRequest) (*taskv1.Task, error) {
+	task, err := s.TaskService.AddAssignee(ctx, req.GetTaskId(), req.GetUserId())
+	if err != nil {
+		return nil, s.statusError(ctx, err)
+	}
+
+	return toProtoTask(task), nil
+}
+
+// RemoveAssignee removes a co-assignee.
+func (s *Server) RemoveAssignee(ctx context.Context, req *taskv1.TaskMemberRequest) (*emptypb.Empty, error) {
+	if err := s.TaskService.RemoveAssignee(ctx, req.GetTaskId(), req.GetUserId()); err != nil {
+		return nil, s.statusError(ctx, err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// AddWatcher subscribes a user to the task.
+func (s *Server) AddWatcher(ctx context.Context, req *taskv1.TaskMemberRequest) (*taskv1.Task, error) {
+	task, err := s.TaskService.AddWatcher(ctx, req.GetTaskId(), req.GetUserId())
+	if err != nil {
+		return nil, s.statusError(ctx, err)
+	}
+
+	return toProtoTask(task), nil
+}
+
+// RemoveWatcher unsubscribes a user from the task.
+func (s *Server) RemoveWatcher(ctx context.Context, req *taskv1.TaskMemberRequest) (*emptypb.Empty, error) {
+	if err := s.TaskService.RemoveWatcher(ctx, req.GetTaskId(), req.GetUserId()); err != nil {
+		return nil, s.statusError(ctx, err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// validateTask checks the fields the REST API validates when binding the request.
+func validateTask(task *entities.Task) error {
+	switch {
+	case task.Title == "":
+		return status.Error(codes.InvalidArgument, ErrTitleRequired)
+	case !task.Status.IsValid():
+		return status.Error(codes.InvalidArgument, ErrInvalidStatus)
+	case task.AssigneeID == 0:
+		return status.Error(codes.InvalidArgument, ErrAssigneeRequired)
+	}
+	return nil
+}
+
+// statusError maps service errors to gRPC status errors, like the REST handlers map
+// them to HTTP status codes. Internal errors are logged and not exposed to the caller.
+func (s *Server) statusError(ctx context.Context, err error) error {
+	switch {
+	case errors.Is(err, postgres.ErrTaskNotFound), errors.Is(err, postgres.ErrTaskMemberNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, authz.ErrForbidden), errors.Is(err, stream.ErrForbidden):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, service.ErrPrimaryAssignee):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case isValidationError(err):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	default:
+		s.logger.ErrorWithContext(ctx, LogFailed, LogKeyError, err)
+		return status.Error(codes.Internal, ErrInternal)
+	}
+}
+
+// isValidationError reports whether err was caused by invalid members or an invalid recurrence.
+func isValidationError(err error) bool {
+	return errors.Is(err, service.ErrAssigneeNotFound) ||
+		errors.Is(err, service.ErrAssigneeInactive) ||
+		errors.Is(err, service.ErrWatcherNotFound) ||
+		errors.Is(err, service.ErrInvalidRecurrenceRule) ||
+		errors.Is(err, service.ErrRecurrenceRequiresDueAt)
+}
+
+// primaryAssignee defaults the primary assignee to the first co-assignee.
+func primaryAssignee(assigneeID int64, assigneeIDs []int64) int64 {
+	if assigneeID == 0 && len(assigneeIDs) > 0 {
+		return assigneeIDs[0]
+	}
+	return assigneeID
+}
+
+const (
+	ErrTitleRequired    = "title is required"
+	ErrInvalidStatus    = "invalid task status"
+	ErrAssigneeRequired = "assignee_id or assignee_ids is required"
+	ErrWatchTooSlow     = "watch stream fell behind, resume with last_event_id"
+	ErrWatchClosed      = "server shutting down, resume with last_event_id"
+	ErrInternal         = "Internal Server Error"
+)
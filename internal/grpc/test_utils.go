@@ -0,0 +1,20 @@
+package grpc
+
+import (
+	"log/slog"
+	"os"
+	"task-manager/internal/auth"
+	"task-manager/internal/config"
+	"task-manager/internal/service"
+	"task-manager/internal/stream"
+	"task-manager/internal/utils"
+	"task-manager/pkg/logger"
+)
+
+func SetupServer(taskService *service.MockTaskService, authenticators ...auth.Authenticator) *Server {
+	myLogger := &logger.StandardLogger{
+		Logger: slog.New(slog.NewTextHandler(os.Stdout, nil)),
+	}
+
+	return CreateServer(myLogger, config.Config{}, taskService, stream.NewHub(stream.Settings{}), utils.InitGlobalGRPCMetrics(), authenticators...)
+}
@@ -0,0 +1,271 @@
+// Package health reports whether the instance is alive, started and ready to serve.
+//
+// Components register a Checker per dependency (database pool, Redis, background
+// jobs) in a Registry. Startup checks, such as the database migrations, must pass
+// once before the instance is started; readiness checks are run on every probe, and
+// the instance is not ready while a required one fails, before it is started or once
+// it is draining for shutdown. Optional checks only degrade the reported status.
+package health
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/cockroachdb/errors"
+)
+
+// DefaultCheckTimeout bounds every check, when no timeout is configured.
+const DefaultCheckTimeout = 2 * time.Second
+
+// ErrDraining is reported once the instance is draining for shutdown.
+var ErrDraining = errors.New("instance is shutting down")
+
+// ErrNotStarted is reported by the readiness checks until the startup checks passed.
+var ErrNotStarted = errors.New("startup checks did not pass yet")
+
+// Statuses of a check and of a report.
+const (
+	StatusOK       = "ok"
+	StatusDegraded = "degraded" // An optional check fails
+	StatusFail     = "fail"
+)
+
+// Checker checks a dependency of the instance, returning why it is unhealthy.
+type Checker interface {
+	Check(ctx context.Context) error
+}
+
+// CheckerFunc adapts a function to a Checker.
+type CheckerFunc func(ctx context.Context) error
+
+// Check implements Checker.
+func (f CheckerFunc) Check(ctx context.Context) error {
+	return f(ctx)
+}
+
+// Option configures a registered check.
+type Option func(*check)
+
+// Optional makes a failing check degrade the status without failing the readiness,
+// for the dependencies the instance serves requests without.
+func Optional() Option {
+	return func(c *check) {
+		c.optional = true
+	}
+}
+
+// check is a registered Checker.
+type check struct {
+	name     string
+	checker  Checker
+	optional bool
+}
+
+// Result is the outcome of a check.
+type Result struct {
+	Name      string  `json:"name"`
+	Status    string  `json:"status"`
+	Optional  bool    `json:"optional,omitempty"`
+	LatencyMS float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// Report is the outcome of the checks of a probe.
+type Report struct {
+	Status   string   `json:"status"`
+	Draining bool     `json:"draining,omitempty"`
+	Started  bool     `json:"started"`
+	Checks   []Result `json:"checks"`
+}
+
+// Healthy reports whether the probe passes: no required check fails.
+func (r Report) Healthy() bool {
+	return r.Status != StatusFail
+}
+
+// Registry holds the checks of the instance. It is safe for concurrent use.
+type Registry struct {
+	timeout time.Duration
+
+	mu        sync.RWMutex
+	readiness []check
+	startup   []check
+
+	started  atomic.Bool
+	draining atomic.Bool
+}
+
+// NewRegistry returns a Registry bounding every check by timeout, DefaultCheckTimeout
+// if not positive.
+func NewRegistry(timeout time.Duration) *Registry {
+	if timeout <= 0 {
+		timeout = DefaultCheckTimeout
+	}
+	return &Registry{timeout: timeout}
+}
+
+// Register adds a readiness check, run on every readiness probe.
+func (r *Registry) Register(name string, checker Checker, opts ...Option) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.readiness = append(r.readiness, newCheck(name, checker, opts))
+}
+
+// RegisterStartup adds a startup check, run until every startup check passed once.
+func (r *Registry) RegisterStartup(name string, checker Checker, opts ...Option) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.startup = append(r.startup, newCheck(name, checker, opts))
+}
+
+// Drain fails the readiness from now on, so the load balancers stop routing
+// requests to the instance before it shuts down.
+func (r *Registry) Drain() {
+	r.draining.Store(true)
+}
+
+// Live reports whether the instance is alive. It checks no dependency: restarting
+// the instance would not fix them.
+func (r *Registry) Live() Report {
+	return Report{Status: StatusOK, Draining: r.draining.Load(), Started: r.started.Load(), Checks: []Result{}}
+}
+
+// Startup runs the startup checks until they all passed once.
+func (r *Registry) Startup(ctx context.Context) Report {
+	if r.started.Load() {
+		return Report{Status: StatusOK, Draining: r.draining.Load(), Started: true, Checks: []Result{}}
+	}
+
+	r.mu.RLock()
+	checks := r.startup
+	r.mu.RUnlock()
+
+	report := r.run(ctx, checks)
+	if report.Healthy() {
+		r.started.Store(true)
+		report.Started = true
+	}
+	return report
+}
+
+// Ready runs the readiness checks, after the startup checks until they passed. The
+// instance is not ready while draining.
+func (r *Registry) Ready(ctx context.Context) Report {
+	if !r.started.Load() {
+		if startup := r.Startup(ctx); !startup.Healthy() {
+			startup.Checks = append(startup.Checks, Result{Name: "startup", Status: StatusFail, Error: ErrNotStarted.Error()})
+			return startup
+		}
+	}
+
+	r.mu.RLock()
+	checks := r.readiness
+	r.mu.RUnlock()
+
+	report := r.run(ctx, checks)
+	if report.Draining {
+		report.Status = StatusFail
+		report.Checks = append(report.Checks, Result{Name: "shutdown", Status: StatusFail, Error: ErrDraining.Error()})
+	}
+	return report
+}
+
+// Details runs every check, startup checks included, for a detailed status. Its
+// status is the readiness of the instance.
+func (r *Registry) Details(ctx context.Context) Report {
+	if !r.started.Load() {
+		r.Startup(ctx)
+	}
+
+	r.mu.RLock()
+	checks := append(append([]check(nil), r.startup...), r.readiness...)
+	r.mu.RUnlock()
+
+	report := r.run(ctx, checks)
+	if report.Draining || !report.Started {
+		report.Status = StatusFail
+	}
+	return report
+}
+
+// run runs the checks concurrently, each bounded by the timeout of the registry.
+func (r *Registry) run(ctx context.Context, checks []check) Report {
+	report := Report{
+		Status:   StatusOK,
+		Draining: r.draining.Load(),
+		Started:  r.started.Load(),
+		Checks:   make([]Result, len(checks)),
+	}
+
+	var wg sync.WaitGroup
+	for i, c := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			report.Checks[i] = r.runCheck(ctx, c)
+		}()
+	}
+	wg.Wait()
+
+	for _, result := range report.Checks {
+		switch {
+		case result.Status == StatusOK:
+		case result.Optional:
+			if report.Status == StatusOK {
+				report.Status = StatusDegraded
+			}
+		default:
+			report.Status = StatusFail
+		}
+	}
+	sort.SliceStable(report.Checks, func(i, j int) bool {
+		return report.Checks[i].Name < report.Checks[j].Name
+	})
+	return report
+}
+
+// runCheck runs a check, reporting its panics as failures.
+func (r *Registry) runCheck(ctx context.Context, c check) Result {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	result := Result{Name: c.name, Status: StatusOK, Optional: c.optional}
+	start := time.Now()
+
+	// Checks ignoring their context are abandoned at the timeout
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				done <- errors.Newf("check panicked: %v", recovered)
+			}
+		}()
+		done <- c.checker.Check(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	result.LatencyMS = float64(time.Since(start).Microseconds()) / 1000
+	if err != nil {
+		result.Status = StatusFail
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// newCheck returns a check configured by opts.
+func newCheck(name string, checker Checker, opts []Option) check {
+	c := check{name: name, checker: checker}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return c
+}
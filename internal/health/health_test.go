@@ -0,0 +1,189 @@
+package health_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"task-manager/internal/health"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	passing = health.CheckerFunc(func(context.Context) error { return nil })
+	failing = health.CheckerFunc(func(context.Context) error { return errors.New("connection refused") })
+)
+
+// TestRegistry_Ready tests the readiness status of the checks.
+func TestRegistry_Ready(t *testing.T) {
+	tests := []struct {
+		name     string
+		register func(r *health.Registry)
+		want     string
+	}{
+		{
+			name:     "no check",
+			register: func(*health.Registry) {},
+			want:     health.StatusOK,
+		},
+		{
+			name: "passing checks",
+			register: func(r *health.Registry) {
+				r.Register("postgres", passing)
+				r.Register("redis", passing)
+			},
+			want: health.StatusOK,
+		},
+		{
+			name: "failing required check",
+			register: func(r *health.Registry) {
+				r.Register("postgres", failing)
+				r.Register("job:outbox", passing, health.Optional())
+			},
+			want: health.StatusFail,
+		},
+		{
+			name: "failing optional check",
+			register: func(r *health.Registry) {
+				r.Register("postgres", passing)
+				r.Register("job:outbox", failing, health.Optional())
+			},
+			want: health.StatusDegraded,
+		},
+		{
+			name: "panicking check",
+			register: func(r *health.Registry) {
+				r.Register("postgres", health.CheckerFunc(func(context.Context) error { panic("boom") }))
+			},
+			want: health.StatusFail,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := health.NewRegistry(time.Second)
+			tt.register(registry)
+
+			report := registry.Ready(context.Background())
+
+			assert.Equal(t, tt.want, report.Status)
+			assert.Equal(t, tt.want != health.StatusFail, report.Healthy())
+			assert.True(t, report.Started)
+		})
+	}
+}
+
+// TestRegistry_Ready_ShouldReportEveryCheck tests that every check is reported by name
+// with its status, latency and error.
+func TestRegistry_Ready_ShouldReportEveryCheck(t *testing.T) {
+	registry := health.NewRegistry(time.Second)
+	registry.Register("redis", failing)
+	registry.Register("postgres", health.CheckerFunc(func(context.Context) error {
+		time.Sleep(5 * time.Millisecond)
+		return nil
+	}))
+
+	report := registry.Ready(context.Background())
+
+	require.Len(t, report.Checks, 2)
+	assert.Equal(t, "postgres", report.Checks[0].Name)
+	assert.Equal(t, health.StatusOK, report.Checks[0].Status)
+	assert.GreaterOrEqual(t, report.Checks[0].LatencyMS, 5.0)
+	assert.Empty(t, report.Checks[0].Error)
+	assert.Equal(t, "redis", report.Checks[1].Name)
+	assert.Equal(t, health.StatusFail, report.Checks[1].Status)
+	assert.Equal(t, "connection refused", report.Checks[1].Error)
+}
+
+// TestRegistry_Ready_SlowCheck_ShouldTimeOut tests that the checks are abandoned at the
+// timeout of the registry, even when they ignore their context.
+func TestRegistry_Ready_SlowCheck_ShouldTimeOut(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	registry := health.NewRegistry(20 * time.Millisecond)
+	registry.Register("postgres", health.CheckerFunc(func(context.Context) error {
+		<-release
+		return nil
+	}))
+
+	start := time.Now()
+	report := registry.Ready(context.Background())
+
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Equal(t, health.StatusFail, report.Status)
+	assert.Equal(t, context.DeadlineExceeded.Error(), report.Checks[0].Error)
+}
+
+// TestRegistry_Startup_ShouldWaitForTheStartupChecks tests that the instance is neither
+// started nor ready until the startup checks passed, which are not run once they did.
+func TestRegistry_Startup_ShouldWaitForTheStartupChecks(t *testing.T) {
+	var migrated atomic.Bool
+	var runs atomic.Int32
+	registry := health.NewRegistry(time.Second)
+	registry.Register("postgres", passing)
+	registry.RegisterStartup("migrations", health.CheckerFunc(func(context.Context) error {
+		runs.Add(1)
+		if !migrated.Load() {
+			return errors.New("schema at version 10, waiting for version 11")
+		}
+		return nil
+	}))
+
+	startup := registry.Startup(context.Background())
+	assert.Equal(t, health.StatusFail, startup.Status)
+	assert.False(t, startup.Started)
+
+	ready := registry.Ready(context.Background())
+	assert.Equal(t, health.StatusFail, ready.Status)
+	assert.False(t, ready.Started)
+
+	migrated.Store(true)
+	startup = registry.Startup(context.Background())
+	assert.Equal(t, health.StatusOK, startup.Status)
+	assert.True(t, startup.Started)
+
+	runs.Store(0)
+	migrated.Store(false)
+	assert.Equal(t, health.StatusOK, registry.Startup(context.Background()).Status)
+	assert.Equal(t, health.StatusOK, registry.Ready(context.Background()).Status)
+	assert.Zero(t, runs.Load())
+}
+
+// TestRegistry_Drain_ShouldFailTheReadiness tests that the instance is not ready once it
+// drains, but still alive.
+func TestRegistry_Drain_ShouldFailTheReadiness(t *testing.T) {
+	registry := health.NewRegistry(time.Second)
+	registry.Register("postgres", passing)
+	require.True(t, registry.Ready(context.Background()).Healthy())
+
+	registry.Drain()
+
+	ready := registry.Ready(context.Background())
+	assert.Equal(t, health.StatusFail, ready.Status)
+	assert.True(t, ready.Draining)
+	assert.Equal(t, health.StatusFail, registry.Details(context.Background()).Status)
+	assert.Equal(t, health.StatusOK, registry.Live().Status)
+}
+
+// TestRegistry_Details_ShouldRunEveryCheck tests that the detailed status reports the
+// startup checks next to the readiness checks.
+func TestRegistry_Details_ShouldRunEveryCheck(t *testing.T) {
+	registry := health.NewRegistry(time.Second)
+	registry.Register("postgres", passing)
+	registry.Register("job:outbox", failing, health.Optional())
+	registry.RegisterStartup("migrations", passing)
+
+	report := registry.Details(context.Background())
+
+	assert.Equal(t, health.StatusDegraded, report.Status)
+	assert.True(t, report.Started)
+	names := make([]string, len(report.Checks))
+	for i, check := range report.Checks {
+		names[i] = check.Name
+	}
+	assert.Equal(t, []string{"job:outbox", "migrations", "postgres"}, names)
+}
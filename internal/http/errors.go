@@ -0,0 +1,277 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"task-manager/internal/auth"
+	"task-manager/internal/authz"
+	"task-manager/internal/repository/postgres"
+	"task-manager/internal/service"
+	"task-manager/internal/stream"
+	"task-manager/internal/tenant"
+	"task-manager/pkg/logger"
+	"task-manager/pkg/rest"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// ProblemTypePrefix prefixes the code of an APIError in the type of its problem details.
+const ProblemTypePrefix = "urn:task-manager:problem:"
+
+// ValidationError is the message of the standard responses of invalid requests.
+const ValidationError = "validation error"
+
+// APIError is an entry of the error catalog: the HTTP status, title and stable code
+// of a kind of failure. Handlers report errors with c.Error and ErrorMiddleware
+// writes them, mapping the service errors to their APIError (see apiErrorOf).
+type APIError struct {
+	Code    string // Machine readable code, clients match on it rather than on messages
+	Status  int
+	Title   string
+	Message string // Message of the StandardResponse, kept for the existing clients
+}
+
+// Error returns the title.
+func (e *APIError) Error() string {
+	return e.Title
+}
+
+// Wrap returns an error of the catalog entry, whose detail is cause.
+func (e *APIError) Wrap(cause error) error {
+	return &causedError{apiErr: e, cause: cause}
+}
+
+// causedError is an APIError with the cause of the occurrence.
+type causedError struct {
+	apiErr *APIError
+	cause  error
+}
+
+func (e *causedError) Error() string {
+	return e.cause.Error()
+}
+
+func (e *causedError) Unwrap() []error {
+	return []error{e.apiErr, e.cause}
+}
+
+// -------------------------------
+// Error catalog
+// -------------------------------
+
+// Codes are part of the API: they are documented in the README and never change.
+var (
+	ErrMalformedBody         = &APIError{"MALFORMED_BODY", http.StatusBadRequest, "Malformed request body", ValidationError}
+	ErrValidationFailed      = &APIError{"VALIDATION_FAILED", http.StatusBadRequest, "Request validation failed", ValidationError}
+	ErrInvalidID             = &APIError{"INVALID_ID", http.StatusBadRequest, "Invalid ID", ValidationError}
+	ErrInvalidTaskStatus     = &APIError{"INVALID_TASK_STATUS", http.StatusBadRequest, InvalidTaskStatus, ValidationError}
+	ErrInvalidFilter         = &APIError{"INVALID_FILTER", http.StatusBadRequest, "Invalid filter", ValidationError}
+	ErrInvalidTenant         = &APIError{"INVALID_TENANT", http.StatusBadRequest, "Invalid tenant", ValidationError}
+	ErrInvalidIdempotencyKey = &APIError{"INVALID_IDEMPOTENCY_KEY", http.StatusBadRequest, "Invalid Idempotency-Key", ValidationError}
+
+	ErrUnauthenticated = &APIError{"UNAUTHENTICATED", http.StatusUnauthorized, "Authentication required", Unauthorized}
+	ErrForbidden       = &APIError{"FORBIDDEN", http.StatusForbidden, "Operation not allowed", Forbidden}
+
+	ErrRouteNotFound           = &APIError{"ROUTE_NOT_FOUND", http.StatusNotFound, "Route not found", rest.NotFound.Message}
+	ErrTaskNotFound            = &APIError{"TASK_NOT_FOUND", http.StatusNotFound, "Task not found", rest.NotFound.Message}
+	ErrTaskMemberNotFound      = &APIError{"TASK_MEMBER_NOT_FOUND", http.StatusNotFound, "Task member not found", rest.NotFound.Message}
+	ErrUserNotFound            = &APIError{"USER_NOT_FOUND", http.StatusNotFound, "User not found", rest.NotFound.Message}
+	ErrWebhookNotFound         = &APIError{"WEBHOOK_NOT_FOUND", http.StatusNotFound, "Webhook not found", rest.NotFound.Message}
+	ErrWebhookDeliveryNotFound = &APIError{"WEBHOOK_DELIVERY_NOT_FOUND", http.StatusNotFound, "Webhook delivery not found", rest.NotFound.Message}
+	ErrAPIKeyNotFound          = &APIError{"API_KEY_NOT_FOUND", http.StatusNotFound, "API key not found", rest.NotFound.Message}
+
+	ErrPrimaryAssignee        = &APIError{"PRIMARY_ASSIGNEE", http.StatusConflict, "User is the primary assignee", service.ErrPrimaryAssignee.Error()}
+	ErrUserEmailExists        = &APIError{"USER_EMAIL_EXISTS", http.StatusConflict, UserEmailExists, UserEmailExists}
+	ErrUserInUse              = &APIError{"USER_IN_USE", http.StatusConflict, UserInUse, UserInUse}
+	ErrAPIKeyRevoked          = &APIError{"API_KEY_REVOKED", http.StatusConflict, "API key is revoked", ValidationError}
+	ErrIdempotencyKeyInFlight = &APIError{"IDEMPOTENCY_KEY_IN_FLIGHT", http.StatusConflict, "Idempotency-Key in use", IdempotencyKeyInFlight}
+
+	ErrAssigneeNotFound        = &APIError{"ASSIGNEE_NOT_FOUND", http.StatusUnprocessableEntity, "Assignee not found", ValidationError}
+	ErrAssigneeInactive        = &APIError{"ASSIGNEE_INACTIVE", http.StatusUnprocessableEntity, "Assignee is deactivated", ValidationError}
+	ErrWatcherNotFound         = &APIError{"WATCHER_NOT_FOUND", http.StatusUnprocessableEntity, "Watcher not found", ValidationError}
+	ErrInvalidRecurrenceRule   = &APIError{"INVALID_RECURRENCE_RULE", http.StatusUnprocessableEntity, "Invalid recurrence rule", ValidationError}
+	ErrRecurrenceRequiresDueAt = &APIError{"RECURRENCE_REQUIRES_DUE_AT", http.StatusUnprocessableEntity, "Recurring tasks require a due date", ValidationError}
+	ErrInvalidWebhookURL       = &APIError{"INVALID_WEBHOOK_URL", http.StatusUnprocessableEntity, "Invalid webhook URL", ValidationError}
+	ErrInvalidWebhookEvent     = &APIError{"INVALID_WEBHOOK_EVENT", http.StatusUnprocessableEntity, "Invalid webhook event", ValidationError}
+	ErrInvalidAPIKeyScope      = &APIError{"INVALID_API_KEY_SCOPE", http.StatusUnprocessableEntity, "Invalid API key scope", ValidationError}
+	ErrInvalidAPIKeyExpiry     = &APIError{"INVALID_API_KEY_EXPIRY", http.StatusUnprocessableEntity, "Invalid API key expiry", ValidationError}
+	ErrIdempotencyKeyReused    = &APIError{"IDEMPOTENCY_KEY_REUSED", http.StatusUnprocessableEntity, "Idempotency-Key reused", IdempotencyKeyReused}
+
+	ErrBodyTooLarge = &APIError{"BODY_TOO_LARGE", http.StatusRequestEntityTooLarge, "Request body too large", BodyTooLarge}
+
+	ErrRateLimited = &APIError{"RATE_LIMITED", http.StatusTooManyRequests, "Rate limit exceeded", TooManyRequests}
+
+	ErrInternal = &APIError{"INTERNAL", http.StatusInternalServerError, "Internal server error", rest.InternalServerError.Message}
+)
+
+// serviceErrors maps the errors of the services and repositories to the catalog.
+var serviceErrors = []struct {
+	err    error
+	apiErr *APIError
+}{
+	{postgres.ErrTaskNotFound, ErrTaskNotFound},
+	{postgres.ErrTaskMemberNotFound, ErrTaskMemberNotFound},
+	{postgres.ErrUserNotFound, ErrUserNotFound},
+	{postgres.ErrWebhookNotFound, ErrWebhookNotFound},
+	{postgres.ErrWebhookDeliveryNotFound, ErrWebhookDeliveryNotFound},
+	{postgres.ErrAPIKeyNotFound, ErrAPIKeyNotFound},
+	{postgres.ErrUserEmailExists, ErrUserEmailExists},
+	{postgres.ErrUserInUse, ErrUserInUse},
+	{authz.ErrForbidden, ErrForbidden},
+	{tenant.ErrForeignTenant, ErrForbidden},
+	{tenant.ErrInvalidTenant, ErrInvalidTenant},
+	{auth.ErrUnauthenticated, ErrUnauthenticated},
+	{auth.ErrInvalidCredentials, ErrUnauthenticated},
+	{stream.ErrInvalidFilter, ErrInvalidFilter},
+	{stream.ErrForbidden, ErrForbidden},
+	{service.ErrPrimaryAssignee, ErrPrimaryAssignee},
+	{service.ErrAPIKeyRevoked, ErrAPIKeyRevoked},
+	{service.ErrAssigneeNotFound, ErrAssigneeNotFound},
+	{service.ErrAssigneeInactive, ErrAssigneeInactive},
+	{service.ErrWatcherNotFound, ErrWatcherNotFound},
+	{service.ErrInvalidRecurrenceRule, ErrInvalidRecurrenceRule},
+	{service.ErrRecurrenceRequiresDueAt, ErrRecurrenceRequiresDueAt},
+	{service.ErrInvalidWebhookURL, ErrInvalidWebhookURL},
+	{service.ErrInvalidWebhookEvent, ErrInvalidWebhookEvent},
+	{service.ErrInvalidAPIKeyScope, ErrInvalidAPIKeyScope},
+	{service.ErrInvalidAPIKeyExpiry, ErrInvalidAPIKeyExpiry},
+}
+
+// apiErrorOf returns the catalog entry of err: the APIError it wraps, the entry of the
+// service error it wraps, ErrInternal otherwise.
+func apiErrorOf(err error) *APIError {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr
+	}
+
+	for _, known := range serviceErrors {
+		if errors.Is(err, known.err) {
+			return known.apiErr
+		}
+	}
+
+	return ErrInternal
+}
+
+// bindJSON binds the JSON body of the request to obj. Bodies failing the binding rules
+// are reported as ErrValidationFailed, bodies that can't be decoded as ErrMalformedBody.
+func bindJSON(c *gin.Context, obj any) error {
+	err := c.ShouldBindJSON(obj)
+
+	var ve validator.ValidationErrors
+	switch {
+	case err == nil:
+		return nil
+	case errors.As(err, &ve):
+		return ErrValidationFailed.Wrap(err)
+	default:
+		return ErrMalformedBody.Wrap(err)
+	}
+}
+
+// -------------------------------
+// Error responses
+// -------------------------------
+
+// ErrorMiddleware writes the response of the last error reported by the handler with
+// c.Error and logs it. It must be the last middleware of the routes, so the
+// middlewares before it see the error response.
+func ErrorMiddleware(logger logger.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		last := c.Errors.Last()
+		if last == nil {
+			return
+		}
+
+		apiErr := apiErrorOf(last.Err)
+		logger.ErrorWithContext(c.Request.Context(), LogRequestFailed,
+			LogKeyMethod, c.Request.Method, LogKeyCode, apiErr.Code, LogKeyError, last.Err)
+
+		if !c.Writer.Written() {
+			writeError(c, last.Err)
+		}
+	}
+}
+
+// abortWithError aborts the request with the response of err, for the middlewares
+// running before ErrorMiddleware.
+func abortWithError(c *gin.Context, err error) {
+	_ = c.Error(err)
+	writeError(c, err)
+	c.Abort()
+}
+
+// writeError writes the response of err: problem details to the clients accepting
+// application/problem+json, a StandardResponse with the code otherwise. The detail
+// of internal errors is not exposed.
+func writeError(c *gin.Context, err error) {
+	apiErr := apiErrorOf(err)
+
+	var detail string
+	if err != error(apiErr) && apiErr.Status < http.StatusInternalServerError {
+		detail = err.Error()
+	}
+
+	if c.NegotiateFormat(gin.MIMEJSON, rest.ProblemContentType) == rest.ProblemContentType {
+		// The invalid fields are detailed one by one
+		var ve validator.ValidationErrors
+		var fe rest.FieldErrorer
+		if errors.As(err, &ve) || errors.As(err, &fe) {
+			detail = ""
+		}
+
+		c.Header("Content-Type", rest.ProblemContentType)
+		c.JSON(apiErr.Status, rest.Problem{
+			Type:     ProblemTypePrefix + apiErr.Code,
+			Title:    apiErr.Title,
+			Status:   apiErr.Status,
+			Detail:   detail,
+			Instance: c.Request.URL.Path,
+			Code:     apiErr.Code,
+			TraceID:  TraceIDFromContext(c.Request.Context()),
+			Errors:   rest.GetFieldErrors(err),
+		})
+		return
+	}
+
+	response := rest.GetFailedResponseFromMessage(apiErr.Message)
+	if detail != "" {
+		response = rest.GetFailedValidationResponse(err)
+		response.Message = apiErr.Message
+	}
+	response.Code = apiErr.Code
+	c.JSON(apiErr.Status, response)
+}
+
+// registerFieldNames registers the JSON field names once, the validator is shared.
+var registerFieldNames sync.Once
+
+// registerJSONFieldNames names the fields of the binding errors after their JSON
+// names, as the clients send them.
+func registerJSONFieldNames() {
+	validate, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return
+	}
+
+	// Fields without JSON name keep their Go name
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+}
+
+// LogRequestFailed logs the errors written by ErrorMiddleware, with the method, code
+// and error of the request.
+const LogRequestFailed = "Request failed"
@@ -0,0 +1,221 @@
+package http_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"task-manager/internal/auth"
+	"task-manager/internal/authz"
+	"task-manager/internal/entities"
+	HTTPhandler "task-manager/internal/http"
+	"task-manager/internal/repository/postgres"
+	"task-manager/internal/service"
+	"task-manager/internal/tenant"
+	"task-manager/pkg/rest"
+	"testing"
+)
+
+// TestErrorResponses tests that the handler errors are mapped to the status and code
+// of the error catalog, whatever the handler.
+func TestErrorResponses(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		setup      func(taskService *service.MockTaskService)
+		wantStatus int
+		wantCode   string
+		wantErrors []string
+	}{
+		{
+			name: "malformed body", method: http.MethodPost, path: "/api/tasks/", body: `{"title":`,
+			wantStatus: http.StatusBadRequest, wantCode: "MALFORMED_BODY", wantErrors: []string{"unexpected EOF"},
+		},
+		{
+			name: "invalid task status", method: http.MethodPost, path: "/api/tasks/", body: `{"title":"x","assignee_id":1,"status":"bogus"}`,
+			wantStatus: http.StatusBadRequest, wantCode: "VALIDATION_FAILED", wantErrors: []string{"status must be one of pending, in_progress, done, canceled"},
+		},
+		{
+			name: "body too large", method: http.MethodPost, path: "/api/tasks/", body: `{"title":"` + strings.Repeat("x", HTTPhandler.MaxBodySize) + `"}`,
+			wantStatus: http.StatusRequestEntityTooLarge, wantCode: "BODY_TOO_LARGE", wantErrors: []string{fmt.Sprintf("request body exceeds %d bytes", HTTPhandler.MaxBodySize)},
+		},
+		{
+			name: "invalid task ID on update", method: http.MethodPut, path: "/api/tasks/abc", body: `{"title":"x","status":"done","assignee_id":1}`,
+			wantStatus: http.StatusBadRequest, wantCode: "INVALID_ID", wantErrors: []string{HTTPhandler.InvalidTaskID},
+		},
+		{
+			name: "task not found", method: http.MethodGet, path: "/api/tasks/404",
+			setup: func(taskService *service.MockTaskService) {
+				taskService.On("GetByID", mock.Anything, int64(404)).Return((*entities.Task)(nil), postgres.ErrTaskNotFound)
+			},
+			wantStatus: http.StatusNotFound, wantCode: "TASK_NOT_FOUND", wantErrors: []string{postgres.ErrTaskNotFound.Error()},
+		},
+		{
+			name: "forbidden", method: http.MethodDelete, path: "/api/tasks/1",
+			setup: func(taskService *service.MockTaskService) {
+				taskService.On("Delete", mock.Anything, int64(1)).Return(fmt.Errorf("%w: not yours", authz.ErrForbidden))
+			},
+			wantStatus: http.StatusForbidden, wantCode: "FORBIDDEN", wantErrors: []string{"forbidden: not yours"},
+		},
+		{
+			name: "primary assignee", method: http.MethodDelete, path: "/api/tasks/1/assignees/10",
+			setup: func(taskService *service.MockTaskService) {
+				taskService.On("RemoveAssignee", mock.Anything, int64(1), int64(10)).Return(service.ErrPrimaryAssignee)
+			},
+			wantStatus: http.StatusConflict, wantCode: "PRIMARY_ASSIGNEE", wantErrors: []string{service.ErrPrimaryAssignee.Error()},
+		},
+		{
+			name: "unknown watcher", method: http.MethodPost, path: "/api/tasks/1/watchers", body: `{"user_id":7}`,
+			setup: func(taskService *service.MockTaskService) {
+				taskService.On("AddWatcher", mock.Anything, int64(1), int64(7)).Return((*entities.Task)(nil), fmt.Errorf("user 7: %w", service.ErrWatcherNotFound))
+			},
+			wantStatus: http.StatusUnprocessableEntity, wantCode: "WATCHER_NOT_FOUND", wantErrors: []string{"user 7: watcher not found"},
+		},
+		{
+			name: "internal error", method: http.MethodGet, path: "/api/tasks/1",
+			setup: func(taskService *service.MockTaskService) {
+				taskService.On("GetByID", mock.Anything, int64(1)).Return((*entities.Task)(nil), errUnknown)
+			},
+			wantStatus: http.StatusInternalServerError, wantCode: "INTERNAL",
+		},
+		{
+			name: "unknown route", method: http.MethodGet, path: "/api/unknown",
+			wantStatus: http.StatusNotFound, wantCode: "ROUTE_NOT_FOUND",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			taskService := service.MockTaskService{}
+			if tt.setup != nil {
+				tt.setup(&taskService)
+			}
+			router := HTTPhandler.SetupHandler(&taskService).SetupRouter()
+
+			req := httptest.NewRequest(tt.method, tt.path, bytes.NewBufferString(tt.body))
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			var response rest.StandardResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			assert.Equal(t, rest.Fail, response.Status)
+			assert.Equal(t, tt.wantCode, response.Code)
+			assert.Equal(t, tt.wantErrors, response.Errors)
+			taskService.AssertExpectations(t)
+		})
+	}
+}
+
+// TestErrorResponses_AcceptProblem_ShouldReturnProblemDetails tests that clients
+// accepting application/problem+json get problem details, with the invalid fields.
+func TestErrorResponses_AcceptProblem_ShouldReturnProblemDetails(t *testing.T) {
+	router := HTTPhandler.SetupHandler(&service.MockTaskService{}).SetupRouter()
+
+	body := `{"title":"","status":"bogus","assignee_id":1}`
+	req := httptest.NewRequest(http.MethodPut, "/api/tasks/12", bytes.NewBufferString(body))
+	req.Header.Set("Accept", rest.ProblemContentType)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var problem rest.Problem
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, rest.ProblemContentType, w.Header().Get("Content-Type"))
+	assert.Equal(t, HTTPhandler.ProblemTypePrefix+"VALIDATION_FAILED", problem.Type)
+	assert.Equal(t, HTTPhandler.ErrValidationFailed.Title, problem.Title)
+	assert.Equal(t, http.StatusBadRequest, problem.Status)
+	assert.Equal(t, "VALIDATION_FAILED", problem.Code)
+	assert.Equal(t, "/api/tasks/12", problem.Instance)
+	assert.NotEmpty(t, problem.TraceID)
+	assert.Equal(t, []rest.FieldError{
+		{Field: "status", Pointer: "/status", Rule: "enum", Param: "pending in_progress done canceled", Message: "must be one of pending, in_progress, done, canceled"},
+		{Field: "title", Pointer: "/title", Rule: "minLength", Param: "1", Message: "length must be at least 1"},
+	}, problem.Errors)
+}
+
+// TestErrorResponses_AcceptProblem_MalformedField_ShouldReturnField tests that values
+// of the wrong JSON type are reported as invalid fields, with their JSON pointer.
+func TestErrorResponses_AcceptProblem_MalformedField_ShouldReturnField(t *testing.T) {
+	router := HTTPhandler.SetupHandler(&service.MockTaskService{}).SetupRouter()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/tasks/", bytes.NewBufferString(`{"title":"x","assignee_id":"ten"}`))
+	req.Header.Set("Accept", "application/problem+json, application/json;q=0.5")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var problem rest.Problem
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, "VALIDATION_FAILED", problem.Code)
+	require.Len(t, problem.Errors, 1)
+	assert.Equal(t, "assignee_id", problem.Errors[0].Field)
+	assert.Equal(t, "/assignee_id", problem.Errors[0].Pointer)
+	assert.Equal(t, "type", problem.Errors[0].Rule)
+}
+
+// TestErrorResponses_Middleware_ShouldNegotiate tests that the errors of the
+// middlewares are negotiated like the errors of the handlers.
+func TestErrorResponses_Middleware_ShouldNegotiate(t *testing.T) {
+	handler := HTTPhandler.SetupHandler(&service.MockTaskService{})
+	handler.Authenticators = []auth.Authenticator{tokenAuthenticator{}}
+	router := handler.SetupRouter()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/tasks/", nil)
+	req.Header.Set("Accept", rest.ProblemContentType)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var problem rest.Problem
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Equal(t, "UNAUTHENTICATED", problem.Code)
+	assert.Equal(t, auth.ErrUnauthenticated.Error(), problem.Detail)
+	assert.NotEmpty(t, w.Header().Get("WWW-Authenticate"))
+}
+
+// TestErrorMiddleware_ShouldLogStructuredAttributes tests that the logs of a request
+// carry its trace, route and tenant, and the failures their method, code and error.
+func TestErrorMiddleware_ShouldLogStructuredAttributes(t *testing.T) {
+	var logs bytes.Buffer
+	taskService := service.MockTaskService{}
+	taskService.On("GetByID", mock.Anything, int64(1)).Return((*entities.Task)(nil), errUnknown)
+	router := HTTPhandler.SetupHandlerWithLogs(&taskService, &logs).SetupRouter()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/tasks/1", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	records := map[string]map[string]any{}
+	for _, line := range bytes.Split(bytes.TrimSpace(logs.Bytes()), []byte("\n")) {
+		var record map[string]any
+		require.NoError(t, json.Unmarshal(line, &record))
+		records[record["msg"].(string)] = record
+	}
+
+	traceID := w.Header().Get("X-Trace-ID")
+	require.NotEmpty(t, traceID)
+	for _, msg := range []string{HTTPhandler.LogIncomingTaskFetch, HTTPhandler.LogRequestFailed} {
+		require.Contains(t, records, msg)
+		assert.Equal(t, traceID, records[msg]["trace_id"])
+		assert.Equal(t, "GET /api/tasks/:id", records[msg]["route"])
+		assert.Equal(t, tenant.Default, records[msg]["tenant"])
+	}
+
+	failed := records[HTTPhandler.LogRequestFailed]
+	assert.Equal(t, "ERROR", failed["level"])
+	assert.Equal(t, http.MethodGet, failed["method"])
+	assert.Equal(t, "INTERNAL", failed["code"])
+	assert.Equal(t, errUnknown.Error(), failed["error"])
+}
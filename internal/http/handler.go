@@ -4,13 +4,25 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"sync"
+	"task-manager/docs/schema"
+	"task-manager/internal/auth"
+	"task-manager/internal/authz"
 	"task-manager/internal/config"
+	"task-manager/internal/gql"
+	"task-manager/internal/health"
+	"task-manager/internal/idempotency"
+	"task-manager/internal/ratelimit"
 	"task-manager/internal/service"
+	"task-manager/internal/stream"
+	"task-manager/internal/validation"
+	"task-manager/internal/version"
 	"task-manager/pkg/logger"
 	"task-manager/pkg/monitoring"
 	"time"
 
 	"github.com/cockroachdb/errors"
+	"github.com/gin-gonic/gin"
 )
 
 // -------------------------------
@@ -37,22 +49,38 @@ const WriteTimeout = 15 * time.Second
 // Files larger than this limit will be stored on disk to prevent excessive RAM usage.
 const MaxMultipartMemory = 8 << 20 // 8 MiB
 
+// MaxBodySize is the maximum size of the request bodies read by the middlewares, e.g.
+// to fingerprint an idempotent request. Larger bodies are rejected with 413.
+const MaxBodySize = 1 << 20 // 1 MiB
+
 // -------------------------------
 // Handler Struct
 // -------------------------------
 
 // Handler contains HTTP server, services, logger, metrics, and version info.
 type Handler struct {
-	TaskService service.TaskService
-	logger      logger.Logger
-	HTTPServer  *http.Server
-
-	VersionInfo struct {
-		GitCommit     string
-		BuildTime     string
-		StartTime     time.Time
-		ContainerName string
-	}
+	TaskService    service.TaskService
+	UserService    service.UserService
+	WebhookService service.WebhookService
+	APIKeyService  service.APIKeyService
+	TaskEvents     *stream.Hub
+	Authorizer     stream.Authorizer    // Authorizes the task socket and GraphQL subscriptions, task:read of Policy by default
+	Policy         authz.Authorizer     // Authorizes the user and webhook writes, authz.DefaultPolicy by default
+	Authenticators []auth.Authenticator // Authenticate the API routes, which are open if empty
+	RateLimiter    ratelimit.Limiter    // Limits the requests of every client to RateLimits, unlimited if nil
+	RateLimits     ratelimit.Rules
+	IPRateLimit    ratelimit.Limit      // Limits the requests of every IP address before the authentication, with RateLimiter
+	Idempotency    idempotency.Store    // Stores the responses of the requests with an Idempotency-Key, ignored if nil
+	IdempotencyTTL time.Duration        // How long the responses are replayed, idempotency.DefaultTTL if zero
+	Schemas        *validation.Registry // Validates the request bodies, not validated if nil
+	APIVersions    []APIVersion         // Versions of the REST API, the legacy routes serve LegacyAPIVersion
+	Health         *health.Registry     // Checks of the health probes, registered by the components
+	GraphQLServer  *gql.Server
+	logger         logger.Logger
+	HTTPServer     *http.Server
+	sockets        sync.WaitGroup // Open task sockets, waited for on Stop
+
+	VersionInfo version.Info // Build of the instance, served by GET /version
 
 	config      config.Config
 	TaskMetrics *monitoring.TaskMetrics
@@ -67,14 +95,90 @@ func CreateHandler(
 	logger logger.Logger,
 	config config.Config,
 	TaskService service.TaskService,
+	UserService service.UserService,
+	WebhookService service.WebhookService,
+	APIKeyService service.APIKeyService,
+	TaskEvents *stream.Hub,
 	TaskMetrics *monitoring.TaskMetrics,
 ) *Handler {
-	return &Handler{
-		logger:      logger,
-		config:      config,
-		TaskService: TaskService,
-		TaskMetrics: TaskMetrics,
+	policy := authz.DefaultPolicy()
+	h := &Handler{
+		logger:         logger,
+		config:         config,
+		TaskService:    TaskService,
+		UserService:    UserService,
+		WebhookService: WebhookService,
+		APIKeyService:  APIKeyService,
+		TaskEvents:     TaskEvents,
+		Authorizer:     stream.PolicyAuthorizer(policy),
+		Policy:         policy,
+		TaskMetrics:    TaskMetrics,
+		Schemas:        validation.MustLoad(schema.FS),
+		APIVersions:    DefaultAPIVersions(config.API),
+		Health:         health.NewRegistry(config.Health.CheckTimeout),
+		VersionInfo:    version.New("", "", ""),
+	}
+
+	// Defer to the Authorizer of the handler, which may be replaced after creation
+	authorizer := stream.AuthorizerFunc(func(ctx context.Context, filter stream.Filter) error {
+		return h.Authorizer.Authorize(ctx, filter)
+	})
+	h.GraphQLServer = gql.CreateServer(logger, config.GraphQL, TaskService, UserService, TaskEvents, authorizer)
+
+	return h
+}
+
+// authentication returns the authentication middleware of the API routes, a no-op
+// if no authenticator is set.
+func (h *Handler) authentication() gin.HandlerFunc {
+	if len(h.Authenticators) == 0 {
+		// Without authentication every caller acts on behalf of the deployment
+		return func(c *gin.Context) {
+			c.Request = c.Request.WithContext(authz.WithInternalCaller(c.Request.Context()))
+			c.Next()
+		}
+	}
+	return AuthenticationMiddleware(h.Authenticators...)
+}
+
+// idempotency returns the idempotency middleware of the API routes, a no-op if no
+// store is set.
+func (h *Handler) idempotency() gin.HandlerFunc {
+	if h.Idempotency == nil {
+		return func(c *gin.Context) { c.Next() }
+	}
+	ttl := h.IdempotencyTTL
+	if ttl <= 0 {
+		ttl = idempotency.DefaultTTL
 	}
+	return IdempotencyMiddleware(h.Idempotency, ttl, h.logger)
+}
+
+// validated returns the middleware validating the request bodies against the named
+// schema of the API version, a no-op if no schema is set.
+func (h *Handler) validated(version, name string) gin.HandlerFunc {
+	if h.Schemas == nil {
+		return func(c *gin.Context) { c.Next() }
+	}
+	return SchemaMiddleware(h.Schemas, version, name)
+}
+
+// rateLimit returns the rate limiting middleware of the API routes, a no-op if no
+// limiter is set.
+func (h *Handler) rateLimit() gin.HandlerFunc {
+	if h.RateLimiter == nil {
+		return func(c *gin.Context) { c.Next() }
+	}
+	return RateLimitMiddleware(h.RateLimiter, h.RateLimits, h.logger)
+}
+
+// ipRateLimit returns the middleware limiting the requests of every IP address before
+// their authentication, a no-op if no limiter or IP limit is set.
+func (h *Handler) ipRateLimit() gin.HandlerFunc {
+	if h.RateLimiter == nil || !h.IPRateLimit.Enabled() {
+		return func(c *gin.Context) { c.Next() }
+	}
+	return IPRateLimitMiddleware(h.RateLimiter, h.IPRateLimit, h.logger)
 }
 
 // -------------------------------
@@ -94,6 +198,9 @@ func (h *Handler) StartBlocking(ctx context.Context, defaultPort int) {
 		IdleTimeout:  IdleTimeout,
 	}
 
+	// End the open task streams and sockets, Shutdown does not wait for hijacked or streaming connections otherwise
+	h.HTTPServer.RegisterOnShutdown(h.TaskEvents.Close)
+
 	h.logger.InfoF("[OK] Starting HTTP REST Server on %s", addr)
 	err := h.HTTPServer.ListenAndServe()
 	if !errors.Is(err, http.ErrServerClosed) {
@@ -105,7 +212,8 @@ func (h *Handler) StartBlocking(ctx context.Context, defaultPort int) {
 }
 
 // Stop gracefully shuts down the HTTP server within DefaultTimeOutForGracefulShutDown.
-// Any in-flight requests will be given up to 5 seconds to complete.
+// Any in-flight requests will be given up to 5 seconds to complete, the task sockets
+// the same time to send their close frame.
 func (h *Handler) Stop() {
 	ctxTimeout, cancelTimeout := context.WithTimeout(context.Background(), DefaultTimeOutForGracefulShutDown)
 	defer cancelTimeout()
@@ -115,5 +223,17 @@ func (h *Handler) Stop() {
 		h.logger.Error(err.Error())
 	}
 
+	// Hijacked connections are not tracked by Shutdown
+	closed := make(chan struct{})
+	go func() {
+		h.sockets.Wait()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+	case <-ctxTimeout.Done():
+		h.logger.Error("task sockets did not close in time")
+	}
+
 	h.logger.Info("[OK] HTTP REST Server graceful shutdown completed")
 }
@@ -0,0 +1,263 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"task-manager/internal/entities"
+	"task-manager/pkg/rest"
+	"time"
+)
+
+// APIKeyCreate issues a new API key.
+//
+// @Summary Issue an API key
+// @Description Issues a key for service-to-service calls, sent as `Authorization: ApiKey <key>`.
+// @Description The key is only returned by this endpoint and the rotation, only its hash is stored. Requires the admin role.
+// @Tags API Keys
+// @Accept json
+// @Produce json
+// @Param request body CreateAPIKeyRequest true "API key payload"
+// @Success 201 {object} rest.StandardResponse{data=APIKeyResponse} "API key successfully issued"
+// @Failure 400 {object} rest.StandardResponse{data=nil} "Invalid request payload"
+// @Failure 403 {object} rest.StandardResponse{data=nil} "Admin role required"
+// @Failure 422 {object} rest.StandardResponse{data=nil} "Unknown scope or expiry in the past"
+// @Failure 500 {object} rest.StandardResponse{data=nil} "Internal server error"
+// @Router /api/admin/api-keys/ [post]
+func (h *Handler) APIKeyCreate(c *gin.Context) {
+	h.logger.InfoWithContext(c.Request.Context(), LogIncomingAPIKeyCreate)
+
+	var req CreateAPIKeyRequest
+	if err := bindJSON(c, &req); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	created, key, err := h.APIKeyService.Issue(c, &entities.APIKey{
+		Name:      req.Name,
+		Scopes:    req.Scopes,
+		ExpiresAt: req.ExpiresAt,
+	})
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	h.logger.InfoWithContext(c.Request.Context(), LogAPIKeyCreateSuccess, LogKeyAPIKeyID, created.ID)
+
+	response := newAPIKeyResponse(created)
+	response.Key = key
+
+	c.JSON(http.StatusCreated, rest.GetSuccessResponse(response))
+}
+
+// APIKeyGetByID retrieves an API key by its ID.
+//
+// @Summary Get API key by ID
+// @Description Retrieves an API key, without its secret. Requires the admin role.
+// @Tags API Keys
+// @Accept json
+// @Produce json
+// @Param id path int true "API key ID"
+// @Success 200 {object} rest.StandardResponse{data=APIKeyResponse} "API key successfully fetched"
+// @Failure 400 {object} rest.StandardResponse{data=nil} "Invalid API key ID"
+// @Failure 403 {object} rest.StandardResponse{data=nil} "Admin role required"
+// @Failure 404 {object} rest.StandardResponse{data=nil} "API key not found"
+// @Failure 500 {object} rest.StandardResponse{data=nil} "Internal server error"
+// @Router /api/admin/api-keys/{id} [get]
+func (h *Handler) APIKeyGetByID(c *gin.Context) {
+	h.logger.InfoWithContext(c.Request.Context(), LogIncomingAPIKeyFetch)
+
+	keyID, err := pathID(c, ID, InvalidAPIKeyID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	key, err := h.APIKeyService.Get(c, keyID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	h.logger.InfoWithContext(c.Request.Context(), LogAPIKeyFetchSuccess, LogKeyAPIKeyID, keyID)
+
+	c.JSON(http.StatusOK, rest.GetSuccessResponse(newAPIKeyResponse(key)))
+}
+
+// APIKeyList retrieves a paginated list of API keys.
+//
+// @Summary List API keys
+// @Description Retrieves a paginated list of API keys, revoked ones included. Secrets are not returned. Requires the admin role.
+// @Tags API Keys
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param per_page query int false "Number of API keys per page" default(20)
+// @Param revoked query bool false "Filter by revocation"
+// @Success 200 {object} rest.StandardResponse{data=[]APIKeyResponse, meta=rest.PaginationMeta} "List of API keys successfully fetched"
+// @Failure 403 {object} rest.StandardResponse{data=nil} "Admin role required"
+// @Failure 500 {object} rest.StandardResponse{data=nil} "Internal server error"
+// @Router /api/admin/api-keys [get]
+func (h *Handler) APIKeyList(c *gin.Context) {
+	h.logger.InfoWithContext(c.Request.Context(), LogIncomingAPIKeyFetch)
+
+	query := rest.ParseQuery(c)
+
+	keys, total, err := h.APIKeyService.List(c, query)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	h.logger.InfoWithContext(c.Request.Context(), LogAPIKeyFetchSuccess, LogKeyCount, len(keys))
+
+	response := make([]APIKeyResponse, 0, len(keys))
+	for i := range keys {
+		response = append(response, newAPIKeyResponse(&keys[i]))
+	}
+
+	c.JSON(http.StatusOK, rest.GetSuccessResponseWithMeta(response, rest.PaginationMeta{
+		Total:   total,
+		Page:    query.Page,
+		PerPage: query.PerPage,
+	}))
+}
+
+// APIKeyRevoke revokes an API key.
+//
+// @Summary Revoke an API key
+// @Description Revokes an API key for good. The key is kept, with its revocation time, for auditing. Requires the admin role.
+// @Tags API Keys
+// @Accept json
+// @Produce json
+// @Param id path int true "API key ID"
+// @Success 200 {object} rest.StandardResponse{data=APIKeyResponse} "API key successfully revoked"
+// @Failure 400 {object} rest.StandardResponse{data=nil} "Invalid API key ID"
+// @Failure 403 {object} rest.StandardResponse{data=nil} "Admin role required"
+// @Failure 404 {object} rest.StandardResponse{data=nil} "API key not found"
+// @Failure 500 {object} rest.StandardResponse{data=nil} "Internal server error"
+// @Router /api/admin/api-keys/{id} [delete]
+func (h *Handler) APIKeyRevoke(c *gin.Context) {
+	h.logger.InfoWithContext(c.Request.Context(), LogIncomingAPIKeyRevoke)
+
+	keyID, err := pathID(c, ID, InvalidAPIKeyID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	revoked, err := h.APIKeyService.Revoke(c, keyID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	h.logger.InfoWithContext(c.Request.Context(), LogAPIKeyRevokeSuccess, LogKeyAPIKeyID, keyID)
+
+	c.JSON(http.StatusOK, rest.GetSuccessResponse(newAPIKeyResponse(revoked)))
+}
+
+// APIKeyRotate replaces the secret of an API key.
+//
+// @Summary Rotate an API key
+// @Description Issues a new key in place of an active one, keeping its name, scopes and expiry.
+// @Description The previous key stops working. The new key is only returned by this endpoint. Requires the admin role.
+// @Tags API Keys
+// @Accept json
+// @Produce json
+// @Param id path int true "API key ID"
+// @Success 200 {object} rest.StandardResponse{data=APIKeyResponse} "API key successfully rotated"
+// @Failure 400 {object} rest.StandardResponse{data=nil} "Invalid API key ID"
+// @Failure 403 {object} rest.StandardResponse{data=nil} "Admin role required"
+// @Failure 404 {object} rest.StandardResponse{data=nil} "API key not found"
+// @Failure 409 {object} rest.StandardResponse{data=nil} "API key is revoked"
+// @Failure 500 {object} rest.StandardResponse{data=nil} "Internal server error"
+// @Router /api/admin/api-keys/{id}/rotate [post]
+func (h *Handler) APIKeyRotate(c *gin.Context) {
+	h.logger.InfoWithContext(c.Request.Context(), LogIncomingAPIKeyRotate)
+
+	keyID, err := pathID(c, ID, InvalidAPIKeyID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	rotated, key, err := h.APIKeyService.Rotate(c, keyID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	h.logger.InfoWithContext(c.Request.Context(), LogAPIKeyRotateSuccess, LogKeyAPIKeyID, keyID)
+
+	response := newAPIKeyResponse(rotated)
+	response.Key = key
+
+	c.JSON(http.StatusOK, rest.GetSuccessResponse(response))
+}
+
+// newAPIKeyResponse maps an API key to its API representation, without its secret.
+func newAPIKeyResponse(key *entities.APIKey) APIKeyResponse {
+	scopes := key.Scopes
+	if scopes == nil {
+		scopes = []string{}
+	}
+
+	response := APIKeyResponse{
+		ID:        key.ID,
+		Name:      key.Name,
+		Prefix:    key.Prefix,
+		Scopes:    scopes,
+		Active:    key.Active(time.Now()),
+		CreatedAt: key.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: key.UpdatedAt.Format(time.RFC3339),
+	}
+
+	if key.ExpiresAt != nil {
+		response.ExpiresAt = key.ExpiresAt.Format(time.RFC3339)
+	}
+	if key.LastUsedAt != nil {
+		response.LastUsedAt = key.LastUsedAt.Format(time.RFC3339)
+	}
+	if key.RevokedAt != nil {
+		response.RevokedAt = key.RevokedAt.Format(time.RFC3339)
+	}
+
+	return response
+}
+
+const (
+	LogIncomingAPIKeyCreate = "Incoming API key create request"
+	LogAPIKeyCreateSuccess  = "API key created successfully"
+
+	LogIncomingAPIKeyFetch = "Incoming API key fetch request"
+	LogAPIKeyFetchSuccess  = "API key fetch successfully"
+
+	LogIncomingAPIKeyRevoke = "Incoming API key revoke request"
+	LogAPIKeyRevokeSuccess  = "API key revoked successfully"
+
+	LogIncomingAPIKeyRotate = "Incoming API key rotate request"
+	LogAPIKeyRotateSuccess  = "API key rotated successfully"
+
+	InvalidAPIKeyID = "Invalid API key ID"
+)
+
+type CreateAPIKeyRequest struct {
+	Name      string     `json:"name" binding:"required"`
+	Scopes    []string   `json:"scopes" binding:"required"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"` // optional, RFC 3339, never expires when absent
+}
+
+type APIKeyResponse struct {
+	ID         int64    `json:"id"`
+	Name       string   `json:"name"`
+	Prefix     string   `json:"prefix"`
+	Key        string   `json:"key,omitempty"` // only returned on creation and rotation
+	Scopes     []string `json:"scopes"`
+	Active     bool     `json:"active"` // neither revoked nor expired
+	ExpiresAt  string   `json:"expires_at,omitempty"`
+	LastUsedAt string   `json:"last_used_at,omitempty"`
+	RevokedAt  string   `json:"revoked_at,omitempty"`
+	CreatedAt  string   `json:"created_at"`
+	UpdatedAt  string   `json:"updated_at"`
+}
@@ -0,0 +1,160 @@
+package http_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"task-manager/internal/auth"
+	"task-manager/internal/entities"
+	HTTPhandler "task-manager/internal/http"
+	"task-manager/internal/service"
+	"task-manager/pkg/rest"
+	"testing"
+	"time"
+)
+
+var stubAPIKey = entities.APIKey{
+	ID:        5,
+	Name:      "backup",
+	Prefix:    "0a1b2c3d4e5f",
+	Scopes:    []string{auth.ScopeTasksRead},
+	CreatedAt: time.Now(),
+	UpdatedAt: time.Now(),
+}
+
+// TestAPIKeyCreate_Success_ShouldReturnKeyOnce tests that the full key is only returned on creation.
+func TestAPIKeyCreate_Success_ShouldReturnKeyOnce(t *testing.T) {
+	apiKeyService := service.MockAPIKeyService{}
+
+	apiKeyService.On(
+		"Issue",
+		mock.Anything,
+		mock.MatchedBy(func(k *entities.APIKey) bool { return k.Name == "backup" && len(k.Scopes) == 1 }),
+	).Return(&stubAPIKey, "tm_0a1b2c3d4e5f_s3cret", nil)
+	apiKeyService.On("Get", mock.Anything, stubAPIKey.ID).Return(&stubAPIKey, nil)
+
+	router := HTTPhandler.SetupHandlerWithAPIKeys(&apiKeyService).SetupRouter()
+
+	jsonBytes, _ := json.Marshal(HTTPhandler.CreateAPIKeyRequest{Name: "backup", Scopes: []string{auth.ScopeTasksRead}})
+	req, _ := http.NewRequest(http.MethodPost, "/api/admin/api-keys/", bytes.NewBuffer(jsonBytes))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var res rest.StandardResponse
+	_ = json.Unmarshal(w.Body.Bytes(), &res)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "tm_0a1b2c3d4e5f_s3cret", res.Data.(map[string]interface{})["key"])
+	assert.Equal(t, true, res.Data.(map[string]interface{})["active"])
+
+	req, _ = http.NewRequest(http.MethodGet, "/api/admin/api-keys/5", nil)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	res = rest.StandardResponse{}
+	_ = json.Unmarshal(w.Body.Bytes(), &res)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, res.Data.(map[string]interface{}), "key")
+
+	apiKeyService.AssertExpectations(t)
+}
+
+// TestAPIKeyCreate_UnknownScope_ShouldReturnUnprocessableEntity tests an unknown scope.
+func TestAPIKeyCreate_UnknownScope_ShouldReturnUnprocessableEntity(t *testing.T) {
+	apiKeyService := service.MockAPIKeyService{}
+
+	apiKeyService.On("Issue", mock.Anything, mock.Anything).
+		Return((*entities.APIKey)(nil), "", errors.Wrap(service.ErrInvalidAPIKeyScope, `unknown scope "tasks:delete"`))
+
+	router := HTTPhandler.SetupHandlerWithAPIKeys(&apiKeyService).SetupRouter()
+
+	jsonBytes, _ := json.Marshal(HTTPhandler.CreateAPIKeyRequest{Name: "backup", Scopes: []string{"tasks:delete"}})
+	req, _ := http.NewRequest(http.MethodPost, "/api/admin/api-keys/", bytes.NewBuffer(jsonBytes))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+}
+
+// TestAPIKeyRotate_Revoked_ShouldReturnConflict tests that revoked keys can't be rotated.
+func TestAPIKeyRotate_Revoked_ShouldReturnConflict(t *testing.T) {
+	apiKeyService := service.MockAPIKeyService{}
+
+	apiKeyService.On("Rotate", mock.Anything, stubAPIKey.ID).Return((*entities.APIKey)(nil), "", service.ErrAPIKeyRevoked)
+
+	router := HTTPhandler.SetupHandlerWithAPIKeys(&apiKeyService).SetupRouter()
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/admin/api-keys/5/rotate", nil)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+// TestAPIKeyAuthentication_ShouldEnforceScopesAndAdminRole tests the requests of an
+// API key limited to tasks:read.
+func TestAPIKeyAuthentication_ShouldEnforceScopesAndAdminRole(t *testing.T) {
+	apiKeyService := service.MockAPIKeyService{}
+	apiKeyService.On("Authenticate", mock.Anything, "tm_0a1b2c3d4e5f_s3cret").Return(&auth.Principal{
+		Subject: "api-key:5", Name: "backup", Scopes: []string{auth.ScopeTasksRead}, Method: auth.MethodAPIKey,
+	}, nil)
+	apiKeyService.On("Authenticate", mock.Anything, mock.Anything).Return(nil, auth.ErrInvalidCredentials)
+
+	taskService := service.MockTaskService{}
+	taskService.On("List", mock.Anything, mock.Anything).Return([]entities.Task{}, 0, nil)
+
+	handler := HTTPhandler.SetupHandlerWithServices(&taskService, &service.MockUserService{}, &service.MockWebhookService{}, &apiKeyService)
+	handler.Authenticators = []auth.Authenticator{tokenAuthenticator{}, handler.APIKeyService}
+	router := handler.SetupRouter()
+
+	tests := []struct {
+		name          string
+		method        string
+		target        string
+		body          string
+		authorization string
+		wantStatus    int
+	}{
+		{"read with read scope", http.MethodGet, "/api/tasks/", "", "ApiKey tm_0a1b2c3d4e5f_s3cret", http.StatusOK},
+		{"write without write scope", http.MethodPost, "/api/tasks/", `{"title": "x"}`, "ApiKey tm_0a1b2c3d4e5f_s3cret", http.StatusForbidden},
+		{"other resource", http.MethodGet, "/api/users/", "", "ApiKey tm_0a1b2c3d4e5f_s3cret", http.StatusForbidden},
+		{"admin endpoint", http.MethodGet, "/api/admin/api-keys/", "", "ApiKey tm_0a1b2c3d4e5f_s3cret", http.StatusForbidden},
+		{"graphql mutation", http.MethodPost, "/graphql", `{"query": "mutation { deleteTask(id: 1) }"}`, "ApiKey tm_0a1b2c3d4e5f_s3cret", http.StatusForbidden},
+		{"graphql query sent with GET", http.MethodGet, "/graphql?query=%7B%20tasks%20%7B%20total%20%7D%20%7D", "", "ApiKey tm_0a1b2c3d4e5f_s3cret", http.StatusOK},
+		{"unknown key", http.MethodGet, "/api/tasks/", "", "ApiKey tm_0a1b2c3d4e5f_forged", http.StatusUnauthorized},
+		{"user token without admin role", http.MethodGet, "/api/admin/api-keys/", "", "Bearer valid", http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequestWithContext(context.Background(), tt.method, tt.target, strings.NewReader(tt.body))
+			req.Header.Set("Authorization", tt.authorization)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.target == "/graphql" {
+				assert.Contains(t, w.Body.String(), "credentials lack the tasks:write scope")
+			}
+			if tt.method == http.MethodGet && strings.HasPrefix(tt.target, "/graphql") {
+				assert.JSONEq(t, `{"data": {"tasks": {"total": 0}}}`, w.Body.String())
+			}
+			if tt.wantStatus == http.StatusForbidden {
+				var response rest.StandardResponse
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Equal(t, HTTPhandler.Forbidden, response.Message)
+			}
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"task-manager/internal/gql"
+	"time"
+
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+)
+
+// GraphQL executes a GraphQL query or mutation.
+//
+// @Summary GraphQL endpoint
+// @Description Executes a GraphQL query or mutation over tasks, their assignees, watchers and recurrence parent.
+// @Description Relations are batched per request. Operations deeper or more complex than the configured limits are
+// @Description rejected before execution. Errors carry a machine readable "code" extension. Subscriptions are served
+// @Description by /graphql/stream. Posting needs the tasks:write scope; queries may also be sent with GET, in the
+// @Description query, operationName and variables (JSON) parameters, with tasks:read only.
+// @Tags GraphQL
+// @Accept json
+// @Produce json
+// @Param request body gql.Request true "GraphQL request"
+// @Success 200 {object} graphql.Result "Result, possibly with errors"
+// @Failure 400 {object} graphql.Result "Request body is not a GraphQL request"
+// @Router /graphql [post]
+// @Router /graphql [get]
+func (h *Handler) GraphQL(c *gin.Context) {
+	h.logger.InfoWithContext(c.Request.Context(), LogIncomingGraphQL)
+
+	req, err := graphQLRequest(c)
+	if err != nil {
+		h.logger.ErrorWithContext(c.Request.Context(), LogGraphQLFailed, LogKeyError, err)
+		c.JSON(http.StatusBadRequest, gql.ErrorResult(&gql.Error{Message: err.Error(), Code: gql.CodeBadUserInput}))
+		return
+	}
+
+	c.JSON(http.StatusOK, h.GraphQLServer.Execute(c.Request.Context(), req))
+}
+
+// GraphQLStream executes a GraphQL subscription and streams its results as Server-Sent Events.
+//
+// @Summary GraphQL subscriptions
+// @Description Executes a GraphQL operation following the distinct connections mode of GraphQL over Server-Sent
+// @Description Events: every result is sent as a "next" event and a "complete" event ends the stream. The taskEvents
+// @Description subscription starts with a TaskStreamReady message and pushes the changes of the matching tasks;
+// @Description resubscribe with lastEventId to resume after a disconnect. Queries and mutations yield a single result.
+// @Description Like /graphql, subscriptions may be sent with GET with tasks:read only.
+// @Tags GraphQL
+// @Accept json
+// @Produce text/event-stream
+// @Param request body gql.Request true "GraphQL request"
+// @Success 200 {object} graphql.Result "Stream of results"
+// @Failure 400 {object} graphql.Result "Request body is not a GraphQL request"
+// @Router /graphql/stream [post]
+// @Router /graphql/stream [get]
+func (h *Handler) GraphQLStream(c *gin.Context) {
+	h.logger.InfoWithContext(c.Request.Context(), LogIncomingGraphQLStream)
+
+	req, err := graphQLRequest(c)
+	if err != nil {
+		h.logger.ErrorWithContext(c.Request.Context(), LogGraphQLFailed, LogKeyError, err)
+		c.JSON(http.StatusBadRequest, gql.ErrorResult(&gql.Error{Message: err.Error(), Code: gql.CodeBadUserInput}))
+		return
+	}
+
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	results := h.GraphQLServer.Subscribe(ctx, req)
+	defer func() {
+		cancel()
+		for range results {
+			// The executor blocks until its last result is received
+		}
+	}()
+
+	// The stream outlives the server write timeout
+	if err := http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{}); err != nil {
+		h.logger.ErrorWithContext(c.Request.Context(), LogGraphQLFailed, LogKeyError, err)
+	}
+
+	header := c.Writer.Header()
+	header.Set("Content-Type", sse.ContentType)
+	header.Set("Cache-Control", "no-cache")
+	header.Set("Connection", "keep-alive")
+	header.Set("X-Accel-Buffering", "no") // disable proxy buffering (nginx)
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(h.streamHeartbeat())
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-heartbeat.C:
+			_, _ = c.Writer.WriteString(": heartbeat\n\n")
+			c.Writer.Flush()
+		case result, ok := <-results:
+			if !ok {
+				_ = sse.Encode(c.Writer, sse.Event{Event: GraphQLEventComplete, Data: ""})
+				c.Writer.Flush()
+				return
+			}
+			_ = sse.Encode(c.Writer, sse.Event{Event: GraphQLEventNext, Data: result})
+			c.Writer.Flush()
+			heartbeat.Reset(h.streamHeartbeat())
+		}
+	}
+}
+
+// graphQLRequest binds the GraphQL request of c: the JSON body of a POST request, the
+// query, operationName and variables parameters of a GET request, which may not run
+// mutations.
+func graphQLRequest(c *gin.Context) (gql.Request, error) {
+	var req gql.Request
+	if c.Request.Method != http.MethodGet {
+		err := c.ShouldBindJSON(&req)
+		return req, err
+	}
+
+	req = gql.Request{Query: c.Query("query"), OperationName: c.Query("operationName"), ReadOnly: true}
+	if req.Query == "" {
+		return req, errors.New(ErrGraphQLQueryRequired)
+	}
+	if variables := c.Query("variables"); variables != "" {
+		if err := json.Unmarshal([]byte(variables), &req.Variables); err != nil {
+			return req, err
+		}
+	}
+	return req, nil
+}
+
+const (
+	LogIncomingGraphQL       = "Incoming GraphQL request"
+	LogIncomingGraphQLStream = "Incoming GraphQL stream request"
+	LogGraphQLFailed         = "Failed to execute GraphQL request"
+
+	ErrGraphQLQueryRequired = "query parameter is required"
+
+	GraphQLEventNext     = "next"
+	GraphQLEventComplete = "complete"
+)
@@ -0,0 +1,140 @@
+package http_test
+
+import (
+	"bufio"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"task-manager/internal/entities"
+	HTTPhandler "task-manager/internal/http"
+	"task-manager/internal/service"
+	"testing"
+	"time"
+)
+
+// TestGraphQL_ShouldExecuteQuery tests that queries are executed against the services.
+func TestGraphQL_ShouldExecuteQuery(t *testing.T) {
+	mockService := &service.MockTaskService{}
+	router := HTTPhandler.SetupHandler(mockService).SetupRouter()
+
+	mockService.On("GetByID", mock.Anything, int64(1)).
+		Return(&entities.Task{ID: 1, Title: "Write docs", Status: entities.TaskStatusInProgress}, nil).Once()
+
+	body := `{"query": "query($id: ID!) { task(id: $id) { title status } }", "variables": {"id": 1}}`
+	req, _ := http.NewRequest(http.MethodPost, "/graphql", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"data": {"task": {"title": "Write docs", "status": "IN_PROGRESS"}}}`, w.Body.String())
+	mockService.AssertExpectations(t)
+}
+
+// TestGraphQL_InvalidBody_ShouldReturnBadRequest tests that requests without a query are rejected.
+func TestGraphQL_InvalidBody_ShouldReturnBadRequest(t *testing.T) {
+	router := HTTPhandler.SetupHandler(&service.MockTaskService{}).SetupRouter()
+
+	req, _ := http.NewRequest(http.MethodPost, "/graphql", strings.NewReader(`{"variables": {}}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"BAD_USER_INPUT"`)
+}
+
+// TestGraphQL_Get_ShouldExecuteQueriesOnly tests the operations sent in the query
+// string: queries run, mutations are refused.
+func TestGraphQL_Get_ShouldExecuteQueriesOnly(t *testing.T) {
+	mockService := &service.MockTaskService{}
+	router := HTTPhandler.SetupHandler(mockService).SetupRouter()
+
+	mockService.On("GetByID", mock.Anything, int64(1)).
+		Return(&entities.Task{ID: 1, Title: "Write docs", Status: entities.TaskStatusInProgress}, nil).Once()
+
+	get := func(params url.Values) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(http.MethodGet, "/graphql?"+params.Encode(), nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := get(url.Values{"query": {"query($id: ID!) { task(id: $id) { title } }"}, "variables": {`{"id": 1}`}})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"data": {"task": {"title": "Write docs"}}}`, w.Body.String())
+
+	w = get(url.Values{"query": {"mutation { deleteTask(id: 1) }"}})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "mutations must be posted")
+	mockService.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+
+	w = get(url.Values{})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"BAD_USER_INPUT"`)
+}
+
+// TestGraphQLStream_ShouldStreamSubscription tests that subscription results are sent as "next" events.
+func TestGraphQLStream_ShouldStreamSubscription(t *testing.T) {
+	handler := HTTPhandler.SetupHandler(&service.MockTaskService{})
+	server := httptest.NewServer(handler.SetupRouter())
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	body := `{"query": "subscription { taskEvents { ... on TaskStreamReady { resetRequired } ... on TaskEvent { eventId } } }"}`
+	req, _ := http.NewRequestWithContext(ctx, http.MethodPost, server.URL+"/graphql/stream", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	res, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer res.Body.Close()
+
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Contains(t, res.Header.Get("Content-Type"), "text/event-stream")
+
+	reader := bufio.NewReader(res.Body)
+	_, event, data := readSSEEvent(t, reader)
+	assert.Equal(t, HTTPhandler.GraphQLEventNext, event)
+	assert.JSONEq(t, `{"data": {"taskEvents": {"resetRequired": false}}}`, data)
+
+	task := &entities.Task{ID: 1, Title: "Write docs", Status: entities.TaskStatusPending}
+	require.NoError(t, handler.TaskEvents.Publish(ctx, entities.OutboxEvent{Sequence: 1, TaskEvent: entities.TaskEvent{ID: "e1", Type: entities.TaskEventCreated, TaskID: 1, Task: task}}))
+
+	_, event, data = readSSEEvent(t, reader)
+	assert.Equal(t, HTTPhandler.GraphQLEventNext, event)
+	assert.JSONEq(t, `{"data": {"taskEvents": {"eventId": "e1"}}}`, data)
+}
+
+// TestGraphQLStream_Query_ShouldComplete tests that a query yields a single result followed by "complete".
+func TestGraphQLStream_Query_ShouldComplete(t *testing.T) {
+	mockService := &service.MockTaskService{}
+	server := httptest.NewServer(HTTPhandler.SetupHandler(mockService).SetupRouter())
+	defer server.Close()
+
+	mockService.On("GetByID", mock.Anything, int64(1)).
+		Return(&entities.Task{ID: 1, Title: "Write docs", Status: entities.TaskStatusDone}, nil).Once()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	body := `{"query": "{ task(id: 1) { title } }"}`
+	req, _ := http.NewRequestWithContext(ctx, http.MethodPost, server.URL+"/graphql/stream", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	res, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer res.Body.Close()
+
+	reader := bufio.NewReader(res.Body)
+	_, event, data := readSSEEvent(t, reader)
+	assert.Equal(t, HTTPhandler.GraphQLEventNext, event)
+	assert.JSONEq(t, `{"data": {"task": {"title": "Write docs"}}}`, data)
+
+	_, event, _ = readSSEEvent(t, reader)
+	assert.Equal(t, HTTPhandler.GraphQLEventComplete, event)
+}
@@ -0,0 +1,76 @@
+package http
+
+import (
+	"net/http"
+	"task-manager/internal/health"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Liveness reports whether the process is alive.
+//
+// @Summary Liveness probe
+// @Description Reports that the process serves requests. Dependencies are not checked: restarting the instance would not fix them.
+// @Tags Health
+// @Produce json
+// @Success 200 {object} health.Report "Instance is alive"
+// @Router /healthz [get]
+func (h *Handler) Liveness(c *gin.Context) {
+	c.JSON(http.StatusOK, h.Health.Live())
+}
+
+// Readiness reports whether the instance is ready to serve requests.
+//
+// @Summary Readiness probe
+// @Description Runs the readiness checks of the dependencies. Fails until the startup checks passed, while a required check fails, and once the instance is draining for shutdown.
+// @Tags Health
+// @Produce json
+// @Success 200 {object} health.Report "Instance is ready, optional checks may fail"
+// @Failure 503 {object} health.Report "Instance is not ready"
+// @Router /readyz [get]
+func (h *Handler) Readiness(c *gin.Context) {
+	h.writeReport(c, h.Health.Ready(c.Request.Context()))
+}
+
+// Startup reports whether the instance started, e.g. once the migrations are applied.
+//
+// @Summary Startup probe
+// @Description Runs the startup checks, such as the database migrations, until they all passed once.
+// @Tags Health
+// @Produce json
+// @Success 200 {object} health.Report "Instance started"
+// @Failure 503 {object} health.Report "Instance is starting"
+// @Router /startupz [get]
+func (h *Handler) Startup(c *gin.Context) {
+	h.writeReport(c, h.Health.Startup(c.Request.Context()))
+}
+
+// HealthDetails reports the status and latency of every check.
+//
+// @Summary Detailed health status
+// @Description Runs every startup and readiness check, reporting the status, latency and error of each. Fails like the readiness probe.
+// @Tags Health
+// @Produce json
+// @Success 200 {object} health.Report "Instance is ready, optional checks may fail"
+// @Failure 503 {object} health.Report "Instance is not ready"
+// @Router /health [get]
+func (h *Handler) HealthDetails(c *gin.Context) {
+	h.writeReport(c, h.Health.Details(c.Request.Context()))
+}
+
+// writeReport writes a report, 503 Service Unavailable if it failed.
+func (h *Handler) writeReport(c *gin.Context, report health.Report) {
+	if !report.Healthy() {
+		for _, check := range report.Checks {
+			if check.Status != health.StatusOK {
+				h.logger.WarnWithContext(c.Request.Context(), LogHealthCheckFailed, LogKeyCheck, check.Name, LogKeyError, check.Error)
+			}
+		}
+		c.JSON(http.StatusServiceUnavailable, report)
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// LogHealthCheckFailed logs the failed checks of the reports, with their name and error.
+const LogHealthCheckFailed = "Health check failed"
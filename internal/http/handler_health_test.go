@@ -0,0 +1,128 @@
+package http_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"task-manager/internal/auth"
+	"task-manager/internal/health"
+	HTTPhandler "task-manager/internal/http"
+	"task-manager/internal/service"
+	"testing"
+)
+
+// TestHealthProbes tests the status codes of the probes for the state of the checks.
+func TestHealthProbes(t *testing.T) {
+	failing := health.CheckerFunc(func(context.Context) error { return errors.New("connection refused") })
+	passing := health.CheckerFunc(func(context.Context) error { return nil })
+
+	tests := []struct {
+		name     string
+		register func(r *health.Registry)
+		want     map[string]int
+	}{
+		{
+			name: "healthy",
+			register: func(r *health.Registry) {
+				r.Register("postgres", passing)
+				r.RegisterStartup("migrations", passing)
+			},
+			want: map[string]int{"/healthz": http.StatusOK, "/readyz": http.StatusOK, "/startupz": http.StatusOK, "/health": http.StatusOK},
+		},
+		{
+			name: "migrations pending",
+			register: func(r *health.Registry) {
+				r.Register("postgres", passing)
+				r.RegisterStartup("migrations", failing)
+			},
+			want: map[string]int{"/healthz": http.StatusOK, "/readyz": http.StatusServiceUnavailable, "/startupz": http.StatusServiceUnavailable, "/health": http.StatusServiceUnavailable},
+		},
+		{
+			name: "database down",
+			register: func(r *health.Registry) {
+				r.Register("postgres", failing)
+			},
+			want: map[string]int{"/healthz": http.StatusOK, "/readyz": http.StatusServiceUnavailable, "/startupz": http.StatusOK, "/health": http.StatusServiceUnavailable},
+		},
+		{
+			name: "job stalled",
+			register: func(r *health.Registry) {
+				r.Register("postgres", passing)
+				r.Register("job:outbox", failing, health.Optional())
+			},
+			want: map[string]int{"/healthz": http.StatusOK, "/readyz": http.StatusOK, "/startupz": http.StatusOK, "/health": http.StatusOK},
+		},
+		{
+			name: "draining",
+			register: func(r *health.Registry) {
+				r.Register("postgres", passing)
+				r.Drain()
+			},
+			want: map[string]int{"/healthz": http.StatusOK, "/readyz": http.StatusServiceUnavailable, "/startupz": http.StatusOK, "/health": http.StatusServiceUnavailable},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for path, want := range tt.want {
+				h := HTTPhandler.SetupHandler(&service.MockTaskService{})
+				tt.register(h.Health)
+				router := h.SetupRouter()
+
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+
+				var report health.Report
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+				assert.Equal(t, want, w.Code, path)
+			}
+		})
+	}
+}
+
+// TestHealthDetails_ShouldReportEveryCheck tests that the detailed status reports the
+// status and latency of every check.
+func TestHealthDetails_ShouldReportEveryCheck(t *testing.T) {
+	h := HTTPhandler.SetupHandler(&service.MockTaskService{})
+	h.Health.Register("postgres", health.CheckerFunc(func(context.Context) error { return nil }))
+	h.Health.Register("job:outbox", health.CheckerFunc(func(context.Context) error {
+		return errors.New("background job outbox completed no run for 5m0s")
+	}), health.Optional())
+	router := h.SetupRouter()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/health", nil))
+
+	var report health.Report
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, health.StatusDegraded, report.Status)
+	require.Len(t, report.Checks, 2)
+	assert.Equal(t, health.Result{
+		Name:      "job:outbox",
+		Status:    health.StatusFail,
+		Optional:  true,
+		LatencyMS: report.Checks[0].LatencyMS,
+		Error:     "background job outbox completed no run for 5m0s",
+	}, report.Checks[0])
+	assert.Equal(t, "postgres", report.Checks[1].Name)
+	assert.Equal(t, health.StatusOK, report.Checks[1].Status)
+}
+
+// TestHealthProbes_Authenticated_ShouldNotRequireCredentials tests that the probes stay
+// open when the API routes require authentication.
+func TestHealthProbes_Authenticated_ShouldNotRequireCredentials(t *testing.T) {
+	h := HTTPhandler.SetupHandler(&service.MockTaskService{})
+	h.Authenticators = []auth.Authenticator{tokenAuthenticator{}}
+	router := h.SetupRouter()
+
+	for _, path := range []string{"/healthz", "/readyz", "/startupz", "/health"} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Equal(t, http.StatusOK, w.Code, path)
+	}
+}
@@ -1,13 +1,13 @@
 package http
 
 import (
-	"fmt"
+	"context"
 	"github.com/cockroachdb/errors"
 	"github.com/gin-gonic/gin"
 	"net/http"
+	"slices"
 	"strconv"
 	"task-manager/internal/entities"
-	"task-manager/internal/repository/postgres"
 	"task-manager/pkg/rest"
 	"time"
 )
@@ -25,56 +25,33 @@ import (
 // @Param request body CreateTaskRequest true "Task creation payload"
 // @Success 201 {object} rest.StandardResponse{data=TaskResponse} "Task successfully created"
 // @Failure 400 {object} rest.StandardResponse{data=nil} "Invalid request payload or invalid task status"
+// @Failure 422 {object} rest.StandardResponse{data=nil} "Assignee or watcher does not exist, assignee is deactivated, or invalid recurrence"
+// @Failure 403 {object} rest.StandardResponse{data=nil} "Operation not allowed by the authorization policy"
 // @Failure 500 {object} rest.StandardResponse{data=nil} "Internal server error"
-// @Router /api/tasks/ [post]
+// @Router /api/v1/tasks/ [post]
 func (h *Handler) TaskCreate(c *gin.Context) {
-	traceID := TraceIDFromContext(c.Request.Context())
-
-	h.logger.InfoF(LogTemplateIncoming, traceID, LogIncomingTaskCreate)
+	h.createTask(c, taskMapperV1{})
+}
 
-	var req CreateTaskRequest
+// createTask creates the task bound by the mapper of the API version.
+func (h *Handler) createTask(c *gin.Context, mapper TaskMapper) {
+	h.logger.InfoWithContext(c.Request.Context(), LogIncomingTaskCreate)
 
-	if err := c.ShouldBindJSON(&req); err != nil {
-		h.logger.ErrorF(LogTemplateError, traceID, LogTaskCreateFailed, err.Error())
-		c.JSON(http.StatusBadRequest, rest.GetFailedValidationResponse(err))
-		return
-	}
-
-	if req.Status == "" {
-		req.Status = entities.TaskStatusPending
-	}
-
-	if !req.Status.IsValid() {
-		h.logger.ErrorF(LogTemplateError, traceID, LogTaskCreateFailed, errors.New(InvalidTaskStatus))
-		c.JSON(http.StatusBadRequest, rest.GetFailedValidationResponse(errors.New(InvalidTaskStatus)))
+	task, err := mapper.CreateTask(c)
+	if err != nil {
+		_ = c.Error(err)
 		return
 	}
 
-	task := &entities.Task{
-		Title:       req.Title,
-		Description: req.Description,
-		Status:      req.Status,
-		AssigneeID:  req.AssigneeID,
-	}
-
 	createdTask, err := h.TaskService.Create(c, task)
 	if err != nil {
-		h.logger.ErrorWithContext(c, fmt.Sprintf(LogTemplateError, traceID, Error, err.Error()))
-		c.JSON(http.StatusInternalServerError, rest.InternalServerError)
+		_ = c.Error(err)
 		return
 	}
 
-	h.logger.InfoF(LogTemplateSuccess, traceID, LogTaskCreateSuccess, createdTask.ID)
-
-	c.JSON(http.StatusCreated, rest.GetSuccessResponse(TaskResponse{
-		ID:          createdTask.ID,
-		Title:       createdTask.Title,
-		Description: createdTask.Description,
-		Status:      createdTask.Status,
-		AssigneeID:  createdTask.AssigneeID,
-		CreatedAt:   createdTask.CreatedAt.Format(time.RFC3339),
-		UpdatedAt:   createdTask.UpdatedAt.Format(time.RFC3339),
-	}))
+	h.logger.InfoWithContext(c.Request.Context(), LogTaskCreateSuccess, LogKeyTaskID, createdTask.ID)
+
+	c.JSON(http.StatusCreated, rest.GetSuccessResponse(mapper.TaskResponse(createdTask, nil)))
 }
 
 // TaskUpdate updates an existing task by its ID.
@@ -89,68 +66,39 @@ func (h *Handler) TaskCreate(c *gin.Context) {
 // @Success 200 {object} rest.StandardResponse{data=TaskResponse} "Task successfully updated"
 // @Failure 400 {object} rest.StandardResponse{data=nil} "Invalid task ID or request payload"
 // @Failure 404 {object} rest.StandardResponse{data=nil} "Task not found"
+// @Failure 422 {object} rest.StandardResponse{data=nil} "Assignee or watcher does not exist, assignee is deactivated, or invalid recurrence"
+// @Failure 403 {object} rest.StandardResponse{data=nil} "Operation not allowed by the authorization policy"
 // @Failure 500 {object} rest.StandardResponse{data=nil} "Internal server error"
-// @Router /api/tasks/{id} [put]
+// @Router /api/v1/tasks/{id} [put]
 func (h *Handler) TaskUpdate(c *gin.Context) {
-	traceID := TraceIDFromContext(c.Request.Context())
+	h.updateTask(c, taskMapperV1{})
+}
 
-	h.logger.InfoF(LogTemplateIncoming, traceID, LogIncomingTaskUpdate)
-	taskIDParam := c.Param(ID)
-	if taskIDParam == "" {
-		h.logger.ErrorF(LogTemplateError, traceID, LogTaskUpdateFailed, errors.New(TaskIDIsRequired))
-		c.JSON(http.StatusBadRequest, rest.GetFailedValidationResponse(errors.New(TaskIDIsRequired)))
-		return
-	}
+// updateTask replaces the task bound by the mapper of the API version.
+func (h *Handler) updateTask(c *gin.Context, mapper TaskMapper) {
+	h.logger.InfoWithContext(c.Request.Context(), LogIncomingTaskUpdate)
 
-	taskID, err := strconv.ParseInt(taskIDParam, 10, 64)
+	taskID, err := taskIDOf(c)
 	if err != nil {
-		h.logger.ErrorF(LogTemplateError, traceID, LogTaskUpdateFailed, errors.New(InvalidTaskID))
-		c.JSON(http.StatusBadRequest, rest.GetFailedValidationResponse(errors.New(InvalidTaskStatus)))
+		_ = c.Error(err)
 		return
 	}
 
-	var req UpdateTaskRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		h.logger.ErrorF(LogTemplateError, traceID, LogTaskUpdateFailed, err.Error())
-		c.JSON(http.StatusBadRequest, rest.GetFailedValidationResponse(err))
+	task, err := mapper.UpdateTask(c, taskID)
+	if err != nil {
+		_ = c.Error(err)
 		return
 	}
 
-	task := &entities.Task{
-		ID:          taskID,
-		Title:       req.Title,
-		Description: req.Description,
-		AssigneeID:  req.AssigneeID,
-		Status:      req.Status,
-	}
-
 	updatedTask, err := h.TaskService.Update(c, task)
 	if err != nil {
-		h.logger.ErrorWithContext(c, fmt.Sprintf(LogTemplateError, traceID, Error, err.Error()))
-		if errors.Is(err, postgres.ErrTaskNotFound) {
-			h.logger.ErrorF(LogTemplateError, traceID, LogTaskUpdateFailed, rest.NotFound)
-			c.JSON(http.StatusNotFound, rest.NotFound)
-
-			return
-		}
-
-		h.logger.ErrorF(LogTemplateError, traceID, LogTaskUpdateFailed, rest.InternalServerError)
-		c.JSON(http.StatusInternalServerError, rest.InternalServerError)
-
+		_ = c.Error(err)
 		return
 	}
 
-	h.logger.InfoF(LogTemplateSuccess, traceID, LogTaskUpdateSuccess, updatedTask.ID)
-
-	c.JSON(http.StatusOK, rest.GetSuccessResponse(TaskResponse{
-		ID:          updatedTask.ID,
-		Title:       updatedTask.Title,
-		Description: updatedTask.Description,
-		Status:      updatedTask.Status,
-		AssigneeID:  updatedTask.AssigneeID,
-		CreatedAt:   updatedTask.CreatedAt.Format(time.RFC3339),
-		UpdatedAt:   updatedTask.UpdatedAt.Format(time.RFC3339),
-	}))
+	h.logger.InfoWithContext(c.Request.Context(), LogTaskUpdateSuccess, LogKeyTaskID, updatedTask.ID)
+
+	c.JSON(http.StatusOK, rest.GetSuccessResponse(mapper.TaskResponse(updatedTask, nil)))
 }
 
 // TaskDelete deletes an existing task by its ID.
@@ -164,41 +112,25 @@ func (h *Handler) TaskUpdate(c *gin.Context) {
 // @Success 204 "Task successfully deleted"
 // @Failure 400 {object} rest.StandardResponse{data=nil} "Invalid task ID"
 // @Failure 404 {object} rest.StandardResponse{data=nil} "Task not found"
+// @Failure 403 {object} rest.StandardResponse{data=nil} "Operation not allowed by the authorization policy"
 // @Failure 500 {object} rest.StandardResponse{data=nil} "Internal server error"
-// @Router /api/tasks/{id} [delete]
+// @Router /api/v1/tasks/{id} [delete]
+// @Router /api/v2/tasks/{id} [delete]
 func (h *Handler) TaskDelete(c *gin.Context) {
-	traceID := TraceIDFromContext(c.Request.Context())
+	h.logger.InfoWithContext(c.Request.Context(), LogIncomingTaskDelete)
 
-	h.logger.InfoF(LogTemplateIncoming, traceID, LogIncomingTaskDelete)
-
-	taskIDParam := c.Param(ID)
-	if taskIDParam == "" {
-		h.logger.ErrorF(LogTemplateError, traceID, LogTaskDeleteFailed, errors.New(TaskIDIsRequired))
-		c.JSON(http.StatusBadRequest, rest.GetFailedValidationResponse(errors.New(TaskIDIsRequired)))
-		return
-	}
-
-	taskID, err := strconv.ParseInt(taskIDParam, 10, 64)
+	taskID, err := taskIDOf(c)
 	if err != nil {
-		h.logger.ErrorF(LogTemplateError, traceID, LogTaskDeleteFailed, errors.New(InvalidTaskStatus))
-		c.JSON(http.StatusBadRequest, rest.GetFailedValidationResponse(errors.New(InvalidTaskID)))
+		_ = c.Error(err)
 		return
 	}
 
-	err = h.TaskService.Delete(c, taskID)
-	if err != nil {
-		if errors.Is(err, postgres.ErrTaskNotFound) {
-			h.logger.ErrorF(LogTemplateError, traceID, LogTaskDeleteFailed, rest.NotFound)
-			c.JSON(http.StatusNotFound, rest.NotFound)
-			return
-		}
-
-		h.logger.ErrorWithContext(c, fmt.Sprintf(LogTemplateError, traceID, Error, err.Error()))
-		c.JSON(http.StatusInternalServerError, rest.InternalServerError)
+	if err := h.TaskService.Delete(c, taskID); err != nil {
+		_ = c.Error(err)
 		return
 	}
 
-	h.logger.InfoF(LogTemplateSuccess, traceID, LogTaskDeleteSuccess, taskID)
+	h.logger.InfoWithContext(c.Request.Context(), LogTaskDeleteSuccess, LogKeyTaskID, taskID)
 
 	c.Status(http.StatusNoContent)
 }
@@ -211,56 +143,44 @@ func (h *Handler) TaskDelete(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param id path int true "Task ID"
+// @Param include query string false "Comma separated related resources to embed (assignee)"
 // @Success 200 {object} rest.StandardResponse{data=TaskResponse} "Task successfully fetched"
 // @Failure 400 {object} rest.StandardResponse{data=nil} "Invalid task ID"
 // @Failure 404 {object} rest.StandardResponse{data=nil} "Task not found"
+// @Failure 403 {object} rest.StandardResponse{data=nil} "Operation not allowed by the authorization policy"
 // @Failure 500 {object} rest.StandardResponse{data=nil} "Internal server error"
-// @Router /api/tasks/{id} [get]
+// @Router /api/v1/tasks/{id} [get]
 func (h *Handler) TaskGetByID(c *gin.Context) {
-	traceID := TraceIDFromContext(c.Request.Context())
-
-	h.logger.InfoF(LogTemplateIncoming, traceID, LogIncomingTaskFetch)
-
-	taskIDParam := c.Param(ID)
-	if taskIDParam == "" {
-		h.logger.ErrorF(LogTemplateError, traceID, LogTaskFetchFailed, errors.New(TaskIDIsRequired))
+	h.getTask(c, taskMapperV1{})
+}
 
-		c.JSON(http.StatusBadRequest, rest.GetFailedValidationResponse(errors.New(TaskIDIsRequired)))
+// getTask writes the task mapped by the mapper of the API version.
+func (h *Handler) getTask(c *gin.Context, mapper TaskMapper) {
+	h.logger.InfoWithContext(c.Request.Context(), LogIncomingTaskFetch)
 
+	taskID, err := taskIDOf(c)
+	if err != nil {
+		_ = c.Error(err)
 		return
 	}
 
-	taskID, err := strconv.ParseInt(taskIDParam, 10, 64)
+	task, err := h.TaskService.GetByID(c, taskID)
 	if err != nil {
-		h.logger.ErrorF(LogTemplateError, traceID, LogTaskFetchFailed, errors.New(InvalidTaskID))
-		c.JSON(http.StatusBadRequest, rest.GetFailedValidationResponse(errors.New(InvalidTaskID)))
+		_ = c.Error(err)
 		return
 	}
 
-	task, err := h.TaskService.GetByID(c, taskID)
-	if err != nil {
-		if errors.Is(err, postgres.ErrTaskNotFound) {
-			h.logger.ErrorF(LogTemplateError, traceID, LogTaskFetchFailed, rest.NotFound)
-			c.JSON(http.StatusNotFound, rest.NotFound)
+	var assignees map[int64]*AssigneeResponse
+	if rest.ParseQuery(c).Includes(IncludeAssignee) {
+		if assignees, err = h.assigneesOf(c, []entities.Task{*task}); err != nil {
+			_ = c.Error(err)
 			return
 		}
-
-		h.logger.ErrorWithContext(c, fmt.Sprintf(LogTemplateError, traceID, Error, err.Error()))
-		c.JSON(http.StatusInternalServerError, rest.InternalServerError)
-		return
 	}
 
-	h.logger.InfoF(LogTemplateSuccess, traceID, LogTaskFetchSuccess, taskID)
+	h.logger.InfoWithContext(c.Request.Context(), LogTaskFetchSuccess, LogKeyTaskID, taskID)
 
-	c.JSON(http.StatusOK, rest.GetSuccessResponse(TaskResponse{
-		ID:          task.ID,
-		Title:       task.Title,
-		Description: task.Description,
-		Status:      task.Status,
-		AssigneeID:  task.AssigneeID,
-		CreatedAt:   task.CreatedAt.Format(time.RFC3339),
-		UpdatedAt:   task.UpdatedAt.Format(time.RFC3339),
-	}))
+	c.JSON(http.StatusOK, rest.GetSuccessResponse(mapper.TaskResponse(task, assignees)))
 }
 
 // TaskList retrieves a paginated list of tasks.
@@ -273,28 +193,45 @@ func (h *Handler) TaskGetByID(c *gin.Context) {
 // @Param page query int false "Page number" default(1)
 // @Param per_page query int false "Number of tasks per page" default(20)
 // @Param filter query string false "Filter by any task field (e.g., title, status)"
-// @Success 200 {object} rest.StandardResponse{data=[]entities.Task, meta=rest.PaginationMeta} "List of tasks successfully fetched"
+// @Param assignee_id query string false "Comma separated user IDs, matches tasks assigned to any of them"
+// @Param watcher_id query string false "Comma separated user IDs, matches tasks watched by any of them"
+// @Param include query string false "Comma separated related resources to embed (assignee)"
+// @Success 200 {object} rest.StandardResponse{data=[]TaskResponse, meta=rest.PaginationMeta} "List of tasks successfully fetched"
+// @Failure 403 {object} rest.StandardResponse{data=nil} "Operation not allowed by the authorization policy"
 // @Failure 500 {object} rest.StandardResponse{data=nil} "Internal server error"
-// @Router /api/tasks [get]
+// @Router /api/v1/tasks [get]
 func (h *Handler) TaskList(c *gin.Context) {
-	traceID := TraceIDFromContext(c.Request.Context())
+	h.listTasks(c, taskMapperV1{})
+}
 
-	h.logger.InfoF(LogTemplateIncoming, traceID, LogIncomingTaskFetch)
+// listTasks writes the page of tasks mapped by the mapper of the API version.
+func (h *Handler) listTasks(c *gin.Context, mapper TaskMapper) {
+	h.logger.InfoWithContext(c.Request.Context(), LogIncomingTaskFetch)
 
 	query := rest.ParseQuery(c)
 
 	tasks, total, err := h.TaskService.List(c, query)
 	if err != nil {
-		h.logger.ErrorWithContext(c, fmt.Sprintf(LogTemplateError, traceID, Error, err.Error()))
+		_ = c.Error(err)
+		return
+	}
 
-		c.JSON(http.StatusInternalServerError, rest.InternalServerError)
+	var assignees map[int64]*AssigneeResponse
+	if query.Includes(IncludeAssignee) {
+		if assignees, err = h.assigneesOf(c, tasks); err != nil {
+			_ = c.Error(err)
+			return
+		}
+	}
 
-		return
+	response := make([]any, 0, len(tasks))
+	for i := range tasks {
+		response = append(response, mapper.TaskResponse(&tasks[i], assignees))
 	}
 
-	h.logger.InfoF(LogTemplateSuccess, traceID, LogTaskFetchSuccess, Bulk)
+	h.logger.InfoWithContext(c.Request.Context(), LogTaskFetchSuccess, LogKeyCount, len(tasks))
 
-	c.JSON(http.StatusOK, rest.GetSuccessResponseWithMeta(tasks, rest.PaginationMeta{
+	c.JSON(http.StatusOK, rest.GetSuccessResponseWithMeta(response, rest.PaginationMeta{
 		Total:   total,
 		Page:    query.Page,
 		PerPage: query.PerPage,
@@ -303,50 +240,193 @@ func (h *Handler) TaskList(c *gin.Context) {
 	)
 }
 
+// newTaskResponse maps a task entity to its API representation.
+func newTaskResponse(task *entities.Task) TaskResponse {
+	response := TaskResponse{
+		ID:          task.ID,
+		Title:       task.Title,
+		Description: task.Description,
+		Status:      task.Status,
+		AssigneeID:  task.AssigneeID,
+		AssigneeIDs: task.AssigneeIDs,
+		WatcherIDs:  task.WatcherIDs,
+		CreatedAt:   task.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:   task.UpdatedAt.Format(time.RFC3339),
+
+		RecurrenceRule:     task.RecurrenceRule,
+		RecurrenceParentID: task.RecurrenceParentID,
+	}
+
+	if task.DueAt != nil {
+		response.DueAt = task.DueAt.Format(time.RFC3339)
+	}
+
+	return response
+}
+
+// taskMapperV1 maps the task DTOs of the v1 API.
+type taskMapperV1 struct{}
+
+// CreateTask binds a CreateTaskRequest. The status defaults to pending.
+func (taskMapperV1) CreateTask(c *gin.Context) (*entities.Task, error) {
+	var req CreateTaskRequest
+	if err := bindJSON(c, &req); err != nil {
+		return nil, err
+	}
+
+	if req.Status == "" {
+		req.Status = entities.TaskStatusPending
+	}
+
+	if !req.Status.IsValid() {
+		return nil, ErrInvalidTaskStatus
+	}
+
+	return &entities.Task{
+		Title:       req.Title,
+		Description: req.Description,
+		Status:      req.Status,
+		AssigneeID:  primaryAssignee(req.AssigneeID, req.AssigneeIDs),
+		AssigneeIDs: req.AssigneeIDs,
+		WatcherIDs:  req.WatcherIDs,
+		DueAt:       req.DueAt,
+
+		RecurrenceRule: req.RecurrenceRule,
+	}, nil
+}
+
+// UpdateTask binds an UpdateTaskRequest.
+func (taskMapperV1) UpdateTask(c *gin.Context, id int64) (*entities.Task, error) {
+	var req UpdateTaskRequest
+	if err := bindJSON(c, &req); err != nil {
+		return nil, err
+	}
+
+	return &entities.Task{
+		ID:          id,
+		Title:       req.Title,
+		Description: req.Description,
+		AssigneeID:  primaryAssignee(req.AssigneeID, req.AssigneeIDs),
+		AssigneeIDs: req.AssigneeIDs,
+		WatcherIDs:  req.WatcherIDs,
+		Status:      req.Status,
+		DueAt:       req.DueAt,
+
+		RecurrenceRule: req.RecurrenceRule,
+	}, nil
+}
+
+// TaskResponse maps a task to a TaskResponse.
+func (taskMapperV1) TaskResponse(task *entities.Task, assignees map[int64]*AssigneeResponse) any {
+	response := newTaskResponse(task)
+	response.Assignee = assignees[task.AssigneeID]
+	return response
+}
+
+// assigneesOf loads the assignees of the tasks by user ID, fetching the distinct users in
+// a single query. Assignees that no longer exist are left empty instead of failing the
+// whole request.
+func (h *Handler) assigneesOf(ctx context.Context, tasks []entities.Task) (map[int64]*AssigneeResponse, error) {
+	ids := make([]int64, 0, len(tasks))
+	for _, task := range tasks {
+		if !slices.Contains(ids, task.AssigneeID) {
+			ids = append(ids, task.AssigneeID)
+		}
+	}
+
+	users, err := h.UserService.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	assignees := make(map[int64]*AssigneeResponse, len(users))
+	for _, user := range users {
+		assignees[user.ID] = &AssigneeResponse{ID: user.ID, Name: user.Name, Email: user.Email}
+	}
+
+	return assignees, nil
+}
+
+// taskIDOf parses the task ID path parameter.
+func taskIDOf(c *gin.Context) (int64, error) {
+	return pathID(c, ID, InvalidTaskID)
+}
+
+// pathID parses the ID path parameter name. Missing or malformed IDs are reported as
+// ErrInvalidID, detailed by invalid.
+func pathID(c *gin.Context, name, invalid string) (int64, error) {
+	id, err := strconv.ParseInt(c.Param(name), 10, 64)
+	if err != nil {
+		return 0, ErrInvalidID.Wrap(errors.New(invalid))
+	}
+	return id, nil
+}
+
+// primaryAssignee keeps backward compatibility with clients sending a single assignee_id.
+// Clients sending only assignee_ids get the first one as primary assignee.
+func primaryAssignee(assigneeID int64, assigneeIDs []int64) int64 {
+	if assigneeID == 0 && len(assigneeIDs) > 0 {
+		return assigneeIDs[0]
+	}
+	return assigneeID
+}
+
 const (
 	LogIncomingTaskCreate = "Incoming task create request"
 	LogTaskCreateSuccess  = "Task created successfully"
-	LogTaskCreateFailed   = "Failed to create task"
 
 	LogIncomingTaskUpdate = "Incoming task update request"
 	LogTaskUpdateSuccess  = "Task updated successfully"
-	LogTaskUpdateFailed   = "Failed to update task"
 
 	LogIncomingTaskDelete = "Incoming task delete request"
 	LogTaskDeleteSuccess  = "Task delete successfully"
-	LogTaskDeleteFailed   = "Failed to delete task"
 
 	LogIncomingTaskFetch = "Incoming task fetch request"
 	LogTaskFetchSuccess  = "Task fetch successfully"
-	LogTaskFetchFailed   = "Failed to fetch task"
 
 	InvalidTaskStatus = "Invalid task status"
-	TaskIDIsRequired  = "Task ID is required"
 	InvalidTaskID     = "Invalid task ID"
 
-	ID    = "id"
-	Error = "err"
-	Bulk  = "bulk"
-)
-
-var (
-	LogTemplateIncoming = "[TRACE %s] %s"
-	LogTemplateSuccess  = "[TRACE %s] %s: ID=%d"
-	LogTemplateError    = "[TRACE %s] %s: %v"
+	ID = "id"
+
+	// Keys of the attributes of the handler logs, next to the trace, route, principal
+	// and tenant of the request
+	LogKeyTaskID     = "task_id"
+	LogKeyUserID     = "user_id"
+	LogKeyWebhookID  = "webhook_id"
+	LogKeyDeliveryID = "delivery_id"
+	LogKeyAPIKeyID   = "api_key_id"
+	LogKeyCount      = "count"
+	LogKeyError      = "error"
+	LogKeyMethod     = "method"
+	LogKeyCode       = "code"
+	LogKeyCheck      = "check"
+
+	IncludeAssignee = "assignee"
 )
 
 type CreateTaskRequest struct {
 	Title       string              `json:"title" binding:"required"`
 	Description string              `json:"description,omitempty"`
 	Status      entities.TaskStatus `json:"status,omitempty"` // optional, default pending
-	AssigneeID  int64               `json:"assignee_id" binding:"required"`
+	AssigneeID  int64               `json:"assignee_id" binding:"required_without=AssigneeIDs"`
+	AssigneeIDs []int64             `json:"assignee_ids,omitempty"` // optional co-assignees
+	WatcherIDs  []int64             `json:"watcher_ids,omitempty"`  // optional watchers
+	DueAt       *time.Time          `json:"due_at,omitempty"`       // optional deadline, RFC 3339
+
+	RecurrenceRule string `json:"recurrence_rule,omitempty"` // optional RRULE, e.g. FREQ=WEEKLY;BYDAY=MO, requires due_at
 }
 
 type UpdateTaskRequest struct {
 	Title       string              `json:"title" binding:"required"`
 	Description string              `json:"description,omitempty"`
 	Status      entities.TaskStatus `json:"status" binding:"required,oneof=pending in_progress done canceled"`
-	AssigneeID  int64               `json:"assignee_id" binding:"required"`
+	AssigneeID  int64               `json:"assignee_id" binding:"required_without=AssigneeIDs"`
+	AssigneeIDs []int64             `json:"assignee_ids,omitempty"` // replaces co-assignees when present
+	WatcherIDs  []int64             `json:"watcher_ids,omitempty"`  // replaces watchers when present
+	DueAt       *time.Time          `json:"due_at,omitempty"`       // clears the deadline when absent
+
+	RecurrenceRule string `json:"recurrence_rule,omitempty"` // stops the recurrence when absent
 }
 
 type TaskResponse struct {
@@ -355,6 +435,19 @@ type TaskResponse struct {
 	Description string              `json:"description,omitempty"`
 	Status      entities.TaskStatus `json:"status"`
 	AssigneeID  int64               `json:"assignee_id" binding:"required"`
+	AssigneeIDs []int64             `json:"assignee_ids"`
+	WatcherIDs  []int64             `json:"watcher_ids"`
+	Assignee    *AssigneeResponse   `json:"assignee,omitempty"` // only with ?include=assignee
+	DueAt       string              `json:"due_at,omitempty"`
 	CreatedAt   string              `json:"created_at"`
 	UpdatedAt   string              `json:"updated_at"`
+
+	RecurrenceRule     string `json:"recurrence_rule,omitempty"`
+	RecurrenceParentID *int64 `json:"recurrence_parent_id,omitempty"` // occurrence this task was created from
+}
+
+type AssigneeResponse struct {
+	ID    int64  `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
 }
@@ -0,0 +1,167 @@
+package http
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"task-manager/internal/entities"
+	"task-manager/pkg/rest"
+)
+
+// TaskAddAssignee adds a co-assignee to a task.
+//
+// @Summary Add a task assignee
+// @Description Adds an active user as co-assignee of the task. Adding an existing assignee is a no-op.
+// @Tags Tasks
+// @Accept json
+// @Produce json
+// @Param id path int true "Task ID"
+// @Param request body TaskMemberRequest true "User to assign"
+// @Success 200 {object} rest.StandardResponse{data=TaskResponse} "Assignee successfully added"
+// @Failure 400 {object} rest.StandardResponse{data=nil} "Invalid task ID or request payload"
+// @Failure 404 {object} rest.StandardResponse{data=nil} "Task not found"
+// @Failure 422 {object} rest.StandardResponse{data=nil} "User does not exist or is deactivated"
+// @Failure 403 {object} rest.StandardResponse{data=nil} "Operation not allowed by the authorization policy"
+// @Failure 500 {object} rest.StandardResponse{data=nil} "Internal server error"
+// @Router /api/v1/tasks/{id}/assignees [post]
+func (h *Handler) TaskAddAssignee(c *gin.Context) {
+	h.addTaskMember(c, LogIncomingTaskAssigneeAdd, h.TaskService.AddAssignee, taskMapperV1{})
+}
+
+// TaskRemoveAssignee removes a co-assignee from a task.
+//
+// @Summary Remove a task assignee
+// @Description Removes a co-assignee from the task. The primary assignee must be changed through the task update instead.
+// @Tags Tasks
+// @Accept json
+// @Produce json
+// @Param id path int true "Task ID"
+// @Param user_id path int true "User ID"
+// @Success 204 "Assignee successfully removed"
+// @Failure 400 {object} rest.StandardResponse{data=nil} "Invalid task or user ID"
+// @Failure 404 {object} rest.StandardResponse{data=nil} "Task not found or user not assigned"
+// @Failure 409 {object} rest.StandardResponse{data=nil} "User is the primary assignee"
+// @Failure 403 {object} rest.StandardResponse{data=nil} "Operation not allowed by the authorization policy"
+// @Failure 500 {object} rest.StandardResponse{data=nil} "Internal server error"
+// @Router /api/v1/tasks/{id}/assignees/{user_id} [delete]
+// @Router /api/v2/tasks/{id}/assignees/{user_id} [delete]
+func (h *Handler) TaskRemoveAssignee(c *gin.Context) {
+	h.removeTaskMember(c, LogIncomingTaskAssigneeRemove, h.TaskService.RemoveAssignee)
+}
+
+// TaskAddWatcher subscribes a user to a task.
+//
+// @Summary Add a task watcher
+// @Description Subscribes an existing user to the task. Adding an existing watcher is a no-op.
+// @Tags Tasks
+// @Accept json
+// @Produce json
+// @Param id path int true "Task ID"
+// @Param request body TaskMemberRequest true "User to subscribe"
+// @Success 200 {object} rest.StandardResponse{data=TaskResponse} "Watcher successfully added"
+// @Failure 400 {object} rest.StandardResponse{data=nil} "Invalid task ID or request payload"
+// @Failure 404 {object} rest.StandardResponse{data=nil} "Task not found"
+// @Failure 422 {object} rest.StandardResponse{data=nil} "User does not exist"
+// @Failure 403 {object} rest.StandardResponse{data=nil} "Operation not allowed by the authorization policy"
+// @Failure 500 {object} rest.StandardResponse{data=nil} "Internal server error"
+// @Router /api/v1/tasks/{id}/watchers [post]
+func (h *Handler) TaskAddWatcher(c *gin.Context) {
+	h.addTaskMember(c, LogIncomingTaskWatcherAdd, h.TaskService.AddWatcher, taskMapperV1{})
+}
+
+// TaskRemoveWatcher unsubscribes a user from a task.
+//
+// @Summary Remove a task watcher
+// @Description Unsubscribes a user from the task.
+// @Tags Tasks
+// @Accept json
+// @Produce json
+// @Param id path int true "Task ID"
+// @Param user_id path int true "User ID"
+// @Success 204 "Watcher successfully removed"
+// @Failure 400 {object} rest.StandardResponse{data=nil} "Invalid task or user ID"
+// @Failure 404 {object} rest.StandardResponse{data=nil} "Task not found or user not watching"
+// @Failure 403 {object} rest.StandardResponse{data=nil} "Operation not allowed by the authorization policy"
+// @Failure 500 {object} rest.StandardResponse{data=nil} "Internal server error"
+// @Router /api/v1/tasks/{id}/watchers/{user_id} [delete]
+// @Router /api/v2/tasks/{id}/watchers/{user_id} [delete]
+func (h *Handler) TaskRemoveWatcher(c *gin.Context) {
+	h.removeTaskMember(c, LogIncomingTaskWatcherRemove, h.TaskService.RemoveWatcher)
+}
+
+// addTaskMember binds the member payload, calls the given service operation and
+// writes the refreshed task, mapped by the mapper of the API version.
+func (h *Handler) addTaskMember(
+	c *gin.Context,
+	incoming string,
+	add func(ctx context.Context, taskID, userID int64) (*entities.Task, error),
+	mapper TaskMapper,
+) {
+	h.logger.InfoWithContext(c.Request.Context(), incoming)
+
+	taskID, err := taskIDOf(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	var req TaskMemberRequest
+	if err := bindJSON(c, &req); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	task, err := add(c, taskID, req.UserID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	h.logger.InfoWithContext(c.Request.Context(), LogTaskMemberSuccess, LogKeyTaskID, taskID)
+
+	c.JSON(http.StatusOK, rest.GetSuccessResponse(mapper.TaskResponse(task, nil)))
+}
+
+// removeTaskMember parses the task and user IDs and calls the given service operation.
+func (h *Handler) removeTaskMember(
+	c *gin.Context,
+	incoming string,
+	remove func(ctx context.Context, taskID, userID int64) error,
+) {
+	h.logger.InfoWithContext(c.Request.Context(), incoming)
+
+	taskID, err := taskIDOf(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	userID, err := pathID(c, UserIDParam, InvalidUserID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	if err := remove(c, taskID, userID); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	h.logger.InfoWithContext(c.Request.Context(), LogTaskMemberSuccess, LogKeyTaskID, taskID)
+
+	c.Status(http.StatusNoContent)
+}
+
+const (
+	LogIncomingTaskAssigneeAdd    = "Incoming task assignee add request"
+	LogIncomingTaskAssigneeRemove = "Incoming task assignee remove request"
+	LogIncomingTaskWatcherAdd     = "Incoming task watcher add request"
+	LogIncomingTaskWatcherRemove  = "Incoming task watcher remove request"
+	LogTaskMemberSuccess          = "Task members updated successfully"
+
+	UserIDParam = "user_id"
+)
+
+type TaskMemberRequest struct {
+	UserID int64 `json:"user_id" binding:"required"`
+}
@@ -0,0 +1,128 @@
+package http_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"task-manager/internal/entities"
+	HTTPhandler "task-manager/internal/http"
+	"task-manager/internal/service"
+	"task-manager/pkg/rest"
+	"testing"
+)
+
+// TestTaskCreate_OnlyAssigneeIDs_ShouldUseFirstAsPrimary tests that clients may omit
+// the legacy assignee_id when sending assignee_ids.
+func TestTaskCreate_OnlyAssigneeIDs_ShouldUseFirstAsPrimary(t *testing.T) {
+	taskService := service.MockTaskService{}
+	taskService.On(
+		"Create",
+		mock.Anything,
+		mock.MatchedBy(func(task *entities.Task) bool {
+			return task.AssigneeID == 3 && len(task.AssigneeIDs) == 2 && len(task.WatcherIDs) == 1
+		}),
+	).Return(&stubTask, nil)
+
+	router := HTTPhandler.SetupHandler(&taskService).SetupRouter()
+
+	body := map[string]interface{}{
+		"title":        "Test",
+		"assignee_ids": []int64{3, 4},
+		"watcher_ids":  []int64{5},
+	}
+	jsonBytes, _ := json.Marshal(body)
+	req, err := http.NewRequest(http.MethodPost, "/api/tasks/", bytes.NewBuffer(jsonBytes))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	taskService.AssertExpectations(t)
+}
+
+// TestTaskCreate_WithoutAnyAssignee_ShouldReturnBadRequest tests that at least one assignee is required.
+func TestTaskCreate_WithoutAnyAssignee_ShouldReturnBadRequest(t *testing.T) {
+	router := HTTPhandler.SetupHandler(&service.MockTaskService{}).SetupRouter()
+
+	jsonBytes, _ := json.Marshal(map[string]interface{}{"title": "Test"})
+	req, err := http.NewRequest(http.MethodPost, "/api/tasks/", bytes.NewBuffer(jsonBytes))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+// TestTaskAddAssignee_Success_ShouldReturnTask tests adding a co-assignee.
+func TestTaskAddAssignee_Success_ShouldReturnTask(t *testing.T) {
+	task := stubTask
+	task.AssigneeIDs = []int64{stubTask.AssigneeID, 20}
+
+	taskService := service.MockTaskService{}
+	taskService.On("AddAssignee", mock.Anything, int64(12), int64(20)).Return(&task, nil)
+
+	router := HTTPhandler.SetupHandler(&taskService).SetupRouter()
+
+	jsonBytes, _ := json.Marshal(HTTPhandler.TaskMemberRequest{UserID: 20})
+	req, err := http.NewRequest(http.MethodPost, "/api/tasks/12/assignees", bytes.NewBuffer(jsonBytes))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var res rest.StandardResponse
+	_ = json.Unmarshal(w.Body.Bytes(), &res)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, []interface{}{float64(stubTask.AssigneeID), float64(20)}, res.Data.(map[string]interface{})["assignee_ids"])
+	taskService.AssertExpectations(t)
+}
+
+// TestTaskRemoveAssignee_Primary_ShouldReturnConflict tests that the primary assignee cannot be removed.
+func TestTaskRemoveAssignee_Primary_ShouldReturnConflict(t *testing.T) {
+	taskService := service.MockTaskService{}
+	taskService.On("RemoveAssignee", mock.Anything, int64(12), int64(10)).Return(service.ErrPrimaryAssignee)
+
+	router := HTTPhandler.SetupHandler(&taskService).SetupRouter()
+
+	req, err := http.NewRequest(http.MethodDelete, "/api/tasks/12/assignees/10", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	taskService.AssertExpectations(t)
+}
+
+// TestTaskRemoveWatcher_Success_ShouldReturnNoContent tests unsubscribing a watcher.
+func TestTaskRemoveWatcher_Success_ShouldReturnNoContent(t *testing.T) {
+	taskService := service.MockTaskService{}
+	taskService.On("RemoveWatcher", mock.Anything, int64(12), int64(5)).Return(nil)
+
+	router := HTTPhandler.SetupHandler(&taskService).SetupRouter()
+
+	req, err := http.NewRequest(http.MethodDelete, "/api/tasks/12/watchers/5", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	taskService.AssertExpectations(t)
+}
@@ -0,0 +1,81 @@
+package http_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"task-manager/internal/entities"
+	HTTPhandler "task-manager/internal/http"
+	"task-manager/internal/service"
+	"testing"
+	"time"
+)
+
+// TestTaskCreate_Recurring_ShouldPassRuleAndDueDate tests that the due date and the
+// recurrence rule reach the service and are returned to the client.
+func TestTaskCreate_Recurring_ShouldPassRuleAndDueDate(t *testing.T) {
+	dueAt := time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC)
+	recurringTask := stubTask
+	recurringTask.DueAt = &dueAt
+	recurringTask.RecurrenceRule = "FREQ=WEEKLY;BYDAY=MO"
+
+	taskService := service.MockTaskService{}
+	taskService.On(
+		"Create",
+		mock.Anything,
+		mock.MatchedBy(func(task *entities.Task) bool {
+			return task.DueAt != nil && task.DueAt.Equal(dueAt) && task.RecurrenceRule == "FREQ=WEEKLY;BYDAY=MO"
+		}),
+	).Return(&recurringTask, nil)
+
+	router := HTTPhandler.SetupHandler(&taskService).SetupRouter()
+
+	body := map[string]interface{}{
+		"title":           "Weekly report",
+		"assignee_id":     1,
+		"due_at":          "2025-01-06T09:00:00Z",
+		"recurrence_rule": "FREQ=WEEKLY;BYDAY=MO",
+	}
+	jsonBytes, _ := json.Marshal(body)
+	req, err := http.NewRequest(http.MethodPost, "/api/tasks/", bytes.NewBuffer(jsonBytes))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Contains(t, w.Body.String(), `"due_at":"2025-01-06T09:00:00Z"`)
+	assert.Contains(t, w.Body.String(), `"recurrence_rule":"FREQ=WEEKLY;BYDAY=MO"`)
+	taskService.AssertExpectations(t)
+}
+
+// TestTaskCreate_InvalidRecurrence_ShouldReturnUnprocessableEntity tests that rule
+// validation errors from the service are reported as 422.
+func TestTaskCreate_InvalidRecurrence_ShouldReturnUnprocessableEntity(t *testing.T) {
+	taskService := service.MockTaskService{}
+	taskService.On("Create", mock.Anything, mock.Anything).Return((*entities.Task)(nil), service.ErrRecurrenceRequiresDueAt)
+
+	router := HTTPhandler.SetupHandler(&taskService).SetupRouter()
+
+	body := map[string]interface{}{
+		"title":           "Weekly report",
+		"assignee_id":     1,
+		"recurrence_rule": "FREQ=WEEKLY",
+	}
+	jsonBytes, _ := json.Marshal(body)
+	req, err := http.NewRequest(http.MethodPost, "/api/tasks/", bytes.NewBuffer(jsonBytes))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	taskService.AssertExpectations(t)
+}
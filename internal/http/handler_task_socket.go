@@ -0,0 +1,377 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"sync"
+	"task-manager/internal/entities"
+	"task-manager/internal/events"
+	"task-manager/internal/stream"
+	"task-manager/internal/tenant"
+	"task-manager/pkg/rest"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+// TaskSocket upgrades the connection to a WebSocket for live task collaboration.
+//
+// @Summary Task collaboration socket
+// @Description Upgrades to a WebSocket speaking a JSON message protocol. Clients send "subscribe" (with an "id" naming
+// @Description the subscription and a "filter" using the task list filters), "unsubscribe" and "mutate" (ops
+// @Description set_status, add_assignee, remove_assignee, add_watcher, remove_watcher) messages. Every client message is
+// @Description answered with an "ack", or an "error" carrying an HTTP status code. Task events are pushed as "event"
+// @Description messages listing the matching subscriptions. Slow clients are closed with code 1013, shutdown closes
+// @Description with 1001.
+// @Tags Tasks
+// @Success 101 "Switching protocols"
+// @Failure 400 "Not a WebSocket handshake"
+// @Failure 403 "Origin not allowed"
+// @Router /api/tasks/socket [get]
+func (h *Handler) TaskSocket(c *gin.Context) {
+	h.logger.InfoWithContext(c.Request.Context(), LogIncomingTaskSocket)
+
+	upgrader := websocket.Upgrader{
+		HandshakeTimeout: ReadTimeout,
+		CheckOrigin:      h.checkSocketOrigin,
+	}
+
+	// The upgrader writes the HTTP error response itself
+	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		h.logger.ErrorWithContext(c.Request.Context(), LogTaskSocketFailed, LogKeyError, err)
+		return
+	}
+
+	h.sockets.Add(1)
+	defer h.sockets.Done()
+
+	socket := &taskSocket{
+		handler:       h,
+		conn:          conn,
+		replies:       make(chan SocketReply, SocketReplyBuffer),
+		done:          make(chan struct{}),
+		subscriptions: make(map[string]stream.Filter),
+	}
+	socket.sub, _, _ = h.TaskEvents.Subscribe("")
+
+	h.logger.InfoWithContext(c.Request.Context(), LogTaskSocketStarted)
+
+	// Only the writer writes to the connection, the reader handles the client messages
+	go socket.read(c.Request.Context())
+	socket.write(c.Request.Context())
+}
+
+// taskSocket is a single WebSocket connection. It holds one Hub subscription and
+// matches every event against the filters of its subscriptions, so the Hub fans
+// out to connections rather than to subscriptions.
+type taskSocket struct {
+	handler *Handler
+	conn    *websocket.Conn
+	sub     *stream.Subscription
+	replies chan SocketReply // Replies to client messages, sent by the writer
+	done    chan struct{}    // Closed when the reader stops
+
+	mu            sync.Mutex
+	subscriptions map[string]stream.Filter
+}
+
+// write sends the replies, the matching task events and the pings until the
+// client goes away, falls behind or the Hub is closed. ctx is the context of the
+// upgraded request, for the logs.
+func (s *taskSocket) write(ctx context.Context) {
+	h := s.handler
+	defer func() {
+		h.TaskEvents.Unsubscribe(s.sub)
+		_ = s.conn.Close()
+	}()
+
+	heartbeat := h.streamHeartbeat()
+	ping := time.NewTicker(heartbeat)
+	defer ping.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case reply := <-s.replies:
+			if err := s.send(reply); err != nil {
+				return
+			}
+		case <-ping.C:
+			if err := s.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(SocketWriteWait)); err != nil {
+				return
+			}
+		case e, ok := <-s.sub.Events():
+			if !ok {
+				if h.TaskEvents.Dropped(s.sub) {
+					h.logger.InfoWithContext(ctx, LogTaskSocketDropped)
+					s.close(websocket.CloseTryAgainLater, SocketCloseTooSlow)
+				} else {
+					s.close(websocket.CloseGoingAway, SocketCloseShutdown)
+				}
+				return
+			}
+
+			if matched := s.match(e.Event); len(matched) > 0 {
+				payload := events.NewPayload(e.Event)
+				err := s.send(SocketReply{Type: SocketEvent, EventID: e.ID, Subscriptions: matched, Event: &payload})
+				if err != nil {
+					return
+				}
+			}
+		}
+	}
+}
+
+// read handles the client messages in order until the connection fails.
+func (s *taskSocket) read(ctx context.Context) {
+	defer close(s.done)
+
+	// A missing pong means the client is gone
+	pongWait := 2 * s.handler.streamHeartbeat()
+	s.conn.SetReadLimit(SocketMaxMessageSize)
+	_ = s.conn.SetReadDeadline(time.Now().Add(pongWait))
+	s.conn.SetPongHandler(func(string) error {
+		return s.conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	for {
+		var msg SocketMessage
+		if err := s.conn.ReadJSON(&msg); err != nil {
+			if !isJSONError(err) {
+				return
+			}
+			// Malformed messages are answered, the connection stays open
+			s.reply(SocketReply{Type: SocketError, Code: http.StatusBadRequest, Error: err.Error()})
+			continue
+		}
+
+		s.reply(s.handle(ctx, msg))
+	}
+}
+
+// handle executes a client message and returns its reply.
+func (s *taskSocket) handle(ctx context.Context, msg SocketMessage) SocketReply {
+	if msg.ID == "" {
+		return socketError(msg.ID, http.StatusBadRequest, errors.New(SocketIDRequired))
+	}
+
+	switch msg.Type {
+	case SocketSubscribe:
+		return s.subscribe(ctx, msg)
+	case SocketUnsubscribe:
+		s.mu.Lock()
+		_, ok := s.subscriptions[msg.ID]
+		delete(s.subscriptions, msg.ID)
+		s.mu.Unlock()
+
+		if !ok {
+			return socketError(msg.ID, http.StatusNotFound, errors.New(SocketUnknownSubscription))
+		}
+		return SocketReply{Type: SocketAck, ID: msg.ID}
+	case SocketMutate:
+		return s.mutate(ctx, msg)
+	default:
+		return socketError(msg.ID, http.StatusBadRequest, errors.Newf(SocketUnknownType, msg.Type))
+	}
+}
+
+// subscribe validates and authorizes the filter and registers the subscription.
+// Subscribing with an existing ID replaces its filter.
+func (s *taskSocket) subscribe(ctx context.Context, msg SocketMessage) SocketReply {
+	filter, err := stream.NewFilter(msg.Filter)
+	if err != nil {
+		return socketError(msg.ID, http.StatusBadRequest, err)
+	}
+	filter = filter.WithTenant(tenant.Of(ctx))
+
+	if err := s.handler.Authorizer.Authorize(ctx, filter); err != nil {
+		s.handler.logger.ErrorWithContext(ctx, LogTaskSocketFailed, LogKeyError, err)
+		return socketError(msg.ID, http.StatusForbidden, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.subscriptions[msg.ID]; !ok && len(s.subscriptions) >= SocketMaxSubscriptions {
+		return socketError(msg.ID, http.StatusConflict, errors.New(SocketTooManySubscriptions))
+	}
+	s.subscriptions[msg.ID] = filter
+
+	return SocketReply{Type: SocketAck, ID: msg.ID}
+}
+
+// mutate applies a task mutation through the TaskService and acknowledges it with
+// the resulting task. The change itself reaches the subscribers as a task event.
+func (s *taskSocket) mutate(ctx context.Context, msg SocketMessage) SocketReply {
+	h := s.handler
+
+	var (
+		task *entities.Task
+		err  error
+	)
+
+	switch msg.Op {
+	case SocketOpSetStatus:
+		if !msg.Status.IsValid() {
+			return socketError(msg.ID, http.StatusBadRequest, errors.New(InvalidTaskStatus))
+		}
+		if task, err = h.TaskService.GetByID(ctx, msg.TaskID); err == nil {
+			task.Status = msg.Status
+			task, err = h.TaskService.Update(ctx, task)
+		}
+	case SocketOpAddAssignee:
+		task, err = h.TaskService.AddAssignee(ctx, msg.TaskID, msg.UserID)
+	case SocketOpRemoveAssignee:
+		err = h.TaskService.RemoveAssignee(ctx, msg.TaskID, msg.UserID)
+	case SocketOpAddWatcher:
+		task, err = h.TaskService.AddWatcher(ctx, msg.TaskID, msg.UserID)
+	case SocketOpRemoveWatcher:
+		err = h.TaskService.RemoveWatcher(ctx, msg.TaskID, msg.UserID)
+	default:
+		return socketError(msg.ID, http.StatusBadRequest, errors.Newf(SocketUnknownOp, msg.Op))
+	}
+
+	if err != nil {
+		// Replies carry the status of the error catalog, like the REST responses
+		apiErr := apiErrorOf(err)
+		if apiErr.Status >= http.StatusInternalServerError {
+			h.logger.ErrorWithContext(ctx, LogTaskSocketFailed, LogKeyTaskID, msg.TaskID, LogKeyError, err)
+			return socketError(msg.ID, apiErr.Status, errors.New(rest.InternalServerError.Message))
+		}
+		return socketError(msg.ID, apiErr.Status, err)
+	}
+
+	h.logger.InfoWithContext(ctx, LogTaskSocketMutation, LogKeyTaskID, msg.TaskID)
+
+	reply := SocketReply{Type: SocketAck, ID: msg.ID}
+	if task != nil {
+		response := newTaskResponse(task)
+		reply.Task = &response
+	}
+	return reply
+}
+
+// match returns the IDs of the subscriptions matching the event, sorted.
+func (s *taskSocket) match(event entities.TaskEvent) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var matched []string
+	for id, filter := range s.subscriptions {
+		if filter.Match(event) {
+			matched = append(matched, id)
+		}
+	}
+	slices.Sort(matched)
+
+	return matched
+}
+
+// reply hands a reply to the writer, unless the connection is closing.
+func (s *taskSocket) reply(reply SocketReply) {
+	select {
+	case s.replies <- reply:
+	case <-s.done:
+	}
+}
+
+// send writes a message within SocketWriteWait.
+func (s *taskSocket) send(reply SocketReply) error {
+	_ = s.conn.SetWriteDeadline(time.Now().Add(SocketWriteWait))
+	return s.conn.WriteJSON(reply)
+}
+
+// close sends a close frame; the connection is closed by the caller.
+func (s *taskSocket) close(code int, reason string) {
+	msg := websocket.FormatCloseMessage(code, reason)
+	_ = s.conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(SocketWriteWait))
+}
+
+// checkSocketOrigin allows same-origin handshakes and the configured origins ("*" allows any).
+func (h *Handler) checkSocketOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" || slices.Contains(h.config.Stream.SocketOrigins, "*") || slices.Contains(h.config.Stream.SocketOrigins, origin) {
+		return true
+	}
+
+	return origin == "http://"+r.Host || origin == "https://"+r.Host
+}
+
+// isJSONError reports whether err was caused by a malformed message rather than the connection.
+func isJSONError(err error) bool {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	return errors.As(err, &syntaxErr) || errors.As(err, &typeErr)
+}
+
+// socketError builds an error reply.
+func socketError(id string, code int, err error) SocketReply {
+	return SocketReply{Type: SocketError, ID: id, Code: code, Error: err.Error()}
+}
+
+const (
+	LogIncomingTaskSocket = "Incoming task socket request"
+	LogTaskSocketStarted  = "Task socket connected"
+	LogTaskSocketDropped  = "Task socket dropped slow client"
+	LogTaskSocketMutation = "Task socket mutation applied"
+	LogTaskSocketFailed   = "Task socket request failed"
+
+	SocketSubscribe   = "subscribe"
+	SocketUnsubscribe = "unsubscribe"
+	SocketMutate      = "mutate"
+	SocketAck         = "ack"
+	SocketError       = "error"
+	SocketEvent       = "event"
+
+	SocketOpSetStatus      = "set_status"
+	SocketOpAddAssignee    = "add_assignee"
+	SocketOpRemoveAssignee = "remove_assignee"
+	SocketOpAddWatcher     = "add_watcher"
+	SocketOpRemoveWatcher  = "remove_watcher"
+
+	SocketIDRequired           = "message id is required"
+	SocketUnknownType          = "unknown message type %q"
+	SocketUnknownOp            = "unknown mutation %q"
+	SocketUnknownSubscription  = "unknown subscription"
+	SocketTooManySubscriptions = "too many subscriptions"
+	SocketCloseTooSlow         = "too slow, reconnect"
+	SocketCloseShutdown        = "server shutting down"
+
+	// SocketMaxMessageSize is the size limit of a client message.
+	SocketMaxMessageSize = 64 << 10 // 64 KiB
+	// SocketMaxSubscriptions is the number of subscriptions a connection may hold.
+	SocketMaxSubscriptions = 100
+	// SocketReplyBuffer is the number of replies queued for the writer.
+	SocketReplyBuffer = 16
+	// SocketWriteWait is the time allowed to write a message to the client.
+	SocketWriteWait = 10 * time.Second
+)
+
+// SocketMessage is a message sent by the client.
+type SocketMessage struct {
+	Type   string              `json:"type"`              // subscribe, unsubscribe or mutate
+	ID     string              `json:"id"`                // Echoed in the reply; names the subscription for subscribe/unsubscribe
+	Filter rest.Filter         `json:"filter,omitempty"`  // subscribe: status, title, assignee_id, watcher_id
+	Op     string              `json:"op,omitempty"`      // mutate: set_status, add_assignee, remove_assignee, add_watcher, remove_watcher
+	TaskID int64               `json:"task_id,omitempty"` // mutate: the task
+	UserID int64               `json:"user_id,omitempty"` // mutate: the assignee or watcher
+	Status entities.TaskStatus `json:"status,omitempty"`  // mutate: the new status for set_status
+}
+
+// SocketReply is a message sent by the server.
+type SocketReply struct {
+	Type          string          `json:"type"`                    // ack, error or event
+	ID            string          `json:"id,omitempty"`            // ID of the answered client message
+	Task          *TaskResponse   `json:"task,omitempty"`          // ack of a mutation: the resulting task
+	Code          int             `json:"code,omitempty"`          // error: HTTP status code
+	Error         string          `json:"error,omitempty"`         // error: message
+	EventID       string          `json:"event_id,omitempty"`      // event: stream position
+	Subscriptions []string        `json:"subscriptions,omitempty"` // event: the matching subscriptions
+	Event         *events.Payload `json:"event,omitempty"`         // event: the task event
+}
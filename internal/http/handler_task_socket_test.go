@@ -0,0 +1,235 @@
+package http_test
+
+import (
+	"context"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"net/http/httptest"
+	"strings"
+	"task-manager/internal/auth"
+	"task-manager/internal/authz"
+	"task-manager/internal/entities"
+	HTTPhandler "task-manager/internal/http"
+	repoMock "task-manager/internal/repository/mock"
+	"task-manager/internal/repository/postgres"
+	"task-manager/internal/service"
+	"task-manager/internal/stream"
+	"task-manager/internal/utils"
+	"testing"
+	"time"
+)
+
+// dialTaskSocket starts a server for the handler and opens a task socket.
+func dialTaskSocket(t *testing.T, handler *HTTPhandler.Handler) *websocket.Conn {
+	t.Helper()
+
+	server := httptest.NewServer(handler.SetupRouter())
+	t.Cleanup(server.Close)
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/api/tasks/socket", nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	return conn
+}
+
+// roundTrip sends a message and reads the reply.
+func roundTrip(t *testing.T, conn *websocket.Conn, msg HTTPhandler.SocketMessage) HTTPhandler.SocketReply {
+	t.Helper()
+
+	require.NoError(t, conn.WriteJSON(msg))
+
+	var reply HTTPhandler.SocketReply
+	require.NoError(t, conn.ReadJSON(&reply))
+	return reply
+}
+
+// TestTaskSocket_Subscribe_ShouldPushMatchingEvents tests the fan-out to the matching subscriptions.
+func TestTaskSocket_Subscribe_ShouldPushMatchingEvents(t *testing.T) {
+	handler := HTTPhandler.SetupHandler(&service.MockTaskService{})
+	conn := dialTaskSocket(t, handler)
+
+	reply := roundTrip(t, conn, HTTPhandler.SocketMessage{Type: HTTPhandler.SocketSubscribe, ID: "done", Filter: map[string]string{"status": "done"}})
+	assert.Equal(t, HTTPhandler.SocketReply{Type: HTTPhandler.SocketAck, ID: "done"}, reply)
+	reply = roundTrip(t, conn, HTTPhandler.SocketMessage{Type: HTTPhandler.SocketSubscribe, ID: "mine", Filter: map[string]string{"assignee_id": "7"}})
+	assert.Equal(t, HTTPhandler.SocketAck, reply.Type)
+
+	ctx := context.Background()
+	pending := &entities.Task{ID: 1, Status: entities.TaskStatusPending, AssigneeIDs: []int64{8}}
+	done := &entities.Task{ID: 2, Status: entities.TaskStatusDone, AssigneeIDs: []int64{7}}
+	require.NoError(t, handler.TaskEvents.Publish(ctx, entities.OutboxEvent{Sequence: 1, TaskEvent: entities.TaskEvent{ID: "e1", Type: entities.TaskEventUpdated, TaskID: 1, Task: pending}}))
+	require.NoError(t, handler.TaskEvents.Publish(ctx, entities.OutboxEvent{Sequence: 2, TaskEvent: entities.TaskEvent{ID: "e2", Type: entities.TaskEventUpdated, TaskID: 2, Task: done}}))
+
+	var event HTTPhandler.SocketReply
+	require.NoError(t, conn.ReadJSON(&event))
+	assert.Equal(t, HTTPhandler.SocketEvent, event.Type)
+	assert.Equal(t, []string{"done", "mine"}, event.Subscriptions)
+	require.NotNil(t, event.Event)
+	assert.Equal(t, "e2", event.Event.ID)
+
+	reply = roundTrip(t, conn, HTTPhandler.SocketMessage{Type: HTTPhandler.SocketUnsubscribe, ID: "done"})
+	assert.Equal(t, HTTPhandler.SocketAck, reply.Type)
+	reply = roundTrip(t, conn, HTTPhandler.SocketMessage{Type: HTTPhandler.SocketUnsubscribe, ID: "done"})
+	assert.Equal(t, 404, reply.Code)
+}
+
+// TestTaskSocket_Subscribe_Forbidden tests that every subscription is authorized.
+func TestTaskSocket_Subscribe_Forbidden(t *testing.T) {
+	handler := HTTPhandler.SetupHandler(&service.MockTaskService{})
+	handler.Authorizer = stream.AuthorizerFunc(func(_ context.Context, filter stream.Filter) error {
+		if len(filter.AssigneeIDs()) == 0 {
+			return stream.ErrForbidden
+		}
+		return nil
+	})
+	conn := dialTaskSocket(t, handler)
+
+	reply := roundTrip(t, conn, HTTPhandler.SocketMessage{Type: HTTPhandler.SocketSubscribe, ID: "all"})
+	assert.Equal(t, HTTPhandler.SocketError, reply.Type)
+	assert.Equal(t, 403, reply.Code)
+
+	reply = roundTrip(t, conn, HTTPhandler.SocketMessage{Type: HTTPhandler.SocketSubscribe, ID: "mine", Filter: map[string]string{"assignee_id": "7"}})
+	assert.Equal(t, HTTPhandler.SocketAck, reply.Type)
+}
+
+// TestTaskSocket_Subscribe_PolicyWithoutTaskRead tests that the subscriptions follow the
+// task:read action of the authorization policy.
+func TestTaskSocket_Subscribe_PolicyWithoutTaskRead(t *testing.T) {
+	policy, err := authz.ParsePolicy([]byte(`
+roles:
+  triager:
+    allow: [task:assign]
+  admin:
+    allow: ["*"]
+default_roles: [triager]
+api_key_roles: [triager]
+`))
+	require.NoError(t, err)
+
+	handler := HTTPhandler.SetupHandler(&service.MockTaskService{})
+	handler.Authenticators = []auth.Authenticator{roleAuthenticator{}}
+	handler.Policy = policy
+	handler.Authorizer = stream.PolicyAuthorizer(policy)
+
+	server := httptest.NewServer(handler.SetupRouter())
+	t.Cleanup(server.Close)
+
+	for token, code := range map[string]int{authz.RoleMember: 403, authz.RoleAdmin: 0} {
+		conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/api/tasks/socket?access_token="+token, nil)
+		require.NoError(t, err)
+		_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+		reply := roundTrip(t, conn, HTTPhandler.SocketMessage{Type: HTTPhandler.SocketSubscribe, ID: "all"})
+		assert.Equal(t, code, reply.Code, token)
+		_ = conn.Close()
+	}
+}
+
+// TestTaskSocket_Mutate tests the mutations and the mapping of their errors.
+func TestTaskSocket_Mutate(t *testing.T) {
+	taskService := service.MockTaskService{}
+	task := &entities.Task{ID: 1, Title: "Ship", Status: entities.TaskStatusPending, AssigneeID: 7}
+	taskService.On("GetByID", mock.Anything, int64(1)).Return(task, nil)
+	taskService.On("GetByID", mock.Anything, int64(2)).Return((*entities.Task)(nil), postgres.ErrTaskNotFound)
+	taskService.On("Update", mock.Anything, mock.MatchedBy(func(t *entities.Task) bool {
+		return t.ID == 1 && t.Status == entities.TaskStatusDone && t.Title == "Ship"
+	})).Return(&entities.Task{ID: 1, Title: "Ship", Status: entities.TaskStatusDone, AssigneeID: 7}, nil)
+	taskService.On("RemoveAssignee", mock.Anything, int64(1), int64(7)).Return(service.ErrPrimaryAssignee)
+
+	conn := dialTaskSocket(t, HTTPhandler.SetupHandler(&taskService))
+
+	reply := roundTrip(t, conn, HTTPhandler.SocketMessage{Type: HTTPhandler.SocketMutate, ID: "m1", Op: HTTPhandler.SocketOpSetStatus, TaskID: 1, Status: entities.TaskStatusDone})
+	assert.Equal(t, HTTPhandler.SocketAck, reply.Type)
+	assert.Equal(t, "m1", reply.ID)
+	require.NotNil(t, reply.Task)
+	assert.Equal(t, entities.TaskStatusDone, reply.Task.Status)
+
+	reply = roundTrip(t, conn, HTTPhandler.SocketMessage{Type: HTTPhandler.SocketMutate, ID: "m2", Op: HTTPhandler.SocketOpSetStatus, TaskID: 2, Status: entities.TaskStatusDone})
+	assert.Equal(t, 404, reply.Code)
+
+	reply = roundTrip(t, conn, HTTPhandler.SocketMessage{Type: HTTPhandler.SocketMutate, ID: "m3", Op: HTTPhandler.SocketOpSetStatus, TaskID: 1, Status: "bogus"})
+	assert.Equal(t, 400, reply.Code)
+
+	reply = roundTrip(t, conn, HTTPhandler.SocketMessage{Type: HTTPhandler.SocketMutate, ID: "m4", Op: HTTPhandler.SocketOpRemoveAssignee, TaskID: 1, UserID: 7})
+	assert.Equal(t, 409, reply.Code)
+
+	reply = roundTrip(t, conn, HTTPhandler.SocketMessage{Type: "delete_everything", ID: "m5"})
+	assert.Equal(t, 400, reply.Code)
+
+	// Malformed messages do not end the connection
+	require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte("{not json")))
+	var malformed HTTPhandler.SocketReply
+	require.NoError(t, conn.ReadJSON(&malformed))
+	assert.Equal(t, 400, malformed.Code)
+
+	reply = roundTrip(t, conn, HTTPhandler.SocketMessage{Type: HTTPhandler.SocketSubscribe, ID: "s1"})
+	assert.Equal(t, HTTPhandler.SocketAck, reply.Type)
+}
+
+// TestTaskSocket_MutateMembership_ShouldPushToOtherConnections tests that assignee and
+// watcher changes made on one connection reach the subscribers of another one.
+func TestTaskSocket_MutateMembership_ShouldPushToOtherConnections(t *testing.T) {
+	taskRepo := &repoMock.MockTaskRepository{}
+	userRepo := &repoMock.MockUserRepository{}
+	outboxRepo := &repoMock.MockOutboxRepository{}
+
+	watched := &entities.Task{ID: 1, Title: "Ship", Status: entities.TaskStatusPending, AssigneeID: 7, AssigneeIDs: []int64{7}, WatcherIDs: []int64{9}}
+	unwatched := &entities.Task{ID: 1, Title: "Ship", Status: entities.TaskStatusPending, AssigneeID: 7, AssigneeIDs: []int64{7}}
+	userRepo.On("GetByID", mock.Anything, int64(9)).Return(&entities.User{ID: 9, IsActive: true}, nil)
+	taskRepo.On("GetForUpdate", mock.Anything, int64(1)).Return(unwatched, nil)
+	taskRepo.On("AddWatcher", mock.Anything, int64(1), int64(9)).Return(nil)
+	taskRepo.On("RemoveWatcher", mock.Anything, int64(1), int64(9)).Return(nil)
+	taskRepo.On("GetByID", mock.Anything, int64(1)).Return(watched, nil).Once()
+	taskRepo.On("GetByID", mock.Anything, int64(1)).Return(unwatched, nil).Once()
+
+	handler := HTTPhandler.SetupHandler(&service.MockTaskService{})
+	handler.TaskService = service.NewTaskService(taskRepo, userRepo, utils.InitGlobalTaskMetrics(),
+		service.WithOutbox(&repoMock.MockTransactor{}, outboxRepo))
+
+	// The recorded events reach the hub like the stream tailer would pass them on
+	var sequence int64
+	outboxRepo.On("Append", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		for _, event := range args.Get(1).([]entities.TaskEvent) {
+			sequence++
+			require.NoError(t, handler.TaskEvents.Publish(context.Background(), entities.OutboxEvent{Sequence: sequence, TaskEvent: event}))
+		}
+	}).Return(nil)
+
+	follower := dialTaskSocket(t, handler)
+	mutator := dialTaskSocket(t, handler)
+	reply := roundTrip(t, follower, HTTPhandler.SocketMessage{Type: HTTPhandler.SocketSubscribe, ID: "all"})
+	require.Equal(t, HTTPhandler.SocketAck, reply.Type)
+
+	reply = roundTrip(t, mutator, HTTPhandler.SocketMessage{Type: HTTPhandler.SocketMutate, ID: "m1", Op: HTTPhandler.SocketOpAddWatcher, TaskID: 1, UserID: 9})
+	require.Equal(t, HTTPhandler.SocketAck, reply.Type)
+	reply = roundTrip(t, mutator, HTTPhandler.SocketMessage{Type: HTTPhandler.SocketMutate, ID: "m2", Op: HTTPhandler.SocketOpRemoveWatcher, TaskID: 1, UserID: 9})
+	require.Equal(t, HTTPhandler.SocketAck, reply.Type)
+
+	var event HTTPhandler.SocketReply
+	require.NoError(t, follower.ReadJSON(&event))
+	assert.Equal(t, HTTPhandler.SocketEvent, event.Type)
+	assert.Equal(t, "1", event.EventID)
+	assert.Equal(t, entities.TaskEventUpdated, event.Event.Type)
+	assert.Equal(t, []int64{9}, event.Event.Data.Task.WatcherIDs)
+
+	require.NoError(t, follower.ReadJSON(&event))
+	assert.Equal(t, "2", event.EventID)
+	assert.Equal(t, entities.TaskEventUpdated, event.Event.Type)
+	assert.Empty(t, event.Event.Data.Task.WatcherIDs)
+}
+
+// TestTaskSocket_HubClosed_ShouldCloseGoingAway tests the close frame sent on shutdown.
+func TestTaskSocket_HubClosed_ShouldCloseGoingAway(t *testing.T) {
+	handler := HTTPhandler.SetupHandler(&service.MockTaskService{})
+	conn := dialTaskSocket(t, handler)
+
+	// The connection is registered once the first message is answered
+	roundTrip(t, conn, HTTPhandler.SocketMessage{Type: HTTPhandler.SocketSubscribe, ID: "all"})
+	handler.TaskEvents.Close()
+
+	_, _, err := conn.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.CloseGoingAway), err)
+}
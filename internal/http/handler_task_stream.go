@@ -0,0 +1,141 @@
+package http
+
+import (
+	"net/http"
+	"task-manager/internal/events"
+	"task-manager/internal/stream"
+	"task-manager/internal/tenant"
+	"task-manager/pkg/rest"
+	"time"
+
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+)
+
+// TaskStream streams live task changes as Server-Sent Events.
+//
+// @Summary Stream task changes
+// @Description Pushes task.created, task.updated, task.status_changed and task.deleted events as Server-Sent Events,
+// @Description filtered by the same query parameters as the task list. Every event carries an ID; reconnecting with
+// @Description the Last-Event-ID header replays the events missed in between from a bounded buffer. The first event
+// @Description is "ready"; its "reset" flag is set when the missed events are no longer available and the client has
+// @Description to reload the task list. Heartbeat comments are sent while idle. Clients that do not keep up are
+// @Description disconnected and resume with Last-Event-ID.
+// @Tags Tasks
+// @Produce text/event-stream
+// @Param Last-Event-ID header string false "ID of the last received event"
+// @Param status query string false "Only tasks with this status"
+// @Param title query string false "Only tasks with this title"
+// @Param assignee_id query string false "Comma separated user IDs, tasks assigned to any of them"
+// @Param watcher_id query string false "Comma separated user IDs, tasks watched by any of them"
+// @Success 200 {object} events.Payload "Stream of task events"
+// @Failure 400 {object} rest.StandardResponse{data=nil} "Invalid filter"
+// @Failure 403 {object} rest.StandardResponse{data=nil} "Subscription not allowed by the authorization policy"
+// @Router /api/tasks/stream [get]
+func (h *Handler) TaskStream(c *gin.Context) {
+	h.logger.InfoWithContext(c.Request.Context(), LogIncomingTaskStream)
+
+	filter, err := stream.NewFilter(rest.ParseQuery(c).Filter)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	filter = filter.WithTenant(tenant.Of(c.Request.Context()))
+
+	if err := h.Authorizer.Authorize(c.Request.Context(), filter); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	sub, replay, resumed := h.TaskEvents.Subscribe(c.GetHeader(LastEventIDHeader))
+	defer h.TaskEvents.Unsubscribe(sub)
+
+	// The stream outlives the server write timeout
+	if err := http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{}); err != nil {
+		h.logger.ErrorWithContext(c.Request.Context(), LogTaskStreamFailed, LogKeyError, err)
+	}
+
+	header := c.Writer.Header()
+	header.Set("Content-Type", sse.ContentType)
+	header.Set("Cache-Control", "no-cache")
+	header.Set("Connection", "keep-alive")
+	header.Set("X-Accel-Buffering", "no") // disable proxy buffering (nginx)
+	c.Status(http.StatusOK)
+
+	_ = sse.Encode(c.Writer, sse.Event{
+		Event: StreamEventReady,
+		Retry: uint(StreamRetry.Milliseconds()),
+		Data:  StreamReady{Reset: !resumed},
+	})
+	for _, e := range replay {
+		h.writeTaskEvent(c, filter, e)
+	}
+	c.Writer.Flush()
+
+	h.logger.InfoWithContext(c.Request.Context(), LogTaskStreamStarted)
+
+	heartbeat := time.NewTicker(h.streamHeartbeat())
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case <-heartbeat.C:
+			_, _ = c.Writer.WriteString(": heartbeat\n\n")
+			c.Writer.Flush()
+		case e, ok := <-sub.Events():
+			if !ok {
+				if h.TaskEvents.Dropped(sub) {
+					h.logger.InfoWithContext(c.Request.Context(), LogTaskStreamDropped)
+				}
+				return
+			}
+			h.writeTaskEvent(c, filter, e)
+			c.Writer.Flush()
+			heartbeat.Reset(h.streamHeartbeat())
+		}
+	}
+}
+
+// writeTaskEvent writes a task event to the stream if it passes the filter. Filtered
+// out events only advance the client's Last-Event-ID, which keeps it resumable.
+func (h *Handler) writeTaskEvent(c *gin.Context, filter stream.Filter, e stream.Event) {
+	if !filter.Match(e.Event) {
+		_, _ = c.Writer.WriteString("id:" + e.ID + "\n\n")
+		return
+	}
+
+	_ = sse.Encode(c.Writer, sse.Event{
+		Id:    e.ID,
+		Event: string(e.Event.Type),
+		Data:  events.NewPayload(e.Event),
+	})
+}
+
+// streamHeartbeat returns the configured heartbeat interval, or DefaultStreamHeartbeat.
+func (h *Handler) streamHeartbeat() time.Duration {
+	if h.config.Stream.Heartbeat > 0 {
+		return h.config.Stream.Heartbeat
+	}
+	return DefaultStreamHeartbeat
+}
+
+const (
+	LogIncomingTaskStream = "Incoming task stream request"
+	LogTaskStreamStarted  = "Task stream started"
+	LogTaskStreamDropped  = "Task stream dropped slow client"
+	LogTaskStreamFailed   = "Failed to stream tasks"
+
+	LastEventIDHeader = "Last-Event-ID"
+	StreamEventReady  = "ready"
+
+	// DefaultStreamHeartbeat is the idle time after which a heartbeat comment is sent.
+	DefaultStreamHeartbeat = 15 * time.Second
+	// StreamRetry is the reconnection delay advertised to clients.
+	StreamRetry = 3 * time.Second
+)
+
+type StreamReady struct {
+	Reset bool `json:"reset"` // missed events are unavailable, reload the task list
+}
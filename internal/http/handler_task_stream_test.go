@@ -0,0 +1,120 @@
+package http_test
+
+import (
+	"bufio"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"task-manager/internal/entities"
+	HTTPhandler "task-manager/internal/http"
+	"task-manager/internal/service"
+	"task-manager/internal/stream"
+	"testing"
+	"time"
+)
+
+// readSSEEvent reads the next event from the stream, skipping comments and ID-only events.
+func readSSEEvent(t *testing.T, r *bufio.Reader) (id, event, data string) {
+	t.Helper()
+	for {
+		line, err := r.ReadString('\n')
+		require.NoError(t, err)
+
+		line = strings.TrimRight(line, "\n")
+		switch {
+		case line == "":
+			if event != "" {
+				return id, event, data
+			}
+		case strings.HasPrefix(line, "id:"):
+			id = strings.TrimPrefix(line, "id:")
+		case strings.HasPrefix(line, "event:"):
+			event = strings.TrimPrefix(line, "event:")
+		case strings.HasPrefix(line, "data:"):
+			data = strings.TrimPrefix(line, "data:")
+		}
+	}
+}
+
+// TestTaskStream_ShouldStreamFilteredEvents tests that only events of matching tasks are sent.
+func TestTaskStream_ShouldStreamFilteredEvents(t *testing.T) {
+	handler := HTTPhandler.SetupHandler(&service.MockTaskService{})
+	server := httptest.NewServer(handler.SetupRouter())
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/api/tasks/stream?status=done", nil)
+	res, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer res.Body.Close()
+
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Contains(t, res.Header.Get("Content-Type"), "text/event-stream")
+
+	body := bufio.NewReader(res.Body)
+	_, event, data := readSSEEvent(t, body)
+	assert.Equal(t, HTTPhandler.StreamEventReady, event)
+	assert.JSONEq(t, `{"reset":false}`, data)
+
+	pending := &entities.Task{ID: 1, Title: "Write docs", Status: entities.TaskStatusPending}
+	done := &entities.Task{ID: 2, Title: "Ship", Status: entities.TaskStatusDone}
+	require.NoError(t, handler.TaskEvents.Publish(ctx, entities.OutboxEvent{Sequence: 1, TaskEvent: entities.TaskEvent{ID: "e1", Type: entities.TaskEventUpdated, TaskID: 1, Task: pending}}))
+	require.NoError(t, handler.TaskEvents.Publish(ctx, entities.OutboxEvent{Sequence: 2, TaskEvent: entities.TaskEvent{ID: "e2", Type: entities.TaskEventUpdated, TaskID: 2, Task: done}}))
+
+	id, event, data := readSSEEvent(t, body)
+	assert.NotEmpty(t, id)
+	assert.Equal(t, string(entities.TaskEventUpdated), event)
+	assert.Contains(t, data, `"id":"e2"`)
+}
+
+// TestTaskStream_UnknownLastEventID_ShouldReset tests that clients are told to reload when events cannot be replayed.
+func TestTaskStream_UnknownLastEventID_ShouldReset(t *testing.T) {
+	server := httptest.NewServer(HTTPhandler.SetupHandler(&service.MockTaskService{}).SetupRouter())
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/api/tasks/stream", nil)
+	req.Header.Set(HTTPhandler.LastEventIDHeader, "unknown-42")
+	res, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer res.Body.Close()
+
+	_, event, data := readSSEEvent(t, bufio.NewReader(res.Body))
+	assert.Equal(t, HTTPhandler.StreamEventReady, event)
+	assert.JSONEq(t, `{"reset":true}`, data)
+}
+
+// TestTaskStream_InvalidFilter_ShouldReturnBadRequest tests the validation of the filter.
+func TestTaskStream_InvalidFilter_ShouldReturnBadRequest(t *testing.T) {
+	router := HTTPhandler.SetupHandler(&service.MockTaskService{}).SetupRouter()
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/tasks/stream?assignee_id=abc", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+// TestTaskStream_Denied_ShouldReturnForbidden tests that the subscription is authorized
+// before the stream starts.
+func TestTaskStream_Denied_ShouldReturnForbidden(t *testing.T) {
+	handler := HTTPhandler.SetupHandler(&service.MockTaskService{})
+	handler.Authorizer = stream.AuthorizerFunc(func(context.Context, stream.Filter) error {
+		return stream.ErrForbidden
+	})
+	router := handler.SetupRouter()
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/tasks/stream", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.NotContains(t, w.Header().Get("Content-Type"), "text/event-stream")
+}
@@ -2,15 +2,23 @@ package http_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"net/http"
 	"net/http/httptest"
+	"strings"
+	"task-manager/internal/auth"
+	"task-manager/internal/authz"
+	"task-manager/internal/cache"
 	"task-manager/internal/entities"
 	HTTPhandler "task-manager/internal/http"
+	"task-manager/internal/idempotency"
 	"task-manager/internal/service"
+	"task-manager/internal/tenant"
 	"task-manager/pkg/rest"
 	"testing"
 	"time"
@@ -162,3 +170,201 @@ func TestTaskCreate_InvalidStatus_ShouldReturnBadRequest(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 	assert.Equal(t, rest.Fail, res.Status)
 }
+
+// TestTaskDelete_Forbidden_ShouldReturnForbidden tests that the principal of the request
+// reaches the TaskService, and that its authorization denial is answered with 403.
+func TestTaskDelete_Forbidden_ShouldReturnForbidden(t *testing.T) {
+	taskService := service.MockTaskService{}
+	taskService.On("Delete", mock.MatchedBy(func(ctx context.Context) bool {
+		principal, ok := auth.PrincipalFromContext(ctx)
+		return ok && principal.UserID == 42
+	}), int64(1)).Return(fmt.Errorf("%w: your roles do not allow task:delete", authz.ErrForbidden))
+
+	handler := HTTPhandler.SetupHandler(&taskService)
+	handler.Authenticators = []auth.Authenticator{tokenAuthenticator{}}
+	router := handler.SetupRouter()
+
+	req, _ := http.NewRequest(http.MethodDelete, "/api/tasks/1", nil)
+	req.Header.Set("Authorization", "Bearer valid")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var response rest.StandardResponse
+	_ = json.Unmarshal(w.Body.Bytes(), &response)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Equal(t, HTTPhandler.Forbidden, response.Message)
+	taskService.AssertExpectations(t)
+}
+
+// TestTaskList_Tenant tests that the tenant of the principal reaches the TaskService,
+// and that requesting another tenant is forbidden.
+func TestTaskList_Tenant(t *testing.T) {
+	taskService := service.MockTaskService{}
+	taskService.On("List", mock.MatchedBy(func(ctx context.Context) bool {
+		return tenant.Of(ctx) == tenant.Default
+	}), mock.Anything).Return([]entities.Task{}, 0, nil).Once()
+
+	handler := HTTPhandler.SetupHandler(&taskService)
+	handler.Authenticators = []auth.Authenticator{tokenAuthenticator{}}
+	router := handler.SetupRouter()
+
+	tests := []struct {
+		name   string
+		header string
+		code   int
+	}{
+		{"own tenant", "", http.StatusOK},
+		{"foreign tenant", "acme", http.StatusForbidden},
+		{"invalid tenant", "Not Valid", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodGet, "/api/tasks/", nil)
+			req.Header.Set("Authorization", "Bearer valid")
+			if tt.header != "" {
+				req.Header.Set(tenant.Header, tt.header)
+			}
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.code, w.Code)
+		})
+	}
+
+	taskService.AssertExpectations(t)
+}
+
+// TestTaskGetByID_ForeignTenant_ShouldRequirePlatformAdmin tests that the admins of a
+// tenant can't read the tasks of another one, only the platform admins can.
+func TestTaskGetByID_ForeignTenant_ShouldRequirePlatformAdmin(t *testing.T) {
+	taskService := service.MockTaskService{}
+	taskService.On("GetByID", mock.MatchedBy(func(ctx context.Context) bool {
+		return tenant.Of(ctx) == "acme"
+	}), int64(1)).Return(&stubTask, nil).Once()
+
+	handler := HTTPhandler.SetupHandler(&taskService)
+	handler.Authenticators = []auth.Authenticator{roleAuthenticator{}}
+	router := handler.SetupRouter()
+
+	tests := []struct {
+		token string
+		code  int
+	}{
+		{authz.RoleAdmin, http.StatusForbidden},
+		{auth.RolePlatformAdmin, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.token, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodGet, "/api/tasks/1", nil)
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+			req.Header.Set(tenant.Header, "acme")
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.code, w.Code)
+		})
+	}
+
+	taskService.AssertExpectations(t)
+}
+
+// TestTaskCreate_IdempotencyKey tests that retries of a creation replay its response
+// instead of creating the task again, and that a key can't be reused for another task.
+func TestTaskCreate_IdempotencyKey(t *testing.T) {
+	taskService := service.MockTaskService{}
+	taskService.On("Create", mock.Anything, mock.AnythingOfType("*entities.Task")).Return(&stubTask, nil).Once()
+
+	body := HTTPhandler.CreateTaskRequest{Title: "Test", Status: entities.TaskStatusPending, AssigneeID: 10}
+	store := idempotency.NewCacheStore(cache.NewMemory())
+	handler := HTTPhandler.SetupHandler(&taskService)
+	handler.Idempotency = store
+	router := handler.SetupRouter()
+
+	post := func(key string, body any) *httptest.ResponseRecorder {
+		jsonBytes, _ := json.Marshal(body)
+		req, _ := http.NewRequest(http.MethodPost, "/api/tasks/", bytes.NewBuffer(jsonBytes))
+		req.Header.Set(idempotency.Header, key)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	first := post("create-1", body)
+	assert.Equal(t, http.StatusCreated, first.Code)
+	assert.Empty(t, first.Header().Get(idempotency.ReplayedHeader))
+
+	retry := post("create-1", body)
+	assert.Equal(t, http.StatusCreated, retry.Code)
+	assert.Equal(t, "true", retry.Header().Get(idempotency.ReplayedHeader))
+	assert.Equal(t, first.Header().Get("Content-Type"), retry.Header().Get("Content-Type"))
+	assert.JSONEq(t, first.Body.String(), retry.Body.String())
+
+	other := body
+	other.Title = "Another task"
+	assert.Equal(t, http.StatusUnprocessableEntity, post("create-1", other).Code)
+
+	// A request with the key is still running
+	_, _ = store.Reserve(context.Background(), idempotency.ScopedKey("default", "ip:", "create-2"),
+		idempotency.Fingerprint(http.MethodPost, "/api/tasks/", mustJSON(body)), time.Hour)
+	inFlight := post("create-2", body)
+	assert.Equal(t, http.StatusConflict, inFlight.Code)
+	assert.Equal(t, "1", inFlight.Header().Get("Retry-After"))
+
+	taskService.AssertExpectations(t)
+}
+
+// TestTaskCreate_IdempotencyKeyFailed_ShouldRunRetry tests that failed requests are not
+// replayed, their retries run.
+func TestTaskCreate_IdempotencyKeyFailed_ShouldRunRetry(t *testing.T) {
+	taskService := service.MockTaskService{}
+	taskService.On("Create", mock.Anything, mock.AnythingOfType("*entities.Task")).Return((*entities.Task)(nil), errors.New("connection refused")).Once()
+	taskService.On("Create", mock.Anything, mock.AnythingOfType("*entities.Task")).Return(&stubTask, nil).Once()
+
+	body := HTTPhandler.CreateTaskRequest{Title: "Test", Status: entities.TaskStatusPending, AssigneeID: 10}
+	handler := HTTPhandler.SetupHandler(&taskService)
+	handler.Idempotency = idempotency.NewCacheStore(cache.NewMemory())
+	router := handler.SetupRouter()
+
+	for _, want := range []int{http.StatusInternalServerError, http.StatusCreated} {
+		req, _ := http.NewRequest(http.MethodPost, "/api/tasks/", bytes.NewBuffer(mustJSON(body)))
+		req.Header.Set(idempotency.Header, "create-1")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, want, w.Code)
+		assert.Empty(t, w.Header().Get(idempotency.ReplayedHeader))
+	}
+
+	taskService.AssertExpectations(t)
+}
+
+// TestTaskCreate_IdempotencyKeyBodyTooLarge_ShouldReturn413 tests that the body of an
+// idempotent request is read up to MaxBodySize.
+func TestTaskCreate_IdempotencyKeyBodyTooLarge_ShouldReturn413(t *testing.T) {
+	taskService := service.MockTaskService{}
+	handler := HTTPhandler.SetupHandler(&taskService)
+	handler.Idempotency = idempotency.NewCacheStore(cache.NewMemory())
+	router := handler.SetupRouter()
+
+	body := `{"title": "` + strings.Repeat("x", HTTPhandler.MaxBodySize) + `"}`
+	req, _ := http.NewRequest(http.MethodPost, "/api/tasks/", strings.NewReader(body))
+	req.Header.Set(idempotency.Header, "create-1")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"BODY_TOO_LARGE"`)
+	taskService.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+// mustJSON encodes v as JSON.
+func mustJSON(v any) []byte {
+	data, _ := json.Marshal(v)
+	return data
+}
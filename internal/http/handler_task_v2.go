@@ -0,0 +1,266 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"task-manager/internal/entities"
+	"time"
+)
+
+// TaskCreateV2 handles the creation of a new task.
+//
+// @Summary Create a new task
+// @Description Creates a new task with a title, description, status, and assignees, the first one being the primary assignee.
+//
+//	If status is not provided, it defaults to "pending".
+//
+// @Tags Tasks
+// @Accept json
+// @Produce json
+// @Param request body CreateTaskRequestV2 true "Task creation payload"
+// @Success 201 {object} rest.StandardResponse{data=TaskResponseV2} "Task successfully created"
+// @Failure 400 {object} rest.StandardResponse{data=nil} "Invalid request payload or invalid task status"
+// @Failure 422 {object} rest.StandardResponse{data=nil} "Assignee or watcher does not exist, assignee is deactivated, or invalid recurrence"
+// @Failure 403 {object} rest.StandardResponse{data=nil} "Operation not allowed by the authorization policy"
+// @Failure 500 {object} rest.StandardResponse{data=nil} "Internal server error"
+// @Router /api/v2/tasks/ [post]
+func (h *Handler) TaskCreateV2(c *gin.Context) {
+	h.createTask(c, taskMapperV2{})
+}
+
+// TaskUpdateV2 updates an existing task by its ID.
+//
+// @Summary Update an existing task
+// @Description Replaces the details of an existing task such as title, description, status, and assignees.
+// @Tags Tasks
+// @Accept json
+// @Produce json
+// @Param id path int true "Task ID"
+// @Param request body UpdateTaskRequestV2 true "Updated task data"
+// @Success 200 {object} rest.StandardResponse{data=TaskResponseV2} "Task successfully updated"
+// @Failure 400 {object} rest.StandardResponse{data=nil} "Invalid task ID or request payload"
+// @Failure 404 {object} rest.StandardResponse{data=nil} "Task not found"
+// @Failure 422 {object} rest.StandardResponse{data=nil} "Assignee or watcher does not exist, assignee is deactivated, or invalid recurrence"
+// @Failure 403 {object} rest.StandardResponse{data=nil} "Operation not allowed by the authorization policy"
+// @Failure 500 {object} rest.StandardResponse{data=nil} "Internal server error"
+// @Router /api/v2/tasks/{id} [put]
+func (h *Handler) TaskUpdateV2(c *gin.Context) {
+	h.updateTask(c, taskMapperV2{})
+}
+
+// TaskGetByIDV2 retrieves a task by its ID.
+//
+// @Summary Get task by ID
+// @Description Retrieves the details of a specific task using its ID.
+// @Tags Tasks
+// @Accept json
+// @Produce json
+// @Param id path int true "Task ID"
+// @Param include query string false "Comma separated related resources to embed (assignee)"
+// @Success 200 {object} rest.StandardResponse{data=TaskResponseV2} "Task successfully fetched"
+// @Failure 400 {object} rest.StandardResponse{data=nil} "Invalid task ID"
+// @Failure 404 {object} rest.StandardResponse{data=nil} "Task not found"
+// @Failure 403 {object} rest.StandardResponse{data=nil} "Operation not allowed by the authorization policy"
+// @Failure 500 {object} rest.StandardResponse{data=nil} "Internal server error"
+// @Router /api/v2/tasks/{id} [get]
+func (h *Handler) TaskGetByIDV2(c *gin.Context) {
+	h.getTask(c, taskMapperV2{})
+}
+
+// TaskListV2 retrieves a paginated list of tasks.
+//
+// @Summary List tasks
+// @Description Retrieves a paginated list of tasks with optional filters.
+// @Tags Tasks
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param per_page query int false "Number of tasks per page" default(20)
+// @Param filter query string false "Filter by any task field (e.g., title, status)"
+// @Param assignee_id query string false "Comma separated user IDs, matches tasks assigned to any of them"
+// @Param watcher_id query string false "Comma separated user IDs, matches tasks watched by any of them"
+// @Param include query string false "Comma separated related resources to embed (assignee)"
+// @Success 200 {object} rest.StandardResponse{data=[]TaskResponseV2, meta=rest.PaginationMeta} "List of tasks successfully fetched"
+// @Failure 403 {object} rest.StandardResponse{data=nil} "Operation not allowed by the authorization policy"
+// @Failure 500 {object} rest.StandardResponse{data=nil} "Internal server error"
+// @Router /api/v2/tasks [get]
+func (h *Handler) TaskListV2(c *gin.Context) {
+	h.listTasks(c, taskMapperV2{})
+}
+
+// TaskAddAssigneeV2 adds a co-assignee to a task.
+//
+// @Summary Add a task assignee
+// @Description Adds an active user as co-assignee of the task. Adding an existing assignee is a no-op.
+// @Tags Tasks
+// @Accept json
+// @Produce json
+// @Param id path int true "Task ID"
+// @Param request body TaskMemberRequest true "User to assign"
+// @Success 200 {object} rest.StandardResponse{data=TaskResponseV2} "Assignee successfully added"
+// @Failure 400 {object} rest.StandardResponse{data=nil} "Invalid task ID or request payload"
+// @Failure 404 {object} rest.StandardResponse{data=nil} "Task not found"
+// @Failure 422 {object} rest.StandardResponse{data=nil} "User does not exist or is deactivated"
+// @Failure 403 {object} rest.StandardResponse{data=nil} "Operation not allowed by the authorization policy"
+// @Failure 500 {object} rest.StandardResponse{data=nil} "Internal server error"
+// @Router /api/v2/tasks/{id}/assignees [post]
+func (h *Handler) TaskAddAssigneeV2(c *gin.Context) {
+	h.addTaskMember(c, LogIncomingTaskAssigneeAdd, h.TaskService.AddAssignee, taskMapperV2{})
+}
+
+// TaskAddWatcherV2 subscribes a user to a task.
+//
+// @Summary Add a task watcher
+// @Description Subscribes an existing user to the task. Adding an existing watcher is a no-op.
+// @Tags Tasks
+// @Accept json
+// @Produce json
+// @Param id path int true "Task ID"
+// @Param request body TaskMemberRequest true "User to subscribe"
+// @Success 200 {object} rest.StandardResponse{data=TaskResponseV2} "Watcher successfully added"
+// @Failure 400 {object} rest.StandardResponse{data=nil} "Invalid task ID or request payload"
+// @Failure 404 {object} rest.StandardResponse{data=nil} "Task not found"
+// @Failure 422 {object} rest.StandardResponse{data=nil} "User does not exist"
+// @Failure 403 {object} rest.StandardResponse{data=nil} "Operation not allowed by the authorization policy"
+// @Failure 500 {object} rest.StandardResponse{data=nil} "Internal server error"
+// @Router /api/v2/tasks/{id}/watchers [post]
+func (h *Handler) TaskAddWatcherV2(c *gin.Context) {
+	h.addTaskMember(c, LogIncomingTaskWatcherAdd, h.TaskService.AddWatcher, taskMapperV2{})
+}
+
+// taskMapperV2 maps the task DTOs of the v2 API.
+type taskMapperV2 struct{}
+
+// CreateTask binds a CreateTaskRequestV2. The status defaults to pending.
+func (taskMapperV2) CreateTask(c *gin.Context) (*entities.Task, error) {
+	var req CreateTaskRequestV2
+	if err := bindJSON(c, &req); err != nil {
+		return nil, err
+	}
+
+	if req.Status == "" {
+		req.Status = entities.TaskStatusPending
+	}
+
+	if !req.Status.IsValid() {
+		return nil, ErrInvalidTaskStatus
+	}
+
+	return &entities.Task{
+		Title:       req.Title,
+		Description: req.Description,
+		Status:      req.Status,
+		AssigneeID:  req.AssigneeIDs[0],
+		AssigneeIDs: req.AssigneeIDs,
+		WatcherIDs:  req.WatcherIDs,
+		DueAt:       req.DueAt,
+
+		RecurrenceRule: req.RecurrenceRule,
+	}, nil
+}
+
+// UpdateTask binds an UpdateTaskRequestV2.
+func (taskMapperV2) UpdateTask(c *gin.Context, id int64) (*entities.Task, error) {
+	var req UpdateTaskRequestV2
+	if err := bindJSON(c, &req); err != nil {
+		return nil, err
+	}
+
+	return &entities.Task{
+		ID:          id,
+		Title:       req.Title,
+		Description: req.Description,
+		Status:      req.Status,
+		AssigneeID:  req.AssigneeIDs[0],
+		AssigneeIDs: req.AssigneeIDs,
+		WatcherIDs:  req.WatcherIDs,
+		DueAt:       req.DueAt,
+
+		RecurrenceRule: req.RecurrenceRule,
+	}, nil
+}
+
+// TaskResponse maps a task to a TaskResponseV2.
+func (taskMapperV2) TaskResponse(task *entities.Task, assignees map[int64]*AssigneeResponse) any {
+	return TaskResponseV2{
+		ID:          task.ID,
+		Title:       task.Title,
+		Description: task.Description,
+		Status:      task.Status,
+		AssigneeIDs: primaryFirst(task.AssigneeID, task.AssigneeIDs),
+		WatcherIDs:  nonNil(task.WatcherIDs),
+		Assignee:    assignees[task.AssigneeID],
+		DueAt:       task.DueAt,
+		CreatedAt:   task.CreatedAt,
+		UpdatedAt:   task.UpdatedAt,
+
+		RecurrenceRule:     task.RecurrenceRule,
+		RecurrenceParentID: task.RecurrenceParentID,
+	}
+}
+
+// primaryFirst returns the assignees with the primary assignee first.
+func primaryFirst(primary int64, assigneeIDs []int64) []int64 {
+	ids := make([]int64, 0, len(assigneeIDs)+1)
+	if primary != 0 {
+		ids = append(ids, primary)
+	}
+	for _, id := range assigneeIDs {
+		if id != primary {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// nonNil returns the IDs, an empty slice if nil, so they are never null in JSON.
+func nonNil(ids []int64) []int64 {
+	if ids == nil {
+		return []int64{}
+	}
+	return ids
+}
+
+// CreateTaskRequestV2 differs from CreateTaskRequest by assignee_ids, whose first
+// assignee is the primary assignee, replacing assignee_id.
+type CreateTaskRequestV2 struct {
+	Title       string              `json:"title" binding:"required"`
+	Description string              `json:"description,omitempty"`
+	Status      entities.TaskStatus `json:"status,omitempty"`                      // optional, default pending
+	AssigneeIDs []int64             `json:"assignee_ids" binding:"required,min=1"` // the first one is the primary assignee
+	WatcherIDs  []int64             `json:"watcher_ids,omitempty"`                 // optional watchers
+	DueAt       *time.Time          `json:"due_at,omitempty"`                      // optional deadline, RFC 3339
+
+	RecurrenceRule string `json:"recurrence_rule,omitempty"` // optional RRULE, e.g. FREQ=WEEKLY;BYDAY=MO, requires due_at
+}
+
+// UpdateTaskRequestV2 differs from UpdateTaskRequest by assignee_ids, whose first
+// assignee is the primary assignee, replacing assignee_id.
+type UpdateTaskRequestV2 struct {
+	Title       string              `json:"title" binding:"required"`
+	Description string              `json:"description,omitempty"`
+	Status      entities.TaskStatus `json:"status" binding:"required,oneof=pending in_progress done canceled"`
+	AssigneeIDs []int64             `json:"assignee_ids" binding:"required,min=1"` // replaces the assignees, the first one is the primary assignee
+	WatcherIDs  []int64             `json:"watcher_ids,omitempty"`                 // replaces watchers when present
+	DueAt       *time.Time          `json:"due_at,omitempty"`                      // clears the deadline when absent
+
+	RecurrenceRule string `json:"recurrence_rule,omitempty"` // stops the recurrence when absent
+}
+
+// TaskResponseV2 differs from TaskResponse by assignee_ids, listing the primary
+// assignee first and replacing assignee_id, and by fields always present: empty
+// description, null due_at and timestamps with their fractional seconds.
+type TaskResponseV2 struct {
+	ID          int64               `json:"id"`
+	Title       string              `json:"title"`
+	Description string              `json:"description"`
+	Status      entities.TaskStatus `json:"status"`
+	AssigneeIDs []int64             `json:"assignee_ids"`       // the first one is the primary assignee
+	WatcherIDs  []int64             `json:"watcher_ids"`        //
+	Assignee    *AssigneeResponse   `json:"assignee,omitempty"` // primary assignee, only with ?include=assignee
+	DueAt       *time.Time          `json:"due_at"`
+	CreatedAt   time.Time           `json:"created_at"`
+	UpdatedAt   time.Time           `json:"updated_at"`
+
+	RecurrenceRule     string `json:"recurrence_rule,omitempty"`
+	RecurrenceParentID *int64 `json:"recurrence_parent_id,omitempty"` // occurrence this task was created from
+}
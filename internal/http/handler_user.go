@@ -0,0 +1,258 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"task-manager/internal/entities"
+	"task-manager/pkg/rest"
+	"time"
+)
+
+// UserCreate handles the creation of a new user.
+//
+// @Summary Create a new user
+// @Description Creates a new user that tasks can be assigned to. Users are active by default.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param request body CreateUserRequest true "User creation payload"
+// @Success 201 {object} rest.StandardResponse{data=UserResponse} "User successfully created"
+// @Failure 400 {object} rest.StandardResponse{data=nil} "Invalid request payload"
+// @Failure 403 {object} rest.StandardResponse{data=nil} "user:write not allowed"
+// @Failure 409 {object} rest.StandardResponse{data=nil} "Email already exists"
+// @Failure 500 {object} rest.StandardResponse{data=nil} "Internal server error"
+// @Router /api/users/ [post]
+func (h *Handler) UserCreate(c *gin.Context) {
+	h.logger.InfoWithContext(c.Request.Context(), LogIncomingUserCreate)
+
+	var req CreateUserRequest
+	if err := bindJSON(c, &req); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	user := &entities.User{
+		Name:     req.Name,
+		Email:    req.Email,
+		IsActive: true,
+	}
+	if req.IsActive != nil {
+		user.IsActive = *req.IsActive
+	}
+
+	createdUser, err := h.UserService.Create(c, user)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	h.logger.InfoWithContext(c.Request.Context(), LogUserCreateSuccess, LogKeyUserID, createdUser.ID)
+
+	c.JSON(http.StatusCreated, rest.GetSuccessResponse(newUserResponse(createdUser)))
+}
+
+// UserUpdate updates an existing user by its ID.
+//
+// @Summary Update an existing user
+// @Description Updates the name, email and active flag of a user. Deactivated users can no longer be assigned to tasks.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Param request body UpdateUserRequest true "Updated user data"
+// @Success 200 {object} rest.StandardResponse{data=UserResponse} "User successfully updated"
+// @Failure 400 {object} rest.StandardResponse{data=nil} "Invalid user ID or request payload"
+// @Failure 403 {object} rest.StandardResponse{data=nil} "user:write not allowed"
+// @Failure 404 {object} rest.StandardResponse{data=nil} "User not found"
+// @Failure 409 {object} rest.StandardResponse{data=nil} "Email already exists"
+// @Failure 500 {object} rest.StandardResponse{data=nil} "Internal server error"
+// @Router /api/users/{id} [put]
+func (h *Handler) UserUpdate(c *gin.Context) {
+	h.logger.InfoWithContext(c.Request.Context(), LogIncomingUserUpdate)
+
+	userID, err := pathID(c, ID, InvalidUserID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	var req UpdateUserRequest
+	if err := bindJSON(c, &req); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	user := &entities.User{
+		ID:       userID,
+		Name:     req.Name,
+		Email:    req.Email,
+		IsActive: *req.IsActive,
+	}
+
+	updatedUser, err := h.UserService.Update(c, user)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	h.logger.InfoWithContext(c.Request.Context(), LogUserUpdateSuccess, LogKeyUserID, updatedUser.ID)
+
+	c.JSON(http.StatusOK, rest.GetSuccessResponse(newUserResponse(updatedUser)))
+}
+
+// UserDelete deletes an existing user by its ID.
+//
+// @Summary Delete a user
+// @Description Deletes a user. Users that are still assigned to tasks cannot be deleted and should be deactivated instead.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 204 "User successfully deleted"
+// @Failure 400 {object} rest.StandardResponse{data=nil} "Invalid user ID"
+// @Failure 403 {object} rest.StandardResponse{data=nil} "user:write not allowed"
+// @Failure 404 {object} rest.StandardResponse{data=nil} "User not found"
+// @Failure 409 {object} rest.StandardResponse{data=nil} "User is still assigned to tasks"
+// @Failure 500 {object} rest.StandardResponse{data=nil} "Internal server error"
+// @Router /api/users/{id} [delete]
+func (h *Handler) UserDelete(c *gin.Context) {
+	h.logger.InfoWithContext(c.Request.Context(), LogIncomingUserDelete)
+
+	userID, err := pathID(c, ID, InvalidUserID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	err = h.UserService.Delete(c, userID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	h.logger.InfoWithContext(c.Request.Context(), LogUserDeleteSuccess, LogKeyUserID, userID)
+
+	c.Status(http.StatusNoContent)
+}
+
+// UserGetByID retrieves a user by its ID.
+//
+// @Summary Get user by ID
+// @Description Retrieves the details of a specific user using its ID.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} rest.StandardResponse{data=UserResponse} "User successfully fetched"
+// @Failure 400 {object} rest.StandardResponse{data=nil} "Invalid user ID"
+// @Failure 404 {object} rest.StandardResponse{data=nil} "User not found"
+// @Failure 500 {object} rest.StandardResponse{data=nil} "Internal server error"
+// @Router /api/users/{id} [get]
+func (h *Handler) UserGetByID(c *gin.Context) {
+	h.logger.InfoWithContext(c.Request.Context(), LogIncomingUserFetch)
+
+	userID, err := pathID(c, ID, InvalidUserID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	user, err := h.UserService.GetByID(c, userID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	h.logger.InfoWithContext(c.Request.Context(), LogUserFetchSuccess, LogKeyUserID, userID)
+
+	c.JSON(http.StatusOK, rest.GetSuccessResponse(newUserResponse(user)))
+}
+
+// UserList retrieves a paginated list of users.
+//
+// @Summary List users
+// @Description Retrieves a paginated list of users with optional filters.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param per_page query int false "Number of users per page" default(20)
+// @Param filter query string false "Filter by name, email or is_active"
+// @Success 200 {object} rest.StandardResponse{data=[]UserResponse, meta=rest.PaginationMeta} "List of users successfully fetched"
+// @Failure 500 {object} rest.StandardResponse{data=nil} "Internal server error"
+// @Router /api/users [get]
+func (h *Handler) UserList(c *gin.Context) {
+	h.logger.InfoWithContext(c.Request.Context(), LogIncomingUserFetch)
+
+	query := rest.ParseQuery(c)
+
+	users, total, err := h.UserService.List(c, query)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	h.logger.InfoWithContext(c.Request.Context(), LogUserFetchSuccess, LogKeyCount, len(users))
+
+	response := make([]UserResponse, 0, len(users))
+	for i := range users {
+		response = append(response, newUserResponse(&users[i]))
+	}
+
+	c.JSON(http.StatusOK, rest.GetSuccessResponseWithMeta(response, rest.PaginationMeta{
+		Total:   total,
+		Page:    query.Page,
+		PerPage: query.PerPage,
+	}))
+}
+
+// newUserResponse maps a user entity to its API representation.
+func newUserResponse(user *entities.User) UserResponse {
+	return UserResponse{
+		ID:        user.ID,
+		Name:      user.Name,
+		Email:     user.Email,
+		IsActive:  user.IsActive,
+		CreatedAt: user.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: user.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+const (
+	LogIncomingUserCreate = "Incoming user create request"
+	LogUserCreateSuccess  = "User created successfully"
+
+	LogIncomingUserUpdate = "Incoming user update request"
+	LogUserUpdateSuccess  = "User updated successfully"
+
+	LogIncomingUserDelete = "Incoming user delete request"
+	LogUserDeleteSuccess  = "User delete successfully"
+
+	LogIncomingUserFetch = "Incoming user fetch request"
+	LogUserFetchSuccess  = "User fetch successfully"
+
+	InvalidUserID   = "Invalid user ID"
+	UserEmailExists = "User email already exists"
+	UserInUse       = "User is still assigned to tasks"
+)
+
+type CreateUserRequest struct {
+	Name     string `json:"name" binding:"required"`
+	Email    string `json:"email" binding:"required,email"`
+	IsActive *bool  `json:"is_active,omitempty"` // optional, default true
+}
+
+type UpdateUserRequest struct {
+	Name     string `json:"name" binding:"required"`
+	Email    string `json:"email" binding:"required,email"`
+	IsActive *bool  `json:"is_active" binding:"required"`
+}
+
+type UserResponse struct {
+	ID        int64  `json:"id"`
+	Name      string `json:"name"`
+	Email     string `json:"email"`
+	IsActive  bool   `json:"is_active"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
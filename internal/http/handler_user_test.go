@@ -0,0 +1,233 @@
+package http_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"task-manager/internal/auth"
+	"task-manager/internal/entities"
+	HTTPhandler "task-manager/internal/http"
+	"task-manager/internal/repository/postgres"
+	"task-manager/internal/service"
+	"task-manager/internal/tenant"
+	"task-manager/pkg/rest"
+	"testing"
+)
+
+var (
+	stubUser = entities.User{
+		ID:       10,
+		Name:     "Jane Doe",
+		Email:    "jane@example.com",
+		IsActive: true,
+	}
+	stubUserBody = HTTPhandler.CreateUserRequest{
+		Name:  stubUser.Name,
+		Email: stubUser.Email,
+	}
+)
+
+// TestUserCreate_Success_ShouldReturnCreated tests successful creation of a user.
+func TestUserCreate_Success_ShouldReturnCreated(t *testing.T) {
+	userService := service.MockUserService{}
+
+	userService.On(
+		"Create",
+		mock.Anything,
+		mock.MatchedBy(func(u *entities.User) bool { return u.IsActive }),
+	).Return(&stubUser, nil)
+
+	router := HTTPhandler.SetupHandlerWithUsers(&service.MockTaskService{}, &userService).SetupRouter()
+
+	jsonBytes, _ := json.Marshal(stubUserBody)
+	req, err := http.NewRequest(http.MethodPost, "/api/users/", bytes.NewBuffer(jsonBytes))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var res rest.StandardResponse
+	_ = json.Unmarshal(w.Body.Bytes(), &res)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, float64(stubUser.ID), res.Data.(map[string]interface{})["id"])
+	assert.Equal(t, stubUser.Email, res.Data.(map[string]interface{})["email"])
+	assert.Equal(t, true, res.Data.(map[string]interface{})["is_active"])
+
+	userService.AssertExpectations(t)
+}
+
+// TestUserCreate_InvalidEmail_ShouldReturnBadRequest tests creation with a malformed email.
+func TestUserCreate_InvalidEmail_ShouldReturnBadRequest(t *testing.T) {
+	router := HTTPhandler.SetupHandlerWithUsers(&service.MockTaskService{}, &service.MockUserService{}).SetupRouter()
+
+	jsonBytes, _ := json.Marshal(HTTPhandler.CreateUserRequest{Name: "Jane", Email: "not-an-email"})
+	req, err := http.NewRequest(http.MethodPost, "/api/users/", bytes.NewBuffer(jsonBytes))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+// TestUserDelete_InUse_ShouldReturnConflict tests deleting a user that still owns tasks.
+func TestUserDelete_InUse_ShouldReturnConflict(t *testing.T) {
+	userService := service.MockUserService{}
+	userService.On("Delete", mock.Anything, stubUser.ID).Return(postgres.ErrUserInUse)
+
+	router := HTTPhandler.SetupHandlerWithUsers(&service.MockTaskService{}, &userService).SetupRouter()
+
+	req, err := http.NewRequest(http.MethodDelete, "/api/users/10", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	userService.AssertExpectations(t)
+}
+
+// TestTaskCreate_UnknownAssignee_ShouldReturnUnprocessableEntity tests that assignee
+// validation errors from the service are reported as 422.
+func TestTaskCreate_UnknownAssignee_ShouldReturnUnprocessableEntity(t *testing.T) {
+	for _, serviceErr := range []error{service.ErrAssigneeNotFound, service.ErrAssigneeInactive} {
+		taskService := service.MockTaskService{}
+		taskService.On(
+			"Create",
+			mock.Anything,
+			mock.AnythingOfType("*entities.Task"),
+		).Return(&entities.Task{}, serviceErr)
+
+		router := HTTPhandler.SetupHandler(&taskService).SetupRouter()
+
+		body := stubBody
+		body.Status = entities.TaskStatusPending
+		jsonBytes, _ := json.Marshal(body)
+		req, err := http.NewRequest(http.MethodPost, "/api/tasks/", bytes.NewBuffer(jsonBytes))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		taskService.AssertExpectations(t)
+	}
+}
+
+// TestTaskGetByID_IncludeAssignee_ShouldEmbedAssignee tests ?include=assignee on a single task.
+func TestTaskGetByID_IncludeAssignee_ShouldEmbedAssignee(t *testing.T) {
+	taskService := service.MockTaskService{}
+	taskService.On("GetByID", mock.Anything, stubTask.ID).Return(&stubTask, nil)
+
+	userService := service.MockUserService{}
+	userService.On("GetByIDs", mock.Anything, []int64{stubTask.AssigneeID}).Return([]entities.User{stubUser}, nil)
+
+	router := HTTPhandler.SetupHandlerWithUsers(&taskService, &userService).SetupRouter()
+
+	req, err := http.NewRequest(http.MethodGet, "/api/tasks/12?include=assignee", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var res rest.StandardResponse
+	_ = json.Unmarshal(w.Body.Bytes(), &res)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assignee := res.Data.(map[string]interface{})["assignee"].(map[string]interface{})
+	assert.Equal(t, stubUser.Name, assignee["name"])
+	assert.Equal(t, stubUser.Email, assignee["email"])
+
+	taskService.AssertExpectations(t)
+	userService.AssertExpectations(t)
+}
+
+// TestTaskList_IncludeAssignee_ShouldFetchAssigneesAtOnce tests that the assignees of
+// a page are fetched in a single query, the missing ones left empty.
+func TestTaskList_IncludeAssignee_ShouldFetchAssigneesAtOnce(t *testing.T) {
+	tasks := []entities.Task{
+		{ID: 1, Title: "a", Status: entities.TaskStatusPending, AssigneeID: stubUser.ID},
+		{ID: 2, Title: "b", Status: entities.TaskStatusPending, AssigneeID: 404},
+		{ID: 3, Title: "c", Status: entities.TaskStatusPending, AssigneeID: stubUser.ID},
+	}
+	taskService := service.MockTaskService{}
+	taskService.On("List", mock.Anything, mock.Anything).Return(tasks, len(tasks), nil)
+
+	userService := service.MockUserService{}
+	userService.On("GetByIDs", mock.Anything, []int64{stubUser.ID, 404}).Return([]entities.User{stubUser}, nil).Once()
+
+	router := HTTPhandler.SetupHandlerWithUsers(&taskService, &userService).SetupRouter()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/tasks/?include=assignee", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var res rest.StandardResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	data := res.Data.([]interface{})
+	require.Len(t, data, 3)
+	assert.Equal(t, stubUser.Name, data[0].(map[string]interface{})["assignee"].(map[string]interface{})["name"])
+	assert.NotContains(t, data[1].(map[string]interface{}), "assignee")
+	assert.Equal(t, stubUser.Name, data[2].(map[string]interface{})["assignee"].(map[string]interface{})["name"])
+
+	taskService.AssertExpectations(t)
+	userService.AssertExpectations(t)
+}
+
+// TestUserList_Tenant tests that the tenant of the request reaches the UserService,
+// and that requesting another tenant is forbidden.
+func TestUserList_Tenant(t *testing.T) {
+	userService := service.MockUserService{}
+	userService.On("List", mock.MatchedBy(func(ctx context.Context) bool {
+		return tenant.Of(ctx) == tenant.Default
+	}), mock.Anything).Return([]entities.User{stubUser}, 1, nil).Once()
+
+	handler := HTTPhandler.SetupHandlerWithUsers(&service.MockTaskService{}, &userService)
+	handler.Authenticators = []auth.Authenticator{tokenAuthenticator{}}
+	router := handler.SetupRouter()
+
+	tests := []struct {
+		name   string
+		header string
+		code   int
+	}{
+		{"own tenant", "", http.StatusOK},
+		{"foreign tenant", "acme", http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodGet, "/api/users/", nil)
+			req.Header.Set("Authorization", "Bearer valid")
+			if tt.header != "" {
+				req.Header.Set(tenant.Header, tt.header)
+			}
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.code, w.Code)
+		})
+	}
+
+	userService.AssertExpectations(t)
+}
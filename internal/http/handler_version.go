@@ -0,0 +1,33 @@
+package http
+
+import (
+	"net/http"
+	"task-manager/internal/version"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// VersionResponse is the build of the instance and how long it has been running.
+type VersionResponse struct {
+	version.Info
+	Uptime        string  `json:"uptime"`         // e.g. 1h2m3s
+	UptimeSeconds float64 `json:"uptime_seconds"` // Uptime in seconds
+}
+
+// Version reports the build of the instance.
+//
+// @Summary Build information
+// @Description Reports the commit, build time, Go version and modules of the build, the container and the uptime of the instance.
+// @Tags Version
+// @Produce json
+// @Success 200 {object} VersionResponse "Build of the instance"
+// @Router /version [get]
+func (h *Handler) Version(c *gin.Context) {
+	uptime := h.VersionInfo.Uptime()
+	c.JSON(http.StatusOK, VersionResponse{
+		Info:          h.VersionInfo,
+		Uptime:        uptime.Round(time.Second).String(),
+		UptimeSeconds: uptime.Seconds(),
+	})
+}
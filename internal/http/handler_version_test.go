@@ -0,0 +1,45 @@
+package http_test
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"task-manager/internal/auth"
+	HTTPhandler "task-manager/internal/http"
+	"task-manager/internal/service"
+	"task-manager/internal/version"
+	"testing"
+	"time"
+)
+
+// TestVersion_ShouldReportTheBuild tests that the build of the instance is reported
+// with its uptime, without credentials.
+func TestVersion_ShouldReportTheBuild(t *testing.T) {
+	h := HTTPhandler.SetupHandler(&service.MockTaskService{})
+	h.Authenticators = []auth.Authenticator{tokenAuthenticator{}}
+	h.VersionInfo = version.Info{
+		GitCommit:     "81d50e7",
+		BuildTime:     "2024-06-02T08:00:00Z",
+		StartTime:     time.Now().Add(-90 * time.Second),
+		ContainerName: "api-1",
+		GoVersion:     "go1.22.4",
+		Module:        version.Module{Path: "task-manager", Version: "v1.4.0"},
+	}
+	router := h.SetupRouter()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/version", nil))
+
+	var response HTTPhandler.VersionResponse
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "81d50e7", response.GitCommit)
+	assert.Equal(t, "2024-06-02T08:00:00Z", response.BuildTime)
+	assert.Equal(t, "api-1", response.ContainerName)
+	assert.Equal(t, "go1.22.4", response.GoVersion)
+	assert.Equal(t, version.Module{Path: "task-manager", Version: "v1.4.0"}, response.Module)
+	assert.Equal(t, "1m30s", response.Uptime)
+	assert.InDelta(t, 90, response.UptimeSeconds, 1)
+}
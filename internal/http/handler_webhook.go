@@ -0,0 +1,404 @@
+package http
+
+import (
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"task-manager/internal/entities"
+	"task-manager/pkg/rest"
+	"time"
+)
+
+// WebhookCreate registers a new webhook subscription.
+//
+// @Summary Create a webhook subscription
+// @Description Registers an endpoint receiving signed task events. Without events, all events are delivered.
+// @Description The signing secret is generated when omitted and only returned by this endpoint.
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Param request body CreateWebhookRequest true "Webhook subscription payload"
+// @Success 201 {object} rest.StandardResponse{data=WebhookResponse} "Webhook successfully created"
+// @Failure 400 {object} rest.StandardResponse{data=nil} "Invalid request payload"
+// @Failure 403 {object} rest.StandardResponse{data=nil} "webhook:write not allowed"
+// @Failure 422 {object} rest.StandardResponse{data=nil} "Invalid URL or unknown event type"
+// @Failure 500 {object} rest.StandardResponse{data=nil} "Internal server error"
+// @Router /api/webhooks/ [post]
+func (h *Handler) WebhookCreate(c *gin.Context) {
+	h.logger.InfoWithContext(c.Request.Context(), LogIncomingWebhookCreate)
+
+	var req CreateWebhookRequest
+	if err := bindJSON(c, &req); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	subscription := &entities.WebhookSubscription{
+		URL:      req.URL,
+		Secret:   req.Secret,
+		Events:   req.Events,
+		IsActive: true,
+	}
+	if req.IsActive != nil {
+		subscription.IsActive = *req.IsActive
+	}
+
+	created, err := h.WebhookService.CreateSubscription(c, subscription)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	h.logger.InfoWithContext(c.Request.Context(), LogWebhookCreateSuccess, LogKeyWebhookID, created.ID)
+
+	response := newWebhookResponse(created)
+	response.Secret = created.Secret
+
+	c.JSON(http.StatusCreated, rest.GetSuccessResponse(response))
+}
+
+// WebhookUpdate updates an existing webhook subscription.
+//
+// @Summary Update a webhook subscription
+// @Description Updates the URL, event filter and active flag of a subscription. The secret cannot be changed.
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Param id path int true "Webhook ID"
+// @Param request body UpdateWebhookRequest true "Updated webhook subscription"
+// @Success 200 {object} rest.StandardResponse{data=WebhookResponse} "Webhook successfully updated"
+// @Failure 400 {object} rest.StandardResponse{data=nil} "Invalid webhook ID or request payload"
+// @Failure 403 {object} rest.StandardResponse{data=nil} "webhook:write not allowed"
+// @Failure 404 {object} rest.StandardResponse{data=nil} "Webhook not found"
+// @Failure 422 {object} rest.StandardResponse{data=nil} "Invalid URL or unknown event type"
+// @Failure 500 {object} rest.StandardResponse{data=nil} "Internal server error"
+// @Router /api/webhooks/{id} [put]
+func (h *Handler) WebhookUpdate(c *gin.Context) {
+	h.logger.InfoWithContext(c.Request.Context(), LogIncomingWebhookUpdate)
+
+	webhookID, err := pathID(c, ID, InvalidWebhookID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	var req UpdateWebhookRequest
+	if err := bindJSON(c, &req); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	updated, err := h.WebhookService.UpdateSubscription(c, &entities.WebhookSubscription{
+		ID:       webhookID,
+		URL:      req.URL,
+		Events:   req.Events,
+		IsActive: *req.IsActive,
+	})
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	h.logger.InfoWithContext(c.Request.Context(), LogWebhookUpdateSuccess, LogKeyWebhookID, updated.ID)
+
+	c.JSON(http.StatusOK, rest.GetSuccessResponse(newWebhookResponse(updated)))
+}
+
+// WebhookDelete deletes a webhook subscription.
+//
+// @Summary Delete a webhook subscription
+// @Description Deletes a subscription together with its pending deliveries and delivery log.
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Param id path int true "Webhook ID"
+// @Success 204 "Webhook successfully deleted"
+// @Failure 400 {object} rest.StandardResponse{data=nil} "Invalid webhook ID"
+// @Failure 403 {object} rest.StandardResponse{data=nil} "webhook:write not allowed"
+// @Failure 404 {object} rest.StandardResponse{data=nil} "Webhook not found"
+// @Failure 500 {object} rest.StandardResponse{data=nil} "Internal server error"
+// @Router /api/webhooks/{id} [delete]
+func (h *Handler) WebhookDelete(c *gin.Context) {
+	h.logger.InfoWithContext(c.Request.Context(), LogIncomingWebhookDelete)
+
+	webhookID, err := pathID(c, ID, InvalidWebhookID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	if err := h.WebhookService.DeleteSubscription(c, webhookID); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	h.logger.InfoWithContext(c.Request.Context(), LogWebhookDeleteSuccess, LogKeyWebhookID, webhookID)
+
+	c.Status(http.StatusNoContent)
+}
+
+// WebhookGetByID retrieves a webhook subscription by its ID.
+//
+// @Summary Get webhook subscription by ID
+// @Description Retrieves a webhook subscription. The signing secret is not returned.
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Param id path int true "Webhook ID"
+// @Success 200 {object} rest.StandardResponse{data=WebhookResponse} "Webhook successfully fetched"
+// @Failure 400 {object} rest.StandardResponse{data=nil} "Invalid webhook ID"
+// @Failure 404 {object} rest.StandardResponse{data=nil} "Webhook not found"
+// @Failure 500 {object} rest.StandardResponse{data=nil} "Internal server error"
+// @Router /api/webhooks/{id} [get]
+func (h *Handler) WebhookGetByID(c *gin.Context) {
+	h.logger.InfoWithContext(c.Request.Context(), LogIncomingWebhookFetch)
+
+	webhookID, err := pathID(c, ID, InvalidWebhookID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	subscription, err := h.WebhookService.GetSubscription(c, webhookID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	h.logger.InfoWithContext(c.Request.Context(), LogWebhookFetchSuccess, LogKeyWebhookID, webhookID)
+
+	c.JSON(http.StatusOK, rest.GetSuccessResponse(newWebhookResponse(subscription)))
+}
+
+// WebhookList retrieves a paginated list of webhook subscriptions.
+//
+// @Summary List webhook subscriptions
+// @Description Retrieves a paginated list of webhook subscriptions. Signing secrets are not returned.
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param per_page query int false "Number of webhooks per page" default(20)
+// @Param is_active query bool false "Filter by active flag"
+// @Success 200 {object} rest.StandardResponse{data=[]WebhookResponse, meta=rest.PaginationMeta} "List of webhooks successfully fetched"
+// @Failure 500 {object} rest.StandardResponse{data=nil} "Internal server error"
+// @Router /api/webhooks [get]
+func (h *Handler) WebhookList(c *gin.Context) {
+	h.logger.InfoWithContext(c.Request.Context(), LogIncomingWebhookFetch)
+
+	query := rest.ParseQuery(c)
+
+	subscriptions, total, err := h.WebhookService.ListSubscriptions(c, query)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	h.logger.InfoWithContext(c.Request.Context(), LogWebhookFetchSuccess, LogKeyCount, len(subscriptions))
+
+	response := make([]WebhookResponse, 0, len(subscriptions))
+	for i := range subscriptions {
+		response = append(response, newWebhookResponse(&subscriptions[i]))
+	}
+
+	c.JSON(http.StatusOK, rest.GetSuccessResponseWithMeta(response, rest.PaginationMeta{
+		Total:   total,
+		Page:    query.Page,
+		PerPage: query.PerPage,
+	}))
+}
+
+// WebhookDeliveryList retrieves the delivery log of a webhook subscription.
+//
+// @Summary List webhook deliveries
+// @Description Retrieves the deliveries of a subscription, newest first, including pending retries and dead letters.
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Param id path int true "Webhook ID"
+// @Param page query int false "Page number" default(1)
+// @Param per_page query int false "Number of deliveries per page" default(20)
+// @Param status query string false "Filter by status (pending, delivered, dead)"
+// @Param event_type query string false "Filter by event type, e.g. task.created"
+// @Success 200 {object} rest.StandardResponse{data=[]WebhookDeliveryResponse, meta=rest.PaginationMeta} "List of deliveries successfully fetched"
+// @Failure 400 {object} rest.StandardResponse{data=nil} "Invalid webhook ID"
+// @Failure 404 {object} rest.StandardResponse{data=nil} "Webhook not found"
+// @Failure 500 {object} rest.StandardResponse{data=nil} "Internal server error"
+// @Router /api/webhooks/{id}/deliveries [get]
+func (h *Handler) WebhookDeliveryList(c *gin.Context) {
+	h.logger.InfoWithContext(c.Request.Context(), LogIncomingWebhookDeliveryFetch)
+
+	webhookID, err := pathID(c, ID, InvalidWebhookID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	query := rest.ParseQuery(c)
+
+	deliveries, total, err := h.WebhookService.ListDeliveries(c, webhookID, query)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	h.logger.InfoWithContext(c.Request.Context(), LogWebhookDeliveryFetchSuccess, LogKeyWebhookID, webhookID)
+
+	response := make([]WebhookDeliveryResponse, 0, len(deliveries))
+	for i := range deliveries {
+		response = append(response, newWebhookDeliveryResponse(&deliveries[i]))
+	}
+
+	c.JSON(http.StatusOK, rest.GetSuccessResponseWithMeta(response, rest.PaginationMeta{
+		Total:   total,
+		Page:    query.Page,
+		PerPage: query.PerPage,
+	}))
+}
+
+// WebhookDeliveryRetry schedules a delivery for immediate redelivery.
+//
+// @Summary Retry a webhook delivery
+// @Description Requeues a delivery, typically a dead-lettered one, with a fresh attempt budget.
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Param id path int true "Webhook ID"
+// @Param delivery_id path int true "Delivery ID"
+// @Success 202 {object} rest.StandardResponse{data=WebhookDeliveryResponse} "Delivery requeued"
+// @Failure 400 {object} rest.StandardResponse{data=nil} "Invalid webhook or delivery ID"
+// @Failure 403 {object} rest.StandardResponse{data=nil} "webhook:write not allowed"
+// @Failure 404 {object} rest.StandardResponse{data=nil} "Delivery not found"
+// @Failure 500 {object} rest.StandardResponse{data=nil} "Internal server error"
+// @Router /api/webhooks/{id}/deliveries/{delivery_id}/retry [post]
+func (h *Handler) WebhookDeliveryRetry(c *gin.Context) {
+	h.logger.InfoWithContext(c.Request.Context(), LogIncomingWebhookDeliveryRetry)
+
+	webhookID, err := pathID(c, ID, InvalidWebhookID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	deliveryID, err := pathID(c, DeliveryIDParam, InvalidDeliveryID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	delivery, err := h.WebhookService.RetryDelivery(c, webhookID, deliveryID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	h.logger.InfoWithContext(c.Request.Context(), LogWebhookDeliveryRetrySuccess, LogKeyDeliveryID, deliveryID)
+
+	c.JSON(http.StatusAccepted, rest.GetSuccessResponse(newWebhookDeliveryResponse(delivery)))
+}
+
+// newWebhookResponse maps a subscription to its API representation, without its secret.
+func newWebhookResponse(subscription *entities.WebhookSubscription) WebhookResponse {
+	events := subscription.Events
+	if events == nil {
+		events = []entities.TaskEventType{}
+	}
+
+	return WebhookResponse{
+		ID:        subscription.ID,
+		URL:       subscription.URL,
+		Events:    events,
+		IsActive:  subscription.IsActive,
+		CreatedAt: subscription.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: subscription.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+// newWebhookDeliveryResponse maps a delivery to its API representation.
+func newWebhookDeliveryResponse(delivery *entities.WebhookDelivery) WebhookDeliveryResponse {
+	response := WebhookDeliveryResponse{
+		ID:             delivery.ID,
+		WebhookID:      delivery.SubscriptionID,
+		EventID:        delivery.EventID,
+		EventType:      delivery.EventType,
+		Payload:        json.RawMessage(delivery.Payload),
+		Status:         delivery.Status,
+		Attempts:       delivery.Attempts,
+		LastError:      delivery.LastError,
+		ResponseStatus: delivery.ResponseStatus,
+		CreatedAt:      delivery.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:      delivery.UpdatedAt.Format(time.RFC3339),
+	}
+
+	if delivery.Status == entities.WebhookDeliveryPending {
+		response.NextAttemptAt = delivery.NextAttemptAt.Format(time.RFC3339)
+	}
+	if delivery.DeliveredAt != nil {
+		response.DeliveredAt = delivery.DeliveredAt.Format(time.RFC3339)
+	}
+
+	return response
+}
+
+const (
+	LogIncomingWebhookCreate = "Incoming webhook create request"
+	LogWebhookCreateSuccess  = "Webhook created successfully"
+
+	LogIncomingWebhookUpdate = "Incoming webhook update request"
+	LogWebhookUpdateSuccess  = "Webhook updated successfully"
+
+	LogIncomingWebhookDelete = "Incoming webhook delete request"
+	LogWebhookDeleteSuccess  = "Webhook delete successfully"
+
+	LogIncomingWebhookFetch = "Incoming webhook fetch request"
+	LogWebhookFetchSuccess  = "Webhook fetch successfully"
+
+	LogIncomingWebhookDeliveryFetch = "Incoming webhook delivery fetch request"
+	LogWebhookDeliveryFetchSuccess  = "Webhook deliveries fetch successfully"
+
+	LogIncomingWebhookDeliveryRetry = "Incoming webhook delivery retry request"
+	LogWebhookDeliveryRetrySuccess  = "Webhook delivery requeued successfully"
+
+	DeliveryIDParam   = "delivery_id"
+	InvalidWebhookID  = "Invalid webhook ID"
+	InvalidDeliveryID = "Invalid delivery ID"
+)
+
+type CreateWebhookRequest struct {
+	URL      string                   `json:"url" binding:"required"`
+	Secret   string                   `json:"secret,omitempty"`    // optional, generated when empty
+	Events   []entities.TaskEventType `json:"events,omitempty"`    // optional, all events when empty
+	IsActive *bool                    `json:"is_active,omitempty"` // optional, default true
+}
+
+type UpdateWebhookRequest struct {
+	URL      string                   `json:"url" binding:"required"`
+	Events   []entities.TaskEventType `json:"events,omitempty"` // all events when empty
+	IsActive *bool                    `json:"is_active" binding:"required"`
+}
+
+type WebhookResponse struct {
+	ID        int64                    `json:"id"`
+	URL       string                   `json:"url"`
+	Secret    string                   `json:"secret,omitempty"` // only returned on creation
+	Events    []entities.TaskEventType `json:"events"`
+	IsActive  bool                     `json:"is_active"`
+	CreatedAt string                   `json:"created_at"`
+	UpdatedAt string                   `json:"updated_at"`
+}
+
+type WebhookDeliveryResponse struct {
+	ID             int64                          `json:"id"`
+	WebhookID      int64                          `json:"webhook_id"`
+	EventID        string                         `json:"event_id"`
+	EventType      entities.TaskEventType         `json:"event_type"`
+	Payload        json.RawMessage                `json:"payload" swaggertype:"object"`
+	Status         entities.WebhookDeliveryStatus `json:"status"`
+	Attempts       int                            `json:"attempts"`
+	NextAttemptAt  string                         `json:"next_attempt_at,omitempty"` // only while pending
+	LastError      string                         `json:"last_error,omitempty"`
+	ResponseStatus int                            `json:"response_status,omitempty"`
+	DeliveredAt    string                         `json:"delivered_at,omitempty"`
+	CreatedAt      string                         `json:"created_at"`
+	UpdatedAt      string                         `json:"updated_at"`
+}
@@ -0,0 +1,184 @@
+package http_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"task-manager/internal/auth"
+	"task-manager/internal/entities"
+	HTTPhandler "task-manager/internal/http"
+	"task-manager/internal/repository/postgres"
+	"task-manager/internal/service"
+	"task-manager/internal/tenant"
+	"task-manager/pkg/rest"
+	"testing"
+	"time"
+)
+
+var stubWebhook = entities.WebhookSubscription{
+	ID:        3,
+	URL:       "https://example.com/hooks/tasks",
+	Secret:    "s3cret",
+	Events:    []entities.TaskEventType{entities.TaskEventCreated},
+	IsActive:  true,
+	CreatedAt: time.Now(),
+	UpdatedAt: time.Now(),
+}
+
+// TestWebhookCreate_Success_ShouldReturnSecret tests that the secret is only returned on creation.
+func TestWebhookCreate_Success_ShouldReturnSecret(t *testing.T) {
+	webhookService := service.MockWebhookService{}
+
+	webhookService.On(
+		"CreateSubscription",
+		mock.Anything,
+		mock.MatchedBy(func(s *entities.WebhookSubscription) bool { return s.IsActive && s.URL == stubWebhook.URL }),
+	).Return(&stubWebhook, nil)
+	webhookService.On("GetSubscription", mock.Anything, stubWebhook.ID).Return(&stubWebhook, nil)
+
+	router := HTTPhandler.SetupHandlerWithWebhooks(&webhookService).SetupRouter()
+
+	jsonBytes, _ := json.Marshal(HTTPhandler.CreateWebhookRequest{URL: stubWebhook.URL, Events: stubWebhook.Events})
+	req, _ := http.NewRequest(http.MethodPost, "/api/webhooks/", bytes.NewBuffer(jsonBytes))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var res rest.StandardResponse
+	_ = json.Unmarshal(w.Body.Bytes(), &res)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, stubWebhook.Secret, res.Data.(map[string]interface{})["secret"])
+	assert.Equal(t, []interface{}{"task.created"}, res.Data.(map[string]interface{})["events"])
+
+	req, _ = http.NewRequest(http.MethodGet, "/api/webhooks/3", nil)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	res = rest.StandardResponse{}
+	_ = json.Unmarshal(w.Body.Bytes(), &res)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, res.Data.(map[string]interface{}), "secret")
+
+	webhookService.AssertExpectations(t)
+}
+
+// TestWebhookCreate_UnknownEvent_ShouldReturnUnprocessableEntity tests an unknown event filter.
+func TestWebhookCreate_UnknownEvent_ShouldReturnUnprocessableEntity(t *testing.T) {
+	webhookService := service.MockWebhookService{}
+
+	webhookService.On("CreateSubscription", mock.Anything, mock.Anything).
+		Return((*entities.WebhookSubscription)(nil), errors.Wrap(service.ErrInvalidWebhookEvent, `"task.archived"`))
+
+	router := HTTPhandler.SetupHandlerWithWebhooks(&webhookService).SetupRouter()
+
+	jsonBytes, _ := json.Marshal(HTTPhandler.CreateWebhookRequest{
+		URL:    stubWebhook.URL,
+		Events: []entities.TaskEventType{"task.archived"},
+	})
+	req, _ := http.NewRequest(http.MethodPost, "/api/webhooks/", bytes.NewBuffer(jsonBytes))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+}
+
+// TestWebhookDeliveryRetry_NotFound_ShouldReturnNotFound tests retrying a delivery of another subscription.
+func TestWebhookDeliveryRetry_NotFound_ShouldReturnNotFound(t *testing.T) {
+	webhookService := service.MockWebhookService{}
+
+	webhookService.On("RetryDelivery", mock.Anything, int64(3), int64(99)).
+		Return((*entities.WebhookDelivery)(nil), postgres.ErrWebhookDeliveryNotFound)
+
+	router := HTTPhandler.SetupHandlerWithWebhooks(&webhookService).SetupRouter()
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/webhooks/3/deliveries/99/retry", nil)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	webhookService.AssertExpectations(t)
+}
+
+// TestWebhookDeliveryList_Success_ShouldReturnDeliveries tests listing the delivery log.
+func TestWebhookDeliveryList_Success_ShouldReturnDeliveries(t *testing.T) {
+	webhookService := service.MockWebhookService{}
+
+	deliveries := []entities.WebhookDelivery{{
+		ID:             5,
+		SubscriptionID: 3,
+		EventID:        "evt-1",
+		EventType:      entities.TaskEventCreated,
+		Payload:        []byte(`{"id":"evt-1"}`),
+		Status:         entities.WebhookDeliveryDead,
+		Attempts:       8,
+		LastError:      "unexpected status 500",
+		ResponseStatus: http.StatusInternalServerError,
+	}}
+
+	webhookService.On("ListDeliveries", mock.Anything, int64(3), mock.Anything).Return(deliveries, 1, nil)
+
+	router := HTTPhandler.SetupHandlerWithWebhooks(&webhookService).SetupRouter()
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/webhooks/3/deliveries?status=dead", nil)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var res rest.StandardResponse
+	_ = json.Unmarshal(w.Body.Bytes(), &res)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	item := res.Data.([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "dead", item["status"])
+	assert.Equal(t, "evt-1", item["payload"].(map[string]interface{})["id"])
+	assert.NotContains(t, item, "next_attempt_at")
+}
+
+// TestWebhookList_Tenant tests that the tenant of the request reaches the WebhookService,
+// and that requesting another tenant is forbidden.
+func TestWebhookList_Tenant(t *testing.T) {
+	webhookService := service.MockWebhookService{}
+	webhookService.On("ListSubscriptions", mock.MatchedBy(func(ctx context.Context) bool {
+		return tenant.Of(ctx) == tenant.Default
+	}), mock.Anything).Return([]entities.WebhookSubscription{}, 0, nil).Once()
+
+	handler := HTTPhandler.SetupHandlerWithWebhooks(&webhookService)
+	handler.Authenticators = []auth.Authenticator{tokenAuthenticator{}}
+	router := handler.SetupRouter()
+
+	tests := []struct {
+		name   string
+		header string
+		code   int
+	}{
+		{"own tenant", "", http.StatusOK},
+		{"foreign tenant", "acme", http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodGet, "/api/webhooks/", nil)
+			req.Header.Set("Authorization", "Bearer valid")
+			if tt.header != "" {
+				req.Header.Set(tenant.Header, tt.header)
+			}
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.code, w.Code)
+		})
+	}
+
+	webhookService.AssertExpectations(t)
+}
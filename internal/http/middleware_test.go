@@ -1,13 +1,30 @@
 package http_test
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"task-manager/internal/auth"
+	"task-manager/internal/authz"
+	"task-manager/internal/entities"
 	HTTPhandler "task-manager/internal/http"
+	"task-manager/internal/ratelimit"
+	"task-manager/internal/service"
+	"task-manager/pkg/rest"
+	"task-manager/pkg/tracing/tracingtest"
 	"testing"
+	"time"
 )
 
 func TestCORSMiddleware(t *testing.T) {
@@ -47,4 +64,348 @@ func TestTracingMiddleware(t *testing.T) {
 	traceID := w.Body.String()
 	assert.NotEmpty(t, traceID)
 	assert.True(t, strings.HasPrefix(traceID, ""))
+	assert.Equal(t, traceID, w.Header().Get("X-Trace-ID"))
+}
+
+// TestTracingMiddleware_Traceparent_ShouldContinueTheTrace tests that the span of the
+// request is a child of the span of the caller, named after the route.
+func TestTracingMiddleware_Traceparent_ShouldContinueTheTrace(t *testing.T) {
+	exporter := tracingtest.Record(t)
+
+	router := gin.New()
+	router.Use(HTTPhandler.TracingMiddleware())
+	router.GET("/tasks/:id", func(c *gin.Context) {
+		c.String(http.StatusInternalServerError, HTTPhandler.TraceIDFromContext(c.Request.Context()))
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/tasks/7", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", w.Body.String())
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", w.Header().Get("X-Trace-ID"))
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "GET /tasks/:id", spans[0].Name)
+	assert.Equal(t, trace.SpanKindServer, spans[0].SpanKind)
+	assert.Equal(t, "00f067aa0ba902b7", spans[0].Parent.SpanID().String())
+	assert.True(t, spans[0].Parent.IsRemote())
+	assert.Equal(t, codes.Error, spans[0].Status.Code)
+	assert.Contains(t, spans[0].Attributes, attribute.Int("http.response.status_code", http.StatusInternalServerError))
+	assert.Contains(t, spans[0].Attributes, attribute.String("http.route", "/tasks/:id"))
+}
+
+// tokenAuthenticator accepts the bearer token "valid".
+type tokenAuthenticator struct{}
+
+func (tokenAuthenticator) Scheme() string { return "Bearer" }
+
+func (tokenAuthenticator) Authenticate(_ context.Context, credentials string) (*auth.Principal, error) {
+	if credentials != "valid" {
+		return nil, auth.ErrInvalidCredentials
+	}
+	return &auth.Principal{Subject: "42", UserID: 42, Method: auth.MethodJWT}, nil
+}
+
+func TestAuthenticationMiddleware(t *testing.T) {
+	router := gin.New()
+	router.Use(HTTPhandler.AuthenticationMiddleware(tokenAuthenticator{}))
+	router.GET("/whoami", func(c *gin.Context) {
+		principal, _ := auth.PrincipalFromContext(c.Request.Context())
+		c.String(http.StatusOK, principal.Subject)
+	})
+
+	tests := []struct {
+		name          string
+		target        string
+		authorization string
+		wantStatus    int
+		wantError     string
+	}{
+		{"valid token", "/whoami", "Bearer valid", http.StatusOK, ""},
+		{"case insensitive scheme", "/whoami", "bearer valid", http.StatusOK, ""},
+		{"query token", "/whoami?access_token=valid", "", http.StatusUnauthorized, auth.ErrUnauthenticated.Error()},
+		{"missing credentials", "/whoami", "", http.StatusUnauthorized, auth.ErrUnauthenticated.Error()},
+		{"invalid token", "/whoami", "Bearer forged", http.StatusUnauthorized, auth.ErrInvalidCredentials.Error()},
+		{"unsupported scheme", "/whoami", "Basic dXNlcjpwYXNz", http.StatusUnauthorized, `invalid credentials: unsupported authorization scheme "Basic"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodGet, tt.target, nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantStatus == http.StatusOK {
+				assert.Equal(t, "42", w.Body.String())
+				return
+			}
+
+			var response rest.StandardResponse
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, rest.Fail, response.Status)
+			assert.Equal(t, HTTPhandler.Unauthorized, response.Message)
+			assert.Equal(t, []string{tt.wantError}, response.Errors)
+			assert.Equal(t, `Bearer realm="task-manager"`, w.Header().Get("WWW-Authenticate"))
+		})
+	}
+}
+
+func TestAccessTokenMiddleware(t *testing.T) {
+	router := gin.New()
+	router.Use(HTTPhandler.AccessTokenMiddleware(), HTTPhandler.AuthenticationMiddleware(tokenAuthenticator{}))
+	router.GET("/whoami", func(c *gin.Context) {
+		principal, _ := auth.PrincipalFromContext(c.Request.Context())
+		c.String(http.StatusOK, principal.Subject+" "+c.Request.URL.RawQuery)
+	})
+
+	req, _ := http.NewRequest(http.MethodGet, "/whoami?access_token=valid&since=1", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "42 since=1", w.Body.String())
+
+	// The Authorization header wins over the query string
+	req, _ = http.NewRequest(http.MethodGet, "/whoami?access_token=valid", nil)
+	req.Header.Set("Authorization", "Bearer forged")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestLoggerMiddleware_ShouldRedactTheQueryString(t *testing.T) {
+	var buf bytes.Buffer
+	defaultWriter := gin.DefaultWriter
+	gin.DefaultWriter = &buf
+	defer func() { gin.DefaultWriter = defaultWriter }()
+
+	router := gin.New()
+	router.Use(HTTPhandler.LoggerMiddleware())
+	router.GET("/api/tasks/stream", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/tasks/stream?access_token=secret", nil)
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Contains(t, buf.String(), `"/api/tasks/stream"`)
+	assert.NotContains(t, buf.String(), "secret")
+}
+
+func TestSetupRouter_Authenticators_ShouldProtectAPIRoutes(t *testing.T) {
+	handler := HTTPhandler.SetupHandler(&service.MockTaskService{})
+	handler.Authenticators = []auth.Authenticator{tokenAuthenticator{}}
+	router := handler.SetupRouter()
+
+	for _, target := range []string{"/api/tasks/", "/api/tasks/1", "/api/users/", "/api/webhooks/", "/api/tasks/stream"} {
+		req, _ := http.NewRequest(http.MethodGet, target, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code, target)
+	}
+
+	// Only the stream and socket routes accept the token in the query string
+	req, _ := http.NewRequest(http.MethodGet, "/api/tasks/?access_token=valid", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	req, _ = http.NewRequest(http.MethodPost, "/graphql", strings.NewReader(`{"query": "{ tasks { total } }"}`))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+// roleAuthenticator accepts the bearer tokens "admin" and "member", for users of these roles.
+type roleAuthenticator struct{}
+
+func (roleAuthenticator) Scheme() string { return "Bearer" }
+
+func (roleAuthenticator) Authenticate(_ context.Context, credentials string) (*auth.Principal, error) {
+	if credentials != authz.RoleAdmin && credentials != authz.RoleMember && credentials != auth.RolePlatformAdmin {
+		return nil, auth.ErrInvalidCredentials
+	}
+	return &auth.Principal{Subject: credentials, UserID: 42, Roles: []string{credentials}, Method: auth.MethodJWT}, nil
+}
+
+// TestPolicyMiddleware_UserAndWebhookWrites_ShouldRequireAdmin tests that only admins
+// manage the users and webhooks, which every authenticated user reads.
+func TestPolicyMiddleware_UserAndWebhookWrites_ShouldRequireAdmin(t *testing.T) {
+	userService := service.MockUserService{}
+	userService.On("List", mock.Anything, mock.Anything).Return([]entities.User{}, 0, nil)
+	userService.On("Delete", mock.Anything, int64(1)).Return(nil)
+	webhookService := service.MockWebhookService{}
+	webhookService.On("ListSubscriptions", mock.Anything, mock.Anything).Return([]entities.WebhookSubscription{}, 0, nil)
+	webhookService.On("DeleteSubscription", mock.Anything, int64(1)).Return(nil)
+
+	handler := HTTPhandler.SetupHandlerWithServices(&service.MockTaskService{}, &userService, &webhookService, &service.MockAPIKeyService{})
+	handler.Authenticators = []auth.Authenticator{roleAuthenticator{}}
+	router := handler.SetupRouter()
+
+	tests := []struct {
+		name       string
+		method     string
+		target     string
+		body       string
+		token      string
+		wantStatus int
+	}{
+		{"member lists users", http.MethodGet, "/api/users/", "", authz.RoleMember, http.StatusOK},
+		{"member creates user", http.MethodPost, "/api/users/", `{"name": "x", "email": "x@example.com"}`, authz.RoleMember, http.StatusForbidden},
+		{"member updates user", http.MethodPut, "/api/users/1", `{"name": "x", "email": "x@example.com"}`, authz.RoleMember, http.StatusForbidden},
+		{"member deletes user", http.MethodDelete, "/api/users/1", "", authz.RoleMember, http.StatusForbidden},
+		{"admin deletes user", http.MethodDelete, "/api/v1/users/1", "", authz.RoleAdmin, http.StatusNoContent},
+		{"member lists webhooks", http.MethodGet, "/api/webhooks/", "", authz.RoleMember, http.StatusOK},
+		{"member creates webhook", http.MethodPost, "/api/webhooks/", `{"url": "https://example.com/hook"}`, authz.RoleMember, http.StatusForbidden},
+		{"member updates webhook", http.MethodPut, "/api/webhooks/1", `{"url": "https://example.com/hook"}`, authz.RoleMember, http.StatusForbidden},
+		{"member deletes webhook", http.MethodDelete, "/api/webhooks/1", "", authz.RoleMember, http.StatusForbidden},
+		{"member retries delivery", http.MethodPost, "/api/webhooks/1/deliveries/2/retry", "", authz.RoleMember, http.StatusForbidden},
+		{"admin deletes webhook", http.MethodDelete, "/api/v1/webhooks/1", "", authz.RoleAdmin, http.StatusNoContent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code, w.Body.String())
+		})
+	}
+	userService.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	webhookService.AssertNotCalled(t, "RetryDelivery", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestRateLimitMiddleware tests that requests over the limit of their route are
+// answered 429 with the rate limit headers, per client.
+func TestRateLimitMiddleware(t *testing.T) {
+	taskService := service.MockTaskService{}
+	taskService.On("GetByID", mock.Anything, int64(1)).Return(&entities.Task{ID: 1}, nil)
+
+	handler := HTTPhandler.SetupHandler(&taskService)
+	handler.Authenticators = []auth.Authenticator{tokenAuthenticator{}}
+	handler.RateLimiter = ratelimit.NewMemory()
+	handler.RateLimits, _ = ratelimit.ParseRules(ratelimit.DefaultLimit, []string{"GET /api/tasks/:id=2/1m"})
+	router := handler.SetupRouter()
+
+	get := func(token string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(http.MethodGet, "/api/tasks/1", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := get("valid")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "2", w.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "1", w.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "2;w=60", w.Header().Get("RateLimit-Policy"))
+
+	assert.Equal(t, http.StatusOK, get("valid").Code)
+
+	w = get("valid")
+	var response rest.StandardResponse
+	_ = json.Unmarshal(w.Body.Bytes(), &response)
+
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, HTTPhandler.TooManyRequests, response.Message)
+	assert.Equal(t, rest.Fail, response.Status)
+	assert.Equal(t, "30", w.Header().Get("Retry-After"))
+	assert.Equal(t, "0", w.Header().Get("RateLimit-Remaining"))
+
+	// Unauthenticated requests are not counted against the principal
+	assert.Equal(t, http.StatusUnauthorized, get("invalid").Code)
+}
+
+// TestRateLimitMiddleware_ExemptRoute_ShouldNotLimit tests that a rule of 0 requests
+// exempts its route from the default limit.
+func TestRateLimitMiddleware_ExemptRoute_ShouldNotLimit(t *testing.T) {
+	taskService := service.MockTaskService{}
+	taskService.On("GetByID", mock.Anything, int64(1)).Return(&entities.Task{ID: 1}, nil)
+	taskService.On("List", mock.Anything, mock.Anything).Return([]entities.Task{}, 0, nil)
+
+	handler := HTTPhandler.SetupHandler(&taskService)
+	handler.RateLimiter = ratelimit.NewMemory()
+	handler.RateLimits, _ = ratelimit.ParseRules(ratelimit.Limit{Requests: 1, Window: time.Minute}, []string{"GET /api/tasks/:id=0/1m"})
+	router := handler.SetupRouter()
+
+	get := func(path string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(http.MethodGet, path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	for range 3 {
+		w := get("/api/tasks/1")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get("RateLimit-Limit"))
+	}
+
+	// The other routes keep the default limit
+	assert.Equal(t, http.StatusOK, get("/api/tasks/").Code)
+	assert.Equal(t, http.StatusTooManyRequests, get("/api/tasks/").Code)
+}
+
+// TestIPRateLimitMiddleware_ShouldLimitFailedAuthentication tests that the requests of
+// an IP address are limited before their authentication, whatever X-Forwarded-For
+// they forge when no proxy is trusted.
+func TestIPRateLimitMiddleware_ShouldLimitFailedAuthentication(t *testing.T) {
+	handler := HTTPhandler.SetupHandler(&service.MockTaskService{})
+	handler.Authenticators = []auth.Authenticator{tokenAuthenticator{}}
+	handler.RateLimiter = ratelimit.NewMemory()
+	handler.IPRateLimit = ratelimit.Limit{Requests: 2, Window: time.Minute}
+	router := handler.SetupRouter()
+
+	get := func(forwardedFor string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(http.MethodGet, "/api/tasks/1", nil)
+		req.RemoteAddr = "203.0.113.7:4242"
+		req.Header.Set("Authorization", "Bearer forged")
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, get("198.51.100.1").Code)
+	assert.Equal(t, http.StatusUnauthorized, get("198.51.100.2").Code)
+
+	w := get("198.51.100.3")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "30", w.Header().Get("Retry-After"))
+}
+
+// failingLimiter fails like an unavailable Redis.
+type failingLimiter struct{}
+
+func (failingLimiter) Allow(context.Context, string, ratelimit.Limit) (ratelimit.Result, error) {
+	return ratelimit.Result{}, errors.New("connection refused")
+}
+
+// TestRateLimitMiddleware_LimiterFails_ShouldPass tests that an unavailable limiter
+// does not take the API down.
+func TestRateLimitMiddleware_LimiterFails_ShouldPass(t *testing.T) {
+	taskService := service.MockTaskService{}
+	taskService.On("GetByID", mock.Anything, int64(1)).Return(&entities.Task{ID: 1}, nil)
+
+	handler := HTTPhandler.SetupHandler(&taskService)
+	handler.RateLimiter = failingLimiter{}
+	handler.RateLimits = ratelimit.Rules{Default: ratelimit.DefaultLimit}
+	router := handler.SetupRouter()
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/tasks/1", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("RateLimit-Limit"))
 }
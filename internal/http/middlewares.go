@@ -1,13 +1,63 @@
 package http
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"github.com/gin-gonic/gin"
-	"github.com/google/uuid"
+	"io"
+	"math"
 	"net/http"
+	"strconv"
+	"strings"
+	"task-manager/internal/auth"
+	"task-manager/internal/authz"
+	"task-manager/internal/entities"
+	"task-manager/internal/idempotency"
+	"task-manager/internal/ratelimit"
+	"task-manager/internal/tenant"
+	"task-manager/internal/validation"
+	"task-manager/pkg/logger"
 	"task-manager/pkg/monitoring"
+	"task-manager/pkg/tracing"
 	"time"
+
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// AccessTokenParam is the query parameter carrying a bearer token on the GET requests
+// of the routes using AccessTokenMiddleware, for clients that can't set headers such
+// as EventSource and browser WebSockets.
+const AccessTokenParam = "access_token"
+
+// Unauthorized is the response of requests failing authentication.
+const Unauthorized = "Unauthorized"
+
+// Forbidden is the response of authenticated requests the principal may not make.
+const Forbidden = "Forbidden"
+
+// IdempotencyKeyInFlight is the response of requests whose idempotency key is used by
+// a request still in progress.
+const IdempotencyKeyInFlight = "A request with the same Idempotency-Key is in progress"
+
+// IdempotencyKeyReused is the response of requests reusing the idempotency key of
+// another request.
+const IdempotencyKeyReused = "Idempotency-Key was used for a different request"
+
+// TooManyRequests is the response of requests exceeding the rate limit of their client.
+const TooManyRequests = "Too Many Requests"
+
+// BodyTooLarge is the response of requests whose body exceeds MaxBodySize.
+const BodyTooLarge = "Request body too large"
+
+// Names of the JSON Schemas of the request bodies, see docs/schema.
+const (
+	CreateTaskSchema = "create_task"
+	UpdateTaskSchema = "update_task"
 )
 
 // CORSMiddleware provides Cross-Origin Resource Sharing (CORS) support.
@@ -17,8 +67,9 @@ func CORSMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
 		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, Authorization, accept, origin, Cache-Control")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, Authorization, accept, origin, Cache-Control, X-Tenant-ID, Idempotency-Key, traceparent, tracestate")
 		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE, PATCH, HEAD")
+		c.Writer.Header().Set("Access-Control-Expose-Headers", "RateLimit-Limit, RateLimit-Remaining, RateLimit-Reset, RateLimit-Policy, Retry-After, Idempotent-Replayed, X-Trace-ID")
 
 		if c.Request.Method == http.MethodOptions {
 			c.AbortWithStatus(http.StatusNoContent)
@@ -61,24 +112,470 @@ func TaskMetricsMiddleware(metrics *monitoring.TaskMetrics) gin.HandlerFunc {
 	}
 }
 
-// TracingMiddleware injects a unique trace ID into the request context for observability.
-// This ID can be used for distributed tracing and correlating logs across services.
+// TracingMiddleware runs every request in a server span, child of the span of the
+// W3C traceparent header when the caller sent one, and returns the trace ID in the
+// X-Trace-ID header. The span is named after the route, and fails on 5xx responses.
+// The route is also stored in the context for the logs of the request.
 func TracingMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		traceID := uuid.New().String()
-		ctx := context.WithValue(c.Request.Context(), "traceID", traceID)
-		c.Request = c.Request.WithContext(ctx)
+		ctx := tracing.Propagator().Extract(c.Request.Context(), propagation.HeaderCarrier(c.Request.Header))
+
+		route := c.FullPath()
+		name := c.Request.Method
+		if route != "" {
+			name += " " + route
+		}
+		ctx, span := tracing.Start(ctx, name,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.HTTPRequestMethodKey.String(c.Request.Method),
+				semconv.HTTPRoute(route),
+				semconv.URLPath(c.Request.URL.Path),
+				semconv.ClientAddress(c.ClientIP()),
+				semconv.UserAgentOriginal(c.Request.UserAgent()),
+			),
+		)
+		defer span.End()
+
+		c.Header(tracing.TraceIDHeader, tracing.TraceID(ctx))
+		c.Request = c.Request.WithContext(logger.WithRoute(ctx, name))
 		c.Next()
+
+		status := c.Writer.Status()
+		span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+		if status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
 	}
 }
 
 // TraceIDFromContext retrieves the trace ID from the context, if available.
 // Returns empty string if not found.
 func TraceIDFromContext(ctx context.Context) string {
-	if v := ctx.Value("traceID"); v != nil {
-		return v.(string)
+	return tracing.TraceID(ctx)
+}
+
+// AuthenticationMiddleware authenticates requests with the authenticator of their
+// Authorization scheme and stores the principal in the request context (see
+// auth.PrincipalFromContext). Requests without credentials, with an unknown scheme or
+// rejected credentials are aborted with 401 and a WWW-Authenticate challenge per scheme,
+// requests whose credentials could not be checked with 500.
+func AuthenticationMiddleware(authenticators ...auth.Authenticator) gin.HandlerFunc {
+	schemes := make(map[string]auth.Authenticator, len(authenticators))
+	challenges := make([]string, 0, len(authenticators))
+	for _, authenticator := range authenticators {
+		schemes[strings.ToLower(authenticator.Scheme())] = authenticator
+		challenges = append(challenges, authenticator.Scheme()+` realm="task-manager"`)
+	}
+
+	return func(c *gin.Context) {
+		scheme, credentials, found := strings.Cut(c.GetHeader("Authorization"), " ")
+
+		var err error
+		authenticator, ok := schemes[strings.ToLower(scheme)]
+		switch {
+		case !found || strings.TrimSpace(credentials) == "":
+			err = auth.ErrUnauthenticated
+		case !ok:
+			err = fmt.Errorf("%w: unsupported authorization scheme %q", auth.ErrInvalidCredentials, scheme)
+		}
+
+		var principal *auth.Principal
+		if err == nil {
+			principal, err = authenticator.Authenticate(c.Request.Context(), strings.TrimSpace(credentials))
+		}
+		if err != nil && !errors.Is(err, auth.ErrUnauthenticated) && !errors.Is(err, auth.ErrInvalidCredentials) {
+			// The credentials could not be checked, e.g. the key store is unavailable
+			abortWithError(c, err)
+			return
+		}
+		if err != nil {
+			for _, challenge := range challenges {
+				c.Writer.Header().Add("WWW-Authenticate", challenge)
+			}
+			abortWithError(c, ErrUnauthenticated.Wrap(err))
+			return
+		}
+
+		c.Request = c.Request.WithContext(auth.WithPrincipal(c.Request.Context(), principal))
+		c.Next()
+	}
+}
+
+// AccessTokenMiddleware accepts the bearer token of GET requests in the
+// AccessTokenParam query parameter, for the routes of clients that can't set headers.
+// The token is moved to the Authorization header, unless one is set, and removed from
+// the URL so that it is not passed any further. It must run before the authentication.
+func AccessTokenMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		query := c.Request.URL.Query()
+		token := query.Get(AccessTokenParam)
+		if c.Request.Method != http.MethodGet || token == "" {
+			c.Next()
+			return
+		}
+
+		if c.GetHeader("Authorization") == "" {
+			c.Request.Header.Set("Authorization", "Bearer "+token)
+		}
+		query.Del(AccessTokenParam)
+		c.Request.URL.RawQuery = query.Encode()
+
+		c.Next()
+	}
+}
+
+// LoggerMiddleware logs every request like gin.Logger, to gin.DefaultWriter, without
+// its query string: it may carry credentials (see AccessTokenParam).
+func LoggerMiddleware() gin.HandlerFunc {
+	return gin.LoggerWithConfig(gin.LoggerConfig{
+		Formatter: func(params gin.LogFormatterParams) string {
+			path, _, _ := strings.Cut(params.Path, "?")
+			return fmt.Sprintf("[GIN] %v | %3d | %13v | %15s | %-7s %#v\n%s",
+				params.TimeStamp.Format("2006/01/02 - 15:04:05"),
+				params.StatusCode,
+				params.Latency,
+				params.ClientIP,
+				params.Method,
+				path,
+				params.ErrorMessage,
+			)
+		},
+	})
+}
+
+// ScopeMiddleware rejects requests of principals whose credentials lack the scope
+// of the request (see auth.Principal.Permits) with 403: the read scope for GET and
+// HEAD requests, the write scope otherwise. Requests without principal pass, the
+// routes are not authenticated then.
+func ScopeMiddleware(read, write string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := auth.PrincipalFromContext(c.Request.Context())
+		if !ok {
+			c.Next()
+			return
+		}
+
+		scope := write
+		if c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead {
+			scope = read
+		}
+		if !principal.Permits(scope) {
+			abortWithError(c, ErrForbidden.Wrap(fmt.Errorf("credentials lack the %s scope", scope)))
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RoleMiddleware rejects requests of principals without role with 403. Requests
+// without principal pass, the routes are not authenticated then.
+func RoleMiddleware(role string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := auth.PrincipalFromContext(c.Request.Context())
+		if ok && !principal.HasRole(role) {
+			abortWithError(c, ErrForbidden.Wrap(fmt.Errorf("the %s role is required", role)))
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// PolicyMiddleware rejects with 403 the requests of principals the policy does not
+// allow action. Requests without principal pass, the routes are not authenticated then.
+func PolicyMiddleware(policy authz.Authorizer, action authz.Action) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := policy.Authorize(c.Request.Context(), action, nil); err != nil {
+			abortWithError(c, err)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// TenantMiddleware resolves the tenant of the request from its principal and the
+// X-Tenant-ID header (see tenant.Resolve) and stores it in the request context, where
+// the repositories read it. Malformed tenants are rejected with 400, tenants the
+// principal does not belong to with 403.
+func TenantMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, _ := auth.PrincipalFromContext(c.Request.Context())
+
+		id, err := tenant.Resolve(principal, c.GetHeader(tenant.Header))
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+
+		c.Request = c.Request.WithContext(tenant.WithID(c.Request.Context(), id))
+		c.Next()
+	}
+}
+
+// RateLimitMiddleware limits the requests of every client with the rule of their route
+// (see ratelimit.Rules.Match), without its API version so that the rules and buckets
+// are shared by the versions. Clients are the principal of the request, or its IP
+// address on unauthenticated routes, so it runs after the authentication. Responses
+// carry the RateLimit-Limit, RateLimit-Remaining, RateLimit-Reset and RateLimit-Policy
+// headers; requests exceeding the limit are aborted with 429 and Retry-After. Requests
+// pass if the limiter fails, e.g. Redis is unavailable.
+func RateLimitMiddleware(limiter ratelimit.Limiter, rules ratelimit.Rules, logger logger.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rule := rules.Match(c.Request.Method, unversionedRoute(c.FullPath()))
+		if !rule.Limit.Enabled() {
+			c.Next()
+			return
+		}
+
+		if rateLimit(c, limiter, rule.Name()+"|"+clientOf(c), rule.Limit, logger) {
+			c.Next()
+		}
+	}
+}
+
+// IPRateLimitMiddleware limits the requests of every IP address to limit, whatever
+// their route, like RateLimitMiddleware. It runs before the authentication, so that
+// the requests failing it are limited too, e.g. the guesses of an API key.
+func IPRateLimitMiddleware(limiter ratelimit.Limiter, limit ratelimit.Limit, logger logger.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if rateLimit(c, limiter, "ip|"+c.ClientIP(), limit, logger) {
+			c.Next()
+		}
+	}
+}
+
+// rateLimit takes a token of the bucket of key and sets the RateLimit headers. Returns
+// false if the request was aborted for exceeding the limit.
+func rateLimit(c *gin.Context, limiter ratelimit.Limiter, key string, limit ratelimit.Limit, logger logger.Logger) bool {
+	res, err := limiter.Allow(c.Request.Context(), key, limit)
+	if err != nil {
+		logger.ErrorWithContext(c.Request.Context(), "Rate limit not applied", LogKeyError, err)
+		return true
 	}
-	return ""
+
+	header := c.Writer.Header()
+	header.Set("RateLimit-Limit", strconv.Itoa(limit.Requests))
+	header.Set("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+	header.Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(res.Reset)))
+	header.Set("RateLimit-Policy", fmt.Sprintf("%d;w=%d", limit.Requests, ceilSeconds(limit.Window)))
+
+	if !res.Allowed {
+		retryAfter := ceilSeconds(res.RetryAfter)
+		header.Set("Retry-After", strconv.Itoa(retryAfter))
+		abortWithError(c, ErrRateLimited.Wrap(fmt.Errorf("rate limit of %s exceeded, retry in %ds", limit, retryAfter)))
+		return false
+	}
+
+	return true
+}
+
+// clientOf returns the client of a request: its principal if authenticated (API key
+// principals are named after their key), its IP address otherwise. The IP address is
+// only read from X-Forwarded-For behind the trusted proxies of the router.
+func clientOf(c *gin.Context) string {
+	if principal, ok := auth.PrincipalFromContext(c.Request.Context()); ok {
+		return "principal:" + principal.Subject
+	}
+	return "ip:" + c.ClientIP()
+}
+
+// IdempotencyMiddleware makes the POST requests sent with an Idempotency-Key header
+// safe to retry (see package idempotency). The first request with a key runs and its
+// response is stored for ttl; the requests sent again with the key get the stored
+// response, with the Idempotent-Replayed header. Requests with a key in flight are
+// rejected with 409, requests reusing a key with another method, path or body with
+// 422, requests whose body exceeds MaxBodySize with 413. Responses with a 5xx status are
+// not stored, the request can be retried. Keys are scoped to the tenant and client of
+// the request, so it runs after them.
+func IdempotencyMiddleware(store idempotency.Store, ttl time.Duration, logger logger.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(idempotency.Header)
+		if c.Request.Method != http.MethodPost || key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > idempotency.MaxKeyLength {
+			abortWithError(c, ErrInvalidIdempotencyKey.Wrap(
+				fmt.Errorf("%s must be at most %d characters", idempotency.Header, idempotency.MaxKeyLength)))
+			return
+		}
+
+		ctx := c.Request.Context()
+
+		body, err := readBody(c)
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+
+		key = idempotency.ScopedKey(tenant.Of(ctx), clientOf(c), key)
+		fingerprint := idempotency.Fingerprint(c.Request.Method, c.Request.URL.Path, body)
+
+		record, err := store.Reserve(ctx, key, fingerprint, ttl)
+		switch {
+		case err != nil:
+			logger.ErrorWithContext(ctx, "Failed to reserve idempotency key", LogKeyError, err)
+			abortWithError(c, err)
+			return
+		case record == nil:
+			// Reserved, run the request below
+		case record.Fingerprint != fingerprint:
+			abortWithError(c, ErrIdempotencyKeyReused)
+			return
+		case !record.Completed():
+			c.Writer.Header().Set("Retry-After", "1")
+			abortWithError(c, ErrIdempotencyKeyInFlight)
+			return
+		default:
+			for name, value := range record.Response.Header {
+				c.Writer.Header().Set(name, value)
+			}
+			c.Writer.Header().Set(idempotency.ReplayedHeader, "true")
+			c.Writer.WriteHeader(record.Response.StatusCode)
+			_, _ = c.Writer.Write(record.Response.Body)
+			c.Abort()
+			return
+		}
+
+		// The key is stored or released even if the client is gone or the handler panics
+		ctx = context.WithoutCancel(ctx)
+		recorder := &recordingWriter{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		completed := false
+		defer func() {
+			if completed {
+				return
+			}
+			if err := store.Release(ctx, key); err != nil {
+				logger.ErrorWithContext(ctx, "Failed to release idempotency key", LogKeyError, err)
+			}
+		}()
+
+		c.Next()
+
+		if recorder.Status() >= http.StatusInternalServerError {
+			return
+		}
+		response := entities.StoredResponse{
+			StatusCode: recorder.Status(),
+			Header:     storedHeaders(recorder.Header()),
+			Body:       recorder.body.Bytes(),
+		}
+		if err := store.Complete(ctx, key, response); err != nil {
+			logger.ErrorWithContext(ctx, "Failed to store idempotent response", LogKeyError, err)
+			return
+		}
+		completed = true
+	}
+}
+
+// readBody reads the request body, up to MaxBodySize, and restores it for the next
+// handlers. Larger bodies fail with ErrBodyTooLarge.
+func readBody(c *gin.Context) ([]byte, error) {
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, MaxBodySize))
+
+	var tooLarge *http.MaxBytesError
+	switch {
+	case errors.As(err, &tooLarge):
+		return nil, ErrBodyTooLarge.Wrap(fmt.Errorf("request body exceeds %d bytes", tooLarge.Limit))
+	case err != nil:
+		return nil, ErrMalformedBody.Wrap(err)
+	}
+
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+// storedHeaders returns the headers of a response replayed with its body.
+func storedHeaders(header http.Header) map[string]string {
+	stored := make(map[string]string)
+	for _, name := range []string{"Content-Type", "Location"} {
+		if value := header.Get(name); value != "" {
+			stored[name] = value
+		}
+	}
+	return stored
+}
+
+// recordingWriter keeps a copy of the body written to the response.
+type recordingWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *recordingWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *recordingWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// VersionMiddleware announces the deprecation of an API version on its responses, with
+// the Deprecation (RFC 9745) and Sunset (RFC 8594) headers and a Link to the successor
+// version. It is a no-op for the versions neither deprecated nor sunset.
+func VersionMiddleware(version APIVersion) gin.HandlerFunc {
+	header := make(http.Header)
+	if !version.Deprecation.IsZero() {
+		header.Set("Deprecation", fmt.Sprintf("@%d", version.Deprecation.Unix()))
+		if version.Successor != "" {
+			header.Set("Link", fmt.Sprintf(`</api/%s/>; rel="successor-version"`, version.Successor))
+		}
+	}
+	if !version.Sunset.IsZero() {
+		header.Set("Sunset", version.Sunset.UTC().Format(http.TimeFormat))
+	}
+
+	return func(c *gin.Context) {
+		for name, values := range header {
+			c.Writer.Header()[name] = values
+		}
+		c.Next()
+	}
+}
+
+// SchemaMiddleware validates the JSON request bodies against the schema of the given
+// version and name before binding, rejecting the invalid bodies with every violation
+// and the ones exceeding MaxBodySize with 413. The body is restored for the handler.
+func SchemaMiddleware(schemas *validation.Registry, version, name string) gin.HandlerFunc {
+	if !schemas.Has(version, name) {
+		panic(fmt.Sprintf("unknown schema %s/%s", version, name))
+	}
+
+	return func(c *gin.Context) {
+		if c.Request.Body == nil {
+			abortWithError(c, ErrMalformedBody.Wrap(errors.New("request body is required")))
+			return
+		}
+		body, err := readBody(c)
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+
+		var violations validation.Violations
+		err = schemas.Validate(version, name, body)
+		switch {
+		case err == nil:
+			c.Next()
+		case errors.As(err, &violations):
+			abortWithError(c, ErrValidationFailed.Wrap(violations))
+		case errors.Is(err, validation.ErrInvalidJSON):
+			abortWithError(c, ErrMalformedBody.Wrap(err))
+		default:
+			abortWithError(c, err)
+		}
+	}
+}
+
+// ceilSeconds rounds a duration up to whole seconds, as the rate limit headers expect.
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
 }
 
 // statusLabel converts HTTP status code to string for Prometheus labels.
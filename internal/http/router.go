@@ -1,13 +1,11 @@
 package http
 
 import (
-	swaggerFiles "github.com/swaggo/files"
-	ginSwagger "github.com/swaggo/gin-swagger"
 	"net/http"
 	_ "net/http/pprof"
-	_ "task-manager/docs"
+	"task-manager/internal/auth"
+	"task-manager/internal/authz"
 	"task-manager/pkg/monitoring"
-	"task-manager/pkg/rest"
 
 	"github.com/gin-gonic/gin"
 )
@@ -30,21 +28,37 @@ import (
 func (h *Handler) SetupRouter() *gin.Engine {
 	// Set Gin to release mode to reduce logging overhead in production
 	gin.SetMode(gin.ReleaseMode)
-	r := gin.Default()
+	r := gin.New()
+
+	// The client IP of the logs and rate limits is only read from X-Forwarded-For when
+	// the request comes from a trusted proxy, it could be forged otherwise
+	if err := r.SetTrustedProxies(h.config.HTTP.TrustedProxies); err != nil {
+		h.logger.Error("invalid trusted proxies, none trusted: " + err.Error())
+		_ = r.SetTrustedProxies(nil)
+	}
+
+	// Handlers pass the gin context to the services, which read the principal
+	// from the request context
+	r.ContextWithFallback = true
 
 	// Set up pprof
 	r.GET("/debug/pprof/*any", gin.WrapH(http.DefaultServeMux))
 
 	// Programmatically set Swagger host and base path
 	//docs.SwaggerInfo.Host = h.config.HostBasePath
-	r.GET("/swagger/*any", ginSwagger.DisablingWrapHandler(swaggerFiles.Handler, "DISABLE_SWAGGER"))
+	// The document of every API version is served under /swagger/<version>/
+	r.GET("/swagger/*any", h.swagger())
 
 	// Limit maximum memory for multipart forms (uploads)
 	r.MaxMultipartMemory = MaxMultipartMemory
 
+	// Binding errors name the fields as the clients send them
+	registerFieldNames.Do(registerJSONFieldNames)
+
 	// Global middlewares
-	r.Use(gin.Recovery())   // recover from panics and prevent server crash
-	r.Use(CORSMiddleware()) // handle Cross-Origin Resource Sharing
+	r.Use(LoggerMiddleware()) // log the requests, without their query string
+	r.Use(gin.Recovery())     // recover from panics and prevent server crash
+	r.Use(CORSMiddleware())   // handle Cross-Origin Resource Sharing
 	r.Use(TracingMiddleware())
 
 	// Initialize Prometheus metrics endpoint
@@ -59,22 +73,151 @@ func (h *Handler) SetupRouter() *gin.Engine {
 	)
 
 	// -------------------------------
-	// Task CRUD endpoints
+	// Health endpoints
+	// -------------------------------
+	// @tag.name Health
+	// @tag.description Liveness, readiness and startup probes
+	// Probed by the orchestrator, not authenticated nor limited
+	r.GET("/healthz", h.Liveness)
+	r.GET("/readyz", h.Readiness)
+	r.GET("/startupz", h.Startup)
+	r.GET("/health", h.HealthDetails)
+
+	// -------------------------------
+	// Version endpoint
 	// -------------------------------
-	// @tag.name Tasks
-	// @tag.description Task management endpoints
-	tasks := r.Group("/api/tasks/").Use(TaskMetricsMiddleware(h.TaskMetrics))
-	{
-		tasks.POST("", h.TaskCreate)
-		tasks.GET("", h.TaskList)
-		tasks.GET(":id", h.TaskGetByID)
-		tasks.PUT(":id", h.TaskUpdate)
-		tasks.DELETE(":id", h.TaskDelete)
+	// @tag.name Version
+	// @tag.description Build information of the instance
+	r.GET("/version", h.Version)
+
+	// Requests are limited per IP address before the authentication, so that failing
+	// credentials are limited too
+	throttled := h.ipRateLimit()
+	// API routes require authentication when authenticators are configured
+	authenticated := h.authentication()
+	// Requests are limited per client, known once authenticated
+	limited := h.rateLimit()
+	// Tasks, users, API keys and webhooks belong to the tenant of the request
+	tenanted := TenantMiddleware()
+	// POST requests with an Idempotency-Key are safe to retry, within the tenant and
+	// client of the request
+	idempotent := h.idempotency()
+	// Errors reported by the handlers are written last, so the middlewares above
+	// (metrics, idempotency) see the error responses
+	handled := ErrorMiddleware(h.logger)
+
+	// -------------------------------
+	// Task stream endpoints
+	// -------------------------------
+	// Long-lived streams of task events, not versioned and kept out of the request
+	// latency metrics. EventSource and browser WebSocket clients can't set headers, so
+	// these routes alone accept the token in the query string
+	taskScopes := ScopeMiddleware(auth.ScopeTasksRead, auth.ScopeTasksWrite)
+	tokenQuery := AccessTokenMiddleware()
+	r.GET("/api/tasks/stream", tokenQuery, throttled, authenticated, limited, tenanted, taskScopes, handled, h.TaskStream)
+	r.GET("/api/tasks/socket", tokenQuery, throttled, authenticated, limited, tenanted, taskScopes, h.TaskSocket)
+
+	// mountAPI registers the REST routes of an API version under api
+	mountAPI := func(api *gin.RouterGroup, version APIVersion) {
+		metered := TaskMetricsMiddleware(h.TaskMetrics)
+		versioned := VersionMiddleware(version)
+
+		// -------------------------------
+		// Task CRUD endpoints
+		// -------------------------------
+		// @tag.name Tasks
+		// @tag.description Task management endpoints
+		tasks := api.Group("/tasks/").Use(metered, versioned, throttled, authenticated, limited, tenanted, taskScopes, idempotent, handled)
+		{
+			// Routes whose DTOs change between versions
+			version.Tasks(h, tasks)
+
+			tasks.DELETE(":id", h.TaskDelete)
+			tasks.DELETE(":id/assignees/:user_id", h.TaskRemoveAssignee)
+			tasks.DELETE(":id/watchers/:user_id", h.TaskRemoveWatcher)
+		}
+
+		// -------------------------------
+		// User CRUD endpoints
+		// -------------------------------
+		// @tag.name Users
+		// @tag.description User management endpoints (task assignees)
+		users := api.Group("/users/").Use(metered, versioned, throttled, authenticated, limited, tenanted,
+			ScopeMiddleware(auth.ScopeUsersRead, auth.ScopeUsersWrite), idempotent, handled)
+		{
+			// Users are managed by the admins (user:write of the policy)
+			userWrite := PolicyMiddleware(h.Policy, authz.ActionUserWrite)
+
+			users.POST("", userWrite, h.UserCreate)
+			users.GET("", h.UserList)
+			users.GET(":id", h.UserGetByID)
+			users.PUT(":id", userWrite, h.UserUpdate)
+			users.DELETE(":id", userWrite, h.UserDelete)
+		}
+
+		// -------------------------------
+		// Webhook endpoints
+		// -------------------------------
+		// @tag.name Webhooks
+		// @tag.description Outgoing webhook subscriptions and their delivery log
+		webhooks := api.Group("/webhooks/").Use(metered, versioned, throttled, authenticated, limited, tenanted,
+			ScopeMiddleware(auth.ScopeWebhooksRead, auth.ScopeWebhooksWrite), idempotent, handled)
+		{
+			// Webhooks are managed by the admins (webhook:write of the policy)
+			webhookWrite := PolicyMiddleware(h.Policy, authz.ActionWebhookWrite)
+
+			webhooks.POST("", webhookWrite, h.WebhookCreate)
+			webhooks.GET("", h.WebhookList)
+			webhooks.GET(":id", h.WebhookGetByID)
+			webhooks.PUT(":id", webhookWrite, h.WebhookUpdate)
+			webhooks.DELETE(":id", webhookWrite, h.WebhookDelete)
+
+			webhooks.GET(":id/deliveries", h.WebhookDeliveryList)
+			webhooks.POST(":id/deliveries/:delivery_id/retry", webhookWrite, h.WebhookDeliveryRetry)
+		}
+
+		// -------------------------------
+		// API key endpoints
+		// -------------------------------
+		// @tag.name API Keys
+		// @tag.description Service-to-service API keys, managed by admins
+		apiKeys := api.Group("/admin/api-keys/").Use(metered, versioned, throttled, authenticated, limited, tenanted, RoleMiddleware(auth.RoleAdmin), idempotent, handled)
+		{
+			apiKeys.POST("", h.APIKeyCreate)
+			apiKeys.GET("", h.APIKeyList)
+			apiKeys.GET(":id", h.APIKeyGetByID)
+			apiKeys.DELETE(":id", h.APIKeyRevoke)
+			apiKeys.POST(":id/rotate", h.APIKeyRotate)
+		}
+	}
+
+	// Every version under /api/<version>/, and the legacy version under /api/ for the
+	// clients predating the versions
+	for _, version := range h.APIVersions {
+		mountAPI(r.Group("/api/"+version.Name), version)
+		if version.Name == LegacyAPIVersion {
+			mountAPI(r.Group("/api"), version)
+		}
 	}
 
+	// -------------------------------
+	// GraphQL endpoints
+	// -------------------------------
+	// @tag.name GraphQL
+	// @tag.description Tasks and their relations over GraphQL
+	// Posted operations need tasks:write; queries and subscriptions may be sent with GET,
+	// which rejects mutations, to need tasks:read only
+	graphQLScopes := ScopeMiddleware(auth.ScopeTasksRead, auth.ScopeTasksWrite)
+	graphQLMetrics := TaskMetricsMiddleware(h.TaskMetrics)
+	r.POST("/graphql", graphQLMetrics, throttled, authenticated, limited, tenanted, graphQLScopes, h.GraphQL)
+	r.GET("/graphql", graphQLMetrics, throttled, authenticated, limited, tenanted, graphQLScopes, h.GraphQL)
+	// Long-lived stream, kept out of the request latency metrics
+	r.POST("/graphql/stream", throttled, authenticated, limited, tenanted, graphQLScopes, h.GraphQLStream)
+	r.GET("/graphql/stream", throttled, authenticated, limited, tenanted, graphQLScopes, h.GraphQLStream)
+
 	// Handle unknown routes
 	r.NoRoute(func(c *gin.Context) {
-		c.JSON(http.StatusNotFound, rest.NotFound)
+		writeError(c, ErrRouteNotFound)
 	})
 
 	return r
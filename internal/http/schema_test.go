@@ -0,0 +1,210 @@
+package http_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+	"task-manager/docs/schema"
+	"task-manager/internal/entities"
+	HTTPhandler "task-manager/internal/http"
+	"task-manager/internal/service"
+	"task-manager/pkg/rest"
+	"testing"
+	"time"
+)
+
+// jsonSchema is the part of a JSON Schema, or of a Swagger definition, kept in sync
+// with the binding structs.
+type jsonSchema struct {
+	Type       string                `json:"type"`
+	Ref        string                `json:"$ref"`
+	AllOf      []jsonSchema          `json:"allOf"`
+	Enum       []string              `json:"enum"`
+	Items      *jsonSchema           `json:"items"`
+	Properties map[string]jsonSchema `json:"properties"`
+	Required   []string              `json:"required"`
+	Else       *jsonSchema           `json:"else"`
+}
+
+// TestSchemas_ShouldMatchBindingStructsAndSwagger tests that the JSON Schemas validating
+// the request bodies, the binding structs and the Swagger definitions describe the same
+// fields, types, required fields and statuses.
+func TestSchemas_ShouldMatchBindingStructsAndSwagger(t *testing.T) {
+	var swagger swaggerDoc
+	data, err := os.ReadFile("../../docs/swagger.json")
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, &swagger))
+
+	statuses := []string{
+		string(entities.TaskStatusPending),
+		string(entities.TaskStatusInProgress),
+		string(entities.TaskStatusDone),
+		string(entities.TaskStatusCanceled),
+	}
+	assert.Equal(t, statuses, swagger.Definitions["task-manager_internal_entities.TaskStatus"].Enum)
+
+	tests := []struct {
+		version    string
+		schema     string
+		request    any
+		definition string
+	}{
+		{version: "v1", schema: HTTPhandler.CreateTaskSchema, request: HTTPhandler.CreateTaskRequest{}, definition: "internal_http.CreateTaskRequest"},
+		{version: "v1", schema: HTTPhandler.UpdateTaskSchema, request: HTTPhandler.UpdateTaskRequest{}, definition: "internal_http.UpdateTaskRequest"},
+		{version: "v2", schema: HTTPhandler.CreateTaskSchema, request: HTTPhandler.CreateTaskRequestV2{}, definition: "internal_http.CreateTaskRequestV2"},
+		{version: "v2", schema: HTTPhandler.UpdateTaskSchema, request: HTTPhandler.UpdateTaskRequestV2{}, definition: "internal_http.UpdateTaskRequestV2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version+"/"+tt.schema, func(t *testing.T) {
+			var sch jsonSchema
+			data, err := schema.FS.ReadFile(tt.version + "/" + tt.schema + "_schema.json")
+			require.NoError(t, err)
+			require.NoError(t, json.Unmarshal(data, &sch))
+
+			definition, ok := swagger.Definitions[tt.definition]
+			require.True(t, ok, "missing Swagger definition %s", tt.definition)
+
+			fields, required, requiredWithout := bindingFields(reflect.TypeOf(tt.request))
+
+			assert.ElementsMatch(t, keys(fields), keys(sch.Properties), "schema properties")
+			assert.ElementsMatch(t, keys(fields), keys(definition.Properties), "Swagger properties")
+
+			assert.ElementsMatch(t, required, sch.Required, "schema required")
+			assert.ElementsMatch(t, required, definition.Required, "Swagger required")
+			if len(requiredWithout) > 0 {
+				require.NotNil(t, sch.Else, "schema conditionally required")
+				assert.ElementsMatch(t, requiredWithout, sch.Else.Required, "schema conditionally required")
+			}
+
+			for name, field := range fields {
+				want := jsonType(field.Type)
+				assert.Equal(t, want, sch.Properties[name].Type, "schema type of %s", name)
+				assert.Equal(t, want, swagger.typeOf(definition.Properties[name]), "Swagger type of %s", name)
+				if want == "array" {
+					assert.Equal(t, jsonType(field.Type.Elem()), sch.Properties[name].Items.Type, "schema item type of %s", name)
+					assert.Equal(t, jsonType(field.Type.Elem()), definition.Properties[name].Items.Type, "Swagger item type of %s", name)
+				}
+
+				if field.Type != reflect.TypeOf(entities.TaskStatus("")) {
+					continue
+				}
+				assert.Equal(t, statuses, sch.Properties[name].Enum, "schema statuses")
+				if oneOf, ok := bindingRule(field, "oneof"); ok {
+					assert.Equal(t, statuses, strings.Fields(oneOf), "binding statuses")
+					assert.Equal(t, statuses, definition.Properties[name].Enum, "Swagger statuses")
+				}
+			}
+		})
+	}
+}
+
+// TestTaskCreate_InvalidBody_ShouldReportEveryViolation tests that the task bodies are
+// validated against their schema before reaching the service.
+func TestTaskCreate_InvalidBody_ShouldReportEveryViolation(t *testing.T) {
+	taskService := service.MockTaskService{}
+	router := HTTPhandler.SetupHandler(&taskService).SetupRouter()
+
+	body := `{"status":"bogus","watcher_ids":[1,"2"],"priority":1}`
+	req := httptest.NewRequest(http.MethodPost, "/api/tasks/", bytes.NewBufferString(body))
+	req.Header.Set("Accept", rest.ProblemContentType)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var problem rest.Problem
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, "VALIDATION_FAILED", problem.Code)
+	assert.Empty(t, problem.Detail)
+
+	pointers := make([]string, len(problem.Errors))
+	for i, e := range problem.Errors {
+		pointers[i] = e.Pointer
+	}
+	assert.Equal(t, []string{"/assignee_id", "/priority", "/status", "/title", "/watcher_ids/1"}, pointers)
+	taskService.AssertExpectations(t)
+}
+
+// swaggerDoc holds the definitions of docs/swagger.json.
+type swaggerDoc struct {
+	Definitions map[string]jsonSchema `json:"definitions"`
+}
+
+// typeOf returns the type of a Swagger property, following its references.
+func (d swaggerDoc) typeOf(property jsonSchema) string {
+	switch {
+	case property.Type != "":
+		return property.Type
+	case property.Ref != "":
+		return d.typeOf(d.Definitions[strings.TrimPrefix(property.Ref, "#/definitions/")])
+	case len(property.AllOf) > 0:
+		return d.typeOf(property.AllOf[0])
+	}
+	return ""
+}
+
+// bindingFields returns the fields of a binding struct by JSON name, with the names
+// of the required fields and of the fields required without another one.
+func bindingFields(typ reflect.Type) (fields map[string]reflect.StructField, required, requiredWithout []string) {
+	fields = make(map[string]reflect.StructField)
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		fields[name] = field
+
+		if _, ok := bindingRule(field, "required"); ok {
+			required = append(required, name)
+		}
+		if _, ok := bindingRule(field, "required_without"); ok {
+			requiredWithout = append(requiredWithout, name)
+		}
+	}
+	return fields, required, requiredWithout
+}
+
+// bindingRule returns the parameter of a rule of the binding tag of a field.
+func bindingRule(field reflect.StructField, rule string) (string, bool) {
+	for _, r := range strings.Split(field.Tag.Get("binding"), ",") {
+		name, param, _ := strings.Cut(r, "=")
+		if name == rule {
+			return param, true
+		}
+	}
+	return "", false
+}
+
+// jsonType returns the JSON type of the values of a Go type.
+func jsonType(typ reflect.Type) string {
+	if typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	switch {
+	case typ == reflect.TypeOf(time.Time{}):
+		return "string"
+	case typ.Kind() == reflect.String:
+		return "string"
+	case typ.Kind() == reflect.Int64:
+		return "integer"
+	case typ.Kind() == reflect.Slice:
+		return "array"
+	}
+	return typ.Kind().String()
+}
+
+// keys returns the sorted keys of a map.
+func keys[V any](m map[string]V) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
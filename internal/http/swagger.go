@@ -0,0 +1,88 @@
+package http
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"sync"
+	"task-manager/docs"
+
+	"github.com/gin-gonic/gin"
+	swaggerFiles "github.com/swaggo/files"
+	ginSwagger "github.com/swaggo/gin-swagger"
+	"github.com/swaggo/swag"
+)
+
+// DisableSwaggerEnv turns the Swagger UI and documents off when set.
+const DisableSwaggerEnv = "DISABLE_SWAGGER"
+
+// registeredDocs holds the names of the versions whose documents are registered, as swag
+// panics when a name is registered twice.
+var registeredDocs sync.Map
+
+// versionDoc is the Swagger document of an API version: the generated document
+// restricted to the paths of the version.
+type versionDoc struct {
+	version string
+}
+
+// ReadDoc returns the document, the generated one if it cannot be parsed.
+func (d versionDoc) ReadDoc() string {
+	doc := docs.SwaggerInfo.ReadDoc()
+
+	var spec map[string]any
+	if err := json.Unmarshal([]byte(doc), &spec); err != nil {
+		return doc
+	}
+
+	prefix := "/api/" + d.version + "/"
+	paths, _ := spec["paths"].(map[string]any)
+	for path := range paths {
+		if !strings.HasPrefix(path, prefix) {
+			delete(paths, path)
+		}
+	}
+	if info, ok := spec["info"].(map[string]any); ok {
+		info["version"] = d.version
+	}
+
+	filtered, err := json.Marshal(spec)
+	if err != nil {
+		return doc
+	}
+	return string(filtered)
+}
+
+// swaggerDocName returns the name the document of an API version is registered with.
+func swaggerDocName(version string) string {
+	return docs.SwaggerInfo.InstanceName() + "-" + version
+}
+
+// swagger serves the Swagger UI and the document of every API version under
+// /swagger/<version>/, and the complete document under /swagger/.
+func (h *Handler) swagger() gin.HandlerFunc {
+	if os.Getenv(DisableSwaggerEnv) != "" {
+		return ginSwagger.DisablingWrapHandler(swaggerFiles.Handler, DisableSwaggerEnv)
+	}
+
+	// Each UI keeps the path prefix it is first served under, so the versions do not
+	// share the file handler
+	versions := make(map[string]gin.HandlerFunc, len(h.APIVersions))
+	for _, version := range h.APIVersions {
+		name := swaggerDocName(version.Name)
+		if _, loaded := registeredDocs.LoadOrStore(name, true); !loaded {
+			swag.Register(name, versionDoc{version: version.Name})
+		}
+		versions[version.Name] = ginSwagger.WrapHandler(swaggerFiles.NewHandler(), ginSwagger.InstanceName(name))
+	}
+	all := ginSwagger.WrapHandler(swaggerFiles.Handler)
+
+	return func(c *gin.Context) {
+		version, _, _ := strings.Cut(strings.TrimPrefix(c.Param("any"), "/"), "/")
+		if handler, ok := versions[version]; ok {
+			handler(c)
+			return
+		}
+		all(c)
+	}
+}
@@ -1,15 +1,50 @@
 package http
 
 import (
+	"io"
 	"log/slog"
 	"os"
+	"task-manager/internal/auth"
 	"task-manager/internal/config"
 	"task-manager/internal/service"
+	"task-manager/internal/stream"
+	"task-manager/internal/tenant"
 	"task-manager/internal/utils"
 	"task-manager/pkg/logger"
 )
 
 func SetupHandler(taskService *service.MockTaskService) *Handler {
+	return SetupHandlerWithUsers(taskService, &service.MockUserService{})
+}
+
+// SetupHandlerWithLogs returns a handler writing its logs to w as JSON, with the
+// attributes of the request contexts, like the logger of the server.
+func SetupHandlerWithLogs(taskService *service.MockTaskService, w io.Writer) *Handler {
+	h := SetupHandler(taskService)
+	h.logger = &logger.StandardLogger{
+		Logger: slog.New(logger.NewContextHandler(slog.NewJSONHandler(w, nil), auth.LogAttrs, tenant.LogAttrs)),
+	}
+	return h
+}
+
+func SetupHandlerWithUsers(taskService *service.MockTaskService, userService *service.MockUserService) *Handler {
+	return SetupHandlerWithServices(taskService, userService, &service.MockWebhookService{}, &service.MockAPIKeyService{})
+}
+
+func SetupHandlerWithWebhooks(webhookService *service.MockWebhookService) *Handler {
+	return SetupHandlerWithServices(&service.MockTaskService{}, &service.MockUserService{}, webhookService, &service.MockAPIKeyService{})
+}
+
+func SetupHandlerWithAPIKeys(apiKeyService *service.MockAPIKeyService) *Handler {
+	return SetupHandlerWithServices(&service.MockTaskService{}, &service.MockUserService{}, &service.MockWebhookService{}, apiKeyService)
+}
+
+func SetupHandlerWithServices(
+	taskService *service.MockTaskService,
+	userService *service.MockUserService,
+	webhookService *service.MockWebhookService,
+	apiKeyService *service.MockAPIKeyService,
+) *Handler {
 	consoleHandler := slog.NewTextHandler(os.Stdout, nil)
 
 	slogLogger := slog.New(consoleHandler)
@@ -18,5 +53,5 @@ func SetupHandler(taskService *service.MockTaskService) *Handler {
 		Logger: slogLogger,
 	}
 
-	return CreateHandler(myLogger, config.Config{}, taskService, utils.InitGlobalTaskMetrics())
+	return CreateHandler(myLogger, config.Config{}, taskService, userService, webhookService, apiKeyService, stream.NewHub(stream.Settings{}), utils.InitGlobalTaskMetrics())
 }
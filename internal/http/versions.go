@@ -0,0 +1,83 @@
+package http
+
+import (
+	"strings"
+	"task-manager/internal/config"
+	"task-manager/internal/entities"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LegacyAPIVersion is the version served by the unversioned /api/ routes, kept for the
+// clients predating the versions.
+const LegacyAPIVersion = "v1"
+
+// APIVersion is a version of the REST API, mounted under /api/<Name>/. The versions
+// share the services and the handlers of the routes whose DTOs did not change; Tasks
+// registers the other task routes, whose handlers map the DTOs of the version with
+// their TaskMapper.
+type APIVersion struct {
+	Name        string
+	Deprecation time.Time // When the version is deprecated, zero if it is not
+	Sunset      time.Time // When the version is removed, zero if not scheduled
+	Successor   string    // Version to migrate to, linked once deprecated
+
+	Tasks func(h *Handler, tasks gin.IRoutes) // Registers the task routes specific to the version
+}
+
+// TaskMapper maps the task DTOs of an API version from and to the task entities, so
+// that the handlers of every version share the TaskService.
+type TaskMapper interface {
+	// CreateTask binds the body of a create request.
+	CreateTask(c *gin.Context) (*entities.Task, error)
+	// UpdateTask binds the body of an update request of the task id.
+	UpdateTask(c *gin.Context, id int64) (*entities.Task, error)
+	// TaskResponse maps a task, embedding its assignee from assignees, nil unless
+	// asked for.
+	TaskResponse(task *entities.Task, assignees map[int64]*AssigneeResponse) any
+}
+
+// DefaultAPIVersions returns the versions of the REST API, v1 being deprecated in favor
+// of v2 at the dates of the configuration.
+func DefaultAPIVersions(cfg config.APIConfig) []APIVersion {
+	return []APIVersion{
+		{Name: "v1", Deprecation: cfg.V1Deprecation.Time, Sunset: cfg.V1Sunset.Time, Successor: "v2", Tasks: (*Handler).taskRoutesV1},
+		{Name: "v2", Tasks: (*Handler).taskRoutesV2},
+	}
+}
+
+// taskRoutesV1 registers the task routes mapping the v1 DTOs.
+func (h *Handler) taskRoutesV1(tasks gin.IRoutes) {
+	tasks.POST("", h.validated("v1", CreateTaskSchema), h.TaskCreate)
+	tasks.GET("", h.TaskList)
+	tasks.GET(":id", h.TaskGetByID)
+	tasks.PUT(":id", h.validated("v1", UpdateTaskSchema), h.TaskUpdate)
+	tasks.POST(":id/assignees", h.TaskAddAssignee)
+	tasks.POST(":id/watchers", h.TaskAddWatcher)
+}
+
+// taskRoutesV2 registers the task routes mapping the v2 DTOs.
+func (h *Handler) taskRoutesV2(tasks gin.IRoutes) {
+	tasks.POST("", h.validated("v2", CreateTaskSchema), h.TaskCreateV2)
+	tasks.GET("", h.TaskListV2)
+	tasks.GET(":id", h.TaskGetByIDV2)
+	tasks.PUT(":id", h.validated("v2", UpdateTaskSchema), h.TaskUpdateV2)
+	tasks.POST(":id/assignees", h.TaskAddAssigneeV2)
+	tasks.POST(":id/watchers", h.TaskAddWatcherV2)
+}
+
+// unversionedRoute returns the route without its API version, e.g. /api/tasks/:id for
+// /api/v2/tasks/:id, so the settings written for a route apply to every version.
+func unversionedRoute(route string) string {
+	rest, ok := strings.CutPrefix(route, "/api/v")
+	if !ok {
+		return route
+	}
+
+	number, path, found := strings.Cut(rest, "/")
+	if !found || number == "" || strings.Trim(number, "0123456789") != "" {
+		return route
+	}
+	return "/api/" + path
+}
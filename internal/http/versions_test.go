@@ -0,0 +1,224 @@
+package http_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"task-manager/internal/config"
+	"task-manager/internal/entities"
+	HTTPhandler "task-manager/internal/http"
+	"task-manager/internal/service"
+	"task-manager/pkg/rest"
+	"testing"
+	"time"
+)
+
+// TestTaskGetByID_Versions_ShouldMapTheVersionDTO tests that every version maps the task
+// returned by the shared service with its own DTO, the legacy routes serving v1.
+func TestTaskGetByID_Versions_ShouldMapTheVersionDTO(t *testing.T) {
+	task := &entities.Task{
+		ID:          12,
+		Title:       "Test",
+		Status:      entities.TaskStatusPending,
+		AssigneeID:  10,
+		AssigneeIDs: []int64{11, 10},
+	}
+
+	tests := []struct {
+		path    string
+		present []string
+		absent  []string
+	}{
+		{path: "/api/tasks/12", present: []string{"assignee_id", "assignee_ids"}, absent: []string{"due_at", "description"}},
+		{path: "/api/v1/tasks/12", present: []string{"assignee_id", "assignee_ids"}, absent: []string{"due_at", "description"}},
+		{path: "/api/v2/tasks/12", present: []string{"assignee_ids", "watcher_ids", "due_at", "description"}, absent: []string{"assignee_id"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			taskService := service.MockTaskService{}
+			taskService.On("GetByID", mock.Anything, int64(12)).Return(task, nil)
+			router := HTTPhandler.SetupHandler(&taskService).SetupRouter()
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			var resp struct {
+				Data map[string]json.RawMessage `json:"data"`
+			}
+			require.Equal(t, http.StatusOK, w.Code)
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			for _, field := range tt.present {
+				assert.Contains(t, resp.Data, field)
+			}
+			for _, field := range tt.absent {
+				assert.NotContains(t, resp.Data, field)
+			}
+			taskService.AssertExpectations(t)
+		})
+	}
+}
+
+// TestTaskGetByIDV2_ShouldListThePrimaryAssigneeFirst tests that the v2 response lists
+// the primary assignee first in place of assignee_id.
+func TestTaskGetByIDV2_ShouldListThePrimaryAssigneeFirst(t *testing.T) {
+	taskService := service.MockTaskService{}
+	taskService.On("GetByID", mock.Anything, int64(12)).Return(&entities.Task{
+		ID:          12,
+		Title:       "Test",
+		Status:      entities.TaskStatusPending,
+		AssigneeID:  10,
+		AssigneeIDs: []int64{11, 10},
+	}, nil)
+	router := HTTPhandler.SetupHandler(&taskService).SetupRouter()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v2/tasks/12", nil))
+
+	var resp struct {
+		Data HTTPhandler.TaskResponseV2 `json:"data"`
+	}
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, []int64{10, 11}, resp.Data.AssigneeIDs)
+	assert.Equal(t, []int64{}, resp.Data.WatcherIDs)
+	assert.Nil(t, resp.Data.DueAt)
+	taskService.AssertExpectations(t)
+}
+
+// TestTaskCreateV2_ShouldMapAssigneeIDs tests that v2 creates the task with the first
+// assignee as the primary assignee.
+func TestTaskCreateV2_ShouldMapAssigneeIDs(t *testing.T) {
+	taskService := service.MockTaskService{}
+	taskService.On("Create", mock.Anything, mock.MatchedBy(func(task *entities.Task) bool {
+		return task.AssigneeID == 10 && slices.Equal(task.AssigneeIDs, []int64{10, 11}) && task.Status == entities.TaskStatusPending
+	})).Return(&entities.Task{ID: 12, Title: "Test", Status: entities.TaskStatusPending, AssigneeID: 10, AssigneeIDs: []int64{10, 11}}, nil)
+	router := HTTPhandler.SetupHandler(&taskService).SetupRouter()
+
+	body := `{"title":"Test","assignee_ids":[10,11]}`
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v2/tasks/", bytes.NewBufferString(body)))
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	taskService.AssertExpectations(t)
+}
+
+// TestTaskCreateV2_AssigneeID_ShouldFailValidation tests that the v2 bodies are validated
+// against the v2 schema, which replaced assignee_id by assignee_ids.
+func TestTaskCreateV2_AssigneeID_ShouldFailValidation(t *testing.T) {
+	taskService := service.MockTaskService{}
+	router := HTTPhandler.SetupHandler(&taskService).SetupRouter()
+
+	body := `{"title":"Test","assignee_id":10}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v2/tasks/", bytes.NewBufferString(body))
+	req.Header.Set("Accept", rest.ProblemContentType)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var problem rest.Problem
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, "VALIDATION_FAILED", problem.Code)
+	pointers := make([]string, len(problem.Errors))
+	for i, e := range problem.Errors {
+		pointers[i] = e.Pointer
+	}
+	assert.Equal(t, []string{"/assignee_id", "/assignee_ids"}, pointers)
+	taskService.AssertExpectations(t)
+}
+
+// TestVersionMiddleware_Deprecated_ShouldSetDeprecationHeaders tests that the routes of a
+// deprecated version, legacy routes included, announce its deprecation, sunset and
+// successor.
+func TestVersionMiddleware_Deprecated_ShouldSetDeprecationHeaders(t *testing.T) {
+	deprecation := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	sunset := time.Date(2026, time.July, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		path       string
+		deprecated bool
+	}{
+		{path: "/api/tasks/12", deprecated: true},
+		{path: "/api/v1/tasks/12", deprecated: true},
+		{path: "/api/v1/users/1", deprecated: true},
+		{path: "/api/v2/tasks/12", deprecated: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			taskService := service.MockTaskService{}
+			taskService.On("GetByID", mock.Anything, int64(12)).Return(&stubTask, nil).Maybe()
+			userService := service.MockUserService{}
+			userService.On("GetByID", mock.Anything, int64(1)).Return(&entities.User{ID: 1}, nil).Maybe()
+
+			h := HTTPhandler.SetupHandlerWithUsers(&taskService, &userService)
+			h.APIVersions = HTTPhandler.DefaultAPIVersions(config.APIConfig{
+				V1Deprecation: config.Timestamp{Time: deprecation},
+				V1Sunset:      config.Timestamp{Time: sunset},
+			})
+			router := h.SetupRouter()
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			require.Equal(t, http.StatusOK, w.Code)
+			if !tt.deprecated {
+				assert.Empty(t, w.Header().Get("Deprecation"))
+				assert.Empty(t, w.Header().Get("Sunset"))
+				assert.Empty(t, w.Header().Get("Link"))
+				return
+			}
+			assert.Equal(t, "@1767225600", w.Header().Get("Deprecation"))
+			assert.Equal(t, "Wed, 01 Jul 2026 00:00:00 GMT", w.Header().Get("Sunset"))
+			assert.Equal(t, `</api/v2/>; rel="successor-version"`, w.Header().Get("Link"))
+		})
+	}
+}
+
+// TestSwagger_Version_ShouldServeThePathsOfTheVersion tests that the Swagger document of
+// a version only describes the paths of that version.
+func TestSwagger_Version_ShouldServeThePathsOfTheVersion(t *testing.T) {
+	router := HTTPhandler.SetupHandler(&service.MockTaskService{}).SetupRouter()
+
+	tests := []struct {
+		path    string
+		version string
+		want    []string
+	}{
+		{path: "/swagger/doc.json", version: "1.0", want: []string{"/api/v1/", "/api/v2/", "/health", "/healthz", "/readyz", "/startupz", "/version"}},
+		{path: "/swagger/v1/doc.json", version: "v1", want: []string{"/api/v1/"}},
+		{path: "/swagger/v2/doc.json", version: "v2", want: []string{"/api/v2/"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			var doc struct {
+				Info  struct{ Version string } `json:"info"`
+				Paths map[string]any           `json:"paths"`
+			}
+			require.Equal(t, http.StatusOK, w.Code)
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &doc))
+			assert.Equal(t, tt.version, doc.Info.Version)
+
+			// The paths of the versions by prefix, the others as is
+			prefixes := make(map[string]bool)
+			for path := range doc.Paths {
+				if strings.HasPrefix(path, "/api/v") {
+					path = path[:len("/api/vN/")]
+				}
+				prefixes[path] = true
+			}
+			assert.ElementsMatch(t, tt.want, keys(prefixes))
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package idempotency
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"task-manager/internal/cache"
+	"task-manager/internal/entities"
+	"time"
+
+	"github.com/cockroachdb/errors"
+)
+
+// cachePrefix prefixes the keys in the cache.
+const cachePrefix = "idempotency:"
+
+// CacheStore is the Store keeping the keys in a cache.Cache, which expires them.
+//
+// Reservations are atomic within an instance only: with a cache shared by several
+// instances, such as Redis, concurrent duplicates hitting different instances at the
+// same time may both run. Use the database store when that matters.
+type CacheStore struct {
+	mu    sync.Mutex
+	cache cache.Cache
+	now   func() time.Time
+}
+
+// NewCacheStore
+//
+// Returns a Store keeping the keys in c.
+func NewCacheStore(c cache.Cache) *CacheStore {
+	return &CacheStore{cache: c, now: time.Now}
+}
+
+// Reserve implements Store.
+func (s *CacheStore) Reserve(_ context.Context, key, fingerprint string, ttl time.Duration) (*entities.IdempotencyKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if record, ok, err := s.get(key); err != nil || ok {
+		return record, err
+	}
+
+	now := s.now()
+	return nil, s.set(&entities.IdempotencyKey{
+		Key:         key,
+		Fingerprint: fingerprint,
+		ExpiresAt:   now.Add(ttl),
+		CreatedAt:   now,
+	})
+}
+
+// Complete implements Store.
+func (s *CacheStore) Complete(_ context.Context, key string, response entities.StoredResponse) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record, ok, err := s.get(key)
+	if err != nil || !ok {
+		return err
+	}
+
+	record.Response = &response
+	return s.set(record)
+}
+
+// Release implements Store.
+func (s *CacheStore) Release(_ context.Context, key string) error {
+	s.cache.Delete(cachePrefix + key)
+	return nil
+}
+
+// get returns the record of key, if cached.
+func (s *CacheStore) get(key string) (*entities.IdempotencyKey, bool, error) {
+	value, ok := s.cache.Get(cachePrefix + key)
+	if !ok {
+		return nil, false, nil
+	}
+
+	var record entities.IdempotencyKey
+	if err := json.Unmarshal([]byte(value), &record); err != nil {
+		return nil, false, errors.Wrap(err, "failed to decode idempotency key")
+	}
+
+	return &record, true, nil
+}
+
+// set caches the record until it expires.
+func (s *CacheStore) set(record *entities.IdempotencyKey) error {
+	value, err := json.Marshal(record)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode idempotency key")
+	}
+
+	s.cache.Set(cachePrefix+record.Key, string(value), record.ExpiresAt.Sub(s.now()))
+	return nil
+}
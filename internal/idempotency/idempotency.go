@@ -0,0 +1,75 @@
+// Package idempotency makes the retries of a request safe: the response of a request
+// sent with an Idempotency-Key header is stored, and replayed to the requests sent
+// again with the same key instead of running them twice.
+//
+// Keys are reserved in a Store before the request runs, so concurrent duplicates are
+// told apart from retries, and bound to a fingerprint of the request, so a key can't
+// be reused for another request.
+package idempotency
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"task-manager/internal/entities"
+	"time"
+)
+
+// Header is the request header carrying the idempotency key.
+const Header = "Idempotency-Key"
+
+// ReplayedHeader marks the responses replayed from a previous request.
+const ReplayedHeader = "Idempotent-Replayed"
+
+// MaxKeyLength is the length limit of the keys sent by the clients.
+const MaxKeyLength = 255
+
+// DefaultTTL is how long the keys are remembered, when not configured.
+const DefaultTTL = 24 * time.Hour
+
+// Backends of the keys.
+const (
+	BackendDB     = "db"
+	BackendMemory = "memory"
+	BackendRedis  = "redis"
+)
+
+// Store keeps the idempotency keys and their responses.
+type Store interface {
+	// Reserve records key as in flight for the request of fingerprint, for ttl.
+	// Returns nil if the key was reserved, the record of the key if it is already
+	// known: in flight, or completed with its response.
+	Reserve(ctx context.Context, key, fingerprint string, ttl time.Duration) (*entities.IdempotencyKey, error)
+
+	// Complete stores the response of the request of a reserved key.
+	Complete(ctx context.Context, key string, response entities.StoredResponse) error
+
+	// Release forgets a reserved key whose request failed, so it can be retried.
+	Release(ctx context.Context, key string) error
+}
+
+// Purger deletes the expired keys of a Store that does not expire them itself.
+type Purger interface {
+	// Purge deletes up to limit keys expired before the given time and returns the
+	// number of deleted keys.
+	Purge(ctx context.Context, before time.Time, limit int) (int, error)
+}
+
+// Fingerprint
+//
+// Returns the hash identifying a request: its method, path and body.
+func Fingerprint(method, path string, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(method + " " + path + "\n"))
+	hash.Write(body)
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// ScopedKey
+//
+// Returns the key of a Store for the key sent by a client of a tenant: clients can't
+// replay the responses of each other.
+func ScopedKey(tenant, client, key string) string {
+	return tenant + "/" + client + "/" + key
+}
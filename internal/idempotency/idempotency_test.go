@@ -0,0 +1,68 @@
+package idempotency_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"task-manager/internal/cache"
+	"task-manager/internal/entities"
+	"task-manager/internal/idempotency"
+	"testing"
+	"time"
+)
+
+// TestFingerprint tests that requests differing by method, path or body differ.
+func TestFingerprint(t *testing.T) {
+	fingerprint := idempotency.Fingerprint("POST", "/api/tasks/", []byte(`{"title":"a"}`))
+
+	assert.Len(t, fingerprint, 64)
+	assert.Equal(t, fingerprint, idempotency.Fingerprint("POST", "/api/tasks/", []byte(`{"title":"a"}`)))
+	assert.NotEqual(t, fingerprint, idempotency.Fingerprint("POST", "/api/tasks/", []byte(`{"title":"b"}`)))
+	assert.NotEqual(t, fingerprint, idempotency.Fingerprint("POST", "/api/users/", []byte(`{"title":"a"}`)))
+	assert.NotEqual(t, fingerprint, idempotency.Fingerprint("PUT", "/api/tasks/", []byte(`{"title":"a"}`)))
+}
+
+// TestCacheStore tests the lifecycle of a key: reserved, in flight, completed, and
+// reserved again once released.
+func TestCacheStore(t *testing.T) {
+	ctx := context.Background()
+	store := idempotency.NewCacheStore(cache.NewMemory())
+
+	record, err := store.Reserve(ctx, "key", "fingerprint", time.Hour)
+	require.NoError(t, err)
+	assert.Nil(t, record)
+
+	record, err = store.Reserve(ctx, "key", "fingerprint", time.Hour)
+	require.NoError(t, err)
+	require.NotNil(t, record)
+	assert.False(t, record.Completed())
+
+	response := entities.StoredResponse{StatusCode: 201, Header: map[string]string{"Content-Type": "application/json"}, Body: []byte(`{}`)}
+	require.NoError(t, store.Complete(ctx, "key", response))
+
+	record, err = store.Reserve(ctx, "key", "other", time.Hour)
+	require.NoError(t, err)
+	require.True(t, record.Completed())
+	assert.Equal(t, "fingerprint", record.Fingerprint)
+	assert.Equal(t, response, *record.Response)
+
+	_, _ = store.Reserve(ctx, "failed", "fingerprint", time.Hour)
+	require.NoError(t, store.Release(ctx, "failed"))
+	record, err = store.Reserve(ctx, "failed", "fingerprint", time.Hour)
+	require.NoError(t, err)
+	assert.Nil(t, record)
+}
+
+// TestCacheStore_Expired_ShouldReserveAgain tests that keys are forgotten after their ttl.
+func TestCacheStore_Expired_ShouldReserveAgain(t *testing.T) {
+	ctx := context.Background()
+	store := idempotency.NewCacheStore(cache.NewMemory())
+
+	_, err := store.Reserve(ctx, "key", "fingerprint", time.Millisecond)
+	require.NoError(t, err)
+	time.Sleep(5 * time.Millisecond)
+
+	record, err := store.Reserve(ctx, "key", "other", time.Hour)
+	require.NoError(t, err)
+	assert.Nil(t, record)
+}
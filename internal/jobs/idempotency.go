@@ -0,0 +1,30 @@
+package jobs
+
+import (
+	"context"
+	"task-manager/internal/idempotency"
+	"time"
+)
+
+// idempotencyPurgeBatchSize is the max number of keys deleted per run.
+const idempotencyPurgeBatchSize = 1000
+
+// IdempotencyPurgeJob deletes the expired idempotency keys of the database.
+type IdempotencyPurgeJob struct {
+	purger idempotency.Purger
+}
+
+// NewIdempotencyPurgeJob returns an IdempotencyPurgeJob backed by the given purger.
+func NewIdempotencyPurgeJob(purger idempotency.Purger) *IdempotencyPurgeJob {
+	return &IdempotencyPurgeJob{purger: purger}
+}
+
+// Name implements Job.
+func (j *IdempotencyPurgeJob) Name() string {
+	return "idempotency_purge"
+}
+
+// Run implements Job.
+func (j *IdempotencyPurgeJob) Run(ctx context.Context) (int, error) {
+	return j.purger.Purge(ctx, time.Now(), idempotencyPurgeBatchSize)
+}
@@ -0,0 +1,27 @@
+package jobs
+
+import (
+	"context"
+	"task-manager/internal/service"
+	"time"
+)
+
+// OutboxRelayJob relays the task events written to the outbox to the event sinks.
+type OutboxRelayJob struct {
+	service service.OutboxService
+}
+
+// NewOutboxRelayJob returns an OutboxRelayJob backed by the given service.
+func NewOutboxRelayJob(service service.OutboxService) *OutboxRelayJob {
+	return &OutboxRelayJob{service: service}
+}
+
+// Name implements Job.
+func (j *OutboxRelayJob) Name() string {
+	return "outbox_relay"
+}
+
+// Run implements Job.
+func (j *OutboxRelayJob) Run(ctx context.Context) (int, error) {
+	return j.service.Relay(ctx, time.Now())
+}
@@ -0,0 +1,39 @@
+package jobs
+
+import (
+	"context"
+	"task-manager/internal/service"
+	"task-manager/pkg/logger"
+	"time"
+)
+
+// RecurrenceJob materializes the next occurrence of recurring tasks that were
+// completed, canceled or became overdue since the previous run.
+type RecurrenceJob struct {
+	service service.RecurrenceService
+	logger  logger.Logger
+}
+
+// NewRecurrenceJob returns a RecurrenceJob backed by the given service.
+func NewRecurrenceJob(service service.RecurrenceService, logger logger.Logger) *RecurrenceJob {
+	return &RecurrenceJob{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// Name implements Job.
+func (j *RecurrenceJob) Name() string {
+	return "recurrence"
+}
+
+// Run implements Job.
+func (j *RecurrenceJob) Run(ctx context.Context) (int, error) {
+	created, err := j.service.MaterializeDue(ctx, time.Now())
+
+	if len(created) > 0 {
+		j.logger.InfoF("materialized %d recurring task occurrence(s)", len(created))
+	}
+
+	return len(created), err
+}
@@ -0,0 +1,27 @@
+package jobs
+
+import (
+	"context"
+	"task-manager/internal/service"
+	"time"
+)
+
+// ReminderJob emits due-date reminders and overdue escalations for open tasks.
+type ReminderJob struct {
+	service service.ReminderService
+}
+
+// NewReminderJob returns a ReminderJob backed by the given service.
+func NewReminderJob(service service.ReminderService) *ReminderJob {
+	return &ReminderJob{service: service}
+}
+
+// Name implements Job.
+func (j *ReminderJob) Name() string {
+	return "reminder"
+}
+
+// Run implements Job.
+func (j *ReminderJob) Run(ctx context.Context) (int, error) {
+	return j.service.SendDue(ctx, time.Now())
+}
@@ -0,0 +1,112 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"task-manager/internal/authz"
+	"task-manager/pkg/logger"
+	"task-manager/pkg/monitoring"
+	"time"
+)
+
+// MinStallTimeout is the least time without completed run before a job is reported
+// stalled, 3 intervals otherwise.
+const MinStallTimeout = time.Minute
+
+// Job is a unit of background work executed periodically by a Runner.
+// Implementations must be safe to run concurrently on several instances of the
+// application, e.g. by locking the rows they process.
+// Run returns the number of items processed, reported in the job metrics.
+type Job interface {
+	Name() string
+	Run(ctx context.Context) (int, error)
+}
+
+// Runner executes a Job on a fixed interval until its context is canceled.
+type Runner struct {
+	job      Job
+	interval time.Duration
+	logger   logger.Logger
+	metrics  *monitoring.JobMetrics
+
+	running   atomic.Bool
+	heartbeat atomic.Int64 // Unix nanoseconds of the start or of the last completed run
+}
+
+// NewRunner returns a Runner executing job every interval and recording every run in metrics.
+func NewRunner(job Job, interval time.Duration, logger logger.Logger, metrics *monitoring.JobMetrics) *Runner {
+	return &Runner{
+		job:      job,
+		interval: interval,
+		logger:   logger,
+		metrics:  metrics,
+	}
+}
+
+// Run executes the job immediately and then on every tick. It blocks until ctx is
+// canceled; a failing run is logged and retried on the next tick.
+func (r *Runner) Run(ctx context.Context) {
+	r.logger.InfoF("[OK] Starting background job %s every %s", r.job.Name(), r.interval)
+
+	r.heartbeat.Store(time.Now().UnixNano())
+	r.running.Store(true)
+	defer r.running.Store(false)
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		r.runOnce(ctx)
+
+		select {
+		case <-ctx.Done():
+			r.logger.InfoF("[OK] Background job %s stopped", r.job.Name())
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runOnce executes the job a single time and records the outcome. Jobs run as
+// internal callers, which the authorization policy does not restrict.
+func (r *Runner) runOnce(ctx context.Context) {
+	name := r.job.Name()
+	start := time.Now()
+
+	processed, err := r.job.Run(authz.WithInternalCaller(ctx))
+	r.heartbeat.Store(time.Now().UnixNano())
+
+	r.metrics.Duration.WithLabelValues(name).Observe(time.Since(start).Seconds())
+	r.metrics.Processed.WithLabelValues(name).Add(float64(processed))
+
+	if err != nil {
+		r.metrics.Runs.WithLabelValues(name, "error").Inc()
+		if ctx.Err() == nil {
+			r.logger.ErrorF("background job %s failed: %v", name, err)
+		}
+		return
+	}
+
+	r.metrics.Runs.WithLabelValues(name, "success").Inc()
+	r.metrics.LastSuccess.WithLabelValues(name).SetToCurrentTime()
+}
+
+// Check fails when the job is not running, or completed no run for 3 intervals, at
+// least MinStallTimeout. It implements health.Checker.
+func (r *Runner) Check(context.Context) error {
+	if !r.running.Load() {
+		return fmt.Errorf("background job %s is not running", r.job.Name())
+	}
+
+	stallTimeout := max(3*r.interval, MinStallTimeout)
+	if since := time.Since(time.Unix(0, r.heartbeat.Load())); since > stallTimeout {
+		return fmt.Errorf("background job %s completed no run for %s", r.job.Name(), since.Round(time.Second))
+	}
+	return nil
+}
+
+// Name returns the name of the job.
+func (r *Runner) Name() string {
+	return r.job.Name()
+}
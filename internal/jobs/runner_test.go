@@ -0,0 +1,88 @@
+package jobs_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync/atomic"
+	"task-manager/internal/jobs"
+	"task-manager/internal/utils"
+	"task-manager/pkg/logger"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeJob counts its runs and fails every other one.
+type fakeJob struct {
+	name string
+	runs atomic.Int32
+}
+
+func (j *fakeJob) Name() string { return j.name }
+
+func (j *fakeJob) Run(context.Context) (int, error) {
+	if j.runs.Add(1)%2 == 0 {
+		return 0, errors.New("boom")
+	}
+	return 3, nil
+}
+
+// TestRunner_RecordsRunsUntilCanceled verifies that the runner keeps running the job
+// on every tick, survives failures, records metrics and returns once canceled.
+func TestRunner_RecordsRunsUntilCanceled(t *testing.T) {
+	job := &fakeJob{name: "fake"}
+	metrics := utils.InitGlobalJobMetrics()
+	log := &logger.StandardLogger{Logger: slog.New(slog.NewTextHandler(io.Discard, nil))}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	go func() {
+		jobs.NewRunner(job, 5*time.Millisecond, log, metrics).Run(ctx)
+		close(done)
+	}()
+
+	assert.Eventually(t, func() bool { return job.runs.Load() >= 4 }, time.Second, time.Millisecond)
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("runner did not stop after cancel")
+	}
+
+	runs := job.runs.Load()
+	successes := testutil.ToFloat64(metrics.Runs.WithLabelValues("fake", "success"))
+	failures := testutil.ToFloat64(metrics.Runs.WithLabelValues("fake", "error"))
+
+	assert.Equal(t, float64(runs), successes+failures)
+	assert.GreaterOrEqual(t, failures, float64(2))
+	assert.Equal(t, 3*successes, testutil.ToFloat64(metrics.Processed.WithLabelValues("fake")))
+}
+
+// TestRunner_Check_ShouldReportWhetherTheJobRuns verifies that the health check of a
+// runner passes only while the job runs.
+func TestRunner_Check_ShouldReportWhetherTheJobRuns(t *testing.T) {
+	job := &fakeJob{name: "fake"}
+	log := &logger.StandardLogger{Logger: slog.New(slog.NewTextHandler(io.Discard, nil))}
+	runner := jobs.NewRunner(job, 5*time.Millisecond, log, utils.InitGlobalJobMetrics())
+
+	assert.EqualError(t, runner.Check(context.Background()), "background job fake is not running")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		runner.Run(ctx)
+		close(done)
+	}()
+
+	assert.Eventually(t, func() bool { return runner.Check(context.Background()) == nil }, time.Second, time.Millisecond)
+	cancel()
+	<-done
+
+	assert.EqualError(t, runner.Check(context.Background()), "background job fake is not running")
+}
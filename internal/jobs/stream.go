@@ -0,0 +1,28 @@
+package jobs
+
+import (
+	"context"
+	"task-manager/internal/stream"
+	"time"
+)
+
+// StreamTailJob publishes the task events written to the outbox to the live streams
+// of this instance.
+type StreamTailJob struct {
+	tailer *stream.Tailer
+}
+
+// NewStreamTailJob returns a StreamTailJob backed by the given tailer.
+func NewStreamTailJob(tailer *stream.Tailer) *StreamTailJob {
+	return &StreamTailJob{tailer: tailer}
+}
+
+// Name implements Job.
+func (j *StreamTailJob) Name() string {
+	return "stream_tail"
+}
+
+// Run implements Job.
+func (j *StreamTailJob) Run(ctx context.Context) (int, error) {
+	return j.tailer.Poll(ctx, time.Now())
+}
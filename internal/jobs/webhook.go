@@ -0,0 +1,27 @@
+package jobs
+
+import (
+	"context"
+	"task-manager/internal/service"
+	"time"
+)
+
+// WebhookDeliveryJob sends the queued webhook deliveries that are due.
+type WebhookDeliveryJob struct {
+	service service.WebhookService
+}
+
+// NewWebhookDeliveryJob returns a WebhookDeliveryJob backed by the given service.
+func NewWebhookDeliveryJob(service service.WebhookService) *WebhookDeliveryJob {
+	return &WebhookDeliveryJob{service: service}
+}
+
+// Name implements Job.
+func (j *WebhookDeliveryJob) Name() string {
+	return "webhook_delivery"
+}
+
+// Run implements Job.
+func (j *WebhookDeliveryJob) Run(ctx context.Context) (int, error) {
+	return j.service.DeliverDue(ctx, time.Now())
+}
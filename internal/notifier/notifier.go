@@ -0,0 +1,68 @@
+package notifier
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"task-manager/internal/entities"
+	"task-manager/pkg/logger"
+)
+
+// Event describes a deadline notification for a task.
+type Event struct {
+	Kind       entities.ReminderKind // due_soon or overdue (escalation)
+	Task       entities.Task         // Task the deadline belongs to
+	Recipients []int64               // IDs of the users to notify
+	At         time.Time             // Time the event was emitted
+}
+
+// Notifier delivers deadline notifications. Implementations decide on the channel
+// (log, email, chat, webhooks...) and must return an error if the event was not
+// delivered, so it is retried on the next run.
+type Notifier interface {
+	Notify(ctx context.Context, event Event) error
+}
+
+// -----------------------------------------------------------------------------
+// Log notifier
+// -----------------------------------------------------------------------------
+
+// Log writes every event to the application logger. It is the default notifier
+// when no other channel is configured.
+type Log struct {
+	logger logger.Logger
+}
+
+// NewLogNotifier returns a Notifier writing events to the given logger.
+func NewLogNotifier(logger logger.Logger) *Log {
+	return &Log{logger: logger}
+}
+
+// Notify implements Notifier.
+func (n *Log) Notify(_ context.Context, event Event) error {
+	n.logger.InfoF("task %d %s (due at %s), notifying users %v",
+		event.Task.ID, event.Kind, event.Task.DueAt.Format(time.RFC3339), event.Recipients)
+
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+// Multi notifier
+// -----------------------------------------------------------------------------
+
+// Multi fans an event out to several notifiers. Every notifier is called even if
+// a previous one failed; the errors are joined and the whole event is retried.
+type Multi []Notifier
+
+// Notify implements Notifier.
+func (m Multi) Notify(ctx context.Context, event Event) error {
+	var errs []error
+	for _, n := range m {
+		if err := n.Notify(ctx, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
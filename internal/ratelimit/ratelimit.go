@@ -0,0 +1,186 @@
+// Package ratelimit limits how many requests each client of the API may make.
+//
+// Every client (principal, API key or IP address) gets a token bucket per Rule: the
+// bucket holds up to Limit.Requests tokens, refilled at Limit.Requests per
+// Limit.Window, and every request takes one. Buckets live in a Limiter backend, in
+// memory for a single instance or in Redis to share them between instances.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+)
+
+// ErrInvalidRule is returned when a rule can't be parsed.
+var ErrInvalidRule = errors.New(`rate limit rules are "[METHOD ]PATH=REQUESTS/WINDOW", e.g. "POST /api/tasks/=10/1m", 0 requests exempting the route`)
+
+// DefaultLimit is the limit of the routes without rule, when none is configured.
+var DefaultLimit = Limit{Requests: 100, Window: time.Minute}
+
+// DefaultIPLimit is the limit of every IP address before the authentication, when
+// none is configured. It bounds the credentials an address may try.
+var DefaultIPLimit = Limit{Requests: 300, Window: time.Minute}
+
+// Backends of the buckets.
+const (
+	BackendMemory = "memory"
+	BackendRedis  = "redis"
+)
+
+// AnyMethod matches the requests of every method.
+const AnyMethod = "*"
+
+// Limit is the size and refill rate of a bucket.
+type Limit struct {
+	Requests int           // Bucket size, and tokens refilled per Window
+	Window   time.Duration // Time to refill a bucket
+}
+
+// Enabled reports whether the limit restricts anything.
+func (l Limit) Enabled() bool {
+	return l.Requests > 0 && l.Window > 0
+}
+
+// String returns the limit as "REQUESTS/WINDOW".
+func (l Limit) String() string {
+	return fmt.Sprintf("%d/%s", l.Requests, l.Window)
+}
+
+// rate returns the tokens refilled per second.
+func (l Limit) rate() float64 {
+	return float64(l.Requests) / l.Window.Seconds()
+}
+
+// Result is the state of a bucket after a request.
+type Result struct {
+	Allowed    bool          // Whether the request took a token
+	Limit      Limit         // Limit of the bucket
+	Remaining  int           // Whole tokens left
+	Reset      time.Duration // Time until the bucket is full again
+	RetryAfter time.Duration // Time until the next token, zero if the request was allowed
+}
+
+// result builds the Result of a request leaving tokens in the bucket.
+func result(allowed bool, limit Limit, tokens float64) Result {
+	rate := limit.rate()
+	res := Result{
+		Allowed:   allowed,
+		Limit:     limit,
+		Remaining: int(math.Floor(tokens)),
+		Reset:     seconds((float64(limit.Requests) - tokens) / rate),
+	}
+	if !allowed {
+		res.RetryAfter = seconds((1 - tokens) / rate)
+	}
+
+	return res
+}
+
+// seconds converts seconds to a duration.
+func seconds(s float64) time.Duration {
+	return time.Duration(math.Max(s, 0) * float64(time.Second))
+}
+
+// Limiter is a backend of the buckets.
+type Limiter interface {
+	// Allow takes a token from the bucket of key, created full with limit if it
+	// does not exist.
+	Allow(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// Rule is the limit of the requests to a route.
+type Rule struct {
+	Method string // HTTP method, AnyMethod for all
+	Path   string // Route pattern, e.g. /api/tasks/:id
+	Limit  Limit
+}
+
+// Name identifies the buckets of the rule.
+func (r Rule) Name() string {
+	return r.Method + " " + r.Path
+}
+
+// ParseRule
+//
+// Parses a rule written "[METHOD ]PATH=REQUESTS/WINDOW", e.g. "POST /api/tasks/=10/1m".
+// Rules without method apply to every method of the route. 0 requests exempts the
+// route from limiting, e.g. "/api/tasks/stream=0/1m" for long-lived connections;
+// negative requests are rejected.
+func ParseRule(s string) (Rule, error) {
+	route, limit, found := strings.Cut(strings.TrimSpace(s), "=")
+	requests, window, ok := strings.Cut(limit, "/")
+	if !found || !ok {
+		return Rule{}, fmt.Errorf("%w: %q", ErrInvalidRule, s)
+	}
+
+	rule := Rule{Method: AnyMethod, Path: strings.TrimSpace(route)}
+	if method, path, found := strings.Cut(rule.Path, " "); found {
+		rule.Method, rule.Path = strings.ToUpper(method), strings.TrimSpace(path)
+	}
+
+	var err error
+	if rule.Limit.Requests, err = strconv.Atoi(strings.TrimSpace(requests)); err != nil {
+		return Rule{}, fmt.Errorf("%w: %q", ErrInvalidRule, s)
+	}
+	if rule.Limit.Window, err = time.ParseDuration(strings.TrimSpace(window)); err != nil {
+		return Rule{}, fmt.Errorf("%w: %q", ErrInvalidRule, s)
+	}
+	if !strings.HasPrefix(rule.Path, "/") || rule.Limit.Requests < 0 || rule.Limit.Window <= 0 {
+		return Rule{}, fmt.Errorf("%w: %q", ErrInvalidRule, s)
+	}
+
+	return rule, nil
+}
+
+// Rules are the limits of the API: the ones of the routes with a rule, and a default
+// limit shared by all the other routes.
+type Rules struct {
+	Default Limit
+	Routes  []Rule
+}
+
+// ParseRules
+//
+// Returns the rules with the default limit and the routes limits (see ParseRule).
+func ParseRules(defaultLimit Limit, routes []string) (Rules, error) {
+	rules := Rules{Default: defaultLimit}
+	for _, route := range routes {
+		if strings.TrimSpace(route) == "" {
+			continue
+		}
+
+		rule, err := ParseRule(route)
+		if err != nil {
+			return Rules{}, err
+		}
+		rules.Routes = append(rules.Routes, rule)
+	}
+
+	return rules, nil
+}
+
+// Match
+//
+// Returns the rule of a request: the first rule of its method and route, then the
+// first rule of its route for any method, then the default limit on every route.
+// Routes are matched on their pattern, not the requested path.
+func (r Rules) Match(method, path string) Rule {
+	for _, rule := range r.Routes {
+		if rule.Method == method && rule.Path == path {
+			return rule
+		}
+	}
+	for _, rule := range r.Routes {
+		if rule.Method == AnyMethod && rule.Path == path {
+			return rule
+		}
+	}
+
+	return Rule{Method: AnyMethod, Path: "*", Limit: r.Default}
+}
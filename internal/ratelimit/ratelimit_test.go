@@ -0,0 +1,96 @@
+package ratelimit
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+// TestParseRule tests the rule syntax.
+func TestParseRule(t *testing.T) {
+	tests := []struct {
+		rule string
+		want Rule
+		err  bool
+	}{
+		{"POST /api/tasks/=10/1m", Rule{Method: "POST", Path: "/api/tasks/", Limit: Limit{10, time.Minute}}, false},
+		{"get /api/tasks/:id = 300/30s", Rule{Method: "GET", Path: "/api/tasks/:id", Limit: Limit{300, 30 * time.Second}}, false},
+		{"/api/tasks/stream=0/1m", Rule{Method: AnyMethod, Path: "/api/tasks/stream", Limit: Limit{0, time.Minute}}, false},
+		{"POST /api/tasks/", Rule{}, true},
+		{"POST /api/tasks/=ten/1m", Rule{}, true},
+		{"POST /api/tasks/=10/minute", Rule{}, true},
+		{"POST api/tasks/=10/1m", Rule{}, true},
+		{"POST /api/tasks/=-1/1m", Rule{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rule, func(t *testing.T) {
+			rule, err := ParseRule(tt.rule)
+
+			if tt.err {
+				assert.ErrorIs(t, err, ErrInvalidRule)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, rule)
+		})
+	}
+}
+
+// TestRulesMatch_ShouldPreferMethodRules tests that method rules win over the rules
+// of any method, and that other routes share the default limit.
+func TestRulesMatch_ShouldPreferMethodRules(t *testing.T) {
+	rules, err := ParseRules(DefaultLimit, []string{"/api/tasks/=50/1m", "POST /api/tasks/=10/1m"})
+	require.NoError(t, err)
+
+	assert.Equal(t, 10, rules.Match("POST", "/api/tasks/").Limit.Requests)
+	assert.Equal(t, 50, rules.Match("GET", "/api/tasks/").Limit.Requests)
+	assert.Equal(t, rules.Match("GET", "/api/users/").Name(), rules.Match("DELETE", "/api/tasks/:id").Name())
+	assert.Equal(t, DefaultLimit, rules.Match("GET", "/api/users/").Limit)
+}
+
+// TestMemoryAllow_ShouldRefillOverTime tests the token bucket of the memory backend:
+// bursts up to the limit, then one request per refilled token.
+func TestMemoryAllow_ShouldRefillOverTime(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	limiter := NewMemory()
+	limiter.now = func() time.Time { return now }
+	limit := Limit{Requests: 3, Window: 3 * time.Second}
+
+	for i := 2; i >= 0; i-- {
+		res, err := limiter.Allow(context.Background(), "client", limit)
+		require.NoError(t, err)
+		assert.True(t, res.Allowed)
+		assert.Equal(t, i, res.Remaining)
+	}
+
+	res, _ := limiter.Allow(context.Background(), "client", limit)
+	assert.False(t, res.Allowed)
+	assert.Equal(t, time.Second, res.RetryAfter)
+	assert.Equal(t, 3*time.Second, res.Reset)
+
+	// Other clients have their own bucket
+	res, _ = limiter.Allow(context.Background(), "other", limit)
+	assert.True(t, res.Allowed)
+
+	now = now.Add(time.Second)
+	res, _ = limiter.Allow(context.Background(), "client", limit)
+	assert.True(t, res.Allowed)
+	assert.Equal(t, 0, res.Remaining)
+}
+
+// TestMemorySweep_ShouldDropFullBuckets tests that idle buckets do not accumulate.
+func TestMemorySweep_ShouldDropFullBuckets(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	limiter := NewMemory()
+	limiter.now = func() time.Time { return now }
+
+	_, _ = limiter.Allow(context.Background(), "idle", Limit{Requests: 1, Window: time.Second})
+	now = now.Add(sweepInterval)
+	_, _ = limiter.Allow(context.Background(), "active", Limit{Requests: 1, Window: time.Second})
+
+	assert.Len(t, limiter.buckets, 1)
+	assert.Contains(t, limiter.buckets, "active")
+}
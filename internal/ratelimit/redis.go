@@ -0,0 +1,78 @@
+package ratelimit
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/cockroachdb/errors"
+	"github.com/go-redis/redis/v8"
+)
+
+// DefaultRedisPrefix prefixes the keys of the buckets in Redis.
+const DefaultRedisPrefix = "ratelimit:"
+
+// takeToken refills and takes a token from the bucket KEYS[1] of ARGV[1] tokens
+// refilled per ARGV[2] milliseconds, atomically. The clock of Redis is used, so the
+// instances of the API don't need synchronized clocks. Returns whether a token was
+// taken and the tokens left, as a string to keep the fraction.
+var takeToken = redis.NewScript(`
+redis.replicate_commands()
+
+local capacity = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local time = redis.call('TIME')
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'updated')
+local tokens = tonumber(state[1]) or capacity
+local updated = tonumber(state[2]) or now
+
+tokens = math.min(capacity, tokens + math.max(now - updated, 0) * capacity / window)
+local allowed = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'updated', now)
+redis.call('PEXPIRE', KEYS[1], window)
+
+return {allowed, tostring(tokens)}
+`)
+
+// Redis is the Limiter keeping the buckets in Redis, shared by all the instances of
+// the API. Buckets expire once they had the time to fill up again.
+type Redis struct {
+	client redis.UniversalClient
+	prefix string
+}
+
+// NewRedis
+//
+// Returns a Limiter storing the buckets in Redis, under keys starting with prefix
+// (DefaultRedisPrefix if empty).
+func NewRedis(client redis.UniversalClient, prefix string) *Redis {
+	if prefix == "" {
+		prefix = DefaultRedisPrefix
+	}
+
+	return &Redis{client: client, prefix: prefix}
+}
+
+// Allow implements Limiter.
+func (r *Redis) Allow(ctx context.Context, key string, limit Limit) (Result, error) {
+	values, err := takeToken.Run(ctx, r.client, []string{r.prefix + key},
+		limit.Requests, limit.Window.Milliseconds()).Slice()
+	if err != nil {
+		return Result{}, errors.Wrap(err, "failed to take a rate limit token")
+	}
+
+	allowed, _ := values[0].(int64)
+	raw, _ := values[1].(string)
+	tokens, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return Result{}, errors.Wrap(err, "failed to parse the rate limit tokens")
+	}
+
+	return result(allowed == 1, limit, tokens), nil
+}
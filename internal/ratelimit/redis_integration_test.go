@@ -0,0 +1,47 @@
+package ratelimit_test
+
+import (
+	"context"
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"task-manager/internal/ratelimit"
+	"testing"
+	"time"
+)
+
+// TestRedisAllowIntegration tests that the instances sharing Redis share the buckets.
+func TestRedisAllowIntegration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	client := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+	t.Cleanup(func() { _ = client.Close() })
+	require.NoError(t, client.Ping(context.Background()).Err())
+
+	first := ratelimit.NewRedis(client, "ratelimit-test:")
+	second := ratelimit.NewRedis(client, "ratelimit-test:")
+	key := uuid.NewString()
+	limit := ratelimit.Limit{Requests: 2, Window: time.Minute}
+
+	res, err := first.Allow(context.Background(), key, limit)
+	require.NoError(t, err)
+	assert.True(t, res.Allowed)
+	assert.Equal(t, 1, res.Remaining)
+
+	res, err = second.Allow(context.Background(), key, limit)
+	require.NoError(t, err)
+	assert.True(t, res.Allowed)
+	assert.Equal(t, 0, res.Remaining)
+
+	res, err = first.Allow(context.Background(), key, limit)
+	require.NoError(t, err)
+	assert.False(t, res.Allowed)
+	assert.InDelta(t, 30*time.Second, res.RetryAfter, float64(time.Second))
+
+	ttl, err := client.PTTL(context.Background(), "ratelimit-test:"+key).Result()
+	require.NoError(t, err)
+	assert.LessOrEqual(t, ttl, time.Minute)
+}
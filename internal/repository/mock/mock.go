@@ -3,7 +3,9 @@ package mock
 import (
 	"context"
 	"task-manager/internal/entities"
+	"task-manager/internal/repository/postgres"
 	"task-manager/pkg/rest"
+	"time"
 
 	"github.com/stretchr/testify/mock"
 )
@@ -64,6 +66,20 @@ func (m *MockTaskRepository) GetByID(ctx context.Context, id int64) (*entities.T
 	return t, args.Error(1)
 }
 
+// GetForUpdate mocks TaskRepository.GetForUpdate
+//
+//	mockRepo.On("GetForUpdate", mock.Anything, int64(10)).Return(task, nil)
+func (m *MockTaskRepository) GetForUpdate(ctx context.Context, id int64) (*entities.Task, error) {
+	args := m.Called(ctx, id)
+
+	var t *entities.Task
+	if args.Get(0) != nil {
+		t = args.Get(0).(*entities.Task)
+	}
+
+	return t, args.Error(1)
+}
+
 // Delete mocks TaskRepository.Delete
 //
 // It simulates removing a task by ID.
@@ -96,3 +112,403 @@ func (m *MockTaskRepository) List(ctx context.Context, query rest.Query) ([]enti
 
 	return tasks, args.Int(1), args.Error(2)
 }
+
+// AddAssignee mocks TaskRepository.AddAssignee
+func (m *MockTaskRepository) AddAssignee(ctx context.Context, taskID, userID int64) error {
+	args := m.Called(ctx, taskID, userID)
+	return args.Error(0)
+}
+
+// RemoveAssignee mocks TaskRepository.RemoveAssignee
+func (m *MockTaskRepository) RemoveAssignee(ctx context.Context, taskID, userID int64) error {
+	args := m.Called(ctx, taskID, userID)
+	return args.Error(0)
+}
+
+// AddWatcher mocks TaskRepository.AddWatcher
+func (m *MockTaskRepository) AddWatcher(ctx context.Context, taskID, userID int64) error {
+	args := m.Called(ctx, taskID, userID)
+	return args.Error(0)
+}
+
+// RemoveWatcher mocks TaskRepository.RemoveWatcher
+func (m *MockTaskRepository) RemoveWatcher(ctx context.Context, taskID, userID int64) error {
+	args := m.Called(ctx, taskID, userID)
+	return args.Error(0)
+}
+
+// MaterializeDueOccurrences mocks TaskRepository.MaterializeDueOccurrences
+func (m *MockTaskRepository) MaterializeDueOccurrences(ctx context.Context, now time.Time, limit int, next postgres.NextOccurrenceFunc) ([]entities.Task, error) {
+	args := m.Called(ctx, now, limit, next)
+
+	var tasks []entities.Task
+	if args.Get(0) != nil {
+		tasks = args.Get(0).([]entities.Task)
+	}
+
+	return tasks, args.Error(1)
+}
+
+// MaterializeNextOccurrence mocks TaskRepository.MaterializeNextOccurrence
+func (m *MockTaskRepository) MaterializeNextOccurrence(ctx context.Context, taskID int64, next postgres.NextOccurrenceFunc) (*entities.Task, error) {
+	args := m.Called(ctx, taskID, next)
+
+	var t *entities.Task
+	if args.Get(0) != nil {
+		t = args.Get(0).(*entities.Task)
+	}
+
+	return t, args.Error(1)
+}
+
+// MockUserRepository
+//
+// A testify-based mock implementation of the UserRepository interface.
+// It lets service tests control assignee lookups without a real database.
+type MockUserRepository struct {
+	mock.Mock
+}
+
+// Create mocks UserRepository.Create
+func (m *MockUserRepository) Create(ctx context.Context, user *entities.User) (*entities.User, error) {
+	args := m.Called(ctx, user)
+
+	var u *entities.User
+	if args.Get(0) != nil {
+		u = args.Get(0).(*entities.User)
+	}
+
+	return u, args.Error(1)
+}
+
+// Update mocks UserRepository.Update
+func (m *MockUserRepository) Update(ctx context.Context, user *entities.User) (*entities.User, error) {
+	args := m.Called(ctx, user)
+
+	var u *entities.User
+	if args.Get(0) != nil {
+		u = args.Get(0).(*entities.User)
+	}
+
+	return u, args.Error(1)
+}
+
+// GetByID mocks UserRepository.GetByID
+//
+// Example mock setup:
+//
+//	mockRepo.On("GetByID", mock.Anything, int64(10)).Return(user, nil)
+func (m *MockUserRepository) GetByID(ctx context.Context, id int64) (*entities.User, error) {
+	args := m.Called(ctx, id)
+
+	var u *entities.User
+	if args.Get(0) != nil {
+		u = args.Get(0).(*entities.User)
+	}
+
+	return u, args.Error(1)
+}
+
+// GetByIDs mocks UserRepository.GetByIDs
+func (m *MockUserRepository) GetByIDs(ctx context.Context, ids []int64) ([]entities.User, error) {
+	args := m.Called(ctx, ids)
+	return args.Get(0).([]entities.User), args.Error(1)
+}
+
+// Delete mocks UserRepository.Delete
+func (m *MockUserRepository) Delete(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+// List mocks UserRepository.List
+func (m *MockUserRepository) List(ctx context.Context, query rest.Query) ([]entities.User, int, error) {
+	args := m.Called(ctx, query)
+
+	var users []entities.User
+	if args.Get(0) != nil {
+		users = args.Get(0).([]entities.User)
+	}
+
+	return users, args.Int(1), args.Error(2)
+}
+
+// MockReminderRepository
+//
+// A testify-based mock implementation of the ReminderRepository interface.
+type MockReminderRepository struct {
+	mock.Mock
+}
+
+// ListUnsent mocks ReminderRepository.ListUnsent
+func (m *MockReminderRepository) ListUnsent(ctx context.Context, kind entities.ReminderKind, dueAfter, dueBefore time.Time, limit int) ([]entities.Task, error) {
+	args := m.Called(ctx, kind, dueAfter, dueBefore, limit)
+
+	var tasks []entities.Task
+	if args.Get(0) != nil {
+		tasks = args.Get(0).([]entities.Task)
+	}
+
+	return tasks, args.Error(1)
+}
+
+// Claim mocks ReminderRepository.Claim
+func (m *MockReminderRepository) Claim(ctx context.Context, reminder *entities.Reminder) (bool, error) {
+	args := m.Called(ctx, reminder)
+	return args.Bool(0), args.Error(1)
+}
+
+// Release mocks ReminderRepository.Release
+func (m *MockReminderRepository) Release(ctx context.Context, reminder *entities.Reminder) error {
+	args := m.Called(ctx, reminder)
+	return args.Error(0)
+}
+
+// MockWebhookRepository
+//
+// A testify-based mock implementation of the WebhookRepository interface.
+type MockWebhookRepository struct {
+	mock.Mock
+}
+
+// CreateSubscription mocks WebhookRepository.CreateSubscription
+func (m *MockWebhookRepository) CreateSubscription(ctx context.Context, subscription *entities.WebhookSubscription) (*entities.WebhookSubscription, error) {
+	args := m.Called(ctx, subscription)
+
+	var s *entities.WebhookSubscription
+	if args.Get(0) != nil {
+		s = args.Get(0).(*entities.WebhookSubscription)
+	}
+
+	return s, args.Error(1)
+}
+
+// UpdateSubscription mocks WebhookRepository.UpdateSubscription
+func (m *MockWebhookRepository) UpdateSubscription(ctx context.Context, subscription *entities.WebhookSubscription) (*entities.WebhookSubscription, error) {
+	args := m.Called(ctx, subscription)
+
+	var s *entities.WebhookSubscription
+	if args.Get(0) != nil {
+		s = args.Get(0).(*entities.WebhookSubscription)
+	}
+
+	return s, args.Error(1)
+}
+
+// GetSubscription mocks WebhookRepository.GetSubscription
+func (m *MockWebhookRepository) GetSubscription(ctx context.Context, id int64) (*entities.WebhookSubscription, error) {
+	args := m.Called(ctx, id)
+
+	var s *entities.WebhookSubscription
+	if args.Get(0) != nil {
+		s = args.Get(0).(*entities.WebhookSubscription)
+	}
+
+	return s, args.Error(1)
+}
+
+// ListSubscriptions mocks WebhookRepository.ListSubscriptions
+func (m *MockWebhookRepository) ListSubscriptions(ctx context.Context, query rest.Query) ([]entities.WebhookSubscription, int, error) {
+	args := m.Called(ctx, query)
+
+	var subscriptions []entities.WebhookSubscription
+	if args.Get(0) != nil {
+		subscriptions = args.Get(0).([]entities.WebhookSubscription)
+	}
+
+	return subscriptions, args.Int(1), args.Error(2)
+}
+
+// DeleteSubscription mocks WebhookRepository.DeleteSubscription
+func (m *MockWebhookRepository) DeleteSubscription(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+// Enqueue mocks WebhookRepository.Enqueue
+func (m *MockWebhookRepository) Enqueue(ctx context.Context, eventID string, eventType entities.TaskEventType, tenantID string, payload []byte) (int, error) {
+	args := m.Called(ctx, eventID, eventType, tenantID, payload)
+	return args.Int(0), args.Error(1)
+}
+
+// ClaimDue mocks WebhookRepository.ClaimDue
+func (m *MockWebhookRepository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]entities.WebhookDelivery, error) {
+	args := m.Called(ctx, now, lease, limit)
+
+	var deliveries []entities.WebhookDelivery
+	if args.Get(0) != nil {
+		deliveries = args.Get(0).([]entities.WebhookDelivery)
+	}
+
+	return deliveries, args.Error(1)
+}
+
+// SaveAttempt mocks WebhookRepository.SaveAttempt
+func (m *MockWebhookRepository) SaveAttempt(ctx context.Context, delivery *entities.WebhookDelivery) error {
+	args := m.Called(ctx, delivery)
+	return args.Error(0)
+}
+
+// ListDeliveries mocks WebhookRepository.ListDeliveries
+func (m *MockWebhookRepository) ListDeliveries(ctx context.Context, subscriptionID int64, query rest.Query) ([]entities.WebhookDelivery, int, error) {
+	args := m.Called(ctx, subscriptionID, query)
+
+	var deliveries []entities.WebhookDelivery
+	if args.Get(0) != nil {
+		deliveries = args.Get(0).([]entities.WebhookDelivery)
+	}
+
+	return deliveries, args.Int(1), args.Error(2)
+}
+
+// Requeue mocks WebhookRepository.Requeue
+func (m *MockWebhookRepository) Requeue(ctx context.Context, subscriptionID, deliveryID int64, now time.Time) (*entities.WebhookDelivery, error) {
+	args := m.Called(ctx, subscriptionID, deliveryID, now)
+
+	var d *entities.WebhookDelivery
+	if args.Get(0) != nil {
+		d = args.Get(0).(*entities.WebhookDelivery)
+	}
+
+	return d, args.Error(1)
+}
+
+// MockOutboxRepository
+//
+// A testify-based mock implementation of the OutboxRepository interface.
+type MockOutboxRepository struct {
+	mock.Mock
+}
+
+// Append mocks OutboxRepository.Append
+func (m *MockOutboxRepository) Append(ctx context.Context, events ...entities.TaskEvent) error {
+	args := m.Called(ctx, events)
+	return args.Error(0)
+}
+
+// ClaimDue mocks OutboxRepository.ClaimDue
+func (m *MockOutboxRepository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]entities.OutboxEvent, error) {
+	args := m.Called(ctx, now, lease, limit)
+
+	var events []entities.OutboxEvent
+	if args.Get(0) != nil {
+		events = args.Get(0).([]entities.OutboxEvent)
+	}
+
+	return events, args.Error(1)
+}
+
+// MarkPublished mocks OutboxRepository.MarkPublished
+func (m *MockOutboxRepository) MarkPublished(ctx context.Context, sequence int64, at time.Time) error {
+	args := m.Called(ctx, sequence, at)
+	return args.Error(0)
+}
+
+// SaveFailure mocks OutboxRepository.SaveFailure
+func (m *MockOutboxRepository) SaveFailure(ctx context.Context, event *entities.OutboxEvent) error {
+	args := m.Called(ctx, event)
+	return args.Error(0)
+}
+
+// Purge mocks OutboxRepository.Purge
+func (m *MockOutboxRepository) Purge(ctx context.Context, before time.Time, limit int) (int, error) {
+	args := m.Called(ctx, before, limit)
+	return args.Int(0), args.Error(1)
+}
+
+// LatestSequence mocks OutboxRepository.LatestSequence
+func (m *MockOutboxRepository) LatestSequence(ctx context.Context) (int64, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// ListAfter mocks OutboxRepository.ListAfter
+func (m *MockOutboxRepository) ListAfter(ctx context.Context, sequence int64, limit int) ([]entities.OutboxEvent, error) {
+	args := m.Called(ctx, sequence, limit)
+
+	var events []entities.OutboxEvent
+	if args.Get(0) != nil {
+		events = args.Get(0).([]entities.OutboxEvent)
+	}
+
+	return events, args.Error(1)
+}
+
+// MockAPIKeyRepository
+//
+// A testify-based mock implementation of the APIKeyRepository interface.
+type MockAPIKeyRepository struct {
+	mock.Mock
+}
+
+// apiKey returns the API key held by the first return value, if any.
+func apiKey(args mock.Arguments) *entities.APIKey {
+	if args.Get(0) == nil {
+		return nil
+	}
+	return args.Get(0).(*entities.APIKey)
+}
+
+// Create mocks APIKeyRepository.Create
+func (m *MockAPIKeyRepository) Create(ctx context.Context, key *entities.APIKey) (*entities.APIKey, error) {
+	args := m.Called(ctx, key)
+	return apiKey(args), args.Error(1)
+}
+
+// GetByID mocks APIKeyRepository.GetByID
+func (m *MockAPIKeyRepository) GetByID(ctx context.Context, id int64) (*entities.APIKey, error) {
+	args := m.Called(ctx, id)
+	return apiKey(args), args.Error(1)
+}
+
+// GetByPrefix mocks APIKeyRepository.GetByPrefix
+func (m *MockAPIKeyRepository) GetByPrefix(ctx context.Context, prefix string) (*entities.APIKey, error) {
+	args := m.Called(ctx, prefix)
+	return apiKey(args), args.Error(1)
+}
+
+// List mocks APIKeyRepository.List
+func (m *MockAPIKeyRepository) List(ctx context.Context, query rest.Query) ([]entities.APIKey, int, error) {
+	args := m.Called(ctx, query)
+
+	var keys []entities.APIKey
+	if args.Get(0) != nil {
+		keys = args.Get(0).([]entities.APIKey)
+	}
+
+	return keys, args.Int(1), args.Error(2)
+}
+
+// Revoke mocks APIKeyRepository.Revoke
+func (m *MockAPIKeyRepository) Revoke(ctx context.Context, id int64, at time.Time) (*entities.APIKey, error) {
+	args := m.Called(ctx, id, at)
+	return apiKey(args), args.Error(1)
+}
+
+// Rotate mocks APIKeyRepository.Rotate
+func (m *MockAPIKeyRepository) Rotate(ctx context.Context, id int64, prefix, secretHash string) (*entities.APIKey, error) {
+	args := m.Called(ctx, id, prefix, secretHash)
+	return apiKey(args), args.Error(1)
+}
+
+// TouchLastUsed mocks APIKeyRepository.TouchLastUsed
+func (m *MockAPIKeyRepository) TouchLastUsed(ctx context.Context, id int64, at time.Time) error {
+	args := m.Called(ctx, id, at)
+	return args.Error(0)
+}
+
+// MockTransactor
+//
+// Runs the function directly, without a transaction. Set Err to simulate a
+// transaction that fails to commit.
+type MockTransactor struct {
+	Err error
+}
+
+// WithinTx implements postgres.Transactor
+func (m *MockTransactor) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if err := fn(ctx); err != nil {
+		return err
+	}
+	return m.Err
+}
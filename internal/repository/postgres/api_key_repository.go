@@ -0,0 +1,177 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/lib/pq"
+	"task-manager/internal/entities"
+	"task-manager/internal/tenant"
+	"task-manager/pkg/db"
+	"task-manager/pkg/rest"
+)
+
+// -----------------------------------------------------------------------------
+// Errors
+// -----------------------------------------------------------------------------
+
+// ErrAPIKeyNotFound is returned when an API key with the given ID or prefix does not exist.
+var ErrAPIKeyNotFound = fmt.Errorf("api key not found")
+
+// apiKeyColumns lists the columns mapped onto apiKeyRow.
+const apiKeyColumns = `id, tenant_id, name, prefix, secret_hash, scopes, expires_at, last_used_at, revoked_at, created_at, updated_at`
+
+// -----------------------------------------------------------------------------
+// Interfaces
+// -----------------------------------------------------------------------------
+
+// APIKeyRepository defines the operations on the API keys. The keys are managed
+// within the tenant of the context; GetByPrefix and TouchLastUsed, used to
+// authenticate a key before its tenant is known, serve every tenant.
+type APIKeyRepository interface {
+	Create(ctx context.Context, key *entities.APIKey) (*entities.APIKey, error)
+	GetByID(ctx context.Context, id int64) (*entities.APIKey, error)
+	GetByPrefix(ctx context.Context, prefix string) (*entities.APIKey, error)
+	List(ctx context.Context, query rest.Query) ([]entities.APIKey, int, error)
+	Revoke(ctx context.Context, id int64, at time.Time) (*entities.APIKey, error)
+	Rotate(ctx context.Context, id int64, prefix, secretHash string) (*entities.APIKey, error)
+	TouchLastUsed(ctx context.Context, id int64, at time.Time) error
+}
+
+// -----------------------------------------------------------------------------
+// Repository implementation
+// -----------------------------------------------------------------------------
+
+// APIKey implements APIKeyRepository using a SQL database.
+type APIKey struct {
+	db db.DB
+}
+
+// NewAPIKeyRepository returns a new APIKey repository.
+func NewAPIKeyRepository(db db.DB) *APIKey {
+	return &APIKey{db: db}
+}
+
+// apiKeyRow maps the scopes array of an API key row.
+type apiKeyRow struct {
+	entities.APIKey
+	ScopeNames pq.StringArray `db:"scopes"`
+}
+
+// entity converts the row to an API key entity.
+func (r *apiKeyRow) entity() *entities.APIKey {
+	k := r.APIKey
+	k.Scopes = append([]string{}, r.ScopeNames...)
+	return &k
+}
+
+// Create inserts a new API key of the tenant of ctx and returns it with generated fields.
+func (r *APIKey) Create(ctx context.Context, k *entities.APIKey) (*entities.APIKey, error) {
+	query := `
+        INSERT INTO api_keys (name, prefix, secret_hash, scopes, expires_at, tenant_id)
+        VALUES ($1, $2, $3, $4, $5, $6)
+        RETURNING ` + apiKeyColumns
+
+	var row apiKeyRow
+	if err := r.db.GetContext(ctx, &row, query, k.Name, k.Prefix, k.SecretHash, pq.StringArray(k.Scopes), k.ExpiresAt, tenant.Of(ctx)); err != nil {
+		return nil, err
+	}
+
+	return row.entity(), nil
+}
+
+// GetByID fetches an API key by its ID.
+// Returns ErrAPIKeyNotFound if no rows are returned.
+func (r *APIKey) GetByID(ctx context.Context, id int64) (*entities.APIKey, error) {
+	return r.get(ctx, `SELECT `+apiKeyColumns+` FROM api_keys WHERE id = $1 AND tenant_id = $2`, id, tenant.Of(ctx))
+}
+
+// GetByPrefix fetches an API key by the public prefix of the key.
+// Returns ErrAPIKeyNotFound if no rows are returned.
+func (r *APIKey) GetByPrefix(ctx context.Context, prefix string) (*entities.APIKey, error) {
+	return r.get(ctx, `SELECT `+apiKeyColumns+` FROM api_keys WHERE prefix = $1`, prefix)
+}
+
+// List returns a page of API keys along with the total count, revoked ones included.
+// Supports filtering by revoked (true or false).
+func (r *APIKey) List(ctx context.Context, query rest.Query) ([]entities.APIKey, int, error) {
+	where := " WHERE tenant_id = $1"
+	if value, ok := query.Filter["revoked"]; ok {
+		switch value {
+		case "true":
+			where += " AND revoked_at IS NOT NULL"
+		case "false":
+			where += " AND revoked_at IS NULL"
+		}
+	}
+
+	var total int
+	if err := r.db.GetContext(ctx, &total, `SELECT COUNT(*) FROM api_keys`+where, tenant.Of(ctx)); err != nil {
+		return nil, 0, err
+	}
+
+	offset := (query.Page - 1) * query.PerPage
+	listQuery := `SELECT ` + apiKeyColumns + ` FROM api_keys` + where + ` ORDER BY id LIMIT $2 OFFSET $3`
+
+	var rows []apiKeyRow
+	if err := r.db.SelectContext(ctx, &rows, listQuery, tenant.Of(ctx), query.PerPage, offset); err != nil {
+		return nil, 0, err
+	}
+
+	keys := make([]entities.APIKey, 0, len(rows))
+	for i := range rows {
+		keys = append(keys, *rows[i].entity())
+	}
+
+	return keys, total, nil
+}
+
+// Revoke marks an API key as revoked at the given time. Revoking a key twice keeps the
+// first revocation time. Returns ErrAPIKeyNotFound if it does not exist.
+func (r *APIKey) Revoke(ctx context.Context, id int64, at time.Time) (*entities.APIKey, error) {
+	query := `
+        UPDATE api_keys
+        SET revoked_at = COALESCE(revoked_at, $2),
+            updated_at = now()
+        WHERE id = $1 AND tenant_id = $3
+        RETURNING ` + apiKeyColumns
+
+	return r.get(ctx, query, id, at, tenant.Of(ctx))
+}
+
+// Rotate replaces the prefix and secret hash of an API key, keeping its name, scopes
+// and expiry. The previous key stops working right away.
+// Returns ErrAPIKeyNotFound if it does not exist.
+func (r *APIKey) Rotate(ctx context.Context, id int64, prefix, secretHash string) (*entities.APIKey, error) {
+	query := `
+        UPDATE api_keys
+        SET prefix = $2,
+            secret_hash = $3,
+            updated_at = now()
+        WHERE id = $1 AND tenant_id = $4
+        RETURNING ` + apiKeyColumns
+
+	return r.get(ctx, query, id, prefix, secretHash, tenant.Of(ctx))
+}
+
+// TouchLastUsed records the last successful authentication of an API key.
+func (r *APIKey) TouchLastUsed(ctx context.Context, id int64, at time.Time) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE api_keys SET last_used_at = $2 WHERE id = $1`, id, at)
+	return err
+}
+
+// get runs a query returning a single API key row.
+func (r *APIKey) get(ctx context.Context, query string, args ...interface{}) (*entities.APIKey, error) {
+	var row apiKeyRow
+	if err := r.db.GetContext(ctx, &row, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrAPIKeyNotFound
+		}
+		return nil, err
+	}
+
+	return row.entity(), nil
+}
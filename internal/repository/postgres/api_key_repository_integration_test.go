@@ -0,0 +1,81 @@
+package postgres_test
+
+import (
+	"context"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"task-manager/internal/entities"
+	"task-manager/internal/repository"
+	"task-manager/internal/repository/postgres"
+	"task-manager/internal/utils"
+	"task-manager/pkg/rest"
+	"testing"
+	"time"
+)
+
+// TestAPIKeyIntegration tests that API keys are found by prefix, rotated and revoked.
+func TestAPIKeyIntegration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	apiKeyRepository := repository.MakeNewAPIKeyRepository()
+
+	expiresAt := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Second)
+	created, err := apiKeyRepository.Create(ctx, &entities.APIKey{
+		Name: "backup", Prefix: "0a1b2c3d4e5f", SecretHash: "hash",
+		Scopes: []string{"tasks:read"}, ExpiresAt: &expiresAt,
+	})
+	require.NoError(t, err)
+	assert.NotZero(t, created.ID)
+	assert.Equal(t, []string{"tasks:read"}, created.Scopes)
+	assert.True(t, created.ExpiresAt.Equal(expiresAt))
+
+	found, err := apiKeyRepository.GetByPrefix(ctx, "0a1b2c3d4e5f")
+	require.NoError(t, err)
+	assert.Equal(t, created.ID, found.ID)
+
+	usedAt := time.Now().UTC().Truncate(time.Second)
+	require.NoError(t, apiKeyRepository.TouchLastUsed(ctx, created.ID, usedAt))
+
+	rotated, err := apiKeyRepository.Rotate(ctx, created.ID, "f5e4d3c2b1a0", "new-hash")
+	require.NoError(t, err)
+	assert.Equal(t, "new-hash", rotated.SecretHash)
+	assert.Equal(t, "backup", rotated.Name)
+	require.NotNil(t, rotated.LastUsedAt)
+	assert.True(t, rotated.LastUsedAt.Equal(usedAt))
+
+	// The previous prefix is gone with the rotation
+	_, err = apiKeyRepository.GetByPrefix(ctx, "0a1b2c3d4e5f")
+	assert.ErrorIs(t, err, postgres.ErrAPIKeyNotFound)
+
+	revokedAt := time.Now().UTC().Truncate(time.Second)
+	revoked, err := apiKeyRepository.Revoke(ctx, created.ID, revokedAt)
+	require.NoError(t, err)
+	require.NotNil(t, revoked.RevokedAt)
+	assert.True(t, revoked.RevokedAt.Equal(revokedAt))
+
+	// Revoking again keeps the first revocation time
+	again, err := apiKeyRepository.Revoke(ctx, created.ID, revokedAt.Add(time.Hour))
+	require.NoError(t, err)
+	assert.True(t, again.RevokedAt.Equal(revokedAt))
+
+	keys, total, err := apiKeyRepository.List(ctx, rest.Query{
+		Filter:         rest.Filter{"revoked": "true"},
+		PaginationMeta: rest.PaginationMeta{Page: 1, PerPage: 10},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	require.Len(t, keys, 1)
+	assert.Equal(t, created.ID, keys[0].ID)
+
+	_, err = apiKeyRepository.GetByID(ctx, created.ID+1000)
+	assert.ErrorIs(t, err, postgres.ErrAPIKeyNotFound)
+
+	t.Cleanup(func() {
+		fmt.Println("🧹 Cleaning up after test...")
+		utils.TruncateTables(t)
+	})
+}
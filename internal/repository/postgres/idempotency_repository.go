@@ -0,0 +1,168 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"task-manager/internal/entities"
+	"task-manager/pkg/db"
+)
+
+// -----------------------------------------------------------------------------
+// Errors
+// -----------------------------------------------------------------------------
+
+// ErrIdempotencyKeyContended is returned when a key could not be reserved nor read,
+// because it was released and reserved again concurrently.
+var ErrIdempotencyKeyContended = fmt.Errorf("idempotency key is contended")
+
+// idempotencyKeyColumns lists the columns mapped onto idempotencyKeyRow.
+const idempotencyKeyColumns = `key, fingerprint, status_code, headers, body, expires_at, created_at`
+
+// reserveAttempts is how many times Reserve tries to reserve or read a key.
+const reserveAttempts = 3
+
+// -----------------------------------------------------------------------------
+// Interfaces
+// -----------------------------------------------------------------------------
+
+// IdempotencyRepository defines the operations on the idempotency keys, see
+// idempotency.Store and idempotency.Purger. Expired keys are reserved again.
+type IdempotencyRepository interface {
+	Reserve(ctx context.Context, key, fingerprint string, ttl time.Duration) (*entities.IdempotencyKey, error)
+	Complete(ctx context.Context, key string, response entities.StoredResponse) error
+	Release(ctx context.Context, key string) error
+	Purge(ctx context.Context, before time.Time, limit int) (int, error)
+}
+
+// -----------------------------------------------------------------------------
+// Repository implementation
+// -----------------------------------------------------------------------------
+
+// Idempotency implements IdempotencyRepository using a SQL database.
+type Idempotency struct {
+	db db.DB
+}
+
+// NewIdempotencyRepository returns a new Idempotency repository.
+func NewIdempotencyRepository(db db.DB) *Idempotency {
+	return &Idempotency{db: db}
+}
+
+// idempotencyKeyRow maps the nullable response columns of an idempotency key row.
+type idempotencyKeyRow struct {
+	entities.IdempotencyKey
+	StatusCode sql.NullInt64 `db:"status_code"`
+	Headers    []byte        `db:"headers"`
+	Body       []byte        `db:"body"`
+}
+
+// entity converts the row to an idempotency key entity.
+func (r *idempotencyKeyRow) entity() (*entities.IdempotencyKey, error) {
+	k := r.IdempotencyKey
+	if !r.StatusCode.Valid {
+		return &k, nil
+	}
+
+	k.Response = &entities.StoredResponse{StatusCode: int(r.StatusCode.Int64), Body: r.Body}
+	if len(r.Headers) > 0 {
+		if err := json.Unmarshal(r.Headers, &k.Response.Header); err != nil {
+			return nil, errors.Wrap(err, "failed to decode idempotency key headers")
+		}
+	}
+
+	return &k, nil
+}
+
+// Reserve inserts the key as in flight, or takes over the key if it expired.
+// Returns nil if the key was reserved, the stored key otherwise.
+func (r *Idempotency) Reserve(ctx context.Context, key, fingerprint string, ttl time.Duration) (*entities.IdempotencyKey, error) {
+	reserve := `
+        INSERT INTO idempotency_keys (key, fingerprint, expires_at)
+        VALUES ($1, $2, NOW() + $3 * INTERVAL '1 millisecond')
+        ON CONFLICT (key) DO UPDATE
+        SET fingerprint = EXCLUDED.fingerprint,
+            status_code = NULL,
+            headers = NULL,
+            body = NULL,
+            expires_at = EXCLUDED.expires_at,
+            created_at = NOW()
+        WHERE idempotency_keys.expires_at <= NOW()
+        RETURNING key
+    `
+
+	for attempt := 0; attempt < reserveAttempts; attempt++ {
+		var reserved string
+		err := r.db.GetContext(ctx, &reserved, reserve, key, fingerprint, ttl.Milliseconds())
+		if err == nil {
+			return nil, nil
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+
+		// The key is known and did not expire
+		var row idempotencyKeyRow
+		err = r.db.GetContext(ctx, &row, `SELECT `+idempotencyKeyColumns+` FROM idempotency_keys WHERE key = $1`, key)
+		if err == nil {
+			return row.entity()
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		// Released in between, try to reserve it again
+	}
+
+	return nil, ErrIdempotencyKeyContended
+}
+
+// Complete stores the response of a reserved key. The key keeps its expiry.
+func (r *Idempotency) Complete(ctx context.Context, key string, response entities.StoredResponse) error {
+	headers, err := json.Marshal(response.Header)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode idempotency key headers")
+	}
+
+	query := `
+        UPDATE idempotency_keys
+        SET status_code = $2,
+            headers = $3,
+            body = $4
+        WHERE key = $1
+    `
+
+	_, err = r.db.ExecContext(ctx, query, key, response.StatusCode, headers, response.Body)
+	return err
+}
+
+// Release deletes a key still in flight.
+func (r *Idempotency) Release(ctx context.Context, key string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE key = $1 AND status_code IS NULL`, key)
+	return err
+}
+
+// Purge deletes up to limit keys expired before the given time and returns the
+// number of deleted rows.
+func (r *Idempotency) Purge(ctx context.Context, before time.Time, limit int) (int, error) {
+	query := `
+        DELETE FROM idempotency_keys
+        WHERE key IN (
+            SELECT key FROM idempotency_keys
+            WHERE expires_at < $1
+            ORDER BY expires_at
+            LIMIT $2
+        )
+    `
+
+	result, err := r.db.ExecContext(ctx, query, before, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	rows, err := result.RowsAffected()
+	return int(rows), err
+}
@@ -0,0 +1,99 @@
+package postgres_test
+
+import (
+	"context"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"task-manager/internal/entities"
+	"task-manager/internal/repository"
+	"task-manager/internal/utils"
+	"testing"
+	"time"
+)
+
+// TestIdempotencyIntegration tests that keys are reserved once, complete with their
+// response, and can be reserved again once released or expired.
+func TestIdempotencyIntegration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	idempotencyRepository := repository.MakeNewIdempotencyRepository()
+
+	reserved, err := idempotencyRepository.Reserve(ctx, "default/principal:42/key-1", "fingerprint", time.Hour)
+	require.NoError(t, err)
+	assert.Nil(t, reserved)
+
+	inFlight, err := idempotencyRepository.Reserve(ctx, "default/principal:42/key-1", "other", time.Hour)
+	require.NoError(t, err)
+	require.NotNil(t, inFlight)
+	assert.Equal(t, "fingerprint", inFlight.Fingerprint)
+	assert.False(t, inFlight.Completed())
+
+	response := entities.StoredResponse{
+		StatusCode: 201,
+		Header:     map[string]string{"Content-Type": "application/json; charset=utf-8"},
+		Body:       []byte(`{"status":"ok"}`),
+	}
+	require.NoError(t, idempotencyRepository.Complete(ctx, "default/principal:42/key-1", response))
+
+	completed, err := idempotencyRepository.Reserve(ctx, "default/principal:42/key-1", "fingerprint", time.Hour)
+	require.NoError(t, err)
+	require.True(t, completed.Completed())
+	assert.Equal(t, response, *completed.Response)
+
+	// Completed keys are not released
+	require.NoError(t, idempotencyRepository.Release(ctx, "default/principal:42/key-1"))
+	completed, err = idempotencyRepository.Reserve(ctx, "default/principal:42/key-1", "fingerprint", time.Hour)
+	require.NoError(t, err)
+	assert.NotNil(t, completed)
+
+	// Released keys are reserved again
+	_, err = idempotencyRepository.Reserve(ctx, "default/principal:42/key-2", "fingerprint", time.Hour)
+	require.NoError(t, err)
+	require.NoError(t, idempotencyRepository.Release(ctx, "default/principal:42/key-2"))
+	reserved, err = idempotencyRepository.Reserve(ctx, "default/principal:42/key-2", "fingerprint", time.Hour)
+	require.NoError(t, err)
+	assert.Nil(t, reserved)
+
+	t.Cleanup(func() {
+		fmt.Println("🧹 Cleaning up after test...")
+		utils.TruncateTables(t)
+	})
+}
+
+// TestIdempotencyExpiryIntegration tests that expired keys are taken over and purged.
+func TestIdempotencyExpiryIntegration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	idempotencyRepository := repository.MakeNewIdempotencyRepository()
+
+	_, err := idempotencyRepository.Reserve(ctx, "default/ip:10.0.0.1/expired", "first", time.Millisecond)
+	require.NoError(t, err)
+	_, err = idempotencyRepository.Reserve(ctx, "default/ip:10.0.0.1/live", "first", time.Hour)
+	require.NoError(t, err)
+	time.Sleep(10 * time.Millisecond)
+
+	reserved, err := idempotencyRepository.Reserve(ctx, "default/ip:10.0.0.1/expired", "second", time.Millisecond)
+	require.NoError(t, err)
+	assert.Nil(t, reserved)
+	time.Sleep(10 * time.Millisecond)
+
+	purged, err := idempotencyRepository.Purge(ctx, time.Now(), 100)
+	require.NoError(t, err)
+	assert.Equal(t, 1, purged)
+
+	live, err := idempotencyRepository.Reserve(ctx, "default/ip:10.0.0.1/live", "first", time.Hour)
+	require.NoError(t, err)
+	assert.NotNil(t, live)
+
+	t.Cleanup(func() {
+		fmt.Println("🧹 Cleaning up after test...")
+		utils.TruncateTables(t)
+	})
+}
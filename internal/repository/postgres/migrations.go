@@ -0,0 +1,43 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/cockroachdb/errors"
+	"task-manager/pkg/db"
+)
+
+// MigrationChecker checks that the migrations applied by golang-migrate, recorded in
+// schema_migrations, reached the version the application was built with. It
+// implements health.Checker, as a startup check waiting for the migrations.
+type MigrationChecker struct {
+	db      db.DB
+	version uint
+}
+
+// NewMigrationChecker returns a MigrationChecker waiting for the given version.
+func NewMigrationChecker(db db.DB, version uint) *MigrationChecker {
+	return &MigrationChecker{db: db, version: version}
+}
+
+// Check fails until the schema reached the version, or while a migration is dirty.
+func (m *MigrationChecker) Check(ctx context.Context) error {
+	var row struct {
+		Version uint `db:"version"`
+		Dirty   bool `db:"dirty"`
+	}
+
+	err := m.db.GetContext(ctx, &row, `SELECT version, dirty FROM schema_migrations LIMIT 1`)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return errors.Newf("no migration applied, waiting for version %d", m.version)
+	case err != nil:
+		return errors.Wrap(err, "failed to read the migration version")
+	case row.Dirty:
+		return errors.Newf("migration %d is dirty", row.Version)
+	case row.Version < m.version:
+		return errors.Newf("schema at version %d, waiting for version %d", row.Version, m.version)
+	}
+	return nil
+}
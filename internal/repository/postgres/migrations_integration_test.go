@@ -0,0 +1,29 @@
+package postgres_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"task-manager/database"
+	"task-manager/internal/repository/postgres"
+	"task-manager/internal/utils"
+	"testing"
+)
+
+// TestMigrationCheckerIntegration tests that the migration check passes once the
+// migrations of the repository are applied, and waits for the later ones.
+func TestMigrationCheckerIntegration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	conn := utils.CreateTestDatabaseConnection()
+	version, err := database.LatestMigration(database.Migrations)
+	require.NoError(t, err)
+
+	require.NoError(t, postgres.NewMigrationChecker(conn, version).Check(ctx))
+
+	err = postgres.NewMigrationChecker(conn, version+1).Check(ctx)
+	assert.ErrorContains(t, err, "waiting for version")
+}
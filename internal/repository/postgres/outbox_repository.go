@@ -0,0 +1,255 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"task-manager/internal/entities"
+	"task-manager/pkg/db"
+)
+
+// outboxColumns lists the columns mapped onto outboxRow.
+const outboxColumns = `id, event_id, event_type, task_id, previous_status, task, occurred_at,
+            attempts, next_attempt_at, last_error, published_at`
+
+// -----------------------------------------------------------------------------
+// Interfaces
+// -----------------------------------------------------------------------------
+
+// OutboxRepository defines the operations on the transactional outbox of task events.
+type OutboxRepository interface {
+	Append(ctx context.Context, events ...entities.TaskEvent) error
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]entities.OutboxEvent, error)
+	MarkPublished(ctx context.Context, sequence int64, at time.Time) error
+	SaveFailure(ctx context.Context, event *entities.OutboxEvent) error
+	Purge(ctx context.Context, before time.Time, limit int) (int, error)
+	LatestSequence(ctx context.Context) (int64, error)
+	ListAfter(ctx context.Context, sequence int64, limit int) ([]entities.OutboxEvent, error)
+}
+
+// -----------------------------------------------------------------------------
+// Repository implementation
+// -----------------------------------------------------------------------------
+
+// Outbox implements OutboxRepository using a SQL database.
+type Outbox struct {
+	db db.DB
+}
+
+// NewOutboxRepository returns a new Outbox repository.
+func NewOutboxRepository(db db.DB) *Outbox {
+	return &Outbox{db: db}
+}
+
+// outboxRow maps an outbox row; the task snapshot is stored as JSON.
+type outboxRow struct {
+	Sequence       int64      `db:"id"`
+	EventID        string     `db:"event_id"`
+	EventType      string     `db:"event_type"`
+	TaskID         int64      `db:"task_id"`
+	PreviousStatus string     `db:"previous_status"`
+	Task           []byte     `db:"task"`
+	OccurredAt     time.Time  `db:"occurred_at"`
+	Attempts       int        `db:"attempts"`
+	NextAttemptAt  time.Time  `db:"next_attempt_at"`
+	LastError      string     `db:"last_error"`
+	PublishedAt    *time.Time `db:"published_at"`
+}
+
+// entity converts the row to an outbox event.
+func (r *outboxRow) entity() (entities.OutboxEvent, error) {
+	event := entities.OutboxEvent{
+		TaskEvent: entities.TaskEvent{
+			ID:             r.EventID,
+			Type:           entities.TaskEventType(r.EventType),
+			TaskID:         r.TaskID,
+			PreviousStatus: entities.TaskStatus(r.PreviousStatus),
+			OccurredAt:     r.OccurredAt,
+		},
+		Sequence:      r.Sequence,
+		Attempts:      r.Attempts,
+		NextAttemptAt: r.NextAttemptAt,
+		LastError:     r.LastError,
+		PublishedAt:   r.PublishedAt,
+	}
+
+	if len(r.Task) > 0 {
+		event.Task = &entities.Task{}
+		if err := json.Unmarshal(r.Task, event.Task); err != nil {
+			return event, err
+		}
+	}
+
+	return event, nil
+}
+
+// outboxEvents converts the rows to outbox events.
+func outboxEvents(rows []outboxRow) ([]entities.OutboxEvent, error) {
+	events := make([]entities.OutboxEvent, 0, len(rows))
+	for i := range rows {
+		event, err := rows[i].entity()
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+
+	return events, nil
+}
+
+// -----------------------------------------------------------------------------
+// Append
+// -----------------------------------------------------------------------------
+
+// Append stores the events in order. Called with the context of a running
+// transaction, the events are committed or rolled back together with the change
+// they describe. Appending an event ID twice is a no-op.
+func (r *Outbox) Append(ctx context.Context, events ...entities.TaskEvent) error {
+	query := `
+        INSERT INTO outbox (event_id, event_type, task_id, previous_status, task, occurred_at)
+        VALUES ($1, $2, $3, $4, $5, $6)
+        ON CONFLICT (event_id) DO NOTHING
+    `
+
+	for _, e := range events {
+		var task []byte
+		if e.Task != nil {
+			var err error
+			if task, err = json.Marshal(e.Task); err != nil {
+				return err
+			}
+		}
+
+		if _, err := r.db.ExecContext(ctx, query,
+			e.ID,
+			e.Type,
+			e.TaskID,
+			e.PreviousStatus,
+			task,
+			e.OccurredAt,
+		); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+// Tail
+// -----------------------------------------------------------------------------
+
+// LatestSequence returns the sequence of the last event written, 0 if there is none.
+func (r *Outbox) LatestSequence(ctx context.Context) (int64, error) {
+	var sequence int64
+	err := r.db.GetContext(ctx, &sequence, `SELECT COALESCE(MAX(id), 0) FROM outbox`)
+	return sequence, err
+}
+
+// ListAfter returns up to limit events following the given sequence, published or
+// not, ordered by sequence. Unlike ClaimDue it neither locks nor leases them, so
+// every instance can follow the whole outbox.
+func (r *Outbox) ListAfter(ctx context.Context, sequence int64, limit int) ([]entities.OutboxEvent, error) {
+	query := `SELECT ` + outboxColumns + ` FROM outbox WHERE id > $1 ORDER BY id LIMIT $2`
+
+	var rows []outboxRow
+	if err := r.db.SelectContext(ctx, &rows, query, sequence, limit); err != nil {
+		return nil, err
+	}
+
+	return outboxEvents(rows)
+}
+
+// -----------------------------------------------------------------------------
+// Relay
+// -----------------------------------------------------------------------------
+
+// ClaimDue leases up to limit unpublished events, ordered by sequence, for the relay.
+//
+// Events are claimed per task: only tasks whose oldest unpublished event is due are
+// picked, and their pending events are leased together. The head rows are locked
+// with FOR UPDATE SKIP LOCKED, so several relays never work on the same task and a
+// task's events are never published out of order. If the relay dies, the events
+// are claimed again once the lease expires.
+func (r *Outbox) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]entities.OutboxEvent, error) {
+	query := `
+        WITH heads AS (
+            SELECT o.task_id
+            FROM outbox o
+            WHERE o.published_at IS NULL
+              AND o.next_attempt_at <= $1
+              AND NOT EXISTS (
+                  SELECT 1 FROM outbox p
+                  WHERE p.task_id = o.task_id AND p.published_at IS NULL AND p.id < o.id
+              )
+            ORDER BY o.id
+            LIMIT $3
+            FOR UPDATE SKIP LOCKED
+        ), claimed AS (
+            UPDATE outbox
+            SET next_attempt_at = $1::timestamptz + make_interval(secs => $2)
+            WHERE id IN (
+                SELECT id FROM outbox
+                WHERE published_at IS NULL AND task_id IN (SELECT task_id FROM heads)
+                ORDER BY id
+                LIMIT $3
+            )
+            RETURNING ` + outboxColumns + `
+        )
+        SELECT ` + outboxColumns + ` FROM claimed ORDER BY id
+    `
+
+	var rows []outboxRow
+	if err := r.db.SelectContext(ctx, &rows, query, now, lease.Seconds(), limit); err != nil {
+		return nil, err
+	}
+
+	return outboxEvents(rows)
+}
+
+// MarkPublished records that every sink accepted the event.
+func (r *Outbox) MarkPublished(ctx context.Context, sequence int64, at time.Time) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE outbox SET published_at = $1, last_error = '' WHERE id = $2`,
+		at, sequence,
+	)
+	return err
+}
+
+// SaveFailure stores the attempts, next attempt and last error of a failed event.
+// Later events of the same task wait until it is published.
+func (r *Outbox) SaveFailure(ctx context.Context, e *entities.OutboxEvent) error {
+	query := `
+        UPDATE outbox
+        SET attempts = $1,
+            next_attempt_at = $2,
+            last_error = $3
+        WHERE id = $4
+    `
+
+	_, err := r.db.ExecContext(ctx, query, e.Attempts, e.NextAttemptAt, e.LastError, e.Sequence)
+	return err
+}
+
+// Purge deletes up to limit events published before the given time and returns
+// the number of deleted rows.
+func (r *Outbox) Purge(ctx context.Context, before time.Time, limit int) (int, error) {
+	query := `
+        DELETE FROM outbox
+        WHERE id IN (
+            SELECT id FROM outbox
+            WHERE published_at IS NOT NULL AND published_at < $1
+            ORDER BY published_at
+            LIMIT $2
+        )
+    `
+
+	result, err := r.db.ExecContext(ctx, query, before, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	rows, err := result.RowsAffected()
+	return int(rows), err
+}
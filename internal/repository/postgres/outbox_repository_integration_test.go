@@ -0,0 +1,116 @@
+package postgres_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"task-manager/internal/entities"
+	"task-manager/internal/repository"
+	"task-manager/internal/utils"
+	"testing"
+	"time"
+)
+
+// TestOutboxIntegration tests that events are committed and rolled back together
+// with the task change, and claimed per task in order.
+func TestOutboxIntegration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	taskRepository := repository.MakeNewTaskRepository()
+	outboxRepository := repository.MakeNewOutboxRepository()
+	transactor := repository.MakeNewTransactor()
+
+	task := repository.RandomTask()
+	task.AssigneeID = repository.CreateTestUser().ID
+
+	// A failing transaction discards both the task and its event
+	errAbort := errors.New("abort")
+	err := transactor.WithinTx(ctx, func(ctx context.Context) error {
+		created, err := taskRepository.Create(ctx, task)
+		require.NoError(t, err)
+		require.NoError(t, outboxRepository.Append(ctx, entities.TaskEvent{
+			ID: "00000000-0000-0000-0000-000000000001", Type: entities.TaskEventCreated,
+			TaskID: created.ID, Task: created, OccurredAt: time.Now(),
+		}))
+		return errAbort
+	})
+	require.ErrorIs(t, err, errAbort)
+
+	_, err = taskRepository.GetByID(ctx, task.ID)
+	assert.Error(t, err)
+
+	now := time.Now().UTC()
+	claimed, err := outboxRepository.ClaimDue(ctx, now, time.Minute, 10)
+	require.NoError(t, err)
+	assert.Empty(t, claimed)
+
+	// A committed transaction stores the events in order
+	var created *entities.Task
+	err = transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if created, err = taskRepository.Create(ctx, task); err != nil {
+			return err
+		}
+		return outboxRepository.Append(ctx,
+			entities.TaskEvent{ID: "00000000-0000-0000-0000-000000000002", Type: entities.TaskEventCreated, TaskID: created.ID, Task: created, OccurredAt: now},
+			entities.TaskEvent{ID: "00000000-0000-0000-0000-000000000003", Type: entities.TaskEventUpdated, TaskID: created.ID, Task: created, OccurredAt: now},
+		)
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []int64{created.AssigneeID}, created.AssigneeIDs)
+
+	claimed, err = outboxRepository.ClaimDue(ctx, now, time.Minute, 10)
+	require.NoError(t, err)
+	require.Len(t, claimed, 2)
+	assert.Equal(t, entities.TaskEventCreated, claimed[0].Type)
+	assert.Equal(t, entities.TaskEventUpdated, claimed[1].Type)
+	require.NotNil(t, claimed[0].Task)
+	assert.Equal(t, created.Title, claimed[0].Task.Title)
+
+	// The lease hides the claimed events from other relays
+	leased, err := outboxRepository.ClaimDue(ctx, now, time.Minute, 10)
+	require.NoError(t, err)
+	assert.Empty(t, leased)
+
+	// Tailing lists the events in order whether they are claimed or not
+	latest, err := outboxRepository.LatestSequence(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, claimed[1].Sequence, latest)
+	tail, err := outboxRepository.ListAfter(ctx, claimed[0].Sequence-1, 10)
+	require.NoError(t, err)
+	require.Len(t, tail, 2)
+	assert.Equal(t, claimed[0].Sequence, tail[0].Sequence)
+	assert.Equal(t, claimed[1].ID, tail[1].ID)
+	tail, err = outboxRepository.ListAfter(ctx, latest, 10)
+	require.NoError(t, err)
+	assert.Empty(t, tail)
+
+	// A failed head holds back the rest of the task until it is due again
+	require.NoError(t, outboxRepository.MarkPublished(ctx, claimed[0].Sequence, now))
+	claimed[1].Attempts = 1
+	claimed[1].NextAttemptAt = now.Add(time.Hour)
+	claimed[1].LastError = "sink unavailable"
+	require.NoError(t, outboxRepository.SaveFailure(ctx, &claimed[1]))
+
+	leased, err = outboxRepository.ClaimDue(ctx, now.Add(2*time.Minute), time.Minute, 10)
+	require.NoError(t, err)
+	assert.Empty(t, leased)
+
+	retried, err := outboxRepository.ClaimDue(ctx, now.Add(2*time.Hour), time.Minute, 10)
+	require.NoError(t, err)
+	require.Len(t, retried, 1)
+	assert.Equal(t, 1, retried[0].Attempts)
+
+	purged, err := outboxRepository.Purge(ctx, now.Add(time.Second), 10)
+	require.NoError(t, err)
+	assert.Equal(t, 1, purged)
+
+	t.Cleanup(func() {
+		fmt.Println("🧹 Cleaning up after test...")
+		utils.TruncateTables(t)
+	})
+}
@@ -0,0 +1,111 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"task-manager/internal/entities"
+	"task-manager/pkg/db"
+)
+
+// -----------------------------------------------------------------------------
+// Interfaces
+// -----------------------------------------------------------------------------
+
+// ReminderRepository defines the operations required to send each deadline notification once.
+type ReminderRepository interface {
+	ListUnsent(ctx context.Context, kind entities.ReminderKind, dueAfter, dueBefore time.Time, limit int) ([]entities.Task, error)
+	Claim(ctx context.Context, reminder *entities.Reminder) (bool, error)
+	Release(ctx context.Context, reminder *entities.Reminder) error
+}
+
+// -----------------------------------------------------------------------------
+// Repository implementation
+// -----------------------------------------------------------------------------
+
+// Reminder implements ReminderRepository using a SQL database.
+type Reminder struct {
+	db    db.DB
+	tasks *Task // loads assignees and watchers of the listed tasks
+}
+
+// NewReminderRepository returns a new Reminder repository.
+func NewReminderRepository(db db.DB) *Reminder {
+	return &Reminder{db: db, tasks: NewTaskRepository(db)}
+}
+
+// -----------------------------------------------------------------------------
+// ListUnsent
+// -----------------------------------------------------------------------------
+
+// ListUnsent returns open tasks due in (dueAfter, dueBefore] for which no notification
+// of the given kind was sent for their current deadline, earliest deadline first.
+// Assignees and watchers are loaded so callers can address the notification.
+func (r *Reminder) ListUnsent(ctx context.Context, kind entities.ReminderKind, dueAfter, dueBefore time.Time, limit int) ([]entities.Task, error) {
+	query := `
+        SELECT ` + taskColumns + `
+        FROM tasks
+        WHERE due_at IS NOT NULL
+          AND status IN ('pending', 'in_progress')
+          AND due_at > $2
+          AND due_at <= $3
+          AND NOT EXISTS (
+              SELECT 1 FROM task_reminders r
+              WHERE r.task_id = tasks.id AND r.kind = $1 AND r.due_at = tasks.due_at
+          )
+        ORDER BY due_at
+        LIMIT $4
+    `
+
+	var tasks []entities.Task
+	if err := r.db.SelectContext(ctx, &tasks, query, kind, dueAfter, dueBefore, limit); err != nil {
+		return nil, err
+	}
+
+	refs := make([]*entities.Task, 0, len(tasks))
+	for i := range tasks {
+		refs = append(refs, &tasks[i])
+	}
+
+	if err := r.tasks.loadMembers(ctx, refs); err != nil {
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
+// -----------------------------------------------------------------------------
+// Claim / Release
+// -----------------------------------------------------------------------------
+
+// Claim records the notification as sent. It returns false if it was already
+// recorded, e.g. by another pod, in which case it must not be sent again.
+func (r *Reminder) Claim(ctx context.Context, reminder *entities.Reminder) (bool, error) {
+	query := `
+        INSERT INTO task_reminders (task_id, kind, due_at)
+        VALUES ($1, $2, $3)
+        ON CONFLICT DO NOTHING
+        RETURNING sent_at
+    `
+
+	err := r.db.GetContext(ctx, &reminder.SentAt, query, reminder.TaskID, reminder.Kind, reminder.DueAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, nil
+}
+
+// Release forgets a claimed notification that could not be delivered, so the next
+// run retries it.
+func (r *Reminder) Release(ctx context.Context, reminder *entities.Reminder) error {
+	query := `DELETE FROM task_reminders WHERE task_id = $1 AND kind = $2 AND due_at = $3`
+
+	_, err := r.db.ExecContext(ctx, query, reminder.TaskID, reminder.Kind, reminder.DueAt)
+	return err
+}
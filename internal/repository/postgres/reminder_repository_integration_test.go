@@ -0,0 +1,69 @@
+package postgres_test
+
+import (
+	"context"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"task-manager/internal/entities"
+	"task-manager/internal/repository"
+	"task-manager/internal/utils"
+	"testing"
+	"time"
+)
+
+// TestReminderIntegration tests that a notification is listed until it is claimed,
+// can be claimed only once and is re-armed when the deadline moves.
+func TestReminderIntegration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	taskRepository := repository.MakeNewTaskRepository()
+	reminderRepository := repository.MakeNewReminderRepository()
+
+	now := time.Now().UTC().Truncate(time.Second)
+	dueAt := now.Add(-time.Hour)
+
+	task := repository.RandomTask()
+	task.AssigneeID = repository.CreateTestUser().ID
+	task.Status = entities.TaskStatusPending
+	task.DueAt = &dueAt
+
+	createdTask, err := taskRepository.Create(ctx, task)
+	require.NoError(t, err)
+
+	tasks, err := reminderRepository.ListUnsent(ctx, entities.ReminderKindOverdue, time.Time{}, now, 10)
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+	assert.Equal(t, []int64{createdTask.AssigneeID}, tasks[0].AssigneeIDs)
+
+	reminder := &entities.Reminder{TaskID: createdTask.ID, Kind: entities.ReminderKindOverdue, DueAt: dueAt}
+	claimed, err := reminderRepository.Claim(ctx, reminder)
+	require.NoError(t, err)
+	assert.True(t, claimed)
+
+	claimed, err = reminderRepository.Claim(ctx, reminder)
+	require.NoError(t, err)
+	assert.False(t, claimed)
+
+	tasks, err = reminderRepository.ListUnsent(ctx, entities.ReminderKindOverdue, time.Time{}, now, 10)
+	require.NoError(t, err)
+	assert.Empty(t, tasks)
+
+	// Moving the deadline re-arms the escalation
+	newDueAt := now.Add(-time.Minute)
+	createdTask.DueAt = &newDueAt
+	_, err = taskRepository.Update(ctx, createdTask)
+	require.NoError(t, err)
+
+	tasks, err = reminderRepository.ListUnsent(ctx, entities.ReminderKindOverdue, time.Time{}, now, 10)
+	require.NoError(t, err)
+	assert.Len(t, tasks, 1)
+
+	t.Cleanup(func() {
+		fmt.Println("🧹 Cleaning up after test...")
+		utils.TruncateTables(t)
+	})
+}
@@ -0,0 +1,188 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"github.com/lib/pq"
+	"task-manager/internal/entities"
+	"task-manager/internal/tenant"
+)
+
+// -----------------------------------------------------------------------------
+// Errors
+// -----------------------------------------------------------------------------
+
+// ErrTaskMemberNotFound is returned when the user is not an assignee/watcher of the task.
+var ErrTaskMemberNotFound = fmt.Errorf("task member not found")
+
+// -----------------------------------------------------------------------------
+// Member tables
+// -----------------------------------------------------------------------------
+
+const (
+	taskAssigneesTable = "task_assignees"
+	taskWatchersTable  = "task_watchers"
+)
+
+// memberTables maps list filters to the join table holding the matching users.
+var memberTables = map[string]string{
+	"assignee_id": taskAssigneesTable,
+	"watcher_id":  taskWatchersTable,
+}
+
+// taskMember is a single row of a task/user join table.
+type taskMember struct {
+	TaskID int64 `db:"task_id"`
+	UserID int64 `db:"user_id"`
+}
+
+// -----------------------------------------------------------------------------
+// Assignees
+// -----------------------------------------------------------------------------
+
+// AddAssignee adds a co-assignee to the task. Adding an existing assignee is a no-op.
+func (r *Task) AddAssignee(ctx context.Context, taskID, userID int64) error {
+	return r.addMember(ctx, taskAssigneesTable, taskID, userID)
+}
+
+// RemoveAssignee removes a co-assignee from the task.
+// Returns ErrTaskMemberNotFound if the user is not assigned to the task.
+func (r *Task) RemoveAssignee(ctx context.Context, taskID, userID int64) error {
+	return r.removeMember(ctx, taskAssigneesTable, taskID, userID)
+}
+
+// -----------------------------------------------------------------------------
+// Watchers
+// -----------------------------------------------------------------------------
+
+// AddWatcher subscribes a user to the task. Adding an existing watcher is a no-op.
+func (r *Task) AddWatcher(ctx context.Context, taskID, userID int64) error {
+	return r.addMember(ctx, taskWatchersTable, taskID, userID)
+}
+
+// RemoveWatcher unsubscribes a user from the task.
+// Returns ErrTaskMemberNotFound if the user is not watching the task.
+func (r *Task) RemoveWatcher(ctx context.Context, taskID, userID int64) error {
+	return r.removeMember(ctx, taskWatchersTable, taskID, userID)
+}
+
+// -----------------------------------------------------------------------------
+// Helpers
+// -----------------------------------------------------------------------------
+
+// addMember inserts a task/user pair into the given join table.
+// Returns ErrTaskNotFound if the task does not exist in the tenant of ctx, and
+// translates foreign key violations into ErrUserNotFound.
+func (r *Task) addMember(ctx context.Context, table string, taskID, userID int64) error {
+	query := fmt.Sprintf(`
+        WITH task AS (
+            SELECT id FROM tasks WHERE id = $1 AND tenant_id = $3
+        ), inserted AS (
+            INSERT INTO %s (task_id, user_id)
+            SELECT id, $2::int FROM task
+            ON CONFLICT DO NOTHING
+        )
+        SELECT COUNT(*) FROM task
+    `, table)
+
+	var found int
+	err := r.db.GetContext(ctx, &found, query, taskID, userID, tenant.Of(ctx))
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && string(pqErr.Code) == pqForeignKeyViolation {
+			if strings.Contains(pqErr.Constraint, "task_id") {
+				return ErrTaskNotFound
+			}
+			return ErrUserNotFound
+		}
+		return err
+	}
+
+	if found == 0 {
+		return ErrTaskNotFound
+	}
+
+	return nil
+}
+
+// removeMember deletes a task/user pair from the given join table, if the task
+// belongs to the tenant of ctx.
+func (r *Task) removeMember(ctx context.Context, table string, taskID, userID int64) error {
+	query := fmt.Sprintf(`
+        DELETE FROM %s m
+        USING tasks t
+        WHERE m.task_id = $1 AND m.user_id = $2
+          AND t.id = m.task_id AND t.tenant_id = $3
+    `, table)
+
+	result, err := r.db.ExecContext(ctx, query, taskID, userID, tenant.Of(ctx))
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return ErrTaskMemberNotFound
+	}
+
+	return nil
+}
+
+// loadMembers fills AssigneeIDs and WatcherIDs of the given tasks using one query per join table.
+func (r *Task) loadMembers(ctx context.Context, tasks []*entities.Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, 0, len(tasks))
+	byID := make(map[int64]*entities.Task, len(tasks))
+	for _, t := range tasks {
+		ids = append(ids, t.ID)
+		byID[t.ID] = t
+		t.AssigneeIDs = []int64{}
+		t.WatcherIDs = []int64{}
+	}
+
+	for _, table := range []string{taskAssigneesTable, taskWatchersTable} {
+		query := fmt.Sprintf(`
+            SELECT task_id, user_id
+            FROM %s
+            WHERE task_id = ANY($1)
+            ORDER BY created_at, user_id
+        `, table)
+
+		var members []taskMember
+		if err := r.db.SelectContext(ctx, &members, query, pq.Array(ids)); err != nil {
+			return err
+		}
+
+		for _, m := range members {
+			t := byID[m.TaskID]
+			if table == taskAssigneesTable {
+				t.AssigneeIDs = append(t.AssigneeIDs, m.UserID)
+			} else {
+				t.WatcherIDs = append(t.WatcherIDs, m.UserID)
+			}
+		}
+	}
+
+	return nil
+}
+
+// splitValues splits a comma separated filter value into trimmed, non-empty items.
+func splitValues(value string) []string {
+	var values []string
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
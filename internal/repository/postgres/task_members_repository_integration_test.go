@@ -0,0 +1,128 @@
+package postgres_test
+
+import (
+	"context"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strconv"
+	"task-manager/internal/repository"
+	"task-manager/internal/repository/postgres"
+	"task-manager/internal/utils"
+	"task-manager/pkg/rest"
+	"testing"
+)
+
+// TestTaskMembersIntegration tests adding and removing co-assignees and watchers,
+// and that the primary assignee is always part of the assignees.
+func TestTaskMembersIntegration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	taskRepository := repository.MakeNewTaskRepository()
+
+	createdTask := repository.CreateTestTask()
+	assert.Equal(t, []int64{createdTask.AssigneeID}, createdTask.AssigneeIDs)
+
+	coAssignee := repository.CreateTestUser()
+	watcher := repository.CreateTestUser()
+
+	require.NoError(t, taskRepository.AddAssignee(ctx, createdTask.ID, coAssignee.ID))
+	require.NoError(t, taskRepository.AddWatcher(ctx, createdTask.ID, watcher.ID))
+
+	res, err := taskRepository.GetByID(ctx, createdTask.ID)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []int64{createdTask.AssigneeID, coAssignee.ID}, res.AssigneeIDs)
+	assert.Equal(t, []int64{watcher.ID}, res.WatcherIDs)
+
+	require.NoError(t, taskRepository.RemoveWatcher(ctx, createdTask.ID, watcher.ID))
+	err = taskRepository.RemoveWatcher(ctx, createdTask.ID, watcher.ID)
+	assert.Equal(t, postgres.ErrTaskMemberNotFound, err)
+
+	err = taskRepository.AddAssignee(ctx, createdTask.ID+1000, coAssignee.ID)
+	assert.Equal(t, postgres.ErrTaskNotFound, err)
+
+	t.Cleanup(func() {
+		fmt.Println("🧹 Cleaning up after test...")
+		utils.TruncateTables(t)
+	})
+}
+
+// TestReassignTaskIntegration tests that changing only the primary assignee, as legacy
+// clients do, replaces the previous primary and keeps the other co-assignees.
+func TestReassignTaskIntegration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	taskRepository := repository.MakeNewTaskRepository()
+
+	createdTask := repository.CreateTestTask()
+	previousPrimary := createdTask.AssigneeID
+	coAssignee := repository.CreateTestUser()
+	newPrimary := repository.CreateTestUser()
+	require.NoError(t, taskRepository.AddAssignee(ctx, createdTask.ID, coAssignee.ID))
+
+	createdTask.AssigneeID = newPrimary.ID
+	createdTask.AssigneeIDs = nil
+	updated, err := taskRepository.Update(ctx, createdTask)
+	require.NoError(t, err)
+	assert.Equal(t, newPrimary.ID, updated.AssigneeID)
+	assert.ElementsMatch(t, []int64{newPrimary.ID, coAssignee.ID}, updated.AssigneeIDs)
+	assert.NotContains(t, updated.AssigneeIDs, previousPrimary)
+
+	// Updating other fields keeps the assignees
+	updated.Title = "Renamed"
+	updated.AssigneeIDs = nil
+	updated, err = taskRepository.Update(ctx, updated)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []int64{newPrimary.ID, coAssignee.ID}, updated.AssigneeIDs)
+
+	t.Cleanup(func() {
+		fmt.Println("🧹 Cleaning up after test...")
+		utils.TruncateTables(t)
+	})
+}
+
+// TestListTasksByMembersIntegration tests that assignee_id and watcher_id filters
+// match any of the given users.
+func TestListTasksByMembersIntegration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	taskRepository := repository.MakeNewTaskRepository()
+
+	first := repository.CreateTestTask()
+	second := repository.CreateTestTask()
+	_ = repository.CreateTestTask()
+
+	watcher := repository.CreateTestUser()
+	require.NoError(t, taskRepository.AddWatcher(ctx, second.ID, watcher.ID))
+
+	query := rest.Query{
+		Filter: rest.Filter{
+			"assignee_id": fmt.Sprintf("%d,%d", first.AssigneeID, second.AssigneeID),
+		},
+		PaginationMeta: rest.PaginationMeta{Page: 1, PerPage: 10},
+	}
+
+	_, total, err := taskRepository.List(ctx, query)
+	require.NoError(t, err)
+	assert.Equal(t, 2, total)
+
+	query.Filter = rest.Filter{"watcher_id": strconv.FormatInt(watcher.ID, 10)}
+	res, total, err := taskRepository.List(ctx, query)
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	assert.Equal(t, second.ID, res[0].ID)
+
+	t.Cleanup(func() {
+		fmt.Println("🧹 Cleaning up after test...")
+		utils.TruncateTables(t)
+	})
+}
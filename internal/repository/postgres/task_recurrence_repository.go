@@ -0,0 +1,178 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"task-manager/internal/entities"
+	"task-manager/internal/tenant"
+	"task-manager/pkg/db"
+)
+
+// ErrRecurrenceEnded marks the errors of the series ended by a rule or row that could
+// not be materialized. They do not fail the materialization of the other series.
+var ErrRecurrenceEnded = fmt.Errorf("recurrence series ended")
+
+// NextOccurrenceFunc computes the due date of the occurrence following the given task.
+// ok is false when the recurrence series has ended.
+type NextOccurrenceFunc func(task *entities.Task) (next time.Time, ok bool, err error)
+
+// -----------------------------------------------------------------------------
+// Materialization
+// -----------------------------------------------------------------------------
+
+// MaterializeDueOccurrences creates the next occurrence of every recurring task that
+// was completed/canceled or whose due date has passed, up to limit tasks per call.
+//
+// Candidate rows are locked with FOR UPDATE SKIP LOCKED, so several pods can run the
+// scheduler concurrently without blocking each other or creating duplicates; the
+// unique index on recurrence_parent_id is the last line of defence.
+// It serves every tenant, each occurrence belongs to the tenant of its predecessor.
+// Tasks whose rule cannot be evaluated end their series and are reported in the error,
+// marked with ErrRecurrenceEnded.
+func (r *Task) MaterializeDueOccurrences(ctx context.Context, now time.Time, limit int, next NextOccurrenceFunc) ([]entities.Task, error) {
+	return r.materialize(ctx, `(status IN ('done', 'canceled') OR due_at <= $1) ORDER BY due_at LIMIT $2`, []interface{}{now, limit}, next)
+}
+
+// MaterializeNextOccurrence creates the next occurrence of a single recurring task,
+// typically right after it was completed. It is a no-op (nil task) if the successor
+// already exists, the task is not recurring, it belongs to another tenant than the one
+// of ctx or another pod is handling it right now. It joins the transaction of ctx, if
+// any, so that the occurrence is committed with the change closing the task.
+func (r *Task) MaterializeNextOccurrence(ctx context.Context, taskID int64, next NextOccurrenceFunc) (*entities.Task, error) {
+	created, err := r.materialize(ctx, `id = $1 AND tenant_id = $2`, []interface{}{taskID, tenant.Of(ctx)}, next)
+	if err != nil || len(created) == 0 {
+		return nil, err
+	}
+
+	return &created[0], nil
+}
+
+// materialize locks the pending occurrences matching the condition and inserts their
+// successors in a single transaction, joining the one of ctx if any. Every successor
+// is inserted under a savepoint, so that a failed insert only ends its own series.
+func (r *Task) materialize(ctx context.Context, condition string, args []interface{}, next NextOccurrenceFunc) ([]entities.Task, error) {
+	var (
+		created []entities.Task
+		errs    []error
+	)
+
+	err := db.WithinTx(ctx, r.db, func(ctx context.Context) error {
+		query := `
+            SELECT ` + taskColumns + `
+            FROM tasks
+            WHERE recurrence_rule <> ''
+              AND NOT recurrence_materialized
+              AND ` + condition + `
+            FOR UPDATE SKIP LOCKED
+        `
+
+		var pending []entities.Task
+		if err := r.db.SelectContext(ctx, &pending, query, args...); err != nil {
+			return err
+		}
+
+		for i := range pending {
+			occurrence, err := r.createOccurrence(ctx, &pending[i], next)
+			if err != nil {
+				// A broken rule or row must not block the remaining series: end it and report it
+				errs = append(errs, fmt.Errorf("task %d: %w: %w", pending[i].ID, ErrRecurrenceEnded, err))
+			} else if occurrence != nil {
+				created = append(created, *occurrence)
+			}
+
+			if _, err := r.db.ExecContext(ctx,
+				`UPDATE tasks SET recurrence_materialized = TRUE WHERE id = $1`,
+				pending[i].ID,
+			); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	refs := make([]*entities.Task, 0, len(created))
+	for i := range created {
+		refs = append(refs, &created[i])
+	}
+
+	if err := r.loadMembers(ctx, refs); err != nil {
+		return nil, err
+	}
+
+	return created, errors.Join(errs...)
+}
+
+// createOccurrence inserts the successor of the given task, copying its content,
+// assignees and watchers, under a savepoint of the transaction of ctx. Returns nil if
+// the series has ended or the successor already exists.
+func (r *Task) createOccurrence(ctx context.Context, source *entities.Task, next NextOccurrenceFunc) (occurrence *entities.Task, err error) {
+	dueAt, ok, err := next(source)
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	if _, err := r.db.ExecContext(ctx, `SAVEPOINT occurrence`); err != nil {
+		return nil, err
+	}
+	defer func() {
+		statement := `RELEASE SAVEPOINT occurrence`
+		if err != nil {
+			statement = `ROLLBACK TO SAVEPOINT occurrence`
+		}
+		if _, releaseErr := r.db.ExecContext(ctx, statement); releaseErr != nil {
+			err = errors.Join(err, releaseErr)
+		}
+	}()
+
+	return r.insertOccurrence(ctx, source, dueAt)
+}
+
+// insertOccurrence inserts the successor of source due at dueAt, nil if it already
+// exists.
+func (r *Task) insertOccurrence(ctx context.Context, source *entities.Task, dueAt time.Time) (*entities.Task, error) {
+	occurrence := entities.Task{}
+	query := `
+        INSERT INTO tasks (title, description, status, assignee_id,
+            due_at, recurrence_rule, recurrence_start_at, recurrence_parent_id, tenant_id)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+        ON CONFLICT (recurrence_parent_id) WHERE recurrence_parent_id IS NOT NULL DO NOTHING
+        RETURNING ` + taskColumns
+
+	err := r.db.GetContext(ctx, &occurrence, query,
+		source.Title,
+		source.Description,
+		entities.TaskStatusPending,
+		source.AssigneeID,
+		dueAt,
+		source.RecurrenceRule,
+		source.RecurrenceStartAt,
+		source.ID,
+		source.TenantID,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		// ON CONFLICT DO NOTHING: the successor was created by an earlier run
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, table := range []string{taskAssigneesTable, taskWatchersTable} {
+		if _, err := r.db.ExecContext(ctx,
+			`INSERT INTO `+table+` (task_id, user_id) SELECT $1, user_id FROM `+table+` WHERE task_id = $2`,
+			occurrence.ID, source.ID,
+		); err != nil {
+			return nil, err
+		}
+	}
+
+	return &occurrence, nil
+}
@@ -0,0 +1,124 @@
+package postgres_test
+
+import (
+	"context"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"task-manager/internal/entities"
+	"task-manager/internal/repository"
+	"task-manager/internal/repository/postgres"
+	"task-manager/internal/service"
+	"task-manager/internal/utils"
+	"testing"
+	"time"
+)
+
+// TestMaterializeOccurrencesIntegration tests that completed and overdue recurring tasks
+// get exactly one successor carrying over their members.
+func TestMaterializeOccurrencesIntegration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	taskRepository := repository.MakeNewTaskRepository()
+
+	dueAt := time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+	task := repository.RandomTask()
+	task.AssigneeID = repository.CreateTestUser().ID
+	task.WatcherIDs = []int64{repository.CreateTestUser().ID}
+	task.Status = entities.TaskStatusPending
+	task.DueAt = &dueAt
+	task.RecurrenceRule = "FREQ=DAILY"
+
+	createdTask, err := taskRepository.Create(ctx, task)
+	require.NoError(t, err)
+	require.NotNil(t, createdTask.RecurrenceStartAt)
+
+	now := time.Now()
+	created, err := taskRepository.MaterializeDueOccurrences(ctx, now, 10, service.NextOccurrence(now))
+	require.NoError(t, err)
+	require.Len(t, created, 1)
+
+	occurrence := created[0]
+	assert.Equal(t, createdTask.ID, *occurrence.RecurrenceParentID)
+	assert.Equal(t, entities.TaskStatusPending, occurrence.Status)
+	assert.Equal(t, createdTask.WatcherIDs, occurrence.WatcherIDs)
+	assert.True(t, occurrence.DueAt.After(now))
+
+	// The source is materialized only once
+	created, err = taskRepository.MaterializeDueOccurrences(ctx, now, 10, service.NextOccurrence(now))
+	require.NoError(t, err)
+	assert.Empty(t, created)
+
+	next, err := taskRepository.MaterializeNextOccurrence(ctx, createdTask.ID, service.NextOccurrence(now))
+	require.NoError(t, err)
+	assert.Nil(t, next)
+
+	t.Cleanup(func() {
+		fmt.Println("🧹 Cleaning up after test...")
+		utils.TruncateTables(t)
+	})
+}
+
+// TestMaterializeOccurrences_BrokenRow_ShouldNotBlockTheBatchIntegration tests that a
+// successor failing to insert only ends its own series, and that a successor that
+// already exists is a no-op.
+func TestMaterializeOccurrences_BrokenRow_ShouldNotBlockTheBatchIntegration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	taskRepository := repository.MakeNewTaskRepository()
+
+	sources := make([]*entities.Task, 2)
+	for i := range sources {
+		dueAt := time.Now().Add(time.Duration(i-2) * time.Hour).UTC().Truncate(time.Second)
+		task := repository.RandomTask()
+		task.AssigneeID = repository.CreateTestUser().ID
+		task.Status = entities.TaskStatusPending
+		task.DueAt = &dueAt
+		task.RecurrenceRule = "FREQ=DAILY"
+
+		created, err := taskRepository.Create(ctx, task)
+		require.NoError(t, err)
+		sources[i] = created
+	}
+
+	// The first source is due first; its successor is out of the timestamp range of
+	// Postgres, failing the INSERT
+	now := time.Now()
+	next := func(task *entities.Task) (time.Time, bool, error) {
+		if task.ID == sources[0].ID {
+			return time.Date(300000, 1, 1, 0, 0, 0, 0, time.UTC), true, nil
+		}
+		return service.NextOccurrence(now)(task)
+	}
+
+	created, err := taskRepository.MaterializeDueOccurrences(ctx, now, 10, next)
+	assert.ErrorContains(t, err, fmt.Sprintf("task %d", sources[0].ID))
+	assert.ErrorIs(t, err, postgres.ErrRecurrenceEnded)
+	require.Len(t, created, 1)
+	assert.Equal(t, sources[1].ID, *created[0].RecurrenceParentID)
+
+	// Both series were handled, the broken one is not selected again
+	created, err = taskRepository.MaterializeDueOccurrences(ctx, now, 10, next)
+	require.NoError(t, err)
+	assert.Empty(t, created)
+
+	// A successor that already exists is a no-op, not an error
+	_, err = utils.CreateTestDatabaseConnection().ExecContext(ctx,
+		`UPDATE tasks SET recurrence_materialized = FALSE, status = 'done' WHERE id = $1`, sources[1].ID)
+	require.NoError(t, err)
+
+	occurrence, err := taskRepository.MaterializeNextOccurrence(ctx, sources[1].ID, service.NextOccurrence(now))
+	require.NoError(t, err)
+	assert.Nil(t, occurrence)
+
+	t.Cleanup(func() {
+		fmt.Println("🧹 Cleaning up after test...")
+		utils.TruncateTables(t)
+	})
+}
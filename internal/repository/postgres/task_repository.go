@@ -5,9 +5,12 @@ import (
 	"database/sql"
 	"fmt"
 	"strings"
+	"task-manager/internal/tenant"
 	"task-manager/pkg/rest"
+	"time"
 
 	"github.com/cockroachdb/errors"
+	"github.com/lib/pq"
 	"task-manager/internal/entities"
 	"task-manager/pkg/db"
 )
@@ -19,17 +22,32 @@ import (
 // ErrTaskNotFound is returned when a task with the given ID does not exist.
 var ErrTaskNotFound = fmt.Errorf("task not found")
 
+// taskColumns lists the columns mapped onto entities.Task, shared by every SELECT/RETURNING.
+const taskColumns = `id, tenant_id, title, description, status, assignee_id, due_at,
+            recurrence_rule, recurrence_start_at, recurrence_parent_id, created_at, updated_at`
+
 // -----------------------------------------------------------------------------
 // Interfaces
 // -----------------------------------------------------------------------------
 
 // TaskRepository defines all the operations required for interacting with tasks.
+// Every operation is scoped to the tenant of its context (tenant.Of), except
+// MaterializeDueOccurrences, which serves every tenant.
 type TaskRepository interface {
 	Create(ctx context.Context, task *entities.Task) (*entities.Task, error)
 	Update(ctx context.Context, task *entities.Task) (*entities.Task, error)
 	GetByID(ctx context.Context, id int64) (*entities.Task, error)
+	GetForUpdate(ctx context.Context, id int64) (*entities.Task, error)
 	Delete(ctx context.Context, id int64) error
 	List(ctx context.Context, query rest.Query) ([]entities.Task, int, error)
+
+	AddAssignee(ctx context.Context, taskID, userID int64) error
+	RemoveAssignee(ctx context.Context, taskID, userID int64) error
+	AddWatcher(ctx context.Context, taskID, userID int64) error
+	RemoveWatcher(ctx context.Context, taskID, userID int64) error
+
+	MaterializeDueOccurrences(ctx context.Context, now time.Time, limit int, next NextOccurrenceFunc) ([]entities.Task, error)
+	MaterializeNextOccurrence(ctx context.Context, taskID int64, next NextOccurrenceFunc) (*entities.Task, error)
 }
 
 // -----------------------------------------------------------------------------
@@ -41,30 +59,70 @@ type Task struct {
 	db db.DB
 }
 
+// TaskRepositoryOption configures optional Task repository behaviour.
+type TaskRepositoryOption func(*Task)
+
 // NewTaskRepository returns a new Task repository.
-func NewTaskRepository(db db.DB) *Task {
-	return &Task{db: db}
+func NewTaskRepository(db db.DB, opts ...TaskRepositoryOption) *Task {
+	r := &Task{db: db}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r
 }
 
 // -----------------------------------------------------------------------------
 // Create
 // -----------------------------------------------------------------------------
 
-// Create inserts a new task and returns the created entity with generated fields.
+// Create inserts a new task together with its assignees and watchers in a single
+// statement and returns the created entity with generated fields.
+// The task belongs to the tenant of ctx, whatever its TenantID.
+// The primary assignee is always stored as one of the task assignees.
 func (r *Task) Create(ctx context.Context, t *entities.Task) (*entities.Task, error) {
 	query := `
-        INSERT INTO tasks (title, description, status, assignee_id)
-        VALUES ($1, $2, $3, $4)
-        RETURNING id, created_at, updated_at
+        WITH created AS (
+            INSERT INTO tasks (title, description, status, assignee_id,
+                due_at, recurrence_rule, recurrence_start_at, recurrence_parent_id, tenant_id)
+            VALUES ($1, $2, $3, $4, $7, $8, COALESCE($9, $7), $10, $11)
+            RETURNING ` + taskColumns + `
+        ), assignees AS (
+            INSERT INTO task_assignees (task_id, user_id)
+            SELECT DISTINCT created.id, user_id
+            FROM created, unnest(array_append($5::int[], $4)) AS user_id
+            ON CONFLICT DO NOTHING
+        ), watchers AS (
+            INSERT INTO task_watchers (task_id, user_id)
+            SELECT DISTINCT created.id, user_id
+            FROM created, unnest($6::int[]) AS user_id
+            ON CONFLICT DO NOTHING
+        )
+        SELECT ` + taskColumns + ` FROM created
     `
 	err := r.db.GetContext(ctx, t, query,
 		t.Title,
 		t.Description,
 		t.Status,
 		t.AssigneeID,
+		pq.Array(t.AssigneeIDs),
+		pq.Array(t.WatcherIDs),
+		t.DueAt,
+		t.RecurrenceRule,
+		t.RecurrenceStartAt,
+		t.RecurrenceParentID,
+		tenant.Of(ctx),
 	)
 
 	if err != nil {
+		if isPQError(err, pqForeignKeyViolation) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	if err := r.loadMembers(ctx, []*entities.Task{t}); err != nil {
 		return nil, err
 	}
 
@@ -78,15 +136,27 @@ func (r *Task) Create(ctx context.Context, t *entities.Task) (*entities.Task, er
 // GetByID fetches a task by its ID.
 // Returns ErrTaskNotFound if no rows are returned.
 func (r *Task) GetByID(ctx context.Context, id int64) (*entities.Task, error) {
+	return r.get(ctx, id, "")
+}
+
+// GetForUpdate fetches a task by its ID and locks its row until the running
+// transaction ends, so concurrent writers are serialized.
+// Returns ErrTaskNotFound if no rows are returned.
+func (r *Task) GetForUpdate(ctx context.Context, id int64) (*entities.Task, error) {
+	return r.get(ctx, id, "FOR UPDATE")
+}
+
+// get fetches a task by its ID, appending the given locking clause to the query.
+func (r *Task) get(ctx context.Context, id int64, lock string) (*entities.Task, error) {
 	var task entities.Task
 
 	query := `
-        SELECT id, title, description, status, assignee_id, created_at, updated_at
+        SELECT ` + taskColumns + `
         FROM tasks
-        WHERE id = $1
-    `
+        WHERE id = $1 AND tenant_id = $2
+    ` + lock
 
-	err := r.db.GetContext(ctx, &task, query, id)
+	err := r.db.GetContext(ctx, &task, query, id, tenant.Of(ctx))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, ErrTaskNotFound
@@ -94,6 +164,10 @@ func (r *Task) GetByID(ctx context.Context, id int64) (*entities.Task, error) {
 		return nil, err
 	}
 
+	if err := r.loadMembers(ctx, []*entities.Task{&task}); err != nil {
+		return nil, err
+	}
+
 	return &task, nil
 }
 
@@ -102,35 +176,47 @@ func (r *Task) GetByID(ctx context.Context, id int64) (*entities.Task, error) {
 // -----------------------------------------------------------------------------
 
 // List returns a list of tasks matching filters, along with the total count.
-// Supports filtering by: status, assignee_id, title.
+// Supports filtering by: id, status, title, assignee_id and watcher_id.
+// id accepts comma separated task IDs. assignee_id and watcher_id accept comma
+// separated user IDs and match tasks having any of them as an assignee or watcher
+// respectively.
 func (r *Task) List(ctx context.Context, query rest.Query) ([]entities.Task, int, error) {
 	baseQuery := `
-        SELECT id, title, description, status, assignee_id, created_at, updated_at
+        SELECT ` + taskColumns + `
         FROM tasks
     `
 	countQuery := `SELECT COUNT(*) FROM tasks`
 
 	var (
-		args       []interface{}
-		conditions []string
-		i          = 1
+		args       = []interface{}{tenant.Of(ctx)}
+		conditions = []string{"tenant_id = $1"}
+		i          = 2
 	)
 
 	// Build WHERE filters dynamically
 	for field, value := range query.Filter {
 		switch field {
-		case "status", "assignee_id", "title":
+		case "status", "title":
 			conditions = append(conditions, fmt.Sprintf("%s = $%d", field, i))
 			args = append(args, value)
 			i++
+		case "id":
+			conditions = append(conditions, fmt.Sprintf("id = ANY($%d::int[])", i))
+			args = append(args, pq.StringArray(splitValues(value)))
+			i++
+		case "assignee_id", "watcher_id":
+			conditions = append(conditions, fmt.Sprintf(
+				"EXISTS (SELECT 1 FROM %s m WHERE m.task_id = tasks.id AND m.user_id = ANY($%d::int[]))",
+				memberTables[field], i,
+			))
+			args = append(args, pq.StringArray(splitValues(value)))
+			i++
 		}
 	}
 
-	if len(conditions) > 0 {
-		where := " WHERE " + strings.Join(conditions, " AND ")
-		baseQuery += where
-		countQuery += where
-	}
+	where := " WHERE " + strings.Join(conditions, " AND ")
+	baseQuery += where
+	countQuery += where
 
 	// Fetch total count
 	var total int
@@ -148,6 +234,15 @@ func (r *Task) List(ctx context.Context, query rest.Query) ([]entities.Task, int
 		return nil, 0, err
 	}
 
+	refs := make([]*entities.Task, 0, len(tasks))
+	for i := range tasks {
+		refs = append(refs, &tasks[i])
+	}
+
+	if err := r.loadMembers(ctx, refs); err != nil {
+		return nil, 0, err
+	}
+
 	return tasks, total, nil
 }
 
@@ -156,29 +251,80 @@ func (r *Task) List(ctx context.Context, query rest.Query) ([]entities.Task, int
 // -----------------------------------------------------------------------------
 
 // Update modifies an existing task and returns the updated entity.
+// A nil AssigneeIDs or WatcherIDs leaves the current set untouched, otherwise the
+// set is replaced. The primary assignee is always kept as a task assignee; with a nil
+// AssigneeIDs, a primary assignee replaced by another one leaves the assignees, as
+// legacy clients reassigning the task through AssigneeID expect.
 // If the task does not exist, ErrTaskNotFound is returned.
 func (r *Task) Update(ctx context.Context, t *entities.Task) (*entities.Task, error) {
 	query := `
-        UPDATE tasks
-        SET title = $1,
-            description = $2,
-            status = $3,
-            updated_at = now()
-        WHERE id = $4
-        RETURNING id, title, description, status, assignee_id, created_at, updated_at
+        WITH previous AS (
+            -- Sibling statements see the task as it was before the update
+            SELECT assignee_id FROM tasks WHERE id = $5 AND tenant_id = $11
+        ), updated AS (
+            UPDATE tasks
+            SET title = $1,
+                description = $2,
+                status = $3,
+                assignee_id = $4,
+                due_at = $8,
+                recurrence_rule = $9,
+                recurrence_start_at = COALESCE($10, recurrence_start_at, $8),
+                updated_at = now()
+            WHERE id = $5 AND tenant_id = $11
+            RETURNING ` + taskColumns + `
+        ), removed_assignees AS (
+            DELETE FROM task_assignees
+            WHERE task_id = (SELECT id FROM updated)
+              AND user_id <> $4
+              AND CASE
+                  WHEN $6::int[] IS NULL THEN user_id = (SELECT assignee_id FROM previous)
+                  ELSE user_id <> ALL($6::int[])
+              END
+        ), added_assignees AS (
+            INSERT INTO task_assignees (task_id, user_id)
+            SELECT DISTINCT updated.id, user_id
+            FROM updated, unnest(array_append(COALESCE($6::int[], '{}'), $4)) AS user_id
+            ON CONFLICT DO NOTHING
+        ), removed_watchers AS (
+            DELETE FROM task_watchers
+            WHERE task_id = (SELECT id FROM updated)
+              AND $7::int[] IS NOT NULL
+              AND user_id <> ALL($7::int[])
+        ), added_watchers AS (
+            INSERT INTO task_watchers (task_id, user_id)
+            SELECT DISTINCT updated.id, user_id
+            FROM updated, unnest($7::int[]) AS user_id
+            ON CONFLICT DO NOTHING
+        )
+        SELECT ` + taskColumns + ` FROM updated
     `
 
 	err := r.db.GetContext(ctx, t, query,
 		t.Title,
 		t.Description,
 		t.Status,
+		t.AssigneeID,
 		t.ID,
+		pq.Array(t.AssigneeIDs),
+		pq.Array(t.WatcherIDs),
+		t.DueAt,
+		t.RecurrenceRule,
+		t.RecurrenceStartAt,
+		tenant.Of(ctx),
 	)
 
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, ErrTaskNotFound
 		}
+		if isPQError(err, pqForeignKeyViolation) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	if err := r.loadMembers(ctx, []*entities.Task{t}); err != nil {
 		return nil, err
 	}
 
@@ -192,9 +338,9 @@ func (r *Task) Update(ctx context.Context, t *entities.Task) (*entities.Task, er
 // Delete removes a task by ID.
 // Returns ErrTaskNotFound if no record was deleted.
 func (r *Task) Delete(ctx context.Context, id int64) error {
-	query := `DELETE FROM tasks WHERE id = $1`
+	query := `DELETE FROM tasks WHERE id = $1 AND tenant_id = $2`
 
-	result, err := r.db.ExecContext(ctx, query, id)
+	result, err := r.db.ExecContext(ctx, query, id, tenant.Of(ctx))
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,67 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"task-manager/internal/tenant"
+	"task-manager/pkg/db"
+)
+
+// -----------------------------------------------------------------------------
+// Row-level security
+// -----------------------------------------------------------------------------
+
+// WithRowLevelSecurity backs the tenant scoping of the queries with the row-level
+// security policy of the tasks table: every statement issued with a tenant context
+// runs in a transaction setting app.tenant_id, so the database itself hides the
+// rows of the other tenants. The policy only binds roles that do not own the table,
+// unless it is forced (ALTER TABLE tasks FORCE ROW LEVEL SECURITY).
+func WithRowLevelSecurity() TaskRepositoryOption {
+	return func(r *Task) {
+		r.db = rowLevelSecurity{DB: r.db}
+	}
+}
+
+// rowLevelSecurity is a db.DB setting app.tenant_id before the statements of a tenant
+// context. Statements issued within a transaction of db.WithinTx join it.
+// QueryContext is passed through unbound, its rows would outlive the transaction;
+// the task repository does not use it.
+type rowLevelSecurity struct {
+	db.DB
+}
+
+// within runs fn in a transaction bound to the tenant of ctx, if any.
+func (d rowLevelSecurity) within(ctx context.Context, fn func(ctx context.Context) error) error {
+	id, ok := tenant.FromContext(ctx)
+	if !ok {
+		return fn(ctx)
+	}
+
+	return db.WithinTx(ctx, d.DB, func(ctx context.Context) error {
+		if _, err := d.DB.ExecContext(ctx, `SELECT set_config('app.tenant_id', $1, TRUE)`, id); err != nil {
+			return err
+		}
+		return fn(ctx)
+	})
+}
+
+func (d rowLevelSecurity) ExecContext(ctx context.Context, query string, args ...interface{}) (result sql.Result, err error) {
+	err = d.within(ctx, func(ctx context.Context) error {
+		result, err = d.DB.ExecContext(ctx, query, args...)
+		return err
+	})
+	return
+}
+
+func (d rowLevelSecurity) GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	return d.within(ctx, func(ctx context.Context) error {
+		return d.DB.GetContext(ctx, dest, query, args...)
+	})
+}
+
+func (d rowLevelSecurity) SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	return d.within(ctx, func(ctx context.Context) error {
+		return d.DB.SelectContext(ctx, dest, query, args...)
+	})
+}
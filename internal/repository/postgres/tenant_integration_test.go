@@ -0,0 +1,130 @@
+package postgres_test
+
+import (
+	"context"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"task-manager/internal/repository"
+	"task-manager/internal/repository/postgres"
+	"task-manager/internal/tenant"
+	"task-manager/internal/utils"
+	"task-manager/pkg/db"
+	"task-manager/pkg/rest"
+	"testing"
+)
+
+// TestTaskTenantIsolationIntegration tests that the tasks of a tenant can't be read
+// nor written from another tenant.
+func TestTaskTenantIsolationIntegration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	acme := tenant.WithID(context.Background(), "acme")
+	globex := tenant.WithID(context.Background(), "globex")
+	taskRepository := repository.MakeNewTaskRepository()
+
+	task := repository.RandomTask()
+	task.AssigneeID = repository.CreateTestUser().ID
+	created, err := taskRepository.Create(acme, task)
+	require.NoError(t, err)
+	assert.Equal(t, "acme", created.TenantID)
+
+	_, err = taskRepository.GetByID(globex, created.ID)
+	assert.ErrorIs(t, err, postgres.ErrTaskNotFound)
+
+	tasks, total, err := taskRepository.List(globex, rest.Query{PaginationMeta: rest.PaginationMeta{Page: 1, PerPage: 10}})
+	require.NoError(t, err)
+	assert.Zero(t, total)
+	assert.Empty(t, tasks)
+
+	update := *created
+	update.Title = "taken over"
+	_, err = taskRepository.Update(globex, &update)
+	assert.ErrorIs(t, err, postgres.ErrTaskNotFound)
+
+	watcher := repository.CreateTestUser()
+	assert.ErrorIs(t, taskRepository.AddWatcher(globex, created.ID, watcher.ID), postgres.ErrTaskNotFound)
+	assert.ErrorIs(t, taskRepository.RemoveAssignee(globex, created.ID, created.AssigneeID), postgres.ErrTaskMemberNotFound)
+	assert.ErrorIs(t, taskRepository.Delete(globex, created.ID), postgres.ErrTaskNotFound)
+
+	// The task is untouched in its own tenant, the default tenant does not see it either
+	found, err := taskRepository.GetByID(acme, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, created.Title, found.Title)
+	assert.Equal(t, []int64{created.AssigneeID}, found.AssigneeIDs)
+	assert.Empty(t, found.WatcherIDs)
+
+	_, err = taskRepository.GetByID(context.Background(), created.ID)
+	assert.ErrorIs(t, err, postgres.ErrTaskNotFound)
+
+	t.Cleanup(func() {
+		fmt.Println("🧹 Cleaning up after test...")
+		utils.TruncateTables(t)
+	})
+}
+
+// TestTaskRowLevelSecurityIntegration tests that, with the policy forced, the
+// database itself rejects cross-tenant reads and writes.
+func TestTaskRowLevelSecurityIntegration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	conn := utils.CreateTestDatabaseConnection()
+	_, err := conn.ExecContext(context.Background(), `ALTER TABLE tasks FORCE ROW LEVEL SECURITY`)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_, _ = conn.ExecContext(context.Background(), `ALTER TABLE tasks NO FORCE ROW LEVEL SECURITY`)
+	})
+
+	acme := tenant.WithID(context.Background(), "acme")
+	taskRepository := postgres.NewTaskRepository(conn, postgres.WithRowLevelSecurity())
+
+	task := repository.RandomTask()
+	task.AssigneeID = repository.CreateTestUser().ID
+	created, err := taskRepository.Create(acme, task)
+	require.NoError(t, err)
+
+	found, err := taskRepository.GetByID(acme, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, created.ID, found.ID)
+
+	// Statements bypassing the repository scoping still only see their tenant
+	err = db.WithinTx(context.Background(), conn, func(ctx context.Context) error {
+		if _, err := conn.ExecContext(ctx, `SELECT set_config('app.tenant_id', 'globex', TRUE)`); err != nil {
+			return err
+		}
+
+		var visible int
+		if err := conn.GetContext(ctx, &visible, `SELECT COUNT(*) FROM tasks WHERE id = $1`, created.ID); err != nil {
+			return err
+		}
+		assert.Zero(t, visible)
+
+		result, err := conn.ExecContext(ctx, `UPDATE tasks SET title = 'taken over' WHERE id = $1`, created.ID)
+		if err != nil {
+			return err
+		}
+		updated, _ := result.RowsAffected()
+		assert.Zero(t, updated)
+		return nil
+	})
+	require.NoError(t, err)
+
+	// A tenant can't move its rows to another one
+	err = db.WithinTx(context.Background(), conn, func(ctx context.Context) error {
+		if _, err := conn.ExecContext(ctx, `SELECT set_config('app.tenant_id', 'acme', TRUE)`); err != nil {
+			return err
+		}
+		_, err := conn.ExecContext(ctx, `UPDATE tasks SET tenant_id = 'globex' WHERE id = $1`, created.ID)
+		return err
+	})
+	assert.ErrorContains(t, err, "row-level security")
+
+	t.Cleanup(func() {
+		fmt.Println("🧹 Cleaning up after test...")
+		utils.TruncateTables(t)
+	})
+}
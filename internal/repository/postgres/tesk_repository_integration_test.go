@@ -22,6 +22,7 @@ func TestCreateTaskIntegration(t *testing.T) {
 	taskRepository := repository.MakeNewTaskRepository()
 
 	taskInstance := repository.RandomTask()
+	taskInstance.AssigneeID = repository.CreateTestUser().ID
 
 	res, err := taskRepository.Create(context.Background(), taskInstance)
 
@@ -179,3 +180,33 @@ func TestListTasksIntegration(t *testing.T) {
 		utils.TruncateTables(t)
 	})
 }
+
+// TestListTasksByIDIntegration tests that the id filter returns exactly the given tasks,
+// as used to batch loads by ID.
+func TestListTasksByIDIntegration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	taskRepository := repository.MakeNewTaskRepository()
+
+	first := repository.CreateTestTask()
+	second := repository.CreateTestTask()
+	_ = repository.CreateTestTask()
+
+	query := rest.Query{
+		Filter:         rest.Filter{"id": fmt.Sprintf("%d,%d", first.ID, second.ID)},
+		PaginationMeta: rest.PaginationMeta{Page: 1, PerPage: 10},
+	}
+
+	res, total, err := taskRepository.List(ctx, query)
+	require.NoError(t, err)
+	assert.Equal(t, 2, total)
+	assert.ElementsMatch(t, []int64{first.ID, second.ID}, []int64{res[0].ID, res[1].ID})
+
+	t.Cleanup(func() {
+		fmt.Println("🧹 Cleaning up after test...")
+		utils.TruncateTables(t)
+	})
+}
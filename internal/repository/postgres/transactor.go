@@ -0,0 +1,29 @@
+package postgres
+
+import (
+	"context"
+
+	"task-manager/pkg/db"
+)
+
+// Transactor runs a function in a database transaction. Every repository call made
+// with the context passed to fn joins the transaction.
+type Transactor interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// TxManager implements Transactor using a SQL database.
+type TxManager struct {
+	db db.DB
+}
+
+// NewTransactor returns a new Transactor for the given database.
+func NewTransactor(db db.DB) *TxManager {
+	return &TxManager{db: db}
+}
+
+// WithinTx commits the transaction if fn returns nil and rolls it back otherwise.
+// Nested calls reuse the outer transaction.
+func (m *TxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return db.WithinTx(ctx, m.db, fn)
+}
@@ -0,0 +1,264 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"task-manager/pkg/rest"
+
+	"github.com/cockroachdb/errors"
+	"github.com/lib/pq"
+	"task-manager/internal/entities"
+	"task-manager/internal/tenant"
+	"task-manager/pkg/db"
+)
+
+// -----------------------------------------------------------------------------
+// Errors
+// -----------------------------------------------------------------------------
+
+// ErrUserNotFound is returned when a user with the given ID does not exist.
+var ErrUserNotFound = fmt.Errorf("user not found")
+
+// ErrUserEmailExists is returned when another user of the tenant already owns the given email.
+var ErrUserEmailExists = fmt.Errorf("user email already exists")
+
+// ErrUserInUse is returned when a user cannot be deleted because tasks still reference it.
+var ErrUserInUse = fmt.Errorf("user is still assigned to tasks")
+
+// userColumns lists the columns mapped onto entities.User.
+const userColumns = `id, tenant_id, name, email, is_active, created_at, updated_at`
+
+// Postgres error codes used to translate constraint violations into domain errors.
+const (
+	pqUniqueViolation     = "23505"
+	pqForeignKeyViolation = "23503"
+)
+
+// -----------------------------------------------------------------------------
+// Interfaces
+// -----------------------------------------------------------------------------
+
+// UserRepository defines all the operations required for interacting with users.
+// Users are scoped to the tenant of the context (tenant.Of).
+type UserRepository interface {
+	Create(ctx context.Context, user *entities.User) (*entities.User, error)
+	Update(ctx context.Context, user *entities.User) (*entities.User, error)
+	GetByID(ctx context.Context, id int64) (*entities.User, error)
+	GetByIDs(ctx context.Context, ids []int64) ([]entities.User, error)
+	Delete(ctx context.Context, id int64) error
+	List(ctx context.Context, query rest.Query) ([]entities.User, int, error)
+}
+
+// -----------------------------------------------------------------------------
+// Repository implementation
+// -----------------------------------------------------------------------------
+
+// User implements UserRepository using a SQL database.
+type User struct {
+	db db.DB
+}
+
+// NewUserRepository returns a new User repository.
+func NewUserRepository(db db.DB) *User {
+	return &User{db: db}
+}
+
+// -----------------------------------------------------------------------------
+// Create
+// -----------------------------------------------------------------------------
+
+// Create inserts a new user in the tenant of ctx and returns the created entity with
+// generated fields. Returns ErrUserEmailExists if the email is already taken.
+func (r *User) Create(ctx context.Context, u *entities.User) (*entities.User, error) {
+	query := `
+        INSERT INTO users (name, email, is_active, tenant_id)
+        VALUES ($1, $2, $3, $4)
+        RETURNING id, tenant_id, created_at, updated_at
+    `
+	err := r.db.GetContext(ctx, u, query,
+		u.Name,
+		u.Email,
+		u.IsActive,
+		tenant.Of(ctx),
+	)
+
+	if err != nil {
+		if isPQError(err, pqUniqueViolation) {
+			return nil, ErrUserEmailExists
+		}
+		return nil, err
+	}
+
+	return u, nil
+}
+
+// -----------------------------------------------------------------------------
+// GetByID
+// -----------------------------------------------------------------------------
+
+// GetByID fetches a user by its ID.
+// Returns ErrUserNotFound if no rows are returned.
+func (r *User) GetByID(ctx context.Context, id int64) (*entities.User, error) {
+	var user entities.User
+
+	query := `SELECT ` + userColumns + ` FROM users WHERE id = $1 AND tenant_id = $2`
+
+	err := r.db.GetContext(ctx, &user, query, id, tenant.Of(ctx))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// -----------------------------------------------------------------------------
+// GetByIDs
+// -----------------------------------------------------------------------------
+
+// GetByIDs fetches the users of the given IDs in a single query, ordered by ID.
+// IDs without a user are left out of the result.
+func (r *User) GetByIDs(ctx context.Context, ids []int64) ([]entities.User, error) {
+	users := []entities.User{}
+	if len(ids) == 0 {
+		return users, nil
+	}
+
+	query := `SELECT ` + userColumns + ` FROM users WHERE id = ANY($1) AND tenant_id = $2 ORDER BY id`
+
+	if err := r.db.SelectContext(ctx, &users, query, pq.Array(ids), tenant.Of(ctx)); err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+// -----------------------------------------------------------------------------
+// List (with pagination + filters)
+// -----------------------------------------------------------------------------
+
+// List returns a list of users matching filters, along with the total count.
+// Supports filtering by: id (comma separated user IDs), name, email, is_active.
+func (r *User) List(ctx context.Context, query rest.Query) ([]entities.User, int, error) {
+	baseQuery := `SELECT ` + userColumns + ` FROM users`
+	countQuery := `SELECT COUNT(*) FROM users`
+
+	var (
+		args       = []interface{}{tenant.Of(ctx)}
+		conditions = []string{"tenant_id = $1"}
+		i          = 2
+	)
+
+	// Build WHERE filters dynamically
+	for field, value := range query.Filter {
+		switch field {
+		case "id":
+			conditions = append(conditions, fmt.Sprintf("id = ANY($%d::int[])", i))
+			args = append(args, pq.StringArray(splitValues(value)))
+			i++
+		case "name", "email", "is_active":
+			conditions = append(conditions, fmt.Sprintf("%s = $%d", field, i))
+			args = append(args, value)
+			i++
+		}
+	}
+
+	where := " WHERE " + strings.Join(conditions, " AND ")
+	baseQuery += where
+	countQuery += where
+
+	// Fetch total count
+	var total int
+	if err := r.db.GetContext(ctx, &total, countQuery, args...); err != nil {
+		return nil, 0, err
+	}
+
+	// Pagination
+	offset := (query.Page - 1) * query.PerPage
+	baseQuery += fmt.Sprintf(" ORDER BY id LIMIT $%d OFFSET $%d", i, i+1)
+	args = append(args, query.PerPage, offset)
+
+	var users []entities.User
+	if err := r.db.SelectContext(ctx, &users, baseQuery, args...); err != nil {
+		return nil, 0, err
+	}
+
+	return users, total, nil
+}
+
+// -----------------------------------------------------------------------------
+// Update
+// -----------------------------------------------------------------------------
+
+// Update modifies an existing user and returns the updated entity.
+// If the user does not exist, ErrUserNotFound is returned.
+func (r *User) Update(ctx context.Context, u *entities.User) (*entities.User, error) {
+	query := `
+        UPDATE users
+        SET name = $1,
+            email = $2,
+            is_active = $3,
+            updated_at = now()
+        WHERE id = $4 AND tenant_id = $5
+        RETURNING ` + userColumns
+
+	err := r.db.GetContext(ctx, u, query,
+		u.Name,
+		u.Email,
+		u.IsActive,
+		u.ID,
+		tenant.Of(ctx),
+	)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+		if isPQError(err, pqUniqueViolation) {
+			return nil, ErrUserEmailExists
+		}
+		return nil, err
+	}
+
+	return u, nil
+}
+
+// -----------------------------------------------------------------------------
+// Delete
+// -----------------------------------------------------------------------------
+
+// Delete removes a user by ID.
+// Returns ErrUserNotFound if no record was deleted and ErrUserInUse if tasks
+// still reference the user.
+func (r *User) Delete(ctx context.Context, id int64) error {
+	query := `DELETE FROM users WHERE id = $1 AND tenant_id = $2`
+
+	result, err := r.db.ExecContext(ctx, query, id, tenant.Of(ctx))
+	if err != nil {
+		if isPQError(err, pqForeignKeyViolation) {
+			return ErrUserInUse
+		}
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return ErrUserNotFound
+	}
+
+	return nil
+}
+
+// isPQError reports whether err is a Postgres error with the given SQLSTATE code.
+func isPQError(err error, code string) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && string(pqErr.Code) == code
+}
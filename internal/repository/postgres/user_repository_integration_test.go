@@ -0,0 +1,145 @@
+package postgres_test
+
+import (
+	"context"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"task-manager/internal/repository"
+	"task-manager/internal/repository/postgres"
+	"task-manager/internal/tenant"
+	"task-manager/internal/utils"
+	"task-manager/pkg/rest"
+	"testing"
+)
+
+// TestCreateUserIntegration tests that a user can be created and fetched back,
+// and that a duplicated email is rejected.
+func TestCreateUserIntegration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	userRepository := repository.MakeNewUserRepository()
+
+	userInstance := repository.RandomUser()
+	res, err := userRepository.Create(ctx, userInstance)
+	require.NoError(t, err)
+	assert.NotZero(t, res.ID)
+
+	fetched, err := userRepository.GetByID(ctx, res.ID)
+	require.NoError(t, err)
+	assert.Equal(t, userInstance.Email, fetched.Email)
+	assert.True(t, fetched.IsActive)
+
+	duplicate := repository.RandomUser()
+	duplicate.Email = userInstance.Email
+	_, err = userRepository.Create(ctx, duplicate)
+	assert.Equal(t, postgres.ErrUserEmailExists, err)
+
+	t.Cleanup(func() {
+		fmt.Println("🧹 Cleaning up after test...")
+		utils.TruncateTables(t)
+	})
+}
+
+// TestDeleteUserIntegration tests that users referenced by tasks cannot be deleted,
+// while unassigned users can.
+func TestDeleteUserIntegration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	userRepository := repository.MakeNewUserRepository()
+
+	createdTask := repository.CreateTestTask()
+	err := userRepository.Delete(ctx, createdTask.AssigneeID)
+	assert.Equal(t, postgres.ErrUserInUse, err)
+
+	unassigned := repository.CreateTestUser()
+	require.NoError(t, userRepository.Delete(ctx, unassigned.ID))
+
+	err = userRepository.Delete(ctx, unassigned.ID)
+	assert.Equal(t, postgres.ErrUserNotFound, err)
+
+	t.Cleanup(func() {
+		fmt.Println("🧹 Cleaning up after test...")
+		utils.TruncateTables(t)
+	})
+}
+
+// TestGetUsersByIDsIntegration tests that the users of a batch are fetched at once,
+// leaving out the missing IDs.
+func TestGetUsersByIDsIntegration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	userRepository := repository.MakeNewUserRepository()
+
+	first := repository.CreateTestUser()
+	second := repository.CreateTestUser()
+
+	users, err := userRepository.GetByIDs(ctx, []int64{second.ID, first.ID, second.ID + 1000})
+	require.NoError(t, err)
+	require.Len(t, users, 2)
+	assert.Equal(t, first.ID, users[0].ID)
+	assert.Equal(t, second.ID, users[1].ID)
+
+	users, err = userRepository.GetByIDs(ctx, nil)
+	require.NoError(t, err)
+	assert.Empty(t, users)
+
+	t.Cleanup(func() {
+		fmt.Println("🧹 Cleaning up after test...")
+		utils.TruncateTables(t)
+	})
+}
+
+// TestUserTenantIsolationIntegration tests that users are hidden from the other
+// tenants, and that emails are only unique within a tenant.
+func TestUserTenantIsolationIntegration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	acme := tenant.WithID(context.Background(), "acme")
+	globex := tenant.WithID(context.Background(), "globex")
+	userRepository := repository.MakeNewUserRepository()
+
+	acmeUser, err := userRepository.Create(acme, repository.RandomUser())
+	require.NoError(t, err)
+	assert.Equal(t, "acme", acmeUser.TenantID)
+
+	namesake := repository.RandomUser()
+	namesake.Email = acmeUser.Email
+	globexUser, err := userRepository.Create(globex, namesake)
+	require.NoError(t, err)
+
+	_, err = userRepository.GetByID(globex, acmeUser.ID)
+	assert.ErrorIs(t, err, postgres.ErrUserNotFound)
+
+	users, err := userRepository.GetByIDs(globex, []int64{acmeUser.ID, globexUser.ID})
+	require.NoError(t, err)
+	require.Len(t, users, 1)
+	assert.Equal(t, globexUser.ID, users[0].ID)
+
+	listed, total, err := userRepository.List(globex, rest.Query{PaginationMeta: rest.PaginationMeta{Page: 1, PerPage: 10}})
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	require.Len(t, listed, 1)
+	assert.Equal(t, globexUser.ID, listed[0].ID)
+
+	acmeUser.Name = "Renamed"
+	_, err = userRepository.Update(globex, acmeUser)
+	assert.ErrorIs(t, err, postgres.ErrUserNotFound)
+	assert.ErrorIs(t, userRepository.Delete(globex, acmeUser.ID), postgres.ErrUserNotFound)
+
+	t.Cleanup(func() {
+		fmt.Println("🧹 Cleaning up after test...")
+		utils.TruncateTables(t)
+	})
+}
@@ -0,0 +1,357 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/lib/pq"
+	"task-manager/internal/entities"
+	"task-manager/internal/tenant"
+	"task-manager/pkg/db"
+	"task-manager/pkg/rest"
+)
+
+// -----------------------------------------------------------------------------
+// Errors
+// -----------------------------------------------------------------------------
+
+// ErrWebhookNotFound is returned when a webhook subscription with the given ID does not exist.
+var ErrWebhookNotFound = fmt.Errorf("webhook not found")
+
+// ErrWebhookDeliveryNotFound is returned when a delivery does not exist for the given subscription.
+var ErrWebhookDeliveryNotFound = fmt.Errorf("webhook delivery not found")
+
+// webhookSubscriptionColumns lists the columns mapped onto subscriptionRow.
+const webhookSubscriptionColumns = `id, tenant_id, url, secret, events, is_active, created_at, updated_at`
+
+// webhookDeliveryColumns lists the columns mapped onto entities.WebhookDelivery.
+const webhookDeliveryColumns = `id, subscription_id, event_id, event_type, payload, status, attempts,
+            next_attempt_at, last_error, response_status, delivered_at, created_at, updated_at`
+
+// -----------------------------------------------------------------------------
+// Interfaces
+// -----------------------------------------------------------------------------
+
+// WebhookRepository defines the operations on webhook subscriptions and their delivery queue.
+//
+// Subscriptions and their deliveries are scoped to the tenant of the context
+// (tenant.Of), except Enqueue, which serves the tenant of the event, and ClaimDue
+// and SaveAttempt, which serve every tenant.
+type WebhookRepository interface {
+	CreateSubscription(ctx context.Context, subscription *entities.WebhookSubscription) (*entities.WebhookSubscription, error)
+	UpdateSubscription(ctx context.Context, subscription *entities.WebhookSubscription) (*entities.WebhookSubscription, error)
+	GetSubscription(ctx context.Context, id int64) (*entities.WebhookSubscription, error)
+	ListSubscriptions(ctx context.Context, query rest.Query) ([]entities.WebhookSubscription, int, error)
+	DeleteSubscription(ctx context.Context, id int64) error
+
+	Enqueue(ctx context.Context, eventID string, eventType entities.TaskEventType, tenantID string, payload []byte) (int, error)
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]entities.WebhookDelivery, error)
+	SaveAttempt(ctx context.Context, delivery *entities.WebhookDelivery) error
+	ListDeliveries(ctx context.Context, subscriptionID int64, query rest.Query) ([]entities.WebhookDelivery, int, error)
+	Requeue(ctx context.Context, subscriptionID, deliveryID int64, now time.Time) (*entities.WebhookDelivery, error)
+}
+
+// -----------------------------------------------------------------------------
+// Repository implementation
+// -----------------------------------------------------------------------------
+
+// Webhook implements WebhookRepository using a SQL database.
+type Webhook struct {
+	db db.DB
+}
+
+// NewWebhookRepository returns a new Webhook repository.
+func NewWebhookRepository(db db.DB) *Webhook {
+	return &Webhook{db: db}
+}
+
+// subscriptionRow maps the events array of a subscription row.
+type subscriptionRow struct {
+	entities.WebhookSubscription
+	EventNames pq.StringArray `db:"events"`
+}
+
+// entity converts the row to a subscription entity.
+func (r *subscriptionRow) entity() *entities.WebhookSubscription {
+	s := r.WebhookSubscription
+	s.Events = make([]entities.TaskEventType, 0, len(r.EventNames))
+	for _, name := range r.EventNames {
+		s.Events = append(s.Events, entities.TaskEventType(name))
+	}
+	return &s
+}
+
+// eventNames converts subscribed event types to a Postgres text array.
+func eventNames(events []entities.TaskEventType) pq.StringArray {
+	names := make(pq.StringArray, 0, len(events))
+	for _, e := range events {
+		names = append(names, string(e))
+	}
+	return names
+}
+
+// -----------------------------------------------------------------------------
+// Subscriptions
+// -----------------------------------------------------------------------------
+
+// CreateSubscription inserts a new subscription and returns it with generated fields.
+// The subscription belongs to the tenant of ctx, whatever its TenantID.
+func (r *Webhook) CreateSubscription(ctx context.Context, s *entities.WebhookSubscription) (*entities.WebhookSubscription, error) {
+	query := `
+        INSERT INTO webhook_subscriptions (url, secret, events, is_active, tenant_id)
+        VALUES ($1, $2, $3, $4, $5)
+        RETURNING ` + webhookSubscriptionColumns
+
+	var row subscriptionRow
+	if err := r.db.GetContext(ctx, &row, query, s.URL, s.Secret, eventNames(s.Events), s.IsActive, tenant.Of(ctx)); err != nil {
+		return nil, err
+	}
+
+	return row.entity(), nil
+}
+
+// UpdateSubscription modifies the URL, event filter and active flag of a subscription.
+// The secret is never changed. Returns ErrWebhookNotFound if it does not exist.
+func (r *Webhook) UpdateSubscription(ctx context.Context, s *entities.WebhookSubscription) (*entities.WebhookSubscription, error) {
+	query := `
+        UPDATE webhook_subscriptions
+        SET url = $1,
+            events = $2,
+            is_active = $3,
+            updated_at = now()
+        WHERE id = $4 AND tenant_id = $5
+        RETURNING ` + webhookSubscriptionColumns
+
+	var row subscriptionRow
+	if err := r.db.GetContext(ctx, &row, query, s.URL, eventNames(s.Events), s.IsActive, s.ID, tenant.Of(ctx)); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrWebhookNotFound
+		}
+		return nil, err
+	}
+
+	return row.entity(), nil
+}
+
+// GetSubscription fetches a subscription by its ID.
+// Returns ErrWebhookNotFound if no rows are returned.
+func (r *Webhook) GetSubscription(ctx context.Context, id int64) (*entities.WebhookSubscription, error) {
+	query := `SELECT ` + webhookSubscriptionColumns + ` FROM webhook_subscriptions WHERE id = $1 AND tenant_id = $2`
+
+	var row subscriptionRow
+	if err := r.db.GetContext(ctx, &row, query, id, tenant.Of(ctx)); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrWebhookNotFound
+		}
+		return nil, err
+	}
+
+	return row.entity(), nil
+}
+
+// ListSubscriptions returns a page of subscriptions along with the total count.
+// Supports filtering by is_active.
+func (r *Webhook) ListSubscriptions(ctx context.Context, query rest.Query) ([]entities.WebhookSubscription, int, error) {
+	baseQuery := `SELECT ` + webhookSubscriptionColumns + ` FROM webhook_subscriptions`
+	countQuery := `SELECT COUNT(*) FROM webhook_subscriptions`
+
+	var (
+		args  = []interface{}{tenant.Of(ctx)}
+		where = " WHERE tenant_id = $1"
+		i     = 2
+	)
+
+	if value, ok := query.Filter["is_active"]; ok {
+		where += fmt.Sprintf(" AND is_active = $%d", i)
+		args = append(args, value)
+		i++
+	}
+
+	var total int
+	if err := r.db.GetContext(ctx, &total, countQuery+where, args...); err != nil {
+		return nil, 0, err
+	}
+
+	offset := (query.Page - 1) * query.PerPage
+	baseQuery += where + fmt.Sprintf(" ORDER BY id LIMIT $%d OFFSET $%d", i, i+1)
+	args = append(args, query.PerPage, offset)
+
+	var rows []subscriptionRow
+	if err := r.db.SelectContext(ctx, &rows, baseQuery, args...); err != nil {
+		return nil, 0, err
+	}
+
+	subscriptions := make([]entities.WebhookSubscription, 0, len(rows))
+	for i := range rows {
+		subscriptions = append(subscriptions, *rows[i].entity())
+	}
+
+	return subscriptions, total, nil
+}
+
+// DeleteSubscription removes a subscription and its delivery log.
+// Returns ErrWebhookNotFound if no record was deleted.
+func (r *Webhook) DeleteSubscription(ctx context.Context, id int64) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM webhook_subscriptions WHERE id = $1 AND tenant_id = $2`, id, tenant.Of(ctx))
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return ErrWebhookNotFound
+	}
+
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+// Delivery queue
+// -----------------------------------------------------------------------------
+
+// Enqueue queues the event for every active subscription of its tenant accepting its
+// type and returns the number of deliveries created. Enqueuing the same event twice
+// is a no-op.
+func (r *Webhook) Enqueue(ctx context.Context, eventID string, eventType entities.TaskEventType, tenantID string, payload []byte) (int, error) {
+	query := `
+        INSERT INTO webhook_deliveries (subscription_id, event_id, event_type, payload, tenant_id)
+        SELECT id, $1, $2, $3, tenant_id
+        FROM webhook_subscriptions
+        WHERE is_active AND (cardinality(events) = 0 OR $2 = ANY(events)) AND tenant_id = $4
+        ON CONFLICT (subscription_id, event_id) DO NOTHING
+    `
+
+	result, err := r.db.ExecContext(ctx, query, eventID, eventType, payload, tenantID)
+	if err != nil {
+		return 0, err
+	}
+
+	rows, err := result.RowsAffected()
+	return int(rows), err
+}
+
+// ClaimDue leases up to limit pending deliveries whose next attempt is due, together
+// with the URL and secret of their subscription. A claimed delivery is hidden from
+// other workers for the lease duration; if the worker dies it is retried afterwards.
+func (r *Webhook) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]entities.WebhookDelivery, error) {
+	query := `
+        WITH due AS (
+            SELECT id
+            FROM webhook_deliveries
+            WHERE status = 'pending' AND next_attempt_at <= $1
+            ORDER BY next_attempt_at
+            LIMIT $3
+            FOR UPDATE SKIP LOCKED
+        )
+        UPDATE webhook_deliveries d
+        SET next_attempt_at = $1::timestamptz + make_interval(secs => $2)
+        FROM webhook_subscriptions s
+        WHERE d.id IN (SELECT id FROM due) AND s.id = d.subscription_id
+        RETURNING d.id, d.subscription_id, d.event_id, d.event_type, d.payload, d.status, d.attempts,
+            d.next_attempt_at, d.last_error, d.response_status, d.delivered_at, d.created_at, d.updated_at,
+            s.url, s.secret
+    `
+
+	var deliveries []entities.WebhookDelivery
+	if err := r.db.SelectContext(ctx, &deliveries, query, now, lease.Seconds(), limit); err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+// SaveAttempt stores the outcome of a delivery attempt: status, attempts, next
+// attempt, last error, response status and delivery time.
+func (r *Webhook) SaveAttempt(ctx context.Context, d *entities.WebhookDelivery) error {
+	query := `
+        UPDATE webhook_deliveries
+        SET status = $1,
+            attempts = $2,
+            next_attempt_at = $3,
+            last_error = $4,
+            response_status = $5,
+            delivered_at = $6,
+            updated_at = now()
+        WHERE id = $7
+    `
+
+	_, err := r.db.ExecContext(ctx, query,
+		d.Status,
+		d.Attempts,
+		d.NextAttemptAt,
+		d.LastError,
+		d.ResponseStatus,
+		d.DeliveredAt,
+		d.ID,
+	)
+
+	return err
+}
+
+// ListDeliveries returns the delivery log of a subscription, newest first, along with
+// the total count. Supports filtering by status and event_type.
+func (r *Webhook) ListDeliveries(ctx context.Context, subscriptionID int64, query rest.Query) ([]entities.WebhookDelivery, int, error) {
+	conditions := []string{"subscription_id = $1", "tenant_id = $2"}
+	args := []interface{}{subscriptionID, tenant.Of(ctx)}
+	i := 3
+
+	for field, value := range query.Filter {
+		switch field {
+		case "status", "event_type":
+			conditions = append(conditions, fmt.Sprintf("%s = $%d", field, i))
+			args = append(args, value)
+			i++
+		}
+	}
+
+	where := " WHERE " + strings.Join(conditions, " AND ")
+
+	var total int
+	if err := r.db.GetContext(ctx, &total, `SELECT COUNT(*) FROM webhook_deliveries`+where, args...); err != nil {
+		return nil, 0, err
+	}
+
+	offset := (query.Page - 1) * query.PerPage
+	baseQuery := `SELECT ` + webhookDeliveryColumns + ` FROM webhook_deliveries` + where +
+		fmt.Sprintf(" ORDER BY id DESC LIMIT $%d OFFSET $%d", i, i+1)
+	args = append(args, query.PerPage, offset)
+
+	var deliveries []entities.WebhookDelivery
+	if err := r.db.SelectContext(ctx, &deliveries, baseQuery, args...); err != nil {
+		return nil, 0, err
+	}
+
+	return deliveries, total, nil
+}
+
+// Requeue schedules a delivery for immediate redelivery with a fresh attempt budget,
+// typically to replay a dead-lettered one.
+// Returns ErrWebhookDeliveryNotFound if it does not belong to the subscription.
+func (r *Webhook) Requeue(ctx context.Context, subscriptionID, deliveryID int64, now time.Time) (*entities.WebhookDelivery, error) {
+	query := `
+        UPDATE webhook_deliveries
+        SET status = 'pending',
+            attempts = 0,
+            next_attempt_at = $3,
+            updated_at = now()
+        WHERE id = $1 AND subscription_id = $2 AND tenant_id = $4
+        RETURNING ` + webhookDeliveryColumns
+
+	var delivery entities.WebhookDelivery
+	if err := r.db.GetContext(ctx, &delivery, query, deliveryID, subscriptionID, now, tenant.Of(ctx)); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrWebhookDeliveryNotFound
+		}
+		return nil, err
+	}
+
+	return &delivery, nil
+}
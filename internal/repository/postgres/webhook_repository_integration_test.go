@@ -0,0 +1,159 @@
+package postgres_test
+
+import (
+	"context"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"task-manager/internal/entities"
+	"task-manager/internal/repository"
+	"task-manager/internal/repository/postgres"
+	"task-manager/internal/tenant"
+	"task-manager/internal/utils"
+	"task-manager/pkg/rest"
+	"testing"
+	"time"
+)
+
+// TestWebhookDeliveryIntegration tests that events are queued once per matching
+// subscription, leased by ClaimDue and requeued after being dead-lettered.
+func TestWebhookDeliveryIntegration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	webhookRepository := repository.MakeNewWebhookRepository()
+
+	all, err := webhookRepository.CreateSubscription(ctx, &entities.WebhookSubscription{
+		URL: "https://example.com/all", Secret: "a", IsActive: true,
+	})
+	require.NoError(t, err)
+
+	deletedOnly, err := webhookRepository.CreateSubscription(ctx, &entities.WebhookSubscription{
+		URL: "https://example.com/deleted", Secret: "b", IsActive: true,
+		Events: []entities.TaskEventType{entities.TaskEventDeleted},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []entities.TaskEventType{entities.TaskEventDeleted}, deletedOnly.Events)
+
+	_, err = webhookRepository.CreateSubscription(ctx, &entities.WebhookSubscription{
+		URL: "https://example.com/inactive", Secret: "c", IsActive: false,
+	})
+	require.NoError(t, err)
+
+	payload := []byte(`{"id":"evt-1"}`)
+	queued, err := webhookRepository.Enqueue(ctx, "evt-1", entities.TaskEventCreated, tenant.Default, payload)
+	require.NoError(t, err)
+	assert.Equal(t, 1, queued)
+
+	// Enqueuing the same event again is a no-op
+	queued, err = webhookRepository.Enqueue(ctx, "evt-1", entities.TaskEventCreated, tenant.Default, payload)
+	require.NoError(t, err)
+	assert.Equal(t, 0, queued)
+
+	now := time.Now().UTC()
+	deliveries, err := webhookRepository.ClaimDue(ctx, now, time.Minute, 10)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, all.ID, deliveries[0].SubscriptionID)
+	assert.Equal(t, all.URL, deliveries[0].URL)
+	assert.Equal(t, all.Secret, deliveries[0].Secret)
+	assert.JSONEq(t, string(payload), string(deliveries[0].Payload))
+
+	// The lease hides the delivery from other workers
+	leased, err := webhookRepository.ClaimDue(ctx, now, time.Minute, 10)
+	require.NoError(t, err)
+	assert.Empty(t, leased)
+
+	delivery := deliveries[0]
+	delivery.Status = entities.WebhookDeliveryDead
+	delivery.Attempts = 8
+	delivery.LastError = "unexpected status 500"
+	require.NoError(t, webhookRepository.SaveAttempt(ctx, &delivery))
+
+	dead, total, err := webhookRepository.ListDeliveries(ctx, all.ID, rest.Query{
+		Filter:         rest.Filter{"status": string(entities.WebhookDeliveryDead)},
+		PaginationMeta: rest.PaginationMeta{Page: 1, PerPage: 10},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	require.Len(t, dead, 1)
+	assert.Equal(t, 8, dead[0].Attempts)
+
+	_, err = webhookRepository.Requeue(ctx, deletedOnly.ID, delivery.ID, now)
+	assert.ErrorIs(t, err, postgres.ErrWebhookDeliveryNotFound)
+
+	requeued, err := webhookRepository.Requeue(ctx, all.ID, delivery.ID, now)
+	require.NoError(t, err)
+	assert.Equal(t, entities.WebhookDeliveryPending, requeued.Status)
+	assert.Zero(t, requeued.Attempts)
+
+	deliveries, err = webhookRepository.ClaimDue(ctx, now.Add(time.Second), time.Minute, 10)
+	require.NoError(t, err)
+	assert.Len(t, deliveries, 1)
+
+	t.Cleanup(func() {
+		fmt.Println("🧹 Cleaning up after test...")
+		utils.TruncateTables(t)
+	})
+}
+
+// TestWebhookTenantIsolationIntegration tests that subscriptions only receive the
+// events of their tenant and are hidden from the other tenants.
+func TestWebhookTenantIsolationIntegration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	acme := tenant.WithID(context.Background(), "acme")
+	globex := tenant.WithID(context.Background(), "globex")
+	webhookRepository := repository.MakeNewWebhookRepository()
+
+	acmeSubscription, err := webhookRepository.CreateSubscription(acme, &entities.WebhookSubscription{
+		URL: "https://acme.example.com/hook", Secret: "a", IsActive: true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "acme", acmeSubscription.TenantID)
+
+	globexSubscription, err := webhookRepository.CreateSubscription(globex, &entities.WebhookSubscription{
+		URL: "https://globex.example.com/hook", Secret: "b", IsActive: true,
+	})
+	require.NoError(t, err)
+
+	// An acme event is only queued for the acme subscription
+	queued, err := webhookRepository.Enqueue(context.Background(), "evt-acme", entities.TaskEventCreated, "acme", []byte(`{}`))
+	require.NoError(t, err)
+	assert.Equal(t, 1, queued)
+
+	deliveries, err := webhookRepository.ClaimDue(context.Background(), time.Now().UTC(), time.Minute, 10)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, acmeSubscription.ID, deliveries[0].SubscriptionID)
+
+	page := rest.Query{PaginationMeta: rest.PaginationMeta{Page: 1, PerPage: 10}}
+	globexDeliveries, total, err := webhookRepository.ListDeliveries(globex, globexSubscription.ID, page)
+	require.NoError(t, err)
+	assert.Zero(t, total)
+	assert.Empty(t, globexDeliveries)
+
+	// globex can neither see nor change the acme subscription and its deliveries
+	_, err = webhookRepository.GetSubscription(globex, acmeSubscription.ID)
+	assert.ErrorIs(t, err, postgres.ErrWebhookNotFound)
+
+	subscriptions, total, err := webhookRepository.ListSubscriptions(globex, page)
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	require.Len(t, subscriptions, 1)
+	assert.Equal(t, globexSubscription.ID, subscriptions[0].ID)
+
+	_, err = webhookRepository.Requeue(globex, acmeSubscription.ID, deliveries[0].ID, time.Now().UTC())
+	assert.ErrorIs(t, err, postgres.ErrWebhookDeliveryNotFound)
+
+	assert.ErrorIs(t, webhookRepository.DeleteSubscription(globex, acmeSubscription.ID), postgres.ErrWebhookNotFound)
+
+	t.Cleanup(func() {
+		fmt.Println("🧹 Cleaning up after test...")
+		utils.TruncateTables(t)
+	})
+}
@@ -15,12 +15,60 @@ func MakeNewTaskRepository() postgres.TaskRepository {
 	return postgres.NewTaskRepository(utils.CreateTestDatabaseConnection())
 }
 
+// MakeNewUserRepository initializes a new UserRepository using the test database connection.
+func MakeNewUserRepository() postgres.UserRepository {
+	return postgres.NewUserRepository(utils.CreateTestDatabaseConnection())
+}
+
+// MakeNewReminderRepository initializes a new ReminderRepository using the test database connection.
+func MakeNewReminderRepository() postgres.ReminderRepository {
+	return postgres.NewReminderRepository(utils.CreateTestDatabaseConnection())
+}
+
+// MakeNewWebhookRepository initializes a new WebhookRepository using the test database connection.
+func MakeNewWebhookRepository() postgres.WebhookRepository {
+	return postgres.NewWebhookRepository(utils.CreateTestDatabaseConnection())
+}
+
+// MakeNewOutboxRepository initializes a new OutboxRepository using the test database connection.
+func MakeNewOutboxRepository() postgres.OutboxRepository {
+	return postgres.NewOutboxRepository(utils.CreateTestDatabaseConnection())
+}
+
+// MakeNewAPIKeyRepository initializes a new APIKeyRepository using the test database connection.
+func MakeNewAPIKeyRepository() postgres.APIKeyRepository {
+	return postgres.NewAPIKeyRepository(utils.CreateTestDatabaseConnection())
+}
+
+// MakeNewIdempotencyRepository initializes a new IdempotencyRepository using the test database connection.
+func MakeNewIdempotencyRepository() postgres.IdempotencyRepository {
+	return postgres.NewIdempotencyRepository(utils.CreateTestDatabaseConnection())
+}
+
+// MakeNewTransactor initializes a new Transactor using the test database connection.
+func MakeNewTransactor() postgres.Transactor {
+	return postgres.NewTransactor(utils.CreateTestDatabaseConnection())
+}
+
+// CreateTestUser generates a random active user and saves it to the test database.
+// Returns the created User entity. Panics if creation fails.
+func CreateTestUser() *entities.User {
+	user, err := MakeNewUserRepository().Create(context.Background(), RandomUser())
+	if err != nil {
+		panic(err)
+	}
+
+	return user
+}
+
 // CreateTestTask generates a random task and saves it to the test database.
+// The task is assigned to a freshly created user to satisfy the foreign key.
 // Returns the created Task entity. Panics if creation fails.
 func CreateTestTask() *entities.Task {
 	repo := MakeNewTaskRepository()
 
 	task := RandomTask()
+	task.AssigneeID = CreateTestUser().ID
 	createdTask, err := repo.Create(context.Background(), task)
 	if err != nil {
 		panic(err)
@@ -49,3 +97,14 @@ func RandomTask() *entities.Task {
 		UpdatedAt:   time.Now(),
 	}
 }
+
+// RandomUser generates a new active User entity with randomized name and email.
+func RandomUser() *entities.User {
+	return &entities.User{
+		Name:      gofakeit.Name(),
+		Email:     gofakeit.Email(),
+		IsActive:  true,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+}
@@ -0,0 +1,272 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"slices"
+	"strconv"
+	"sync"
+	"task-manager/internal/auth"
+	"task-manager/internal/entities"
+	"task-manager/internal/repository/postgres"
+	"task-manager/pkg/rest"
+	"time"
+
+	"github.com/cockroachdb/errors"
+)
+
+// Defaults applied to zero APIKeySettings fields.
+const (
+	DefaultAPIKeyCacheTTL      = time.Minute
+	DefaultAPIKeyTouchInterval = time.Minute
+)
+
+// ErrInvalidAPIKeyScope is returned when an API key is issued without scopes or with an unknown one.
+var ErrInvalidAPIKeyScope = errors.New("api key scopes must be one or more of " + fmt.Sprint(auth.Scopes))
+
+// ErrInvalidAPIKeyExpiry is returned when an API key is issued with an expiry in the past.
+var ErrInvalidAPIKeyExpiry = errors.New("api key expiry must be in the future")
+
+// ErrAPIKeyRevoked is returned when rotating a revoked API key.
+var ErrAPIKeyRevoked = errors.New("api key is revoked")
+
+// APIKeySettings
+//
+// Configures the verification of API keys.
+type APIKeySettings struct {
+	CacheTTL      time.Duration // How long a verified key is trusted before it is checked against the database again
+	TouchInterval time.Duration // Minimum time between two last-used updates of a key
+}
+
+// APIKeyService
+//
+// Interface defining the management of API keys. It authenticates the keys of the
+// ApiKey Authorization scheme as well.
+type APIKeyService interface {
+	auth.Authenticator
+
+	Issue(ctx context.Context, key *entities.APIKey) (*entities.APIKey, string, error)
+	Get(ctx context.Context, id int64) (*entities.APIKey, error)
+	List(ctx context.Context, query rest.Query) ([]entities.APIKey, int, error)
+	Revoke(ctx context.Context, id int64) (*entities.APIKey, error)
+	Rotate(ctx context.Context, id int64) (*entities.APIKey, string, error)
+}
+
+// APIKey
+//
+// Concrete implementation of APIKeyService. Keys are stored as a public prefix and
+// the bcrypt hash of their secret. As bcrypt is deliberately slow, verified keys are
+// remembered for CacheTTL: a key revoked or rotated on another instance keeps working
+// there for at most that long.
+type APIKey struct {
+	apiKeyRepo postgres.APIKeyRepository
+	settings   APIKeySettings
+
+	mu       sync.Mutex
+	verified map[string]verifiedAPIKey // By prefix
+}
+
+// verifiedAPIKey is a key whose secret matched its hash.
+type verifiedAPIKey struct {
+	digest     [sha256.Size]byte // Digest of the full key that was verified
+	key        *entities.APIKey
+	verifiedAt time.Time
+}
+
+// NewAPIKeyService
+//
+// Constructs a new APIKeyService. Zero settings fall back to the defaults.
+func NewAPIKeyService(apiKeyRepo postgres.APIKeyRepository, settings APIKeySettings) APIKeyService {
+	if settings.CacheTTL <= 0 {
+		settings.CacheTTL = DefaultAPIKeyCacheTTL
+	}
+	if settings.TouchInterval <= 0 {
+		settings.TouchInterval = DefaultAPIKeyTouchInterval
+	}
+
+	return &APIKey{
+		apiKeyRepo: apiKeyRepo,
+		settings:   settings,
+		verified:   make(map[string]verifiedAPIKey),
+	}
+}
+
+// Issue
+//
+// Validates and stores a new API key, acting in the tenant of ctx. It returns the
+// stored key and the full key, which is not stored and can't be retrieved afterwards.
+func (a *APIKey) Issue(ctx context.Context, key *entities.APIKey) (*entities.APIKey, string, error) {
+	if len(key.Scopes) == 0 {
+		return nil, "", ErrInvalidAPIKeyScope
+	}
+	for _, scope := range key.Scopes {
+		if !slices.Contains(auth.Scopes, scope) {
+			return nil, "", errors.Wrapf(ErrInvalidAPIKeyScope, "unknown scope %q", scope)
+		}
+	}
+	if key.ExpiresAt != nil && !key.ExpiresAt.After(time.Now()) {
+		return nil, "", ErrInvalidAPIKeyExpiry
+	}
+
+	generated, hash, err := generateAPIKey()
+	if err != nil {
+		return nil, "", err
+	}
+	key.Prefix = generated.Prefix
+	key.SecretHash = hash
+
+	created, err := a.apiKeyRepo.Create(ctx, key)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return created, generated.Key, nil
+}
+
+// Get
+//
+// Fetches an API key by its ID.
+func (a *APIKey) Get(ctx context.Context, id int64) (*entities.APIKey, error) {
+	return a.apiKeyRepo.GetByID(ctx, id)
+}
+
+// List
+//
+// Fetches a paginated list of API keys.
+func (a *APIKey) List(ctx context.Context, query rest.Query) ([]entities.APIKey, int, error) {
+	return a.apiKeyRepo.List(ctx, query)
+}
+
+// Revoke
+//
+// Revokes an API key for good. It stops working on this instance right away.
+func (a *APIKey) Revoke(ctx context.Context, id int64) (*entities.APIKey, error) {
+	revoked, err := a.apiKeyRepo.Revoke(ctx, id, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	a.forget(revoked.ID)
+	return revoked, nil
+}
+
+// Rotate
+//
+// Replaces the secret and prefix of an active API key, keeping its name, scopes and
+// expiry. It returns the updated key and the new full key; the previous one stops
+// working on this instance right away.
+func (a *APIKey) Rotate(ctx context.Context, id int64) (*entities.APIKey, string, error) {
+	current, err := a.apiKeyRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, "", err
+	}
+	if current.RevokedAt != nil {
+		return nil, "", ErrAPIKeyRevoked
+	}
+
+	generated, hash, err := generateAPIKey()
+	if err != nil {
+		return nil, "", err
+	}
+
+	rotated, err := a.apiKeyRepo.Rotate(ctx, id, generated.Prefix, hash)
+	if err != nil {
+		return nil, "", err
+	}
+
+	a.forget(id)
+	return rotated, generated.Key, nil
+}
+
+// Scheme implements auth.Authenticator.
+func (a *APIKey) Scheme() string {
+	return "ApiKey"
+}
+
+// Authenticate implements auth.Authenticator. The key is looked up by its prefix and
+// its secret checked against the stored hash; revoked and expired keys are rejected.
+func (a *APIKey) Authenticate(ctx context.Context, credentials string) (*auth.Principal, error) {
+	parsed, err := auth.ParseAPIKey(credentials)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	digest := sha256.Sum256([]byte(parsed.Key))
+
+	a.mu.Lock()
+	cached, ok := a.verified[parsed.Prefix]
+	a.mu.Unlock()
+
+	key := cached.key
+	if !ok || cached.digest != digest || now.Sub(cached.verifiedAt) > a.settings.CacheTTL {
+		key, err = a.apiKeyRepo.GetByPrefix(ctx, parsed.Prefix)
+		if errors.Is(err, postgres.ErrAPIKeyNotFound) {
+			return nil, fmt.Errorf("%w: unknown API key", auth.ErrInvalidCredentials)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !auth.VerifyAPIKeySecret(key.SecretHash, parsed.Secret) {
+			return nil, fmt.Errorf("%w: API key does not match", auth.ErrInvalidCredentials)
+		}
+		cached = verifiedAPIKey{digest: digest, key: key, verifiedAt: now}
+	}
+
+	if !key.Active(now) {
+		a.forget(key.ID)
+		return nil, fmt.Errorf("%w: API key is revoked or expired", auth.ErrInvalidCredentials)
+	}
+
+	// Last use is only tracked approximately, a write per request would be too costly.
+	// A failed update does not reject the key, it is retried on the next request.
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= a.settings.TouchInterval {
+		if err := a.apiKeyRepo.TouchLastUsed(ctx, key.ID, now); err == nil {
+			touched := *key
+			touched.LastUsedAt = &now
+			cached.key = &touched
+		}
+	}
+
+	a.mu.Lock()
+	a.verified[parsed.Prefix] = cached
+	a.mu.Unlock()
+
+	return &auth.Principal{
+		Subject:  "api-key:" + strconv.FormatInt(key.ID, 10),
+		Name:     key.Name,
+		Scopes:   key.Scopes,
+		Method:   auth.MethodAPIKey,
+		TenantID: key.TenantID,
+	}, nil
+}
+
+// forget drops the verified key with the given ID, if any.
+func (a *APIKey) forget(id int64) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for prefix, cached := range a.verified {
+		if cached.key.ID == id {
+			delete(a.verified, prefix)
+		}
+	}
+}
+
+// generateAPIKey
+//
+// Generates a new API key and the hash of its secret.
+func generateAPIKey() (auth.APIKey, string, error) {
+	key, err := auth.GenerateAPIKey()
+	if err != nil {
+		return auth.APIKey{}, "", err
+	}
+
+	hash, err := auth.HashAPIKeySecret(key.Secret)
+	if err != nil {
+		return auth.APIKey{}, "", err
+	}
+
+	return key, hash, nil
+}
@@ -0,0 +1,123 @@
+package service_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"task-manager/internal/auth"
+	"task-manager/internal/entities"
+	repoMock "task-manager/internal/repository/mock"
+	"task-manager/internal/repository/postgres"
+	"task-manager/internal/service"
+	"testing"
+	"time"
+)
+
+// issueAPIKey issues a key through svc and returns the stored entity and the full key.
+func issueAPIKey(t *testing.T, svc service.APIKeyService, repo *repoMock.MockAPIKeyRepository, key entities.APIKey) (*entities.APIKey, string) {
+	t.Helper()
+
+	var stored *entities.APIKey
+	repo.On("Create", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			k := *args.Get(1).(*entities.APIKey)
+			k.ID = 1
+			stored = &k
+		}).
+		Return(&entities.APIKey{ID: 1}, nil).
+		Once()
+
+	_, full, err := svc.Issue(context.Background(), &key)
+	require.NoError(t, err)
+	return stored, full
+}
+
+// TestAPIKeyIssue_ShouldStoreHashOnly tests that the secret of an issued key is only stored hashed.
+func TestAPIKeyIssue_ShouldStoreHashOnly(t *testing.T) {
+	repo := &repoMock.MockAPIKeyRepository{}
+	svc := service.NewAPIKeyService(repo, service.APIKeySettings{})
+
+	stored, full := issueAPIKey(t, svc, repo, entities.APIKey{Name: "backup", Scopes: []string{auth.ScopeTasksRead}})
+
+	parsed, err := auth.ParseAPIKey(full)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(full, auth.APIKeyPrefix+stored.Prefix+"_"))
+	assert.NotContains(t, stored.SecretHash, parsed.Secret)
+	assert.True(t, auth.VerifyAPIKeySecret(stored.SecretHash, parsed.Secret))
+}
+
+// TestAPIKeyIssue_Invalid_ShouldFail tests the validation of the scopes and expiry.
+func TestAPIKeyIssue_Invalid_ShouldFail(t *testing.T) {
+	svc := service.NewAPIKeyService(&repoMock.MockAPIKeyRepository{}, service.APIKeySettings{})
+	past := time.Now().Add(-time.Minute)
+
+	_, _, err := svc.Issue(context.Background(), &entities.APIKey{Name: "backup"})
+	assert.ErrorIs(t, err, service.ErrInvalidAPIKeyScope)
+
+	_, _, err = svc.Issue(context.Background(), &entities.APIKey{Name: "backup", Scopes: []string{"tasks:delete"}})
+	assert.ErrorIs(t, err, service.ErrInvalidAPIKeyScope)
+
+	_, _, err = svc.Issue(context.Background(), &entities.APIKey{Name: "backup", Scopes: []string{auth.ScopeTasksRead}, ExpiresAt: &past})
+	assert.ErrorIs(t, err, service.ErrInvalidAPIKeyExpiry)
+}
+
+// TestAPIKeyAuthenticate_ShouldVerifyOnceAndTrackLastUse tests that a key is verified
+// against its hash once, then served from the cache until it is revoked.
+func TestAPIKeyAuthenticate_ShouldVerifyOnceAndTrackLastUse(t *testing.T) {
+	repo := &repoMock.MockAPIKeyRepository{}
+	svc := service.NewAPIKeyService(repo, service.APIKeySettings{})
+	stored, full := issueAPIKey(t, svc, repo, entities.APIKey{Name: "backup", Scopes: []string{auth.ScopeTasksRead}})
+
+	repo.On("GetByPrefix", mock.Anything, stored.Prefix).Return(stored, nil)
+	repo.On("TouchLastUsed", mock.Anything, stored.ID, mock.Anything).Return(nil).Once()
+
+	for range 3 {
+		principal, err := svc.Authenticate(context.Background(), full)
+		require.NoError(t, err)
+		assert.Equal(t, &auth.Principal{
+			Subject: "api-key:1", Name: "backup", Scopes: []string{auth.ScopeTasksRead}, Method: auth.MethodAPIKey,
+		}, principal)
+	}
+	repo.AssertNumberOfCalls(t, "GetByPrefix", 1)
+
+	_, err := svc.Authenticate(context.Background(), auth.APIKeyPrefix+stored.Prefix+"_forged")
+	assert.ErrorIs(t, err, auth.ErrInvalidCredentials)
+
+	revoked := *stored
+	revokedAt := time.Now()
+	revoked.RevokedAt = &revokedAt
+	repo.On("Revoke", mock.Anything, stored.ID, mock.Anything).Return(&revoked, nil)
+	_, err = svc.Revoke(context.Background(), stored.ID)
+	require.NoError(t, err)
+
+	// The revocation drops the cached key, so the next request sees it
+	repo.ExpectedCalls = repo.ExpectedCalls[:0]
+	repo.On("GetByPrefix", mock.Anything, stored.Prefix).Return(&revoked, nil)
+	_, err = svc.Authenticate(context.Background(), full)
+	assert.ErrorIs(t, err, auth.ErrInvalidCredentials)
+
+	repo.AssertExpectations(t)
+}
+
+// TestAPIKeyAuthenticate_Rejected_ShouldFail tests malformed, unknown and expired keys.
+func TestAPIKeyAuthenticate_Rejected_ShouldFail(t *testing.T) {
+	repo := &repoMock.MockAPIKeyRepository{}
+	svc := service.NewAPIKeyService(repo, service.APIKeySettings{})
+
+	expiresAt := time.Now().Add(time.Hour)
+	stored, full := issueAPIKey(t, svc, repo, entities.APIKey{Name: "backup", Scopes: []string{auth.ScopeTasksRead}, ExpiresAt: &expiresAt})
+	expired := time.Now().Add(-time.Second)
+	stored.ExpiresAt = &expired
+
+	repo.On("GetByPrefix", mock.Anything, stored.Prefix).Return(stored, nil)
+	repo.On("GetByPrefix", mock.Anything, "ffffffffffff").Return(nil, postgres.ErrAPIKeyNotFound)
+
+	for _, key := range []string{"not-a-key", "tm_ffffffffffff_s3cret", full} {
+		principal, err := svc.Authenticate(context.Background(), key)
+
+		assert.Nil(t, principal, key)
+		assert.ErrorIs(t, err, auth.ErrInvalidCredentials, key)
+	}
+}
@@ -3,8 +3,10 @@ package service
 import (
 	"context"
 	"github.com/stretchr/testify/mock"
+	"task-manager/internal/auth"
 	"task-manager/internal/entities"
 	"task-manager/pkg/rest"
+	"time"
 )
 
 // MockTaskService
@@ -69,3 +71,192 @@ func (m *MockTaskService) ListByStatus(ctx context.Context, status entities.Task
 	args := m.Called(ctx, status)
 	return args.Get(0).([]entities.Task), args.Error(1)
 }
+
+// AddAssignee mocks TaskService.AddAssignee
+//
+// Returns the task with the new co-assignee or an error as configured in tests.
+func (m *MockTaskService) AddAssignee(ctx context.Context, taskID, userID int64) (*entities.Task, error) {
+	args := m.Called(ctx, taskID, userID)
+	return args.Get(0).(*entities.Task), args.Error(1)
+}
+
+// RemoveAssignee mocks TaskService.RemoveAssignee
+func (m *MockTaskService) RemoveAssignee(ctx context.Context, taskID, userID int64) error {
+	args := m.Called(ctx, taskID, userID)
+	return args.Error(0)
+}
+
+// AddWatcher mocks TaskService.AddWatcher
+//
+// Returns the task with the new watcher or an error as configured in tests.
+func (m *MockTaskService) AddWatcher(ctx context.Context, taskID, userID int64) (*entities.Task, error) {
+	args := m.Called(ctx, taskID, userID)
+	return args.Get(0).(*entities.Task), args.Error(1)
+}
+
+// RemoveWatcher mocks TaskService.RemoveWatcher
+func (m *MockTaskService) RemoveWatcher(ctx context.Context, taskID, userID int64) error {
+	args := m.Called(ctx, taskID, userID)
+	return args.Error(0)
+}
+
+// MockUserService
+//
+// A testify-based mock implementation of the UserService interface.
+// Used by handler tests for the user endpoints and assignee embedding.
+type MockUserService struct {
+	mock.Mock
+}
+
+// Create mocks UserService.Create
+func (m *MockUserService) Create(ctx context.Context, user *entities.User) (*entities.User, error) {
+	args := m.Called(ctx, user)
+	return args.Get(0).(*entities.User), args.Error(1)
+}
+
+// Update mocks UserService.Update
+func (m *MockUserService) Update(ctx context.Context, user *entities.User) (*entities.User, error) {
+	args := m.Called(ctx, user)
+	return args.Get(0).(*entities.User), args.Error(1)
+}
+
+// Delete mocks UserService.Delete
+func (m *MockUserService) Delete(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+// GetByID mocks UserService.GetByID
+func (m *MockUserService) GetByID(ctx context.Context, id int64) (*entities.User, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*entities.User), args.Error(1)
+}
+
+// GetByIDs mocks UserService.GetByIDs
+func (m *MockUserService) GetByIDs(ctx context.Context, ids []int64) ([]entities.User, error) {
+	args := m.Called(ctx, ids)
+	return args.Get(0).([]entities.User), args.Error(1)
+}
+
+// List mocks UserService.List
+func (m *MockUserService) List(ctx context.Context, query rest.Query) ([]entities.User, int, error) {
+	args := m.Called(ctx, query)
+	return args.Get(0).([]entities.User), args.Int(1), args.Error(2)
+}
+
+// MockWebhookService
+//
+// A testify-based mock implementation of the WebhookService interface.
+// Used by handler tests for the webhook endpoints.
+type MockWebhookService struct {
+	mock.Mock
+}
+
+// CreateSubscription mocks WebhookService.CreateSubscription
+func (m *MockWebhookService) CreateSubscription(ctx context.Context, subscription *entities.WebhookSubscription) (*entities.WebhookSubscription, error) {
+	args := m.Called(ctx, subscription)
+	return args.Get(0).(*entities.WebhookSubscription), args.Error(1)
+}
+
+// UpdateSubscription mocks WebhookService.UpdateSubscription
+func (m *MockWebhookService) UpdateSubscription(ctx context.Context, subscription *entities.WebhookSubscription) (*entities.WebhookSubscription, error) {
+	args := m.Called(ctx, subscription)
+	return args.Get(0).(*entities.WebhookSubscription), args.Error(1)
+}
+
+// GetSubscription mocks WebhookService.GetSubscription
+func (m *MockWebhookService) GetSubscription(ctx context.Context, id int64) (*entities.WebhookSubscription, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*entities.WebhookSubscription), args.Error(1)
+}
+
+// ListSubscriptions mocks WebhookService.ListSubscriptions
+func (m *MockWebhookService) ListSubscriptions(ctx context.Context, query rest.Query) ([]entities.WebhookSubscription, int, error) {
+	args := m.Called(ctx, query)
+	return args.Get(0).([]entities.WebhookSubscription), args.Int(1), args.Error(2)
+}
+
+// DeleteSubscription mocks WebhookService.DeleteSubscription
+func (m *MockWebhookService) DeleteSubscription(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+// ListDeliveries mocks WebhookService.ListDeliveries
+func (m *MockWebhookService) ListDeliveries(ctx context.Context, subscriptionID int64, query rest.Query) ([]entities.WebhookDelivery, int, error) {
+	args := m.Called(ctx, subscriptionID, query)
+	return args.Get(0).([]entities.WebhookDelivery), args.Int(1), args.Error(2)
+}
+
+// RetryDelivery mocks WebhookService.RetryDelivery
+func (m *MockWebhookService) RetryDelivery(ctx context.Context, subscriptionID, deliveryID int64) (*entities.WebhookDelivery, error) {
+	args := m.Called(ctx, subscriptionID, deliveryID)
+	return args.Get(0).(*entities.WebhookDelivery), args.Error(1)
+}
+
+// Publish mocks WebhookService.Publish
+func (m *MockWebhookService) Publish(ctx context.Context, event entities.TaskEvent) error {
+	args := m.Called(ctx, event)
+	return args.Error(0)
+}
+
+// DeliverDue mocks WebhookService.DeliverDue
+func (m *MockWebhookService) DeliverDue(ctx context.Context, now time.Time) (int, error) {
+	args := m.Called(ctx, now)
+	return args.Int(0), args.Error(1)
+}
+
+// MockAPIKeyService
+//
+// A testify-based mock implementation of the APIKeyService interface.
+// Used by handler tests for the API key endpoints and the ApiKey scheme.
+type MockAPIKeyService struct {
+	mock.Mock
+}
+
+// Scheme mocks APIKeyService.Scheme
+func (m *MockAPIKeyService) Scheme() string {
+	return "ApiKey"
+}
+
+// Authenticate mocks APIKeyService.Authenticate
+func (m *MockAPIKeyService) Authenticate(ctx context.Context, credentials string) (*auth.Principal, error) {
+	args := m.Called(ctx, credentials)
+
+	var p *auth.Principal
+	if args.Get(0) != nil {
+		p = args.Get(0).(*auth.Principal)
+	}
+
+	return p, args.Error(1)
+}
+
+// Issue mocks APIKeyService.Issue
+func (m *MockAPIKeyService) Issue(ctx context.Context, key *entities.APIKey) (*entities.APIKey, string, error) {
+	args := m.Called(ctx, key)
+	return args.Get(0).(*entities.APIKey), args.String(1), args.Error(2)
+}
+
+// Get mocks APIKeyService.Get
+func (m *MockAPIKeyService) Get(ctx context.Context, id int64) (*entities.APIKey, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*entities.APIKey), args.Error(1)
+}
+
+// List mocks APIKeyService.List
+func (m *MockAPIKeyService) List(ctx context.Context, query rest.Query) ([]entities.APIKey, int, error) {
+	args := m.Called(ctx, query)
+	return args.Get(0).([]entities.APIKey), args.Int(1), args.Error(2)
+}
+
+// Revoke mocks APIKeyService.Revoke
+func (m *MockAPIKeyService) Revoke(ctx context.Context, id int64) (*entities.APIKey, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*entities.APIKey), args.Error(1)
+}
+
+// Rotate mocks APIKeyService.Rotate
+func (m *MockAPIKeyService) Rotate(ctx context.Context, id int64) (*entities.APIKey, string, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*entities.APIKey), args.String(1), args.Error(2)
+}
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"task-manager/internal/entities"
+	"task-manager/internal/events"
+	"task-manager/internal/repository/postgres"
+	"task-manager/pkg/retry"
+	"time"
+
+	"github.com/cockroachdb/errors"
+)
+
+// Defaults applied to zero OutboxSettings fields.
+const (
+	DefaultOutboxBatchSize   = 100
+	DefaultOutboxLease       = time.Minute
+	DefaultOutboxBaseBackoff = time.Second
+	DefaultOutboxMaxBackoff  = 5 * time.Minute
+	DefaultOutboxRetention   = 7 * 24 * time.Hour
+)
+
+// OutboxSettings
+//
+// Configures the outbox relay.
+type OutboxSettings struct {
+	BatchSize   int           // Max events relayed per run
+	Lease       time.Duration // Time a claimed batch is hidden from other relays
+	BaseBackoff time.Duration // Delay before relaying a failed event again, doubled on every retry
+	MaxBackoff  time.Duration // Upper bound of the retry delay
+	Retention   time.Duration // Published events are purged after this long
+}
+
+// OutboxService
+//
+// Interface defining the operations used by the outbox relay.
+type OutboxService interface {
+	Relay(ctx context.Context, now time.Time) (int, error)
+}
+
+// Outbox
+//
+// Concrete implementation of OutboxService. Relays the task events written by
+// TaskService to the event sink with at-least-once delivery, preserving the order
+// of the events of each task: a failing event holds back the later events of its
+// task until it is published.
+type Outbox struct {
+	outboxRepo postgres.OutboxRepository
+	sink       events.Sink
+	settings   OutboxSettings
+}
+
+// NewOutboxService
+//
+// Constructs a new OutboxService relaying to the given sink. Zero settings fall
+// back to the defaults.
+func NewOutboxService(outboxRepo postgres.OutboxRepository, sink events.Sink, settings OutboxSettings) OutboxService {
+	if settings.BatchSize <= 0 {
+		settings.BatchSize = DefaultOutboxBatchSize
+	}
+	if settings.Lease <= 0 {
+		settings.Lease = DefaultOutboxLease
+	}
+	if settings.BaseBackoff <= 0 {
+		settings.BaseBackoff = DefaultOutboxBaseBackoff
+	}
+	if settings.MaxBackoff <= 0 {
+		settings.MaxBackoff = DefaultOutboxMaxBackoff
+	}
+	if settings.Retention <= 0 {
+		settings.Retention = DefaultOutboxRetention
+	}
+
+	return &Outbox{
+		outboxRepo: outboxRepo,
+		sink:       sink,
+		settings:   settings,
+	}
+}
+
+// Relay
+//
+// Publishes the due outbox events in order and returns the number of published
+// ones. Failed events are retried with exponential backoff. Published events older
+// than the retention are purged afterwards.
+func (o *Outbox) Relay(ctx context.Context, now time.Time) (int, error) {
+	claimed, err := o.outboxRepo.ClaimDue(ctx, now, o.settings.Lease, o.settings.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		published int
+		blocked   = map[int64]bool{}
+		errs      []error
+	)
+
+	for i := range claimed {
+		event := &claimed[i]
+		if ctx.Err() != nil {
+			// Unfinished events are claimed again once their lease expires
+			break
+		}
+
+		// Keep the order: later events wait until the failed one is published
+		if blocked[event.TaskID] {
+			continue
+		}
+
+		if err := o.publish(ctx, event); err != nil {
+			blocked[event.TaskID] = true
+			errs = append(errs, err)
+			continue
+		}
+		published++
+	}
+
+	if _, err := o.outboxRepo.Purge(ctx, now.Add(-o.settings.Retention), o.settings.BatchSize); err != nil {
+		errs = append(errs, errors.Wrap(err, "purge outbox"))
+	}
+
+	return published, errors.Join(errs...)
+}
+
+// publish
+//
+// Hands a single event to the sink and records the outcome.
+func (o *Outbox) publish(ctx context.Context, event *entities.OutboxEvent) error {
+	sinkErr := o.sink.Publish(ctx, event.TaskEvent)
+	if sinkErr == nil {
+		return o.outboxRepo.MarkPublished(ctx, event.Sequence, time.Now())
+	}
+
+	event.Attempts++
+	event.LastError = sinkErr.Error()
+	event.NextAttemptAt = time.Now().Add(retry.Backoff(event.Attempts, o.settings.BaseBackoff, o.settings.MaxBackoff))
+
+	if err := o.outboxRepo.SaveFailure(ctx, event); err != nil {
+		return errors.Wrapf(err, "event %s", event.ID)
+	}
+
+	return errors.Wrapf(sinkErr, "event %s", event.ID)
+}
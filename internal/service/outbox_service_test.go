@@ -0,0 +1,61 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"task-manager/internal/entities"
+	repoMock "task-manager/internal/repository/mock"
+	"task-manager/internal/service"
+	"testing"
+	"time"
+)
+
+// failingSink records the published events and rejects the listed event IDs.
+type failingSink struct {
+	published []string
+	fail      map[string]bool
+}
+
+func (s *failingSink) Publish(_ context.Context, event entities.TaskEvent) error {
+	if s.fail[event.ID] {
+		return errors.New("sink unavailable")
+	}
+	s.published = append(s.published, event.ID)
+	return nil
+}
+
+// TestRelay_PreservesTaskOrder verifies that a failing event is rescheduled with
+// backoff and holds back the later events of its task, but not those of other tasks.
+func TestRelay_PreservesTaskOrder(t *testing.T) {
+	now := time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC)
+
+	claimed := []entities.OutboxEvent{
+		{Sequence: 1, TaskEvent: entities.TaskEvent{ID: "a", TaskID: 1, Type: entities.TaskEventUpdated}},
+		{Sequence: 2, TaskEvent: entities.TaskEvent{ID: "b", TaskID: 2, Type: entities.TaskEventCreated}},
+		{Sequence: 3, TaskEvent: entities.TaskEvent{ID: "c", TaskID: 1, Type: entities.TaskEventDeleted}},
+		{Sequence: 4, TaskEvent: entities.TaskEvent{ID: "d", TaskID: 2, Type: entities.TaskEventUpdated}},
+	}
+
+	repo := &repoMock.MockOutboxRepository{}
+	repo.On("ClaimDue", mock.Anything, now, time.Minute, 10).Return(claimed, nil)
+	repo.On("MarkPublished", mock.Anything, int64(2), mock.Anything).Return(nil)
+	repo.On("MarkPublished", mock.Anything, int64(4), mock.Anything).Return(nil)
+	repo.On("SaveFailure", mock.Anything, mock.MatchedBy(func(e *entities.OutboxEvent) bool {
+		return e.Sequence == 1 && e.Attempts == 1 && e.LastError == "sink unavailable"
+	})).Return(nil)
+	repo.On("Purge", mock.Anything, now.Add(-service.DefaultOutboxRetention), 10).Return(0, nil)
+
+	sink := &failingSink{fail: map[string]bool{"a": true}}
+	svc := service.NewOutboxService(repo, sink, service.OutboxSettings{BatchSize: 10, BaseBackoff: time.Second})
+
+	published, err := svc.Relay(context.Background(), now)
+
+	require.Error(t, err)
+	assert.Equal(t, 2, published)
+	assert.Equal(t, []string{"b", "d"}, sink.published)
+	assert.WithinDuration(t, time.Now().Add(time.Second), claimed[0].NextAttemptAt, time.Second)
+	repo.AssertExpectations(t)
+}
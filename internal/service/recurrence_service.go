@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"github.com/cockroachdb/errors"
+	"task-manager/internal/entities"
+	"task-manager/internal/repository/postgres"
+	"task-manager/pkg/recurrence"
+	"time"
+)
+
+// DefaultRecurrenceBatchSize is the maximum number of series advanced per run.
+const DefaultRecurrenceBatchSize = 100
+
+// RecurrenceService
+//
+// Interface defining the operations used by the recurrence scheduler.
+type RecurrenceService interface {
+	MaterializeDue(ctx context.Context, now time.Time) ([]entities.Task, error)
+}
+
+// Recurrence
+//
+// Concrete implementation of RecurrenceService. Creates the next occurrence of
+// recurring tasks that were completed, canceled or became overdue.
+type Recurrence struct {
+	taskRepo   postgres.TaskRepository
+	transactor postgres.Transactor
+	outboxRepo postgres.OutboxRepository
+	batchSize  int
+}
+
+// RecurrenceOption
+//
+// Configures optional RecurrenceService dependencies.
+type RecurrenceOption func(*Recurrence)
+
+// WithOccurrenceEvents
+//
+// Emits a task.created event for every occurrence, written to the outbox in the
+// same transaction as the occurrence itself.
+func WithOccurrenceEvents(transactor postgres.Transactor, outboxRepo postgres.OutboxRepository) RecurrenceOption {
+	return func(r *Recurrence) {
+		r.transactor = transactor
+		r.outboxRepo = outboxRepo
+	}
+}
+
+// NewRecurrenceService
+//
+// Constructs a new RecurrenceService advancing at most batchSize series per call.
+// A non-positive batchSize falls back to DefaultRecurrenceBatchSize.
+func NewRecurrenceService(taskRepo postgres.TaskRepository, batchSize int, opts ...RecurrenceOption) RecurrenceService {
+	if batchSize <= 0 {
+		batchSize = DefaultRecurrenceBatchSize
+	}
+
+	r := &Recurrence{
+		taskRepo:  taskRepo,
+		batchSize: batchSize,
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r
+}
+
+// MaterializeDue
+//
+// Creates the next occurrence of every pending recurring task due at or before now.
+// Returns the created occurrences; tasks whose rule could not be evaluated are
+// reported in the error while the others are still materialized.
+func (r *Recurrence) MaterializeDue(ctx context.Context, now time.Time) ([]entities.Task, error) {
+	if r.outboxRepo == nil {
+		return r.taskRepo.MaterializeDueOccurrences(ctx, now, r.batchSize, NextOccurrence(now))
+	}
+
+	var (
+		created []entities.Task
+		ended   error
+	)
+
+	err := r.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		occurrences, err := r.taskRepo.MaterializeDueOccurrences(ctx, now, r.batchSize, NextOccurrence(now))
+		if err != nil && !errors.Is(err, postgres.ErrRecurrenceEnded) {
+			return err
+		}
+
+		created, ended = occurrences, err
+		if len(occurrences) == 0 {
+			return nil
+		}
+
+		events := make([]entities.TaskEvent, 0, len(occurrences))
+		for i := range occurrences {
+			events = append(events, newTaskEvent(entities.TaskEventCreated, &occurrences[i], ""))
+		}
+
+		return r.outboxRepo.Append(ctx, events...)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return created, ended
+}
+
+// NextOccurrence
+//
+// Returns a postgres.NextOccurrenceFunc computing the due date following the task's
+// current one. Occurrences that would already be in the past at now are skipped,
+// so an overdue series does not produce a backlog of overdue copies.
+func NextOccurrence(now time.Time) postgres.NextOccurrenceFunc {
+	return func(task *entities.Task) (time.Time, bool, error) {
+		if task.DueAt == nil {
+			return time.Time{}, false, nil
+		}
+
+		start := *task.DueAt
+		if task.RecurrenceStartAt != nil {
+			start = *task.RecurrenceStartAt
+		}
+
+		after := *task.DueAt
+		if now.After(after) {
+			after = now
+		}
+
+		return recurrence.Next(task.RecurrenceRule, start, after)
+	}
+}
@@ -0,0 +1,178 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"task-manager/internal/entities"
+	repoMock "task-manager/internal/repository/mock"
+	"task-manager/internal/repository/postgres"
+	"task-manager/internal/service"
+	"task-manager/internal/utils"
+	"testing"
+	"time"
+)
+
+// TestCreate_RecurrenceValidation verifies that recurring tasks need a valid rule and a due date.
+func TestCreate_RecurrenceValidation(t *testing.T) {
+	dueAt := time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		dueAt    *time.Time
+		rule     string
+		expected error
+	}{
+		{name: "missing due date", rule: "FREQ=WEEKLY", expected: service.ErrRecurrenceRequiresDueAt},
+		{name: "invalid rule", dueAt: &dueAt, rule: "FREQ=SOMETIMES", expected: service.ErrInvalidRecurrenceRule},
+		{name: "dtstart in rule", dueAt: &dueAt, rule: "DTSTART:20250101T000000Z\nRRULE:FREQ=DAILY", expected: service.ErrInvalidRecurrenceRule},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			taskRepo := &repoMock.MockTaskRepository{}
+			userRepo := &repoMock.MockUserRepository{}
+
+			svc := service.NewTaskService(taskRepo, userRepo, utils.InitGlobalTaskMetrics())
+
+			_, err := svc.Create(context.Background(), &entities.Task{AssigneeID: 7, DueAt: tt.dueAt, RecurrenceRule: tt.rule})
+
+			assert.ErrorIs(t, err, tt.expected)
+			taskRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+// TestUpdate_DoneRecurringTask_ShouldMaterializeNextOccurrence verifies that completing
+// a recurring task creates its successor in the same transaction, with its
+// task.created event.
+func TestUpdate_DoneRecurringTask_ShouldMaterializeNextOccurrence(t *testing.T) {
+	taskRepo := &repoMock.MockTaskRepository{}
+	userRepo := &repoMock.MockUserRepository{}
+	outboxRepo := &repoMock.MockOutboxRepository{}
+
+	dueAt := time.Now().Add(time.Hour)
+	task := &entities.Task{ID: 1, AssigneeID: 7, Status: entities.TaskStatusDone, DueAt: &dueAt, RecurrenceRule: "FREQ=DAILY"}
+
+	userRepo.On("GetByID", mock.Anything, int64(7)).Return(&entities.User{ID: 7, IsActive: true}, nil)
+	taskRepo.On("GetForUpdate", mock.Anything, int64(1)).Return(&entities.Task{ID: 1, AssigneeID: 7, Status: entities.TaskStatusDone}, nil)
+	taskRepo.On("Update", mock.Anything, task).Return(task, nil)
+	taskRepo.On("MaterializeNextOccurrence", mock.Anything, int64(1), mock.Anything).Return(&entities.Task{ID: 2}, nil)
+
+	var recorded []entities.TaskEvent
+	outboxRepo.On("Append", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { recorded = args.Get(1).([]entities.TaskEvent) }).
+		Return(nil)
+
+	svc := service.NewTaskService(taskRepo, userRepo, utils.InitGlobalTaskMetrics(),
+		service.WithOutbox(&repoMock.MockTransactor{}, outboxRepo))
+
+	_, err := svc.Update(context.Background(), task)
+
+	require.NoError(t, err)
+	taskRepo.AssertExpectations(t)
+	require.Len(t, recorded, 2)
+	assert.Equal(t, entities.TaskEventUpdated, recorded[0].Type)
+	assert.Equal(t, entities.TaskEventCreated, recorded[1].Type)
+	assert.Equal(t, int64(2), recorded[1].TaskID)
+}
+
+// TestUpdate_MaterializeFailure_ShouldFailTheUpdate verifies that a successor that
+// cannot be created fails the update, rolled back with it, instead of being dropped.
+func TestUpdate_MaterializeFailure_ShouldFailTheUpdate(t *testing.T) {
+	taskRepo := &repoMock.MockTaskRepository{}
+	userRepo := &repoMock.MockUserRepository{}
+	outboxRepo := &repoMock.MockOutboxRepository{}
+
+	dueAt := time.Now().Add(time.Hour)
+	task := &entities.Task{ID: 1, AssigneeID: 7, Status: entities.TaskStatusDone, DueAt: &dueAt, RecurrenceRule: "FREQ=DAILY"}
+
+	userRepo.On("GetByID", mock.Anything, int64(7)).Return(&entities.User{ID: 7, IsActive: true}, nil)
+	taskRepo.On("GetForUpdate", mock.Anything, int64(1)).Return(task, nil)
+	taskRepo.On("Update", mock.Anything, task).Return(task, nil)
+	taskRepo.On("MaterializeNextOccurrence", mock.Anything, int64(1), mock.Anything).
+		Return((*entities.Task)(nil), errors.New("task 1: foreign key violation"))
+
+	svc := service.NewTaskService(taskRepo, userRepo, utils.InitGlobalTaskMetrics(),
+		service.WithOutbox(&repoMock.MockTransactor{}, outboxRepo))
+
+	_, err := svc.Update(context.Background(), task)
+
+	assert.ErrorContains(t, err, "materialize next occurrence: task 1: foreign key violation")
+	outboxRepo.AssertNotCalled(t, "Append", mock.Anything, mock.Anything)
+}
+
+// TestNextOccurrence verifies that the series stays anchored at its start and skips
+// occurrences already in the past.
+func TestNextOccurrence(t *testing.T) {
+	start := time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC) // Monday
+	dueAt := start.AddDate(0, 0, 7)
+
+	task := &entities.Task{DueAt: &dueAt, RecurrenceStartAt: &start, RecurrenceRule: "FREQ=WEEKLY;COUNT=4"}
+
+	next, ok, err := service.NextOccurrence(start)(task)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, start.AddDate(0, 0, 14), next)
+
+	// Overdue for two weeks: the missed occurrence is skipped
+	next, ok, err = service.NextOccurrence(start.AddDate(0, 0, 15))(task)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, start.AddDate(0, 0, 21), next)
+
+	// COUNT is exhausted after the fourth occurrence
+	_, ok, err = service.NextOccurrence(start.AddDate(0, 0, 22))(task)
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+// TestMaterializeDue_ShouldRecordTaskCreated verifies that the occurrences created by
+// the scheduler emit their task.created events, including when another series ended.
+func TestMaterializeDue_ShouldRecordTaskCreated(t *testing.T) {
+	taskRepo := &repoMock.MockTaskRepository{}
+	outboxRepo := &repoMock.MockOutboxRepository{}
+
+	now := time.Now()
+	ended := errors.Join(fmt.Errorf("task 3: %w: invalid rule", postgres.ErrRecurrenceEnded))
+
+	taskRepo.On("MaterializeDueOccurrences", mock.Anything, now, service.DefaultRecurrenceBatchSize, mock.Anything).
+		Return([]entities.Task{{ID: 2}}, ended)
+
+	var recorded []entities.TaskEvent
+	outboxRepo.On("Append", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { recorded = args.Get(1).([]entities.TaskEvent) }).
+		Return(nil)
+
+	svc := service.NewRecurrenceService(taskRepo, 0,
+		service.WithOccurrenceEvents(&repoMock.MockTransactor{}, outboxRepo))
+
+	created, err := svc.MaterializeDue(context.Background(), now)
+
+	assert.ErrorIs(t, err, postgres.ErrRecurrenceEnded)
+	require.Len(t, created, 1)
+	require.Len(t, recorded, 1)
+	assert.Equal(t, entities.TaskEventCreated, recorded[0].Type)
+	assert.Equal(t, int64(2), recorded[0].TaskID)
+}
+
+// TestMaterializeDue_Failure_ShouldNotRecordEvents verifies that a failed batch, rolled
+// back as a whole, does not emit any event.
+func TestMaterializeDue_Failure_ShouldNotRecordEvents(t *testing.T) {
+	taskRepo := &repoMock.MockTaskRepository{}
+	outboxRepo := &repoMock.MockOutboxRepository{}
+
+	taskRepo.On("MaterializeDueOccurrences", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(nil, errors.New("connection reset"))
+
+	svc := service.NewRecurrenceService(taskRepo, 0,
+		service.WithOccurrenceEvents(&repoMock.MockTransactor{}, outboxRepo))
+
+	_, err := svc.MaterializeDue(context.Background(), time.Now())
+
+	assert.ErrorContains(t, err, "connection reset")
+	outboxRepo.AssertNotCalled(t, "Append", mock.Anything, mock.Anything)
+}
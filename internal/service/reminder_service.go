@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"task-manager/internal/entities"
+	"task-manager/internal/notifier"
+	"task-manager/internal/repository/postgres"
+	"time"
+
+	"github.com/cockroachdb/errors"
+)
+
+// Defaults applied to zero ReminderSettings fields.
+const (
+	DefaultReminderLeadTime  = 24 * time.Hour
+	DefaultReminderBatchSize = 100
+)
+
+// ReminderSettings
+//
+// Configures when deadline notifications are emitted.
+type ReminderSettings struct {
+	LeadTime        time.Duration // How long before the deadline the due_soon reminder is sent
+	EscalationAfter time.Duration // How long after the deadline the overdue escalation is sent
+	BatchSize       int           // Max tasks notified per kind and run
+}
+
+// ReminderService
+//
+// Interface defining the operations used by the reminder scheduler.
+type ReminderService interface {
+	SendDue(ctx context.Context, now time.Time) (int, error)
+}
+
+// Reminder
+//
+// Concrete implementation of ReminderService. Emits a due_soon reminder to the
+// assignees of tasks nearing their deadline and an overdue escalation to assignees
+// and watchers of tasks past it, each at most once per deadline.
+type Reminder struct {
+	reminderRepo postgres.ReminderRepository
+	notifier     notifier.Notifier
+	settings     ReminderSettings
+}
+
+// NewReminderService
+//
+// Constructs a new ReminderService delivering events through the given notifier.
+// Zero settings fall back to the defaults.
+func NewReminderService(reminderRepo postgres.ReminderRepository, notifier notifier.Notifier, settings ReminderSettings) ReminderService {
+	if settings.LeadTime <= 0 {
+		settings.LeadTime = DefaultReminderLeadTime
+	}
+	if settings.BatchSize <= 0 {
+		settings.BatchSize = DefaultReminderBatchSize
+	}
+
+	return &Reminder{
+		reminderRepo: reminderRepo,
+		notifier:     notifier,
+		settings:     settings,
+	}
+}
+
+// SendDue
+//
+// Notifies every open task entering its reminder or escalation window and returns
+// the number of events delivered. A notification is recorded before it is sent so
+// concurrent pods never send it twice; failed deliveries are released and retried
+// on the next run.
+func (r *Reminder) SendDue(ctx context.Context, now time.Time) (int, error) {
+	windows := []struct {
+		kind      entities.ReminderKind
+		dueAfter  time.Time
+		dueBefore time.Time
+	}{
+		{kind: entities.ReminderKindDueSoon, dueAfter: now, dueBefore: now.Add(r.settings.LeadTime)},
+		{kind: entities.ReminderKindOverdue, dueBefore: now.Add(-r.settings.EscalationAfter)},
+	}
+
+	var (
+		sent int
+		errs []error
+	)
+
+	for _, w := range windows {
+		tasks, err := r.reminderRepo.ListUnsent(ctx, w.kind, w.dueAfter, w.dueBefore, r.settings.BatchSize)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		for i := range tasks {
+			ok, err := r.notify(ctx, w.kind, &tasks[i], now)
+			if err != nil {
+				errs = append(errs, errors.Wrapf(err, "task %d %s", tasks[i].ID, w.kind))
+			} else if ok {
+				sent++
+			}
+		}
+	}
+
+	return sent, errors.Join(errs...)
+}
+
+// notify
+//
+// Claims and delivers a single notification. Returns false if it was already sent.
+func (r *Reminder) notify(ctx context.Context, kind entities.ReminderKind, task *entities.Task, now time.Time) (bool, error) {
+	reminder := &entities.Reminder{TaskID: task.ID, Kind: kind, DueAt: *task.DueAt}
+
+	claimed, err := r.reminderRepo.Claim(ctx, reminder)
+	if err != nil || !claimed {
+		return false, err
+	}
+
+	event := notifier.Event{Kind: kind, Task: *task, Recipients: recipients(kind, task), At: now}
+
+	if err := r.notifier.Notify(ctx, event); err != nil {
+		if releaseErr := r.reminderRepo.Release(ctx, reminder); releaseErr != nil {
+			return false, errors.Join(err, releaseErr)
+		}
+		return false, err
+	}
+
+	return true, nil
+}
+
+// recipients
+//
+// Reminders go to the assignees; escalations also reach the watchers.
+func recipients(kind entities.ReminderKind, task *entities.Task) []int64 {
+	users := append([]int64{}, task.AssigneeIDs...)
+	if len(users) == 0 {
+		users = append(users, task.AssigneeID)
+	}
+
+	if kind == entities.ReminderKindOverdue {
+		for _, id := range task.WatcherIDs {
+			if !slices.Contains(users, id) {
+				users = append(users, id)
+			}
+		}
+	}
+
+	return users
+}
@@ -0,0 +1,89 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"task-manager/internal/entities"
+	"task-manager/internal/notifier"
+	repoMock "task-manager/internal/repository/mock"
+	"task-manager/internal/service"
+	"testing"
+	"time"
+)
+
+// recordingNotifier collects the delivered events and fails while err is set.
+type recordingNotifier struct {
+	events []notifier.Event
+	err    error
+}
+
+func (n *recordingNotifier) Notify(_ context.Context, event notifier.Event) error {
+	if n.err != nil {
+		return n.err
+	}
+	n.events = append(n.events, event)
+	return nil
+}
+
+// TestSendDue verifies the reminder windows, the recipients of each kind and that
+// already sent notifications are skipped.
+func TestSendDue(t *testing.T) {
+	now := time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC)
+	soon := now.Add(2 * time.Hour)
+	late := now.Add(-3 * time.Hour)
+
+	dueSoon := entities.Task{ID: 1, DueAt: &soon, AssigneeID: 7, AssigneeIDs: []int64{7}, WatcherIDs: []int64{9}}
+	overdue := entities.Task{ID: 2, DueAt: &late, AssigneeID: 7, AssigneeIDs: []int64{7, 8}, WatcherIDs: []int64{8, 9}}
+	alreadySent := entities.Task{ID: 3, DueAt: &late, AssigneeID: 7}
+
+	repo := &repoMock.MockReminderRepository{}
+	repo.On("ListUnsent", mock.Anything, entities.ReminderKindDueSoon, now, now.Add(24*time.Hour), 100).
+		Return([]entities.Task{dueSoon}, nil)
+	repo.On("ListUnsent", mock.Anything, entities.ReminderKindOverdue, time.Time{}, now.Add(-time.Hour), 100).
+		Return([]entities.Task{overdue, alreadySent}, nil)
+	repo.On("Claim", mock.Anything, mock.MatchedBy(func(r *entities.Reminder) bool { return r.TaskID != 3 })).Return(true, nil)
+	repo.On("Claim", mock.Anything, mock.MatchedBy(func(r *entities.Reminder) bool { return r.TaskID == 3 })).Return(false, nil)
+
+	n := &recordingNotifier{}
+	svc := service.NewReminderService(repo, n, service.ReminderSettings{EscalationAfter: time.Hour})
+
+	sent, err := svc.SendDue(context.Background(), now)
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, sent)
+	require.Len(t, n.events, 2)
+
+	assert.Equal(t, entities.ReminderKindDueSoon, n.events[0].Kind)
+	assert.Equal(t, []int64{7}, n.events[0].Recipients)
+
+	assert.Equal(t, entities.ReminderKindOverdue, n.events[1].Kind)
+	assert.Equal(t, []int64{7, 8, 9}, n.events[1].Recipients)
+
+	repo.AssertExpectations(t)
+}
+
+// TestSendDue_NotifierFailure_ShouldRelease verifies that undelivered notifications
+// are released so the next run retries them.
+func TestSendDue_NotifierFailure_ShouldRelease(t *testing.T) {
+	now := time.Now()
+	late := now.Add(-time.Minute)
+	task := entities.Task{ID: 1, DueAt: &late, AssigneeID: 7}
+
+	repo := &repoMock.MockReminderRepository{}
+	repo.On("ListUnsent", mock.Anything, entities.ReminderKindDueSoon, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+	repo.On("ListUnsent", mock.Anything, entities.ReminderKindOverdue, mock.Anything, mock.Anything, mock.Anything).
+		Return([]entities.Task{task}, nil)
+	repo.On("Claim", mock.Anything, mock.Anything).Return(true, nil)
+	repo.On("Release", mock.Anything, &entities.Reminder{TaskID: 1, Kind: entities.ReminderKindOverdue, DueAt: late}).Return(nil)
+
+	svc := service.NewReminderService(repo, &recordingNotifier{err: errors.New("smtp down")}, service.ReminderSettings{})
+
+	sent, err := svc.SendDue(context.Background(), now)
+
+	assert.Error(t, err)
+	assert.Zero(t, sent)
+	repo.AssertExpectations(t)
+}
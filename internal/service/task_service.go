@@ -2,52 +2,144 @@ package service
 
 import (
 	"context"
+	"github.com/cockroachdb/errors"
+	"github.com/google/uuid"
+	"task-manager/internal/authz"
 	"task-manager/internal/entities"
 	"task-manager/internal/repository/postgres"
 	"task-manager/pkg/monitoring"
+	"task-manager/pkg/recurrence"
 	"task-manager/pkg/rest"
+	"task-manager/pkg/tracing"
 	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // TaskService
 //
 // Interface defining the available task-related operations.
 // All operations are context-aware and return standard Go errors.
+// Every operation is traced in a TaskService.<Operation> span.
 type TaskService interface {
 	Create(ctx context.Context, task *entities.Task) (*entities.Task, error)
 	Update(ctx context.Context, task *entities.Task) (*entities.Task, error)
 	GetByID(ctx context.Context, id int64) (*entities.Task, error)
 	List(ctx context.Context, query rest.Query) ([]entities.Task, int, error)
 	Delete(ctx context.Context, id int64) error
+
+	AddAssignee(ctx context.Context, taskID, userID int64) (*entities.Task, error)
+	RemoveAssignee(ctx context.Context, taskID, userID int64) error
+	AddWatcher(ctx context.Context, taskID, userID int64) (*entities.Task, error)
+	RemoveWatcher(ctx context.Context, taskID, userID int64) error
 }
 
+// ErrAssigneeNotFound is returned when a task references a user that does not exist.
+var ErrAssigneeNotFound = errors.New("assignee not found")
+
+// ErrAssigneeInactive is returned when a task is assigned to a deactivated user.
+var ErrAssigneeInactive = errors.New("assignee is deactivated")
+
+// ErrWatcherNotFound is returned when a task is watched by a user that does not exist.
+var ErrWatcherNotFound = errors.New("watcher not found")
+
+// ErrPrimaryAssignee is returned when removing the primary assignee from the co-assignees.
+// The task has to be reassigned through Update first.
+var ErrPrimaryAssignee = errors.New("primary assignee cannot be removed, reassign the task first")
+
+// ErrInvalidRecurrenceRule is returned when a task carries a malformed RRULE.
+var ErrInvalidRecurrenceRule = recurrence.ErrInvalidRule
+
+// ErrRecurrenceRequiresDueAt is returned when a recurring task has no due date to anchor the series.
+var ErrRecurrenceRequiresDueAt = errors.New("recurring tasks require a due date")
+
 // Task
 //
-// Concrete implementation of TaskService. Wraps a repository and metrics
+// Concrete implementation of TaskService. Wraps the repositories and metrics
 // to perform database operations and record Prometheus metrics for each request.
 type Task struct {
-	taskRepo postgres.TaskRepository
-	metrics  *monitoring.TaskMetrics
+	taskRepo   postgres.TaskRepository
+	userRepo   postgres.UserRepository
+	metrics    *monitoring.TaskMetrics
+	transactor postgres.Transactor
+	outboxRepo postgres.OutboxRepository
+	authorizer authz.Authorizer
+}
+
+// TaskOption
+//
+// Configures optional TaskService dependencies.
+type TaskOption func(*Task)
+
+// WithOutbox
+//
+// Emits task.created/updated/deleted/status_changed events. Every event is written
+// to the outbox in the same transaction as the change it describes, and relayed to
+// the event sinks by OutboxService.
+func WithOutbox(transactor postgres.Transactor, outboxRepo postgres.OutboxRepository) TaskOption {
+	return func(t *Task) {
+		t.transactor = transactor
+		t.outboxRepo = outboxRepo
+	}
+}
+
+// WithAuthorizer
+//
+// Authorizes every operation against the principal of its context. Denied
+// operations fail with an error wrapping authz.ErrForbidden.
+func WithAuthorizer(authorizer authz.Authorizer) TaskOption {
+	return func(t *Task) {
+		t.authorizer = authorizer
+	}
 }
 
 // NewTaskService
 //
-// Constructs a new TaskService with the provided repository and metrics manager.
-func NewTaskService(taskRepo postgres.TaskRepository, metrics *monitoring.TaskMetrics) TaskService {
-	return &Task{
+// Constructs a new TaskService with the provided repositories and metrics manager.
+// The user repository is used to validate task assignees.
+func NewTaskService(taskRepo postgres.TaskRepository, userRepo postgres.UserRepository, metrics *monitoring.TaskMetrics, opts ...TaskOption) TaskService {
+	t := &Task{
 		taskRepo: taskRepo,
+		userRepo: userRepo,
 		metrics:  metrics,
 	}
+
+	for _, opt := range opts {
+		opt(t)
+	}
+
+	return t
 }
 
 // Create
 //
 // Creates a new task in the database and increments metrics counters.
+// All assignees must exist and be active, watchers must exist.
+// Recurring tasks must carry a valid RRULE and a due date.
+// Emits task.created through the outbox, if configured.
 // Records the request latency in Prometheus.
 func (t *Task) Create(ctx context.Context, task *entities.Task) (createdTask *entities.Task, err error) {
 	start := time.Now()
+	ctx, span := tracing.Start(ctx, "TaskService.Create")
+	defer func() { tracing.End(span, err) }()
 
-	createdTask, err = t.taskRepo.Create(ctx, task)
+	if err = t.authorize(ctx, authz.ActionTaskCreate, 0); err == nil {
+		err = t.validate(ctx, task)
+	}
+	if err == nil {
+		err = t.withinTx(ctx, func(ctx context.Context) error {
+			if createdTask, err = t.taskRepo.Create(ctx, task); err != nil {
+				return err
+			}
+			return t.record(ctx, newTaskEvent(entities.TaskEventCreated, createdTask, ""))
+		})
+	}
+
+	// The assignee may have been removed between validation and the write
+	if errors.Is(err, postgres.ErrUserNotFound) {
+		err = ErrAssigneeNotFound
+	}
 
 	if err == nil {
 		t.metrics.TasksCount.WithLabelValues("task_service").Inc()
@@ -66,8 +158,15 @@ func (t *Task) Create(ctx context.Context, task *entities.Task) (createdTask *en
 // Records request latency in Prometheus.
 func (t *Task) GetByID(ctx context.Context, id int64) (task *entities.Task, err error) {
 	start := time.Now()
+	ctx, span := tracing.Start(ctx, "TaskService.GetByID", trace.WithAttributes(attribute.Int64("task.id", id)))
+	defer func() { tracing.End(span, err) }()
 
-	task, err = t.taskRepo.GetByID(ctx, id)
+	if task, err = t.taskRepo.GetByID(ctx, id); err == nil {
+		err = t.authorizeTask(ctx, authz.ActionTaskRead, task)
+	}
+	if err != nil {
+		task = nil
+	}
 
 	t.metrics.RequestLatency.
 		WithLabelValues("GET", statusLabel(err), "task_service").
@@ -83,8 +182,12 @@ func (t *Task) GetByID(ctx context.Context, id int64) (task *entities.Task, err
 // Records request latency in Prometheus.
 func (t *Task) List(ctx context.Context, query rest.Query) (tasks []entities.Task, total int, err error) {
 	start := time.Now()
+	ctx, span := tracing.Start(ctx, "TaskService.List")
+	defer func() { tracing.End(span, err) }()
 
-	tasks, total, err = t.taskRepo.List(ctx, query)
+	if err = t.authorize(ctx, authz.ActionTaskRead, 0); err == nil {
+		tasks, total, err = t.taskRepo.List(ctx, query)
+	}
 
 	t.metrics.RequestLatency.
 		WithLabelValues("GET", statusLabel(err), "task_service").
@@ -96,11 +199,54 @@ func (t *Task) List(ctx context.Context, query rest.Query) (tasks []entities.Tas
 // Update
 //
 // Updates an existing task and records request latency.
+// All assignees must exist and be active, watchers must exist.
+// Completing or canceling a recurring task materializes its next occurrence in the
+// same transaction, failing the update if it cannot be created.
+// Emits task.updated, plus task.status_changed when the status changed and
+// task.created for the next occurrence.
 // Returns the updated task and an error if any.
 func (t *Task) Update(ctx context.Context, task *entities.Task) (updatedTask *entities.Task, err error) {
 	start := time.Now()
+	ctx, span := tracing.Start(ctx, "TaskService.Update", trace.WithAttributes(attribute.Int64("task.id", task.ID)))
+	defer func() { tracing.End(span, err) }()
+
+	if err = t.authorize(ctx, authz.ActionTaskUpdate, task.ID); err == nil {
+		err = t.validate(ctx, task)
+	}
+	if err == nil {
+		err = t.withinTx(ctx, func(ctx context.Context) error {
+			previous, err := t.snapshot(ctx, task.ID)
+			if err != nil {
+				return err
+			}
+
+			if updatedTask, err = t.taskRepo.Update(ctx, task); err != nil {
+				return err
+			}
 
-	updatedTask, err = t.taskRepo.Update(ctx, task)
+			events := []entities.TaskEvent{newTaskEvent(entities.TaskEventUpdated, updatedTask, "")}
+			if previous != nil && previous.Status != updatedTask.Status {
+				events = append(events, newTaskEvent(entities.TaskEventStatusChanged, updatedTask, previous.Status))
+			}
+
+			if updatedTask.IsRecurring() && isClosed(updatedTask.Status) {
+				occurrence, err := t.taskRepo.MaterializeNextOccurrence(ctx, updatedTask.ID, NextOccurrence(time.Now()))
+				if err != nil {
+					return errors.Wrap(err, "materialize next occurrence")
+				}
+				if occurrence != nil {
+					events = append(events, newTaskEvent(entities.TaskEventCreated, occurrence, ""))
+				}
+			}
+
+			return t.record(ctx, events...)
+		})
+	}
+
+	// The assignee may have been removed between validation and the write
+	if errors.Is(err, postgres.ErrUserNotFound) {
+		err = ErrAssigneeNotFound
+	}
 
 	t.metrics.RequestLatency.
 		WithLabelValues("PUT", statusLabel(err), "task_service").
@@ -112,10 +258,25 @@ func (t *Task) Update(ctx context.Context, task *entities.Task) (updatedTask *en
 // Delete
 //
 // Deletes a task by ID. Updates metrics counters and records request latency.
+// Emits task.deleted carrying the last state of the task.
 func (t *Task) Delete(ctx context.Context, id int64) (err error) {
 	start := time.Now()
+	ctx, span := tracing.Start(ctx, "TaskService.Delete", trace.WithAttributes(attribute.Int64("task.id", id)))
+	defer func() { tracing.End(span, err) }()
 
-	err = t.taskRepo.Delete(ctx, id)
+	if err = t.authorize(ctx, authz.ActionTaskDelete, id); err == nil {
+		err = t.withinTx(ctx, func(ctx context.Context) error {
+			previous, err := t.snapshot(ctx, id)
+			if err != nil {
+				return err
+			}
+
+			if err := t.taskRepo.Delete(ctx, id); err != nil {
+				return err
+			}
+			return t.record(ctx, newTaskEvent(entities.TaskEventDeleted, previous, ""))
+		})
+	}
 
 	if err == nil {
 		t.metrics.TasksCount.WithLabelValues("task_service").Desc()
@@ -128,6 +289,335 @@ func (t *Task) Delete(ctx context.Context, id int64) (err error) {
 	return
 }
 
+// AddAssignee
+//
+// Adds an active user as co-assignee of the task and returns the refreshed task.
+// Emits task.updated.
+func (t *Task) AddAssignee(ctx context.Context, taskID, userID int64) (task *entities.Task, err error) {
+	start := time.Now()
+	ctx, span := tracing.Start(ctx, "TaskService.AddAssignee", trace.WithAttributes(attribute.Int64("task.id", taskID), attribute.Int64("user.id", userID)))
+	defer func() { tracing.End(span, err) }()
+
+	if err = t.authorize(ctx, authz.ActionTaskAssign, taskID); err == nil {
+		err = t.validateAssignee(ctx, userID)
+	}
+	if err == nil {
+		err = t.withinTx(ctx, func(ctx context.Context) error {
+			if _, err := t.snapshot(ctx, taskID); err != nil {
+				return err
+			}
+			if err := t.taskRepo.AddAssignee(ctx, taskID, userID); err != nil {
+				return err
+			}
+
+			task, err = t.recordUpdated(ctx, taskID, true)
+			return err
+		})
+	}
+
+	t.metrics.RequestLatency.
+		WithLabelValues("POST", statusLabel(err), "task_service").
+		Observe(float64(time.Since(start).Milliseconds()))
+
+	return
+}
+
+// RemoveAssignee
+//
+// Removes a co-assignee from the task. The primary assignee cannot be removed.
+// Emits task.updated.
+func (t *Task) RemoveAssignee(ctx context.Context, taskID, userID int64) (err error) {
+	start := time.Now()
+	ctx, span := tracing.Start(ctx, "TaskService.RemoveAssignee", trace.WithAttributes(attribute.Int64("task.id", taskID), attribute.Int64("user.id", userID)))
+	defer func() { tracing.End(span, err) }()
+
+	var task *entities.Task
+	if task, err = t.taskRepo.GetByID(ctx, taskID); err == nil {
+		err = t.authorizeTask(ctx, authz.ActionTaskAssign, task)
+	}
+	if err == nil {
+		if task.AssigneeID == userID {
+			err = ErrPrimaryAssignee
+		} else {
+			err = t.withinTx(ctx, func(ctx context.Context) error {
+				if _, err := t.snapshot(ctx, taskID); err != nil {
+					return err
+				}
+				if err := t.taskRepo.RemoveAssignee(ctx, taskID, userID); err != nil {
+					return err
+				}
+
+				_, err := t.recordUpdated(ctx, taskID, false)
+				return err
+			})
+		}
+	}
+
+	t.metrics.RequestLatency.
+		WithLabelValues("DELETE", statusLabel(err), "task_service").
+		Observe(float64(time.Since(start).Milliseconds()))
+
+	return
+}
+
+// AddWatcher
+//
+// Subscribes an existing user to the task and returns the refreshed task.
+// Emits task.updated.
+func (t *Task) AddWatcher(ctx context.Context, taskID, userID int64) (task *entities.Task, err error) {
+	start := time.Now()
+	ctx, span := tracing.Start(ctx, "TaskService.AddWatcher", trace.WithAttributes(attribute.Int64("task.id", taskID), attribute.Int64("user.id", userID)))
+	defer func() { tracing.End(span, err) }()
+
+	if err = t.authorize(ctx, authz.ActionTaskWatch, taskID); err == nil {
+		err = t.validateWatcher(ctx, userID)
+	}
+	if err == nil {
+		err = t.withinTx(ctx, func(ctx context.Context) error {
+			if _, err := t.snapshot(ctx, taskID); err != nil {
+				return err
+			}
+			if err := t.taskRepo.AddWatcher(ctx, taskID, userID); err != nil {
+				return err
+			}
+
+			task, err = t.recordUpdated(ctx, taskID, true)
+			return err
+		})
+	}
+
+	t.metrics.RequestLatency.
+		WithLabelValues("POST", statusLabel(err), "task_service").
+		Observe(float64(time.Since(start).Milliseconds()))
+
+	return
+}
+
+// RemoveWatcher
+//
+// Unsubscribes a user from the task.
+// Emits task.updated.
+func (t *Task) RemoveWatcher(ctx context.Context, taskID, userID int64) (err error) {
+	start := time.Now()
+	ctx, span := tracing.Start(ctx, "TaskService.RemoveWatcher", trace.WithAttributes(attribute.Int64("task.id", taskID), attribute.Int64("user.id", userID)))
+	defer func() { tracing.End(span, err) }()
+
+	if err = t.authorize(ctx, authz.ActionTaskWatch, taskID); err == nil {
+		err = t.withinTx(ctx, func(ctx context.Context) error {
+			if _, err := t.snapshot(ctx, taskID); err != nil {
+				return err
+			}
+			if err := t.taskRepo.RemoveWatcher(ctx, taskID, userID); err != nil {
+				return err
+			}
+
+			_, err := t.recordUpdated(ctx, taskID, false)
+			return err
+		})
+	}
+
+	t.metrics.RequestLatency.
+		WithLabelValues("DELETE", statusLabel(err), "task_service").
+		Observe(float64(time.Since(start).Milliseconds()))
+
+	return
+}
+
+// authorize
+//
+// Authorizes action on the task with the given ID, 0 for actions not bound to an
+// existing task. The task is only loaded if ownership decides.
+func (t *Task) authorize(ctx context.Context, action authz.Action, taskID int64) error {
+	if t.authorizer == nil {
+		return nil
+	}
+
+	var load func() (*entities.Task, error)
+	if taskID != 0 {
+		load = func() (*entities.Task, error) { return t.taskRepo.GetByID(ctx, taskID) }
+	}
+
+	return t.authorizer.Authorize(ctx, action, load)
+}
+
+// authorizeTask
+//
+// Authorizes action on a task that is already loaded.
+func (t *Task) authorizeTask(ctx context.Context, action authz.Action, task *entities.Task) error {
+	if t.authorizer == nil {
+		return nil
+	}
+
+	return t.authorizer.Authorize(ctx, action, func() (*entities.Task, error) { return task, nil })
+}
+
+// withinTx
+//
+// Runs fn in a transaction when an outbox is configured, so the change and its
+// events are committed together. Runs fn directly otherwise.
+func (t *Task) withinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if t.transactor == nil {
+		return fn(ctx)
+	}
+
+	return t.transactor.WithinTx(ctx, fn)
+}
+
+// snapshot
+//
+// Loads and locks the current state of a task for its change events. Skipped when
+// no outbox is configured, so the write path stays a single query.
+func (t *Task) snapshot(ctx context.Context, id int64) (*entities.Task, error) {
+	if t.outboxRepo == nil {
+		return nil, nil
+	}
+
+	return t.taskRepo.GetForUpdate(ctx, id)
+}
+
+// record
+//
+// Writes the events to the outbox, if configured.
+func (t *Task) record(ctx context.Context, events ...entities.TaskEvent) error {
+	if t.outboxRepo == nil {
+		return nil
+	}
+
+	return t.outboxRepo.Append(ctx, events...)
+}
+
+// recordUpdated
+//
+// Reloads a task whose assignees or watchers changed and records task.updated with
+// it. The task is only reloaded for the outbox unless the caller returns it.
+func (t *Task) recordUpdated(ctx context.Context, id int64, reload bool) (*entities.Task, error) {
+	if t.outboxRepo == nil && !reload {
+		return nil, nil
+	}
+
+	task, err := t.taskRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return task, t.record(ctx, newTaskEvent(entities.TaskEventUpdated, task, ""))
+}
+
+// newTaskEvent
+//
+// Builds a task event with a unique ID.
+func newTaskEvent(eventType entities.TaskEventType, task *entities.Task, previousStatus entities.TaskStatus) entities.TaskEvent {
+	event := entities.TaskEvent{
+		ID:             uuid.NewString(),
+		Type:           eventType,
+		Task:           task,
+		PreviousStatus: previousStatus,
+		OccurredAt:     time.Now(),
+	}
+	if task != nil {
+		event.TaskID = task.ID
+	}
+
+	return event
+}
+
+// validate
+//
+// Runs every validation required before a task is written.
+func (t *Task) validate(ctx context.Context, task *entities.Task) error {
+	if err := validateRecurrence(task); err != nil {
+		return err
+	}
+
+	return t.validateMembers(ctx, task)
+}
+
+// validateRecurrence
+//
+// Ensures recurring tasks carry a valid RRULE and a due date anchoring the series.
+func validateRecurrence(task *entities.Task) error {
+	if !task.IsRecurring() {
+		return nil
+	}
+
+	if task.DueAt == nil {
+		return ErrRecurrenceRequiresDueAt
+	}
+
+	return recurrence.Validate(task.RecurrenceRule)
+}
+
+// isClosed
+//
+// Reports whether the status ends the current occurrence of a recurring task.
+func isClosed(status entities.TaskStatus) bool {
+	return status == entities.TaskStatusDone || status == entities.TaskStatusCanceled
+}
+
+// validateMembers
+//
+// Validates the primary assignee, every co-assignee and every watcher of a task.
+// Each user is looked up only once.
+func (t *Task) validateMembers(ctx context.Context, task *entities.Task) error {
+	seen := make(map[int64]bool)
+
+	for _, id := range append([]int64{task.AssigneeID}, task.AssigneeIDs...) {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		if err := t.validateAssignee(ctx, id); err != nil {
+			return err
+		}
+	}
+
+	for _, id := range task.WatcherIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		if err := t.validateWatcher(ctx, id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateWatcher
+//
+// Ensures the given user exists. Deactivated users may keep watching tasks.
+func (t *Task) validateWatcher(ctx context.Context, userID int64) error {
+	_, err := t.userRepo.GetByID(ctx, userID)
+	if errors.Is(err, postgres.ErrUserNotFound) {
+		return ErrWatcherNotFound
+	}
+
+	return err
+}
+
+// validateAssignee
+//
+// Ensures the given user exists and is active so tasks are never assigned to
+// unknown or deactivated people.
+func (t *Task) validateAssignee(ctx context.Context, assigneeID int64) error {
+	user, err := t.userRepo.GetByID(ctx, assigneeID)
+	if err != nil {
+		if errors.Is(err, postgres.ErrUserNotFound) {
+			return ErrAssigneeNotFound
+		}
+		return err
+	}
+
+	if !user.IsActive {
+		return ErrAssigneeInactive
+	}
+
+	return nil
+}
+
 // statusLabel
 //
 // Helper function to map error presence to a Prometheus metric label.
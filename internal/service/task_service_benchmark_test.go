@@ -10,10 +10,13 @@ import (
 // BenchmarkCreateTask benchmarks the Create method
 func BenchmarkCreateTask(b *testing.B) {
 	svc := service.MakeNewTaskService()
+	assignee := service.CreateTestUser()
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		_, _ = svc.Create(context.Background(), service.RandomTask())
+		task := service.RandomTask()
+		task.AssigneeID = assignee.ID
+		_, _ = svc.Create(context.Background(), task)
 	}
 }
 
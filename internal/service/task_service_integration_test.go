@@ -23,6 +23,7 @@ func TestCreateTaskIntegration(t *testing.T) {
 
 	taskService := service.MakeNewTaskService()
 	task := service.RandomTask()
+	task.AssigneeID = service.CreateTestUser().ID
 
 	res, err := taskService.Create(ctx, task)
 
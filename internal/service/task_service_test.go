@@ -0,0 +1,267 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"task-manager/internal/auth"
+	"task-manager/internal/authz"
+	"task-manager/internal/entities"
+	repoMock "task-manager/internal/repository/mock"
+	"task-manager/internal/repository/postgres"
+	"task-manager/internal/service"
+	"task-manager/internal/utils"
+	"task-manager/pkg/tracing"
+	"task-manager/pkg/tracing/tracingtest"
+	"testing"
+)
+
+// TestCreate_AssigneeValidation verifies that tasks are only written for existing, active assignees.
+func TestCreate_AssigneeValidation(t *testing.T) {
+	tests := []struct {
+		name     string
+		user     *entities.User
+		userErr  error
+		expected error
+	}{
+		{name: "unknown assignee", userErr: postgres.ErrUserNotFound, expected: service.ErrAssigneeNotFound},
+		{name: "deactivated assignee", user: &entities.User{ID: 7, IsActive: false}, expected: service.ErrAssigneeInactive},
+		{name: "active assignee", user: &entities.User{ID: 7, IsActive: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			taskRepo := &repoMock.MockTaskRepository{}
+			userRepo := &repoMock.MockUserRepository{}
+
+			task := &entities.Task{Title: "task", Status: entities.TaskStatusPending, AssigneeID: 7}
+
+			userRepo.On("GetByID", mock.Anything, int64(7)).Return(tt.user, tt.userErr)
+			if tt.expected == nil {
+				taskRepo.On("Create", mock.Anything, task).Return(task, nil)
+			}
+
+			svc := service.NewTaskService(taskRepo, userRepo, utils.InitGlobalTaskMetrics())
+
+			_, err := svc.Create(context.Background(), task)
+
+			assert.ErrorIs(t, err, tt.expected)
+			taskRepo.AssertExpectations(t)
+			userRepo.AssertExpectations(t)
+		})
+	}
+}
+
+// TestGetByID_ShouldRunInASpan verifies that the operations are traced in a child span
+// of the request, failed when the operation failed.
+func TestGetByID_ShouldRunInASpan(t *testing.T) {
+	exporter := tracingtest.Record(t)
+	taskRepo := &repoMock.MockTaskRepository{}
+	userRepo := &repoMock.MockUserRepository{}
+
+	taskRepo.On("GetByID", mock.MatchedBy(func(ctx context.Context) bool {
+		return trace.SpanContextFromContext(ctx).IsValid()
+	}), int64(5)).Return(nil, postgres.ErrTaskNotFound)
+
+	svc := service.NewTaskService(taskRepo, userRepo, utils.InitGlobalTaskMetrics())
+
+	ctx, request := tracing.Start(context.Background(), "GET /api/v1/tasks/:id")
+	_, err := svc.GetByID(ctx, 5)
+	request.End()
+
+	assert.ErrorIs(t, err, postgres.ErrTaskNotFound)
+	spans := exporter.GetSpans()
+	assert.Equal(t, []string{"TaskService.GetByID", "GET /api/v1/tasks/:id"}, tracingtest.Names(spans))
+	assert.Equal(t, spans[1].SpanContext.SpanID(), spans[0].Parent.SpanID())
+	assert.Equal(t, codes.Error, spans[0].Status.Code)
+	assert.Contains(t, spans[0].Attributes, attribute.Int64("task.id", 5))
+	taskRepo.AssertExpectations(t)
+}
+
+// TestRemoveAssignee_Primary verifies that the primary assignee is never removed from the co-assignees.
+func TestRemoveAssignee_Primary(t *testing.T) {
+	taskRepo := &repoMock.MockTaskRepository{}
+	userRepo := &repoMock.MockUserRepository{}
+
+	taskRepo.On("GetByID", mock.Anything, int64(1)).Return(&entities.Task{ID: 1, AssigneeID: 7}, nil)
+	taskRepo.On("RemoveAssignee", mock.Anything, int64(1), int64(8)).Return(nil)
+
+	svc := service.NewTaskService(taskRepo, userRepo, utils.InitGlobalTaskMetrics())
+
+	assert.ErrorIs(t, svc.RemoveAssignee(context.Background(), 1, 7), service.ErrPrimaryAssignee)
+	assert.NoError(t, svc.RemoveAssignee(context.Background(), 1, 8))
+
+	taskRepo.AssertExpectations(t)
+}
+
+// TestCreate_UnknownWatcher verifies that watchers must reference existing users.
+func TestCreate_UnknownWatcher(t *testing.T) {
+	taskRepo := &repoMock.MockTaskRepository{}
+	userRepo := &repoMock.MockUserRepository{}
+
+	userRepo.On("GetByID", mock.Anything, int64(7)).Return(&entities.User{ID: 7, IsActive: true}, nil)
+	userRepo.On("GetByID", mock.Anything, int64(9)).Return(nil, postgres.ErrUserNotFound)
+
+	svc := service.NewTaskService(taskRepo, userRepo, utils.InitGlobalTaskMetrics())
+
+	_, err := svc.Create(context.Background(), &entities.Task{AssigneeID: 7, WatcherIDs: []int64{9}})
+
+	assert.ErrorIs(t, err, service.ErrWatcherNotFound)
+	taskRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+// TestUpdate_RecordsStatusChange verifies that a status change writes both
+// task.updated and task.status_changed, with the previous status, to the outbox.
+func TestUpdate_RecordsStatusChange(t *testing.T) {
+	taskRepo := &repoMock.MockTaskRepository{}
+	userRepo := &repoMock.MockUserRepository{}
+	outboxRepo := &repoMock.MockOutboxRepository{}
+
+	task := &entities.Task{ID: 1, Title: "task", Status: entities.TaskStatusDone, AssigneeID: 7}
+
+	userRepo.On("GetByID", mock.Anything, int64(7)).Return(&entities.User{ID: 7, IsActive: true}, nil)
+	taskRepo.On("GetForUpdate", mock.Anything, int64(1)).
+		Return(&entities.Task{ID: 1, Status: entities.TaskStatusInProgress, AssigneeID: 7}, nil)
+	taskRepo.On("Update", mock.Anything, task).Return(task, nil)
+
+	var recorded []entities.TaskEvent
+	outboxRepo.On("Append", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { recorded = args.Get(1).([]entities.TaskEvent) }).
+		Return(nil)
+
+	svc := service.NewTaskService(taskRepo, userRepo, utils.InitGlobalTaskMetrics(),
+		service.WithOutbox(&repoMock.MockTransactor{}, outboxRepo))
+
+	_, err := svc.Update(context.Background(), task)
+
+	assert.NoError(t, err)
+	if assert.Len(t, recorded, 2) {
+		assert.Equal(t, entities.TaskEventUpdated, recorded[0].Type)
+		assert.Equal(t, entities.TaskEventStatusChanged, recorded[1].Type)
+		assert.Equal(t, entities.TaskStatusInProgress, recorded[1].PreviousStatus)
+		assert.Equal(t, int64(1), recorded[1].TaskID)
+		assert.NotEqual(t, recorded[0].ID, recorded[1].ID)
+	}
+}
+
+// TestMembership_RecordsTaskUpdated verifies that adding and removing assignees and
+// watchers records task.updated carrying the refreshed task.
+func TestMembership_RecordsTaskUpdated(t *testing.T) {
+	taskRepo := &repoMock.MockTaskRepository{}
+	userRepo := &repoMock.MockUserRepository{}
+	outboxRepo := &repoMock.MockOutboxRepository{}
+
+	current := &entities.Task{ID: 1, Title: "task", Status: entities.TaskStatusPending, AssigneeID: 7, AssigneeIDs: []int64{7, 8}, WatcherIDs: []int64{9}}
+
+	userRepo.On("GetByID", mock.Anything, mock.Anything).Return(&entities.User{ID: 8, IsActive: true}, nil)
+	taskRepo.On("GetForUpdate", mock.Anything, int64(1)).Return(current, nil)
+	taskRepo.On("GetByID", mock.Anything, int64(1)).Return(current, nil)
+	taskRepo.On("AddAssignee", mock.Anything, int64(1), int64(8)).Return(nil)
+	taskRepo.On("RemoveAssignee", mock.Anything, int64(1), int64(8)).Return(nil)
+	taskRepo.On("AddWatcher", mock.Anything, int64(1), int64(9)).Return(nil)
+	taskRepo.On("RemoveWatcher", mock.Anything, int64(1), int64(9)).Return(nil)
+
+	var recorded []entities.TaskEvent
+	outboxRepo.On("Append", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { recorded = append(recorded, args.Get(1).([]entities.TaskEvent)...) }).
+		Return(nil)
+
+	svc := service.NewTaskService(taskRepo, userRepo, utils.InitGlobalTaskMetrics(),
+		service.WithOutbox(&repoMock.MockTransactor{}, outboxRepo))
+	ctx := context.Background()
+
+	_, err := svc.AddAssignee(ctx, 1, 8)
+	require.NoError(t, err)
+	require.NoError(t, svc.RemoveAssignee(ctx, 1, 8))
+	_, err = svc.AddWatcher(ctx, 1, 9)
+	require.NoError(t, err)
+	require.NoError(t, svc.RemoveWatcher(ctx, 1, 9))
+
+	require.Len(t, recorded, 4)
+	for _, event := range recorded {
+		assert.Equal(t, entities.TaskEventUpdated, event.Type)
+		assert.Equal(t, current, event.Task)
+	}
+	taskRepo.AssertNumberOfCalls(t, "GetForUpdate", 4)
+}
+
+// TestCreate_OutboxFailure verifies that the task write fails when its event cannot
+// be written to the outbox, so no change is committed without its event.
+func TestCreate_OutboxFailure(t *testing.T) {
+	taskRepo := &repoMock.MockTaskRepository{}
+	userRepo := &repoMock.MockUserRepository{}
+	outboxRepo := &repoMock.MockOutboxRepository{}
+
+	task := &entities.Task{Title: "task", Status: entities.TaskStatusPending, AssigneeID: 7}
+
+	userRepo.On("GetByID", mock.Anything, int64(7)).Return(&entities.User{ID: 7, IsActive: true}, nil)
+	taskRepo.On("Create", mock.Anything, task).Return(task, nil)
+	outboxRepo.On("Append", mock.Anything, mock.Anything).Return(errors.New("outbox unavailable"))
+
+	svc := service.NewTaskService(taskRepo, userRepo, utils.InitGlobalTaskMetrics(),
+		service.WithOutbox(&repoMock.MockTransactor{}, outboxRepo))
+
+	_, err := svc.Create(context.Background(), task)
+
+	assert.EqualError(t, err, "outbox unavailable")
+}
+
+// TestDelete_MemberForbidden verifies that the service itself denies deleting a task
+// to a member assigned to it, without touching the task.
+func TestDelete_MemberForbidden(t *testing.T) {
+	taskRepo := &repoMock.MockTaskRepository{}
+	userRepo := &repoMock.MockUserRepository{}
+
+	svc := service.NewTaskService(taskRepo, userRepo, utils.InitGlobalTaskMetrics(),
+		service.WithAuthorizer(authz.DefaultPolicy()),
+	)
+	ctx := auth.WithPrincipal(context.Background(), &auth.Principal{UserID: 7, Roles: []string{authz.RoleMember}})
+
+	err := svc.Delete(ctx, 1)
+
+	assert.ErrorIs(t, err, authz.ErrForbidden)
+	taskRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
+
+// TestUpdate_Ownership verifies that members only update the tasks they are assigned to.
+func TestUpdate_Ownership(t *testing.T) {
+	tests := []struct {
+		name     string
+		current  *entities.Task
+		expected error
+	}{
+		{name: "assigned", current: &entities.Task{ID: 1, AssigneeID: 7}},
+		{name: "not assigned", current: &entities.Task{ID: 1, AssigneeID: 8}, expected: authz.ErrForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			taskRepo := &repoMock.MockTaskRepository{}
+			userRepo := &repoMock.MockUserRepository{}
+
+			task := &entities.Task{ID: 1, Title: "task", Status: entities.TaskStatusDone, AssigneeID: 7}
+
+			taskRepo.On("GetByID", mock.Anything, int64(1)).Return(tt.current, nil)
+			if tt.expected == nil {
+				userRepo.On("GetByID", mock.Anything, int64(7)).Return(&entities.User{ID: 7, IsActive: true}, nil)
+				taskRepo.On("Update", mock.Anything, task).Return(task, nil)
+			}
+
+			svc := service.NewTaskService(taskRepo, userRepo, utils.InitGlobalTaskMetrics(),
+				service.WithAuthorizer(authz.DefaultPolicy()),
+			)
+			ctx := auth.WithPrincipal(context.Background(), &auth.Principal{UserID: 7, Roles: []string{authz.RoleMember}})
+
+			_, err := svc.Update(ctx, task)
+
+			assert.ErrorIs(t, err, tt.expected)
+			taskRepo.AssertExpectations(t)
+			userRepo.AssertExpectations(t)
+		})
+	}
+}
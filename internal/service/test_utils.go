@@ -17,7 +17,30 @@ func MakeNewTaskService() TaskService {
 		utils.CreateTestDatabaseConnection(),
 	)
 
-	return NewTaskService(repo, utils.InitGlobalTaskMetrics())
+	return NewTaskService(repo, postgres.NewUserRepository(utils.CreateTestDatabaseConnection()), utils.InitGlobalTaskMetrics())
+}
+
+// MakeNewUserService creates a new UserService instance backed by the test database.
+func MakeNewUserService() UserService {
+	repo := postgres.NewUserRepository(
+		utils.CreateTestDatabaseConnection(),
+	)
+
+	return NewUserService(repo, utils.InitGlobalTaskMetrics())
+}
+
+// CreateTestUser creates a random active user in the database for testing purposes.
+// Panics if creation fails. Tasks need an existing assignee, so call this first.
+func CreateTestUser() *entities.User {
+	user, err := MakeNewUserService().Create(
+		context.Background(),
+		RandomUser(),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	return user
 }
 
 // CreateTestTask creates a random task in the database for testing purposes.
@@ -25,9 +48,12 @@ func MakeNewTaskService() TaskService {
 func CreateTestTask() *entities.Task {
 	service := MakeNewTaskService()
 
+	task := RandomTask()
+	task.AssigneeID = CreateTestUser().ID
+
 	task, err := service.Create(
 		context.Background(),
-		RandomTask(),
+		task,
 	)
 	if err != nil {
 		panic(err)
@@ -55,3 +81,14 @@ func RandomTask() *entities.Task {
 		UpdatedAt:   time.Now(),
 	}
 }
+
+// RandomUser generates a new active User entity with randomized name and email.
+func RandomUser() *entities.User {
+	return &entities.User{
+		Name:      gofakeit.Name(),
+		Email:     gofakeit.Email(),
+		IsActive:  true,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+}
@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"task-manager/internal/entities"
+	"task-manager/internal/repository/postgres"
+	"task-manager/pkg/monitoring"
+	"task-manager/pkg/rest"
+	"time"
+)
+
+// UserService
+//
+// Interface defining the available user-related operations.
+// All operations are context-aware and return standard Go errors.
+type UserService interface {
+	Create(ctx context.Context, user *entities.User) (*entities.User, error)
+	Update(ctx context.Context, user *entities.User) (*entities.User, error)
+	GetByID(ctx context.Context, id int64) (*entities.User, error)
+	GetByIDs(ctx context.Context, ids []int64) ([]entities.User, error)
+	List(ctx context.Context, query rest.Query) ([]entities.User, int, error)
+	Delete(ctx context.Context, id int64) error
+}
+
+// User
+//
+// Concrete implementation of UserService. Wraps a repository and metrics
+// to perform database operations and record Prometheus metrics for each request.
+type User struct {
+	userRepo postgres.UserRepository
+	metrics  *monitoring.TaskMetrics
+}
+
+// NewUserService
+//
+// Constructs a new UserService with the provided repository and metrics manager.
+func NewUserService(userRepo postgres.UserRepository, metrics *monitoring.TaskMetrics) UserService {
+	return &User{
+		userRepo: userRepo,
+		metrics:  metrics,
+	}
+}
+
+// Create
+//
+// Creates a new user in the database and records the request latency.
+func (u *User) Create(ctx context.Context, user *entities.User) (createdUser *entities.User, err error) {
+	start := time.Now()
+
+	createdUser, err = u.userRepo.Create(ctx, user)
+
+	u.observe("POST", err, start)
+
+	return
+}
+
+// GetByID
+//
+// Fetches a user by its ID from the repository.
+func (u *User) GetByID(ctx context.Context, id int64) (user *entities.User, err error) {
+	start := time.Now()
+
+	user, err = u.userRepo.GetByID(ctx, id)
+
+	u.observe("GET", err, start)
+
+	return
+}
+
+// GetByIDs
+//
+// Fetches the users of the given IDs at once, leaving out the missing ones.
+func (u *User) GetByIDs(ctx context.Context, ids []int64) (users []entities.User, err error) {
+	start := time.Now()
+
+	users, err = u.userRepo.GetByIDs(ctx, ids)
+
+	u.observe("GET", err, start)
+
+	return
+}
+
+// List
+//
+// Fetches a paginated list of users with optional filters.
+func (u *User) List(ctx context.Context, query rest.Query) (users []entities.User, total int, err error) {
+	start := time.Now()
+
+	users, total, err = u.userRepo.List(ctx, query)
+
+	u.observe("GET", err, start)
+
+	return
+}
+
+// Update
+//
+// Updates an existing user, including its active flag.
+func (u *User) Update(ctx context.Context, user *entities.User) (updatedUser *entities.User, err error) {
+	start := time.Now()
+
+	updatedUser, err = u.userRepo.Update(ctx, user)
+
+	u.observe("PUT", err, start)
+
+	return
+}
+
+// Delete
+//
+// Deletes a user by ID. Users that still own tasks cannot be deleted
+// and should be deactivated instead.
+func (u *User) Delete(ctx context.Context, id int64) (err error) {
+	start := time.Now()
+
+	err = u.userRepo.Delete(ctx, id)
+
+	u.observe("DELETE", err, start)
+
+	return
+}
+
+// observe
+//
+// Records the request latency of a user operation in Prometheus.
+func (u *User) observe(method string, err error, start time.Time) {
+	u.metrics.RequestLatency.
+		WithLabelValues(method, statusLabel(err), "user_service").
+		Observe(float64(time.Since(start).Milliseconds()))
+}
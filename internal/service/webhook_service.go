@@ -0,0 +1,311 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"task-manager/internal/entities"
+	"task-manager/internal/events"
+	"task-manager/internal/repository/postgres"
+	"task-manager/internal/tenant"
+	"task-manager/pkg/rest"
+	"task-manager/pkg/retry"
+	"task-manager/pkg/webhook"
+	"time"
+
+	"github.com/cockroachdb/errors"
+)
+
+// Defaults applied to zero WebhookSettings fields.
+const (
+	DefaultWebhookMaxAttempts = 8
+	DefaultWebhookBaseBackoff = 30 * time.Second
+	DefaultWebhookMaxBackoff  = time.Hour
+	DefaultWebhookTimeout     = 10 * time.Second
+	DefaultWebhookBatchSize   = 50
+)
+
+// ErrInvalidWebhookURL is returned when a subscription URL is not an absolute http(s) URL.
+var ErrInvalidWebhookURL = errors.New("webhook url must be an absolute http or https url")
+
+// ErrInvalidWebhookEvent is returned when a subscription filters on an unknown event type.
+var ErrInvalidWebhookEvent = errors.New("unknown webhook event type")
+
+// WebhookSettings
+//
+// Configures the webhook delivery queue.
+type WebhookSettings struct {
+	MaxAttempts int           // Attempts before a delivery is dead-lettered
+	BaseBackoff time.Duration // Delay before the first retry, doubled on every retry
+	MaxBackoff  time.Duration // Upper bound of the retry delay
+	Timeout     time.Duration // Timeout of a single HTTP attempt
+	BatchSize   int           // Max deliveries attempted per run
+
+	AllowPrivateAddresses bool // Lets the subscriptions target loopback, link-local and private addresses
+}
+
+// WebhookService
+//
+// Interface defining webhook subscription management, event publishing and delivery.
+type WebhookService interface {
+	CreateSubscription(ctx context.Context, subscription *entities.WebhookSubscription) (*entities.WebhookSubscription, error)
+	UpdateSubscription(ctx context.Context, subscription *entities.WebhookSubscription) (*entities.WebhookSubscription, error)
+	GetSubscription(ctx context.Context, id int64) (*entities.WebhookSubscription, error)
+	ListSubscriptions(ctx context.Context, query rest.Query) ([]entities.WebhookSubscription, int, error)
+	DeleteSubscription(ctx context.Context, id int64) error
+
+	ListDeliveries(ctx context.Context, subscriptionID int64, query rest.Query) ([]entities.WebhookDelivery, int, error)
+	RetryDelivery(ctx context.Context, subscriptionID, deliveryID int64) (*entities.WebhookDelivery, error)
+
+	Publish(ctx context.Context, event entities.TaskEvent) error
+	DeliverDue(ctx context.Context, now time.Time) (int, error)
+}
+
+// Webhook
+//
+// Concrete implementation of WebhookService. Events are queued in the database
+// once per matching subscription and delivered by DeliverDue with exponential
+// backoff; deliveries failing MaxAttempts times are dead-lettered.
+type Webhook struct {
+	webhookRepo postgres.WebhookRepository
+	client      *webhook.Client
+	settings    WebhookSettings
+}
+
+// NewWebhookService
+//
+// Constructs a new WebhookService. Zero settings fall back to the defaults.
+func NewWebhookService(webhookRepo postgres.WebhookRepository, settings WebhookSettings) WebhookService {
+	if settings.MaxAttempts <= 0 {
+		settings.MaxAttempts = DefaultWebhookMaxAttempts
+	}
+	if settings.BaseBackoff <= 0 {
+		settings.BaseBackoff = DefaultWebhookBaseBackoff
+	}
+	if settings.MaxBackoff <= 0 {
+		settings.MaxBackoff = DefaultWebhookMaxBackoff
+	}
+	if settings.Timeout <= 0 {
+		settings.Timeout = DefaultWebhookTimeout
+	}
+	if settings.BatchSize <= 0 {
+		settings.BatchSize = DefaultWebhookBatchSize
+	}
+
+	var opts []webhook.ClientOption
+	if settings.AllowPrivateAddresses {
+		opts = append(opts, webhook.AllowPrivateAddresses())
+	}
+
+	return &Webhook{
+		webhookRepo: webhookRepo,
+		client:      webhook.NewClient(settings.Timeout, opts...),
+		settings:    settings,
+	}
+}
+
+// CreateSubscription
+//
+// Validates and stores a new subscription. A random secret is generated when none is given.
+func (w *Webhook) CreateSubscription(ctx context.Context, subscription *entities.WebhookSubscription) (*entities.WebhookSubscription, error) {
+	if err := w.validateSubscription(subscription); err != nil {
+		return nil, err
+	}
+
+	if subscription.Secret == "" {
+		secret, err := generateSecret()
+		if err != nil {
+			return nil, err
+		}
+		subscription.Secret = secret
+	}
+
+	return w.webhookRepo.CreateSubscription(ctx, subscription)
+}
+
+// UpdateSubscription
+//
+// Validates and updates the URL, event filter and active flag of a subscription.
+func (w *Webhook) UpdateSubscription(ctx context.Context, subscription *entities.WebhookSubscription) (*entities.WebhookSubscription, error) {
+	if err := w.validateSubscription(subscription); err != nil {
+		return nil, err
+	}
+
+	return w.webhookRepo.UpdateSubscription(ctx, subscription)
+}
+
+// GetSubscription
+//
+// Fetches a subscription by its ID.
+func (w *Webhook) GetSubscription(ctx context.Context, id int64) (*entities.WebhookSubscription, error) {
+	return w.webhookRepo.GetSubscription(ctx, id)
+}
+
+// ListSubscriptions
+//
+// Fetches a paginated list of subscriptions.
+func (w *Webhook) ListSubscriptions(ctx context.Context, query rest.Query) ([]entities.WebhookSubscription, int, error) {
+	return w.webhookRepo.ListSubscriptions(ctx, query)
+}
+
+// DeleteSubscription
+//
+// Deletes a subscription together with its pending deliveries and delivery log.
+func (w *Webhook) DeleteSubscription(ctx context.Context, id int64) error {
+	return w.webhookRepo.DeleteSubscription(ctx, id)
+}
+
+// ListDeliveries
+//
+// Fetches the delivery log of a subscription, newest first.
+func (w *Webhook) ListDeliveries(ctx context.Context, subscriptionID int64, query rest.Query) ([]entities.WebhookDelivery, int, error) {
+	if _, err := w.webhookRepo.GetSubscription(ctx, subscriptionID); err != nil {
+		return nil, 0, err
+	}
+
+	return w.webhookRepo.ListDeliveries(ctx, subscriptionID, query)
+}
+
+// RetryDelivery
+//
+// Schedules a delivery, typically a dead-lettered one, for immediate redelivery.
+func (w *Webhook) RetryDelivery(ctx context.Context, subscriptionID, deliveryID int64) (*entities.WebhookDelivery, error) {
+	return w.webhookRepo.Requeue(ctx, subscriptionID, deliveryID, time.Now())
+}
+
+// Publish
+//
+// Queues the event for every active subscription of the task's tenant accepting its type.
+// Implements events.Sink.
+func (w *Webhook) Publish(ctx context.Context, event entities.TaskEvent) error {
+	payload, err := json.Marshal(events.NewPayload(event))
+	if err != nil {
+		return err
+	}
+
+	_, err = w.webhookRepo.Enqueue(ctx, event.ID, event.Type, eventTenant(event), payload)
+	return err
+}
+
+// eventTenant returns the tenant of the event's task. Events are relayed outside
+// of any request, so the tenant cannot come from the context.
+func eventTenant(event entities.TaskEvent) string {
+	if event.Task == nil || event.Task.TenantID == "" {
+		return tenant.Default
+	}
+	return event.Task.TenantID
+}
+
+// DeliverDue
+//
+// Attempts every queued delivery that is due and returns the number of successful ones.
+// Failed attempts are rescheduled with exponential backoff, or dead-lettered once
+// MaxAttempts is reached.
+func (w *Webhook) DeliverDue(ctx context.Context, now time.Time) (int, error) {
+	// Lease the batch long enough for every attempt to time out
+	lease := time.Duration(w.settings.BatchSize+1) * w.settings.Timeout
+
+	deliveries, err := w.webhookRepo.ClaimDue(ctx, now, lease, w.settings.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		delivered int
+		errs      []error
+	)
+
+	for i := range deliveries {
+		if ctx.Err() != nil {
+			// Unfinished deliveries are picked up again once their lease expires
+			break
+		}
+
+		if w.deliver(ctx, &deliveries[i]) {
+			delivered++
+		}
+
+		if err := w.webhookRepo.SaveAttempt(ctx, &deliveries[i]); err != nil {
+			errs = append(errs, errors.Wrapf(err, "delivery %d", deliveries[i].ID))
+		}
+	}
+
+	return delivered, errors.Join(errs...)
+}
+
+// deliver
+//
+// Sends a single delivery and records the outcome on it. Returns true on success.
+func (w *Webhook) deliver(ctx context.Context, d *entities.WebhookDelivery) bool {
+	sentAt := time.Now()
+
+	status, err := w.client.Send(ctx, webhook.Request{
+		URL:        d.URL,
+		Secret:     d.Secret,
+		Event:      string(d.EventType),
+		DeliveryID: strconv.FormatInt(d.ID, 10),
+		Body:       d.Payload,
+		SentAt:     sentAt,
+	})
+
+	d.Attempts++
+	d.ResponseStatus = status
+
+	if err == nil {
+		d.Status = entities.WebhookDeliveryDelivered
+		d.LastError = ""
+		d.DeliveredAt = &sentAt
+		return true
+	}
+
+	d.LastError = err.Error()
+	if d.Attempts >= w.settings.MaxAttempts {
+		d.Status = entities.WebhookDeliveryDead
+		return false
+	}
+
+	d.Status = entities.WebhookDeliveryPending
+	d.NextAttemptAt = sentAt.Add(retry.Backoff(d.Attempts, w.settings.BaseBackoff, w.settings.MaxBackoff))
+	return false
+}
+
+// validateSubscription
+//
+// Ensures the subscription URL is an absolute http(s) URL, not targeting localhost or
+// an internal IP address unless AllowPrivateAddresses is set, and every filtered event
+// is known. Host names resolving to internal addresses are refused on delivery.
+func (w *Webhook) validateSubscription(subscription *entities.WebhookSubscription) error {
+	u, err := url.Parse(subscription.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ErrInvalidWebhookURL
+	}
+	if !w.settings.AllowPrivateAddresses {
+		if err := webhook.CheckHost(u.Hostname()); err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidWebhookURL, err)
+		}
+	}
+
+	for _, event := range subscription.Events {
+		if !event.IsValid() {
+			return errors.Wrapf(ErrInvalidWebhookEvent, "%q", event)
+		}
+	}
+
+	return nil
+}
+
+// generateSecret
+//
+// Returns a random 32 byte hex encoded signing secret.
+func generateSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(secret), nil
+}
@@ -0,0 +1,164 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"task-manager/internal/entities"
+	"task-manager/internal/events"
+	repoMock "task-manager/internal/repository/mock"
+	"task-manager/internal/service"
+	"task-manager/internal/tenant"
+	"task-manager/pkg/webhook"
+	"testing"
+	"time"
+)
+
+// TestDeliverDue verifies that deliveries are signed, and that failures are retried
+// with backoff until they are dead-lettered.
+func TestDeliverDue(t *testing.T) {
+	now := time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC)
+	body := []byte(`{"id":"evt-1","type":"task.created"}`)
+
+	var received []*http.Request
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload, _ := io.ReadAll(r.Body)
+		timestamp, _ := strconv.ParseInt(r.Header.Get(webhook.HeaderTimestamp), 10, 64)
+		assert.Equal(t, body, payload)
+		assert.True(t, webhook.Verify("s3cret", timestamp, payload, r.Header.Get(webhook.HeaderSignature)))
+
+		received = append(received, r)
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(receiver.Close)
+
+	deliveries := []entities.WebhookDelivery{
+		{ID: 1, EventType: entities.TaskEventCreated, Payload: body, URL: receiver.URL + "/ok", Secret: "s3cret", Status: entities.WebhookDeliveryPending},
+		{ID: 2, EventType: entities.TaskEventCreated, Payload: body, URL: receiver.URL + "/fail", Secret: "s3cret", Status: entities.WebhookDeliveryPending},
+		{ID: 3, EventType: entities.TaskEventCreated, Payload: body, URL: receiver.URL + "/fail", Secret: "s3cret", Status: entities.WebhookDeliveryPending, Attempts: 2},
+	}
+
+	saved := map[int64]entities.WebhookDelivery{}
+	repo := &repoMock.MockWebhookRepository{}
+	repo.On("ClaimDue", mock.Anything, now, mock.Anything, 10).Return(deliveries, nil)
+	repo.On("SaveAttempt", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			d := args.Get(1).(*entities.WebhookDelivery)
+			saved[d.ID] = *d
+		}).
+		Return(nil)
+
+	svc := service.NewWebhookService(repo, service.WebhookSettings{
+		MaxAttempts: 3,
+		BaseBackoff: time.Minute,
+		BatchSize:   10,
+
+		AllowPrivateAddresses: true,
+	})
+
+	delivered, err := svc.DeliverDue(context.Background(), now)
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, delivered)
+	require.Len(t, received, 3)
+	assert.Equal(t, string(entities.TaskEventCreated), received[0].Header.Get(webhook.HeaderEvent))
+	assert.Equal(t, "1", received[0].Header.Get(webhook.HeaderDelivery))
+
+	ok := saved[1]
+	assert.Equal(t, entities.WebhookDeliveryDelivered, ok.Status)
+	assert.Equal(t, 1, ok.Attempts)
+	assert.Equal(t, http.StatusNoContent, ok.ResponseStatus)
+	assert.NotNil(t, ok.DeliveredAt)
+
+	retry := saved[2]
+	assert.Equal(t, entities.WebhookDeliveryPending, retry.Status)
+	assert.Equal(t, 1, retry.Attempts)
+	assert.Equal(t, http.StatusInternalServerError, retry.ResponseStatus)
+	assert.NotEmpty(t, retry.LastError)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), retry.NextAttemptAt, 5*time.Second)
+
+	dead := saved[3]
+	assert.Equal(t, entities.WebhookDeliveryDead, dead.Status)
+	assert.Equal(t, 3, dead.Attempts)
+}
+
+// TestPublishWebhookPayload verifies the payload queued for a status change.
+func TestPublishWebhookPayload(t *testing.T) {
+	occurredAt := time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC)
+	event := entities.TaskEvent{
+		ID:             "evt-1",
+		Type:           entities.TaskEventStatusChanged,
+		TaskID:         42,
+		Task:           &entities.Task{ID: 42, Title: "Ship", Status: entities.TaskStatusDone},
+		PreviousStatus: entities.TaskStatusInProgress,
+		OccurredAt:     occurredAt,
+	}
+
+	var payload events.Payload
+	repo := &repoMock.MockWebhookRepository{}
+	repo.On("Enqueue", mock.Anything, "evt-1", entities.TaskEventStatusChanged, tenant.Default, mock.Anything).
+		Run(func(args mock.Arguments) {
+			require.NoError(t, json.Unmarshal(args.Get(4).([]byte), &payload))
+		}).
+		Return(2, nil)
+
+	svc := service.NewWebhookService(repo, service.WebhookSettings{})
+
+	require.NoError(t, svc.Publish(context.Background(), event))
+	assert.Equal(t, "evt-1", payload.ID)
+	assert.Equal(t, occurredAt, payload.OccurredAt)
+	assert.Equal(t, int64(42), payload.Data.TaskID)
+	assert.Equal(t, entities.TaskStatusInProgress, payload.Data.PreviousStatus)
+	require.NotNil(t, payload.Data.Task)
+	assert.Equal(t, entities.TaskStatusDone, payload.Data.Task.Status)
+}
+
+// TestPublishWebhookTenant verifies events are queued for the subscriptions of their task's tenant.
+func TestPublishWebhookTenant(t *testing.T) {
+	event := entities.TaskEvent{
+		ID:     "evt-2",
+		Type:   entities.TaskEventCreated,
+		TaskID: 7,
+		Task:   &entities.Task{ID: 7, TenantID: "acme"},
+	}
+
+	repo := &repoMock.MockWebhookRepository{}
+	repo.On("Enqueue", mock.Anything, "evt-2", entities.TaskEventCreated, "acme", mock.Anything).Return(1, nil)
+
+	svc := service.NewWebhookService(repo, service.WebhookSettings{})
+
+	require.NoError(t, svc.Publish(context.Background(), event))
+	repo.AssertExpectations(t)
+}
+
+// TestCreateSubscriptionValidation verifies URL and event filter validation.
+func TestCreateSubscriptionValidation(t *testing.T) {
+	repo := &repoMock.MockWebhookRepository{}
+	svc := service.NewWebhookService(repo, service.WebhookSettings{})
+
+	_, err := svc.CreateSubscription(context.Background(), &entities.WebhookSubscription{URL: "ftp://example.com"})
+	assert.ErrorIs(t, err, service.ErrInvalidWebhookURL)
+
+	_, err = svc.CreateSubscription(context.Background(), &entities.WebhookSubscription{
+		URL:    "https://example.com/hook",
+		Events: []entities.TaskEventType{"task.archived"},
+	})
+	assert.ErrorIs(t, err, service.ErrInvalidWebhookEvent)
+
+	for _, url := range []string{"http://localhost:8080/hook", "http://127.0.0.1/hook", "http://[::1]/hook", "http://169.254.169.254/latest/meta-data", "http://10.0.0.1/hook"} {
+		_, err = svc.CreateSubscription(context.Background(), &entities.WebhookSubscription{URL: url})
+		assert.ErrorIs(t, err, service.ErrInvalidWebhookURL, url)
+	}
+
+	repo.AssertNotCalled(t, "CreateSubscription", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,42 @@
+package stream
+
+import (
+	"context"
+	"fmt"
+	"task-manager/internal/authz"
+
+	"github.com/cockroachdb/errors"
+)
+
+// ErrForbidden is returned by an Authorizer that denies a subscription.
+var ErrForbidden = errors.New("subscription not allowed")
+
+// Authorizer decides whether the caller of ctx may follow the tasks matched by a
+// filter. It is asked for every subscription of a connection, so a connection can
+// hold subscriptions with different permissions.
+type Authorizer interface {
+	Authorize(ctx context.Context, filter Filter) error
+}
+
+// AuthorizerFunc adapts a function to the Authorizer interface.
+type AuthorizerFunc func(ctx context.Context, filter Filter) error
+
+// Authorize calls f(ctx, filter).
+func (f AuthorizerFunc) Authorize(ctx context.Context, filter Filter) error {
+	return f(ctx, filter)
+}
+
+// AllowAll is the Authorizer that allows every subscription.
+var AllowAll = AuthorizerFunc(func(context.Context, Filter) error { return nil })
+
+// PolicyAuthorizer returns the Authorizer allowing the subscriptions of the callers
+// policy grants task:read on any task. Subscriptions follow tasks the caller is not
+// necessarily assigned to, so task:read on their own tasks is not enough.
+func PolicyAuthorizer(policy authz.Authorizer) Authorizer {
+	return AuthorizerFunc(func(ctx context.Context, _ Filter) error {
+		if err := policy.Authorize(ctx, authz.ActionTaskRead, nil); err != nil {
+			return fmt.Errorf("%w: %w", ErrForbidden, err)
+		}
+		return nil
+	})
+}
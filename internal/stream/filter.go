@@ -0,0 +1,130 @@
+package stream
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"task-manager/internal/entities"
+	"task-manager/internal/tenant"
+	"task-manager/pkg/rest"
+)
+
+// ErrInvalidFilter is returned when a user ID filter is not a comma separated list of integers.
+var ErrInvalidFilter = errors.New("assignee_id and watcher_id must be comma separated integers")
+
+// Filter selects the events a subscriber receives, using the filters of the task list:
+// status, title, assignee_id and watcher_id (comma separated user IDs, any of them).
+// Filters bound to a tenant (WithTenant) only match the tasks of that tenant.
+type Filter struct {
+	tenant    string
+	status    entities.TaskStatus
+	title     string
+	assignees []int64
+	watchers  []int64
+}
+
+// NewFilter builds a Filter from the query filters. Unknown filters are ignored,
+// like in the task list.
+func NewFilter(filter rest.Filter) (Filter, error) {
+	f := Filter{
+		status: entities.TaskStatus(filter["status"]),
+		title:  filter["title"],
+	}
+
+	var err error
+	if f.assignees, err = parseIDs(filter["assignee_id"]); err != nil {
+		return f, err
+	}
+	if f.watchers, err = parseIDs(filter["watcher_id"]); err != nil {
+		return f, err
+	}
+
+	return f, nil
+}
+
+// WithTenant returns a copy of the filter only matching the tasks of the tenant.
+func (f Filter) WithTenant(id string) Filter {
+	f.tenant = id
+	return f
+}
+
+// Tenant returns the tenant of the filter, empty if any.
+func (f Filter) Tenant() string { return f.tenant }
+
+// Status returns the filtered status, empty if any.
+func (f Filter) Status() entities.TaskStatus { return f.status }
+
+// AssigneeIDs returns the filtered assignees, empty if any.
+func (f Filter) AssigneeIDs() []int64 { return f.assignees }
+
+// WatcherIDs returns the filtered watchers, empty if any.
+func (f Filter) WatcherIDs() []int64 { return f.watchers }
+
+// Match reports whether the event passes the filter. Events are matched on the task
+// state they carry; a status change also matches on the previous status, so
+// subscribers learn about tasks leaving the filtered status.
+func (f Filter) Match(event entities.TaskEvent) bool {
+	task := event.Task
+	if f.tenant != "" && (task == nil || tenantOf(task) != f.tenant) {
+		return false
+	}
+	if task == nil {
+		return f.status == "" && f.title == "" && len(f.assignees) == 0 && len(f.watchers) == 0
+	}
+
+	if f.status != "" && task.Status != f.status &&
+		(event.Type != entities.TaskEventStatusChanged || event.PreviousStatus != f.status) {
+		return false
+	}
+	if f.title != "" && task.Title != f.title {
+		return false
+	}
+	if len(f.assignees) > 0 && !containsAny(task.AssigneeIDs, f.assignees) {
+		return false
+	}
+	if len(f.watchers) > 0 && !containsAny(task.WatcherIDs, f.watchers) {
+		return false
+	}
+
+	return true
+}
+
+// tenantOf returns the tenant of the task. Events recorded before tenants existed
+// carry none, their tasks belong to the default tenant.
+func tenantOf(task *entities.Task) string {
+	if task.TenantID == "" {
+		return tenant.Default
+	}
+	return task.TenantID
+}
+
+// parseIDs parses a comma separated list of user IDs.
+func parseIDs(value string) ([]int64, error) {
+	var ids []int64
+	for _, part := range strings.Split(value, ",") {
+		if part = strings.TrimSpace(part); part == "" {
+			continue
+		}
+
+		id, err := strconv.ParseInt(part, 10, 64)
+		if err != nil {
+			return nil, ErrInvalidFilter
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+// containsAny reports whether ids contains any of wanted.
+func containsAny(ids, wanted []int64) bool {
+	for _, id := range ids {
+		for _, w := range wanted {
+			if id == w {
+				return true
+			}
+		}
+	}
+	return false
+}
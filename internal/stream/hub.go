@@ -0,0 +1,206 @@
+package stream
+
+import (
+	"context"
+	"strconv"
+	"sync"
+
+	"task-manager/internal/entities"
+)
+
+// Defaults applied to zero Settings fields.
+const (
+	DefaultReplaySize   = 1000
+	DefaultClientBuffer = 64
+)
+
+// Settings configures a Hub.
+type Settings struct {
+	ReplaySize   int // Recent events kept for Last-Event-ID resume
+	ClientBuffer int // Events queued per subscriber before it is dropped as too slow
+}
+
+// Event is a task event together with its position in the stream.
+type Event struct {
+	ID    string             // Stream position, sent as the SSE event ID
+	Event entities.TaskEvent // The task event
+	seq   int64
+}
+
+// Hub fans the task events written to the outbox out to live subscribers, such as
+// SSE connections, and keeps a bounded buffer of recent events so reconnecting
+// clients can resume where they left off.
+//
+// Event IDs are the outbox sequences of the events. Every instance follows the
+// whole outbox (see Tailer), so an ID can be resumed on any instance that still
+// buffers the events following it.
+type Hub struct {
+	mu       sync.Mutex
+	settings Settings
+	seq      int64   // sequence of the last published event
+	floor    int64   // events after this sequence are published or buffered
+	replay   []Event // ring buffer of the last ReplaySize events
+	next     int     // ring buffer write position
+	subs     map[*Subscription]struct{}
+	closed   bool
+}
+
+// Subscription receives the events published after it was created.
+type Subscription struct {
+	events  chan Event
+	after   int64 // events up to this sequence were already sent to the client
+	dropped bool
+}
+
+// Events returns the channel delivering the events. It is closed when the
+// subscription is canceled, dropped for falling behind or the Hub is closed.
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+// NewHub returns an empty Hub. Zero settings fall back to the defaults.
+func NewHub(settings Settings) *Hub {
+	if settings.ReplaySize <= 0 {
+		settings.ReplaySize = DefaultReplaySize
+	}
+	if settings.ClientBuffer <= 0 {
+		settings.ClientBuffer = DefaultClientBuffer
+	}
+
+	return &Hub{
+		settings: settings,
+		replay:   make([]Event, 0, settings.ReplaySize),
+		subs:     make(map[*Subscription]struct{}),
+	}
+}
+
+// Publish appends the event to the stream and hands it to every subscriber.
+// Events must be published in sequence order; an event at or before the last
+// published sequence is ignored. Subscribers whose buffer is full are dropped
+// rather than blocking the tailer; they reconnect and resume from the replay buffer.
+func (h *Hub) Publish(_ context.Context, event entities.OutboxEvent) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if event.Sequence <= h.seq {
+		return nil
+	}
+	h.seq = event.Sequence
+	e := Event{ID: strconv.FormatInt(event.Sequence, 10), Event: event.TaskEvent, seq: event.Sequence}
+
+	if len(h.replay) < h.settings.ReplaySize {
+		h.replay = append(h.replay, e)
+	} else {
+		h.floor = h.replay[h.next].seq
+		h.replay[h.next] = e
+	}
+	h.next = (h.next + 1) % h.settings.ReplaySize
+
+	for sub := range h.subs {
+		if e.seq <= sub.after {
+			continue
+		}
+		select {
+		case sub.events <- e:
+		default:
+			sub.dropped = true
+			h.remove(sub)
+		}
+	}
+
+	return nil
+}
+
+// Subscribe registers a new subscriber. If lastEventID is set, the buffered events
+// following it are returned for replay; resumed is false if they are no longer (or
+// were never) available, in which case the client has to reload its state. An ID
+// this Hub has not reached yet, e.g. one sent by an instance ahead of it, resumes
+// with the events following it.
+// Replay and registration are atomic, so no event is missed in between.
+func (h *Hub) Subscribe(lastEventID string) (sub *Subscription, replay []Event, resumed bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	sub = &Subscription{events: make(chan Event, h.settings.ClientBuffer)}
+	if h.closed {
+		close(sub.events)
+		return sub, nil, false
+	}
+	h.subs[sub] = struct{}{}
+
+	if lastEventID == "" {
+		return sub, nil, true
+	}
+
+	last, err := strconv.ParseInt(lastEventID, 10, 64)
+	if err != nil || last < h.floor {
+		return sub, nil, false
+	}
+	sub.after = last
+
+	for _, e := range h.ordered() {
+		if e.seq > last {
+			replay = append(replay, e)
+		}
+	}
+
+	return sub, replay, true
+}
+
+// start makes the Hub stream the events following sequence, which it has no
+// replay for. Called by the Tailer before publishing its first event.
+func (h *Hub) start(sequence int64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if sequence > h.seq {
+		h.seq = sequence
+		h.floor = sequence
+		h.replay = h.replay[:0]
+		h.next = 0
+	}
+}
+
+// Unsubscribe cancels the subscription and closes its channel.
+func (h *Hub) Unsubscribe(sub *Subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.remove(sub)
+}
+
+// Dropped reports whether the subscription was canceled because it fell behind.
+func (h *Hub) Dropped(sub *Subscription) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return sub.dropped
+}
+
+// Close closes every subscription, ending the streams, e.g. on shutdown.
+// Later subscriptions are closed right away.
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.closed = true
+	for sub := range h.subs {
+		h.remove(sub)
+	}
+}
+
+// remove unregisters the subscription and closes its channel. Callers hold h.mu.
+func (h *Hub) remove(sub *Subscription) {
+	if _, ok := h.subs[sub]; ok {
+		delete(h.subs, sub)
+		close(sub.events)
+	}
+}
+
+// ordered returns the buffered events, oldest first. Callers hold h.mu.
+func (h *Hub) ordered() []Event {
+	if len(h.replay) < h.settings.ReplaySize {
+		return h.replay
+	}
+	return append(append([]Event{}, h.replay[h.next:]...), h.replay[:h.next]...)
+}
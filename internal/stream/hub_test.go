@@ -0,0 +1,136 @@
+package stream_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"task-manager/internal/entities"
+	"task-manager/internal/stream"
+	"task-manager/internal/tenant"
+)
+
+// publish publishes an event per task, the task ID doubling as the outbox sequence.
+func publish(t *testing.T, hub *stream.Hub, taskIDs ...int64) {
+	t.Helper()
+	for _, id := range taskIDs {
+		require.NoError(t, hub.Publish(context.Background(), entities.OutboxEvent{
+			Sequence:  id,
+			TaskEvent: entities.TaskEvent{TaskID: id, Type: entities.TaskEventUpdated},
+		}))
+	}
+}
+
+func taskIDs(events []stream.Event) []int64 {
+	ids := make([]int64, 0, len(events))
+	for _, e := range events {
+		ids = append(ids, e.Event.TaskID)
+	}
+	return ids
+}
+
+func TestHub_ResumeFromReplayBuffer(t *testing.T) {
+	hub := stream.NewHub(stream.Settings{ReplaySize: 3})
+
+	sub, _, _ := hub.Subscribe("")
+	publish(t, hub, 1, 2)
+	first := <-sub.Events()
+	hub.Unsubscribe(sub)
+
+	publish(t, hub, 3, 4)
+
+	// Events 2, 3 and 4 are still buffered
+	_, replay, resumed := hub.Subscribe(first.ID)
+	assert.True(t, resumed)
+	assert.Equal(t, []int64{2, 3, 4}, taskIDs(replay))
+
+	// Event 2 was evicted by a later event
+	publish(t, hub, 5)
+	_, replay, resumed = hub.Subscribe(first.ID)
+	assert.False(t, resumed)
+	assert.Empty(t, replay)
+
+	// Another hub only resumes the IDs it buffers the events after
+	other := stream.NewHub(stream.Settings{})
+	publish(t, other, 1, 2, 3)
+	_, replay, resumed = other.Subscribe(first.ID)
+	assert.True(t, resumed)
+	assert.Equal(t, []int64{2, 3}, taskIDs(replay))
+	_, _, resumed = other.Subscribe("bogus")
+	assert.False(t, resumed)
+}
+
+func TestHub_ResumeAheadOfTheHub(t *testing.T) {
+	hub := stream.NewHub(stream.Settings{})
+	publish(t, hub, 1)
+
+	// The client already received event 2 from an instance ahead of this one
+	sub, replay, resumed := hub.Subscribe("2")
+	assert.True(t, resumed)
+	assert.Empty(t, replay)
+
+	publish(t, hub, 2, 3, 3)
+	assert.Equal(t, []int64{3}, taskIDs([]stream.Event{<-sub.Events()}))
+	assert.Empty(t, sub.Events())
+}
+
+func TestHub_DropsSlowSubscriber(t *testing.T) {
+	hub := stream.NewHub(stream.Settings{ClientBuffer: 2})
+
+	slow, _, _ := hub.Subscribe("")
+	publish(t, hub, 1, 2, 3)
+
+	var received []stream.Event
+	for e := range slow.Events() {
+		received = append(received, e)
+	}
+
+	assert.Equal(t, []int64{1, 2}, taskIDs(received))
+	assert.True(t, hub.Dropped(slow))
+
+	// The dropped subscriber resumes where it left off
+	_, replay, resumed := hub.Subscribe(received[1].ID)
+	assert.True(t, resumed)
+	assert.Equal(t, []int64{3}, taskIDs(replay))
+}
+
+func TestHub_CloseEndsSubscriptions(t *testing.T) {
+	hub := stream.NewHub(stream.Settings{})
+
+	sub, _, _ := hub.Subscribe("")
+	hub.Close()
+
+	_, ok := <-sub.Events()
+	assert.False(t, ok)
+	assert.False(t, hub.Dropped(sub))
+
+	late, _, _ := hub.Subscribe("")
+	_, ok = <-late.Events()
+	assert.False(t, ok)
+}
+
+func TestFilter_Match(t *testing.T) {
+	task := &entities.Task{ID: 1, Title: "Ship", Status: entities.TaskStatusDone, AssigneeIDs: []int64{7, 8}, WatcherIDs: []int64{9}}
+
+	filter, err := stream.NewFilter(map[string]string{"assignee_id": "3, 8", "status": "in_progress"})
+	require.NoError(t, err)
+
+	// The task left the filtered status
+	assert.True(t, filter.Match(entities.TaskEvent{Type: entities.TaskEventStatusChanged, Task: task, PreviousStatus: entities.TaskStatusInProgress}))
+	assert.False(t, filter.Match(entities.TaskEvent{Type: entities.TaskEventUpdated, Task: task}))
+
+	filter, err = stream.NewFilter(map[string]string{"watcher_id": "10"})
+	require.NoError(t, err)
+	assert.False(t, filter.Match(entities.TaskEvent{Type: entities.TaskEventUpdated, Task: task}))
+
+	_, err = stream.NewFilter(map[string]string{"watcher_id": "abc"})
+	assert.ErrorIs(t, err, stream.ErrInvalidFilter)
+
+	// Tasks without tenant belong to the default one
+	filter, err = stream.NewFilter(nil)
+	require.NoError(t, err)
+	assert.True(t, filter.WithTenant(tenant.Default).Match(entities.TaskEvent{Type: entities.TaskEventUpdated, Task: task}))
+	assert.False(t, filter.WithTenant("acme").Match(entities.TaskEvent{Type: entities.TaskEventUpdated, Task: task}))
+	assert.False(t, filter.WithTenant("acme").Match(entities.TaskEvent{Type: entities.TaskEventDeleted}))
+}
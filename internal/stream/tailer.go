@@ -0,0 +1,105 @@
+package stream
+
+import (
+	"context"
+	"time"
+
+	"task-manager/internal/entities"
+)
+
+// Defaults applied to zero TailSettings fields.
+const (
+	DefaultTailBatchSize  = 500
+	DefaultTailGapTimeout = 10 * time.Second
+)
+
+// Source lists the task events written to the outbox, see postgres.OutboxRepository.
+type Source interface {
+	LatestSequence(ctx context.Context) (int64, error)
+	ListAfter(ctx context.Context, sequence int64, limit int) ([]entities.OutboxEvent, error)
+}
+
+// TailSettings configures a Tailer.
+type TailSettings struct {
+	BatchSize  int           // Max events read per poll
+	GapTimeout time.Duration // How long a missing sequence is waited for before it is skipped
+}
+
+// Tailer feeds a Hub with the events of the outbox, in sequence order. It reads
+// the outbox without claiming events, so every instance streams every event no
+// matter which instance relays it, and the event IDs are the same everywhere.
+//
+// Sequences are allocated when an event is written but become visible when its
+// transaction commits, so a missing sequence may still show up. The Tailer stops
+// before it for up to GapTimeout, then skips it: it was rolled back, or belongs
+// to a transaction that ran so long its event is not streamed.
+type Tailer struct {
+	hub      *Hub
+	source   Source
+	settings TailSettings
+	started  bool
+	cursor   int64               // sequence of the last published event
+	gaps     map[int64]time.Time // first time each event was seen behind a missing sequence
+}
+
+// NewTailer returns a Tailer publishing the events of source to hub. Zero settings
+// fall back to the defaults.
+func NewTailer(hub *Hub, source Source, settings TailSettings) *Tailer {
+	if settings.BatchSize <= 0 {
+		settings.BatchSize = DefaultTailBatchSize
+	}
+	if settings.GapTimeout <= 0 {
+		settings.GapTimeout = DefaultTailGapTimeout
+	}
+
+	return &Tailer{
+		hub:      hub,
+		source:   source,
+		settings: settings,
+		gaps:     make(map[int64]time.Time),
+	}
+}
+
+// Poll publishes the events written since the previous poll and returns their
+// number. The first poll starts after the latest event: earlier ones are not replayed.
+func (t *Tailer) Poll(ctx context.Context, now time.Time) (int, error) {
+	if !t.started {
+		latest, err := t.source.LatestSequence(ctx)
+		if err != nil {
+			return 0, err
+		}
+		t.hub.start(latest)
+		t.cursor, t.started = latest, true
+	}
+
+	events, err := t.source.ListAfter(ctx, t.cursor, t.settings.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	published, previous, blocked := 0, t.cursor, false
+	for i := range events {
+		event := events[i]
+		if event.Sequence > previous+1 {
+			// Every event behind a gap is timed, so the gaps of a batch expire together
+			seen, ok := t.gaps[event.Sequence]
+			if !ok {
+				t.gaps[event.Sequence], seen = now, now
+			}
+			blocked = blocked || now.Sub(seen) < t.settings.GapTimeout
+		}
+		previous = event.Sequence
+		if blocked {
+			continue
+		}
+
+		if err := t.hub.Publish(ctx, event); err != nil {
+			return published, err
+		}
+		t.cursor = event.Sequence
+		delete(t.gaps, event.Sequence)
+		published++
+	}
+
+	return published, nil
+}
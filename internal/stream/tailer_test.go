@@ -0,0 +1,64 @@
+package stream_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"task-manager/internal/entities"
+	repository "task-manager/internal/repository/mock"
+	"task-manager/internal/stream"
+)
+
+func outboxEvents(sequences ...int64) []entities.OutboxEvent {
+	events := make([]entities.OutboxEvent, 0, len(sequences))
+	for _, sequence := range sequences {
+		events = append(events, entities.OutboxEvent{
+			Sequence:  sequence,
+			TaskEvent: entities.TaskEvent{TaskID: sequence, Type: entities.TaskEventUpdated},
+		})
+	}
+	return events
+}
+
+func TestTailer_WaitsForMissingSequences(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	source := new(repository.MockOutboxRepository)
+	source.On("LatestSequence", ctx).Return(int64(10), nil).Once()
+	source.On("ListAfter", ctx, int64(10), 100).Return(outboxEvents(11, 13, 15), nil).Once()
+	source.On("ListAfter", ctx, int64(11), 100).Return(outboxEvents(13, 15), nil).Once()
+	source.On("ListAfter", ctx, int64(11), 100).Return(outboxEvents(12, 13, 15), nil).Once()
+
+	hub := stream.NewHub(stream.Settings{})
+	tailer := stream.NewTailer(hub, source, stream.TailSettings{BatchSize: 100, GapTimeout: time.Second})
+	sub, _, _ := hub.Subscribe("")
+
+	// 12 may still be committed: 13 and 15 wait
+	published, err := tailer.Poll(ctx, now)
+	require.NoError(t, err)
+	assert.Equal(t, 1, published)
+	published, err = tailer.Poll(ctx, now.Add(500*time.Millisecond))
+	require.NoError(t, err)
+	assert.Equal(t, 0, published)
+
+	// 12 shows up, 14 is given up on: 15 waited since the first poll
+	published, err = tailer.Poll(ctx, now.Add(time.Second))
+	require.NoError(t, err)
+	assert.Equal(t, 3, published)
+
+	hub.Close()
+	var received []stream.Event
+	for e := range sub.Events() {
+		received = append(received, e)
+	}
+	assert.Equal(t, []int64{11, 12, 13, 15}, taskIDs(received))
+	assert.Equal(t, "15", received[3].ID)
+	source.AssertExpectations(t)
+
+	// Events before the first poll cannot be resumed
+	_, _, resumed := hub.Subscribe("10")
+	assert.False(t, resumed)
+}
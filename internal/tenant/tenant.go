@@ -0,0 +1,116 @@
+// Package tenant isolates the data of the teams sharing a deployment.
+//
+// The transport layer resolves the tenant of every request (from the principal or
+// a header) and stores it in the context; the repositories scope their queries to
+// the tenant of the context they are called with.
+package tenant
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"task-manager/internal/auth"
+	"task-manager/pkg/logger"
+
+	"github.com/cockroachdb/errors"
+)
+
+// Default is the tenant of the requests that name none, and of the rows created
+// before tenants existed.
+const Default = "default"
+
+// Header is the HTTP header and, lower-cased, the gRPC metadata key naming the
+// tenant of a request.
+const Header = "X-Tenant-ID"
+
+// ErrInvalidTenant is returned when a tenant ID is malformed.
+var ErrInvalidTenant = errors.New("tenant IDs are 1 to 64 lowercase letters, digits, dashes or underscores")
+
+// ErrForeignTenant is returned when a principal requests a tenant it does not belong to.
+var ErrForeignTenant = errors.New("credentials are not valid for the tenant")
+
+// validID matches the tenant IDs, safe in headers, URLs and logs.
+var validID = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,63}$`)
+
+// Validate returns an error wrapping ErrInvalidTenant if id is malformed.
+func Validate(id string) error {
+	if !validID.MatchString(id) {
+		return fmt.Errorf("%w: %q", ErrInvalidTenant, id)
+	}
+
+	return nil
+}
+
+// Resolve
+//
+// Returns the tenant of a request made by principal, nil if it is not
+// authenticated, naming the tenant requested, empty if none:
+//
+//   - unauthenticated requests, only accepted when authentication is disabled or
+//     done by a gateway in front of the API, get the requested tenant;
+//   - principals get their own tenant, the default one if they carry none. Only
+//     users with the platform admin role may request another one, the admins of
+//     a tenant are bound to it like its other users.
+func Resolve(principal *auth.Principal, requested string) (string, error) {
+	if requested != "" {
+		if err := Validate(requested); err != nil {
+			return "", err
+		}
+	}
+
+	if principal == nil {
+		if requested == "" {
+			return Default, nil
+		}
+		return requested, nil
+	}
+
+	bound := principal.TenantID
+	if bound == "" {
+		bound = Default
+	}
+	if err := Validate(bound); err != nil {
+		return "", err
+	}
+
+	switch {
+	case requested == "" || requested == bound:
+		return bound, nil
+	case principal.Method != auth.MethodAPIKey && principal.HasRole(auth.RolePlatformAdmin):
+		return requested, nil
+	default:
+		return "", fmt.Errorf("%w %q", ErrForeignTenant, requested)
+	}
+}
+
+type tenantKey struct{}
+
+// WithID returns a copy of ctx carrying the tenant id.
+func WithID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, id)
+}
+
+// FromContext returns the tenant of ctx, if it was resolved.
+func FromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(tenantKey{}).(string)
+	return id, ok && id != ""
+}
+
+// LogAttrs returns the tenant of ctx, logged with the records of the requests once
+// their tenant is resolved (see logger.WithContextAttrs).
+func LogAttrs(ctx context.Context) []slog.Attr {
+	if id, ok := FromContext(ctx); ok {
+		return []slog.Attr{slog.String(logger.TenantKey, id)}
+	}
+	return nil
+}
+
+// Of returns the tenant of ctx, Default if none was resolved.
+func Of(ctx context.Context) string {
+	if id, ok := FromContext(ctx); ok {
+		return id
+	}
+
+	return Default
+}
@@ -0,0 +1,65 @@
+package tenant_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"log/slog"
+	"task-manager/internal/auth"
+	"task-manager/internal/tenant"
+	"testing"
+)
+
+// TestResolve tests the tenant of the requests, by principal and requested tenant.
+func TestResolve(t *testing.T) {
+	user := &auth.Principal{Subject: "u", UserID: 7, TenantID: "acme", Method: auth.MethodJWT}
+	legacy := &auth.Principal{Subject: "l", UserID: 7, Method: auth.MethodJWT}
+	admin := &auth.Principal{Subject: "a", UserID: 1, TenantID: "acme", Roles: []string{auth.RoleAdmin}, Method: auth.MethodJWT}
+	operator := &auth.Principal{Subject: "o", UserID: 2, TenantID: "acme", Roles: []string{auth.RolePlatformAdmin}, Method: auth.MethodJWT}
+	apiKey := &auth.Principal{Subject: "api-key:1", TenantID: "acme", Roles: []string{auth.RolePlatformAdmin}, Method: auth.MethodAPIKey}
+
+	tests := []struct {
+		name      string
+		principal *auth.Principal
+		requested string
+		want      string
+		err       error
+	}{
+		{"anonymous without header", nil, "", tenant.Default, nil},
+		{"anonymous with header", nil, "globex", "globex", nil},
+		{"anonymous with invalid header", nil, "Not Valid", "", tenant.ErrInvalidTenant},
+		{"user without header", user, "", "acme", nil},
+		{"user with own tenant", user, "acme", "acme", nil},
+		{"user with foreign tenant", user, "globex", "", tenant.ErrForeignTenant},
+		{"user without tenant", legacy, "", tenant.Default, nil},
+		{"user without tenant with foreign tenant", legacy, "globex", "", tenant.ErrForeignTenant},
+		{"admin with foreign tenant", admin, "globex", "", tenant.ErrForeignTenant},
+		{"platform admin with foreign tenant", operator, "globex", "globex", nil},
+		{"api key with foreign tenant", apiKey, "globex", "", tenant.ErrForeignTenant},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tenant.Resolve(tt.principal, tt.requested)
+
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+// TestOf_WithoutTenant_ShouldReturnDefault tests the tenant of the contexts that were
+// not resolved, such as the ones of the background jobs.
+func TestOf_WithoutTenant_ShouldReturnDefault(t *testing.T) {
+	assert.Equal(t, tenant.Default, tenant.Of(context.Background()))
+	assert.Equal(t, "acme", tenant.Of(tenant.WithID(context.Background(), "acme")))
+}
+
+// TestLogAttrs tests the tenant logged with the records of the resolved requests.
+func TestLogAttrs(t *testing.T) {
+	assert.Empty(t, tenant.LogAttrs(context.Background()))
+	assert.Equal(t, []slog.Attr{slog.String("tenant", "acme")}, tenant.LogAttrs(tenant.WithID(context.Background(), "acme")))
+}
@@ -17,7 +17,7 @@ var (
 
 func TruncateTables(t *testing.T) {
 	dbTest = CreateTestDatabaseConnection()
-	tables := []string{"tasks"}
+	tables := []string{"idempotency_keys", "api_keys", "outbox", "webhook_deliveries", "webhook_subscriptions", "task_reminders", "task_watchers", "task_assignees", "tasks", "users"}
 
 	for _, tbl := range tables {
 		_, err := dbTest.ExecContext(context.Background(),
@@ -19,3 +19,31 @@ func InitGlobalTaskMetrics() *monitoring.TaskMetrics {
 
 	return globalTaskMetrics
 }
+
+var (
+	initJobMetricsOnce sync.Once
+	globalJobMetrics   *monitoring.JobMetrics
+)
+
+// InitGlobalJobMetrics initializes the background job metrics only once and returns the instance
+func InitGlobalJobMetrics() *monitoring.JobMetrics {
+	initJobMetricsOnce.Do(func() {
+		globalJobMetrics = monitoring.InitJobMetrics(monitoring.NewMetricsManager())
+	})
+
+	return globalJobMetrics
+}
+
+var (
+	initGRPCMetricsOnce sync.Once
+	globalGRPCMetrics   *monitoring.GRPCMetrics
+)
+
+// InitGlobalGRPCMetrics initializes the gRPC server metrics only once and returns the instance
+func InitGlobalGRPCMetrics() *monitoring.GRPCMetrics {
+	initGRPCMetricsOnce.Do(func() {
+		globalGRPCMetrics = monitoring.InitGRPCMetrics(monitoring.NewMetricsManager())
+	})
+
+	return globalGRPCMetrics
+}
@@ -0,0 +1,243 @@
+// Package validation validates the request bodies against the JSON Schemas of the
+// API, loaded once at startup from <version>/<name>_schema.json files (see package
+// docs/schema). Every violation is reported, with the JSON pointer of its value.
+package validation
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/santhosh-tekuri/jsonschema/v6/kind"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"task-manager/pkg/rest"
+)
+
+// schemaSuffix ends the names of the schema files.
+const schemaSuffix = "_schema.json"
+
+// ErrUnknownSchema is returned when validating against a schema that was not loaded.
+var ErrUnknownSchema = errors.New("unknown schema")
+
+// ErrInvalidJSON is returned when the body is not JSON.
+var ErrInvalidJSON = errors.New("invalid JSON")
+
+// printer formats the messages of the violations without a message of their own.
+var printer = message.NewPrinter(language.English)
+
+// pointerEscaper escapes the reference tokens of a JSON pointer.
+var pointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+// Registry holds the compiled schemas by version and name.
+type Registry struct {
+	schemas map[string]*jsonschema.Schema
+}
+
+// Load compiles the <version>/<name>_schema.json files of fsys. Formats, such as
+// date-time, are asserted.
+func Load(fsys fs.FS) (*Registry, error) {
+	files, err := fs.Glob(fsys, "*/*"+schemaSuffix)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, errors.New("no schema found")
+	}
+
+	compiler := jsonschema.NewCompiler()
+	compiler.AssertFormat()
+	for _, file := range files {
+		data, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, err
+		}
+		doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse schema %s: %w", file, err)
+		}
+		if err := compiler.AddResource(schemaURL(file), doc); err != nil {
+			return nil, fmt.Errorf("failed to add schema %s: %w", file, err)
+		}
+	}
+
+	registry := &Registry{schemas: make(map[string]*jsonschema.Schema, len(files))}
+	for _, file := range files {
+		sch, err := compiler.Compile(schemaURL(file))
+		if err != nil {
+			return nil, fmt.Errorf("failed to compile schema %s: %w", file, err)
+		}
+		registry.schemas[strings.TrimSuffix(file, schemaSuffix)] = sch
+	}
+
+	return registry, nil
+}
+
+// MustLoad is like Load but panics if the schemas can't be loaded, for the embedded
+// schemas.
+func MustLoad(fsys fs.FS) *Registry {
+	registry, err := Load(fsys)
+	if err != nil {
+		panic(err)
+	}
+	return registry
+}
+
+// schemaURL returns the URL identifying a schema file in the compiler.
+func schemaURL(file string) string {
+	return "mem:///" + file
+}
+
+// Has reports whether the schema of the given version and name is loaded.
+func (r *Registry) Has(version, name string) bool {
+	_, ok := r.schemas[path.Join(version, name)]
+	return ok
+}
+
+// Validate validates the JSON body against the schema of the given version and name.
+// Returns Violations if the body is invalid, ErrInvalidJSON if it is not JSON.
+func (r *Registry) Validate(version, name string, body []byte) error {
+	sch, ok := r.schemas[path.Join(version, name)]
+	if !ok {
+		return fmt.Errorf("%w: %s/%s", ErrUnknownSchema, version, name)
+	}
+
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(body))
+	if err != nil {
+		return &invalidJSONError{err: err}
+	}
+
+	err = sch.Validate(doc)
+	var ve *jsonschema.ValidationError
+	if !errors.As(err, &ve) {
+		return err
+	}
+
+	var violations Violations
+	collect(ve, &violations)
+	sort.SliceStable(violations, func(i, j int) bool {
+		return violations[i].Pointer < violations[j].Pointer
+	})
+	return violations
+}
+
+// invalidJSONError is ErrInvalidJSON, with the message of the parser.
+type invalidJSONError struct {
+	err error
+}
+
+func (e *invalidJSONError) Error() string {
+	return e.err.Error()
+}
+
+func (e *invalidJSONError) Is(target error) bool {
+	return target == ErrInvalidJSON
+}
+
+func (e *invalidJSONError) Unwrap() error {
+	return e.err
+}
+
+// -------------------------------
+// Violations
+// -------------------------------
+
+// Violations lists the values of a body violating its schema.
+type Violations []rest.FieldError
+
+// Error lists the violations.
+func (v Violations) Error() string {
+	messages := make([]string, len(v))
+	for i, violation := range v {
+		messages[i] = violation.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+// FieldErrors returns the violations, see rest.FieldErrorer.
+func (v Violations) FieldErrors() []rest.FieldError {
+	return v
+}
+
+// collect appends the violations of the leaves of a validation error tree, the inner
+// nodes only group their causes.
+func collect(ve *jsonschema.ValidationError, violations *Violations) {
+	if len(ve.Causes) > 0 {
+		for _, cause := range ve.Causes {
+			collect(cause, violations)
+		}
+		return
+	}
+
+	location := ve.InstanceLocation
+	switch k := ve.ErrorKind.(type) {
+	case *kind.Required:
+		// Reported on the missing properties rather than on their object
+		for _, property := range k.Missing {
+			*violations = append(*violations, violation(append(location[:len(location):len(location)], property), "required", "", "is required"))
+		}
+	case *kind.AdditionalProperties:
+		for _, property := range k.Properties {
+			*violations = append(*violations, violation(append(location[:len(location):len(location)], property), "additionalProperties", "", "is not allowed"))
+		}
+	case *kind.Enum:
+		values := make([]string, len(k.Want))
+		for i, want := range k.Want {
+			values[i] = fmt.Sprint(want)
+		}
+		*violations = append(*violations, violation(location, "enum", strings.Join(values, " "),
+			"must be one of "+strings.Join(values, ", ")))
+	case *kind.Type:
+		want := strings.Join(k.Want, " or ")
+		*violations = append(*violations, violation(location, "type", want, fmt.Sprintf("must be of type %s, not %s", want, k.Got)))
+	case *kind.MinLength:
+		param := strconv.Itoa(k.Want)
+		*violations = append(*violations, violation(location, "minLength", param, "length must be at least "+param))
+	case *kind.MinItems:
+		param := strconv.Itoa(k.Want)
+		*violations = append(*violations, violation(location, "minItems", param, "must have at least "+param+" items"))
+	case *kind.Minimum:
+		param := k.Want.RatString()
+		*violations = append(*violations, violation(location, "minimum", param, "must be at least "+param))
+	case *kind.Format:
+		*violations = append(*violations, violation(location, "format", k.Want, "must be a valid "+k.Want))
+	default:
+		keyword := ""
+		if path := ve.ErrorKind.KeywordPath(); len(path) > 0 {
+			keyword = path[len(path)-1]
+		}
+		*violations = append(*violations, violation(location, keyword, "", ve.ErrorKind.LocalizedString(printer)))
+	}
+}
+
+// violation returns the violation of the value at the given location.
+func violation(location []string, rule, param, message string) rest.FieldError {
+	var field, pointer strings.Builder
+	for _, token := range location {
+		pointer.WriteByte('/')
+		pointer.WriteString(pointerEscaper.Replace(token))
+
+		if _, err := strconv.Atoi(token); err == nil {
+			field.WriteString("[" + token + "]")
+			continue
+		}
+		if field.Len() > 0 {
+			field.WriteByte('.')
+		}
+		field.WriteString(token)
+	}
+
+	return rest.FieldError{
+		Field:   field.String(),
+		Pointer: pointer.String(),
+		Rule:    rule,
+		Param:   param,
+		Message: message,
+	}
+}
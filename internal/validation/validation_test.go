@@ -0,0 +1,141 @@
+package validation_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"task-manager/docs/schema"
+	"task-manager/internal/validation"
+	"task-manager/pkg/rest"
+)
+
+// TestValidate tests that every violation of a body is reported with its JSON pointer.
+func TestValidate(t *testing.T) {
+	registry, err := validation.Load(schema.FS)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		version string
+		schema  string
+		body    string
+		want    []rest.FieldError
+	}{
+		{
+			name:    "valid",
+			version: "v1",
+			schema:  "create_task",
+			body:    `{"title":"Write docs","assignee_id":1,"watcher_ids":[2,3],"due_at":"2026-01-02T15:04:05Z"}`,
+		},
+		{
+			name:    "assignee_ids without assignee_id",
+			version: "v1",
+			schema:  "create_task",
+			body:    `{"title":"Write docs","assignee_ids":[1,2]}`,
+		},
+		{
+			name:    "missing fields",
+			version: "v1",
+			schema:  "update_task",
+			body:    `{}`,
+			want: []rest.FieldError{
+				{Field: "assignee_id", Pointer: "/assignee_id", Rule: "required", Message: "is required"},
+				{Field: "status", Pointer: "/status", Rule: "required", Message: "is required"},
+				{Field: "title", Pointer: "/title", Rule: "required", Message: "is required"},
+			},
+		},
+		{
+			name:    "empty assignee_ids",
+			version: "v1",
+			schema:  "create_task",
+			body:    `{"title":"Write docs","assignee_ids":[]}`,
+			want: []rest.FieldError{
+				{Field: "assignee_id", Pointer: "/assignee_id", Rule: "required", Message: "is required"},
+			},
+		},
+		{
+			name:    "invalid values",
+			version: "v1",
+			schema:  "create_task",
+			body:    `{"title":"Write docs","assignee_id":1,"status":"bogus","watcher_ids":[2,"three"],"due_at":"tomorrow","color":"red"}`,
+			want: []rest.FieldError{
+				{Field: "color", Pointer: "/color", Rule: "additionalProperties", Message: "is not allowed"},
+				{Field: "due_at", Pointer: "/due_at", Rule: "format", Param: "date-time", Message: "must be a valid date-time"},
+				{Field: "status", Pointer: "/status", Rule: "enum", Param: "pending in_progress done canceled", Message: "must be one of pending, in_progress, done, canceled"},
+				{Field: "watcher_ids[1]", Pointer: "/watcher_ids/1", Rule: "type", Param: "integer", Message: "must be of type integer, not string"},
+			},
+		},
+		{
+			name:    "invalid IDs",
+			version: "v1",
+			schema:  "create_task",
+			body:    `{"title":"Write docs","assignee_id":0}`,
+			want: []rest.FieldError{
+				{Field: "assignee_id", Pointer: "/assignee_id", Rule: "minimum", Param: "1", Message: "must be at least 1"},
+			},
+		},
+		{
+			name:    "v2 valid",
+			version: "v2",
+			schema:  "create_task",
+			body:    `{"title":"Write docs","assignee_ids":[1,2]}`,
+		},
+		{
+			name:    "v2 assignee_id",
+			version: "v2",
+			schema:  "create_task",
+			body:    `{"title":"Write docs","assignee_id":1,"assignee_ids":[]}`,
+			want: []rest.FieldError{
+				{Field: "assignee_id", Pointer: "/assignee_id", Rule: "additionalProperties", Message: "is not allowed"},
+				{Field: "assignee_ids", Pointer: "/assignee_ids", Rule: "minItems", Param: "1", Message: "must have at least 1 items"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := registry.Validate(tt.version, tt.schema, []byte(tt.body))
+			if tt.want == nil {
+				assert.NoError(t, err)
+				return
+			}
+
+			var violations validation.Violations
+			require.ErrorAs(t, err, &violations)
+			assert.Equal(t, tt.want, violations.FieldErrors())
+		})
+	}
+}
+
+// TestValidate_InvalidJSON_ShouldReturnErrInvalidJSON tests that bodies that are not
+// JSON are told apart from invalid bodies.
+func TestValidate_InvalidJSON_ShouldReturnErrInvalidJSON(t *testing.T) {
+	registry := validation.MustLoad(schema.FS)
+
+	err := registry.Validate("v1", "create_task", []byte(`{"title":`))
+
+	assert.ErrorIs(t, err, validation.ErrInvalidJSON)
+	assert.Equal(t, "unexpected EOF", err.Error())
+}
+
+// TestValidate_UnknownSchema_ShouldReturnErrUnknownSchema tests that validating against
+// a schema that was not loaded fails.
+func TestValidate_UnknownSchema_ShouldReturnErrUnknownSchema(t *testing.T) {
+	registry := validation.MustLoad(schema.FS)
+
+	assert.False(t, registry.Has("v0", "create_task"))
+	assert.ErrorIs(t, registry.Validate("v0", "create_task", []byte(`{}`)), validation.ErrUnknownSchema)
+}
+
+// TestLoad_InvalidSchema_ShouldFail tests that the schemas are checked at startup.
+func TestLoad_InvalidSchema_ShouldFail(t *testing.T) {
+	_, err := validation.Load(fstest.MapFS{
+		"v1/broken_schema.json": {Data: []byte(`{"type": 42}`)},
+	})
+	assert.ErrorContains(t, err, "v1/broken_schema.json")
+
+	_, err = validation.Load(fstest.MapFS{})
+	assert.Error(t, err)
+}
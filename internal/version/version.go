@@ -0,0 +1,100 @@
+// Package version describes the build of the running binary: the commit and build
+// time stamped by the linker, completed by the module data embedded by the Go
+// toolchain (runtime/debug.ReadBuildInfo).
+package version
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"time"
+)
+
+// DevelopmentCommit is the commit of the binaries built without stamped commit.
+const DevelopmentCommit = "Development"
+
+// Module is a Go module of the build.
+type Module struct {
+	Path    string `json:"path"`
+	Version string `json:"version"`
+	Sum     string `json:"sum,omitempty"`
+}
+
+// Info describes the build of the running instance.
+type Info struct {
+	GitCommit     string            `json:"git_commit"`
+	BuildTime     string            `json:"build_time"`
+	StartTime     time.Time         `json:"start_time"`
+	ContainerName string            `json:"container_name"`
+	GoVersion     string            `json:"go_version"`
+	Module        Module            `json:"module"`             // Main module
+	Settings      map[string]string `json:"settings,omitempty"` // Build settings, e.g. vcs.revision, GOOS
+	Deps          []Module          `json:"deps,omitempty"`     // Dependencies, after replacements
+}
+
+// readBuildInfo reads the build info embedded in the binary, replaced by the tests.
+var readBuildInfo = debug.ReadBuildInfo
+
+// New returns the Info of the running binary, started now. The commit and build time
+// stamped by the linker are preferred; the VCS revision and time recorded by the Go
+// toolchain are used otherwise.
+func New(gitCommit, buildTime, containerName string) Info {
+	info := Info{
+		GitCommit:     gitCommit,
+		BuildTime:     buildTime,
+		StartTime:     time.Now(),
+		ContainerName: containerName,
+		GoVersion:     runtime.Version(),
+	}
+
+	build, ok := readBuildInfo()
+	if !ok {
+		if info.GitCommit == "" {
+			info.GitCommit = DevelopmentCommit
+		}
+		return info
+	}
+
+	info.GoVersion = build.GoVersion
+	info.Module = Module{Path: build.Main.Path, Version: build.Main.Version, Sum: build.Main.Sum}
+	info.Settings = make(map[string]string, len(build.Settings))
+	for _, setting := range build.Settings {
+		info.Settings[setting.Key] = setting.Value
+	}
+	for _, dep := range build.Deps {
+		if dep.Replace != nil {
+			dep = dep.Replace
+		}
+		info.Deps = append(info.Deps, Module{Path: dep.Path, Version: dep.Version, Sum: dep.Sum})
+	}
+
+	if info.GitCommit == "" || info.GitCommit == DevelopmentCommit {
+		if revision := info.Settings["vcs.revision"]; revision != "" {
+			info.GitCommit = revision
+			if info.Settings["vcs.modified"] == "true" {
+				info.GitCommit += "-dirty"
+			}
+		}
+	}
+	if info.GitCommit == "" {
+		info.GitCommit = DevelopmentCommit
+	}
+	if info.BuildTime == "" {
+		info.BuildTime = info.Settings["vcs.time"]
+	}
+	return info
+}
+
+// Uptime returns how long the instance has been running.
+func (i Info) Uptime() time.Duration {
+	return time.Since(i.StartTime)
+}
+
+// String returns the build on one line, e.g. for the --version flag.
+func (i Info) String() string {
+	version := i.Module.Version
+	if version == "" {
+		version = "(devel)"
+	}
+	return fmt.Sprintf("%s %s (commit %s, built %s, %s)", i.Module.Path, version, i.GitCommit, i.BuildTime, i.GoVersion)
+}
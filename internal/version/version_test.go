@@ -0,0 +1,108 @@
+package version
+
+import (
+	"runtime/debug"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// stubBuildInfo replaces the build info of the binary for the test.
+func stubBuildInfo(t *testing.T, info *debug.BuildInfo) {
+	t.Helper()
+	original := readBuildInfo
+	readBuildInfo = func() (*debug.BuildInfo, bool) { return info, info != nil }
+	t.Cleanup(func() { readBuildInfo = original })
+}
+
+// TestNew tests the commit and build time of the builds, stamped or not.
+func TestNew(t *testing.T) {
+	build := &debug.BuildInfo{
+		GoVersion: "go1.22.4",
+		Main:      debug.Module{Path: "task-manager", Version: "v1.4.0", Sum: "h1:main"},
+		Deps: []*debug.Module{
+			{Path: "github.com/gin-gonic/gin", Version: "v1.10.0", Sum: "h1:gin"},
+			{Path: "github.com/lib/pq", Version: "v1.10.9", Replace: &debug.Module{Path: "github.com/fork/pq", Version: "v1.10.10"}},
+		},
+		Settings: []debug.BuildSetting{
+			{Key: "vcs.revision", Value: "0b4645d"},
+			{Key: "vcs.time", Value: "2024-06-01T10:00:00Z"},
+		},
+	}
+	modified := *build
+	modified.Settings = append([]debug.BuildSetting{{Key: "vcs.modified", Value: "true"}}, build.Settings...)
+
+	tests := []struct {
+		name          string
+		build         *debug.BuildInfo
+		gitCommit     string
+		buildTime     string
+		wantCommit    string
+		wantBuildTime string
+	}{
+		{
+			name:          "stamped",
+			build:         build,
+			gitCommit:     "81d50e7",
+			buildTime:     "2024-06-02T08:00:00Z",
+			wantCommit:    "81d50e7",
+			wantBuildTime: "2024-06-02T08:00:00Z",
+		},
+		{
+			name:          "vcs revision",
+			build:         build,
+			gitCommit:     DevelopmentCommit,
+			wantCommit:    "0b4645d",
+			wantBuildTime: "2024-06-01T10:00:00Z",
+		},
+		{
+			name:          "modified vcs revision",
+			build:         &modified,
+			wantCommit:    "0b4645d-dirty",
+			wantBuildTime: "2024-06-01T10:00:00Z",
+		},
+		{
+			name:       "no build info",
+			wantCommit: DevelopmentCommit,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stubBuildInfo(t, tt.build)
+
+			info := New(tt.gitCommit, tt.buildTime, "api-1")
+
+			assert.Equal(t, tt.wantCommit, info.GitCommit)
+			assert.Equal(t, tt.wantBuildTime, info.BuildTime)
+			assert.Equal(t, "api-1", info.ContainerName)
+			assert.WithinDuration(t, time.Now(), info.StartTime, time.Second)
+		})
+	}
+}
+
+// TestNew_ShouldReadTheModules tests that the main module, the dependencies after
+// replacements and the build settings are read from the build info.
+func TestNew_ShouldReadTheModules(t *testing.T) {
+	stubBuildInfo(t, &debug.BuildInfo{
+		GoVersion: "go1.22.4",
+		Main:      debug.Module{Path: "task-manager", Version: "v1.4.0", Sum: "h1:main"},
+		Deps: []*debug.Module{
+			{Path: "github.com/gin-gonic/gin", Version: "v1.10.0", Sum: "h1:gin"},
+			{Path: "github.com/lib/pq", Version: "v1.10.9", Replace: &debug.Module{Path: "github.com/fork/pq", Version: "v1.10.10"}},
+		},
+		Settings: []debug.BuildSetting{{Key: "GOOS", Value: "linux"}},
+	})
+
+	info := New("81d50e7", "2024-06-02T08:00:00Z", "api-1")
+
+	assert.Equal(t, "go1.22.4", info.GoVersion)
+	assert.Equal(t, Module{Path: "task-manager", Version: "v1.4.0", Sum: "h1:main"}, info.Module)
+	assert.Equal(t, []Module{
+		{Path: "github.com/gin-gonic/gin", Version: "v1.10.0", Sum: "h1:gin"},
+		{Path: "github.com/fork/pq", Version: "v1.10.10"},
+	}, info.Deps)
+	assert.Equal(t, map[string]string{"GOOS": "linux"}, info.Settings)
+	assert.Equal(t, "task-manager v1.4.0 (commit 81d50e7, built 2024-06-02T08:00:00Z, go1.22.4)", info.String())
+}
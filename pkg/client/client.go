@@ -0,0 +1,223 @@
+// Package client is a typed Go client for the task manager REST API.
+//
+// It decodes the standard response envelope, maps failures to *APIError values
+// matching the sentinel errors of this package, and retries idempotent calls
+// (GET, PUT, DELETE) on network errors and temporary server failures with an
+// exponential backoff. Every call takes a context bounding all of its attempts.
+//
+//	c := client.New("http://localhost:8080", client.Config{})
+//	task, err := c.GetTask(ctx, 42)
+//	if errors.Is(err, client.ErrNotFound) {
+//		...
+//	}
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"task-manager/pkg/rest"
+	"task-manager/pkg/retry"
+	"time"
+)
+
+// Defaults applied to zero Config fields.
+const (
+	DefaultTimeout     = 30 * time.Second
+	DefaultMaxRetries  = 3
+	DefaultBaseBackoff = 100 * time.Millisecond
+	DefaultMaxBackoff  = 5 * time.Second
+	DefaultUserAgent   = "task-manager-client/1.0"
+)
+
+// Config configures a Client. Zero values fall back to the defaults.
+type Config struct {
+	HTTPClient  *http.Client  // Client sending the requests, one with DefaultTimeout if nil
+	MaxRetries  int           // Retries of a failed idempotent call, negative to disable
+	BaseBackoff time.Duration // Delay before the first retry, doubled on every retry
+	MaxBackoff  time.Duration // Upper bound of the retry delay and of a Retry-After wait
+	UserAgent   string        // User-Agent header of every request
+	Token       string        // Bearer token sent in the Authorization header, if set
+}
+
+// Client calls the task manager API. It is safe for concurrent use.
+type Client struct {
+	baseURL string
+	http    *http.Client
+	config  Config
+}
+
+// New returns a Client for the API served at baseURL, e.g. http://localhost:8080.
+func New(baseURL string, config Config) *Client {
+	if config.HTTPClient == nil {
+		config.HTTPClient = &http.Client{Timeout: DefaultTimeout}
+	}
+	if config.MaxRetries == 0 {
+		config.MaxRetries = DefaultMaxRetries
+	}
+	if config.BaseBackoff <= 0 {
+		config.BaseBackoff = DefaultBaseBackoff
+	}
+	if config.MaxBackoff <= 0 {
+		config.MaxBackoff = DefaultMaxBackoff
+	}
+	if config.UserAgent == "" {
+		config.UserAgent = DefaultUserAgent
+	}
+
+	return &Client{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		http:    config.HTTPClient,
+		config:  config,
+	}
+}
+
+// call sends a request and decodes the data of the response envelope into out, if
+// not nil. The meta of the envelope is returned for list calls.
+func (c *Client) call(ctx context.Context, method, path string, query url.Values, body, out any) (rest.PaginationMeta, error) {
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return rest.PaginationMeta{}, err
+		}
+	}
+
+	target := c.baseURL + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	retries := 0
+	if isIdempotent(method) {
+		retries = max(c.config.MaxRetries, 0)
+	}
+
+	for attempt := 0; ; attempt++ {
+		resp, err := c.send(ctx, method, target, payload)
+		if err == nil && !isRetryableStatus(resp.StatusCode) || attempt >= retries || ctx.Err() != nil {
+			if err != nil {
+				return rest.PaginationMeta{}, err
+			}
+			return decode(resp, out)
+		}
+
+		wait := retry.Backoff(attempt+1, c.config.BaseBackoff, c.config.MaxBackoff)
+		if err == nil {
+			wait = retryAfter(resp, wait, c.config.MaxBackoff)
+			drain(resp)
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return rest.PaginationMeta{}, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// send sends a single attempt of a request.
+func (c *Client) send(ctx context.Context, method, target string, payload []byte) (*http.Response, error) {
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target, body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", c.config.UserAgent)
+	if c.config.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.config.Token)
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	return c.http.Do(req)
+}
+
+// decode reads the response envelope, returning an *APIError for non-2xx statuses.
+func decode(resp *http.Response, out any) (rest.PaginationMeta, error) {
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNoContent {
+		return rest.PaginationMeta{}, nil
+	}
+
+	var envelope struct {
+		rest.StandardResponse
+		Data json.RawMessage `json:"data"`
+	}
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return rest.PaginationMeta{}, err
+	}
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &envelope); err != nil && resp.StatusCode < 300 {
+			return rest.PaginationMeta{}, fmt.Errorf("decode response: %w", err)
+		}
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return rest.PaginationMeta{}, &APIError{
+			StatusCode: resp.StatusCode,
+			Code:       envelope.Code,
+			Message:    envelope.Message,
+			Errors:     envelope.Errors,
+		}
+	}
+
+	if out != nil && len(envelope.Data) > 0 {
+		if err := json.Unmarshal(envelope.Data, out); err != nil {
+			return rest.PaginationMeta{}, fmt.Errorf("decode response data: %w", err)
+		}
+	}
+
+	return envelope.Meta, nil
+}
+
+// isIdempotent reports whether a call with the given method may be safely retried.
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// isRetryableStatus reports whether a response status is a temporary failure.
+func isRetryableStatus(status int) bool {
+	switch status {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// retryAfter returns the delay requested by the Retry-After header of the response,
+// capped at max, or fallback if there is none.
+func retryAfter(resp *http.Response, fallback, max time.Duration) time.Duration {
+	seconds, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	if err != nil || seconds < 0 {
+		return fallback
+	}
+	return min(time.Duration(seconds)*time.Second, max)
+}
+
+// drain discards a bounded part of the body so the connection can be reused.
+func drain(resp *http.Response) {
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	_ = resp.Body.Close()
+}
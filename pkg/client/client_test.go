@@ -0,0 +1,199 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"task-manager/internal/entities"
+	HTTPhandler "task-manager/internal/http"
+	"task-manager/internal/repository/postgres"
+	"task-manager/internal/service"
+	"task-manager/pkg/client"
+	"task-manager/pkg/rest"
+	"testing"
+	"time"
+)
+
+// setupClient starts the real router backed by the given mock and returns a client for it.
+func setupClient(t *testing.T, taskService *service.MockTaskService) *client.Client {
+	t.Helper()
+
+	server := httptest.NewServer(HTTPhandler.SetupHandler(taskService).SetupRouter())
+	t.Cleanup(server.Close)
+
+	return client.New(server.URL, client.Config{BaseBackoff: time.Millisecond, MaxBackoff: 10 * time.Millisecond})
+}
+
+// TestCreateTask_ShouldReturnCreatedTask tests that a created task is decoded from the envelope.
+func TestCreateTask_ShouldReturnCreatedTask(t *testing.T) {
+	mockService := &service.MockTaskService{}
+	c := setupClient(t, mockService)
+
+	dueAt := time.Date(2030, 1, 2, 9, 0, 0, 0, time.UTC)
+	mockService.On("Create", mock.Anything, mock.MatchedBy(func(task *entities.Task) bool {
+		return task.Title == "Write docs" && task.Status == entities.TaskStatusPending && task.AssigneeID == 10
+	})).Return(&entities.Task{
+		ID: 7, Title: "Write docs", Status: entities.TaskStatusPending, AssigneeID: 10, AssigneeIDs: []int64{10}, DueAt: &dueAt,
+	}, nil).Once()
+
+	task, err := c.CreateTask(context.Background(), client.CreateTaskRequest{Title: "Write docs", AssigneeID: 10, DueAt: &dueAt})
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), task.ID)
+	assert.Equal(t, client.TaskStatusPending, task.Status)
+	assert.Equal(t, []int64{10}, task.AssigneeIDs)
+	require.NotNil(t, task.DueAt)
+	assert.True(t, dueAt.Equal(*task.DueAt))
+	mockService.AssertExpectations(t)
+}
+
+// TestCreateTask_InvalidMember_ShouldReturnValidationError tests that a 422 envelope maps to ErrValidation.
+func TestCreateTask_InvalidMember_ShouldReturnValidationError(t *testing.T) {
+	mockService := &service.MockTaskService{}
+	c := setupClient(t, mockService)
+
+	mockService.On("Create", mock.Anything, mock.Anything).Return((*entities.Task)(nil), service.ErrAssigneeNotFound).Once()
+
+	_, err := c.CreateTask(context.Background(), client.CreateTaskRequest{Title: "Write docs", AssigneeID: 99})
+
+	assert.ErrorIs(t, err, client.ErrValidation)
+
+	var apiErr *client.APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusUnprocessableEntity, apiErr.StatusCode)
+	assert.Equal(t, "ASSIGNEE_NOT_FOUND", apiErr.Code)
+	assert.Equal(t, []string{service.ErrAssigneeNotFound.Error()}, apiErr.Errors)
+}
+
+// TestGetTask_NotFound_ShouldReturnErrNotFound tests that a 404 envelope maps to ErrNotFound.
+func TestGetTask_NotFound_ShouldReturnErrNotFound(t *testing.T) {
+	mockService := &service.MockTaskService{}
+	c := setupClient(t, mockService)
+
+	mockService.On("GetByID", mock.Anything, int64(404)).Return((*entities.Task)(nil), postgres.ErrTaskNotFound).Once()
+
+	task, err := c.GetTask(context.Background(), 404)
+
+	assert.Nil(t, task)
+	assert.ErrorIs(t, err, client.ErrNotFound)
+	assert.NotErrorIs(t, err, client.ErrServer)
+	mockService.AssertExpectations(t)
+}
+
+// TestUpdateAndDeleteTask_ShouldSucceed tests the update and delete round trips.
+func TestUpdateAndDeleteTask_ShouldSucceed(t *testing.T) {
+	mockService := &service.MockTaskService{}
+	c := setupClient(t, mockService)
+
+	mockService.On("Update", mock.Anything, mock.MatchedBy(func(task *entities.Task) bool {
+		return task.ID == 7 && task.Status == entities.TaskStatusDone
+	})).Return(&entities.Task{ID: 7, Title: "Write docs", Status: entities.TaskStatusDone, AssigneeID: 10}, nil).Once()
+	mockService.On("Delete", mock.Anything, int64(7)).Return(nil).Once()
+
+	task, err := c.UpdateTask(context.Background(), 7, client.UpdateTaskRequest{Title: "Write docs", Status: client.TaskStatusDone, AssigneeID: 10})
+	require.NoError(t, err)
+	assert.Equal(t, client.TaskStatusDone, task.Status)
+
+	require.NoError(t, c.DeleteTask(context.Background(), 7))
+	mockService.AssertExpectations(t)
+}
+
+// TestTasks_ShouldIterateAllPages tests that the iterator follows the pagination meta.
+func TestTasks_ShouldIterateAllPages(t *testing.T) {
+	mockService := &service.MockTaskService{}
+	c := setupClient(t, mockService)
+
+	page := func(n int) interface{} {
+		return mock.MatchedBy(func(q rest.Query) bool {
+			return q.Page == n && q.PerPage == 2 && q.Filter["status"] == "done"
+		})
+	}
+	mockService.On("List", mock.Anything, page(1)).Return([]entities.Task{{ID: 1}, {ID: 2}}, 3, nil).Once()
+	mockService.On("List", mock.Anything, page(2)).Return([]entities.Task{{ID: 3}}, 3, nil).Once()
+
+	var ids []int64
+	for task, err := range c.Tasks(context.Background(), client.ListOptions{PerPage: 2, Status: client.TaskStatusDone}) {
+		require.NoError(t, err)
+		ids = append(ids, task.ID)
+	}
+
+	assert.Equal(t, []int64{1, 2, 3}, ids)
+	mockService.AssertExpectations(t)
+}
+
+// TestTasks_Error_ShouldStopIteration tests that a failed page is yielded as the last element.
+func TestTasks_Error_ShouldStopIteration(t *testing.T) {
+	mockService := &service.MockTaskService{}
+	c := setupClient(t, mockService)
+
+	mockService.On("List", mock.Anything, mock.Anything).Return([]entities.Task(nil), 0, errors.New("db down")).Once()
+
+	var errs []error
+	for _, err := range c.Tasks(context.Background(), client.ListOptions{}) {
+		errs = append(errs, err)
+	}
+
+	require.Len(t, errs, 1)
+	assert.ErrorIs(t, errs[0], client.ErrServer)
+}
+
+// TestGetTask_TemporaryFailure_ShouldRetry tests that idempotent calls are retried on 503.
+func TestGetTask_TemporaryFailure_ShouldRetry(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"data": {"id": 1, "title": "Write docs", "status": "pending"}, "status": "ok"}`))
+	}))
+	defer server.Close()
+
+	c := client.New(server.URL, client.Config{BaseBackoff: time.Millisecond})
+
+	task, err := c.GetTask(context.Background(), 1)
+
+	require.NoError(t, err)
+	assert.Equal(t, "Write docs", task.Title)
+	assert.Equal(t, int32(3), calls.Load())
+}
+
+// TestCreateTask_TemporaryFailure_ShouldNotRetry tests that non-idempotent calls are sent once.
+func TestCreateTask_TemporaryFailure_ShouldNotRetry(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	c := client.New(server.URL, client.Config{BaseBackoff: time.Millisecond})
+
+	_, err := c.CreateTask(context.Background(), client.CreateTaskRequest{Title: "Write docs", AssigneeID: 1})
+
+	assert.ErrorIs(t, err, client.ErrServer)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+// TestGetTask_ContextCanceled_ShouldStopRetrying tests that the context bounds the retries.
+func TestGetTask_ContextCanceled_ShouldStopRetrying(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	c := client.New(server.URL, client.Config{BaseBackoff: time.Minute, MaxBackoff: time.Minute})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := c.GetTask(ctx, 1)
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
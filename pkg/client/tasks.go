@@ -0,0 +1,221 @@
+package client
+
+import (
+	"context"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"task-manager/pkg/rest"
+	"time"
+)
+
+// TaskStatus is the status of a task.
+type TaskStatus string
+
+// Task statuses.
+const (
+	TaskStatusPending    TaskStatus = "pending"
+	TaskStatusInProgress TaskStatus = "in_progress"
+	TaskStatusDone       TaskStatus = "done"
+	TaskStatusCanceled   TaskStatus = "canceled"
+)
+
+// Task is a task as returned by the API.
+type Task struct {
+	ID          int64      `json:"id"`
+	Title       string     `json:"title"`
+	Description string     `json:"description,omitempty"`
+	Status      TaskStatus `json:"status"`
+	AssigneeID  int64      `json:"assignee_id"`
+	AssigneeIDs []int64    `json:"assignee_ids"`
+	WatcherIDs  []int64    `json:"watcher_ids"`
+	Assignee    *Assignee  `json:"assignee,omitempty"` // only with ListOptions.IncludeAssignee
+	DueAt       *time.Time `json:"due_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+
+	RecurrenceRule     string `json:"recurrence_rule,omitempty"`
+	RecurrenceParentID *int64 `json:"recurrence_parent_id,omitempty"` // occurrence this task was created from
+}
+
+// Assignee is the primary assignee embedded in a task.
+type Assignee struct {
+	ID    int64  `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+// CreateTaskRequest holds the fields of a new task. Either AssigneeID or
+// AssigneeIDs is required; the status defaults to pending.
+type CreateTaskRequest struct {
+	Title       string     `json:"title"`
+	Description string     `json:"description,omitempty"`
+	Status      TaskStatus `json:"status,omitempty"`
+	AssigneeID  int64      `json:"assignee_id,omitempty"`
+	AssigneeIDs []int64    `json:"assignee_ids,omitempty"`
+	WatcherIDs  []int64    `json:"watcher_ids,omitempty"`
+	DueAt       *time.Time `json:"due_at,omitempty"`
+
+	RecurrenceRule string `json:"recurrence_rule,omitempty"` // RRULE, requires DueAt
+}
+
+// UpdateTaskRequest replaces the fields of a task. Nil AssigneeIDs and WatcherIDs
+// leave the current members untouched; a nil DueAt or an empty RecurrenceRule clear them.
+type UpdateTaskRequest struct {
+	Title       string     `json:"title"`
+	Description string     `json:"description,omitempty"`
+	Status      TaskStatus `json:"status"`
+	AssigneeID  int64      `json:"assignee_id,omitempty"`
+	AssigneeIDs []int64    `json:"assignee_ids,omitempty"`
+	WatcherIDs  []int64    `json:"watcher_ids,omitempty"`
+	DueAt       *time.Time `json:"due_at,omitempty"`
+
+	RecurrenceRule string `json:"recurrence_rule,omitempty"`
+}
+
+// ListOptions filters and paginates the task list. Zero values are left to the API
+// defaults (page 1, 20 tasks per page).
+type ListOptions struct {
+	Page    int
+	PerPage int
+
+	IDs         []int64 // tasks with any of these IDs
+	Status      TaskStatus
+	Title       string
+	AssigneeIDs []int64 // tasks assigned to any of them
+	WatcherIDs  []int64 // tasks watched by any of them
+
+	IncludeAssignee bool // embed the primary assignee
+}
+
+// TaskPage is a page of the task list.
+type TaskPage struct {
+	Tasks []Task
+	Meta  rest.PaginationMeta
+}
+
+// CreateTask creates a task. It is not retried, as it is not idempotent.
+func (c *Client) CreateTask(ctx context.Context, req CreateTaskRequest) (*Task, error) {
+	var task Task
+	if _, err := c.call(ctx, http.MethodPost, "/api/v1/tasks/", nil, req, &task); err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// GetTask returns a task by ID.
+func (c *Client) GetTask(ctx context.Context, id int64) (*Task, error) {
+	var task Task
+	if _, err := c.call(ctx, http.MethodGet, taskPath(id), nil, nil, &task); err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// UpdateTask replaces the fields of a task.
+func (c *Client) UpdateTask(ctx context.Context, id int64, req UpdateTaskRequest) (*Task, error) {
+	var task Task
+	if _, err := c.call(ctx, http.MethodPut, taskPath(id), nil, req, &task); err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// DeleteTask deletes a task.
+func (c *Client) DeleteTask(ctx context.Context, id int64) error {
+	_, err := c.call(ctx, http.MethodDelete, taskPath(id), nil, nil, nil)
+	return err
+}
+
+// ListTasks returns a page of the tasks matching the options.
+func (c *Client) ListTasks(ctx context.Context, opts ListOptions) (*TaskPage, error) {
+	var tasks []Task
+	meta, err := c.call(ctx, http.MethodGet, "/api/v1/tasks/", opts.values(), nil, &tasks)
+	if err != nil {
+		return nil, err
+	}
+	return &TaskPage{Tasks: tasks, Meta: meta}, nil
+}
+
+// Tasks iterates over all tasks matching the options, fetching the pages lazily
+// from opts.Page on. Iteration stops at the first error, which is yielded last.
+// Tasks changed while iterating may be skipped or seen twice, as with manual paging.
+//
+//	for task, err := range c.Tasks(ctx, client.ListOptions{Status: client.TaskStatusDone}) {
+//		if err != nil {
+//			return err
+//		}
+//		...
+//	}
+func (c *Client) Tasks(ctx context.Context, opts ListOptions) iter.Seq2[Task, error] {
+	return func(yield func(Task, error) bool) {
+		if opts.Page < 1 {
+			opts.Page = 1
+		}
+
+		for ; ; opts.Page++ {
+			page, err := c.ListTasks(ctx, opts)
+			if err != nil {
+				yield(Task{}, err)
+				return
+			}
+
+			for _, task := range page.Tasks {
+				if !yield(task, nil) {
+					return
+				}
+			}
+
+			if len(page.Tasks) == 0 || len(page.Tasks) < page.Meta.PerPage ||
+				(page.Meta.Page-1)*page.Meta.PerPage+len(page.Tasks) >= page.Meta.Total {
+				return
+			}
+		}
+	}
+}
+
+// values encodes the options as query parameters.
+func (o ListOptions) values() url.Values {
+	values := url.Values{}
+	if o.Page > 0 {
+		values.Set(rest.Page, strconv.Itoa(o.Page))
+	}
+	if o.PerPage > 0 {
+		values.Set(rest.PerPage, strconv.Itoa(o.PerPage))
+	}
+	if len(o.IDs) > 0 {
+		values.Set("id", joinIDs(o.IDs))
+	}
+	if o.Status != "" {
+		values.Set("status", string(o.Status))
+	}
+	if o.Title != "" {
+		values.Set("title", o.Title)
+	}
+	if len(o.AssigneeIDs) > 0 {
+		values.Set("assignee_id", joinIDs(o.AssigneeIDs))
+	}
+	if len(o.WatcherIDs) > 0 {
+		values.Set("watcher_id", joinIDs(o.WatcherIDs))
+	}
+	if o.IncludeAssignee {
+		values.Set(rest.Include, "assignee")
+	}
+	return values
+}
+
+// taskPath returns the path of a task.
+func taskPath(id int64) string {
+	return "/api/v1/tasks/" + strconv.FormatInt(id, 10)
+}
+
+// joinIDs formats IDs as a comma separated list.
+func joinIDs(ids []int64) string {
+	values := make([]string, 0, len(ids))
+	for _, id := range ids {
+		values = append(values, strconv.FormatInt(id, 10))
+	}
+	return strings.Join(values, ",")
+}
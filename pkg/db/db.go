@@ -45,6 +45,7 @@ type Manager struct {
 //   - SelectContext: Execute a query that returns multiple rows mapped to dest.
 //   - Close: Close the database connection gracefully.
 //   - Raw: Access the underlying *sqlx.DB instance for advanced usage.
+//   - Check: Ping the database, for the health checks.
 //
 // Example usage:
 //
@@ -67,6 +68,7 @@ type DB interface {
 	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
 	Close() error
 	Raw() *sqlx.DB
+	Check(ctx context.Context) error
 }
 
 func NewDB(cfg Configs) (*Manager, error) {
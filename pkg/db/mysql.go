@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"github.com/jmoiron/sqlx"
 	_ "github.com/lib/pq"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
 )
 
 type MySQLDB struct {
@@ -53,19 +54,35 @@ func (m *MySQLDB) Raw() *sqlx.DB {
 	return m.Conn
 }
 
-// QueryContext Forward methods to underlying sqlx.DB
-func (m *MySQLDB) QueryContext(ctx context.Context, query string, args ...interface{}) (*sqlx.Rows, error) {
-	return m.Conn.QueryxContext(ctx, query, args...)
+// Check pings the database with a connection of the pool, failing while the database
+// is unreachable or every connection is busy until ctx is done. It implements
+// health.Checker.
+func (m *MySQLDB) Check(ctx context.Context) error {
+	return m.Conn.PingContext(ctx)
 }
 
-func (m *MySQLDB) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
-	return m.Conn.ExecContext(ctx, query, args...)
+// QueryContext Forward methods to underlying sqlx.DB, or to the transaction
+// started by WithinTx when ctx carries one, in a span per statement
+func (m *MySQLDB) QueryContext(ctx context.Context, query string, args ...interface{}) (rows *sqlx.Rows, err error) {
+	ctx, span := startSpan(ctx, semconv.DBSystemNameMySQL, query)
+	defer func() { endSpan(span, err) }()
+	return executor(ctx, m.Conn).QueryxContext(ctx, query, args...)
 }
 
-func (m *MySQLDB) GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
-	return m.Conn.GetContext(ctx, dest, query, args...)
+func (m *MySQLDB) ExecContext(ctx context.Context, query string, args ...interface{}) (result sql.Result, err error) {
+	ctx, span := startSpan(ctx, semconv.DBSystemNameMySQL, query)
+	defer func() { endSpan(span, err) }()
+	return executor(ctx, m.Conn).ExecContext(ctx, query, args...)
 }
 
-func (m *MySQLDB) SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
-	return m.Conn.SelectContext(ctx, dest, query, args...)
+func (m *MySQLDB) GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) (err error) {
+	ctx, span := startSpan(ctx, semconv.DBSystemNameMySQL, query)
+	defer func() { endSpan(span, err) }()
+	return sqlx.GetContext(ctx, executor(ctx, m.Conn), dest, query, args...)
+}
+
+func (m *MySQLDB) SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) (err error) {
+	ctx, span := startSpan(ctx, semconv.DBSystemNameMySQL, query)
+	defer func() { endSpan(span, err) }()
+	return sqlx.SelectContext(ctx, executor(ctx, m.Conn), dest, query, args...)
 }
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"github.com/jmoiron/sqlx"
 	_ "github.com/lib/pq"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
 )
 
 type PostgresDB struct {
@@ -57,19 +58,35 @@ func (p *PostgresDB) Raw() *sqlx.DB {
 	return p.Conn
 }
 
-// QueryContext Forward methods to underlying sqlx.DB
-func (p *PostgresDB) QueryContext(ctx context.Context, query string, args ...interface{}) (*sqlx.Rows, error) {
-	return p.Conn.QueryxContext(ctx, query, args...)
+// Check pings the database with a connection of the pool, failing while the database
+// is unreachable or every connection is busy until ctx is done. It implements
+// health.Checker.
+func (p *PostgresDB) Check(ctx context.Context) error {
+	return p.Conn.PingContext(ctx)
 }
 
-func (p *PostgresDB) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
-	return p.Conn.ExecContext(ctx, query, args...)
+// QueryContext Forward methods to underlying sqlx.DB, or to the transaction
+// started by WithinTx when ctx carries one, in a span per statement
+func (p *PostgresDB) QueryContext(ctx context.Context, query string, args ...interface{}) (rows *sqlx.Rows, err error) {
+	ctx, span := startSpan(ctx, semconv.DBSystemNamePostgreSQL, query)
+	defer func() { endSpan(span, err) }()
+	return executor(ctx, p.Conn).QueryxContext(ctx, query, args...)
 }
 
-func (p *PostgresDB) GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
-	return p.Conn.GetContext(ctx, dest, query, args...)
+func (p *PostgresDB) ExecContext(ctx context.Context, query string, args ...interface{}) (result sql.Result, err error) {
+	ctx, span := startSpan(ctx, semconv.DBSystemNamePostgreSQL, query)
+	defer func() { endSpan(span, err) }()
+	return executor(ctx, p.Conn).ExecContext(ctx, query, args...)
 }
 
-func (p *PostgresDB) SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
-	return p.Conn.SelectContext(ctx, dest, query, args...)
+func (p *PostgresDB) GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) (err error) {
+	ctx, span := startSpan(ctx, semconv.DBSystemNamePostgreSQL, query)
+	defer func() { endSpan(span, err) }()
+	return sqlx.GetContext(ctx, executor(ctx, p.Conn), dest, query, args...)
+}
+
+func (p *PostgresDB) SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) (err error) {
+	ctx, span := startSpan(ctx, semconv.DBSystemNamePostgreSQL, query)
+	defer func() { endSpan(span, err) }()
+	return sqlx.SelectContext(ctx, executor(ctx, p.Conn), dest, query, args...)
 }
@@ -0,0 +1,42 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"task-manager/pkg/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// startSpan starts the client span of a statement run on the database system, named
+// after the operation of the statement (SELECT, INSERT, ...). Statements issued on the
+// *sqlx.DB returned by Raw are not traced.
+func startSpan(ctx context.Context, system attribute.KeyValue, query string) (context.Context, trace.Span) {
+	operation := operationName(query)
+	return tracing.Start(ctx, operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(system, semconv.DBOperationName(operation), semconv.DBQueryText(query)),
+	)
+}
+
+// endSpan ends the span of a statement, failed if the statement failed. A query
+// returning no row is not a failure.
+func endSpan(span trace.Span, err error) {
+	if errors.Is(err, sql.ErrNoRows) {
+		err = nil
+	}
+	tracing.End(span, err)
+}
+
+// operationName returns the first keyword of the query, e.g. SELECT or WITH.
+func operationName(query string) string {
+	fields := strings.Fields(query)
+	if len(fields) == 0 {
+		return "QUERY"
+	}
+	return strings.ToUpper(fields[0])
+}
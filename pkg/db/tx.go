@@ -0,0 +1,58 @@
+package db
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// txKey is the context key under which WithinTx stores the running transaction.
+type txKey struct{}
+
+// txValue binds a transaction to the connection pool it was started on.
+type txValue struct {
+	conn *sqlx.DB
+	tx   *sqlx.Tx
+}
+
+// WithinTx runs fn in a transaction on the given database. Every statement issued
+// through the DB with the context passed to fn joins the transaction, so
+// repositories do not need to know whether they run inside one.
+//
+// The transaction is committed if fn returns nil and rolled back otherwise.
+// Nested calls reuse the outer transaction.
+//
+// Example usage:
+//
+//	err := db.WithinTx(ctx, conn, func(ctx context.Context) error {
+//	    if _, err := taskRepo.Create(ctx, task); err != nil {
+//	        return err
+//	    }
+//	    return outboxRepo.Append(ctx, event)
+//	})
+func WithinTx(ctx context.Context, database DB, fn func(ctx context.Context) error) error {
+	conn := database.Raw()
+	if v, ok := ctx.Value(txKey{}).(txValue); ok && v.conn == conn {
+		return fn(ctx)
+	}
+
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(context.WithValue(ctx, txKey{}, txValue{conn: conn, tx: tx})); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// executor returns the transaction started by WithinTx on conn, or conn itself.
+func executor(ctx context.Context, conn *sqlx.DB) sqlx.ExtContext {
+	if v, ok := ctx.Value(txKey{}).(txValue); ok && v.conn == conn {
+		return v.tx
+	}
+	return conn
+}
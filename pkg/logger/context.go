@@ -0,0 +1,87 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"task-manager/pkg/tracing"
+)
+
+// Attributes added to the log records from their context.
+const (
+	TraceIDKey   = "trace_id"
+	SpanIDKey    = "span_id"
+	RouteKey     = "route"
+	PrincipalKey = "principal"
+	TenantKey    = "tenant"
+)
+
+// ContextAttrs returns the attributes of ctx to add to the records logged with it,
+// e.g. the principal of the request.
+type ContextAttrs func(ctx context.Context) []slog.Attr
+
+// ContextHandler is a slog.Handler adding the attributes of the context of every
+// record before passing it to the wrapped handler: the trace and span IDs of the span
+// of the context, its route, and the attributes returned by the ContextAttrs.
+type ContextHandler struct {
+	handler slog.Handler
+	attrs   []ContextAttrs
+}
+
+// NewContextHandler returns a ContextHandler wrapping handler.
+func NewContextHandler(handler slog.Handler, attrs ...ContextAttrs) *ContextHandler {
+	return &ContextHandler{handler: handler, attrs: attrs}
+}
+
+// WithContextAttrs returns an Option adding the attributes returned by attrs to the
+// records logged with a context, e.g. auth.LogAttrs. They are added to the
+// ContextHandler of the logger if it already has one, so that the trace and route are
+// not added twice.
+func WithContextAttrs(attrs ...ContextAttrs) Option {
+	return func(logger *slog.Logger) {
+		if h, ok := logger.Handler().(*ContextHandler); ok {
+			*logger = *slog.New(&ContextHandler{handler: h.handler, attrs: append(slices.Clip(h.attrs), attrs...)})
+			return
+		}
+		*logger = *slog.New(NewContextHandler(logger.Handler(), attrs...))
+	}
+}
+
+// Enabled reports whether the wrapped handler handles records at level.
+func (h *ContextHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+// Handle adds the attributes of ctx to the record and passes it to the wrapped handler.
+func (h *ContextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if ctx != nil {
+		if traceID := tracing.TraceID(ctx); traceID != "" {
+			record.AddAttrs(slog.String(TraceIDKey, traceID), slog.String(SpanIDKey, tracing.SpanID(ctx)))
+		}
+		if route, ok := ctx.Value(routeKey{}).(string); ok {
+			record.AddAttrs(slog.String(RouteKey, route))
+		}
+		for _, attrs := range h.attrs {
+			record.AddAttrs(attrs(ctx)...)
+		}
+	}
+	return h.handler.Handle(ctx, record)
+}
+
+// WithAttrs returns a ContextHandler wrapping the handler with the attributes.
+func (h *ContextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &ContextHandler{handler: h.handler.WithAttrs(attrs), attrs: h.attrs}
+}
+
+// WithGroup returns a ContextHandler wrapping the handler with the group.
+func (h *ContextHandler) WithGroup(name string) slog.Handler {
+	return &ContextHandler{handler: h.handler.WithGroup(name), attrs: h.attrs}
+}
+
+type routeKey struct{}
+
+// WithRoute returns a copy of ctx carrying the route of the request, logged with the
+// records of the request, e.g. "GET /api/v1/tasks/:id" or a gRPC method.
+func WithRoute(ctx context.Context, route string) context.Context {
+	return context.WithValue(ctx, routeKey{}, route)
+}
@@ -0,0 +1,105 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"os"
+	"task-manager/pkg/tracing"
+	"testing"
+)
+
+// logRecord logs a message with ctx through a ContextHandler and returns the JSON record.
+func logRecord(t *testing.T, ctx context.Context, attrs ...ContextAttrs) map[string]any {
+	t.Helper()
+
+	var buf bytes.Buffer
+	logger := slog.New(NewContextHandler(slog.NewJSONHandler(&buf, nil), attrs...)).With("service", "test")
+	logger.InfoContext(ctx, "task created", "task_id", 42)
+
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("Expected a JSON record, got %q: %v", buf.String(), err)
+	}
+	return record
+}
+
+func TestContextHandler(t *testing.T) {
+	ctx, span := tracing.Start(context.Background(), "request")
+	defer span.End()
+	ctx = WithRoute(ctx, "POST /api/v1/tasks/")
+
+	principal := func(context.Context) []slog.Attr {
+		return []slog.Attr{slog.String(PrincipalKey, "alice")}
+	}
+	record := logRecord(t, ctx, principal)
+
+	expected := map[string]any{
+		TraceIDKey:   tracing.TraceID(ctx),
+		SpanIDKey:    tracing.SpanID(ctx),
+		RouteKey:     "POST /api/v1/tasks/",
+		PrincipalKey: "alice",
+		"task_id":    float64(42),
+		"service":    "test",
+	}
+	for key, value := range expected {
+		if record[key] != value {
+			t.Errorf("Expected %s=%v, got %v in %v", key, value, record[key], record)
+		}
+	}
+}
+
+func TestContextHandler_WithoutTrace(t *testing.T) {
+	record := logRecord(t, context.Background())
+
+	for _, key := range []string{TraceIDKey, SpanIDKey, RouteKey} {
+		if _, ok := record[key]; ok {
+			t.Errorf("Expected no %s in %v", key, record)
+		}
+	}
+	if record["task_id"] != float64(42) {
+		t.Errorf("Expected task_id=42, got %v", record["task_id"])
+	}
+}
+
+func TestLoggerWithContextAttrs(t *testing.T) {
+	// CreateLogger writes to the stdout of the time of its call
+	stdout := os.Stdout
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe: %v", err)
+	}
+	os.Stdout = w
+	logger := CreateLogger(Config{LogLevel: "info", LoggerFormat: FormatJSON},
+		WithDefaultFields(Fields{"service": "test"}),
+		WithContextAttrs(func(context.Context) []slog.Attr {
+			return []slog.Attr{slog.String(TenantKey, "acme")}
+		}))
+	os.Stdout = stdout
+
+	ctx, span := tracing.Start(context.Background(), "request")
+	defer span.End()
+	ctx = WithRoute(ctx, "POST /api/v1/tasks/")
+	logger.ErrorWithContext(ctx, "task creation failed", "task_id", 42)
+	_ = w.Close()
+
+	out, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("io.ReadAll: %v", err)
+	}
+
+	for _, expected := range []string{
+		`"tenant":"acme"`,
+		`"trace_id":"` + tracing.TraceID(ctx) + `"`,
+		`"span_id":"` + tracing.SpanID(ctx) + `"`,
+		`"route":"POST /api/v1/tasks/"`,
+		`"service":"test"`,
+		`"task_id":42`,
+	} {
+		if n := bytes.Count(out, []byte(expected)); n != 1 {
+			t.Errorf("Expected %s once, got %d times in %s", expected, n, out)
+		}
+	}
+}
@@ -20,12 +20,12 @@ type Logger interface {
 	ErrorF(msg string, args ...any)
 	FatalF(msg string, args ...any)
 
-	TraceWithContext(ctx context.Context, msg string)
-	DebugWithContext(ctx context.Context, msg string)
-	InfoWithContext(ctx context.Context, msg string)
-	WarnWithContext(ctx context.Context, msg string)
-	ErrorWithContext(ctx context.Context, msg string)
-	FatalWithContext(ctx context.Context, msg string)
+	TraceWithContext(ctx context.Context, msg string, args ...any)
+	DebugWithContext(ctx context.Context, msg string, args ...any)
+	InfoWithContext(ctx context.Context, msg string, args ...any)
+	WarnWithContext(ctx context.Context, msg string, args ...any)
+	ErrorWithContext(ctx context.Context, msg string, args ...any)
+	FatalWithContext(ctx context.Context, msg string, args ...any)
 
 	WithField(key string, value any) Logger
 	WithFields(fields Fields) Logger
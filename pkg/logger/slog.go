@@ -111,7 +111,9 @@ func CreateLogger(cfg Config, opts ...Option) *StandardLogger {
 		})
 	}
 
-	logger := slog.New(handler)
+	// Records logged with a context carry its trace, route and the attributes of the
+	// WithContextAttrs options
+	logger := slog.New(NewContextHandler(handler))
 
 	for _, opt := range opts {
 		opt(logger)
@@ -196,39 +198,45 @@ func (l *StandardLogger) FatalF(msg string, args ...any) {
 }
 
 // TraceWithContext logs a message at trace level with the provided context
-// The context can contain values that will be included in the log entry
-func (l *StandardLogger) TraceWithContext(ctx context.Context, message string) {
-	l.Logger.Log(ctx, LevelTrace, message)
+// The trace, route, principal and tenant of the context are included in the log entry,
+// next to the key/value pairs of args
+func (l *StandardLogger) TraceWithContext(ctx context.Context, message string, args ...any) {
+	l.Logger.Log(ctx, LevelTrace, message, args...)
 }
 
 // DebugWithContext logs a message at debug level with the provided context
-// The context can contain values that will be included in the log entry
-func (l *StandardLogger) DebugWithContext(ctx context.Context, message string) {
-	l.Logger.DebugContext(ctx, message)
+// The trace, route, principal and tenant of the context are included in the log entry,
+// next to the key/value pairs of args
+func (l *StandardLogger) DebugWithContext(ctx context.Context, message string, args ...any) {
+	l.Logger.DebugContext(ctx, message, args...)
 }
 
 // InfoWithContext logs a message at info level with the provided context
-// The context can contain values that will be included in the log entry
-func (l *StandardLogger) InfoWithContext(ctx context.Context, message string) {
-	l.Logger.InfoContext(ctx, message)
+// The trace, route, principal and tenant of the context are included in the log entry,
+// next to the key/value pairs of args
+func (l *StandardLogger) InfoWithContext(ctx context.Context, message string, args ...any) {
+	l.Logger.InfoContext(ctx, message, args...)
 }
 
 // WarnWithContext logs a message at warn level with the provided context
-// The context can contain values that will be included in the log entry
-func (l *StandardLogger) WarnWithContext(ctx context.Context, message string) {
-	l.Logger.WarnContext(ctx, message)
+// The trace, route, principal and tenant of the context are included in the log entry,
+// next to the key/value pairs of args
+func (l *StandardLogger) WarnWithContext(ctx context.Context, message string, args ...any) {
+	l.Logger.WarnContext(ctx, message, args...)
 }
 
 // ErrorWithContext logs a message at error level with the provided context
-// The context can contain values that will be included in the log entry
-func (l *StandardLogger) ErrorWithContext(ctx context.Context, message string) {
-	l.Logger.ErrorContext(ctx, message)
+// The trace, route, principal and tenant of the context are included in the log entry,
+// next to the key/value pairs of args
+func (l *StandardLogger) ErrorWithContext(ctx context.Context, message string, args ...any) {
+	l.Logger.ErrorContext(ctx, message, args...)
 }
 
 // FatalWithContext logs a message at error level with the provided context and then panics
-// The context can contain values that will be included in the log entry
-func (l *StandardLogger) FatalWithContext(ctx context.Context, message string) {
-	l.Logger.ErrorContext(ctx, message)
+// The trace, route, principal and tenant of the context are included in the log entry,
+// next to the key/value pairs of args
+func (l *StandardLogger) FatalWithContext(ctx context.Context, message string, args ...any) {
+	l.Logger.ErrorContext(ctx, message, args...)
 	panic(1)
 }
 
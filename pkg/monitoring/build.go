@@ -0,0 +1,27 @@
+package monitoring
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// BuildMetrics
+//
+// Defines the Prometheus metric describing the build of the instance, joined with
+// the other metrics of the instance to tell the versions apart during a rollout.
+type BuildMetrics struct {
+	Info *prometheus.GaugeVec // Always 1, labeled with the build
+}
+
+// InitBuildMetrics
+//
+// Initializes the build metric using a MetricsManager.
+func InitBuildMetrics(m *MetricsManager) *BuildMetrics {
+	return &BuildMetrics{
+		Info: m.RegisterGauge(
+			"build_info",
+			"",
+			"Build of the running instance, always 1",
+			"git_commit", "build_time", "go_version", "version", "container_name",
+		),
+	}
+}
@@ -0,0 +1,44 @@
+package monitoring
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// GRPCMetrics
+//
+// Defines the Prometheus metrics of the gRPC server.
+// Every metric is labeled with the full RPC method name.
+type GRPCMetrics struct {
+	Requests *prometheus.CounterVec   // Finished RPCs by status code
+	Duration *prometheus.HistogramVec // RPC duration in seconds, streams included
+	Streams  *prometheus.GaugeVec     // Currently open server streams
+}
+
+// InitGRPCMetrics
+//
+// Initializes all gRPC server metrics using a MetricsManager.
+func InitGRPCMetrics(m *MetricsManager) *GRPCMetrics {
+	return &GRPCMetrics{
+		Requests: m.RegisterCounter(
+			"requests_total",
+			"grpc",
+			"Total gRPC requests",
+			"method", "code",
+		),
+
+		Duration: m.RegisterHistogram(
+			"request_duration_seconds",
+			"grpc",
+			"gRPC request duration in seconds",
+			getBuckets(),
+			"method",
+		),
+
+		Streams: m.RegisterGauge(
+			"open_streams",
+			"grpc",
+			"Currently open gRPC server streams",
+			"method",
+		),
+	}
+}
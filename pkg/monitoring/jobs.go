@@ -0,0 +1,52 @@
+package monitoring
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// JobMetrics
+//
+// Defines the Prometheus metrics of the background jobs.
+// Every metric is labeled with the job name.
+type JobMetrics struct {
+	Runs        *prometheus.CounterVec   // Job runs by outcome (success/error)
+	Duration    *prometheus.HistogramVec // Job run duration in seconds
+	Processed   *prometheus.CounterVec   // Items handled by the job, e.g. reminders sent
+	LastSuccess *prometheus.GaugeVec     // Unix timestamp of the last successful run
+}
+
+// InitJobMetrics
+//
+// Initializes all background job metrics using a MetricsManager.
+func InitJobMetrics(m *MetricsManager) *JobMetrics {
+	return &JobMetrics{
+		Runs: m.RegisterCounter(
+			"runs_total",
+			"jobs",
+			"Total background job runs",
+			"job", "status",
+		),
+
+		Duration: m.RegisterHistogram(
+			"run_duration_seconds",
+			"jobs",
+			"Background job run duration in seconds",
+			getBuckets(),
+			"job",
+		),
+
+		Processed: m.RegisterCounter(
+			"processed_total",
+			"jobs",
+			"Total items processed by background jobs",
+			"job",
+		),
+
+		LastSuccess: m.RegisterGauge(
+			"last_success_timestamp_seconds",
+			"jobs",
+			"Unix timestamp of the last successful background job run",
+			"job",
+		),
+	}
+}
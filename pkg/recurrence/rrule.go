@@ -0,0 +1,58 @@
+package recurrence
+
+import (
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/teambition/rrule-go"
+)
+
+// ErrInvalidRule is returned when a recurrence rule is not a valid RFC 5545 RRULE.
+var ErrInvalidRule = errors.New("invalid recurrence rule")
+
+// Validate checks that the rule is a valid RFC 5545 RRULE, e.g. "FREQ=WEEKLY;BYDAY=MO".
+// An optional "RRULE:" prefix is accepted. DTSTART must not be part of the rule,
+// it is always taken from the task due date.
+func Validate(rule string) error {
+	_, err := parse(rule, time.Now())
+	return err
+}
+
+// Next returns the first occurrence of the rule strictly after the given time.
+// The series is anchored at start (the due date of the first occurrence), so
+// COUNT and UNTIL are honoured across the whole series.
+// ok is false when the series has no more occurrences.
+func Next(rule string, start, after time.Time) (next time.Time, ok bool, err error) {
+	r, err := parse(rule, start)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+
+	next = r.After(after, false)
+	if next.IsZero() {
+		return time.Time{}, false, nil
+	}
+
+	return next, true, nil
+}
+
+// parse builds an rrule anchored at start.
+func parse(rule string, start time.Time) (*rrule.RRule, error) {
+	option, err := rrule.StrToROption(rule)
+	if err != nil {
+		return nil, errors.Wrap(ErrInvalidRule, err.Error())
+	}
+
+	if !option.Dtstart.IsZero() {
+		return nil, errors.Wrap(ErrInvalidRule, "DTSTART is derived from the task due date")
+	}
+
+	option.Dtstart = start.UTC().Truncate(time.Second)
+
+	r, err := rrule.NewRRule(*option)
+	if err != nil {
+		return nil, errors.Wrap(ErrInvalidRule, err.Error())
+	}
+
+	return r, nil
+}
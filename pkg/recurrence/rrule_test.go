@@ -0,0 +1,50 @@
+package recurrence
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/errors"
+)
+
+func TestValidate(t *testing.T) {
+	valid := []string{"FREQ=WEEKLY;BYDAY=MO", "RRULE:FREQ=DAILY;INTERVAL=2", "FREQ=MONTHLY;COUNT=3"}
+	for _, rule := range valid {
+		if err := Validate(rule); err != nil {
+			t.Errorf("Expected %q to be valid, got %v", rule, err)
+		}
+	}
+
+	invalid := []string{"", "WEEKLY", "FREQ=SOMETIMES", "DTSTART:20250101T000000Z\nRRULE:FREQ=DAILY"}
+	for _, rule := range invalid {
+		if err := Validate(rule); !errors.Is(err, ErrInvalidRule) {
+			t.Errorf("Expected %q to be invalid, got %v", rule, err)
+		}
+	}
+}
+
+func TestNext(t *testing.T) {
+	start := time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC) // Monday
+
+	next, ok, err := Next("FREQ=WEEKLY;BYDAY=MO", start, start)
+	if err != nil || !ok {
+		t.Fatalf("Expected next occurrence, got ok=%v err=%v", ok, err)
+	}
+	if expected := start.AddDate(0, 0, 7); !next.Equal(expected) {
+		t.Errorf("Expected %v, got %v", expected, next)
+	}
+}
+
+func TestNext_SeriesExhausted(t *testing.T) {
+	start := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+
+	second, ok, err := Next("FREQ=DAILY;COUNT=2", start, start)
+	if err != nil || !ok {
+		t.Fatalf("Expected second occurrence, got ok=%v err=%v", ok, err)
+	}
+
+	_, ok, err = Next("FREQ=DAILY;COUNT=2", start, second)
+	if err != nil || ok {
+		t.Errorf("Expected series to be exhausted, got ok=%v err=%v", ok, err)
+	}
+}
@@ -0,0 +1,127 @@
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// ProblemContentType is the media type of the problem details (RFC 7807), sent to
+// the clients accepting it instead of the StandardResponse.
+const ProblemContentType = "application/problem+json"
+
+// Problem describes a failed request as problem details (RFC 7807), extended with a
+// stable code, the trace ID of the request and its invalid fields.
+type Problem struct {
+	Type     string       `json:"type"`               // URI identifying the problem type
+	Title    string       `json:"title"`              // Summary of the problem type, does not change between occurrences
+	Status   int          `json:"status"`             // HTTP status code
+	Detail   string       `json:"detail,omitempty"`   // Explanation of this occurrence
+	Instance string       `json:"instance,omitempty"` // Path of the request
+	Code     string       `json:"code"`               // Machine readable code, e.g. TASK_NOT_FOUND
+	TraceID  string       `json:"trace_id,omitempty"` // Trace ID of the request, for the logs
+	Errors   []FieldError `json:"errors,omitempty"`   // Invalid fields of the request body
+}
+
+// FieldError describes an invalid field of a request body.
+type FieldError struct {
+	Field   string `json:"field"`           // Path of the field, e.g. watcher_ids[0]
+	Pointer string `json:"pointer"`         // JSON pointer (RFC 6901) to the field, e.g. /watcher_ids/0
+	Rule    string `json:"rule"`            // Failed validation rule, e.g. required, or type for malformed values
+	Param   string `json:"param,omitempty"` // Parameter of the rule, e.g. the values of oneof
+	Message string `json:"message"`
+}
+
+// Error returns the path of the field followed by the message.
+func (e FieldError) Error() string {
+	return e.Field + " " + e.Message
+}
+
+// FieldErrorer is implemented by the errors listing their invalid fields themselves,
+// such as the JSON Schema violations.
+type FieldErrorer interface {
+	FieldErrors() []FieldError
+}
+
+// GetFieldErrors returns the invalid fields of a FieldErrorer, of a
+// validator.ValidationErrors or of a JSON type mismatch, nil for other errors.
+func GetFieldErrors(err error) []FieldError {
+	var fe FieldErrorer
+	if errors.As(err, &fe) {
+		return fe.FieldErrors()
+	}
+
+	var ve validator.ValidationErrors
+	if errors.As(err, &ve) {
+		fields := make([]FieldError, 0, len(ve))
+		for _, e := range ve {
+			path := fieldPath(e)
+			fields = append(fields, FieldError{
+				Field:   path,
+				Pointer: Pointer(path),
+				Rule:    e.Tag(),
+				Param:   e.Param(),
+				Message: fieldMessage(e),
+			})
+		}
+		return fields
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		return []FieldError{{
+			Field:   typeErr.Field,
+			Pointer: Pointer(typeErr.Field),
+			Rule:    "type",
+			Param:   typeErr.Type.String(),
+			Message: fmt.Sprintf("must be a %s, not a %s", typeErr.Type, typeErr.Value),
+		}}
+	}
+
+	return nil
+}
+
+// fieldPath returns the path of the field in the request, without the request struct.
+// Names are the JSON names when a tag name function is registered on the validator.
+func fieldPath(e validator.FieldError) string {
+	if _, path, found := strings.Cut(e.Namespace(), "."); found {
+		return path
+	}
+	return e.Field()
+}
+
+// Pointer converts a field path such as assignees[0].name to a JSON pointer such as
+// /assignees/0/name.
+func Pointer(path string) string {
+	var sb strings.Builder
+	for _, token := range strings.FieldsFunc(path, func(r rune) bool { return r == '.' || r == '[' || r == ']' }) {
+		sb.WriteByte('/')
+		sb.WriteString(pointerEscaper.Replace(token))
+	}
+	return sb.String()
+}
+
+// pointerEscaper escapes the reference tokens of a JSON pointer.
+var pointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+// fieldMessage describes the failed rule of a field error.
+func fieldMessage(e validator.FieldError) string {
+	switch e.Tag() {
+	case "required":
+		return "is required"
+	case "required_without":
+		return fmt.Sprintf("is required when %s is absent", e.Param())
+	case "oneof":
+		return fmt.Sprintf("must be one of %s", strings.Join(strings.Fields(e.Param()), ", "))
+	case "email":
+		return "must be an email address"
+	}
+
+	if e.Param() != "" {
+		return fmt.Sprintf("failed the %s=%s rule", e.Tag(), e.Param())
+	}
+	return fmt.Sprintf("failed the %s rule", e.Tag())
+}
@@ -5,6 +5,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
 	"strconv"
+	"strings"
 )
 
 const (
@@ -13,6 +14,7 @@ const (
 
 	Page    = "page"
 	PerPage = "per_page"
+	Include = "include"
 )
 
 var (
@@ -27,8 +29,19 @@ type Filter map[string]string
 
 // Query represents a query for listing entities with filters and pagination metadata.
 type Query struct {
-	Filter         Filter `json:"filter"` // Dynamic filtering by fields like "status" or "assignee_id"
-	PaginationMeta        // Embeds pagination info (page, per_page, total)
+	Filter         Filter   `json:"filter"`  // Dynamic filtering by fields like "status" or "assignee_id"
+	Include        []string `json:"include"` // Related resources to embed in the response (e.g. "assignee")
+	PaginationMeta          // Embeds pagination info (page, per_page, total)
+}
+
+// Includes reports whether the given related resource was requested via ?include=.
+func (q Query) Includes(name string) bool {
+	for _, include := range q.Include {
+		if include == name {
+			return true
+		}
+	}
+	return false
 }
 
 type PaginationMeta struct {
@@ -43,6 +56,7 @@ type StandardResponse struct {
 	Meta    PaginationMeta `json:"meta,omitempty"`
 	Message string         `json:"message"`
 	Errors  []string       `json:"errors,omitempty"`
+	Code    string         `json:"code,omitempty"` // Machine readable code of failed responses, see Problem
 	Status  ResponseStatus `json:"status"`
 }
 
@@ -77,8 +91,13 @@ func GetFailedResponseFromMessageAndErrors(message string, errors []error) Stand
 
 func GetFailedValidationResponse(err error) StandardResponse {
 	var errorsList []error
+	var fe FieldErrorer
 	var ve validator.ValidationErrors
-	if errors.As(err, &ve) {
+	if errors.As(err, &fe) {
+		for _, e := range fe.FieldErrors() {
+			errorsList = append(errorsList, e)
+		}
+	} else if errors.As(err, &ve) {
 		for _, e := range ve {
 			errorsList = append(errorsList, e)
 		}
@@ -115,6 +134,8 @@ func ParseQuery(c *gin.Context) (query Query) {
 			query.Page, _ = strconv.Atoi(value)
 		case PerPage:
 			query.PerPage, _ = strconv.Atoi(value)
+		case Include:
+			query.Include = ParseInclude(value)
 		default:
 			query.Filter[key] = value
 		}
@@ -130,3 +151,14 @@ func ParseQuery(c *gin.Context) (query Query) {
 
 	return
 }
+
+// ParseInclude splits a comma separated ?include= value into trimmed, non-empty names.
+func ParseInclude(value string) []string {
+	var include []string
+	for _, name := range strings.Split(value, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			include = append(include, name)
+		}
+	}
+	return include
+}
@@ -0,0 +1,19 @@
+// Package retry computes the delays between the attempts of the retried operations
+// (webhook deliveries, outbox events, client requests).
+package retry
+
+import "time"
+
+// Backoff returns the delay before the given retry attempt (1 for the first retry):
+// base doubled on every attempt, capped at max.
+func Backoff(attempt int, base, max time.Duration) time.Duration {
+	delay := base
+	for i := 1; i < attempt && delay < max; i++ {
+		delay *= 2
+	}
+
+	if delay > max {
+		return max
+	}
+	return delay
+}
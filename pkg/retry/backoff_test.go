@@ -0,0 +1,19 @@
+package retry_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"task-manager/pkg/retry"
+)
+
+func TestBackoff(t *testing.T) {
+	base, max := 10*time.Second, time.Minute
+
+	assert.Equal(t, 10*time.Second, retry.Backoff(1, base, max))
+	assert.Equal(t, 20*time.Second, retry.Backoff(2, base, max))
+	assert.Equal(t, 40*time.Second, retry.Backoff(3, base, max))
+	assert.Equal(t, time.Minute, retry.Backoff(4, base, max))
+	assert.Equal(t, time.Minute, retry.Backoff(50, base, max))
+}
@@ -0,0 +1,171 @@
+// Package tracing sets up OpenTelemetry tracing: the tracer provider exporting the
+// spans, the W3C trace context propagation, and helpers to start spans and read the
+// trace ID of a context.
+//
+// Until Setup is called, spans are neither sampled nor exported, but still carry
+// trace IDs, so that the logs of every request can be correlated.
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	ExporterNone   = "none"   // Spans are not exported
+	ExporterStdout = "stdout" // Spans are written to stdout as JSON, for development
+	ExporterOTLP   = "otlp"   // Spans are sent to an OTLP collector over gRPC
+
+	DefaultServiceName  = "task-manager"
+	DefaultOTLPEndpoint = "localhost:4317"
+	DefaultSampleRatio  = 1.0
+
+	// ScopeName is the instrumentation scope of the spans of the service.
+	ScopeName = "task-manager"
+
+	// TraceIDHeader is the response header carrying the trace ID of the request.
+	TraceIDHeader = "X-Trace-ID"
+)
+
+// ErrUnknownExporter is returned by Setup for an exporter other than none, stdout or otlp.
+var ErrUnknownExporter = errors.New("unknown tracing exporter")
+
+// Config holds the tracing settings. Zero values fall back to the defaults.
+type Config struct {
+	Exporter     string  `json:"exporter" yaml:"EXPORTER" envconfig:"TRACING_EXPORTER"`                // none (default), stdout or otlp
+	OTLPEndpoint string  `json:"otlp_endpoint" yaml:"OTLP_ENDPOINT" envconfig:"TRACING_OTLP_ENDPOINT"` // host:port of the OTLP gRPC collector (default localhost:4317)
+	OTLPInsecure bool    `json:"otlp_insecure" yaml:"OTLP_INSECURE" envconfig:"TRACING_OTLP_INSECURE"` // Connect to the collector without TLS
+	SampleRatio  float64 `json:"sample_ratio" yaml:"SAMPLE_RATIO" envconfig:"TRACING_SAMPLE_RATIO"`    // Share of the traces started here that are sampled (default 1)
+	ServiceName  string  `json:"service_name" yaml:"SERVICE_NAME" envconfig:"TRACING_SERVICE_NAME"`    // service.name of the spans (default task-manager)
+}
+
+func init() {
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSampler(sdktrace.NeverSample())))
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+}
+
+// Setup installs the tracer provider exporting the spans as configured, for the given
+// version of the service. The returned function flushes the spans and stops the
+// provider, it must be called on shutdown.
+func Setup(ctx context.Context, cfg Config, version string) (func(context.Context) error, error) {
+	exporter, err := newExporter(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if exporter == nil {
+		// The default provider keeps generating the trace IDs of the logs
+		return func(context.Context) error { return nil }, nil
+	}
+
+	serviceName := cfg.ServiceName
+	if serviceName == "" {
+		serviceName = DefaultServiceName
+	}
+	ratio := cfg.SampleRatio
+	if ratio <= 0 {
+		ratio = DefaultSampleRatio
+	}
+	hostname, _ := os.Hostname()
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(serviceName),
+		semconv.ServiceVersion(version),
+		semconv.ServiceInstanceID(hostname),
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	provider := NewProvider(exporter,
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio))),
+	)
+	otel.SetTracerProvider(provider)
+
+	return provider.Shutdown, nil
+}
+
+// NewProvider returns a tracer provider exporting the spans in batches, sampling every
+// trace unless a sampler is given. Tests pass an in-memory exporter, e.g.
+// tracetest.NewInMemoryExporter, and install the provider with otel.SetTracerProvider.
+func NewProvider(exporter sdktrace.SpanExporter, opts ...sdktrace.TracerProviderOption) *sdktrace.TracerProvider {
+	return sdktrace.NewTracerProvider(append([]sdktrace.TracerProviderOption{sdktrace.WithBatcher(exporter)}, opts...)...)
+}
+
+// newExporter returns the exporter of the configuration, nil if the spans are not
+// exported.
+func newExporter(ctx context.Context, cfg Config) (sdktrace.SpanExporter, error) {
+	switch strings.ToLower(cfg.Exporter) {
+	case "", ExporterNone:
+		return nil, nil
+	case ExporterStdout:
+		return stdouttrace.New()
+	case ExporterOTLP:
+		endpoint := cfg.OTLPEndpoint
+		if endpoint == "" {
+			endpoint = DefaultOTLPEndpoint
+		}
+		opts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(endpoint)}
+		if cfg.OTLPInsecure {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+		return otlptracegrpc.New(ctx, opts...)
+	default:
+		return nil, fmt.Errorf("%w %q, expected none, stdout or otlp", ErrUnknownExporter, cfg.Exporter)
+	}
+}
+
+// Start starts a span named name, child of the span of ctx if any. The tracer is
+// looked up on every call, so that the spans follow the provider installed by Setup.
+func Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return otel.Tracer(ScopeName).Start(ctx, name, opts...)
+}
+
+// Propagator returns the propagator of the trace context, reading and writing the W3C
+// traceparent and baggage headers.
+func Propagator() propagation.TextMapPropagator {
+	return otel.GetTextMapPropagator()
+}
+
+// TraceID returns the trace ID of the span of ctx, empty if ctx has none.
+func TraceID(ctx context.Context) string {
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.HasTraceID() {
+		return spanContext.TraceID().String()
+	}
+	return ""
+}
+
+// SpanID returns the ID of the span of ctx, empty if ctx has none.
+func SpanID(ctx context.Context) string {
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.HasSpanID() {
+		return spanContext.SpanID().String()
+	}
+	return ""
+}
+
+// End ends span, recording err and marking the span failed if it is not nil. Meant to
+// be deferred with a named error result:
+//
+//	ctx, span := tracing.Start(ctx, "TaskService.Create")
+//	defer func() { tracing.End(span, err) }()
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,75 @@
+package tracing_test
+
+import (
+	"context"
+	"errors"
+	"task-manager/pkg/tracing"
+	"task-manager/pkg/tracing/tracingtest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TestStart_WithoutSetup_ShouldGenerateTraceIDs tests that the spans carry trace IDs
+// for the logs before tracing is set up, without being sampled.
+func TestStart_WithoutSetup_ShouldGenerateTraceIDs(t *testing.T) {
+	ctx, span := tracing.Start(context.Background(), "request")
+	defer span.End()
+
+	assert.Len(t, tracing.TraceID(ctx), 32)
+	assert.Len(t, tracing.SpanID(ctx), 16)
+	assert.False(t, span.SpanContext().IsSampled())
+	assert.Empty(t, tracing.TraceID(context.Background()))
+}
+
+// TestEnd_ShouldRecordTheError tests that the spans ended with an error are failed.
+func TestEnd_ShouldRecordTheError(t *testing.T) {
+	exporter := tracingtest.Record(t)
+
+	ctx, parent := tracing.Start(context.Background(), "request")
+	_, child := tracing.Start(ctx, "TaskService.Create", trace.WithSpanKind(trace.SpanKindInternal))
+	tracing.End(child, errors.New("assignee not found"))
+	tracing.End(parent, nil)
+
+	spans := exporter.GetSpans()
+	require.Equal(t, []string{"TaskService.Create", "request"}, tracingtest.Names(spans))
+	assert.Equal(t, codes.Error, spans[0].Status.Code)
+	assert.Equal(t, "assignee not found", spans[0].Status.Description)
+	require.Len(t, spans[0].Events, 1)
+	assert.Equal(t, "exception", spans[0].Events[0].Name)
+	assert.Equal(t, spans[1].SpanContext.SpanID(), spans[0].Parent.SpanID())
+	assert.Equal(t, codes.Unset, spans[1].Status.Code)
+}
+
+// TestSetup tests the exporters of the configuration.
+func TestSetup(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     tracing.Config
+		wantErr error
+	}{
+		{name: "default", cfg: tracing.Config{}},
+		{name: "none", cfg: tracing.Config{Exporter: tracing.ExporterNone}},
+		{name: "stdout", cfg: tracing.Config{Exporter: tracing.ExporterStdout, SampleRatio: 0.5}},
+		{name: "otlp", cfg: tracing.Config{Exporter: tracing.ExporterOTLP, OTLPEndpoint: "localhost:4317", OTLPInsecure: true}},
+		{name: "unknown", cfg: tracing.Config{Exporter: "zipkin"}, wantErr: tracing.ErrUnknownExporter},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Restores the provider installed by Setup
+			tracingtest.Record(t)
+
+			shutdown, err := tracing.Setup(context.Background(), tt.cfg, "v1.4.0")
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.NoError(t, shutdown(context.Background()))
+		})
+	}
+}
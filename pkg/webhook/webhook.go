@@ -0,0 +1,205 @@
+// Package webhook signs and sends webhook requests.
+//
+// Every request carries the event type, a delivery ID and a timestamp header. The
+// signature is an HMAC-SHA256 of "<timestamp>.<body>" keyed with the subscription
+// secret, hex encoded and prefixed with "sha256=", so receivers can verify both the
+// origin and the freshness of a request.
+//
+// Requests only reach public addresses: the URLs targeting loopback, link-local,
+// private and other internal addresses are refused, so that a subscription can't be
+// used to probe the internal network or the cloud metadata endpoints (SSRF).
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// Headers set on every webhook request.
+const (
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderDelivery  = "X-Webhook-Delivery"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+
+	signaturePrefix = "sha256="
+)
+
+// ErrPrivateAddress is returned for the URLs and connections targeting an address
+// that is not public.
+var ErrPrivateAddress = errors.New("webhook address is not public")
+
+// nonPublicPrefixes are the ranges of global unicast addresses that are not public
+// either: "this network", shared address space (carrier-grade NAT), benchmarking and
+// reserved.
+var nonPublicPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+}
+
+// IsPublic reports whether ip is a public unicast address: not loopback, link-local
+// (e.g. 169.254.169.254), private, multicast or unspecified.
+func IsPublic(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	if !ip.IsGlobalUnicast() || ip.IsPrivate() {
+		return false
+	}
+	for _, prefix := range nonPublicPrefixes {
+		if prefix.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// CheckHost returns ErrPrivateAddress if host is localhost or an IP address that is
+// not public. Other host names are checked once resolved, when a Client connects.
+func CheckHost(host string) error {
+	name := strings.TrimSuffix(strings.ToLower(host), ".")
+	if name == "localhost" || strings.HasSuffix(name, ".localhost") {
+		return fmt.Errorf("%w: %s", ErrPrivateAddress, host)
+	}
+	if ip, err := netip.ParseAddr(host); err == nil && !IsPublic(ip) {
+		return fmt.Errorf("%w: %s", ErrPrivateAddress, host)
+	}
+	return nil
+}
+
+// Sign returns the signature of body sent at the given unix timestamp.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify reports whether signature is valid for body sent at the given unix timestamp.
+// The comparison runs in constant time.
+func Verify(secret string, timestamp int64, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature))
+}
+
+// Request is a single webhook delivery.
+type Request struct {
+	URL        string    // Endpoint receiving the event
+	Secret     string    // Signing secret of the subscription
+	Event      string    // Event type, e.g. task.created
+	DeliveryID string    // Unique delivery ID
+	Body       []byte    // JSON payload
+	SentAt     time.Time // Signed timestamp
+}
+
+// StatusError is returned when the receiver answers with a non-2xx status.
+type StatusError struct {
+	StatusCode int
+}
+
+// Error implements error.
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("unexpected response status %d", e.StatusCode)
+}
+
+// Client sends signed webhook requests.
+type Client struct {
+	http         *http.Client
+	allowPrivate bool
+}
+
+// ClientOption configures a Client.
+type ClientOption func(*Client)
+
+// AllowPrivateAddresses lets the Client connect to any address, e.g. to deliver to
+// the receivers of a development machine.
+func AllowPrivateAddresses() ClientOption {
+	return func(c *Client) {
+		c.allowPrivate = true
+	}
+}
+
+// NewClient returns a Client whose requests time out after timeout. It only connects
+// to public addresses: the address is checked once the host name is resolved, for
+// every connection and redirect, so that a name resolving to an internal address
+// is refused too. The proxies of the environment are not used, their address would
+// be checked instead of the receiver's.
+func NewClient(timeout time.Duration, opts ...ClientOption) *Client {
+	c := &Client{}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if !c.allowPrivate {
+		dialer := &net.Dialer{Timeout: timeout, Control: dialPublic}
+		transport.DialContext = dialer.DialContext
+		transport.Proxy = nil
+	}
+
+	c.http = &http.Client{Timeout: timeout, Transport: transport}
+	return c
+}
+
+// dialPublic is the Control of the connections of a Client, called with the resolved
+// address: it refuses the addresses that are not public.
+func dialPublic(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return err
+	}
+	if !IsPublic(ip) {
+		return fmt.Errorf("%w: %s", ErrPrivateAddress, ip)
+	}
+	return nil
+}
+
+// Send posts the signed request and returns the response status code.
+// Any non-2xx status is reported as a *StatusError.
+func (c *Client) Send(ctx context.Context, r Request) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.URL, bytes.NewReader(r.Body))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := r.SentAt.Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "task-manager-webhooks/1.0")
+	req.Header.Set(HeaderEvent, r.Event)
+	req.Header.Set(HeaderDelivery, r.DeliveryID)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(r.Secret, timestamp, r.Body))
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	// Drain a bounded part of the body so the connection can be reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, &StatusError{StatusCode: resp.StatusCode}
+	}
+
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,104 @@
+package webhook_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"task-manager/pkg/webhook"
+)
+
+func TestSignVerify(t *testing.T) {
+	body := []byte(`{"type":"task.created"}`)
+	signature := webhook.Sign("secret", 1700000000, body)
+
+	assert.True(t, webhook.Verify("secret", 1700000000, body, signature))
+	assert.False(t, webhook.Verify("other", 1700000000, body, signature))
+	assert.False(t, webhook.Verify("secret", 1700000001, body, signature))
+	assert.False(t, webhook.Verify("secret", 1700000000, []byte(`{}`), signature))
+}
+
+func TestClientSend(t *testing.T) {
+	sentAt := time.Unix(1700000000, 0)
+	body := []byte(`{"id":"1"}`)
+
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received, _ := io.ReadAll(r.Body)
+		timestamp, _ := strconv.ParseInt(r.Header.Get(webhook.HeaderTimestamp), 10, 64)
+
+		assert.Equal(t, "task.created", r.Header.Get(webhook.HeaderEvent))
+		assert.Equal(t, "42", r.Header.Get(webhook.HeaderDelivery))
+		assert.True(t, webhook.Verify("secret", timestamp, received, r.Header.Get(webhook.HeaderSignature)))
+
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer receiver.Close()
+
+	client := webhook.NewClient(time.Second, webhook.AllowPrivateAddresses())
+	request := webhook.Request{URL: receiver.URL, Secret: "secret", Event: "task.created", DeliveryID: "42", Body: body, SentAt: sentAt}
+
+	status, err := client.Send(context.Background(), request)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, status)
+
+	request.URL += "/fail"
+	status, err = client.Send(context.Background(), request)
+	var statusErr *webhook.StatusError
+	require.ErrorAs(t, err, &statusErr)
+	assert.Equal(t, http.StatusServiceUnavailable, status)
+}
+
+func TestClientSend_PrivateAddress_ShouldBeRefused(t *testing.T) {
+	called := false
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer receiver.Close()
+
+	client := webhook.NewClient(time.Second)
+	_, err := client.Send(context.Background(), webhook.Request{URL: receiver.URL, Secret: "secret", Event: "task.created", DeliveryID: "42", SentAt: time.Now()})
+
+	require.ErrorIs(t, err, webhook.ErrPrivateAddress)
+	assert.False(t, called)
+}
+
+func TestIsPublic(t *testing.T) {
+	tests := map[string]bool{
+		"93.184.216.34":      true,
+		"2606:2800:220:1::1": true,
+		"127.0.0.1":          false,
+		"::1":                false,
+		"::ffff:127.0.0.1":   false,
+		"169.254.169.254":    false,
+		"fe80::1":            false,
+		"10.1.2.3":           false,
+		"172.16.0.1":         false,
+		"192.168.1.1":        false,
+		"fd00::1":            false,
+		"100.64.0.1":         false,
+		"0.0.0.0":            false,
+		"224.0.0.1":          false,
+	}
+
+	for address, public := range tests {
+		assert.Equal(t, public, webhook.IsPublic(netip.MustParseAddr(address)), address)
+	}
+}
+
+func TestCheckHost(t *testing.T) {
+	assert.NoError(t, webhook.CheckHost("example.com"))
+	assert.NoError(t, webhook.CheckHost("93.184.216.34"))
+	assert.ErrorIs(t, webhook.CheckHost("localhost"), webhook.ErrPrivateAddress)
+	assert.ErrorIs(t, webhook.CheckHost("api.LOCALHOST"), webhook.ErrPrivateAddress)
+	assert.ErrorIs(t, webhook.CheckHost("169.254.169.254"), webhook.ErrPrivateAddress)
+	assert.ErrorIs(t, webhook.CheckHost("::1"), webhook.ErrPrivateAddress)
+}